@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sjzsdu/utils/markdown"
+)
+
+// runBook 执行book子命令，将目录中的文档合并为一本书
+// html格式没有嵌入图片时会将引用的图片写到输出文件所在目录的 images 目录下
+func runBook(ctx context.Context, args []string) error {
+	fs := newFlagSet("book", "[-format html|pdf|epub] [-o book.epub] [-title title] [-author author] [-cover cover.png] [-docs a.md,b.md] [dir]")
+	format := fs.String("format", "epub", "导出格式：html、pdf 或 epub")
+	output := fs.String("o", "", "输出文件，默认为当前目录下的 book.<format>")
+	title := fs.String("title", "", "书名，默认为 "+markdown.DefaultSiteTitle)
+	author := fs.String("author", "", "作者")
+	language := fs.String("lang", markdown.DefaultBookLanguage, "书籍的语言代码")
+	cover := fs.String("cover", "", "封面图片相对目录的路径")
+	docs := fs.String("docs", "", "以逗号分隔的章节文档路径，按顺序合并；为空时按路径顺序合并所有非草稿文档")
+	inlineImages := fs.Bool("inline-images", false, "将图片以data URL嵌入章节，html格式不再输出 images 目录")
+	pdfCommand := fs.String("pdf-command", "", "将HTML转换为PDF的命令，{input} 和 {output} 会被替换为文件路径，为空时自动查找")
+	verbose := fs.Bool("v", false, "输出调试日志")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	bookFormat := markdown.BookFormat(*format)
+	if bookFormat != markdown.BookFormatHTML && bookFormat != markdown.BookFormatPDF && bookFormat != markdown.BookFormatEPUB {
+		fs.Usage()
+		return fmt.Errorf("不支持的导出格式: %s", *format)
+	}
+
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	tree, err := markdown.NewDirTree(dir)
+	if err != nil {
+		return err
+	}
+
+	options := markdown.DefaultServerOptions()
+	options.Logger = newLogger(*verbose)
+	server, err := markdown.NewMarkdownServer(markdown.NewMarkdownManager(), markdown.NewMarkdownRenderer(), options)
+	if err != nil {
+		return err
+	}
+
+	bookOptions := markdown.BookOptions{
+		Title:        *title,
+		Author:       *author,
+		Language:     *language,
+		Cover:        *cover,
+		InlineImages: *inlineImages,
+		PDFCommand:   strings.Fields(*pdfCommand),
+	}
+	for _, doc := range strings.Split(*docs, ",") {
+		if doc = strings.TrimSpace(doc); doc != "" {
+			bookOptions.Documents = append(bookOptions.Documents, doc)
+		}
+	}
+	book, err := server.BuildBook(tree, bookOptions)
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if path == "" {
+		path = "book." + *format
+	}
+	var buf bytes.Buffer
+	switch bookFormat {
+	case markdown.BookFormatHTML:
+		if err := book.WriteHTML(&buf); err != nil {
+			return err
+		}
+		if !*inlineImages {
+			if err := book.WriteImages(filepath.Dir(path)); err != nil {
+				return err
+			}
+		}
+	case markdown.BookFormatPDF:
+		err = book.WritePDF(ctx, &buf, bookOptions.PDFCommand)
+	case markdown.BookFormatEPUB:
+		err = book.WriteEPUB(&buf)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+
+	fmt.Printf("已导出 %d 章到 %s\n", len(book.Chapters), path)
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	crawlerschema "github.com/sjzsdu/utils/schema/crawler"
+)
+
+// crawlResult 单个数据源的爬取结果
+type crawlResult struct {
+	Source string        `json:"source"`
+	Items  []models.Item `json:"items"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// runCrawl 执行crawl子命令
+// 默认持续运行并输出各数据源的新数据，直到收到中断信号；-once 时每个数据源只爬取一次并输出汇总报告
+func runCrawl(ctx context.Context, args []string) error {
+	fs := newFlagSet("crawl", "[-config crawler.yaml] [-once] [-json]")
+	configPath := fs.String("config", "", "爬虫配置文件，为空时启用所有数据源且不发送通知")
+	once := fs.Bool("once", false, "每个数据源只爬取一次后退出，不发送通知")
+	jsonOutput := fs.Bool("json", false, "以JSON格式输出结果")
+	verbose := fs.Bool("v", false, "输出调试日志")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	schema := crawlerschema.NewEngineSchema()
+	if *configPath != "" {
+		if err := schema.LoadFromFile(*configPath); err != nil {
+			return err
+		}
+	}
+	schema.SetLogger(newLogger(*verbose))
+
+	names, err := schema.SourceNames()
+	if err != nil {
+		return err
+	}
+	engine, err := schema.CreateEngine()
+	if err != nil {
+		return err
+	}
+	defer engine.Stop()
+
+	if *once {
+		report := crawler.Crawl(ctx, engine.FetchItem, names, crawler.WithReportSampleSize(-1))
+		if *jsonOutput {
+			return report.WriteJSON(os.Stdout)
+		}
+		return report.WriteText(os.Stdout)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, name := range names {
+		ch := make(chan []models.Item, 10)
+		if err := engine.Subscribe(name, ch); err != nil {
+			return err
+		}
+		defer engine.Unsubscribe(name, ch)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case items := <-ch:
+					mu.Lock()
+					if *jsonOutput {
+						writeJSON(os.Stdout, crawlResult{Source: name, Items: items})
+					} else {
+						printCrawlResult(crawlResult{Source: name, Items: items})
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	if err := engine.Start(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "已启动 %d 个数据源，按 Ctrl+C 停止\n", len(names))
+
+	<-ctx.Done()
+	wg.Wait()
+	return nil
+}
+
+// printCrawlResult 以文本格式输出爬取结果
+func printCrawlResult(result crawlResult) {
+	if result.Error != "" {
+		fmt.Printf("✗ %s: %s\n", result.Source, result.Error)
+		return
+	}
+
+	fmt.Printf("✓ %s: %d items\n", result.Source, len(result.Items))
+	for i, item := range result.Items {
+		fmt.Printf("  %d. %s\n", i+1, item.Title)
+		if item.URL != "" {
+			fmt.Printf("     %s\n", item.URL)
+		}
+	}
+}
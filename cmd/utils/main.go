@@ -0,0 +1,126 @@
+// Command utils 将仓库中的爬虫、搜索、Markdown服务和通知器组合为命令行工具
+//
+// 用法：
+//
+//	utils crawl    [-config crawler.yaml] [-once] [-json]
+//	utils search   [-config search.yaml] [-engine bing] [-limit 10] [-json] <query>
+//	utils serve-md [-port 8080] [-base-path /docs] [dir]
+//	utils book     [-format html|pdf|epub] [-o book.epub] [-docs a.md,b.md] [dir]
+//	utils notify   -config notifier.yaml [-channel name] -title <title> [-url url] [content]
+//
+// 各子命令的配置文件格式与 schema 下对应的包相同
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sjzsdu/utils/logging"
+)
+
+// command 子命令
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{name: "crawl", usage: "按配置爬取数据源，并将新数据发送到配置的通知渠道", run: runCrawl},
+	{name: "search", usage: "使用配置的搜索引擎搜索", run: runSearch},
+	{name: "serve-md", usage: "启动目录的Markdown文档服务", run: runServeMarkdown},
+	{name: "book", usage: "将目录中的Markdown文档合并导出为HTML、PDF或EPUB电子书", run: runBook},
+	{name: "notify", usage: "向配置的通知渠道发送一条消息", run: runNotify},
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err := cmd.run(ctx, args)
+		stop()
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return
+		case errors.Is(err, errUsage):
+			os.Exit(2)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "utils %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "utils: 未知的子命令 %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage 输出命令的用法
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: utils <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "子命令:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "使用 utils <command> -h 查看子命令的参数")
+}
+
+// errUsage 命令行参数错误，错误信息和用法已由 flag 包输出
+var errUsage = errors.New("参数错误")
+
+// newFlagSet 创建子命令的参数集合，解析失败时返回错误而不是退出
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet("utils "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "用法: utils %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags 解析子命令参数，-h 时返回 flag.ErrHelp，其余解析错误返回 errUsage
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
+	}
+	return err
+}
+
+// newLogger 创建输出到标准错误的日志记录器，verbose为true时输出调试日志
+func newLogger(verbose bool) logging.Logger {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	return logging.New(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+}
+
+// writeJSON 以缩进格式输出JSON
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sjzsdu/utils/notifier"
+	notifierschema "github.com/sjzsdu/utils/schema/notifier"
+)
+
+// message 命令行参数提供的通知消息
+type message struct {
+	title   string
+	url     string
+	content string
+}
+
+// Title 获取标题
+func (m message) Title() string {
+	return m.title
+}
+
+// URL 获取链接
+func (m message) URL() string {
+	return m.url
+}
+
+// Content 获取内容
+func (m message) Content() string {
+	return m.content
+}
+
+// runNotify 执行notify子命令，消息内容为 - 时从标准输入读取
+func runNotify(ctx context.Context, args []string) error {
+	fs := newFlagSet("notify", "-config notifier.yaml [-channel name] -title <title> [-url url] [content|-]")
+	configPath := fs.String("config", "", "通知配置文件")
+	channel := fs.String("channel", "", "发送的通知渠道名称，为空时发送到所有渠道")
+	title := fs.String("title", "", "消息标题")
+	url := fs.String("url", "", "消息链接")
+	verbose := fs.Bool("v", false, "输出调试日志")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *configPath == "" || *title == "" {
+		fs.Usage()
+		return fmt.Errorf("必须指定 -config 和 -title")
+	}
+
+	content := strings.Join(fs.Args(), " ")
+	if content == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("读取标准输入失败: %w", err)
+		}
+		content = string(data)
+	}
+
+	manager, err := notifierschema.LoadAndCreateNotifierManager(*configPath)
+	if err != nil {
+		return err
+	}
+	manager.SetLogger(newLogger(*verbose))
+
+	items := []notifier.MessageItem{message{title: *title, url: *url, content: content}}
+	if *channel != "" {
+		result, err := manager.SendToSpecific(*channel, items)
+		if err != nil {
+			return err
+		}
+		printNotificationResult(*channel, result)
+		return nil
+	}
+
+	results, err := manager.SendToAll(items)
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		printNotificationResult(name, results[name])
+	}
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("没有启用任何通知渠道")
+	}
+	return nil
+}
+
+// printNotificationResult 输出单个渠道的发送结果
+func printNotificationResult(name string, result *notifier.NotificationResult) {
+	fmt.Printf("%s: %s (%d/%d)\n", name, result.Status, result.SuccessCount, result.TotalCount)
+}
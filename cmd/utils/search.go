@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	searchschema "github.com/sjzsdu/utils/schema/search"
+	"github.com/sjzsdu/utils/search"
+)
+
+// runSearch 执行search子命令
+// 除配置文件中的搜索引擎外，环境变量中配置了API密钥的搜索引擎也会被启用
+func runSearch(ctx context.Context, args []string) error {
+	fs := newFlagSet("search", "[-config search.yaml] [-engine name] [-limit 10] [-json] <query>")
+	configPath := fs.String("config", "", "搜索配置文件，为空时只使用环境变量中的API密钥")
+	engine := fs.String("engine", "", "使用的搜索引擎，为空时使用配置的默认搜索引擎和备用搜索引擎")
+	limit := fs.Int("limit", 10, "返回结果数量")
+	jsonOutput := fs.Bool("json", false, "以JSON格式输出结果")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	query := strings.Join(fs.Args(), " ")
+	if query == "" {
+		fs.Usage()
+		return fmt.Errorf("搜索关键词不能为空")
+	}
+
+	schema := searchschema.NewClientSchema()
+	if *configPath != "" {
+		if err := schema.LoadFromFile(*configPath); err != nil {
+			return err
+		}
+	}
+	schema.LoadFromEnv()
+
+	client, err := schema.CreateClient()
+	if err != nil {
+		return err
+	}
+
+	var results []search.SearchResult
+	if *engine != "" {
+		results, err = client.SearchWithEngine(ctx, *engine, query, *limit)
+	} else {
+		results, err = client.Search(ctx, query, *limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		return writeJSON(os.Stdout, results)
+	}
+	for i, result := range results {
+		fmt.Printf("%d. %s\n   %s\n", i+1, result.Title, result.URL)
+		if result.Snippet != "" {
+			fmt.Printf("   %s\n", result.Snippet)
+		}
+	}
+	return nil
+}
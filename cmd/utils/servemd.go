@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sjzsdu/utils/markdown"
+)
+
+// shutdownTimeout 停止服务时等待请求处理完成的时间
+const shutdownTimeout = 5 * time.Second
+
+// runServeMarkdown 执行serve-md子命令，目录为Git仓库时支持对比文档的历史版本
+func runServeMarkdown(ctx context.Context, args []string) error {
+	fs := newFlagSet("serve-md", "[-host 127.0.0.1] [-port 8080] [-base-path /docs] [-access-log] [-metrics] [-lang zh] [-admin-token token] [-book-pdf] [dir]")
+	host := fs.String("host", "127.0.0.1", "监听地址，默认只允许本机访问，设为 0.0.0.0 时允许其他机器访问")
+	port := fs.Int("port", 8080, "监听端口")
+	basePath := fs.String("base-path", "", "服务挂载的路径前缀")
+	verbose := fs.Bool("v", false, "输出调试日志")
+	accessLog := fs.Bool("access-log", false, "记录每个请求的路径、状态码和耗时")
+	metrics := fs.Bool("metrics", false, "通过 /metrics 暴露Prometheus指标")
+	defaultLang := fs.String("lang", "", "没有语言后缀的文档使用的语言代码，如 zh")
+	adminToken := fs.String("admin-token", os.Getenv("MARKDOWN_ADMIN_TOKEN"), "开启 /api/docs 文档管理接口的访问令牌，默认读取环境变量 MARKDOWN_ADMIN_TOKEN")
+	bookPDF := fs.Bool("book-pdf", false, "允许通过 /book?format=pdf 导出PDF，转换时会在服务器上运行无头浏览器")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	tree, err := markdown.NewDirTree(dir)
+	if err != nil {
+		return err
+	}
+
+	logger := newLogger(*verbose)
+	options := markdown.DefaultServerOptions()
+	options.BasePath = *basePath
+	options.Logger = logger
+	options.AccessLog = *accessLog
+	options.Metrics = *metrics
+	options.DefaultLanguage = *defaultLang
+	options.AdminToken = *adminToken
+	options.Book.ServePDF = *bookPDF
+	if _, err := os.Stat(filepath.Join(tree.Root(), ".git")); err == nil {
+		options.GitRoot = tree.Root()
+	}
+
+	manager, err := markdown.NewFileManager(tree.Root())
+	if err != nil {
+		return err
+	}
+	server, err := markdown.NewMarkdownServer(manager, markdown.NewMarkdownRenderer(), options)
+	if err != nil {
+		return err
+	}
+	server.SetProjectTree(tree)
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	if err != nil {
+		return fmt.Errorf("监听端口失败: %w", err)
+	}
+
+	httpServer := &http.Server{Handler: server.Handler()}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+	logger.Info("Markdown文档服务已启动，按 Ctrl+C 停止服务", "dir", tree.Root(), "url", fmt.Sprintf("http://%s%s/", net.JoinHostPort(displayHost(*host), strconv.Itoa(*port)), *basePath))
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// displayHost 返回启动日志中显示的主机名，监听所有网卡时显示 localhost
+func displayHost(host string) string {
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return "localhost"
+	}
+	return host
+}
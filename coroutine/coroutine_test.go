@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -504,3 +506,1422 @@ func TestEachDictWithError(t *testing.T) {
 		}
 	}
 }
+
+// TestPoolSubmit 测试常驻协程池提交任务并获取结果
+func TestPoolSubmit(t *testing.T) {
+	pool := NewPool(2)
+
+	// 提交带返回值的任务
+	futures := make([]*Future[int], 5)
+	for i := 0; i < 5; i++ {
+		idx := i // 捕获循环变量
+		future, err := SubmitValue(pool, func() (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return idx * 2, nil
+		})
+		assert.NoError(t, err, "提交任务不应出错")
+		futures[i] = future
+	}
+
+	// 验证每个任务的结果
+	for i, future := range futures {
+		value, err := future.Get()
+		assert.NoError(t, err, "执行应该没有错误")
+		assert.Equal(t, i*2, value, "结果值应为索引的2倍")
+	}
+
+	// 提交返回错误的任务
+	expectedErr := errors.New("测试错误")
+	future, err := pool.Submit(func() error { return expectedErr })
+	assert.NoError(t, err, "提交任务不应出错")
+	_, err = future.Await(context.Background())
+	assert.Equal(t, expectedErr, err, "应返回任务的错误")
+}
+
+// TestPoolWait 测试Wait等待所有任务完成且协程池可继续复用
+func TestPoolWait(t *testing.T) {
+	pool := NewPool(3)
+
+	var processedCount int32
+	var maxRunning, running int32
+
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 10; i++ {
+			_, err := pool.Submit(func() error {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&maxRunning)
+					if current <= old || atomic.CompareAndSwapInt32(&maxRunning, old, current) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				atomic.AddInt32(&processedCount, 1)
+				return nil
+			})
+			assert.NoError(t, err, "提交任务不应出错")
+		}
+
+		pool.Wait()
+		assert.Equal(t, int32((round+1)*10), atomic.LoadInt32(&processedCount), "Wait返回时所有任务都应完成")
+	}
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3), "并发数不应超过最大工作协程数")
+}
+
+// TestPoolShutdown 测试关闭协程池
+func TestPoolShutdown(t *testing.T) {
+	pool := NewPool(1)
+
+	var processedCount int32
+	for i := 0; i < 3; i++ {
+		_, err := pool.Submit(func() error {
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&processedCount, 1)
+			return nil
+		})
+		assert.NoError(t, err, "提交任务不应出错")
+	}
+
+	// 关闭时应等待已提交的任务完成
+	err := pool.Shutdown(context.Background())
+	assert.NoError(t, err, "关闭协程池不应出错")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&processedCount), "已提交的任务都应完成")
+
+	// 关闭后不再接受新任务
+	_, err = pool.Submit(func() error { return nil })
+	assert.ErrorIs(t, err, ErrPoolClosed, "关闭后提交任务应返回ErrPoolClosed")
+}
+
+// TestPoolShutdownTimeout 测试关闭协程池时上下文超时
+func TestPoolShutdownTimeout(t *testing.T) {
+	pool := NewPool(1)
+
+	release := make(chan struct{})
+	_, err := pool.Submit(func() error {
+		<-release
+		return nil
+	})
+	assert.NoError(t, err, "提交任务不应出错")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = pool.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "任务未完成时应返回上下文超时错误")
+
+	close(release)
+	pool.Wait()
+}
+
+// TestMapWithRetry 测试Map在启用重试时自动重试失败的工作函数
+func TestProcessTreeCycleAndDepth(t *testing.T) {
+	root := &TestNode{id: "root"}
+	child := &TestNode{id: "child"}
+	grandchild := &TestNode{id: "grandchild"}
+	root.children = []TreeNode{child}
+	child.children = []TreeNode{grandchild}
+	// 构造环：grandchild -> root
+	grandchild.children = []TreeNode{root}
+
+	var count int32
+	processFunc := func(node TreeNode) (string, error) {
+		atomic.AddInt32(&count, 1)
+		return node.GetID(), nil
+	}
+
+	for name, process := range map[string]func(...Option) map[string]TreeResult[string]{
+		"parallel": func(opts ...Option) map[string]TreeResult[string] {
+			return ProcessTree(context.Background(), 2, root, processFunc, opts...)
+		},
+		"bfs": func(opts ...Option) map[string]TreeResult[string] {
+			return ProcessTreeBFS(context.Background(), 2, root, processFunc, opts...)
+		},
+		"dfs": func(opts ...Option) map[string]TreeResult[string] {
+			return ProcessTreeDFS(context.Background(), 2, root, processFunc, opts...)
+		},
+	} {
+		atomic.StoreInt32(&count, 0)
+		results := process()
+		assert.Len(t, results, 3, name+": 有环时每个节点只处理一次")
+		assert.Equal(t, int32(3), atomic.LoadInt32(&count), name)
+
+		results = process(WithMaxDepth(1))
+		assert.Len(t, results, 2, name+": 应只处理深度不超过1的节点")
+		assert.NotContains(t, results, "grandchild", name)
+	}
+}
+
+func TestProcessTreeSkipChildren(t *testing.T) {
+	root := &TestNode{id: "root"}
+	skipped := &TestNode{id: "skipped"}
+	kept := &TestNode{id: "kept"}
+	root.children = []TreeNode{skipped, kept}
+	skipped.children = []TreeNode{&TestNode{id: "hidden"}}
+	kept.children = []TreeNode{&TestNode{id: "visible"}}
+
+	processFunc := func(node TreeNode) (int, error) {
+		if node.GetID() == "skipped" {
+			return 1, ErrSkipChildren
+		}
+		return 0, nil
+	}
+
+	for name, results := range map[string]map[string]TreeResult[int]{
+		"parallel": ProcessTree(context.Background(), 2, root, processFunc),
+		"bfs":      ProcessTreeBFS(context.Background(), 2, root, processFunc),
+		"dfs":      ProcessTreeDFS(context.Background(), 2, root, processFunc),
+	} {
+		assert.Len(t, results, 4, name)
+		assert.NotContains(t, results, "hidden", name+": 跳过的子树不应被处理")
+		assert.Contains(t, results, "visible", name)
+		assert.NoError(t, results["skipped"].Err, name+": 跳过子树的节点不应记录错误")
+		assert.Equal(t, 1, results["skipped"].Value, name)
+	}
+}
+
+func TestProcessTreeDFS(t *testing.T) {
+	root := &TestNode{id: "root"}
+	a := &TestNode{id: "a"}
+	b := &TestNode{id: "b"}
+	root.children = []TreeNode{a, b}
+	a.children = []TreeNode{&TestNode{id: "a1"}, &TestNode{id: "a2"}}
+	b.children = []TreeNode{&TestNode{id: "b1"}}
+
+	var order []string
+	results := ProcessTreeDFS(context.Background(), 1, root, func(node TreeNode) (string, error) {
+		order = append(order, node.GetID())
+		return node.GetID(), nil
+	})
+
+	assert.Len(t, results, 6)
+	assert.Equal(t, []string{"root", "a", "a1", "a2", "b", "b1"}, order, "单个工作协程时应为先序深度优先")
+}
+
+func TestProcessTreeWithParent(t *testing.T) {
+	root := &TestNode{id: "root"}
+	usr := &TestNode{id: "usr"}
+	lib := &TestNode{id: "lib"}
+	bin := &TestNode{id: "bin"}
+	root.children = []TreeNode{usr, lib}
+	usr.children = []TreeNode{bin}
+
+	results := ProcessTreeWithParent(context.Background(), 2, root, func(node TreeNode, parent *TreeResult[string]) (string, error) {
+		if parent == nil {
+			return "", nil
+		}
+		return parent.Value + "/" + node.GetID(), nil
+	})
+
+	assert.Len(t, results, 4)
+	assert.Equal(t, "", results["root"].Value)
+	assert.Equal(t, "/usr", results["usr"].Value)
+	assert.Equal(t, "/lib", results["lib"].Value)
+	assert.Equal(t, "/usr/bin", results["bin"].Value, "子节点应基于父节点的结果计算")
+}
+
+func TestUnordered(t *testing.T) {
+	items := []int{30, 20, 10}
+	results := Map(context.Background(), 3, items, func(n int) (int, error) {
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n, nil
+	}, WithUnordered())
+
+	assert.Len(t, results, 3)
+	for _, result := range results {
+		assert.Equal(t, items[result.Index], result.Value, "Index应指向原始位置")
+	}
+	assert.Equal(t, 10, results[0].Value, "结果应按完成顺序排列")
+
+	// 依赖顺序的辅助函数不受无序选项影响
+	filtered, err := Filter(context.Background(), 3, items, func(n int) (bool, error) {
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n > 10, nil
+	}, WithUnordered())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{30, 20}, filtered)
+}
+
+func TestMapSeq(t *testing.T) {
+	items := func(yield func(int) bool) {
+		for i := 0; i < 100; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	sum := 0
+	seen := make(map[int]bool)
+	for result := range MapSeq(context.Background(), 4, items, func(n int) (int, error) {
+		return n * 2, nil
+	}) {
+		assert.NoError(t, result.Err)
+		assert.Equal(t, result.Index*2, result.Value)
+		seen[result.Index] = true
+		sum += result.Value
+	}
+	assert.Len(t, seen, 100)
+	assert.Equal(t, 9900, sum)
+
+	// 提前退出循环时取消剩余工作
+	var executed int32
+	count := 0
+	for range MapSeq(context.Background(), 2, items, func(n int) (int, error) {
+		atomic.AddInt32(&executed, 1)
+		return n, nil
+	}) {
+		count++
+		if count == 5 {
+			break
+		}
+	}
+	assert.Equal(t, 5, count)
+	assert.Less(t, int(atomic.LoadInt32(&executed)), 100, "提前退出后不应执行全部工作")
+}
+
+func TestOnProgress(t *testing.T) {
+	var calls [][2]int
+	items := make([]int, 20)
+	Map(context.Background(), 4, items, func(n int) (int, error) {
+		return n, nil
+	}, WithOnProgress(func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	}))
+
+	assert.Len(t, calls, 20, "每完成一个工作应回调一次")
+	for i, call := range calls {
+		assert.Equal(t, i+1, call[0], "done应严格递增")
+		assert.Equal(t, 20, call[1])
+	}
+
+	// 树处理的总数未知
+	root := &TestNode{id: "root", children: []TreeNode{&TestNode{id: "a"}, &TestNode{id: "b"}}}
+	var last [2]int
+	ProcessTreeBFS(context.Background(), 2, root, func(TreeNode) (int, error) {
+		return 0, nil
+	}, WithOnProgress(func(done, total int) {
+		last = [2]int{done, total}
+	}))
+	assert.Equal(t, [2]int{3, -1}, last)
+}
+
+func TestHooks(t *testing.T) {
+	type traceKey struct{}
+
+	var mu sync.Mutex
+	var infos []WorkInfo
+	var traced int32
+	hooks := WithHooks(Hooks{
+		BeforeWork: func(ctx context.Context, info WorkInfo) context.Context {
+			return context.WithValue(ctx, traceKey{}, fmt.Sprintf("span-%d", info.Index))
+		},
+		AfterWork: func(ctx context.Context, info WorkInfo) {
+			if ctx.Value(traceKey{}) == fmt.Sprintf("span-%d", info.Index) {
+				atomic.AddInt32(&traced, 1)
+			}
+			mu.Lock()
+			infos = append(infos, info)
+			mu.Unlock()
+		},
+	})
+
+	calls := 0
+	results := Map(context.Background(), 1, []int{1, 2}, func(n int) (int, error) {
+		calls++
+		if n == 2 && calls == 2 {
+			return 0, errors.New("临时错误")
+		}
+		return n, nil
+	}, hooks, WithRetry(RetryPolicy{MaxAttempts: 2}))
+
+	assert.NoError(t, results[1].Err)
+	assert.Len(t, infos, 3, "每次执行（包括重试）都应调用回调")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&traced), "BeforeWork返回的上下文应传递给AfterWork")
+	assert.Equal(t, 1, infos[1].Index)
+	assert.Equal(t, 1, infos[1].Attempt)
+	assert.Error(t, infos[1].Err)
+	assert.Equal(t, 2, infos[2].Attempt)
+	assert.NoError(t, infos[2].Err)
+
+	// 流水线阶段收到BeforeWork返回的上下文
+	p := NewPipeline(context.Background(), hooks)
+	out := AddStage(p, From(p, []int{1}), Stage[int, string]{
+		Name: "trace",
+		Process: func(ctx context.Context, n int) (string, error) {
+			value, _ := ctx.Value(traceKey{}).(string)
+			return value, nil
+		},
+	})
+	values, err := Collect(p, out)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"span--1"}, values)
+
+	// 常驻协程池通过WithTaskContext传递上下文
+	pool := NewPool(1, WithHooks(Hooks{
+		BeforeWork: func(ctx context.Context, info WorkInfo) context.Context {
+			assert.Equal(t, "req-1", ctx.Value(traceKey{}))
+			return ctx
+		},
+	}))
+	future, err := pool.Submit(func() error { return nil }, WithTaskContext(context.WithValue(context.Background(), traceKey{}, "req-1")))
+	assert.NoError(t, err)
+	_, err = future.Get()
+	assert.NoError(t, err)
+}
+
+func TestSemaphore(t *testing.T) {
+	sem := NewSemaphore(3)
+	ctx := context.Background()
+
+	assert.NoError(t, sem.Acquire(ctx, 2))
+	assert.True(t, sem.TryAcquire(1))
+	assert.False(t, sem.TryAcquire(1), "资源已满时TryAcquire应失败")
+
+	// 资源不足时阻塞，上下文超时后返回错误
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, sem.Acquire(timeoutCtx, 2), context.DeadlineExceeded)
+
+	acquired := make(chan struct{})
+	go func() {
+		sem.Acquire(ctx, 2)
+		close(acquired)
+	}()
+	sem.Release(1)
+	select {
+	case <-acquired:
+		t.Fatal("资源不足时不应获取成功")
+	case <-time.After(20 * time.Millisecond):
+	}
+	sem.Release(2)
+	<-acquired
+
+	sem.Release(2)
+	assert.True(t, sem.TryAcquire(3), "归还后应可以获取全部资源")
+}
+
+func TestKeyedLimiter(t *testing.T) {
+	limiter := NewKeyedLimiter[string](2)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	running := make(map[string]int)
+	maxRunning := make(map[string]int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		host := fmt.Sprintf("host-%d", i%2)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiter.Do(ctx, host, func() error {
+				mu.Lock()
+				running[host]++
+				maxRunning[host] = max(maxRunning[host], running[host])
+				mu.Unlock()
+
+				time.Sleep(2 * time.Millisecond)
+
+				mu.Lock()
+				running[host]--
+				mu.Unlock()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxRunning["host-0"], 2, "每个键的并发数不应超过限制")
+	assert.LessOrEqual(t, maxRunning["host-1"], 2, "每个键的并发数不应超过限制")
+	assert.Equal(t, 0, limiter.Len(), "空闲的键应被清理")
+
+	assert.True(t, limiter.TryAcquire("a"))
+	assert.True(t, limiter.TryAcquire("a"))
+	assert.False(t, limiter.TryAcquire("a"), "名额已满时TryAcquire应失败")
+	assert.True(t, limiter.TryAcquire("b"), "不同的键互不影响")
+}
+
+func TestWorkStealing(t *testing.T) {
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = i
+	}
+
+	var executed int32
+	results := Map(context.Background(), 7, items, func(n int) (int, error) {
+		atomic.AddInt32(&executed, 1)
+		// 前面的工作耗时更长，触发窃取
+		if n < 10 {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if n%100 == 0 {
+			return 0, fmt.Errorf("错误 %d", n)
+		}
+		return n * 2, nil
+	}, WithWorkStealing())
+
+	assert.Equal(t, int32(1000), atomic.LoadInt32(&executed), "每个工作应只执行一次")
+	assert.Len(t, results, 1000)
+	for i, result := range results {
+		assert.Equal(t, i, result.Index)
+		if i%100 == 0 {
+			assert.Error(t, result.Err)
+		} else {
+			assert.NoError(t, result.Err)
+			assert.Equal(t, i*2, result.Value)
+		}
+	}
+
+	// 快速失败时不再执行剩余工作
+	atomic.StoreInt32(&executed, 0)
+	errs := Each(context.Background(), 2, items, func(n int) error {
+		atomic.AddInt32(&executed, 1)
+		return errors.New("失败")
+	}, WithWorkStealing(), WithFailFast())
+	assert.Len(t, errs, 1000)
+	assert.Less(t, int(atomic.LoadInt32(&executed)), 1000, "快速失败后不应执行全部工作")
+}
+
+// spin 执行指定次数的计算，模拟CPU密集型工作
+func spin(n int) int {
+	sum := 0
+	for i := 0; i < n; i++ {
+		sum += i * i % 7
+	}
+	return sum
+}
+
+// skewedWorks 生成耗时严重不均的工作：少数工作的耗时是其余工作的数百倍
+func skewedWorks(n int) []int {
+	costs := make([]int, n)
+	for i := range costs {
+		costs[i] = 100
+		if i%97 == 0 {
+			costs[i] = 50000
+		}
+	}
+	return costs
+}
+
+func BenchmarkExecuteSkewed(b *testing.B) {
+	costs := skewedWorks(10000)
+	b.Run("shared-queue", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Map(context.Background(), runtime.NumCPU(), costs, func(n int) (int, error) {
+				return spin(n), nil
+			})
+		}
+	})
+	b.Run("work-stealing", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Map(context.Background(), runtime.NumCPU(), costs, func(n int) (int, error) {
+				return spin(n), nil
+			}, WithWorkStealing())
+		}
+	})
+}
+
+func BenchmarkExecuteTiny(b *testing.B) {
+	items := make([]int, 100000)
+	b.Run("shared-queue", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Map(context.Background(), runtime.NumCPU(), items, func(n int) (int, error) {
+				return n + 1, nil
+			})
+		}
+	})
+	b.Run("work-stealing", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Map(context.Background(), runtime.NumCPU(), items, func(n int) (int, error) {
+				return n + 1, nil
+			}, WithWorkStealing())
+		}
+	})
+}
+
+func TestWorkTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	start := time.Now()
+	results := Map(context.Background(), 2, []int{1, 2, 3}, func(n int) (int, error) {
+		if n == 2 {
+			<-release
+		}
+		return n * 10, nil
+	}, WithWorkTimeout(30*time.Millisecond))
+
+	assert.Less(t, time.Since(start), time.Second, "超时的工作不应阻塞整个调用")
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, 10, results[0].Value)
+	assert.ErrorIs(t, results[1].Err, ErrWorkTimeout, "超时的工作应返回ErrWorkTimeout")
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, 30, results[2].Value)
+
+	// 流水线阶段收到带截止时间的上下文
+	p := NewPipeline(context.Background(), WithWorkTimeout(20*time.Millisecond))
+	out := AddStage(p, From(p, []int{1}), Stage[int, int]{
+		Process: func(ctx context.Context, n int) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		},
+	})
+	_, err := Collect(p, out)
+	assert.ErrorIs(t, err, ErrWorkTimeout, "阶段超时应返回ErrWorkTimeout")
+}
+
+func TestPoolScaling(t *testing.T) {
+	pool := NewPool(4, WithScaling(ScalingPolicy{
+		MinWorkers:  2,
+		IdleTimeout: 20 * time.Millisecond,
+	}))
+	defer pool.Shutdown(context.Background())
+
+	// 常驻协程在创建时启动
+	assert.Equal(t, 2, pool.Metrics().Workers, "应启动常驻工作协程")
+
+	release := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		_, err := pool.Submit(func() error {
+			<-release
+			return nil
+		})
+		assert.NoError(t, err, "提交任务不应出错")
+	}
+
+	// 排队任务较多时扩容到最大并发数
+	assert.Eventually(t, func() bool {
+		m := pool.Metrics()
+		return m.Workers == 4 && m.ActiveWorkers == 4 && m.QueueLength == 4
+	}, time.Second, 5*time.Millisecond, "应扩容到最大并发数")
+
+	close(release)
+	pool.Wait()
+
+	m := pool.Metrics()
+	assert.Equal(t, 0, m.Pending, "所有任务应已完成")
+	assert.Greater(t, m.AvgLatency, time.Duration(0), "应记录任务耗时")
+
+	// 空闲超时后缩容到常驻数量
+	assert.Eventually(t, func() bool {
+		m := pool.Metrics()
+		return m.Workers == 2 && m.IdleWorkers == 2
+	}, time.Second, 5*time.Millisecond, "应缩容到常驻协程数")
+}
+
+func TestMapWithRetry(t *testing.T) {
+	items := []int{1, 2, 3}
+	var calls [3]int32
+
+	// 前两次调用失败，第三次成功
+	mapFunc := func(item int) (int, error) {
+		if atomic.AddInt32(&calls[item-1], 1) < 3 {
+			return 0, errors.New("临时错误")
+		}
+		return item * 10, nil
+	}
+
+	ctx := context.Background()
+	results := Map(ctx, 2, items, mapFunc, WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     ConstantBackoff(time.Millisecond),
+	}))
+
+	for i, result := range results {
+		assert.NoError(t, result.Err, "重试后应成功")
+		assert.Equal(t, items[i]*10, result.Value, "结果值应为元素的10倍")
+		assert.Equal(t, 3, result.Attempts, "应记录执行次数")
+	}
+}
+
+// TestRetryPolicyLimits 测试重试次数上限和不可重试错误
+func TestRetryPolicyLimits(t *testing.T) {
+	permanentErr := errors.New("永久错误")
+	temporaryErr := errors.New("临时错误")
+
+	works := []WorkFunc[int]{
+		func() (int, error) { return 0, temporaryErr },
+		func() (int, error) { return 0, permanentErr },
+		func() (int, error) { return 1, nil },
+	}
+
+	pool := NewCoroutinePool[int](2, WithRetry(RetryPolicy{
+		MaxAttempts: 4,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, permanentErr)
+		},
+	}))
+	results := pool.Execute(context.Background(), works)
+
+	assert.Equal(t, temporaryErr, results[0].Err, "超过重试次数后应返回最后一次错误")
+	assert.Equal(t, 4, results[0].Attempts, "应执行到最大次数")
+	assert.Equal(t, permanentErr, results[1].Err, "不可重试的错误应直接返回")
+	assert.Equal(t, 1, results[1].Attempts, "不可重试的错误只执行一次")
+	assert.Equal(t, 1, results[2].Attempts, "成功的工作函数只执行一次")
+}
+
+// TestExponentialBackoff 测试指数退避策略
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, backoff(1))
+	assert.Equal(t, 20*time.Millisecond, backoff(2))
+	assert.Equal(t, 40*time.Millisecond, backoff(3))
+	assert.Equal(t, 50*time.Millisecond, backoff(4), "等待时间不应超过上限")
+}
+
+// TestFailFast 测试快速失败模式在第一个错误后取消剩余工作
+func TestFailFast(t *testing.T) {
+	expectedErr := errors.New("测试错误")
+	var executedCount int32
+
+	works := make([]func() error, 10)
+	works[0] = func() error {
+		atomic.AddInt32(&executedCount, 1)
+		return expectedErr
+	}
+	for i := 1; i < 10; i++ {
+		works[i] = func() error {
+			atomic.AddInt32(&executedCount, 1)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+	}
+
+	// 串行执行，第一个工作失败后其余工作不应再执行
+	errs := ExecuteWithoutResult(context.Background(), 1, works, WithFailFast())
+	assert.Equal(t, expectedErr, errs[0], "应返回第一个工作的错误")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&executedCount), "失败后剩余工作不应执行")
+	for _, err := range errs[1:] {
+		assert.ErrorIs(t, err, ErrNotExecuted, "剩余工作应标记为未执行")
+	}
+}
+
+// TestNotExecuted 测试上下文取消后未开始的工作被标记为ErrNotExecuted，并记录执行耗时
+func TestNotExecuted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	works := make([]WorkFunc[int], 5)
+	for i := range works {
+		idx := i
+		works[i] = func() (int, error) {
+			time.Sleep(20 * time.Millisecond)
+			if idx == 1 {
+				cancel()
+			}
+			return idx, nil
+		}
+	}
+
+	// 串行执行，第2个工作取消上下文后剩余的工作不再开始
+	results := NewCoroutinePool[int](1).Execute(ctx, works)
+	assert.Len(t, results, 5, "结果数量应与工作函数数量相同")
+	for i, result := range results {
+		assert.Equal(t, i, result.Index, "未执行的工作也应记录索引")
+		if i <= 1 {
+			assert.NoError(t, result.Err, "已执行的工作不应有错误")
+			assert.Equal(t, 1, result.Attempts, "已执行的工作执行次数应为1")
+			assert.GreaterOrEqual(t, result.Duration, 20*time.Millisecond, "应记录执行耗时")
+			continue
+		}
+		assert.ErrorIs(t, result.Err, ErrNotExecuted, "未开始的工作应标记为未执行")
+		assert.Zero(t, result.Attempts, "未执行的工作执行次数应为0")
+		assert.Zero(t, result.Duration, "未执行的工作耗时应为0")
+	}
+
+	// 限流等待被取消时同时包装上下文错误
+	timeoutCtx, cancelTimeout := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelTimeout()
+	errs := Each(timeoutCtx, 2, []int{1, 2}, func(int) error { return nil }, WithRateLimit(1, time.Second))
+	var canceled []error
+	for _, err := range errs {
+		if err != nil {
+			canceled = append(canceled, err)
+		}
+	}
+	assert.Len(t, canceled, 1, "超出限额的工作函数应因超时而失败")
+	assert.ErrorIs(t, canceled[0], ErrNotExecuted, "限流等待被取消的工作应标记为未执行")
+	assert.ErrorIs(t, canceled[0], context.DeadlineExceeded, "应包装上下文错误")
+}
+
+// TestExecuteAll 测试ExecuteAll返回合并后的错误
+func TestExecuteAll(t *testing.T) {
+	ctx := context.Background()
+
+	// 全部成功时返回nil
+	err := ExecuteAll(ctx, 2, []func() error{
+		func() error { return nil },
+		func() error { return nil },
+	})
+	assert.NoError(t, err, "全部成功时不应返回错误")
+
+	// 多个错误会被合并
+	err1 := errors.New("错误1")
+	err2 := errors.New("错误2")
+	err = ExecuteAll(ctx, 2, []func() error{
+		func() error { return err1 },
+		func() error { return nil },
+		func() error { return err2 },
+	})
+	assert.ErrorIs(t, err, err1, "合并错误应包含错误1")
+	assert.ErrorIs(t, err, err2, "合并错误应包含错误2")
+}
+
+// TestResultHelpers 测试从结果中提取值和错误的辅助函数
+func TestResultHelpers(t *testing.T) {
+	err1 := errors.New("错误1")
+	err3 := errors.New("错误3")
+	results := Map(context.Background(), 2, []int{0, 1, 2, 3, 4}, func(i int) (int, error) {
+		switch i {
+		case 1:
+			return 0, err1
+		case 3:
+			return 0, err3
+		}
+		return i * 10, nil
+	})
+
+	assert.Equal(t, []int{0, 20, 40}, Values(results), "应按原有顺序返回成功的值")
+	assert.ErrorIs(t, Errors(results), err1, "合并错误应包含错误1")
+	assert.ErrorIs(t, Errors(results), err3, "合并错误应包含错误3")
+	assert.Equal(t, err1, FirstError(results), "应返回第一个失败结果的错误")
+
+	succeeded, failed := Partition(results)
+	assert.Len(t, succeeded, 3, "成功结果数量不正确")
+	assert.Len(t, failed, 2, "失败结果数量不正确")
+	assert.Equal(t, []int{1, 3}, []int{failed[0].Index, failed[1].Index}, "失败结果应保持原有顺序")
+
+	assert.NoError(t, Errors(succeeded), "全部成功时不应返回错误")
+	assert.NoError(t, FirstError(succeeded), "全部成功时不应返回错误")
+
+	// 未执行的工作只在没有其他错误时体现
+	notExecuted := []Result[int]{{Index: 0}, {Index: 1, Err: ErrNotExecuted}}
+	assert.Equal(t, ErrNotExecuted, Errors(notExecuted), "只有未执行的工作时应返回ErrNotExecuted")
+	notExecuted = append(notExecuted, Result[int]{Index: 2, Err: err1})
+	assert.NotErrorIs(t, Errors(notExecuted), ErrNotExecuted, "有其他错误时不应包含ErrNotExecuted")
+
+	dict := map[string]Result[int]{
+		"a": {Value: 1, Index: 2},
+		"b": {Err: err3, Index: 1},
+		"c": {Err: err1, Index: 0},
+	}
+	assert.Equal(t, map[string]int{"a": 1}, ValuesDict(dict), "应只返回成功的值")
+	assert.ErrorIs(t, ErrorsDict(dict), err1, "合并错误应包含错误1")
+	assert.ErrorIs(t, ErrorsDict(dict), err3, "合并错误应包含错误3")
+	assert.Equal(t, "错误1\n错误3", ErrorsDict(dict).Error(), "错误应按索引排序")
+
+	key, err := FirstErrorDict(dict)
+	assert.Equal(t, "c", key, "应返回索引最小的失败结果的键")
+	assert.Equal(t, err1, err, "应返回索引最小的失败结果的错误")
+
+	succeededDict, failedDict := PartitionDict(dict)
+	assert.Len(t, succeededDict, 1, "成功结果数量不正确")
+	assert.Len(t, failedDict, 2, "失败结果数量不正确")
+
+	key, err = FirstErrorDict(succeededDict)
+	assert.Empty(t, key, "全部成功时应返回零值的键")
+	assert.NoError(t, err, "全部成功时不应返回错误")
+}
+
+// TestPanicRecovery 测试工作函数panic时被恢复并转换为错误
+func TestPanicRecovery(t *testing.T) {
+	var handled int32
+	works := []WorkFunc[int]{
+		func() (int, error) { return 1, nil },
+		func() (int, error) { panic("测试panic") },
+	}
+
+	pool := NewCoroutinePool[int](2, WithOnPanic(func(err *PanicError) {
+		atomic.AddInt32(&handled, 1)
+	}))
+	results := pool.Execute(context.Background(), works)
+
+	assert.NoError(t, results[0].Err, "正常工作函数不应有错误")
+
+	var panicErr *PanicError
+	assert.ErrorAs(t, results[1].Err, &panicErr, "panic应转换为PanicError")
+	assert.Equal(t, "测试panic", panicErr.Value, "应保留panic的值")
+	assert.NotEmpty(t, panicErr.Stack, "应包含调用栈")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&handled), "应调用OnPanic回调")
+
+	// 常驻协程池同样会恢复panic
+	future, err := NewPool(1).Submit(func() error { panic(errors.New("错误值")) })
+	assert.NoError(t, err, "提交任务不应出错")
+	_, err = future.Get()
+	assert.ErrorAs(t, err, &panicErr, "panic应转换为PanicError")
+}
+
+// TestGo 测试Go、AwaitTimeout和Then
+func TestGo(t *testing.T) {
+	ctx := context.Background()
+
+	future := Go(ctx, func() (int, error) {
+		return 21, nil
+	})
+	doubled := Then(future, func(v int) (string, error) {
+		return fmt.Sprint(v * 2), nil
+	})
+	value, err := doubled.Await(ctx)
+	assert.NoError(t, err, "执行应该没有错误")
+	assert.Equal(t, "42", value, "Then应使用前一个任务的结果")
+
+	// 前一个任务失败时直接传递错误
+	expectedErr := errors.New("测试错误")
+	failed := Then(Go(ctx, func() (int, error) { return 0, expectedErr }), func(v int) (int, error) {
+		t.Error("前一个任务失败时不应执行")
+		return v, nil
+	})
+	_, err = failed.Get()
+	assert.ErrorIs(t, err, expectedErr, "应传递前一个任务的错误")
+
+	// 超时只结束等待，不取消任务
+	slow := Go(ctx, func() (int, error) {
+		time.Sleep(50 * time.Millisecond)
+		return 1, nil
+	})
+	_, err = slow.AwaitTimeout(10 * time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "等待超时应返回上下文错误")
+	value2, err := slow.AwaitTimeout(time.Second)
+	assert.NoError(t, err, "任务应继续执行完成")
+	assert.Equal(t, 1, value2)
+
+	// 上下文已取消时任务不会执行
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = Go(canceled, func() (int, error) {
+		t.Error("上下文取消后任务不应执行")
+		return 0, nil
+	}).Get()
+	assert.ErrorIs(t, err, ErrNotExecuted, "未执行的任务应返回ErrNotExecuted")
+	assert.ErrorIs(t, err, context.Canceled, "应包装上下文错误")
+
+	// Then中的panic会被恢复
+	_, err = Then(Go(ctx, func() (int, error) { return 1, nil }), func(int) (int, error) {
+		panic("测试panic")
+	}).Get()
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr, "panic应转换为PanicError")
+}
+
+// TestRateLimit 测试限流选项控制执行速率
+func TestRateLimit(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	// 每100毫秒最多执行2次：前2次立即执行，后3次每50毫秒执行一次
+	start := time.Now()
+	errs := Each(context.Background(), 5, items, func(item int) error {
+		return nil
+	}, WithRateLimit(2, 100*time.Millisecond))
+	elapsed := time.Since(start)
+
+	for _, err := range errs {
+		assert.NoError(t, err, "执行应该没有错误")
+	}
+	assert.GreaterOrEqual(t, elapsed, 140*time.Millisecond, "限流应延缓执行")
+
+	// 上下文取消时，等待令牌的工作函数返回上下文错误
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	errs = Each(ctx, 3, []int{1, 2, 3}, func(item int) error {
+		return nil
+	}, WithRateLimit(1, time.Second))
+
+	var canceledCount int
+	for _, err := range errs {
+		if errors.Is(err, context.DeadlineExceeded) {
+			canceledCount++
+		}
+	}
+	assert.Equal(t, 2, canceledCount, "超出限额的工作函数应因超时而失败")
+}
+
+// TestSlidingWindow 测试滑动窗口限制任意时间段内的开始次数
+func TestSlidingWindow(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Time
+	errs := Each(context.Background(), 6, []int{1, 2, 3, 4, 5, 6}, func(item int) error {
+		mu.Lock()
+		starts = append(starts, time.Now())
+		mu.Unlock()
+		return nil
+	}, WithSlidingWindow(3, 100*time.Millisecond))
+
+	for _, err := range errs {
+		assert.NoError(t, err, "执行应该没有错误")
+	}
+	// 窗口内的名额可以一次性用完，第4次执行要等第1次滑出窗口
+	assert.Len(t, starts, 6, "所有工作函数都应执行")
+	for i := 3; i < len(starts); i++ {
+		assert.GreaterOrEqual(t, starts[i].Sub(starts[i-3]), 95*time.Millisecond, "任意100毫秒内最多开始3次执行")
+	}
+
+	window := NewWindow(2, time.Second)
+	assert.True(t, window.Allow(), "窗口内有空闲名额")
+	assert.True(t, window.Allow(), "窗口内有空闲名额")
+	assert.False(t, window.Allow(), "窗口名额已用完")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, window.Wait(ctx), context.DeadlineExceeded, "上下文取消时应返回上下文错误")
+	assert.Nil(t, NewWindow(0, time.Second), "无效参数应返回nil")
+}
+
+// TestPoolPriority 测试高优先级任务先出队执行
+func TestPoolPriority(t *testing.T) {
+	pool := NewPool(1)
+
+	// 先占用唯一的工作协程，让后续任务在队列中排队
+	release := make(chan struct{})
+	_, err := pool.Submit(func() error {
+		<-release
+		return nil
+	})
+	assert.NoError(t, err, "提交任务不应出错")
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() error {
+		return func() error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	pool.Submit(record("low"), WithPriority(PriorityLow))
+	pool.Submit(record("normal1"))
+	pool.Submit(record("high"), WithPriority(PriorityHigh))
+	pool.Submit(record("normal2"))
+
+	close(release)
+	pool.Wait()
+
+	assert.Equal(t, []string{"high", "normal1", "normal2", "low"}, order, "应按优先级执行，相同优先级按提交顺序")
+}
+
+// TestPoolWeight 测试权重较大的任务占用多个并发名额
+func TestPoolWeight(t *testing.T) {
+	pool := NewPool(4)
+	defer pool.Shutdown(context.Background())
+
+	var running, heavyOverlap int32
+	light := func() error {
+		atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+	heavy := func() error {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&heavyOverlap, 1)
+		}
+		defer atomic.AddInt32(&running, -1)
+		time.Sleep(20 * time.Millisecond)
+		if atomic.LoadInt32(&running) > 1 {
+			atomic.AddInt32(&heavyOverlap, 1)
+		}
+		return nil
+	}
+
+	for i := 0; i < 4; i++ {
+		pool.Submit(light)
+	}
+	pool.Submit(heavy, WithWeight(4))
+	// 排在大任务之后的任务不会越过它先执行
+	pool.Submit(light)
+	// 超过最大并发数的权重按最大并发数计算，不会永远等待
+	pool.Submit(heavy, WithWeight(100))
+	pool.Wait()
+
+	assert.Zero(t, atomic.LoadInt32(&heavyOverlap), "占满名额的任务执行期间不应有其他任务")
+	assert.Zero(t, pool.Metrics().QueueLength, "所有任务都应执行完成")
+}
+
+// TestPoolAffinity 测试相同亲和键的任务串行执行，不同键的任务并行执行
+func TestPoolAffinity(t *testing.T) {
+	pool := NewPool(4)
+	defer pool.Shutdown(context.Background())
+
+	var mu sync.Mutex
+	running := make(map[string]int)
+	maxRunning := make(map[string]int)
+	var order []int
+	total, maxTotal := 0, 0
+	work := func(key string, i int) func() error {
+		return func() error {
+			mu.Lock()
+			running[key]++
+			total++
+			maxRunning[key] = max(maxRunning[key], running[key])
+			maxTotal = max(maxTotal, total)
+			if key == "a.com" {
+				order = append(order, i)
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running[key]--
+			total--
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		pool.Submit(work("a.com", i), WithAffinity("a.com"))
+		pool.Submit(work("b.com", i), WithAffinity("b.com"))
+	}
+	assert.GreaterOrEqual(t, pool.Metrics().QueueLength, 4, "等待同键任务的任务应计入队列长度")
+	assert.GreaterOrEqual(t, pool.Stats().Queued, 4, "Stats 与 Metrics 的队列长度应一致")
+	pool.Wait()
+
+	assert.Equal(t, 1, maxRunning["a.com"], "相同键的任务应串行执行")
+	assert.Equal(t, 1, maxRunning["b.com"], "相同键的任务应串行执行")
+	assert.Equal(t, 2, maxTotal, "不同键的任务应并行执行")
+	assert.Equal(t, []int{0, 1, 2, 3}, order, "相同键的任务应按提交顺序执行")
+	assert.Zero(t, pool.Metrics().QueueLength, "所有任务都应执行完成")
+}
+
+// TestPoolNestedSubmit 测试任务中向同一协程池提交并等待子任务不会死锁
+func TestPoolNestedSubmit(t *testing.T) {
+	pool := NewPool(1)
+
+	future, err := SubmitContext(pool, func(ctx context.Context) (int, error) {
+		// 唯一的工作协程正在执行当前任务，子任务只能在等待时直接执行
+		sum := 0
+		for i := 1; i <= 3; i++ {
+			child, err := SubmitContext(pool, func(ctx context.Context) (int, error) {
+				return i * 10, nil
+			}, WithTaskContext(ctx))
+			if err != nil {
+				return 0, err
+			}
+			value, err := child.Await(ctx)
+			if err != nil {
+				return 0, err
+			}
+			sum += value
+		}
+		return sum, nil
+	})
+	assert.NoError(t, err, "提交任务不应出错")
+
+	value, err := future.AwaitTimeout(time.Second)
+	assert.NoError(t, err, "嵌套等待不应死锁")
+	assert.Equal(t, 60, value, "应汇总所有子任务的结果")
+
+	pool.Wait()
+	assert.Equal(t, 0, pool.Metrics().Pending, "所有任务都应完成")
+
+	// 在任务中关闭协程池会等待自身，应返回错误而不是死锁
+	future, err = SubmitContext(pool, func(ctx context.Context) (int, error) {
+		return 0, pool.Shutdown(ctx)
+	})
+	assert.NoError(t, err, "提交任务不应出错")
+	_, err = future.AwaitTimeout(time.Second)
+	assert.ErrorIs(t, err, ErrNestedWait, "应检测到嵌套等待")
+}
+
+// TestPoolChild 测试子协程池的并发预算和关闭
+func TestPoolChild(t *testing.T) {
+	pool := NewPool(2)
+	child := pool.Child(0)
+	assert.Equal(t, 2, child.MaxWorkers(), "未设置并发数时应使用父协程池的最大并发数")
+
+	child = pool.Child(1)
+	var running, maxRunning int32
+	futures := make([]*Future[struct{}], 4)
+	for i := range futures {
+		future, err := pool.Submit(func() error {
+			sub, err := child.Submit(func() error {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&maxRunning)
+					if current <= old || atomic.CompareAndSwapInt32(&maxRunning, old, current) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			_, err = sub.Get()
+			return err
+		})
+		assert.NoError(t, err, "提交任务不应出错")
+		futures[i] = future
+	}
+	for _, future := range futures {
+		_, err := future.AwaitTimeout(time.Second)
+		assert.NoError(t, err, "父任务等待子协程池的任务不应死锁")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxRunning), "子协程池的并发数应受自身限制")
+
+	// 关闭父协程池时同时关闭子协程池
+	assert.NoError(t, pool.Shutdown(context.Background()), "关闭协程池不应出错")
+	_, err := child.Submit(func() error { return nil })
+	assert.ErrorIs(t, err, ErrPoolClosed, "子协程池应随父协程池关闭")
+}
+
+// TestPoolStats 测试协程池的累计统计和Prometheus导出
+func TestPoolStats(t *testing.T) {
+	pool := NewPool(2)
+
+	for i := 0; i < 10; i++ {
+		_, err := pool.Submit(func() error {
+			time.Sleep(time.Duration(i+1) * time.Millisecond)
+			if i%4 == 0 {
+				return errors.New("测试错误")
+			}
+			return nil
+		})
+		assert.NoError(t, err, "提交任务不应出错")
+	}
+	pool.Wait()
+
+	stats := pool.Stats()
+	assert.Equal(t, uint64(10), stats.Submitted, "应统计提交的任务数")
+	assert.Equal(t, uint64(10), stats.Completed, "应统计完成的任务数")
+	assert.Equal(t, uint64(3), stats.Errors, "应统计返回错误的任务数")
+	assert.Equal(t, 0, stats.Queued, "任务完成后队列应为空")
+	assert.Greater(t, stats.P50, time.Duration(0), "中位数应大于0")
+	assert.GreaterOrEqual(t, stats.P99, stats.P50, "99分位数不应小于中位数")
+
+	registry := prometheus.NewRegistry()
+	assert.NoError(t, registry.Register(pool.Collector("test")), "注册收集器不应出错")
+	families, err := registry.Gather()
+	assert.NoError(t, err, "采集指标不应出错")
+	values := map[string]float64{}
+	for _, family := range families {
+		metric := family.GetMetric()[0]
+		assert.Equal(t, "test", metric.GetLabel()[0].GetValue(), "应带有协程池名称标签")
+		switch {
+		case metric.GetCounter() != nil:
+			values[family.GetName()] = metric.GetCounter().GetValue()
+		case metric.GetSummary() != nil:
+			values[family.GetName()] = float64(metric.GetSummary().GetSampleCount())
+		}
+	}
+	assert.Equal(t, 10.0, values["coroutine_pool_tasks_submitted_total"], "应导出提交的任务数")
+	assert.Equal(t, 3.0, values["coroutine_pool_task_errors_total"], "应导出错误数")
+	assert.Equal(t, 10.0, values["coroutine_pool_task_duration_seconds"], "耗时统计应包含所有完成的任务")
+}
+
+// TestFilter 测试Filter函数
+func TestFilter(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6}
+
+	filtered, err := Filter(context.Background(), 3, items, func(item int) (bool, error) {
+		return item%2 == 0, nil
+	})
+	assert.NoError(t, err, "执行应该没有错误")
+	assert.Equal(t, []int{2, 4, 6}, filtered, "应保留偶数并保持顺序")
+
+	// 谓词出错的元素不保留
+	expectedErr := errors.New("过滤错误")
+	filtered, err = Filter(context.Background(), 3, items, func(item int) (bool, error) {
+		if item == 4 {
+			return true, expectedErr
+		}
+		return item%2 == 0, nil
+	})
+	assert.ErrorIs(t, err, expectedErr, "应返回谓词的错误")
+	assert.Equal(t, []int{2, 6}, filtered, "出错的元素不应保留")
+}
+
+// TestReduce 测试Reduce和Fold函数
+func TestReduce(t *testing.T) {
+	ctx := context.Background()
+	items := make([]int, 101)
+	for i := range items {
+		items[i] = i
+	}
+
+	sum := func(a, b int) (int, error) { return a + b, nil }
+
+	total, err := Reduce(ctx, 4, items, sum)
+	assert.NoError(t, err, "执行应该没有错误")
+	assert.Equal(t, 5050, total, "求和结果应正确")
+
+	// 非交换的合并函数也应保持顺序
+	concat, err := Reduce(ctx, 4, []string{"a", "b", "c", "d", "e"}, func(a, b string) (string, error) {
+		return a + b, nil
+	})
+	assert.NoError(t, err, "执行应该没有错误")
+	assert.Equal(t, "abcde", concat, "归约应保持元素顺序")
+
+	empty, err := Reduce(ctx, 4, []int{}, sum)
+	assert.NoError(t, err, "空切片不应出错")
+	assert.Equal(t, 0, empty, "空切片应返回零值")
+
+	// Fold 先映射再归约
+	lengths, err := Fold(ctx, 4, []string{"a", "bb", "ccc"}, 0, func(s string) (int, error) {
+		return len(s), nil
+	}, sum)
+	assert.NoError(t, err, "执行应该没有错误")
+	assert.Equal(t, 6, lengths, "字符串长度之和应为6")
+}
+
+// TestGroupBy 测试GroupBy函数
+func TestGroupBy(t *testing.T) {
+	items := []string{"apple", "avocado", "banana", "blueberry", "cherry"}
+
+	groups, err := GroupBy(context.Background(), 3, items, func(item string) (byte, error) {
+		return item[0], nil
+	})
+	assert.NoError(t, err, "执行应该没有错误")
+	assert.Equal(t, map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}, groups, "应按首字母分组并保持顺序")
+}
+
+// TestMapChunked 测试分块并行处理
+func TestMapReduceDict(t *testing.T) {
+	// 按域名统计文章数
+	articles := map[string]string{
+		"a": "example.com",
+		"b": "example.com",
+		"c": "golang.org",
+		"d": "example.com",
+		"e": "bad",
+	}
+
+	counts, err := MapReduceDict(context.Background(), 3, articles, func(id, host string) (string, int, error) {
+		if host == "bad" {
+			return "", 0, errors.New("无效的域名")
+		}
+		return host, 1, nil
+	}, func(a, b int) (int, error) {
+		return a + b, nil
+	})
+
+	assert.Error(t, err, "映射出错时应返回错误")
+	assert.Equal(t, map[string]int{"example.com": 3, "golang.org": 1}, counts)
+
+	total, err := FoldDict(context.Background(), 3, map[string]int{"a": 1, "b": 2, "c": 3}, 0, func(_ string, v int) (int, error) {
+		return v * 10, nil
+	}, func(a, b int) (int, error) {
+		return a + b, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 60, total)
+}
+
+func TestMapChunked(t *testing.T) {
+	items := make([]int, 10)
+	for i := range items {
+		items[i] = i
+	}
+
+	var batchCount int32
+	results := MapChunked(context.Background(), 2, items, 3, func(chunk []int) ([]int, error) {
+		atomic.AddInt32(&batchCount, 1)
+		values := make([]int, len(chunk))
+		for i, item := range chunk {
+			values[i] = item * 2
+		}
+		return values, nil
+	})
+
+	assert.Equal(t, int32(4), atomic.LoadInt32(&batchCount), "10个元素按3个一块应分为4块")
+	assert.Equal(t, len(items), len(results), "结果应按元素展开")
+	for i, result := range results {
+		assert.NoError(t, result.Err, "执行应该没有错误")
+		assert.Equal(t, i, result.Index, "结果索引应与元素索引相同")
+		assert.Equal(t, i*2, result.Value, "结果值应为元素的2倍")
+	}
+
+	// 块失败时块内所有元素都带有错误
+	expectedErr := errors.New("批量写入失败")
+	errs := EachChunked(context.Background(), 2, items, 5, func(chunk []int) error {
+		if chunk[0] == 5 {
+			return expectedErr
+		}
+		return nil
+	})
+	for i, err := range errs {
+		if i >= 5 {
+			assert.Equal(t, expectedErr, err, "失败块内的元素应带有错误")
+		} else {
+			assert.NoError(t, err, "成功块内的元素不应有错误")
+		}
+	}
+
+	// 结果数量不匹配时返回错误
+	results = MapChunked(context.Background(), 2, items, 5, func(chunk []int) ([]int, error) {
+		return chunk[:1], nil
+	})
+	assert.Error(t, results[0].Err, "结果数量不匹配时应返回错误")
+}
+
+// TestPipeline 测试多阶段流水线
+func TestPipeline(t *testing.T) {
+	p := NewPipeline(context.Background())
+
+	source := From(p, []int{1, 2, 3, 4, 5, 6})
+
+	// 第一阶段：过滤奇数
+	evens := AddStage(p, source, Stage[int, int]{
+		Name:    "filter",
+		Workers: 2,
+		Process: func(ctx context.Context, item int) (int, error) {
+			if item%2 != 0 {
+				return 0, ErrSkipItem
+			}
+			return item, nil
+		},
+	})
+
+	// 第二阶段：转换为字符串
+	labels := AddStage(p, evens, Stage[int, string]{
+		Name:    "format",
+		Workers: 3,
+		Buffer:  2,
+		Process: func(ctx context.Context, item int) (string, error) {
+			return fmt.Sprintf("item-%d", item), nil
+		},
+	})
+
+	results, err := Collect(p, labels)
+	assert.NoError(t, err, "执行应该没有错误")
+	assert.ElementsMatch(t, []string{"item-2", "item-4", "item-6"}, results, "应输出过滤并转换后的结果")
+}
+
+// TestPipelineErrors 测试流水线阶段错误的收集与快速失败
+func TestPipelineErrors(t *testing.T) {
+	expectedErr := errors.New("解析失败")
+	process := func(ctx context.Context, item int) (int, error) {
+		if item == 3 {
+			return 0, expectedErr
+		}
+		return item, nil
+	}
+
+	// 默认模式：记录错误并继续处理其他元素
+	p := NewPipeline(context.Background())
+	out := AddStage(p, From(p, []int{1, 2, 3, 4}), Stage[int, int]{Name: "parse", Process: process})
+	results, err := Collect(p, out)
+	assert.ErrorIs(t, err, expectedErr, "应返回阶段错误")
+	assert.Contains(t, err.Error(), "stage parse", "错误应包含阶段名称")
+	assert.Equal(t, []int{1, 2, 4}, results, "其他元素应继续处理")
+
+	// 快速失败模式：出错后取消流水线
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+	p = NewPipeline(context.Background(), WithFailFast())
+	out = AddStage(p, From(p, items), Stage[int, int]{Name: "parse", Process: process})
+	results, err = Collect(p, out)
+	assert.ErrorIs(t, err, expectedErr, "应返回阶段错误")
+	assert.Less(t, len(results), 99, "出错后应停止处理剩余元素")
+}
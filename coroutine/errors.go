@@ -0,0 +1,40 @@
+package coroutine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPoolClosed 协程池已关闭，不再接受新任务
+var ErrPoolClosed = errors.New("coroutine: pool is closed")
+
+// ErrNestedWait 在协程池自身（或其子协程池）的任务中等待该协程池关闭，等待的任务包括调用者自己，会导致死锁
+var ErrNestedWait = errors.New("coroutine: waiting for pool from its own task")
+
+// ErrNotExecuted 工作函数没有开始执行，例如上下文取消或快速失败后剩余的工作，
+// 可以据此只重试未完成的工作；限流等待被取消时返回的错误同时包装了上下文错误
+var ErrNotExecuted = errors.New("coroutine: work not executed")
+
+// ErrWorkTimeout 工作函数执行超过 WithWorkTimeout 设置的时间
+var ErrWorkTimeout = errors.New("coroutine: work timed out")
+
+// PanicError 工作函数发生panic时返回的错误，包含panic的值和调用栈
+type PanicError struct {
+	// Value recover() 得到的panic值
+	Value any
+	// Stack 发生panic时的调用栈
+	Stack []byte
+}
+
+// Error 实现error接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("coroutine: panic: %v", e.Value)
+}
+
+// Unwrap 当panic的值本身是error时返回该错误，便于使用errors.Is判断
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package coroutine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Future 表示一个异步任务的执行结果，任务完成后可以获取返回值和错误
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+	// inline 任务仍在排队时将其移出队列并在当前协程中执行，只有常驻协程池提交的任务才有
+	inline func() bool
+}
+
+// newFuture 创建一个未完成的Future
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{
+		done: make(chan struct{}),
+	}
+}
+
+// complete 设置结果并标记任务完成，只能调用一次
+func (f *Future[T]) complete(value T, err error) {
+	f.value = value
+	f.err = err
+	close(f.done)
+}
+
+// Done 返回一个在任务完成时关闭的通道
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get 阻塞等待任务完成并返回结果
+func (f *Future[T]) Get() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// Await 等待任务完成或上下文取消，上下文取消时返回上下文错误
+// 在常驻协程池的任务中使用任务收到的上下文等待时，尚未开始的任务直接在当前协程中执行：
+// 当前工作协程本来就在等待，这样不会增加并发数，也不会因所有工作协程都在等待排队的任务而死锁
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	if f.inline != nil && inWorker(ctx) {
+		f.inline()
+	}
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// AwaitTimeout 最多等待d时间，超时返回 context.DeadlineExceeded，任务本身不会被取消
+func (f *Future[T]) AwaitTimeout(d time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return f.Await(ctx)
+}
+
+// defaultPool Go 使用的共享协程池，首次调用时创建
+var defaultPool = sync.OnceValue(func() *Pool {
+	return NewPool(DefaultMaxWorkers())
+})
+
+// Go 在共享的常驻协程池中异步执行一次性任务并返回Future，适合不值得构造工作函数切片的单个调用，
+// 例如在渲染页面的同时预取搜索结果；ctx 在任务开始前取消时，任务不会执行并返回包装了 ErrNotExecuted 的错误
+func Go[T any](ctx context.Context, work WorkFunc[T]) *Future[T] {
+	future, err := SubmitValue(defaultPool(), work, WithTaskContext(ctx))
+	if err != nil {
+		future = newFuture[T]()
+		var zero T
+		future.complete(zero, err)
+	}
+	return future
+}
+
+// Then 在f成功完成后使用其结果执行fn，返回表示fn结果的Future；f失败时直接传递其错误，fn不会执行
+// fn中的panic会被恢复并作为 *PanicError 返回
+func Then[T, R any](f *Future[T], fn func(T) (R, error)) *Future[R] {
+	next := newFuture[R]()
+	go func() {
+		value, err := f.Get()
+		if err != nil {
+			var zero R
+			next.complete(zero, err)
+			return
+		}
+		next.complete(callWork(context.Background(), &options{}, func(context.Context) (R, error) {
+			return fn(value)
+		}))
+	}()
+	return next
+}
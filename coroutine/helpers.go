@@ -2,10 +2,12 @@ package coroutine
 
 import (
 	"context"
+	"errors"
+	"fmt"
 )
 
 // ExecuteWithoutResult 执行一组不需要返回结果的工作函数
-func ExecuteWithoutResult(ctx context.Context, maxWorkers int, works []func() error) []error {
+func ExecuteWithoutResult(ctx context.Context, maxWorkers int, works []func() error, opts ...Option) []error {
 	if len(works) == 0 {
 		return []error{}
 	}
@@ -24,7 +26,7 @@ func ExecuteWithoutResult(ctx context.Context, maxWorkers int, works []func() er
 	}
 
 	// 创建协程池并执行
-	pool := NewCoroutinePool[struct{}](maxWorkers)
+	pool := NewCoroutinePool[struct{}](maxWorkers, opts...)
 	results := pool.Execute(ctx, typedWorks)
 
 	// 提取错误信息
@@ -36,8 +38,15 @@ func ExecuteWithoutResult(ctx context.Context, maxWorkers int, works []func() er
 	return errors
 }
 
+// ExecuteAll 执行一组工作函数，返回所有错误合并后的结果，全部成功时返回nil
+// 配合 WithFailFast 使用时，第一个错误出现后剩余的工作不再执行；
+// 未执行的工作只在没有其他错误时以一个 ErrNotExecuted 体现在结果中
+func ExecuteAll(ctx context.Context, maxWorkers int, works []func() error, opts ...Option) error {
+	return joinErrors(ExecuteWithoutResult(ctx, maxWorkers, works, opts...))
+}
+
 // Map 并行执行map操作，将输入切片中的每个元素应用函数并返回结果
-func Map[T, R any](ctx context.Context, maxWorkers int, items []T, mapFunc func(T) (R, error)) []Result[R] {
+func Map[T, R any](ctx context.Context, maxWorkers int, items []T, mapFunc func(T) (R, error), opts ...Option) []Result[R] {
 	if maxWorkers <= 0 {
 		maxWorkers = DefaultMaxWorkers()
 	}
@@ -51,12 +60,12 @@ func Map[T, R any](ctx context.Context, maxWorkers int, items []T, mapFunc func(
 		}
 	}
 
-	pool := NewCoroutinePool[R](maxWorkers)
+	pool := NewCoroutinePool[R](maxWorkers, opts...)
 	return pool.Execute(ctx, works)
 }
 
 // Each 并行执行forEach操作，对输入切片中的每个元素应用函数
-func Each[T any](ctx context.Context, maxWorkers int, items []T, eachFunc func(T) error) []error {
+func Each[T any](ctx context.Context, maxWorkers int, items []T, eachFunc func(T) error, opts ...Option) []error {
 	if maxWorkers <= 0 {
 		maxWorkers = DefaultMaxWorkers()
 	}
@@ -70,11 +79,11 @@ func Each[T any](ctx context.Context, maxWorkers int, items []T, eachFunc func(T
 		}
 	}
 
-	return ExecuteWithoutResult(ctx, maxWorkers, works)
+	return ExecuteWithoutResult(ctx, maxWorkers, works, opts...)
 }
 
 // MapDict 并行执行字典的map操作，将输入字典中的每个键值对应用函数并返回结果
-func MapDict[K comparable, V, R any](ctx context.Context, maxWorkers int, dict map[K]V, mapFunc func(K, V) (R, error)) map[K]Result[R] {
+func MapDict[K comparable, V, R any](ctx context.Context, maxWorkers int, dict map[K]V, mapFunc func(K, V) (R, error), opts ...Option) map[K]Result[R] {
 	if maxWorkers <= 0 {
 		maxWorkers = DefaultMaxWorkers()
 	}
@@ -97,7 +106,7 @@ func MapDict[K comparable, V, R any](ctx context.Context, maxWorkers int, dict m
 	}
 
 	// 创建协程池并执行
-	pool := NewCoroutinePool[R](maxWorkers)
+	pool := NewCoroutinePool[R](maxWorkers, opts...)
 	results := pool.Execute(ctx, works)
 
 	// 将结果与原始键关联
@@ -111,7 +120,7 @@ func MapDict[K comparable, V, R any](ctx context.Context, maxWorkers int, dict m
 }
 
 // EachDict 并行执行字典的forEach操作，对输入字典中的每个键值对应用函数
-func EachDict[K comparable, V any](ctx context.Context, maxWorkers int, dict map[K]V, eachFunc func(K, V) error) map[K]error {
+func EachDict[K comparable, V any](ctx context.Context, maxWorkers int, dict map[K]V, eachFunc func(K, V) error, opts ...Option) map[K]error {
 	if maxWorkers <= 0 {
 		maxWorkers = DefaultMaxWorkers()
 	}
@@ -120,7 +129,7 @@ func EachDict[K comparable, V any](ctx context.Context, maxWorkers int, dict map
 	results := MapDict(ctx, maxWorkers, dict, func(k K, v V) (struct{}, error) {
 		err := eachFunc(k, v)
 		return struct{}{}, err
-	})
+	}, opts...)
 
 	// 提取错误信息
 	errorMap := make(map[K]error, len(results))
@@ -129,4 +138,228 @@ func EachDict[K comparable, V any](ctx context.Context, maxWorkers int, dict map
 	}
 
 	return errorMap
-}
\ No newline at end of file
+}
+
+// MapReduceDict 并行映射字典中的每个键值对，得到 (输出键, 值)，再按输出键使用合并函数归约
+// 不同输出键的归约并行执行；由于字典遍历顺序不固定，合并函数应满足交换律和结合律
+// 映射或合并出错的值不会参与结果，所有错误合并后返回
+func MapReduceDict[K comparable, V any, K2 comparable, R any](ctx context.Context, maxWorkers int, dict map[K]V, mapFunc func(K, V) (K2, R, error), combine func(R, R) (R, error), opts ...Option) (map[K2]R, error) {
+	type pair struct {
+		key   K2
+		value R
+	}
+
+	mapped := MapDict(ctx, maxWorkers, dict, func(k K, v V) (pair, error) {
+		key, value, err := mapFunc(k, v)
+		return pair{key: key, value: value}, err
+	}, opts...)
+
+	// 按输出键分组
+	var errs []error
+	groups := make(map[K2][]R)
+	for _, result := range mapped {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		groups[result.Value.key] = append(groups[result.Value.key], result.Value.value)
+	}
+
+	// 每个输出键的值依次合并，不同的键并行处理
+	reduced := MapDict(ctx, maxWorkers, groups, func(_ K2, values []R) (R, error) {
+		acc := values[0]
+		for _, value := range values[1:] {
+			var err error
+			if acc, err = combine(acc, value); err != nil {
+				return acc, err
+			}
+		}
+		return acc, nil
+	}, opts...)
+
+	out := make(map[K2]R, len(reduced))
+	for key, result := range reduced {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		out[key] = result.Value
+	}
+
+	return out, errors.Join(errs...)
+}
+
+// FoldDict 并行映射字典中的每个键值对，再使用满足交换律和结合律的合并函数全局归约
+// identity 为合并函数的单位元，空字典时直接返回；任一映射出错时返回第一个错误
+func FoldDict[K comparable, V, R any](ctx context.Context, maxWorkers int, dict map[K]V, identity R, mapFunc func(K, V) (R, error), combine func(R, R) (R, error), opts ...Option) (R, error) {
+	mapped := MapDict(ctx, maxWorkers, dict, mapFunc, opts...)
+
+	values := make([]R, 0, len(mapped)+1)
+	values = append(values, identity)
+	for _, result := range mapped {
+		if result.Err != nil {
+			return identity, result.Err
+		}
+		values = append(values, result.Value)
+	}
+
+	return Reduce(ctx, maxWorkers, values, combine, opts...)
+}
+
+// Filter 并行执行过滤操作，返回谓词函数为true的元素，保持原有顺序
+// 谓词函数返回错误的元素不会被保留，所有错误合并后返回
+func Filter[T any](ctx context.Context, maxWorkers int, items []T, predicate func(T) (bool, error), opts ...Option) ([]T, error) {
+	results := Map(ctx, maxWorkers, items, predicate, orderedOptions(opts)...)
+
+	filtered := make([]T, 0, len(items))
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		if result.Value {
+			filtered = append(filtered, items[i])
+		}
+	}
+
+	return filtered, errors.Join(errs...)
+}
+
+// Reduce 使用满足结合律的合并函数并行归约切片
+// 每一轮将相邻的两个元素两两合并，共需要 log2(n) 轮；空切片返回零值
+func Reduce[T any](ctx context.Context, maxWorkers int, items []T, combine func(T, T) (T, error), opts ...Option) (T, error) {
+	var zero T
+	if len(items) == 0 {
+		return zero, nil
+	}
+
+	current := items
+	for len(current) > 1 {
+		pairCount := len(current) / 2
+		works := make([]WorkFunc[T], pairCount)
+		for i := 0; i < pairCount; i++ {
+			left, right := current[2*i], current[2*i+1]
+			works[i] = func() (T, error) {
+				return combine(left, right)
+			}
+		}
+
+		results := NewCoroutinePool[T](maxWorkers, orderedOptions(opts)...).Execute(ctx, works)
+
+		next := make([]T, 0, pairCount+1)
+		for _, result := range results {
+			if result.Err != nil {
+				return zero, result.Err
+			}
+			next = append(next, result.Value)
+		}
+		// 奇数个元素时，最后一个直接进入下一轮
+		if len(current)%2 == 1 {
+			next = append(next, current[len(current)-1])
+		}
+
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+		current = next
+	}
+
+	return current[0], nil
+}
+
+// Fold 先并行将每个元素映射为R，再使用满足结合律的合并函数归约
+// identity 为合并函数的单位元，空切片时直接返回
+func Fold[T, R any](ctx context.Context, maxWorkers int, items []T, identity R, mapFunc func(T) (R, error), combine func(R, R) (R, error), opts ...Option) (R, error) {
+	results := Map(ctx, maxWorkers, items, mapFunc, orderedOptions(opts)...)
+
+	values := make([]R, 0, len(results)+1)
+	values = append(values, identity)
+	for _, result := range results {
+		if result.Err != nil {
+			return identity, result.Err
+		}
+		values = append(values, result.Value)
+	}
+
+	return Reduce(ctx, maxWorkers, values, combine, opts...)
+}
+
+// GroupBy 并行计算每个元素的分组键，返回按键分组的元素，组内保持原有顺序
+// 计算键出错的元素不会被分组，所有错误合并后返回
+func GroupBy[T any, K comparable](ctx context.Context, maxWorkers int, items []T, keyFunc func(T) (K, error), opts ...Option) (map[K][]T, error) {
+	results := Map(ctx, maxWorkers, items, keyFunc, orderedOptions(opts)...)
+
+	groups := make(map[K][]T)
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		groups[result.Value] = append(groups[result.Value], items[i])
+	}
+
+	return groups, errors.Join(errs...)
+}
+
+// MapChunked 将输入切片按chunkSize分块后并行处理，适合大量小元素或批量接口（如批量写入）
+// 批处理函数必须返回与输入块等长的结果切片；结果按元素展开，与Map的返回格式一致，
+// 某个块失败时该块内所有元素的结果都带有该错误
+func MapChunked[T, R any](ctx context.Context, maxWorkers int, items []T, chunkSize int, batchFunc func([]T) ([]R, error), opts ...Option) []Result[R] {
+	if chunkSize <= 0 {
+		chunkSize = 1
+	}
+
+	chunks := chunkSlice(items, chunkSize)
+	chunkResults := Map(ctx, maxWorkers, chunks, func(chunk []T) ([]R, error) {
+		values, err := batchFunc(chunk)
+		if err == nil && len(values) != len(chunk) {
+			err = fmt.Errorf("coroutine: batch returned %d results for %d items", len(values), len(chunk))
+		}
+		return values, err
+	}, orderedOptions(opts)...)
+
+	// 将块结果展开为逐元素结果
+	results := make([]Result[R], len(items))
+	for chunkIndex, chunkResult := range chunkResults {
+		start := chunkIndex * chunkSize
+		for i := range chunks[chunkIndex] {
+			result := Result[R]{
+				Err:      chunkResult.Err,
+				Index:    start + i,
+				Attempts: chunkResult.Attempts,
+				Duration: chunkResult.Duration,
+			}
+			if chunkResult.Err == nil {
+				result.Value = chunkResult.Value[i]
+			}
+			results[start+i] = result
+		}
+	}
+
+	return results
+}
+
+// EachChunked 将输入切片按chunkSize分块后并行处理，返回每个元素对应的错误
+func EachChunked[T any](ctx context.Context, maxWorkers int, items []T, chunkSize int, batchFunc func([]T) error, opts ...Option) []error {
+	results := MapChunked(ctx, maxWorkers, items, chunkSize, func(chunk []T) ([]struct{}, error) {
+		return make([]struct{}, len(chunk)), batchFunc(chunk)
+	}, opts...)
+
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return errs
+}
+
+// chunkSlice 将切片按指定大小分块，块共享原切片的底层数组
+func chunkSlice[T any](items []T, chunkSize int) [][]T {
+	chunks := make([][]T, 0, (len(items)+chunkSize-1)/chunkSize)
+	for start := 0; start < len(items); start += chunkSize {
+		end := min(start+chunkSize, len(items))
+		chunks = append(chunks, items[start:end:end])
+	}
+	return chunks
+}
@@ -0,0 +1,64 @@
+package coroutine
+
+import (
+	"context"
+	"time"
+)
+
+// WorkInfo 描述一次工作函数的执行，传递给 Hooks 中的回调
+type WorkInfo struct {
+	// Index 工作在本次调用中的位置，常驻协程池和流水线中为-1
+	Index int
+	// NodeID 树处理中的节点ID
+	NodeID string
+	// Stage 流水线中的阶段名称
+	Stage string
+	// Attempt 第几次执行，从1开始，启用重试时可能大于1
+	Attempt int
+	// Start 本次执行的开始时间（限流等待之后）
+	Start time.Time
+	// Duration 本次执行的耗时，仅在AfterWork中有效
+	Duration time.Duration
+	// Err 本次执行返回的错误，仅在AfterWork中有效
+	Err error
+}
+
+// Hooks 工作函数执行前后的回调，可用于创建链路追踪的span或输出结构化日志，
+// 不需要逐个包装工作函数；启用重试时每次执行都会调用
+type Hooks struct {
+	// BeforeWork 工作函数执行前调用，返回的上下文会传递给接收上下文的工作函数（如流水线阶段）和AfterWork
+	BeforeWork func(ctx context.Context, info WorkInfo) context.Context
+	// AfterWork 工作函数执行后调用
+	AfterWork func(ctx context.Context, info WorkInfo)
+}
+
+// WithHooks 设置工作函数执行前后的回调
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) {
+		o.hooks = hooks
+	}
+}
+
+// callWithHooks 在工作函数执行前后调用回调
+func callWithHooks[T any](ctx context.Context, o *options, info WorkInfo, work contextWork[T]) (T, error) {
+	hooks := o.hooks
+	if hooks.BeforeWork == nil && hooks.AfterWork == nil {
+		return work(ctx)
+	}
+
+	info.Start = time.Now()
+	if hooks.BeforeWork != nil {
+		if hookCtx := hooks.BeforeWork(ctx, info); hookCtx != nil {
+			ctx = hookCtx
+		}
+	}
+
+	value, err := work(ctx)
+
+	if hooks.AfterWork != nil {
+		info.Duration = time.Since(info.Start)
+		info.Err = err
+		hooks.AfterWork(ctx, info)
+	}
+	return value, err
+}
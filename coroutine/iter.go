@@ -0,0 +1,90 @@
+package coroutine
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+)
+
+// MapSeq 并行执行map操作并以迭代器形式按完成顺序返回结果，Result.Index为元素在输入序列中的位置
+// 输入序列按需读取，同一时刻最多缓存maxWorkers个结果，适合数据量很大或流式产生的输入；
+// 提前退出range循环会取消剩余的工作，并等待正在执行的工作结束后返回
+func MapSeq[T, R any](ctx context.Context, maxWorkers int, items iter.Seq[T], mapFunc func(T) (R, error), opts ...Option) iter.Seq[Result[R]] {
+	if maxWorkers <= 0 {
+		maxWorkers = DefaultMaxWorkers()
+	}
+	o := newOptions(opts)
+
+	return func(yield func(Result[R]) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type job struct {
+			index int
+			item  T
+		}
+		jobs := make(chan job)
+		results := make(chan Result[R], maxWorkers)
+
+		// 按需读取输入序列
+		go func() {
+			defer close(jobs)
+			index := 0
+			for item := range items {
+				select {
+				case jobs <- job{index: index, item: item}:
+				case <-ctx.Done():
+					return
+				}
+				index++
+			}
+		}()
+
+		// 输入序列的长度未知，进度总数为-1
+		progress := newProgressTracker(o, -1)
+		var wg sync.WaitGroup
+		for i := 0; i < maxWorkers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					if ctx.Err() != nil {
+						return
+					}
+
+					start := time.Now()
+					value, attempts, err := runWork(ctx, o, WorkInfo{Index: j.index}, func(context.Context) (R, error) {
+						return mapFunc(j.item)
+					})
+					if err != nil && o.failFast {
+						cancel()
+					}
+					progress.add()
+
+					select {
+					case results <- Result[R]{Value: value, Err: err, Index: j.index, Attempts: attempts, Duration: time.Since(start)}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for result := range results {
+			if !yield(result) {
+				cancel()
+				break
+			}
+		}
+
+		// 等待所有工作协程退出
+		for range results {
+		}
+	}
+}
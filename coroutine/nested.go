@@ -0,0 +1,79 @@
+package coroutine
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+// workerKey 上下文中标记当前正在执行的常驻协程池任务，值为任务所属的 *Pool
+type workerKey struct{}
+
+// inWorker 判断上下文是否来自常驻协程池中正在执行的任务
+func inWorker(ctx context.Context) bool {
+	_, ok := ctx.Value(workerKey{}).(*Pool)
+	return ok
+}
+
+// SubmitContext 向协程池提交一个接收上下文的任务，任务收到的上下文派生自 WithTaskContext 设置的上下文
+// 任务中再向任意常驻协程池提交任务时，应把收到的上下文通过 WithTaskContext 传下去并使用 Future.Await(ctx) 等待，
+// 尚未开始的子任务会直接在当前工作协程中执行，嵌套提交不会耗尽工作协程而死锁
+func SubmitContext[T any](p *Pool, work func(ctx context.Context) (T, error), opts ...SubmitOption) (*Future[T], error) {
+	return submit(p, contextWork[T](work), opts)
+}
+
+// Child 创建子协程池，用于按层级分配并发预算，例如父协程池处理站点、子协程池抓取页面
+// 子协程池有独立的工作协程，父协程池的任务等待子协程池的任务时不会占用彼此的名额；
+// maxWorkers小于等于0时使用父协程池的最大并发数，
+// 子协程池继承父协程池的选项（WithRateLimit 等限流器与父协程池共享），opts追加在其后。
+// 父协程池 Shutdown 时先等待自身的任务完成，再关闭所有子协程池
+func (p *Pool) Child(maxWorkers int, opts ...Option) *Pool {
+	if maxWorkers <= 0 {
+		maxWorkers = p.maxWorkers
+	}
+
+	childOpts := make([]Option, 0, len(p.opts)+len(opts))
+	childOpts = append(childOpts, p.opts...)
+	child := NewPool(maxWorkers, append(childOpts, opts...)...)
+	child.parent = p
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		child.Shutdown(context.Background())
+	}
+	p.children = append(p.children, child)
+	return child
+}
+
+// runsTask 判断上下文是否来自协程池自身或其子协程池中正在执行的任务
+func (p *Pool) runsTask(ctx context.Context) bool {
+	for pool, _ := ctx.Value(workerKey{}).(*Pool); pool != nil; pool = pool.parent {
+		if pool == p {
+			return true
+		}
+	}
+	return false
+}
+
+// runInline 任务仍在排队时将其移出队列并在当前协程中执行，任务已被工作协程取出时返回false
+func (p *Pool) runInline(task *queuedTask) bool {
+	p.mu.Lock()
+	if task.index < 0 {
+		p.mu.Unlock()
+		return false
+	}
+	heap.Remove(&p.queue, task.index)
+	p.mu.Unlock()
+
+	start := time.Now()
+	err := task.run()
+	elapsed := time.Since(start)
+
+	p.mu.Lock()
+	p.finish(task, elapsed, err)
+	// 放行的同键任务可能需要启动工作协程
+	p.scaleUp()
+	p.mu.Unlock()
+	return true
+}
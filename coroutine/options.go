@@ -0,0 +1,280 @@
+package coroutine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// contextWork 接收上下文的工作函数，用于在内部传递每个工作函数派生的上下文
+type contextWork[T any] func(ctx context.Context) (T, error)
+
+// withoutContext 将不接收上下文的工作函数适配为contextWork
+func withoutContext[T any](work WorkFunc[T]) contextWork[T] {
+	return func(context.Context) (T, error) {
+		return work()
+	}
+}
+
+// Option 配置协程池执行行为的选项
+type Option func(*options)
+
+// options 协程池的执行选项
+type options struct {
+	retry      *RetryPolicy
+	failFast   bool
+	onPanic    func(*PanicError)
+	limiter    *rateLimiter
+	window     *Window
+	scaling    *ScalingPolicy
+	timeout    time.Duration
+	unordered  bool
+	onProgress ProgressFunc
+	hooks      Hooks
+
+	workStealing bool
+
+	// 树处理的深度限制
+	maxDepth     int
+	depthLimited bool
+}
+
+// newOptions 根据选项列表构建执行选项
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithFailFast 启用快速失败模式：任一工作函数返回错误后取消剩余未开始的工作
+// 行为类似 errgroup.WithContext，已在执行的工作函数会继续运行到结束
+func WithFailFast() Option {
+	return func(o *options) {
+		o.failFast = true
+	}
+}
+
+// WithOnPanic 设置工作函数发生panic时的回调，可用于记录日志或上报监控
+// 无论是否设置回调，panic都会被恢复并作为 *PanicError 写入结果的错误
+func WithOnPanic(handler func(*PanicError)) Option {
+	return func(o *options) {
+		o.onPanic = handler
+	}
+}
+
+// WithUnordered 按完成顺序返回结果，结果的Index字段记录工作函数的原始位置
+// 结果集不再按输入长度预先分配，上下文取消后未执行的工作不会出现在结果中
+func WithUnordered() Option {
+	return func(o *options) {
+		o.unordered = true
+	}
+}
+
+// orderedOptions 在选项末尾追加有序模式，用于依赖结果顺序的辅助函数
+func orderedOptions(opts []Option) []Option {
+	ordered := make([]Option, 0, len(opts)+1)
+	ordered = append(ordered, opts...)
+	return append(ordered, func(o *options) {
+		o.unordered = false
+	})
+}
+
+// WithRateLimit 限制工作函数的执行速率为每个周期最多n次（允许n次的突发），重试也计入限额
+// 同一个选项值在多次调用之间共享同一个限流器，可用于对外部API的全局限流
+func WithRateLimit(n int, per time.Duration) Option {
+	if n <= 0 || per <= 0 {
+		return nil
+	}
+	limiter := newRateLimiter(n, per)
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithWorkTimeout 为每个工作函数设置超时时间，启用重试时每次执行单独计时
+// 超时的工作函数立即返回 ErrWorkTimeout，不会阻塞整个调用；接收上下文的工作函数
+// （如流水线阶段）会收到带截止时间的派生上下文，应在上下文结束后尽快返回
+func WithWorkTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// BackoffFunc 根据已失败的次数（从1开始）返回下一次重试前的等待时间
+type BackoffFunc func(attempt int) time.Duration
+
+// RetryPolicy 定义单个工作函数失败后的重试策略
+type RetryPolicy struct {
+	// MaxAttempts 最大执行次数（包含第一次执行），小于等于1时不重试
+	MaxAttempts int
+	// Backoff 重试等待策略，为nil时立即重试
+	Backoff BackoffFunc
+	// Retryable 判断错误是否可以重试，为nil时所有错误都会重试
+	Retryable func(error) bool
+}
+
+// WithRetry 为每个工作函数启用重试，Result.Attempts 记录实际执行次数
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = &policy
+	}
+}
+
+// ConstantBackoff 返回固定间隔的重试等待策略
+func ConstantBackoff(interval time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return interval
+	}
+}
+
+// ExponentialBackoff 返回指数增长的重试等待策略，等待时间为 base * 2^(attempt-1)，不超过 maxDelay
+func ExponentialBackoff(base, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt; i++ {
+			delay *= 2
+			if maxDelay > 0 && delay >= maxDelay {
+				return maxDelay
+			}
+		}
+		if maxDelay > 0 && delay > maxDelay {
+			return maxDelay
+		}
+		return delay
+	}
+}
+
+// safeCall 执行工作函数并将panic转换为 *PanicError，启用限流时先等待令牌和滑动窗口名额，
+// 第一次执行前的等待被取消时返回包装了 ErrNotExecuted 的错误；设置了回调时在执行前后调用
+func safeCall[T any](ctx context.Context, o *options, info WorkInfo, work contextWork[T]) (value T, err error) {
+	if o.limiter != nil {
+		if err := o.limiter.wait(ctx); err != nil {
+			return value, notExecuted(info, err)
+		}
+	}
+	if o.window != nil {
+		if err := o.window.Wait(ctx); err != nil {
+			return value, notExecuted(info, err)
+		}
+	}
+
+	return callWithHooks(ctx, o, info, func(ctx context.Context) (T, error) {
+		return callWithTimeout(ctx, o, work)
+	})
+}
+
+// notExecuted 第一次执行前的等待失败时用 ErrNotExecuted 包装错误，重试前的等待失败时原样返回
+func notExecuted(info WorkInfo, err error) error {
+	if info.Attempt > 1 {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrNotExecuted, err)
+}
+
+// callWithTimeout 执行工作函数，设置了超时时间时在派生的上下文中执行
+func callWithTimeout[T any](ctx context.Context, o *options, work contextWork[T]) (value T, err error) {
+	if o.timeout <= 0 {
+		return callWork(ctx, o, work)
+	}
+
+	workCtx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := callWork(workCtx, o, work)
+		done <- outcome{value: value, err: err}
+	}()
+
+	// 工作函数因派生上下文超时而返回的错误统一转换为ErrWorkTimeout
+	timeoutErr := func(err error) error {
+		if err != nil && ctx.Err() == nil && workCtx.Err() == context.DeadlineExceeded {
+			return ErrWorkTimeout
+		}
+		return err
+	}
+
+	select {
+	case out := <-done:
+		return out.value, timeoutErr(out.err)
+	case <-workCtx.Done():
+		// 工作函数恰好在截止时完成时仍返回其结果
+		select {
+		case out := <-done:
+			return out.value, timeoutErr(out.err)
+		default:
+		}
+		if err := ctx.Err(); err != nil {
+			return value, err
+		}
+		return value, ErrWorkTimeout
+	}
+}
+
+// callWork 执行工作函数并恢复panic
+func callWork[T any](ctx context.Context, o *options, work contextWork[T]) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &PanicError{Value: r, Stack: debug.Stack()}
+			if o.onPanic != nil {
+				o.onPanic(panicErr)
+			}
+			err = panicErr
+		}
+	}()
+	return work(ctx)
+}
+
+// runWork 按照选项执行工作函数，返回结果、执行次数和错误，info 描述该工作并传递给回调
+func runWork[T any](ctx context.Context, o *options, info WorkInfo, work contextWork[T]) (T, int, error) {
+	info.Attempt = 1
+	value, err := safeCall(ctx, o, info, work)
+	if errors.Is(err, ErrNotExecuted) {
+		return value, 0, err
+	}
+	if err == nil || o.retry == nil {
+		return value, 1, err
+	}
+
+	policy := o.retry
+	attempts := 1
+	for attempts < policy.MaxAttempts {
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			break
+		}
+
+		// 等待重试间隔，上下文取消时放弃重试
+		if policy.Backoff != nil {
+			if delay := policy.Backoff(attempts); delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return value, attempts, err
+				}
+			}
+		} else if ctx.Err() != nil {
+			break
+		}
+
+		attempts++
+		info.Attempt = attempts
+		value, err = safeCall(ctx, o, info, work)
+		if err == nil {
+			break
+		}
+	}
+
+	return value, attempts, err
+}
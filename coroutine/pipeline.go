@@ -0,0 +1,177 @@
+package coroutine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSkipItem 阶段处理函数返回该错误时，当前元素被丢弃且不记录为错误，可用于在流水线中过滤数据
+var ErrSkipItem = errors.New("coroutine: skip item")
+
+// Stage 定义流水线中的一个处理阶段，将T类型的输入转换为U类型的输出
+type Stage[T, U any] struct {
+	// Name 阶段名称，用于错误信息
+	Name string
+	// Workers 阶段的并发数，小于等于0时为1
+	Workers int
+	// Buffer 输出通道的缓冲大小，缓冲满时上游阻塞，形成背压
+	Buffer int
+	// Process 处理单个元素
+	Process func(ctx context.Context, item T) (U, error)
+}
+
+// Pipeline 由多个阶段通过通道连接而成的流水线
+// 每个阶段有独立的并发数和缓冲区，适合 抓取→解析→增强→存储 这类流式处理
+type Pipeline struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	options *options
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewPipeline 创建流水线，支持 WithFailFast、WithRetry、WithRateLimit、WithOnPanic、WithWorkTimeout 等选项
+// 启用 WithFailFast 时任一阶段出错都会取消整条流水线
+func NewPipeline(ctx context.Context, opts ...Option) *Pipeline {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Pipeline{
+		ctx:     ctx,
+		cancel:  cancel,
+		options: newOptions(opts),
+	}
+}
+
+// Context 返回流水线的上下文，流水线被取消或出错（快速失败模式）时结束
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Cancel 取消流水线中所有阶段
+func (p *Pipeline) Cancel() {
+	p.cancel()
+}
+
+// Wait 等待所有阶段结束，返回所有阶段错误合并后的结果
+// 调用前必须保证最后一个阶段的输出被消费（例如使用Collect或Drain），否则会因背压而阻塞
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return errors.Join(p.errs...)
+}
+
+// addError 记录阶段错误，快速失败模式下取消流水线
+func (p *Pipeline) addError(err error) {
+	p.mu.Lock()
+	p.errs = append(p.errs, err)
+	p.mu.Unlock()
+
+	if p.options.failFast {
+		p.cancel()
+	}
+}
+
+// From 将切片作为流水线的数据源
+func From[T any](p *Pipeline, items []T) <-chan T {
+	out := make(chan T)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+		for _, item := range items {
+			select {
+			case out <- item:
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// AddStage 在流水线中添加一个阶段，从in读取输入并返回该阶段的输出通道
+func AddStage[T, U any](p *Pipeline, in <-chan T, stage Stage[T, U]) <-chan U {
+	workers := stage.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	buffer := stage.Buffer
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	out := make(chan U, buffer)
+	var stageWG sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		stageWG.Add(1)
+		go func() {
+			defer stageWG.Done()
+			for {
+				var item T
+				var ok bool
+				select {
+				case item, ok = <-in:
+					if !ok {
+						return
+					}
+				case <-p.ctx.Done():
+					return
+				}
+
+				value, _, err := runWork(p.ctx, p.options, WorkInfo{Index: -1, Stage: stage.Name}, func(ctx context.Context) (U, error) {
+					return stage.Process(ctx, item)
+				})
+				if errors.Is(err, ErrSkipItem) {
+					continue
+				}
+				if err != nil {
+					if stage.Name != "" {
+						err = fmt.Errorf("stage %s: %w", stage.Name, err)
+					}
+					p.addError(err)
+					continue
+				}
+
+				select {
+				case out <- value:
+				case <-p.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// 所有工作协程结束后关闭输出通道
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		stageWG.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// Collect 收集最后一个阶段的全部输出并等待流水线结束
+func Collect[T any](p *Pipeline, in <-chan T) ([]T, error) {
+	var items []T
+	for item := range in {
+		items = append(items, item)
+	}
+	return items, p.Wait()
+}
+
+// Drain 消费并丢弃最后一个阶段的全部输出，然后等待流水线结束
+// 适用于最后一个阶段本身完成存储等副作用的场景
+func Drain[T any](p *Pipeline, in <-chan T) error {
+	for range in {
+	}
+	return p.Wait()
+}
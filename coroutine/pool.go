@@ -4,11 +4,13 @@ import (
 	"context"
 	"runtime"
 	"sync"
+	"time"
 )
 
 // CoroutinePool 协程池，用于控制并发执行的协程数量
 type CoroutinePool[T any] struct {
 	maxWorkers int
+	options    *options
 	results    []Result[T]
 	mutex      sync.Mutex
 }
@@ -21,82 +23,96 @@ func DefaultMaxWorkers() int {
 	return numCPU * 2
 }
 
-// NewCoroutinePool 创建一个新的协程池
-func NewCoroutinePool[T any](maxWorkers int) *CoroutinePool[T] {
+// NewCoroutinePool 创建一个新的协程池，可通过选项配置重试等执行行为
+func NewCoroutinePool[T any](maxWorkers int, opts ...Option) *CoroutinePool[T] {
 	if maxWorkers <= 0 {
 		maxWorkers = DefaultMaxWorkers() // 使用基于CPU核心数的默认值
 	}
 
 	return &CoroutinePool[T]{
 		maxWorkers: maxWorkers,
+		options:    newOptions(opts),
 		results:    make([]Result[T], 0),
 	}
 }
 
 // Execute 执行一组工作函数，控制并发数量，并等待所有协程完成
+// 默认结果按工作函数的顺序排列，上下文取消或快速失败后没有开始执行的工作，其结果的错误为 ErrNotExecuted；
+// 启用 WithUnordered 时按完成顺序排列，且只包含已执行的工作
 func (p *CoroutinePool[T]) Execute(ctx context.Context, works []WorkFunc[T]) []Result[T] {
 	if len(works) == 0 {
 		return []Result[T]{}
 	}
 
-	// 重置结果集
+	// 重置结果集，无序模式下按完成顺序追加
 	p.mutex.Lock()
-	p.results = make([]Result[T], len(works))
+	if p.options.unordered {
+		p.results = make([]Result[T], 0)
+	} else {
+		p.results = make([]Result[T], len(works))
+	}
 	p.mutex.Unlock()
 
-	// 创建工作通道和等待组
-	workChan := make(chan int, len(works))
-	var wg sync.WaitGroup
+	// 启用快速失败时，第一个错误出现后取消剩余工作
+	var onError func()
+	if p.options.failFast {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		onError = cancel
+	}
 
-	// 启动工作协程
+	progress := newProgressTracker(p.options, len(works))
 	workerCount := p.maxWorkers
 	if workerCount > len(works) {
 		workerCount = len(works)
 	}
 
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
-		go p.worker(ctx, &wg, workChan, works)
+	if p.options.workStealing {
+		p.executeStealing(ctx, workerCount, works, onError, progress)
+		p.markNotExecuted()
+		return p.results
 	}
 
-	// 启动一个协程来监控上下文取消
-	// 创建完成通道
-	doneChan := make(chan struct{})
-
-	// 启动一个协程来监控上下文取消和工作完成
-	go func() {
-		select {
-		case <-ctx.Done():
-			// 上下文被取消
-			close(workChan)
-		case <-doneChan:
-			// 所有工作已发送完成
-			close(workChan)
-		}
-	}()
-
-	// 发送工作索引到通道
-	for i := 0; i < len(works); i++ {
-		select {
-		case workChan <- i:
-			// 成功发送工作
-		case <-ctx.Done():
-			// 上下文已取消，退出循环
-			break
-		}
+	// 创建工作通道并放入所有工作索引
+	// 通道容量与工作数量相同，发送不会阻塞
+	workChan := make(chan int, len(works))
+	for i := range works {
+		workChan <- i
 	}
+	close(workChan)
 
-	// 通知所有工作已发送完成
-	close(doneChan)
+	// 启动工作协程
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go p.worker(ctx, &wg, workChan, works, onError, progress)
+	}
 
 	// 等待所有工作完成
 	wg.Wait()
+	p.markNotExecuted()
 
 	return p.results
 }
 
-// worker 工作协程，从通道获取工作并执行
-func (p *CoroutinePool[T]) worker(ctx context.Context, wg *sync.WaitGroup, workChan <-chan int, works []WorkFunc[T]) {
+// markNotExecuted 将有序结果中没有开始执行的工作标记为 ErrNotExecuted
+func (p *CoroutinePool[T]) markNotExecuted() {
+	if p.options.unordered {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for i := range p.results {
+		if p.results[i].Attempts == 0 && p.results[i].Err == nil {
+			p.results[i] = Result[T]{Index: i, Err: ErrNotExecuted}
+		}
+	}
+}
+
+// worker 工作协程，从通道获取工作并执行，onError 在工作函数返回错误时调用，progress 统计执行进度
+func (p *CoroutinePool[T]) worker(ctx context.Context, wg *sync.WaitGroup, workChan <-chan int, works []WorkFunc[T], onError func(), progress *progressTracker) {
 	defer wg.Done()
 
 	for {
@@ -107,21 +123,43 @@ func (p *CoroutinePool[T]) worker(ctx context.Context, wg *sync.WaitGroup, workC
 				return
 			}
 
-			// 执行工作函数
-			value, err := works[index]()
-
-			// 保存结果
-			p.mutex.Lock()
-			p.results[index] = Result[T]{
-				Value: value,
-				Err:   err,
-				Index: index,
+			// 上下文已取消时不再执行新的工作
+			if ctx.Err() != nil {
+				return
 			}
-			p.mutex.Unlock()
+
+			p.execute(ctx, index, works, onError, progress)
 
 		case <-ctx.Done():
 			// 上下文被取消
 			return
 		}
 	}
-}
\ No newline at end of file
+}
+
+// execute 执行指定位置的工作函数并保存结果
+func (p *CoroutinePool[T]) execute(ctx context.Context, index int, works []WorkFunc[T], onError func(), progress *progressTracker) {
+	start := time.Now()
+	value, attempts, err := runWork(ctx, p.options, WorkInfo{Index: index}, withoutContext(works[index]))
+	elapsed := time.Since(start)
+	if err != nil && onError != nil {
+		onError()
+	}
+
+	// 保存结果
+	result := Result[T]{
+		Value:    value,
+		Err:      err,
+		Index:    index,
+		Attempts: attempts,
+		Duration: elapsed,
+	}
+	p.mutex.Lock()
+	if p.options.unordered {
+		p.results = append(p.results, result)
+	} else {
+		p.results[index] = result
+	}
+	p.mutex.Unlock()
+	progress.add()
+}
@@ -0,0 +1,100 @@
+package coroutine
+
+import (
+	"context"
+)
+
+// 常用的任务优先级，数值越大越先执行
+const (
+	PriorityLow    = -10
+	PriorityNormal = 0
+	PriorityHigh   = 10
+)
+
+// SubmitOption 配置单个提交任务的选项
+type SubmitOption func(*submitOptions)
+
+// submitOptions 单个任务的提交选项
+type submitOptions struct {
+	priority int
+	ctx      context.Context
+	weight   int
+	affinity string
+}
+
+// WithPriority 设置任务优先级，优先级高的任务先出队执行，相同优先级按提交顺序执行
+func WithPriority(priority int) SubmitOption {
+	return func(o *submitOptions) {
+		o.priority = priority
+	}
+}
+
+// WithTaskContext 设置任务执行时使用的上下文，上下文中的值（如追踪信息）会传递给 Hooks，
+// 上下文取消后尚未开始的任务不再执行，限流等待和重试会提前结束；默认使用 context.Background()
+func WithTaskContext(ctx context.Context) SubmitOption {
+	return func(o *submitOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithWeight 设置任务占用的并发名额，默认为1，超过协程池最大并发数时按最大并发数计算
+// 权重较大的任务要等到空闲名额足够才开始执行，执行期间其他任务可用的名额相应减少，适合内存或CPU开销较大的任务；
+// 排在其后的任务不会越过它先执行，避免大任务一直等不到足够的名额
+func WithWeight(weight int) SubmitOption {
+	return func(o *submitOptions) {
+		o.weight = weight
+	}
+}
+
+// WithAffinity 设置任务的亲和键，键相同的任务按提交顺序逐个执行，不同键的任务之间仍然并行，
+// 例如在共享的协程池中以域名为键，保证对同一站点的请求串行；为空时不限制
+// 等待同键任务完成的任务计入 PoolMetrics.QueueLength，但不会触发扩容
+func WithAffinity(key string) SubmitOption {
+	return func(o *submitOptions) {
+		o.affinity = key
+	}
+}
+
+// queuedTask 队列中等待执行的任务
+type queuedTask struct {
+	run      func() error // 执行任务并返回任务的错误，用于统计
+	priority int
+	seq      uint64 // 提交序号，保证相同优先级先进先出
+	index    int    // 在堆中的位置，出队或等待同键任务时为-1
+	weight   int    // 占用的并发名额
+	affinity string // 亲和键，相同键的任务串行执行
+}
+
+// taskQueue 按优先级排序的任务堆，实现 container/heap 接口
+type taskQueue []*queuedTask
+
+func (q taskQueue) Len() int { return len(q) }
+
+func (q taskQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q taskQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *taskQueue) Push(x any) {
+	task := x.(*queuedTask)
+	task.index = len(*q)
+	*q = append(*q, task)
+}
+
+func (q *taskQueue) Pop() any {
+	old := *q
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	task.index = -1
+	*q = old[:n-1]
+	return task
+}
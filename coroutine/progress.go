@@ -0,0 +1,44 @@
+package coroutine
+
+import (
+	"sync"
+)
+
+// ProgressFunc 进度回调，done为已完成的工作数量，total为工作总数（未知时为-1）
+type ProgressFunc func(done, total int)
+
+// WithOnProgress 设置进度回调，每完成一个工作调用一次，可用于在命令行中渲染进度条
+// 回调串行调用且done严格递增，回调应尽快返回以免拖慢工作协程
+func WithOnProgress(fn ProgressFunc) Option {
+	return func(o *options) {
+		o.onProgress = fn
+	}
+}
+
+// progressTracker 统计已完成的工作数量并串行调用进度回调
+type progressTracker struct {
+	mu    sync.Mutex
+	fn    ProgressFunc
+	done  int
+	total int
+}
+
+// newProgressTracker 创建进度统计，未设置回调时返回nil
+func newProgressTracker(o *options, total int) *progressTracker {
+	if o.onProgress == nil {
+		return nil
+	}
+	return &progressTracker{fn: o.onProgress, total: total}
+}
+
+// add 记录一个工作完成，nil接收者时不做任何事
+func (t *progressTracker) add() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done++
+	t.fn(t.done, t.total)
+}
@@ -0,0 +1,130 @@
+package coroutine
+
+import (
+	"errors"
+	"slices"
+)
+
+// Values 返回成功结果的值，保持原有顺序
+func Values[T any](results []Result[T]) []T {
+	values := make([]T, 0, len(results))
+	for _, result := range results {
+		if result.Err == nil {
+			values = append(values, result.Value)
+		}
+	}
+	return values
+}
+
+// Errors 返回所有失败结果的错误合并后的结果（errors.Join），全部成功时返回nil
+// 与 ExecuteAll 相同，未执行的工作只在没有其他错误时以一个 ErrNotExecuted 体现在结果中
+func Errors[T any](results []Result[T]) error {
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return joinErrors(errs)
+}
+
+// Partition 将结果分为成功和失败两组，组内保持原有顺序
+func Partition[T any](results []Result[T]) (succeeded, failed []Result[T]) {
+	for _, result := range results {
+		if result.Err == nil {
+			succeeded = append(succeeded, result)
+		} else {
+			failed = append(failed, result)
+		}
+	}
+	return succeeded, failed
+}
+
+// FirstError 返回第一个失败结果的错误，全部成功时返回nil
+func FirstError[T any](results []Result[T]) error {
+	for _, result := range results {
+		if result.Err != nil {
+			return result.Err
+		}
+	}
+	return nil
+}
+
+// ValuesDict 返回 MapDict 等函数结果中成功的值
+func ValuesDict[K comparable, T any](results map[K]Result[T]) map[K]T {
+	values := make(map[K]T, len(results))
+	for key, result := range results {
+		if result.Err == nil {
+			values[key] = result.Value
+		}
+	}
+	return values
+}
+
+// ErrorsDict 返回字典结果中所有错误合并后的结果，错误按 Result.Index 排序，全部成功时返回nil
+// 未执行的工作的处理与 Errors 相同
+func ErrorsDict[K comparable, T any](results map[K]Result[T]) error {
+	ordered := sortedResults(results)
+	errs := make([]error, len(ordered))
+	for i, result := range ordered {
+		errs[i] = result.Err
+	}
+	return joinErrors(errs)
+}
+
+// PartitionDict 将字典结果分为成功和失败两组
+func PartitionDict[K comparable, T any](results map[K]Result[T]) (succeeded, failed map[K]Result[T]) {
+	succeeded, failed = make(map[K]Result[T]), make(map[K]Result[T])
+	for key, result := range results {
+		if result.Err == nil {
+			succeeded[key] = result
+		} else {
+			failed[key] = result
+		}
+	}
+	return succeeded, failed
+}
+
+// FirstErrorDict 返回 Result.Index 最小的失败结果的键和错误，全部成功时返回零值和nil
+// 字典的提交顺序是随机的，适用于只需要任一错误并希望结果稳定的场景
+func FirstErrorDict[K comparable, T any](results map[K]Result[T]) (K, error) {
+	var firstKey K
+	var first *Result[T]
+	for key, result := range results {
+		if result.Err != nil && (first == nil || result.Index < first.Index) {
+			firstKey, first = key, &result
+		}
+	}
+	if first == nil {
+		return firstKey, nil
+	}
+	return firstKey, first.Err
+}
+
+// sortedResults 返回按 Result.Index 排序的字典结果
+func sortedResults[K comparable, T any](results map[K]Result[T]) []Result[T] {
+	ordered := make([]Result[T], 0, len(results))
+	for _, result := range results {
+		ordered = append(ordered, result)
+	}
+	slices.SortFunc(ordered, func(a, b Result[T]) int { return a.Index - b.Index })
+	return ordered
+}
+
+// joinErrors 合并非nil的错误，ErrNotExecuted 只在没有其他错误时返回
+func joinErrors(errs []error) error {
+	var joined []error
+	notExecuted := false
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if err == ErrNotExecuted {
+			notExecuted = true
+			continue
+		}
+		joined = append(joined, err)
+	}
+	if notExecuted && len(joined) == 0 {
+		return ErrNotExecuted
+	}
+	return errors.Join(joined...)
+}
@@ -0,0 +1,222 @@
+package coroutine
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Semaphore 加权信号量，用于限制对共享资源的总占用量
+// 每次获取可以占用不同的权重，等待者按先进先出的顺序获得资源，避免大权重请求被饿死
+type Semaphore struct {
+	size int64
+
+	mu      sync.Mutex
+	cur     int64
+	waiters list.List // 等待中的 *semaphoreWaiter
+}
+
+// semaphoreWaiter 等待获取信号量的请求
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewSemaphore 创建总权重为size的信号量，size小于等于0时为1
+func NewSemaphore(size int64) *Semaphore {
+	if size <= 0 {
+		size = 1
+	}
+	return &Semaphore{size: size}
+}
+
+// Acquire 获取权重为n的资源，资源不足时阻塞，上下文取消时返回上下文错误
+// n超过信号量总权重时只能等待上下文取消
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	if n > s.size {
+		s.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	waiter := &semaphoreWaiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(waiter)
+	s.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-waiter.ready:
+			// 取消的同时已获得资源，归还后通知其他等待者
+			s.cur -= n
+			s.notifyWaiters()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			// 队首的等待者被移除后，后面较小的请求可能已经可以满足
+			if isFront && s.size > s.cur {
+				s.notifyWaiters()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire 尝试获取权重为n的资源，不阻塞，成功时返回true
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release 归还权重为n的资源，归还量超过已占用量时panic
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		panic("coroutine: semaphore released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// notifyWaiters 按顺序唤醒资源足够的等待者，调用时需持有锁
+func (s *Semaphore) notifyWaiters() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+
+		waiter := front.Value.(*semaphoreWaiter)
+		if s.size-s.cur < waiter.n {
+			// 保持先进先出，队首资源不足时不唤醒后面的等待者
+			return
+		}
+
+		s.cur += waiter.n
+		s.waiters.Remove(front)
+		close(waiter.ready)
+	}
+}
+
+// KeyedLimiter 按键限制并发数，每个键最多同时有limit个持有者，例如限制对每个域名的并发请求数
+// 没有持有者和等待者的键会被自动清理，键的数量不会无限增长
+type KeyedLimiter[K comparable] struct {
+	limit int
+
+	mu      sync.Mutex
+	entries map[K]*keyedEntry
+}
+
+// keyedEntry 单个键的信号量及其引用计数
+type keyedEntry struct {
+	sem  *Semaphore
+	refs int
+}
+
+// NewKeyedLimiter 创建按键限流器，limit小于等于0时为1
+func NewKeyedLimiter[K comparable](limit int) *KeyedLimiter[K] {
+	if limit <= 0 {
+		limit = 1
+	}
+	return &KeyedLimiter[K]{
+		limit:   limit,
+		entries: make(map[K]*keyedEntry),
+	}
+}
+
+// Acquire 获取键的一个并发名额，名额已满时阻塞，上下文取消时返回上下文错误
+func (l *KeyedLimiter[K]) Acquire(ctx context.Context, key K) error {
+	entry := l.ref(key)
+	if err := entry.sem.Acquire(ctx, 1); err != nil {
+		l.unref(key)
+		return err
+	}
+	return nil
+}
+
+// TryAcquire 尝试获取键的一个并发名额，不阻塞，成功时返回true
+func (l *KeyedLimiter[K]) TryAcquire(key K) bool {
+	entry := l.ref(key)
+	if !entry.sem.TryAcquire(1) {
+		l.unref(key)
+		return false
+	}
+	return true
+}
+
+// Release 归还键的一个并发名额
+func (l *KeyedLimiter[K]) Release(key K) {
+	l.mu.Lock()
+	entry, ok := l.entries[key]
+	l.mu.Unlock()
+	if !ok {
+		panic("coroutine: keyed limiter released without acquire")
+	}
+
+	entry.sem.Release(1)
+	l.unref(key)
+}
+
+// Do 在获取键的并发名额后执行fn，执行完毕后自动归还
+func (l *KeyedLimiter[K]) Do(ctx context.Context, key K, fn func() error) error {
+	if err := l.Acquire(ctx, key); err != nil {
+		return err
+	}
+	defer l.Release(key)
+	return fn()
+}
+
+// ref 获取键对应的条目并增加引用计数
+func (l *KeyedLimiter[K]) ref(key K) *keyedEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &keyedEntry{sem: NewSemaphore(int64(l.limit))}
+		l.entries[key] = entry
+	}
+	entry.refs++
+	return entry
+}
+
+// unref 减少引用计数，没有引用时删除条目
+func (l *KeyedLimiter[K]) unref(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		return
+	}
+	entry.refs--
+	if entry.refs <= 0 {
+		delete(l.entries, key)
+	}
+}
+
+// Len 返回当前有持有者或等待者的键的数量
+func (l *KeyedLimiter[K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
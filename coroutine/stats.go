@@ -0,0 +1,140 @@
+package coroutine
+
+import (
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statsSampleSize 计算耗时分位数时保留的最近任务数
+const statsSampleSize = 1024
+
+// PoolStats 常驻协程池的累计统计，用于压测和调整最大并发数
+// 与 PoolMetrics 的瞬时状态不同，计数从协程池创建开始累计，耗时分位数基于最近1024个任务
+type PoolStats struct {
+	// Queued 排队等待执行的任务数，包括等待同键任务完成的任务，与 PoolMetrics.QueueLength 相同
+	Queued int
+	// Active 正在执行任务的工作协程数
+	Active int
+	// Submitted 累计提交的任务数
+	Submitted uint64
+	// Completed 累计完成的任务数，包括返回错误的任务
+	Completed uint64
+	// Errors 累计返回错误的任务数，包括因上下文取消而没有执行的任务
+	Errors uint64
+	// P50 和 P99 最近任务耗时的中位数和99分位数
+	P50 time.Duration
+	P99 time.Duration
+	// TotalLatency 所有已完成任务的耗时之和
+	TotalLatency time.Duration
+}
+
+// taskStats 协程池的累计统计，由协程池的锁保护
+type taskStats struct {
+	submitted    uint64
+	completed    uint64
+	errors       uint64
+	totalLatency time.Duration
+	samples      []time.Duration // 最近任务的耗时，写满后循环覆盖
+	next         int
+}
+
+// record 记录一个完成的任务
+func (s *taskStats) record(elapsed time.Duration, err error) {
+	s.completed++
+	if err != nil {
+		s.errors++
+	}
+	s.totalLatency += elapsed
+
+	if len(s.samples) < statsSampleSize {
+		s.samples = append(s.samples, elapsed)
+		return
+	}
+	s.samples[s.next] = elapsed
+	s.next = (s.next + 1) % statsSampleSize
+}
+
+// percentiles 返回最近任务耗时的中位数和99分位数
+func (s *taskStats) percentiles() (time.Duration, time.Duration) {
+	if len(s.samples) == 0 {
+		return 0, 0
+	}
+	sorted := slices.Clone(s.samples)
+	slices.Sort(sorted)
+	return percentile(sorted, 0.5), percentile(sorted, 0.99)
+}
+
+// percentile 返回已排序样本的q分位数
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	index := int(q*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(index, 0), len(sorted)-1)]
+}
+
+// Stats 返回协程池的累计统计
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p50, p99 := p.stats.percentiles()
+	return PoolStats{
+		Queued:       len(p.queue) + p.parked,
+		Active:       p.workers - p.idleWorkers,
+		Submitted:    p.stats.submitted,
+		Completed:    p.stats.completed,
+		Errors:       p.stats.errors,
+		P50:          p50,
+		P99:          p99,
+		TotalLatency: p.stats.totalLatency,
+	}
+}
+
+// poolCollector 将协程池的统计导出为Prometheus指标
+type poolCollector struct {
+	pool      *Pool
+	queued    *prometheus.Desc
+	active    *prometheus.Desc
+	workers   *prometheus.Desc
+	submitted *prometheus.Desc
+	errors    *prometheus.Desc
+	latency   *prometheus.Desc
+}
+
+// Collector 返回导出协程池统计的Prometheus收集器，name作为pool标签区分多个协程池
+// 指标在每次抓取时读取，不会影响任务执行；任务耗时以summary导出，分位数为0.5和0.99
+func (p *Pool) Collector(name string) prometheus.Collector {
+	labels := prometheus.Labels{"pool": name}
+	return &poolCollector{
+		pool:      p,
+		queued:    prometheus.NewDesc("coroutine_pool_queued_tasks", "排队等待执行的任务数", nil, labels),
+		active:    prometheus.NewDesc("coroutine_pool_active_workers", "正在执行任务的工作协程数", nil, labels),
+		workers:   prometheus.NewDesc("coroutine_pool_workers", "工作协程总数", nil, labels),
+		submitted: prometheus.NewDesc("coroutine_pool_tasks_submitted_total", "累计提交的任务数", nil, labels),
+		errors:    prometheus.NewDesc("coroutine_pool_task_errors_total", "累计返回错误的任务数", nil, labels),
+		latency:   prometheus.NewDesc("coroutine_pool_task_duration_seconds", "任务的执行耗时", nil, labels),
+	}
+}
+
+// Describe 实现 prometheus.Collector 接口
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{c.queued, c.active, c.workers, c.submitted, c.errors, c.latency} {
+		ch <- desc
+	}
+}
+
+// Collect 实现 prometheus.Collector 接口
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.pool.Stats()
+	metrics := c.pool.Metrics()
+
+	ch <- prometheus.MustNewConstMetric(c.queued, prometheus.GaugeValue, float64(stats.Queued))
+	ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(stats.Active))
+	ch <- prometheus.MustNewConstMetric(c.workers, prometheus.GaugeValue, float64(metrics.Workers))
+	ch <- prometheus.MustNewConstMetric(c.submitted, prometheus.CounterValue, float64(stats.Submitted))
+	ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, float64(stats.Errors))
+	ch <- prometheus.MustNewConstSummary(c.latency, stats.Completed, stats.TotalLatency.Seconds(), map[float64]float64{
+		0.5:  stats.P50.Seconds(),
+		0.99: stats.P99.Seconds(),
+	})
+}
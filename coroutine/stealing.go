@@ -0,0 +1,110 @@
+package coroutine
+
+import (
+	"context"
+	"sync"
+)
+
+// WithWorkStealing 使用工作窃取的方式执行：工作被预先均分给每个工作协程，
+// 自己的工作执行完后从其他协程剩余工作的尾部窃取一半，减少共享队列的竞争，
+// 适合大量耗时很短或耗时严重不均的工作；结果与默认方式完全一致
+func WithWorkStealing() Option {
+	return func(o *options) {
+		o.workStealing = true
+	}
+}
+
+// workRange 工作协程持有的一段连续工作索引 [lo, hi)
+type workRange struct {
+	mu     sync.Mutex
+	lo, hi int
+}
+
+// pop 从头部取出一个工作索引
+func (r *workRange) pop() (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lo >= r.hi {
+		return 0, false
+	}
+	index := r.lo
+	r.lo++
+	return index, true
+}
+
+// steal 从尾部窃取剩余工作的一半（向上取整）
+func (r *workRange) steal() (lo, hi int, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := r.hi - r.lo
+	if remaining <= 0 {
+		return 0, 0, false
+	}
+	n := (remaining + 1) / 2
+	lo, hi = r.hi-n, r.hi
+	r.hi = lo
+	return lo, hi, true
+}
+
+// reset 设置新的工作区间
+func (r *workRange) reset(lo, hi int) {
+	r.mu.Lock()
+	r.lo, r.hi = lo, hi
+	r.mu.Unlock()
+}
+
+// executeStealing 以工作窃取的方式执行所有工作
+func (p *CoroutinePool[T]) executeStealing(ctx context.Context, workerCount int, works []WorkFunc[T], onError func(), progress *progressTracker) {
+	// 将工作均分给每个工作协程
+	ranges := make([]*workRange, workerCount)
+	size := len(works) / workerCount
+	extra := len(works) % workerCount
+	lo := 0
+	for i := range ranges {
+		hi := lo + size
+		if i < extra {
+			hi++
+		}
+		ranges[i] = &workRange{lo: lo, hi: hi}
+		lo = hi
+	}
+
+	var wg sync.WaitGroup
+	for i := range ranges {
+		wg.Add(1)
+		go func(self int) {
+			defer wg.Done()
+			for {
+				index, ok := ranges[self].pop()
+				if !ok {
+					if !stealWork(ranges, self) {
+						// 所有工作协程都没有剩余工作
+						return
+					}
+					continue
+				}
+
+				// 上下文已取消时不再执行新的工作
+				if ctx.Err() != nil {
+					return
+				}
+				p.execute(ctx, index, works, onError, progress)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// stealWork 依次尝试从其他工作协程窃取工作，成功时放入自己的区间
+func stealWork(ranges []*workRange, self int) bool {
+	for offset := 1; offset < len(ranges); offset++ {
+		victim := ranges[(self+offset)%len(ranges)]
+		if lo, hi, ok := victim.steal(); ok {
+			ranges[self].reset(lo, hi)
+			return true
+		}
+	}
+	return false
+}
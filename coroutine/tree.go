@@ -2,122 +2,118 @@ package coroutine
 
 import (
 	"context"
+	"errors"
+	"sync"
 )
 
+// ErrSkipChildren 处理函数返回该错误时，节点的结果照常记录（Err为nil），但不再处理其子树
+var ErrSkipChildren = errors.New("coroutine: skip children")
+
+// WithMaxDepth 限制树处理的深度，根节点深度为0，只处理深度不超过depth的节点
+func WithMaxDepth(depth int) Option {
+	return func(o *options) {
+		o.maxDepth = depth
+		o.depthLimited = depth >= 0
+	}
+}
+
 // ProcessTree 并行处理树形结构
-func ProcessTree[T any](ctx context.Context, maxWorkers int, root TreeNode, processFunc func(TreeNode) (T, error)) map[string]TreeResult[T] {
-	if maxWorkers <= 0 {
-		maxWorkers = DefaultMaxWorkers()
+// 父节点处理完成后其子节点进入队列，不同子树之间并行处理；按节点ID去重，
+// 同一节点只处理一次，因此可以安全地处理有环的图；上下文取消时返回空结果
+func ProcessTree[T any](ctx context.Context, maxWorkers int, root TreeNode, processFunc func(TreeNode) (T, error), opts ...Option) map[string]TreeResult[T] {
+	resultMap := traverseTree(ctx, maxWorkers, root, ignoreParent(processFunc), newOptions(opts), false)
+	if ctx.Err() != nil {
+		return make(map[string]TreeResult[T])
 	}
+	return resultMap
+}
 
-	// 使用BFS遍历树并收集所有节点
-	nodes := []TreeNode{root}
-	var queue []TreeNode
-	queue = append(queue, root)
+// ProcessTreeDFS 以深度优先的顺序并行处理树形结构，深度越大的节点越先执行，
+// 适合希望尽快处理完一棵子树再处理下一棵的场景；上下文取消时返回已处理节点的结果
+func ProcessTreeDFS[T any](ctx context.Context, maxWorkers int, root TreeNode, processFunc func(TreeNode) (T, error), opts ...Option) map[string]TreeResult[T] {
+	return traverseTree(ctx, maxWorkers, root, ignoreParent(processFunc), newOptions(opts), true)
+}
 
-	for len(queue) > 0 {
-		// 检查上下文是否已取消
-		select {
-		case <-ctx.Done():
-			return make(map[string]TreeResult[T])
-		default:
-		}
+// ProcessTreeWithParent 并行处理树形结构，每个节点的处理函数会收到父节点的处理结果（根节点为nil），
+// 适合路径拼接、配置继承等自顶向下的计算；子节点在父节点完成后立即调度，同层节点之间并行处理
+// 父节点处理出错时子节点仍会被处理，可通过 parent.Err 判断；上下文取消时返回已处理节点的结果
+func ProcessTreeWithParent[T any](ctx context.Context, maxWorkers int, root TreeNode, processFunc func(node TreeNode, parent *TreeResult[T]) (T, error), opts ...Option) map[string]TreeResult[T] {
+	return traverseTree(ctx, maxWorkers, root, processFunc, newOptions(opts), false)
+}
 
-		node := queue[0]
-		queue = queue[1:]
+// ignoreParent 将不关心父节点结果的处理函数适配为traverseTree使用的形式
+func ignoreParent[T any](processFunc func(TreeNode) (T, error)) func(TreeNode, *TreeResult[T]) (T, error) {
+	return func(node TreeNode, _ *TreeResult[T]) (T, error) {
+		return processFunc(node)
+	}
+}
 
-		children := node.GetChildren()
-		for _, child := range children {
-			nodes = append(nodes, child)
-			queue = append(queue, child)
-		}
+// traverseTree 使用常驻协程池遍历并处理树，子节点在父节点处理完成后提交
+// depthFirst 为true时以节点深度作为优先级，实现深度优先的执行顺序
+func traverseTree[T any](ctx context.Context, maxWorkers int, root TreeNode, processFunc func(TreeNode, *TreeResult[T]) (T, error), o *options, depthFirst bool) map[string]TreeResult[T] {
+	resultMap := make(map[string]TreeResult[T])
+	if root == nil {
+		return resultMap
 	}
 
-	// 创建工作函数
-	works := make([]WorkFunc[struct {
-		ID    string
-		Value T
-		Err   error
-	}], len(nodes))
-	for i, node := range nodes {
-		// 捕获循环变量
-		capturedNode := node
-		works[i] = func() (struct {
-			ID    string
-			Value T
-			Err   error
-		}, error) {
-			// 检查上下文是否已取消
-			select {
-			case <-ctx.Done():
-				var zero T
-				return struct {
-					ID    string
-					Value T
-					Err   error
-				}{
-					ID:    capturedNode.GetID(),
-					Value: zero,
-					Err:   ctx.Err(),
-				}, nil
-			default:
-			}
+	pool := NewPool(maxWorkers)
+	progress := newProgressTracker(o, -1)
+	var mu sync.Mutex
+	visited := map[string]bool{root.GetID(): true}
 
-			value, err := processFunc(capturedNode)
-			return struct {
-				ID    string
-				Value T
-				Err   error
-			}{
-				ID:    capturedNode.GetID(),
-				Value: value,
-				Err:   err,
-			}, nil
+	var submit func(node TreeNode, parent *TreeResult[T], depth int)
+	submit = func(node TreeNode, parent *TreeResult[T], depth int) {
+		priority := PriorityNormal
+		if depthFirst {
+			priority = depth
 		}
-	}
 
-	// 执行并收集结果
-	pool := NewCoroutinePool[struct {
-		ID    string
-		Value T
-		Err   error
-	}](maxWorkers)
-	results := pool.Execute(ctx, works)
-
-	// 转换为map结果
-	resultMap := make(map[string]TreeResult[T], len(results))
-	for _, result := range results {
-		// 检查上下文是否已取消
-		select {
-		case <-ctx.Done():
-			return make(map[string]TreeResult[T])
-		default:
-		}
+		pool.enqueue(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 
-		if result.Err != nil {
-			// 如果执行工作函数时出错，记录错误
-			var zero T
-			resultMap["error"] = TreeResult[T]{
-				Value:  zero,
-				Err:    result.Err,
-				NodeID: "unknown",
+			id := node.GetID()
+			value, _, err := runWork(ctx, o, WorkInfo{Index: -1, NodeID: id}, func(context.Context) (T, error) {
+				return processFunc(node, parent)
+			})
+			skip := errors.Is(err, ErrSkipChildren)
+			if skip {
+				err = nil
 			}
-			continue
-		}
+			result := &TreeResult[T]{Value: value, Err: err, NodeID: id}
+
+			mu.Lock()
+			resultMap[id] = *result
+			var children []TreeNode
+			if !skip && (!o.depthLimited || depth < o.maxDepth) {
+				for _, child := range node.GetChildren() {
+					if child == nil || visited[child.GetID()] {
+						continue
+					}
+					visited[child.GetID()] = true
+					children = append(children, child)
+				}
+			}
+			mu.Unlock()
+			progress.add()
 
-		// 记录节点处理结果
-		resultMap[result.Value.ID] = TreeResult[T]{
-			Value:  result.Value.Value,
-			Err:    result.Value.Err,
-			NodeID: result.Value.ID,
-		}
+			for _, child := range children {
+				submit(child, result, depth+1)
+			}
+			return err
+		}, &submitOptions{priority: priority})
 	}
 
+	submit(root, nil, 0)
+	pool.Wait()
+
 	return resultMap
 }
 
 // ProcessTreeBFS 使用BFS策略并行处理树形结构，按层处理
-func ProcessTreeBFS[T any](ctx context.Context, maxWorkers int, root TreeNode, processFunc func(TreeNode) (T, error)) map[string]TreeResult[T] {
+// 同一层的节点全部处理完成后才开始处理下一层，按节点ID去重，同一节点只处理一次
+func ProcessTreeBFS[T any](ctx context.Context, maxWorkers int, root TreeNode, processFunc func(TreeNode) (T, error), opts ...Option) map[string]TreeResult[T] {
 	if maxWorkers <= 0 {
 		maxWorkers = DefaultMaxWorkers()
 	}
@@ -127,18 +123,19 @@ func ProcessTreeBFS[T any](ctx context.Context, maxWorkers int, root TreeNode, p
 		return resultMap
 	}
 
-	// 创建协程池（只创建一次）
-	pool := NewCoroutinePool[struct {
-		ID       string
-		Value    T
-		Err      error
-		Children []TreeNode
-	}](maxWorkers)
+	// 创建协程池（只创建一次），结果需要与当前层的节点按位置对应
+	// 进度按整棵树统计，不使用协程池按层统计的进度
+	o := newOptions(opts)
+	progress := newProgressTracker(o, -1)
+	pool := NewCoroutinePool[T](maxWorkers, append(orderedOptions(opts), func(o *options) {
+		o.onProgress = nil
+	})...)
+	visited := map[string]bool{root.GetID(): true}
 
 	// 使用BFS按层处理
 	currentLayer := []TreeNode{root}
 
-	for len(currentLayer) > 0 {
+	for depth := 0; len(currentLayer) > 0; depth++ {
 		// 检查上下文是否已取消
 		select {
 		case <-ctx.Done():
@@ -147,33 +144,12 @@ func ProcessTreeBFS[T any](ctx context.Context, maxWorkers int, root TreeNode, p
 		}
 
 		// 为当前层创建工作函数
-		works := make([]WorkFunc[struct {
-			ID       string
-			Value    T
-			Err      error
-			Children []TreeNode
-		}], len(currentLayer))
+		works := make([]WorkFunc[T], len(currentLayer))
 		for i, node := range currentLayer {
 			// 捕获循环变量
 			capturedNode := node
-			works[i] = func() (struct {
-				ID       string
-				Value    T
-				Err      error
-				Children []TreeNode
-			}, error) {
-				value, err := processFunc(capturedNode)
-				return struct {
-					ID       string
-					Value    T
-					Err      error
-					Children []TreeNode
-				}{
-					ID:       capturedNode.GetID(),
-					Value:    value,
-					Err:      err,
-					Children: capturedNode.GetChildren(),
-				}, nil
+			works[i] = func() (T, error) {
+				return processFunc(capturedNode)
 			}
 		}
 
@@ -182,28 +158,38 @@ func ProcessTreeBFS[T any](ctx context.Context, maxWorkers int, root TreeNode, p
 
 		// 收集结果并准备下一层
 		var nextLayer []TreeNode
+		descend := !o.depthLimited || depth < o.maxDepth
 
-		for _, result := range results {
-			if result.Err != nil {
-				// 如果执行工作函数时出错，记录错误
-				var zero T
-				resultMap["error"] = TreeResult[T]{
-					Value:  zero,
-					Err:    result.Err,
-					NodeID: "unknown",
-				}
+		for i, result := range results {
+			node := currentLayer[i]
+			if errors.Is(result.Err, ErrNotExecuted) {
+				// 上下文取消后未执行的节点
 				continue
 			}
 
+			skip := errors.Is(result.Err, ErrSkipChildren)
+			if skip {
+				result.Err = nil
+			}
+
 			// 记录节点处理结果
-			resultMap[result.Value.ID] = TreeResult[T]{
-				Value:  result.Value.Value,
-				Err:    result.Value.Err,
-				NodeID: result.Value.ID,
+			resultMap[node.GetID()] = TreeResult[T]{
+				Value:  result.Value,
+				Err:    result.Err,
+				NodeID: node.GetID(),
 			}
+			progress.add()
 
-			// 直接添加子节点到下一层，避免二次查找
-			nextLayer = append(nextLayer, result.Value.Children...)
+			if skip || !descend {
+				continue
+			}
+			for _, child := range node.GetChildren() {
+				if child == nil || visited[child.GetID()] {
+					continue
+				}
+				visited[child.GetID()] = true
+				nextLayer = append(nextLayer, child)
+			}
 		}
 
 		// 更新当前层为下一层
@@ -1,5 +1,7 @@
 package coroutine
 
+import "time"
+
 // WorkFunc 定义了协程中执行的工作函数类型
 type WorkFunc[T any] func() (T, error)
 
@@ -8,6 +10,10 @@ type Result[T any] struct {
 	Value T
 	Err   error
 	Index int
+	// Attempts 工作函数的实际执行次数，启用重试时可能大于1，未执行时为0
+	Attempts int
+	// Duration 工作函数的执行耗时，包含重试及其等待时间，未执行时为0
+	Duration time.Duration
 }
 
 // TreeNode 定义了树形结构的节点接口
@@ -0,0 +1,91 @@
+package coroutine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Window 滑动窗口调度器，保证任意长度为window的时间段内最多开始n次执行
+// 与令牌桶限流（WithRateLimit）按固定速率补充不同，窗口内的名额可以一次性用完，
+// 之后要等最早的一次执行滑出窗口才会释放，适合Telegram、Bing等按时间窗口计算突发配额的API
+type Window struct {
+	n      int
+	window time.Duration
+
+	mu     sync.Mutex
+	starts []time.Time // 窗口内各次执行的开始时间，按时间先后排列
+}
+
+// NewWindow 创建每个window时间段内最多开始n次执行的滑动窗口，n或window小于等于0时返回nil
+func NewWindow(n int, window time.Duration) *Window {
+	if n <= 0 || window <= 0 {
+		return nil
+	}
+	return &Window{
+		n:      n,
+		window: window,
+		starts: make([]time.Time, 0, n),
+	}
+}
+
+// Wait 等待窗口内出现空闲名额并占用，上下文取消时返回上下文错误
+func (w *Window) Wait(ctx context.Context) error {
+	for {
+		delay := w.reserve(time.Now())
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Allow 窗口内有空闲名额时占用并返回true，否则立即返回false
+func (w *Window) Allow() bool {
+	return w.reserve(time.Now()) == 0
+}
+
+// reserve 有空闲名额时占用并返回0，否则返回最早一次执行滑出窗口还需等待的时间
+func (w *Window) reserve(now time.Time) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// 移除已滑出窗口的记录
+	expired := 0
+	for expired < len(w.starts) && !now.Before(w.starts[expired].Add(w.window)) {
+		expired++
+	}
+	if expired > 0 {
+		w.starts = append(w.starts[:0], w.starts[expired:]...)
+	}
+
+	if len(w.starts) < w.n {
+		w.starts = append(w.starts, now)
+		return 0
+	}
+	return w.starts[0].Add(w.window).Sub(now)
+}
+
+// WithWindow 使用滑动窗口限制工作函数的开始次数，重试也计入窗口
+// 同一个Window可以在多次调用和多个协程池之间共享，用于对外部API的全局配额控制
+func WithWindow(w *Window) Option {
+	if w == nil {
+		return nil
+	}
+	return func(o *options) {
+		o.window = w
+	}
+}
+
+// WithSlidingWindow 限制任意长度为window的时间段内最多开始n次执行，
+// 等价于 WithWindow(NewWindow(n, window))，同一个选项值在多次调用之间共享同一个窗口
+func WithSlidingWindow(n int, window time.Duration) Option {
+	return WithWindow(NewWindow(n, window))
+}
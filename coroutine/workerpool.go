@@ -0,0 +1,387 @@
+package coroutine
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ScalingPolicy 常驻协程池的动态伸缩策略
+// 工作协程数在 MinWorkers 与最大并发数之间根据队列深度和任务耗时自动调整
+type ScalingPolicy struct {
+	// MinWorkers 常驻的最少工作协程数，即使没有任务也不会退出
+	MinWorkers int
+	// IdleTimeout 超出 MinWorkers 的工作协程空闲多久后退出，为0时队列为空立即退出
+	IdleTimeout time.Duration
+	// QueuePerWorker 队列长度超过 工作协程数*QueuePerWorker 时扩容，为0时有排队任务就扩容
+	QueuePerWorker int
+	// TargetLatency 任务平均耗时超过该值且仍有排队任务时扩容，为0时不考虑耗时
+	TargetLatency time.Duration
+}
+
+// WithScaling 为常驻协程池设置动态伸缩策略，适合突发流量的场景
+func WithScaling(policy ScalingPolicy) Option {
+	return func(o *options) {
+		o.scaling = &policy
+	}
+}
+
+// PoolMetrics 常驻协程池的运行指标
+type PoolMetrics struct {
+	// Workers 当前工作协程总数
+	Workers int
+	// ActiveWorkers 正在执行任务的工作协程数
+	ActiveWorkers int
+	// IdleWorkers 空闲等待任务的工作协程数
+	IdleWorkers int
+	// QueueLength 排队等待执行的任务数，包括等待同键任务完成的任务
+	QueueLength int
+	// Pending 已提交但尚未完成的任务数（包括排队和执行中的任务）
+	Pending int
+	// AvgLatency 任务耗时的指数移动平均值
+	AvgLatency time.Duration
+}
+
+// Pool 可复用的常驻协程池
+// 与每次调用都新建的CoroutinePool不同，Pool可以在多个请求之间共享，
+// 通过Submit提交任务并获得Future，通过Wait等待所有任务完成，通过Shutdown关闭
+type Pool struct {
+	maxWorkers int
+	options    *options
+	opts       []Option // 创建时的选项，子协程池继承这些选项
+	scaling    ScalingPolicy
+	parent     *Pool
+
+	mu          sync.Mutex
+	idle        *sync.Cond    // 所有任务完成时广播
+	available   *sync.Cond    // 有新任务或协程池关闭时通知空闲的工作协程
+	queue       taskQueue     // 等待执行的任务，按优先级排序
+	seq         uint64        // 下一个任务的提交序号
+	workers     int           // 正在运行的工作协程数量
+	idleWorkers int           // 空闲等待任务的工作协程数量
+	pending     int           // 已提交但尚未完成的任务数量
+	avgLatency  time.Duration // 任务耗时的指数移动平均值
+	stats       taskStats     // 累计统计
+	children    []*Pool       // 通过Child创建的子协程池
+	closed      bool
+
+	busy     int                      // 正在执行的任务占用的并发名额
+	parked   int                      // 等待同键任务完成的任务数量
+	affinity map[string][]*queuedTask // 有任务在队列中或正在执行的亲和键，值为按提交顺序等待的同键任务
+}
+
+// NewPool 创建一个常驻协程池，maxWorkers小于等于0时使用默认值
+// 默认情况下工作协程按需启动，队列为空时自动退出，空闲的协程池不占用goroutine；
+// 通过 WithScaling 可以设置常驻协程数和扩缩容条件
+func NewPool(maxWorkers int, opts ...Option) *Pool {
+	if maxWorkers <= 0 {
+		maxWorkers = DefaultMaxWorkers()
+	}
+
+	p := &Pool{
+		maxWorkers: maxWorkers,
+		options:    newOptions(opts),
+		opts:       slices.Clone(opts),
+	}
+	if p.options.scaling != nil {
+		p.scaling = *p.options.scaling
+		p.scaling.MinWorkers = min(max(p.scaling.MinWorkers, 0), maxWorkers)
+	}
+	p.idle = sync.NewCond(&p.mu)
+	p.available = sync.NewCond(&p.mu)
+
+	// 启动常驻工作协程
+	p.mu.Lock()
+	for p.workers < p.scaling.MinWorkers {
+		p.workers++
+		go p.worker()
+	}
+	p.mu.Unlock()
+
+	return p
+}
+
+// Submit 提交一个不需要返回值的任务
+func (p *Pool) Submit(work func() error, opts ...SubmitOption) (*Future[struct{}], error) {
+	return SubmitValue(p, func() (struct{}, error) {
+		return struct{}{}, work()
+	}, opts...)
+}
+
+// SubmitValue 向协程池提交一个带返回值的任务，返回用于获取结果的Future
+// 由于Go的方法不支持类型参数，带返回值的提交以函数形式提供
+func SubmitValue[T any](p *Pool, work WorkFunc[T], opts ...SubmitOption) (*Future[T], error) {
+	return submit(p, withoutContext(work), opts)
+}
+
+// submit 将任务放入队列并返回Future，任务执行时收到的上下文带有当前协程池的标记
+func submit[T any](p *Pool, work contextWork[T], opts []SubmitOption) (*Future[T], error) {
+	so := &submitOptions{priority: PriorityNormal, ctx: context.Background()}
+	for _, opt := range opts {
+		opt(so)
+	}
+
+	future := newFuture[T]()
+	task, err := p.enqueue(func() error {
+		// 排队期间上下文已取消的任务不再执行
+		if err := so.ctx.Err(); err != nil {
+			err = fmt.Errorf("%w: %w", ErrNotExecuted, err)
+			var zero T
+			future.complete(zero, err)
+			return err
+		}
+		ctx := context.WithValue(so.ctx, workerKey{}, p)
+		value, _, err := runWork(ctx, p.options, WorkInfo{Index: -1}, work)
+		future.complete(value, err)
+		return err
+	}, so)
+	if err != nil {
+		return nil, err
+	}
+	future.inline = func() bool {
+		return p.runInline(task)
+	}
+	return future, nil
+}
+
+// enqueue 将任务放入队列，必要时启动新的工作协程
+func (p *Pool) enqueue(run func() error, so *submitOptions) (*queuedTask, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+
+	task := &queuedTask{
+		run:      run,
+		priority: so.priority,
+		seq:      p.seq,
+		index:    -1,
+		weight:   min(max(so.weight, 1), p.maxWorkers),
+		affinity: so.affinity,
+	}
+	p.seq++
+	p.pending++
+	p.stats.submitted++
+	if p.park(task) {
+		return task, nil
+	}
+
+	heap.Push(&p.queue, task)
+	p.available.Signal()
+	p.scaleUp()
+	return task, nil
+}
+
+// park 同键的任务已在队列中或正在执行时暂存任务并返回true，调用时需持有锁
+func (p *Pool) park(task *queuedTask) bool {
+	if task.affinity == "" {
+		return false
+	}
+	if p.affinity == nil {
+		p.affinity = make(map[string][]*queuedTask)
+	}
+	waiting, ok := p.affinity[task.affinity]
+	if !ok {
+		p.affinity[task.affinity] = nil
+		return false
+	}
+	p.affinity[task.affinity] = append(waiting, task)
+	p.parked++
+	return true
+}
+
+// release 任务完成后将同键的下一个暂存任务放入队列，调用时需持有锁
+func (p *Pool) release(task *queuedTask) {
+	if task.affinity == "" {
+		return
+	}
+	waiting := p.affinity[task.affinity]
+	if len(waiting) == 0 {
+		delete(p.affinity, task.affinity)
+		return
+	}
+	next := waiting[0]
+	waiting[0] = nil
+	p.affinity[task.affinity] = waiting[1:]
+	p.parked--
+	heap.Push(&p.queue, next)
+}
+
+// scaleUp 根据伸缩策略判断是否需要启动新的工作协程，调用时需持有锁
+func (p *Pool) scaleUp() {
+	if len(p.queue) == 0 || p.workers >= p.maxWorkers {
+		return
+	}
+	// 有空闲的工作协程时由其处理排队任务
+	if p.idleWorkers >= len(p.queue) {
+		return
+	}
+
+	scale := p.workers == 0 || p.scaling.QueuePerWorker <= 0 ||
+		len(p.queue) > p.workers*p.scaling.QueuePerWorker ||
+		(p.scaling.TargetLatency > 0 && p.avgLatency > p.scaling.TargetLatency)
+	if scale {
+		p.workers++
+		go p.worker()
+	}
+}
+
+// worker 工作协程，持续从队列中取出任务执行
+// 队列为空时，超出常驻数量的协程在空闲超时后退出
+func (p *Pool) worker() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		if !p.waitForTask() {
+			p.workers--
+			return
+		}
+
+		task := heap.Pop(&p.queue).(*queuedTask)
+		p.busy += task.weight
+		p.mu.Unlock()
+
+		start := time.Now()
+		err := task.run()
+		elapsed := time.Since(start)
+
+		p.mu.Lock()
+		p.busy -= task.weight
+		p.finish(task, elapsed, err)
+		// 任务耗时变化后重新评估是否需要扩容
+		p.scaleUp()
+	}
+}
+
+// runnable 判断队首的任务是否可以开始执行，权重较大的任务需要等到空闲名额足够，调用时需持有锁
+func (p *Pool) runnable() bool {
+	return len(p.queue) > 0 && p.busy+p.queue[0].weight <= p.maxWorkers
+}
+
+// waitForTask 等待队列中出现可以执行的任务，返回false表示工作协程应退出，调用时需持有锁
+func (p *Pool) waitForTask() bool {
+	if p.runnable() {
+		return true
+	}
+
+	var deadline time.Time
+	var timer *time.Timer
+	if p.scaling.IdleTimeout > 0 {
+		deadline = time.Now().Add(p.scaling.IdleTimeout)
+		timer = time.AfterFunc(p.scaling.IdleTimeout, func() {
+			p.mu.Lock()
+			p.available.Broadcast()
+			p.mu.Unlock()
+		})
+		defer timer.Stop()
+	}
+
+	for !p.runnable() {
+		if p.closed && len(p.queue) == 0 {
+			return false
+		}
+		// 常驻协程一直等待，其余协程在空闲超时后退出
+		if p.workers > p.scaling.MinWorkers && (timer == nil || !time.Now().Before(deadline)) {
+			return false
+		}
+
+		p.idleWorkers++
+		p.available.Wait()
+		p.idleWorkers--
+	}
+	return true
+}
+
+// finish 记录一个任务执行完成并放行同键的下一个任务，调用时需持有锁
+func (p *Pool) finish(task *queuedTask, elapsed time.Duration, err error) {
+	p.release(task)
+	p.recordLatency(elapsed)
+	p.stats.record(elapsed, err)
+	p.pending--
+	if p.pending == 0 {
+		p.idle.Broadcast()
+	}
+	// 释放的名额或放行的任务可能让等待中的工作协程可以继续执行，协程池关闭后也需要唤醒它们退出
+	if p.idleWorkers > 0 && (len(p.queue) > 0 || p.closed) {
+		p.available.Broadcast()
+	}
+}
+
+// recordLatency 更新任务耗时的指数移动平均值，调用时需持有锁
+func (p *Pool) recordLatency(elapsed time.Duration) {
+	if p.avgLatency == 0 {
+		p.avgLatency = elapsed
+		return
+	}
+	p.avgLatency = (p.avgLatency*4 + elapsed) / 5
+}
+
+// Metrics 返回协程池当前的运行指标
+func (p *Pool) Metrics() PoolMetrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return PoolMetrics{
+		Workers:       p.workers,
+		ActiveWorkers: p.workers - p.idleWorkers,
+		IdleWorkers:   p.idleWorkers,
+		QueueLength:   len(p.queue) + p.parked,
+		Pending:       p.pending,
+		AvgLatency:    p.avgLatency,
+	}
+}
+
+// Wait 阻塞等待当前已提交的所有任务完成，协程池在Wait之后仍可继续使用
+func (p *Pool) Wait() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.pending > 0 {
+		p.idle.Wait()
+	}
+}
+
+// Shutdown 关闭协程池并等待已提交的任务执行完毕，之后关闭所有子协程池
+// 关闭后提交任务会返回ErrPoolClosed；上下文先于任务结束时返回上下文错误，
+// 剩余任务仍会在后台继续执行；使用任务收到的上下文在协程池自身的任务中调用时返回 ErrNestedWait
+func (p *Pool) Shutdown(ctx context.Context) error {
+	if p.runsTask(ctx) {
+		return ErrNestedWait
+	}
+
+	p.mu.Lock()
+	p.closed = true
+	// 唤醒空闲的常驻协程使其退出
+	p.available.Broadcast()
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.Wait()
+		p.mu.Lock()
+		children := p.children
+		p.mu.Unlock()
+		for _, child := range children {
+			child.Shutdown(ctx)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// MaxWorkers 返回协程池的最大并发数
+func (p *Pool) MaxWorkers() int {
+	return p.maxWorkers
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -11,15 +12,20 @@ import (
 
 	"github.com/sjzsdu/utils/crawler/internal/cache"
 	"github.com/sjzsdu/utils/crawler/pkg/crawler"
-	"github.com/sjzsdu/utils/crawler/pkg/models"
 	"github.com/sjzsdu/utils/crawler/sources"
 )
 
 func main() {
 	// 解析命令行参数
-	var categoriesStr, sourcesStr string
+	var categoriesStr, sourcesStr, recordDir, replayDir string
+	var jsonOutput bool
+	var sampleSize int
 	flag.StringVar(&categoriesStr, "categories", "", "指定要爬取的类别列表，多个类别用逗号分隔")
 	flag.StringVar(&sourcesStr, "sources", "", "指定要爬取的数据源名称列表，多个名称用逗号分隔")
+	flag.StringVar(&recordDir, "record", "", "将各数据源的原始响应录制到指定目录，用于离线调试解析逻辑")
+	flag.StringVar(&replayDir, "replay", "", "从指定目录回放录制的原始响应，不发起网络请求")
+	flag.BoolVar(&jsonOutput, "json", false, "以JSON格式输出汇总报告")
+	flag.IntVar(&sampleSize, "sample", -1, "汇总报告中每个数据源列出的数据项数量，小于0时列出全部")
 	flag.Parse()
 
 	// 创建日志文件
@@ -36,7 +42,7 @@ func main() {
 	defer memCache.Close()
 
 	// 创建爬取引擎
-	engine := crawler.NewEngine(memCache)
+	engine := crawler.NewEngine(memCache, crawler.WithRecordDir(recordDir))
 
 	// 获取数据源注册表
 	registry := sources.GetRegistry()
@@ -71,6 +77,18 @@ func main() {
 		selectedSources = registry.List()
 	}
 
+	// 回放模式下用录制的原始响应代替网络请求
+	if replayDir != "" {
+		for i, source := range selectedSources {
+			replay, err := crawler.NewReplaySource(source, replayDir)
+			if err != nil {
+				fmt.Printf("Failed to replay source %s: %v\n", source.GetName(), err)
+				return
+			}
+			selectedSources[i] = replay
+		}
+	}
+
 	// 注册所有数据源
 	for _, source := range selectedSources {
 		if err := engine.RegisterSource(source); err != nil {
@@ -81,158 +99,26 @@ func main() {
 		logger.Printf("Registered source: %s\n", source.GetName())
 	}
 
-	// 启动爬取引擎
-	ctx := context.Background()
-	if err := engine.Start(ctx); err != nil {
-		fmt.Printf("Failed to start engine: %v\n", err)
-		logger.Printf("Failed to start engine: %v\n", err)
-		return
-	}
-	defer engine.Stop()
-
-	// 为每个数据源创建订阅通道
-	sourceChannels := make(map[string]chan []models.Item)
-	for _, source := range selectedSources {
-		sourceName := source.GetName()
-		sourceChannels[sourceName] = make(chan []models.Item, 10)
-		if err := engine.Subscribe(sourceName, sourceChannels[sourceName]); err != nil {
-			fmt.Printf("Failed to subscribe to %s: %v\n", sourceName, err)
-			logger.Printf("Failed to subscribe to %s: %v\n", sourceName, err)
-			continue
-		}
-		defer engine.Unsubscribe(sourceName, sourceChannels[sourceName])
-	}
-
-	fmt.Println("Crawler engine started. Waiting for updates...")
-	fmt.Println("Press Ctrl+C to exit.")
-	logger.Println("Crawler engine started. Waiting for updates...")
-
-	// 用于跟踪已处理的数据源数量
-	processedSources := make(map[string]bool)
-	totalSources := len(selectedSources)
-
-	// 用于统计每个数据源获取的数据量
-	resultsStats := make(map[string]int)
-
-	// 创建一个统一的结果通道
-	resultChan := make(chan struct {
-		sourceName string
-		items      []models.Item
-	}, 100)
-
-	// 为每个数据源启动一个goroutine处理结果
-	for _, source := range selectedSources {
-		sourceName := source.GetName()
-		go func(name string, ch chan []models.Item) {
-			// 设置10秒超时，避免无限期等待
-			select {
-			case items := <-ch:
-				resultChan <- struct {
-					sourceName string
-					items      []models.Item
-				}{name, items}
-			case <-time.After(10 * time.Second):
-				// 超时处理，返回空结果
-				resultChan <- struct {
-					sourceName string
-					items      []models.Item
-				}{name, []models.Item{}}
-			}
-		}(sourceName, sourceChannels[sourceName])
-	}
-
-	// 处理爬取结果的主循环
-	timeout := time.After(10 * time.Minute)
-	checkInterval := time.Tick(5 * time.Second)
-
-	for {
-		select {
-		case result := <-resultChan:
-			// 记录结果
-			resultsStats[result.sourceName] = len(result.items)
-			logSourceResults(result.sourceName, result.items, logger)
-			processedSources[result.sourceName] = true
-
-		// 检查是否所有数据源都已处理
-		case <-checkInterval:
-			if len(processedSources) >= totalSources {
-				fmt.Println("All sources processed, exiting...")
-				logger.Println("All sources processed, exiting...")
-				goto ExitLoop
-			}
-
-		// 超时退出
-		case <-timeout:
-			fmt.Println("Timeout, exiting...")
-			logger.Printf("Timeout, exiting. Processed %d/%d sources\n", len(processedSources), totalSources)
-			goto ExitLoop
-		}
+	// 每个数据源爬取一次，输出汇总报告
+	names := make([]string, len(selectedSources))
+	for i, source := range selectedSources {
+		names[i] = source.GetName()
 	}
 
-ExitLoop:
-	// 打印统计结果
-	separator := strings.Repeat("=", 60)
-	fmt.Println("\n" + separator)
-	fmt.Println("Crawler Results Summary")
-	fmt.Println(separator)
-	logger.Println("\n" + separator)
-	logger.Println("Crawler Results Summary")
-	logger.Println(separator)
+	fmt.Println("Crawling sources...")
+	logger.Println("Crawling sources...")
 
-	// 按照数据源名称排序
-	sourceNames := make([]string, 0, len(selectedSources))
-	for _, source := range selectedSources {
-		sourceNames = append(sourceNames, source.GetName())
-	}
-
-	// 统计成功和失败的数据源数量
-	successCount := 0
-	failCount := 0
-
-	for _, sourceName := range sourceNames {
-		itemCount := resultsStats[sourceName]
-		status := "✓"
-		if itemCount == 0 {
-			status = "✗"
-			failCount++
-		} else {
-			successCount++
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	report := crawler.Crawl(ctx, engine.Trigger, names, crawler.WithReportSampleSize(sampleSize))
 
-		resultStr := fmt.Sprintf("%s %-20s: %d items", status, sourceName, itemCount)
-		fmt.Println(resultStr)
-		logger.Println(resultStr)
-	}
-
-	// 打印汇总信息
-	totalItems := 0
-	for _, count := range resultsStats {
-		totalItems += count
+	output := io.MultiWriter(os.Stdout, logFile)
+	if jsonOutput {
+		err = report.WriteJSON(output)
+	} else {
+		err = report.WriteText(output)
 	}
-
-	fmt.Println(separator)
-	fmt.Printf("Total: %d sources, %d succeeded, %d failed\n", totalSources, successCount, failCount)
-	fmt.Printf("Total items crawled: %d\n", totalItems)
-	fmt.Println(separator)
-
-	logger.Println(separator)
-	logger.Printf("Total: %d sources, %d succeeded, %d failed\n", totalSources, successCount, failCount)
-	logger.Printf("Total items crawled: %d\n", totalItems)
-	logger.Println(separator)
-}
-
-// logSourceResults 记录数据源的爬取结果到日志
-func logSourceResults(sourceName string, items []models.Item, logger *log.Logger) {
-	fmt.Printf("\nReceived %d items from %s\n", len(items), sourceName)
-	logger.Printf("\nReceived %d items from %s\n", len(items), sourceName)
-
-	// 记录所有结果
-	for i, item := range items {
-		fmt.Printf("%d. %s\n", i+1, item.Title)
-		fmt.Printf("   URL: %s\n", item.URL)
-		fmt.Printf("   Category: %s\n", item.Category)
-		logger.Printf("%d. %s\n", i+1, item.Title)
-		logger.Printf("   URL: %s\n", item.URL)
-		logger.Printf("   Category: %s\n", item.Category)
+	if err != nil {
+		fmt.Printf("Failed to write report: %v\n", err)
 	}
 }
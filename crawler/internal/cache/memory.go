@@ -1,6 +1,10 @@
 package cache
 
 import (
+	"container/list"
+	"path"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,23 +15,70 @@ import (
 type item struct {
 	value      []models.Item
 	expiration int64
+	size       int64
+	elem       *list.Element // 在LRU链表中的位置，链表元素的值为键
+}
+
+// Stats 内存缓存的使用统计
+type Stats struct {
+	// Entries 当前的条目数，包括已过期但尚未清理的条目
+	Entries int `json:"entries"`
+
+	// Bytes 当前条目占用内存的估算值
+	Bytes int64 `json:"bytes"`
+
+	// MaxBytes 内存预算，0 表示不限制
+	MaxBytes int64 `json:"max_bytes"`
+
+	// Evictions 因超出内存预算被淘汰的条目数
+	Evictions int64 `json:"evictions"`
+
+	// Expirations 过期后被清理的条目数
+	Expirations int64 `json:"expirations"`
+}
+
+// Option 配置内存缓存的选项
+type Option func(*MemoryCache)
+
+// WithMaxBytes 设置内存预算，写入后估算的总占用超过maxBytes时按最近最少使用的顺序淘汰其他条目，
+// 刚写入的条目不会被淘汰；maxBytes 不大于0时不限制
+func WithMaxBytes(maxBytes int64) Option {
+	return func(c *MemoryCache) {
+		c.maxBytes = max(maxBytes, 0)
+	}
 }
 
 // MemoryCache 是基于内存的缓存实现
 type MemoryCache struct {
 	items    map[string]item
+	lru      *list.List // 表头为最近使用的键
 	mu       sync.RWMutex
 	gcTicker *time.Ticker
 	stopChan chan struct{}
+
+	maxBytes    int64
+	bytes       int64
+	evictions   int64
+	expirations int64
+
+	evictHandlers []EvictHandler
 }
 
+// EvictHandler 在条目因过期被清理或超出内存预算被淘汰后调用，expired 表示是否因过期被清理；
+// 调用时不持有缓存的锁，可以在其中访问缓存
+type EvictHandler func(key string, expired bool)
+
 // NewMemoryCache 创建一个新的内存缓存实例
-func NewMemoryCache(cleanupInterval time.Duration) *MemoryCache {
+func NewMemoryCache(cleanupInterval time.Duration, opts ...Option) *MemoryCache {
 	cache := &MemoryCache{
 		items:    make(map[string]item),
+		lru:      list.New(),
 		gcTicker: time.NewTicker(cleanupInterval),
 		stopChan: make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(cache)
+	}
 
 	// 启动垃圾回收协程
 	go cache.gc()
@@ -53,19 +104,29 @@ func (c *MemoryCache) deleteExpired() {
 	now := time.Now().UnixNano()
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
+	var expired []string
 	for k, v := range c.items {
-		if v.expiration > 0 && now > v.expiration {
-			delete(c.items, k)
+		if v.expired(now) {
+			c.remove(k)
+			c.expirations++
+			expired = append(expired, k)
 		}
 	}
+	handlers := c.evictHandlers
+	c.mu.Unlock()
+
+	notifyEvicted(handlers, expired, true)
 }
 
-// Get 从缓存中获取数据
+// expired 判断缓存项在now时是否已过期
+func (i item) expired(now int64) bool {
+	return i.expiration > 0 && now > i.expiration
+}
+
+// Get 从缓存中获取数据，命中时将条目标记为最近使用
 func (c *MemoryCache) Get(key string) ([]models.Item, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	item, found := c.items[key]
 	if !found {
@@ -73,13 +134,32 @@ func (c *MemoryCache) Get(key string) ([]models.Item, error) {
 	}
 
 	// 检查是否过期
-	if item.expiration > 0 && time.Now().UnixNano() > item.expiration {
+	if item.expired(time.Now().UnixNano()) {
 		return nil, nil
 	}
 
+	c.lru.MoveToFront(item.elem)
 	return item.value, nil
 }
 
+// MGet 批量获取数据，返回的map中不包含不存在或已过期的键
+func (c *MemoryCache) MGet(keys []string) (map[string][]models.Item, error) {
+	now := time.Now().UnixNano()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[string][]models.Item, len(keys))
+	for _, key := range keys {
+		if item, found := c.items[key]; found && !item.expired(now) {
+			c.lru.MoveToFront(item.elem)
+			result[key] = item.value
+		}
+	}
+
+	return result, nil
+}
+
 // Set 将数据存入缓存
 func (c *MemoryCache) Set(key string, value []models.Item, expiration time.Duration) error {
 	var exp int64
@@ -88,13 +168,35 @@ func (c *MemoryCache) Set(key string, value []models.Item, expiration time.Durat
 	}
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.set(key, value, exp)
+	evicted := c.evict(func(k string) bool { return k == key })
+	handlers := c.evictHandlers
+	c.mu.Unlock()
 
-	c.items[key] = item{
-		value:      value,
-		expiration: exp,
+	notifyEvicted(handlers, evicted, false)
+	return nil
+}
+
+// MSet 批量存入数据，所有数据使用相同的过期时间
+func (c *MemoryCache) MSet(entries map[string][]models.Item, expiration time.Duration) error {
+	var exp int64
+	if expiration > 0 {
+		exp = time.Now().Add(expiration).UnixNano()
+	}
+
+	c.mu.Lock()
+	for key, value := range entries {
+		c.set(key, value, exp)
 	}
+	// 同一批写入的条目互不淘汰，只淘汰之前的条目
+	evicted := c.evict(func(key string) bool {
+		_, ok := entries[key]
+		return ok
+	})
+	handlers := c.evictHandlers
+	c.mu.Unlock()
 
+	notifyEvicted(handlers, evicted, false)
 	return nil
 }
 
@@ -103,20 +205,159 @@ func (c *MemoryCache) Delete(key string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	delete(c.items, key)
+	c.remove(key)
 	return nil
 }
 
+// DeleteByPrefix 删除所有以prefix开头的键
+func (c *MemoryCache) DeleteByPrefix(prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(key)
+		}
+	}
+	return nil
+}
+
+// Keys 返回匹配pattern的未过期的键，按字典序排序
+// pattern 使用 path.Match 的通配符语法，为空时返回所有键
+func (c *MemoryCache) Keys(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixNano()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]string, 0, len(c.items))
+	for key, item := range c.items {
+		if item.expired(now) {
+			continue
+		}
+		if pattern != "" {
+			if matched, _ := path.Match(pattern, key); !matched {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+	return keys, nil
+}
+
 // Clear 清空缓存
 func (c *MemoryCache) Clear() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.items = make(map[string]item)
+	c.lru.Init()
+	c.bytes = 0
 	return nil
 }
 
+// Stats 返回缓存的使用统计
+func (c *MemoryCache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Stats{
+		Entries:     len(c.items),
+		Bytes:       c.bytes,
+		MaxBytes:    c.maxBytes,
+		Evictions:   c.evictions,
+		Expirations: c.expirations,
+	}
+}
+
+// OnEvict 添加条目被清理或淘汰时的回调，Delete、DeleteByPrefix 和 Clear 删除的条目不会触发回调
+func (c *MemoryCache) OnEvict(handler EvictHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictHandlers = append(c.evictHandlers, handler)
+}
+
+// notifyEvicted 对每个被清理或淘汰的键调用回调
+func notifyEvicted(handlers []EvictHandler, keys []string, expired bool) {
+	for _, key := range keys {
+		for _, handler := range handlers {
+			handler(key, expired)
+		}
+	}
+}
+
 // Close 关闭缓存，停止垃圾回收
 func (c *MemoryCache) Close() {
 	close(c.stopChan)
 }
+
+// set 写入或替换条目并标记为最近使用，调用方需持有写锁
+func (c *MemoryCache) set(key string, value []models.Item, exp int64) {
+	c.remove(key)
+	size := itemsSize(key, value)
+	c.items[key] = item{
+		value:      value,
+		expiration: exp,
+		size:       size,
+		elem:       c.lru.PushFront(key),
+	}
+	c.bytes += size
+}
+
+// remove 删除条目，调用方需持有写锁
+func (c *MemoryCache) remove(key string) {
+	old, found := c.items[key]
+	if !found {
+		return
+	}
+	c.lru.Remove(old.elem)
+	c.bytes -= old.size
+	delete(c.items, key)
+}
+
+// evict 总占用超过内存预算时按最近最少使用的顺序淘汰条目，keep 返回true的键不会被淘汰，
+// 返回被淘汰的键，调用方需持有写锁
+func (c *MemoryCache) evict(keep func(key string) bool) []string {
+	var evicted []string
+	elem := c.lru.Back()
+	for c.maxBytes > 0 && c.bytes > c.maxBytes && elem != nil {
+		prev := elem.Prev()
+		if key := elem.Value.(string); !keep(key) {
+			c.remove(key)
+			c.evictions++
+			evicted = append(evicted, key)
+		}
+		elem = prev
+	}
+	return evicted
+}
+
+// itemOverhead 单个数据项除字符串和切片内容外的估算开销
+const itemOverhead = 256
+
+// itemsSize 估算条目占用的内存
+func itemsSize(key string, items []models.Item) int64 {
+	size := int64(len(key))
+	for _, it := range items {
+		size += itemOverhead + int64(len(it.ID)+len(it.Title)+len(it.URL)+len(it.Content)+
+			len(it.Source)+len(it.Category)+len(it.Author)+len(it.ImageURL))
+		for _, s := range it.Images {
+			size += 16 + int64(len(s))
+		}
+		for _, s := range it.Tags {
+			size += 16 + int64(len(s))
+		}
+		for k, v := range it.Extra {
+			size += 32 + int64(len(k))
+			if s, ok := v.(string); ok {
+				size += int64(len(s))
+			}
+		}
+	}
+	return size
+}
@@ -0,0 +1,103 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andybalholm/brotli"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// AcceptEncoding 请求头 Accept-Encoding 的值，与 ReadBody 支持的压缩格式一致
+// 手动设置该请求头后 http.Transport 不再自动解压，需要用 ReadBody 读取响应
+const AcceptEncoding = "gzip, deflate, br"
+
+// fallbackCharset 响应未声明字符集且内容不是合法UTF-8时使用的字符集，中文站点未声明时多为GBK，GB18030兼容GBK和GB2312
+const fallbackCharset = "gb18030"
+
+// sniffLen 在内容中查找字符集声明的范围，与HTML规范的预扫描长度一致
+const sniffLen = 1024
+
+// charsetPattern 匹配HTML meta标签和XML声明中的字符集
+var charsetPattern = regexp.MustCompile(`(?i)(?:<meta[^>]+charset|<\?xml[^>]+encoding)\s*=\s*["']?([\w-]+)`)
+
+// ReadBody 读取HTTP响应体，按 Content-Encoding 解压gzip、deflate和brotli内容，并将内容转换为UTF-8
+// 字符集依次取自 Content-Type、HTML meta标签或XML声明；都没有声明时，合法的UTF-8内容原样返回，否则按GB18030解码
+func ReadBody(resp *http.Response) ([]byte, error) {
+	body, err := decompress(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	return ToUTF8(body, resp.Header.Get("Content-Type"))
+}
+
+// decompress 按 Content-Encoding 读取并解压内容，不支持的压缩格式返回错误
+func decompress(body io.Reader, encoding string) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return io.ReadAll(body)
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	case "deflate":
+		// deflate 按规范是zlib格式，但不少服务器直接返回原始deflate数据
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		if reader, err := zlib.NewReader(bytes.NewReader(content)); err == nil {
+			defer reader.Close()
+			return io.ReadAll(reader)
+		}
+		return io.ReadAll(flate.NewReader(bytes.NewReader(content)))
+	case "br":
+		return io.ReadAll(brotli.NewReader(body))
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
+}
+
+// ToUTF8 将contentType或内容中声明了字符集的内容转换为UTF-8，未知的字符集原样返回
+func ToUTF8(content []byte, contentType string) ([]byte, error) {
+	name := declaredCharset(content, contentType)
+	if name == "" {
+		if utf8.Valid(content) {
+			return content, nil
+		}
+		name = fallbackCharset
+	}
+
+	encoding, err := htmlindex.Get(name)
+	if err != nil || encoding == unicode.UTF8 {
+		return content, nil
+	}
+	return encoding.NewDecoder().Bytes(content)
+}
+
+// declaredCharset 返回 Content-Type 或内容开头声明的字符集，没有声明时返回空字符串
+func declaredCharset(content []byte, contentType string) string {
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+		return params["charset"]
+	}
+	if len(content) > sniffLen {
+		content = content[:sniffLen]
+	}
+	if match := charsetPattern.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
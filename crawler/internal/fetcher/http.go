@@ -43,6 +43,7 @@ func (c *HttpClient) Get(ctx context.Context, url string) ([]byte, error) {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+	req.Header.Set("Accept-Encoding", AcceptEncoding)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -50,7 +51,7 @@ func (c *HttpClient) Get(ctx context.Context, url string) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
+	return ReadBody(resp)
 }
 
 // Post 发送POST请求
@@ -64,6 +65,7 @@ func (c *HttpClient) Post(ctx context.Context, url string, body io.Reader, heade
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
 	req.Header.Set("Accept", "application/json, text/plain, */*")
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", AcceptEncoding)
 
 	// 设置自定义请求头
 	for key, value := range headers {
@@ -76,5 +78,5 @@ func (c *HttpClient) Post(ctx context.Context, url string, body io.Reader, heade
 	}
 	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
+	return ReadBody(resp)
 }
\ No newline at end of file
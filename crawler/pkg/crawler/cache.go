@@ -3,6 +3,7 @@ package crawler
 import (
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/cache"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
@@ -19,4 +20,47 @@ type Cache interface {
 
 	// Clear 清空缓存
 	Clear() error
+
+	// MGet 批量获取数据，返回的map中不包含不存在或已过期的键
+	MGet(keys []string) (map[string][]models.Item, error)
+
+	// MSet 批量存入数据，所有数据使用相同的过期时间
+	MSet(entries map[string][]models.Item, expiration time.Duration) error
+
+	// DeleteByPrefix 删除所有以prefix开头的键
+	DeleteByPrefix(prefix string) error
+
+	// Keys 返回匹配pattern的未过期的键，按字典序排序
+	// pattern 使用 path.Match 的通配符语法，为空时返回所有键
+	Keys(pattern string) ([]string, error)
+}
+
+// CacheOption 配置内存缓存的选项
+type CacheOption = cache.Option
+
+// CacheStats 内存缓存的使用统计，包括条目数、估算占用和淘汰次数
+type CacheStats = cache.Stats
+
+// CacheEvictHandler 缓存条目被清理或淘汰后的回调，expired 表示是否因过期被清理
+type CacheEvictHandler = cache.EvictHandler
+
+// EvictingCache 可以通知条目淘汰的缓存，内存缓存实现了该接口；
+// 爬取引擎使用这类缓存时会发布 EventCacheEvicted 事件
+type EvictingCache interface {
+	Cache
+
+	// OnEvict 添加条目被清理或淘汰时的回调
+	OnEvict(handler CacheEvictHandler)
+}
+
+// WithCacheMaxBytes 设置内存缓存的内存预算，超出后按最近最少使用的顺序淘汰数据源的缓存，
+// 长期运行的部署可以借此限制内存占用；maxBytes 不大于0时不限制
+func WithCacheMaxBytes(maxBytes int64) CacheOption {
+	return cache.WithMaxBytes(maxBytes)
+}
+
+// NewMemoryCache 创建基于内存的缓存，cleanupInterval为清理过期数据的间隔
+// 不再使用时需要调用Close停止清理协程
+func NewMemoryCache(cleanupInterval time.Duration, opts ...CacheOption) *cache.MemoryCache {
+	return cache.NewMemoryCache(cleanupInterval, opts...)
 }
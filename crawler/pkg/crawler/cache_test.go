@@ -0,0 +1,99 @@
+package crawler_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+func TestMemoryCacheBatch(t *testing.T) {
+	memCache := crawler.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	var c crawler.Cache = memCache
+	if err := c.MSet(map[string][]models.Item{
+		"hackernews":      {{ID: "hn-1"}},
+		"github:trending": {{ID: "gh-1"}},
+		"github:releases": {{ID: "gh-2"}},
+	}, time.Hour); err != nil {
+		t.Fatalf("Failed to set items: %v", err)
+	}
+	if err := c.Set("expired", []models.Item{{ID: "old"}}, time.Nanosecond); err != nil {
+		t.Fatalf("Failed to set item: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	items, err := c.MGet([]string{"hackernews", "github:trending", "missing", "expired"})
+	if err != nil {
+		t.Fatalf("Failed to get items: %v", err)
+	}
+	if len(items) != 2 || items["github:trending"][0].ID != "gh-1" {
+		t.Errorf("Expected hackernews and github:trending, got %v", items)
+	}
+
+	keys, err := c.Keys("github:*")
+	if err != nil {
+		t.Fatalf("Failed to list keys: %v", err)
+	}
+	if !slices.Equal(keys, []string{"github:releases", "github:trending"}) {
+		t.Errorf("Expected github keys in order, got %v", keys)
+	}
+	if _, err := c.Keys("["); err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+
+	if err := c.DeleteByPrefix("github:"); err != nil {
+		t.Fatalf("Failed to delete by prefix: %v", err)
+	}
+	keys, _ = c.Keys("")
+	if !slices.Equal(keys, []string{"hackernews"}) {
+		t.Errorf("Expected only hackernews to remain, got %v", keys)
+	}
+}
+
+func TestMemoryCacheMaxBytes(t *testing.T) {
+	memCache := crawler.NewMemoryCache(1*time.Hour, crawler.WithCacheMaxBytes(2048))
+	defer memCache.Close()
+
+	large := []models.Item{{ID: "x", Content: strings.Repeat("a", 700)}}
+	for _, key := range []string{"a", "b"} {
+		if err := memCache.Set(key, large, time.Hour); err != nil {
+			t.Fatalf("Failed to set item: %v", err)
+		}
+	}
+
+	// 读取a后b成为最近最少使用的条目，写入c时被淘汰
+	if items, _ := memCache.Get("a"); items == nil {
+		t.Fatal("Expected a to be cached")
+	}
+	if err := memCache.Set("c", large, time.Hour); err != nil {
+		t.Fatalf("Failed to set item: %v", err)
+	}
+
+	keys, _ := memCache.Keys("")
+	if !slices.Equal(keys, []string{"a", "c"}) {
+		t.Errorf("Expected b to be evicted, got %v", keys)
+	}
+	stats := memCache.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 || stats.Bytes > stats.MaxBytes {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// 超出预算的单个条目仍会写入，其他条目全部被淘汰
+	huge := []models.Item{{ID: "y", Content: strings.Repeat("b", 4096)}}
+	if err := memCache.Set("d", huge, time.Hour); err != nil {
+		t.Fatalf("Failed to set item: %v", err)
+	}
+	if keys, _ := memCache.Keys(""); !slices.Equal(keys, []string{"d"}) {
+		t.Errorf("Expected only d to remain, got %v", keys)
+	}
+
+	memCache.Clear()
+	if stats := memCache.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("Expected empty cache after Clear, got %+v", stats)
+	}
+}
@@ -2,6 +2,7 @@ package crawler
 
 import (
 	"context"
+	"time"
 
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
@@ -29,3 +30,42 @@ type Engine interface {
 	// Unsubscribe 取消订阅
 	Unsubscribe(sourceName string, ch chan<- []models.Item) error
 }
+
+// ControllableEngine 在 Engine 的基础上支持分类订阅、运行状态、手动控制、回放和事件订阅的爬取引擎
+// NewEngine 创建的引擎实现了此接口；Engine 的其他实现不需要实现，需要这些功能的调用方通过类型断言判断
+type ControllableEngine interface {
+	Engine
+
+	// SubscribeCategory 订阅分类下所有数据源的更新，包括订阅之后注册的数据源
+	SubscribeCategory(category string, ch chan<- []models.Item) error
+
+	// UnsubscribeCategory 取消分类订阅
+	UnsubscribeCategory(category string, ch chan<- []models.Item) error
+
+	// SubscribeAll 订阅所有数据源的更新，包括订阅之后注册的数据源
+	SubscribeAll(ch chan<- []models.Item) error
+
+	// UnsubscribeAll 取消 SubscribeAll 的订阅
+	UnsubscribeAll(ch chan<- []models.Item) error
+
+	// Status 返回所有已注册数据源的运行状态，按名称排序
+	Status() []SourceStatus
+
+	// Trigger 立即爬取指定数据源，不读取缓存，结果会更新缓存并通知订阅者
+	Trigger(ctx context.Context, sourceName string) ([]models.Item, error)
+
+	// ReplayLatest 将缓存中数据源最近一次爬取结果里在since之后发布的数据项发送到ch，不通知其他订阅者
+	ReplayLatest(ctx context.Context, sourceName string, since time.Time, ch chan<- []models.Item) ([]models.Item, error)
+
+	// Pause 暂停数据源的定时爬取
+	Pause(sourceName string) error
+
+	// Resume 恢复数据源的定时爬取
+	Resume(sourceName string) error
+
+	// SubscribeEvents 订阅引擎事件，types 为空时订阅所有类型的事件
+	SubscribeEvents(ch chan<- Event, types ...EventType) error
+
+	// UnsubscribeEvents 取消事件订阅
+	UnsubscribeEvents(ch chan<- Event) error
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 	"github.com/sjzsdu/utils/crawler/pkg/scheduler"
+	"github.com/sjzsdu/utils/crawler/pkg/taxonomy"
+	"github.com/sjzsdu/utils/logging"
 )
 
 // engineImpl 是 Engine 接口的实现
@@ -18,6 +20,12 @@ type engineImpl struct {
 	// 订阅者映射
 	subscribers map[string][]chan<- []models.Item
 
+	// 按分类订阅的订阅者映射
+	categorySubscribers map[string][]chan<- []models.Item
+
+	// 订阅所有数据源的订阅者
+	allSubscribers []chan<- []models.Item
+
 	// 缓存
 	cache Cache
 
@@ -35,6 +43,84 @@ type engineImpl struct {
 
 	// 调度器
 	scheduler scheduler.Scheduler
+
+	// 日志记录器
+	logger logging.Logger
+
+	// 单次爬取的默认截止时间
+	fetchTimeout time.Duration
+
+	// 已暂停定时爬取的数据源
+	paused map[string]bool
+
+	// 数据源的爬取统计
+	stats map[string]*fetchStats
+
+	// 每个数据源最多保留的数据项数量，0 表示不限制
+	maxItems int
+
+	// 缓存数据项的过期时间，0 表示使用数据源的爬取间隔
+	itemTTL time.Duration
+
+	// 录制原始响应的目录，为空时不录制
+	recordDir string
+
+	// 只保留这些语言和国家或地区的数据项，为空时不过滤
+	languages []string
+	countries []string
+
+	// 是否规范化发布时间，以及发布时间允许超前于本机时间的范围
+	normalizeTimes bool
+	clockSkew      time.Duration
+
+	// 事件订阅者，由 eventMu 保护，发布事件时不需要持有 mu
+	eventSubscribers []eventSubscriber
+	eventMu          sync.RWMutex
+}
+
+// DefaultFetchTimeout 数据源没有设置超时时单次爬取的默认截止时间
+const DefaultFetchTimeout = 30 * time.Second
+
+// EngineOption 配置爬取引擎的选项
+type EngineOption func(*engineImpl)
+
+// WithLogger 设置爬取引擎的日志记录器，同时用于内部的调度器，默认不输出日志
+func WithLogger(logger logging.Logger) EngineOption {
+	return func(e *engineImpl) {
+		e.logger = logging.OrNop(logger)
+	}
+}
+
+// WithFetchTimeout 设置单次爬取的默认截止时间，超时后取消数据源的请求，避免慢速站点长期占用调度器；
+// 数据源通过 TimeoutSource 设置的超时优先，timeout 不大于0时不设置默认截止时间
+func WithFetchTimeout(timeout time.Duration) EngineOption {
+	return func(e *engineImpl) {
+		e.fetchTimeout = timeout
+	}
+}
+
+// WithMaxItemsPerSource 设置每个数据源每次爬取最多保留的数据项数量，超出的部分在写入缓存和通知订阅者之前丢弃，
+// 被丢弃的数量记录在 SourceStatus.Trimmed 中；n 不大于0时不限制
+func WithMaxItemsPerSource(n int) EngineOption {
+	return func(e *engineImpl) {
+		e.maxItems = max(n, 0)
+	}
+}
+
+// WithItemTTL 设置爬取结果在缓存中的过期时间，默认使用数据源的爬取间隔；
+// ttl 不大于0时恢复默认值
+func WithItemTTL(ttl time.Duration) EngineOption {
+	return func(e *engineImpl) {
+		e.itemTTL = max(ttl, 0)
+	}
+}
+
+// WithRecordDir 开启开发模式，注册的数据源会被 RecordingSource 包装，每次爬取的原始响应保存到dir中，
+// 之后可以用 ReplaySource 或 ParseRecording 离线调试解析逻辑；dir 为空时不录制
+func WithRecordDir(dir string) EngineOption {
+	return func(e *engineImpl) {
+		e.recordDir = dir
+	}
 }
 
 // crawlTask 实现了 scheduler.Task 接口，用于爬取数据源
@@ -60,17 +146,33 @@ func (t *crawlTask) Interval() int {
 }
 
 // NewEngine 创建一个新的爬取引擎实例
-func NewEngine(cache Cache) Engine {
+func NewEngine(cache Cache, opts ...EngineOption) ControllableEngine {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &engineImpl{
-		sources:     make(map[string]Source),
-		subscribers: make(map[string][]chan<- []models.Item),
-		cache:       cache,
-		ctx:         ctx,
-		cancel:      cancel,
-		running:     false,
-		scheduler:   scheduler.NewInMemoryScheduler(),
+	e := &engineImpl{
+		sources:             make(map[string]Source),
+		subscribers:         make(map[string][]chan<- []models.Item),
+		categorySubscribers: make(map[string][]chan<- []models.Item),
+		cache:               cache,
+		ctx:                 ctx,
+		cancel:              cancel,
+		running:             false,
+		logger:              logging.Nop(),
+		fetchTimeout:        DefaultFetchTimeout,
+		normalizeTimes:      true,
+		clockSkew:           DefaultClockSkew,
+		paused:              make(map[string]bool),
+		stats:               make(map[string]*fetchStats),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.scheduler = scheduler.NewInMemoryScheduler(scheduler.WithLogger(e.logger))
+	if c, ok := cache.(EvictingCache); ok {
+		c.OnEvict(func(key string, expired bool) {
+			e.publish(Event{Type: EventCacheEvicted, Source: key, Expired: expired})
+		})
 	}
+	return e
 }
 
 // RegisterSource 注册数据源
@@ -82,8 +184,12 @@ func (e *engineImpl) RegisterSource(source Source) error {
 	if _, exists := e.sources[name]; exists {
 		return fmt.Errorf("source %s already registered", name)
 	}
+	if e.recordDir != "" {
+		source = NewRecordingSource(source, e.recordDir)
+	}
 
 	e.sources[name] = source
+	e.publish(Event{Type: EventSourceRegistered, Source: name})
 
 	// 如果引擎正在运行，将任务添加到调度器
 	if e.running {
@@ -106,10 +212,19 @@ func (e *engineImpl) UnregisterSource(name string) error {
 		return fmt.Errorf("source %s not found", name)
 	}
 
+	paused := e.paused[name]
 	delete(e.sources, name)
+	delete(e.paused, name)
+	delete(e.stats, name)
+	e.publish(Event{Type: EventSourceUnregistered, Source: name})
 
-	// 如果引擎正在运行，从调度器中移除任务
-	if e.running {
+	// 清除数据源的缓存，重新注册后不会读到过期的数据
+	if err := e.cache.Delete(name); err != nil {
+		e.logger.Warn("failed to invalidate cache", logging.KeySource, name, logging.KeyError, err)
+	}
+
+	// 如果引擎正在运行，从调度器中移除任务，已暂停的数据源没有任务
+	if e.running && !paused {
 		return e.scheduler.RemoveTask(name)
 	}
 
@@ -126,15 +241,18 @@ func (e *engineImpl) Start(ctx context.Context) error {
 	e.running = true
 	e.mu.Unlock()
 
-	// 将所有数据源添加到调度器
+	// 将所有未暂停的数据源添加到调度器
 	e.mu.RLock()
-	for _, source := range e.sources {
+	for name, source := range e.sources {
+		if e.paused[name] {
+			continue
+		}
 		task := &crawlTask{
 			source: source,
 			engine: e,
 		}
 		if err := e.scheduler.AddTask(task); err != nil {
-			fmt.Printf("Failed to add task for source %s: %v\n", source.GetName(), err)
+			e.logger.Error("failed to add task", logging.KeySource, source.GetName(), logging.KeyError, err)
 			continue
 		}
 	}
@@ -182,18 +300,18 @@ func (e *engineImpl) FetchItem(ctx context.Context, sourceName string) ([]models
 	}
 
 	// 缓存未命中，直接爬取
-	content, err := source.Fetch(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	items, err = source.Parse(content)
+	ctx, cancel := withSourceTimeout(ctx, source, e.fetchTimeout)
+	defer cancel()
+	e.publish(Event{Type: EventFetchStarted, Source: sourceName})
+	start := time.Now()
+	items, err = Run(ctx, source)
+	e.recordFetch(sourceName, start, items, err)
 	if err != nil {
 		return nil, err
 	}
 
 	// 更新缓存
-	e.cache.Set(sourceName, items, time.Duration(source.GetInterval())*time.Second)
+	items = e.store(source, items, start)
 
 	// 通知订阅者
 	e.notifySubscribers(sourceName, items)
@@ -219,50 +337,151 @@ func (e *engineImpl) Unsubscribe(sourceName string, ch chan<- []models.Item) err
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if subscribers, exists := e.subscribers[sourceName]; exists {
-		for i, subscriber := range subscribers {
-			if subscriber == ch {
-				e.subscribers[sourceName] = append(subscribers[:i], subscribers[i+1:]...)
-				break
-			}
-		}
+	e.subscribers[sourceName] = removeSubscriber(e.subscribers[sourceName], ch)
+	return nil
+}
+
+// SubscribeCategory 订阅分类下所有数据源的更新
+// 分类在每次通知时按数据源的 GetCategories 匹配，之后注册的同分类数据源同样会通知到ch；
+// 分类的规范ID、显示名称和别名（见 taxonomy 包）视为同一个分类
+func (e *engineImpl) SubscribeCategory(category string, ch chan<- []models.Item) error {
+	if category == "" {
+		return fmt.Errorf("category is empty")
 	}
 
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	category = taxonomy.Key(category)
+	e.categorySubscribers[category] = append(e.categorySubscribers[category], ch)
+	return nil
+}
+
+// UnsubscribeCategory 取消分类订阅
+func (e *engineImpl) UnsubscribeCategory(category string, ch chan<- []models.Item) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	category = taxonomy.Key(category)
+	e.categorySubscribers[category] = removeSubscriber(e.categorySubscribers[category], ch)
+	if len(e.categorySubscribers[category]) == 0 {
+		delete(e.categorySubscribers, category)
+	}
+	return nil
+}
+
+// SubscribeAll 订阅所有数据源的更新
+func (e *engineImpl) SubscribeAll(ch chan<- []models.Item) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.allSubscribers = append(e.allSubscribers, ch)
+	return nil
+}
+
+// UnsubscribeAll 取消 SubscribeAll 的订阅
+func (e *engineImpl) UnsubscribeAll(ch chan<- []models.Item) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.allSubscribers = removeSubscriber(e.allSubscribers, ch)
 	return nil
 }
 
+// removeSubscriber 从订阅者列表中移除ch，返回新的列表，不修改原列表的底层数组
+func removeSubscriber(subscribers []chan<- []models.Item, ch chan<- []models.Item) []chan<- []models.Item {
+	for i, subscriber := range subscribers {
+		if subscriber == ch {
+			return append(subscribers[:i:i], subscribers[i+1:]...)
+		}
+	}
+	return subscribers
+}
+
 // fetchAndProcess 获取并处理数据源
 func (e *engineImpl) fetchAndProcess(source Source) {
-	content, err := source.Fetch(e.ctx)
+	name := source.GetName()
+
+	ctx, cancel := withSourceTimeout(e.ctx, source, e.fetchTimeout)
+	defer cancel()
+
+	e.publish(Event{Type: EventFetchStarted, Source: name})
+	start := time.Now()
+	content, err := source.Fetch(ctx)
 	if err != nil {
-		fmt.Printf("Failed to fetch source %s: %v\n", source.GetName(), err)
+		e.recordFetch(name, start, nil, fmt.Errorf("fetch %s: %w", name, err))
+		e.logger.Error("failed to fetch source", logging.KeySource, name, logging.KeyError, err)
 		return
 	}
 
 	items, err := source.Parse(content)
 	if err != nil {
-		fmt.Printf("Failed to parse source %s: %v\n", source.GetName(), err)
+		e.recordFetch(name, start, nil, fmt.Errorf("parse %s: %w", name, err))
+		e.logger.Error("failed to parse source", logging.KeySource, name, logging.KeyError, err)
 		return
 	}
-
-	name := source.GetName()
+	e.recordFetch(name, start, items, nil)
+	e.logger.Debug("fetched source", logging.KeySource, name, "items", len(items))
 
 	// 更新缓存
-	e.cache.Set(name, items, time.Duration(source.GetInterval())*time.Second)
+	items = e.store(source, items, start)
 
 	// 通知订阅者
 	e.notifySubscribers(name, items)
 }
 
-// notifySubscribers 通知订阅者
+// store 为爬取结果补充语言和地区信息并规范化发布时间，按语言和地区过滤并按 maxItems 截断后写入缓存，返回处理后的结果
+// start 为本次爬取开始的时间
+func (e *engineImpl) store(source Source, items []models.Item, start time.Time) []models.Item {
+	localized := localize(source, items)
+	if e.normalizeTimes {
+		previous, _ := e.cache.Get(source.GetName())
+		normalizeTimes(source, localized, previous, start, e.clockSkew)
+	}
+	items = e.filterLocale(localized)
+	filtered := len(localized) - len(items)
+
+	trimmed := 0
+	if e.maxItems > 0 && len(items) > e.maxItems {
+		trimmed = len(items) - e.maxItems
+		items = items[:e.maxItems:e.maxItems]
+	}
+	e.recordStore(source.GetName(), filtered, trimmed)
+
+	ttl := e.itemTTL
+	if ttl == 0 {
+		ttl = time.Duration(source.GetInterval()) * time.Second
+	}
+	if err := e.cache.Set(source.GetName(), items, ttl); err != nil {
+		e.logger.Warn("failed to cache items", logging.KeySource, source.GetName(), logging.KeyError, err)
+	}
+	return items
+}
+
+// notifySubscribers 通知订阅者，包括数据源、其所属分类以及所有数据源的订阅者，然后发布 EventItemsPublished 事件
+// 同一个通道通过多种方式订阅时只通知一次
 func (e *engineImpl) notifySubscribers(sourceName string, items []models.Item) {
-	e.mu.RLock()
-	subscribers, exists := e.subscribers[sourceName]
-	e.mu.RUnlock()
+	defer e.publish(Event{Type: EventItemsPublished, Source: sourceName, Items: len(items)})
 
-	if !exists {
-		return
+	e.mu.RLock()
+	var subscribers []chan<- []models.Item
+	seen := make(map[chan<- []models.Item]bool)
+	add := func(chs []chan<- []models.Item) {
+		for _, ch := range chs {
+			if !seen[ch] {
+				seen[ch] = true
+				subscribers = append(subscribers, ch)
+			}
+		}
 	}
+	add(e.subscribers[sourceName])
+	if source, exists := e.sources[sourceName]; exists && len(e.categorySubscribers) > 0 {
+		for _, category := range source.GetCategories() {
+			add(e.categorySubscribers[taxonomy.Key(category)])
+		}
+	}
+	add(e.allSubscribers)
+	e.mu.RUnlock()
 
 	// 通知所有订阅者
 	for _, ch := range subscribers {
@@ -270,7 +489,7 @@ func (e *engineImpl) notifySubscribers(sourceName string, items []models.Item) {
 		case ch <- items:
 		default:
 			// 如果通道已满，跳过本次通知
-			fmt.Printf("Subscriber channel for source %s is full, skipping notification\n", sourceName)
+			e.logger.Warn("subscriber channel is full, skipping notification", logging.KeySource, sourceName)
 		}
 	}
 }
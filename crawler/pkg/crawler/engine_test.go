@@ -1,13 +1,20 @@
 package crawler_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/sjzsdu/utils/crawler/internal/cache"
 	"github.com/sjzsdu/utils/crawler/pkg/crawler"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/logging"
 )
 
 // mockSource 是一个用于测试的模拟数据源
@@ -114,3 +121,543 @@ func TestEngine(t *testing.T) {
 		t.Errorf("Failed to stop engine: %v", err)
 	}
 }
+
+// failingSource 获取内容总是失败的数据源
+type failingSource struct {
+	mockSource
+}
+
+func (f *failingSource) Fetch(ctx context.Context) ([]byte, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestEngineLogger(t *testing.T) {
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	var buf syncBuffer
+	logger := logging.New(slog.New(slog.NewTextHandler(&buf, nil)))
+	engine := crawler.NewEngine(memCache, crawler.WithLogger(logger))
+
+	source := &failingSource{mockSource{name: "broken", interval: 60}}
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+	if err := engine.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start engine: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(buf.String(), "failed to fetch source") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	engine.Stop()
+
+	output := buf.String()
+	if !strings.Contains(output, "failed to fetch source") || !strings.Contains(output, "source=broken") {
+		t.Errorf("Expected fetch error to be logged with source field, got: %s", output)
+	}
+}
+
+func TestRun(t *testing.T) {
+	source := &mockSource{
+		name:  "adhoc",
+		items: []models.Item{{ID: "adhoc-1", Title: "Ad-hoc Item"}},
+	}
+
+	items, err := crawler.Run(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Failed to run source: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Ad-hoc Item" {
+		t.Errorf("Expected the parsed item, got %v", items)
+	}
+
+	_, err = crawler.Run(context.Background(), &failingSource{mockSource{name: "broken"}})
+	if err == nil || !strings.Contains(err.Error(), "fetch broken") {
+		t.Errorf("Expected fetch error with source name, got %v", err)
+	}
+}
+
+// slowSource 获取内容时一直阻塞到上下文结束的数据源
+type slowSource struct {
+	mockSource
+	timeout time.Duration
+}
+
+func (s *slowSource) Fetch(ctx context.Context) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *slowSource) GetTimeout() time.Duration {
+	return s.timeout
+}
+
+func TestEngineFetchTimeout(t *testing.T) {
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache, crawler.WithFetchTimeout(50*time.Millisecond))
+	if err := engine.RegisterSource(&slowSource{mockSource: mockSource{name: "slow"}}); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+	if err := engine.RegisterSource(&slowSource{mockSource: mockSource{name: "slower"}, timeout: 150 * time.Millisecond}); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+
+	for name, expected := range map[string]time.Duration{"slow": 50 * time.Millisecond, "slower": 150 * time.Millisecond} {
+		start := time.Now()
+		_, err := engine.FetchItem(context.Background(), name)
+		elapsed := time.Since(start)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded for %s, got %v", name, err)
+		}
+		if elapsed < expected || elapsed > expected+time.Second {
+			t.Errorf("Expected %s to time out after %v, took %v", name, expected, elapsed)
+		}
+	}
+}
+
+// syncBuffer 并发安全的 bytes.Buffer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestEngineStatus(t *testing.T) {
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache)
+	source := &mockSource{name: "test", url: "https://example.com/api/list?page=1", interval: 60, items: []models.Item{{ID: "test-1"}}}
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+	if err := engine.RegisterSource(&failingSource{mockSource{name: "broken", interval: 60}}); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+
+	// Trigger 忽略缓存，每次都重新爬取
+	ch := make(chan []models.Item, 10)
+	engine.Subscribe("test", ch)
+	for range 2 {
+		if _, err := engine.Trigger(context.Background(), "test"); err != nil {
+			t.Fatalf("Failed to trigger source: %v", err)
+		}
+	}
+	if len(ch) != 2 {
+		t.Errorf("Expected 2 notifications, got %d", len(ch))
+	}
+	<-ch
+	<-ch
+	if _, err := engine.Trigger(context.Background(), "broken"); err == nil {
+		t.Error("Expected trigger to fail")
+	}
+	if err := engine.Pause("test"); err != nil {
+		t.Fatalf("Failed to pause source: %v", err)
+	}
+
+	statuses := engine.Status()
+	if len(statuses) != 2 || statuses[0].Name != "broken" || statuses[1].Name != "test" {
+		t.Fatalf("Expected statuses sorted by name, got %+v", statuses)
+	}
+	if broken := statuses[0]; broken.Failures != 1 || !strings.Contains(broken.LastError, "fetch broken") {
+		t.Errorf("Expected recorded failure, got %+v", broken)
+	}
+	if status := statuses[1]; !status.Paused || status.Fetches != 2 || status.ItemCount != 1 || status.LastFetch.IsZero() {
+		t.Errorf("Expected paused source with 2 fetches, got %+v", status)
+	}
+	if homepage := statuses[1].Homepage; homepage != "https://example.com/" {
+		t.Errorf("Expected homepage derived from source URL, got %q", homepage)
+	}
+
+	// 暂停的数据源启动后不会定时爬取，恢复后重新加入调度
+	if err := engine.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start engine: %v", err)
+	}
+	defer engine.Stop()
+	select {
+	case <-ch:
+		t.Error("Expected paused source not to be crawled")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := engine.Resume("test"); err != nil {
+		t.Fatalf("Failed to resume source: %v", err)
+	}
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected resumed source to be crawled")
+	}
+}
+
+// categorySource 可以指定分类的模拟数据源
+type categorySource struct {
+	mockSource
+	categories []string
+}
+
+func (c *categorySource) GetCategories() []string {
+	return c.categories
+}
+
+func TestEngineSubscribeCategory(t *testing.T) {
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache)
+	news := &categorySource{mockSource{name: "news", interval: 60}, []string{"news", "finance"}}
+	tech := &categorySource{mockSource{name: "tech", interval: 60}, []string{"tech"}}
+	if err := engine.RegisterSource(news); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+
+	newsCh := make(chan []models.Item, 10)
+	allCh := make(chan []models.Item, 10)
+	if err := engine.SubscribeCategory("news", newsCh); err != nil {
+		t.Fatalf("Failed to subscribe category: %v", err)
+	}
+	// 同一个通道通过数据源和分类订阅时只通知一次
+	if err := engine.Subscribe("news", newsCh); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	if err := engine.SubscribeAll(allCh); err != nil {
+		t.Fatalf("Failed to subscribe all: %v", err)
+	}
+	if err := engine.SubscribeCategory("", newsCh); err == nil {
+		t.Error("Expected empty category to be rejected")
+	}
+
+	// 订阅之后注册的数据源同样会通知
+	if err := engine.RegisterSource(tech); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+	for _, name := range []string{"news", "tech"} {
+		if _, err := engine.Trigger(context.Background(), name); err != nil {
+			t.Fatalf("Failed to trigger source: %v", err)
+		}
+	}
+	if len(newsCh) != 1 {
+		t.Errorf("Expected 1 category notification, got %d", len(newsCh))
+	}
+	if len(allCh) != 2 {
+		t.Errorf("Expected 2 notifications for all sources, got %d", len(allCh))
+	}
+
+	engine.Unsubscribe("news", newsCh)
+	if err := engine.UnsubscribeCategory("news", newsCh); err != nil {
+		t.Fatalf("Failed to unsubscribe category: %v", err)
+	}
+	if err := engine.UnsubscribeAll(allCh); err != nil {
+		t.Fatalf("Failed to unsubscribe all: %v", err)
+	}
+	if _, err := engine.Trigger(context.Background(), "news"); err != nil {
+		t.Fatalf("Failed to trigger source: %v", err)
+	}
+	if len(newsCh) != 1 || len(allCh) != 2 {
+		t.Errorf("Expected no notifications after unsubscribe, got %d and %d", len(newsCh), len(allCh))
+	}
+}
+
+func TestEngineMaxItemsPerSource(t *testing.T) {
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache, crawler.WithMaxItemsPerSource(2), crawler.WithItemTTL(time.Millisecond))
+	source := &mockSource{name: "test", interval: 3600, items: []models.Item{{ID: "1"}, {ID: "2"}, {ID: "3"}}}
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+
+	ch := make(chan []models.Item, 1)
+	engine.Subscribe("test", ch)
+	items, err := engine.Trigger(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Failed to trigger source: %v", err)
+	}
+	if len(items) != 2 || len(<-ch) != 2 {
+		t.Errorf("Expected items trimmed to 2, got %v", items)
+	}
+	if status := engine.Status()[0]; status.ItemCount != 3 || status.Trimmed != 1 {
+		t.Errorf("Expected 1 trimmed item, got %+v", status)
+	}
+
+	// 缓存使用 WithItemTTL 设置的过期时间，而不是数据源的爬取间隔
+	time.Sleep(5 * time.Millisecond)
+	if cached, _ := memCache.Get("test"); cached != nil {
+		t.Errorf("Expected cached items to expire, got %v", cached)
+	}
+}
+
+// localizedMockSource 声明了国家或地区和语言的模拟数据源
+type localizedMockSource struct {
+	*mockSource
+	country, language string
+}
+
+func (m *localizedMockSource) GetCountry() string {
+	return m.country
+}
+
+func (m *localizedMockSource) GetLanguage() string {
+	return m.language
+}
+
+func TestEngineLanguages(t *testing.T) {
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache, crawler.WithLanguages("zh"), crawler.WithCountries("cn"), crawler.WithMaxItemsPerSource(2))
+	source := &localizedMockSource{
+		mockSource: &mockSource{name: "test", interval: 3600, items: []models.Item{
+			{ID: "1", Title: "央行宣布降准"},
+			{ID: "2", Title: "Apple releases new iPhone"},
+			{ID: "3", Title: "東京株式市場の動き"},
+			{ID: "4", Title: "Breaking news", Language: "en"},
+			{ID: "5", Title: "港股收盘", Country: "HK"},
+			{ID: "6", Title: "国内油价上调"},
+		}},
+		country:  "CN",
+		language: "zh-CN",
+	}
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+
+	items, err := engine.Trigger(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Failed to trigger source: %v", err)
+	}
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+		if item.Language != "zh-CN" || item.Country != "CN" {
+			t.Errorf("Expected item %s localized from source metadata, got %q and %q", item.ID, item.Language, item.Country)
+		}
+	}
+	// 英文标题使用数据源声明的语言，日文和数据源设置的语言、地区优先
+	if !slices.Equal(ids, []string{"1", "2"}) {
+		t.Errorf("Expected items 1 and 2 after filtering and trimming, got %v", ids)
+	}
+	if status := engine.Status()[0]; status.Filtered != 3 || status.Trimmed != 1 {
+		t.Errorf("Expected 3 filtered and 1 trimmed items, got %+v", status)
+	}
+	if source.items[0].Language != "" {
+		t.Errorf("Expected items returned by the source to be left untouched")
+	}
+}
+
+// nowSource 解析时以当前时间作为发布时间的模拟数据源
+type nowSource struct {
+	*localizedMockSource
+}
+
+func (m *nowSource) Parse(content []byte) ([]models.Item, error) {
+	return append(slices.Clone(m.items), models.Item{ID: "now", PublishedAt: time.Now()}), nil
+}
+
+func TestEngineTimeNormalization(t *testing.T) {
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	ref := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	wall := ref.In(time.FixedZone("CST", 8*3600))
+	engine := crawler.NewEngine(memCache)
+	source := &nowSource{&localizedMockSource{
+		mockSource: &mockSource{name: "test", interval: 3600, items: []models.Item{
+			{ID: "past", PublishedAt: ref},
+			{ID: "zero"},
+			{ID: "local", PublishedAt: time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC)},
+			{ID: "millis", PublishedAt: time.Unix(ref.UnixMilli(), 0)},
+			{ID: "future", PublishedAt: ref.Add(48 * time.Hour)},
+		}},
+		country: "CN",
+	}}
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+
+	first, err := engine.Trigger(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Failed to trigger source: %v", err)
+	}
+	byID := make(map[string]models.Item)
+	for _, item := range first {
+		byID[item.ID] = item
+	}
+	for _, id := range []string{"past", "local", "millis"} {
+		if item := byID[id]; !item.PublishedAt.Equal(ref) || item.TimeEstimated {
+			t.Errorf("Expected %s to be published at %s, got %s (estimated %v)", id, ref, item.PublishedAt, item.TimeEstimated)
+		}
+	}
+	for _, id := range []string{"zero", "future", "now"} {
+		if item := byID[id]; !item.TimeEstimated || item.PublishedAt.After(time.Now()) || item.PublishedAt.Before(ref) {
+			t.Errorf("Expected %s to have an estimated time around now, got %s (estimated %v)", id, item.PublishedAt, item.TimeEstimated)
+		}
+	}
+
+	// 估计的发布时间沿用上一次爬取的结果
+	time.Sleep(5 * time.Millisecond)
+	second, err := engine.Trigger(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Failed to trigger source: %v", err)
+	}
+	for _, item := range second {
+		if item.TimeEstimated && !item.PublishedAt.Equal(byID[item.ID].PublishedAt) {
+			t.Errorf("Expected estimated time of %s to be kept, got %s and %s", item.ID, byID[item.ID].PublishedAt, item.PublishedAt)
+		}
+	}
+
+	disabled := crawler.NewEngine(memCache, crawler.WithTimeNormalization(false))
+	disabled.RegisterSource(&mockSource{name: "raw", interval: 3600, items: []models.Item{{ID: "zero"}}})
+	if items, _ := disabled.Trigger(context.Background(), "raw"); !items[0].PublishedAt.IsZero() || items[0].TimeEstimated {
+		t.Errorf("Expected publish time to be left untouched, got %+v", items[0])
+	}
+}
+
+func TestEngineEvents(t *testing.T) {
+	memCache := crawler.NewMemoryCache(1*time.Hour, crawler.WithCacheMaxBytes(2048))
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache)
+	events := make(chan crawler.Event, 100)
+	if err := engine.SubscribeEvents(events); err != nil {
+		t.Fatalf("Failed to subscribe events: %v", err)
+	}
+	failures := make(chan crawler.Event, 10)
+	if err := engine.SubscribeEvents(failures, crawler.EventFetchFailed); err != nil {
+		t.Fatalf("Failed to subscribe events: %v", err)
+	}
+
+	large := []models.Item{{ID: "x", Content: strings.Repeat("a", 1200)}}
+	sources := []crawler.Source{
+		&mockSource{name: "a", interval: 60, items: large},
+		&mockSource{name: "b", interval: 60, items: large},
+		&failingSource{mockSource{name: "broken", interval: 60}},
+	}
+	for _, source := range sources {
+		if err := engine.RegisterSource(source); err != nil {
+			t.Fatalf("Failed to register source: %v", err)
+		}
+	}
+
+	// 缓存b时超出内存预算，a被淘汰
+	for _, name := range []string{"a", "b", "broken"} {
+		engine.Trigger(context.Background(), name)
+	}
+	if err := engine.UnsubscribeEvents(events); err != nil {
+		t.Fatalf("Failed to unsubscribe events: %v", err)
+	}
+	engine.UnregisterSource("a")
+	close(events)
+
+	var types []string
+	for event := range events {
+		types = append(types, string(event.Type)+":"+event.Source)
+		if event.Time.IsZero() {
+			t.Errorf("Expected event time to be set: %+v", event)
+		}
+		if event.Type == crawler.EventItemsPublished && event.Items != 1 {
+			t.Errorf("Expected 1 published item, got %d", event.Items)
+		}
+	}
+	expected := []string{
+		"source_registered:a", "source_registered:b", "source_registered:broken",
+		"fetch_started:a", "fetch_succeeded:a", "items_published:a",
+		"fetch_started:b", "fetch_succeeded:b", "cache_evicted:a", "items_published:b",
+		"fetch_started:broken", "fetch_failed:broken",
+	}
+	if !slices.Equal(types, expected) {
+		t.Errorf("Unexpected events:\n got %v\nwant %v", types, expected)
+	}
+
+	if len(failures) != 1 {
+		t.Fatalf("Expected 1 failure event, got %d", len(failures))
+	}
+	if event := <-failures; event.Err == nil || !strings.Contains(event.Err.Error(), "connection refused") {
+		t.Errorf("Expected fetch error in failure event, got %v", event.Err)
+	}
+}
+
+func TestEngineReplayLatest(t *testing.T) {
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	now := time.Now()
+	engine := crawler.NewEngine(memCache)
+	source := &mockSource{name: "replay", interval: 60, items: []models.Item{
+		{ID: "old", PublishedAt: now.Add(-3 * time.Hour)},
+		{ID: "recent", PublishedAt: now.Add(-10 * time.Minute)},
+		{ID: "created", CreatedAt: now.Add(-30 * time.Minute)},
+		{ID: "undated"},
+	}}
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+	if _, err := engine.Trigger(context.Background(), "replay"); err != nil {
+		t.Fatalf("Failed to trigger source: %v", err)
+	}
+
+	// 爬取之后才订阅的消费者通过回放补齐最近一小时的数据，已有的订阅者不会重复收到
+	existing := make(chan []models.Item, 1)
+	if err := engine.Subscribe("replay", existing); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	ch := make(chan []models.Item, 1)
+	if err := engine.Subscribe("replay", ch); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	items, err := engine.ReplayLatest(context.Background(), "replay", now.Add(-time.Hour), ch)
+	if err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+	if len(items) != 3 {
+		t.Errorf("Expected 3 replayed items, got %d", len(items))
+	}
+	select {
+	case received := <-ch:
+		var ids []string
+		for _, item := range received {
+			ids = append(ids, item.ID)
+		}
+		if !slices.Equal(ids, []string{"recent", "created", "undated"}) {
+			t.Errorf("Unexpected replayed items: %v", ids)
+		}
+	default:
+		t.Error("Expected subscriber to receive replayed items")
+	}
+	select {
+	case received := <-existing:
+		t.Errorf("Expected existing subscriber not to receive replayed items, got %d", len(received))
+	default:
+	}
+
+	if items, err := engine.ReplayLatest(context.Background(), "replay", now.Add(-time.Hour), nil); err != nil || len(items) != 3 {
+		t.Errorf("Expected 3 replayed items without channel, got %d (%v)", len(items), err)
+	}
+
+	// 通道已满时在ctx结束后返回
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ch <- nil
+	if _, err := engine.ReplayLatest(ctx, "replay", now.Add(-time.Hour), ch); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded replaying to a full channel, got %v", err)
+	}
+
+	if _, err := engine.ReplayLatest(context.Background(), "missing", now, nil); err == nil {
+		t.Error("Expected error replaying unknown source")
+	}
+}
@@ -0,0 +1,126 @@
+package crawler
+
+import (
+	"slices"
+	"time"
+
+	"github.com/sjzsdu/utils/logging"
+)
+
+// EventType 引擎事件的类型
+type EventType string
+
+// 引擎事件的类型
+const (
+	// EventSourceRegistered 数据源已注册
+	EventSourceRegistered EventType = "source_registered"
+
+	// EventSourceUnregistered 数据源已注销
+	EventSourceUnregistered EventType = "source_unregistered"
+
+	// EventFetchStarted 开始爬取数据源，包括定时爬取、FetchItem 缓存未命中和 Trigger
+	EventFetchStarted EventType = "fetch_started"
+
+	// EventFetchSucceeded 爬取成功，Items 为解析得到的数据项数量
+	EventFetchSucceeded EventType = "fetch_succeeded"
+
+	// EventFetchFailed 爬取失败，Err 为获取或解析的错误
+	EventFetchFailed EventType = "fetch_failed"
+
+	// EventItemsPublished 爬取结果已写入缓存并通知订阅者，Items 为通知的数据项数量
+	EventItemsPublished EventType = "items_published"
+
+	// EventCacheEvicted 数据源的缓存因过期被清理或超出内存预算被淘汰，只有缓存实现了 EvictingCache 时才会发布
+	EventCacheEvicted EventType = "cache_evicted"
+)
+
+// Event 引擎事件
+type Event struct {
+	// Type 事件类型
+	Type EventType
+
+	// Source 数据源名称
+	Source string
+
+	// Time 事件发生的时间
+	Time time.Time
+
+	// Duration 爬取的耗时，只在 EventFetchSucceeded 和 EventFetchFailed 中设置
+	Duration time.Duration
+
+	// Items 数据项数量，只在 EventFetchSucceeded 和 EventItemsPublished 中设置
+	Items int
+
+	// Err 爬取的错误，只在 EventFetchFailed 中设置
+	Err error
+
+	// Expired 缓存是否因过期被清理，否则为超出内存预算被淘汰，只在 EventCacheEvicted 中设置
+	Expired bool
+}
+
+// eventSubscriber 事件订阅者，types 为空时接收所有类型的事件
+type eventSubscriber struct {
+	ch    chan<- Event
+	types []EventType
+}
+
+// wants 判断订阅者是否接收该类型的事件
+func (s eventSubscriber) wants(t EventType) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, t)
+}
+
+// SubscribeEvents 订阅引擎事件，types 为空时订阅所有类型的事件
+// 事件在引擎内部同步发布，通道已满时丢弃事件而不会阻塞爬取，订阅者需要及时处理或使用足够大的缓冲
+func (e *engineImpl) SubscribeEvents(ch chan<- Event, types ...EventType) error {
+	e.eventMu.Lock()
+	defer e.eventMu.Unlock()
+
+	e.eventSubscribers = append(e.eventSubscribers, eventSubscriber{ch: ch, types: slices.Clone(types)})
+	return nil
+}
+
+// UnsubscribeEvents 取消事件订阅
+func (e *engineImpl) UnsubscribeEvents(ch chan<- Event) error {
+	e.eventMu.Lock()
+	defer e.eventMu.Unlock()
+
+	e.eventSubscribers = slices.DeleteFunc(e.eventSubscribers, func(s eventSubscriber) bool {
+		return s.ch == ch
+	})
+	return nil
+}
+
+// publish 向订阅了该类型的订阅者发布事件，Time 为空时使用当前时间
+func (e *engineImpl) publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	e.eventMu.RLock()
+	defer e.eventMu.RUnlock()
+
+	for _, subscriber := range e.eventSubscribers {
+		if !subscriber.wants(event.Type) {
+			continue
+		}
+		select {
+		case subscriber.ch <- event:
+		default:
+			// 如果通道已满，丢弃本次事件
+			e.logger.Debug("event channel is full, dropping event", logging.KeySource, event.Source, "event", event.Type)
+		}
+	}
+}
+
+// publishFetch 发布一次爬取的结果事件
+func (e *engineImpl) publishFetch(sourceName string, start time.Time, items int, err error) {
+	event := Event{Source: sourceName, Duration: time.Since(start), Items: items}
+	if err != nil {
+		event.Type = EventFetchFailed
+		event.Items = 0
+		event.Err = err
+	} else {
+		event.Type = EventFetchSucceeded
+	}
+	e.publish(event)
+}
@@ -0,0 +1,77 @@
+package crawler
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/sjzsdu/utils/crawler/pkg/language"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// WithLanguages 只保留指定语言的数据项，languages 为BCP 47语言代码，主语言代码（如 zh）匹配其所有变体；
+// 无法确定语言的数据项同样被丢弃，被丢弃的数量记录在 SourceStatus.Filtered 中；languages 为空时不过滤
+func WithLanguages(languages ...string) EngineOption {
+	return func(e *engineImpl) {
+		e.languages = slices.Clone(languages)
+	}
+}
+
+// WithCountries 只保留指定国家或地区的数据项，countries 为ISO 3166-1代码，不区分大小写；
+// 数据项的国家或地区默认为数据源声明的信息，未知的数据项同样被丢弃；countries 为空时不过滤
+func WithCountries(countries ...string) EngineOption {
+	return func(e *engineImpl) {
+		e.countries = slices.Clone(countries)
+	}
+}
+
+// localize 为没有设置语言和地区的数据项补充信息，返回新的切片，不修改数据源返回的数据
+// 地区使用数据源声明的国家或地区，语言见 itemLanguage
+func localize(source Source, items []models.Item) []models.Item {
+	metadata := Metadata(source)
+
+	localized := slices.Clone(items)
+	for i := range localized {
+		item := &localized[i]
+		if item.Language == "" {
+			item.Language = itemLanguage(*item, metadata.Language)
+		}
+		if item.Country == "" {
+			item.Country = metadata.Country
+		}
+	}
+	return localized
+}
+
+// itemLanguage 推断数据项的语言
+// 从文本推断出拉丁字母以外的语言时使用推断结果，否则使用数据源声明的语言，
+// 因为拉丁字母被许多语言使用，中文数据源的英文标题也不应改变其语言
+func itemLanguage(item models.Item, sourceLanguage string) string {
+	detected := language.Detect(item.Title + "\n" + item.Content)
+	if detected == "" || detected == "en" {
+		return cmp.Or(sourceLanguage, detected)
+	}
+	if language.Match(sourceLanguage, detected) {
+		return sourceLanguage
+	}
+	return detected
+}
+
+// filterLocale 按 WithLanguages 和 WithCountries 过滤数据项，items 会被原地修改
+func (e *engineImpl) filterLocale(items []models.Item) []models.Item {
+	if len(e.languages) == 0 && len(e.countries) == 0 {
+		return items
+	}
+
+	return slices.DeleteFunc(items, func(item models.Item) bool {
+		if len(e.languages) > 0 && !language.MatchAny(item.Language, e.languages) {
+			return true
+		}
+		if len(e.countries) > 0 && !slices.ContainsFunc(e.countries, func(country string) bool {
+			return item.Country != "" && strings.EqualFold(strings.TrimSpace(country), item.Country)
+		}) {
+			return true
+		}
+		return false
+	})
+}
@@ -0,0 +1,132 @@
+package crawler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// DefaultClockSkew 发布时间允许超前于本机时间的范围，用于容忍数据源服务器与本机之间的时钟偏差
+const DefaultClockSkew = 5 * time.Minute
+
+// minPublishTime 早于此时间的发布时间视为解析错误，例如时间戳为0
+var minPublishTime = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// countryZones 常见国家或地区的时区，用于修正按UTC或本机时区解析的当地时间；
+// 只包含没有夏令时且全境使用同一时区的国家或地区
+var countryZones = map[string]*time.Location{
+	"CN": time.FixedZone("CST", 8*3600),
+	"HK": time.FixedZone("HKT", 8*3600),
+	"TW": time.FixedZone("CST", 8*3600),
+	"SG": time.FixedZone("SGT", 8*3600),
+	"JP": time.FixedZone("JST", 9*3600),
+	"KR": time.FixedZone("KST", 9*3600),
+}
+
+// WithClockSkew 设置发布时间允许超前于本机时间的范围，默认为 DefaultClockSkew；skew 小于0时恢复默认值
+func WithClockSkew(skew time.Duration) EngineOption {
+	return func(e *engineImpl) {
+		if skew < 0 {
+			skew = DefaultClockSkew
+		}
+		e.clockSkew = skew
+	}
+}
+
+// WithTimeNormalization 设置是否在写入缓存前规范化数据项的发布时间，默认开启，规则见 normalizePublishTime
+func WithTimeNormalization(enabled bool) EngineOption {
+	return func(e *engineImpl) {
+		e.normalizeTimes = enabled
+	}
+}
+
+// normalizeTimes 规范化数据项的发布时间，items 会被原地修改
+// start 为本次爬取开始的时间，previous 为数据源上一次缓存的数据项：
+// 发布时间为估计值的数据项在上一次的结果中出现过时沿用上一次的发布时间，避免每次爬取都被当作新发布
+func normalizeTimes(source Source, items, previous []models.Item, start time.Time, skew time.Duration) {
+	zone := countryZones[strings.ToUpper(Metadata(source).Country)]
+	now := time.Now()
+
+	seen := make(map[string]models.Item, len(previous))
+	for _, item := range previous {
+		if key := itemKey(item); key != "" {
+			seen[key] = item
+		}
+	}
+
+	for i := range items {
+		item := &items[i]
+		if item.TimeEstimated {
+			continue
+		}
+
+		published, estimated := normalizePublishTime(*item, start, now, zone, skew)
+		if estimated {
+			if prev, ok := seen[itemKey(*item)]; ok && !prev.PublishedAt.IsZero() && !prev.PublishedAt.After(published) {
+				published, estimated = prev.PublishedAt, prev.TimeEstimated
+			}
+		}
+		item.PublishedAt, item.TimeEstimated = published, estimated
+	}
+}
+
+// normalizePublishTime 返回数据项规范化后的发布时间，以及该时间是否为估计值，按以下顺序处理：
+//   - 没有发布时间或早于1990年时使用创建时间，创建时间同样不可用时使用爬取开始的时间作为估计值
+//   - 落在本次爬取期间的发布时间视为数据源以当前时间填充，标记为估计值
+//   - 超前于本机时间超过 skew 时，依次尝试按毫秒时间戳、按数据源所在时区的当地时间、按前一天重新解释，
+//     都不合理时截断为当前时间并标记为估计值；按前一天解释的时间同样是估计值
+func normalizePublishTime(item models.Item, start, now time.Time, zone *time.Location, skew time.Duration) (time.Time, bool) {
+	limit := now.Add(skew)
+	valid := func(t time.Time) bool {
+		return !t.Before(minPublishTime) && !t.After(limit)
+	}
+
+	t := item.PublishedAt
+	if t.IsZero() || t.Before(minPublishTime) {
+		if valid(item.CreatedAt) && item.CreatedAt.Before(start) {
+			return item.CreatedAt, false
+		}
+		return start, true
+	}
+	if !t.Before(start) && !t.After(now) {
+		return t, true
+	}
+	if !t.After(limit) {
+		return t, false
+	}
+
+	// 毫秒时间戳被当作秒解析，年份会远超当前
+	if t.Year() > 3000 {
+		if ms := time.UnixMilli(t.Unix()); valid(ms) {
+			return ms, false
+		}
+	}
+	// 数据源所在时区的当地时间被当作UTC或本机时区的时间解析
+	if zone != nil {
+		local := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), zone)
+		if local.Before(t) && valid(local) {
+			return local, false
+		}
+	}
+	// 只提供时间不提供日期的数据源在跨零点时把前一天的时间当作今天
+	if t.Sub(now) <= 24*time.Hour {
+		if yesterday := t.AddDate(0, 0, -1); valid(yesterday) {
+			return yesterday, true
+		}
+	}
+	return now, true
+}
+
+// itemKey 返回在同一数据源中识别数据项的键，依次使用ID、URL和标题
+func itemKey(item models.Item) string {
+	switch {
+	case item.ID != "":
+		return "id:" + item.ID
+	case item.URL != "":
+		return "url:" + item.URL
+	case item.Title != "":
+		return "title:" + item.Title
+	}
+	return ""
+}
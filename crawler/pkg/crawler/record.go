@@ -0,0 +1,136 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// recordingExt 录制文件的扩展名
+const recordingExt = ".raw"
+
+// recordingLayout 录制文件名使用的时间格式，按文件名排序即为录制的先后顺序
+const recordingLayout = "20060102T150405.000000000"
+
+// RecordingSource 开发模式下使用的数据源包装，将每次 Fetch 成功返回的原始内容保存到磁盘，
+// 文件路径为 <dir>/<数据源名称>/<时间戳>.raw，供 ReplaySource 和 ParseRecording 离线开发和测试 Parse 逻辑
+type RecordingSource struct {
+	Source
+	dir string
+}
+
+// NewRecordingSource 创建将source的原始响应录制到dir的数据源
+func NewRecordingSource(source Source, dir string) *RecordingSource {
+	return &RecordingSource{Source: source, dir: dir}
+}
+
+// Fetch 获取数据源内容并保存到录制目录，保存失败时返回错误，避免开发时误以为已经录制
+func (s *RecordingSource) Fetch(ctx context.Context) ([]byte, error) {
+	content, err := s.Source.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(s.dir, s.GetName())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("record %s: %w", s.GetName(), err)
+	}
+	file := filepath.Join(dir, time.Now().Format(recordingLayout)+recordingExt)
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		return nil, fmt.Errorf("record %s: %w", s.GetName(), err)
+	}
+	return content, nil
+}
+
+// GetTimeout 返回被包装数据源的超时，未实现 TimeoutSource 时返回0
+func (s *RecordingSource) GetTimeout() time.Duration {
+	return sourceTimeout(s.Source)
+}
+
+// Unwrap 返回被包装的数据源
+func (s *RecordingSource) Unwrap() Source {
+	return s.Source
+}
+
+// ReplaySource 回放录制内容的数据源，Fetch 不发起网络请求，按录制的先后顺序返回原始内容，
+// 全部返回后从第一个录制重新开始；Parse 等其他方法使用被包装的数据源
+type ReplaySource struct {
+	Source
+	files []string
+
+	mu   sync.Mutex
+	next int
+}
+
+// NewReplaySource 创建回放dir中source录制内容的数据源，没有录制时返回错误
+func NewReplaySource(source Source, dir string) (*ReplaySource, error) {
+	files, err := Recordings(dir, source.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recordings for source %s in %s", source.GetName(), dir)
+	}
+	return &ReplaySource{Source: source, files: files}, nil
+}
+
+// Fetch 返回下一个录制的原始内容
+func (s *ReplaySource) Fetch(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	file := s.files[s.next]
+	s.next = (s.next + 1) % len(s.files)
+	s.mu.Unlock()
+
+	return os.ReadFile(file)
+}
+
+// GetTimeout 返回被包装数据源的超时，未实现 TimeoutSource 时返回0
+func (s *ReplaySource) GetTimeout() time.Duration {
+	return sourceTimeout(s.Source)
+}
+
+// Unwrap 返回被包装的数据源
+func (s *ReplaySource) Unwrap() Source {
+	return s.Source
+}
+
+// Recordings 返回dir中数据源name的录制文件，按录制的先后顺序排列，目录不存在时返回空列表
+func Recordings(dir, name string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, name, "*"+recordingExt))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// ParseRecording 读取录制文件并用source解析，用于编写不依赖网络的 Parse 单元测试
+func ParseRecording(source Source, file string) ([]models.Item, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	items, err := source.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", source.GetName(), err)
+	}
+	return items, nil
+}
+
+// sourceTimeout 返回数据源设置的超时，未实现 TimeoutSource 时返回0
+func sourceTimeout(source Source) time.Duration {
+	if s, ok := source.(TimeoutSource); ok {
+		return s.GetTimeout()
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+package crawler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/internal/cache"
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// echoSource 每次获取返回递增序号、解析时将内容作为标题的模拟数据源
+type echoSource struct {
+	mockSource
+	fetches int
+}
+
+func (e *echoSource) Fetch(ctx context.Context) ([]byte, error) {
+	e.fetches++
+	return []byte{byte('0' + e.fetches)}, nil
+}
+
+func (e *echoSource) Parse(content []byte) ([]models.Item, error) {
+	return []models.Item{{Title: string(content)}}, nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	memCache := cache.NewMemoryCache(1 * time.Hour)
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache, crawler.WithRecordDir(dir))
+	source := &echoSource{mockSource: mockSource{name: "echo", interval: 60}}
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+	for range 2 {
+		if _, err := engine.Trigger(context.Background(), "echo"); err != nil {
+			t.Fatalf("Failed to trigger source: %v", err)
+		}
+	}
+
+	files, err := crawler.Recordings(dir, "echo")
+	if err != nil || len(files) != 2 {
+		t.Fatalf("Expected 2 recordings, got %v, %v", files, err)
+	}
+	items, err := crawler.ParseRecording(source, files[1])
+	if err != nil || len(items) != 1 || items[0].Title != "2" {
+		t.Errorf("Expected recording 2 to parse, got %v, %v", items, err)
+	}
+
+	// 回放按录制顺序返回内容，不调用被包装数据源的 Fetch
+	replay, err := crawler.NewReplaySource(source, dir)
+	if err != nil {
+		t.Fatalf("Failed to create replay source: %v", err)
+	}
+	var titles string
+	for range 3 {
+		items, err := crawler.Run(context.Background(), replay)
+		if err != nil {
+			t.Fatalf("Failed to run replay source: %v", err)
+		}
+		titles += items[0].Title
+	}
+	if titles != "121" || source.fetches != 2 {
+		t.Errorf("Expected replayed titles 121 without fetching, got %s after %d fetches", titles, source.fetches)
+	}
+
+	if _, err := crawler.NewReplaySource(&mockSource{name: "missing"}, dir); err == nil {
+		t.Error("Expected error for source without recordings")
+	}
+}
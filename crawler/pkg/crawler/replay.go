@@ -0,0 +1,61 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// ReplayLatest 从缓存中数据源最近一次爬取的结果里取出在since之后发布的数据项，发送到ch并返回这些数据项
+// 新接入的消费者（例如新建的通知队列）可以用 ReplayLatest 补齐最近一次爬取的数据，而不必等待下一次爬取；
+// 缓存只保存最近一次爬取的结果，since 只用于筛选这一批数据，不会取回更早批次的数据，
+// 需要回放任意时间窗口时应将爬取结果另行保存到按时间索引的存储中。
+// 数据项按 PublishedAt 判断，没有发布时间时使用 CreatedAt，两者都为空的数据项视为在窗口内。
+// 回放的数据只发送给ch，不会通知其他订阅者；ch 为nil时只返回数据项，ch 已满时阻塞直到ctx结束
+func (e *engineImpl) ReplayLatest(ctx context.Context, sourceName string, since time.Time, ch chan<- []models.Item) ([]models.Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	_, exists := e.sources[sourceName]
+	e.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("source %s not found", sourceName)
+	}
+
+	cached, err := e.cache.Get(sourceName)
+	if err != nil {
+		return nil, fmt.Errorf("read cache of %s: %w", sourceName, err)
+	}
+
+	var items []models.Item
+	for _, item := range cached {
+		if t := itemTime(item); !t.IsZero() && t.Before(since) {
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	if ch != nil {
+		select {
+		case ch <- items:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return items, nil
+}
+
+// itemTime 返回数据项的发布时间，没有发布时间时使用创建时间，都为空时返回零值
+func itemTime(item models.Item) time.Time {
+	if !item.PublishedAt.IsZero() {
+		return item.PublishedAt
+	}
+	return item.CreatedAt
+}
@@ -0,0 +1,211 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// DefaultReportSampleSize 报告中每个数据源默认保留的数据项样本数量
+const DefaultReportSampleSize = 5
+
+// SourceReport 单个数据源在一次爬取中的结果
+type SourceReport struct {
+	// Name 数据源名称
+	Name string `json:"name"`
+
+	// ItemCount 解析得到的数据项数量
+	ItemCount int `json:"item_count"`
+
+	// Duration 爬取的耗时
+	Duration time.Duration `json:"duration"`
+
+	// Error 爬取的错误，成功时为空
+	Error string `json:"error,omitempty"`
+
+	// Sample 数据项样本，保留前若干个数据项
+	Sample []models.Item `json:"sample,omitempty"`
+}
+
+// Succeeded 返回数据源是否爬取成功
+func (r SourceReport) Succeeded() bool {
+	return r.Error == ""
+}
+
+// CrawlReport 一次爬取多个数据源的汇总报告
+// 部分数据源失败时报告仍然包含其余数据源的结果，Err 返回所有失败数据源的错误；
+// 使用 NewCrawlReport 创建并通过 Add 添加结果，或者直接使用 Crawl，Add 不是并发安全的
+type CrawlReport struct {
+	// Started 开始爬取的时间
+	Started time.Time `json:"started"`
+
+	// Duration 爬取的总耗时，由 Finish 设置
+	Duration time.Duration `json:"duration"`
+
+	// Sources 各数据源的结果，按添加的顺序排列
+	Sources []SourceReport `json:"sources"`
+
+	// Succeeded 成功的数据源数量
+	Succeeded int `json:"succeeded"`
+
+	// Failed 失败的数据源数量
+	Failed int `json:"failed"`
+
+	// ItemCount 所有数据源的数据项总数
+	ItemCount int `json:"item_count"`
+
+	sampleSize int
+}
+
+// ReportOption 汇总报告的配置选项
+type ReportOption func(*CrawlReport)
+
+// WithReportSampleSize 设置每个数据源保留的数据项样本数量，默认为 DefaultReportSampleSize；
+// 为0时不保留样本，小于0时保留全部数据项
+func WithReportSampleSize(n int) ReportOption {
+	return func(r *CrawlReport) {
+		r.sampleSize = n
+	}
+}
+
+// NewCrawlReport 创建开始时间为当前时间的汇总报告
+func NewCrawlReport(opts ...ReportOption) *CrawlReport {
+	r := &CrawlReport{
+		Started:    time.Now(),
+		Sources:    []SourceReport{},
+		sampleSize: DefaultReportSampleSize,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Add 添加一个数据源的爬取结果，err 不为nil时数据源计为失败
+func (r *CrawlReport) Add(name string, items []models.Item, duration time.Duration, err error) {
+	source := SourceReport{Name: name, ItemCount: len(items), Duration: duration}
+	if err != nil {
+		source.Error = err.Error()
+		r.Failed++
+	} else {
+		r.Succeeded++
+	}
+	r.ItemCount += len(items)
+
+	sample := items
+	if r.sampleSize >= 0 && len(sample) > r.sampleSize {
+		sample = sample[:r.sampleSize]
+	}
+	if len(sample) > 0 {
+		source.Sample = append([]models.Item(nil), sample...)
+	}
+
+	r.Sources = append(r.Sources, source)
+}
+
+// Finish 将总耗时设置为从开始到现在经过的时间
+func (r *CrawlReport) Finish() {
+	r.Duration = time.Since(r.Started)
+}
+
+// Err 返回所有失败数据源的错误合并后的结果（errors.Join），全部成功时返回nil
+func (r *CrawlReport) Err() error {
+	var errs []error
+	for _, source := range r.Sources {
+		if !source.Succeeded() {
+			errs = append(errs, fmt.Errorf("%s: %s", source.Name, source.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriteJSON 以缩进的JSON格式输出报告
+func (r *CrawlReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteText 以文本格式输出报告，每个数据源一行，样本列在数据源下方，最后是汇总信息
+func (r *CrawlReport) WriteText(w io.Writer) error {
+	separator := strings.Repeat("=", 60)
+
+	var b strings.Builder
+	fmt.Fprintln(&b, separator)
+	fmt.Fprintln(&b, "Crawl Report")
+	fmt.Fprintln(&b, separator)
+	for _, source := range r.Sources {
+		status := "✓"
+		if !source.Succeeded() {
+			status = "✗"
+		}
+		fmt.Fprintf(&b, "%s %-20s: %d items in %s", status, source.Name, source.ItemCount, source.Duration.Round(time.Millisecond))
+		if !source.Succeeded() {
+			fmt.Fprintf(&b, ", error: %s", source.Error)
+		}
+		fmt.Fprintln(&b)
+
+		for i, item := range source.Sample {
+			fmt.Fprintf(&b, "    %d. %s\n", i+1, item.Title)
+			if item.URL != "" {
+				fmt.Fprintf(&b, "       %s\n", item.URL)
+			}
+		}
+		if more := source.ItemCount - len(source.Sample); more > 0 && len(source.Sample) > 0 {
+			fmt.Fprintf(&b, "    ... and %d more\n", more)
+		}
+	}
+	fmt.Fprintln(&b, separator)
+	fmt.Fprintf(&b, "Total: %d sources, %d succeeded, %d failed\n", len(r.Sources), r.Succeeded, r.Failed)
+	fmt.Fprintf(&b, "Total items crawled: %d\n", r.ItemCount)
+	fmt.Fprintf(&b, "Duration: %s\n", r.Duration.Round(time.Millisecond))
+	fmt.Fprintln(&b, separator)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// String 返回文本格式的报告
+func (r *CrawlReport) String() string {
+	var b strings.Builder
+	r.WriteText(&b)
+	return b.String()
+}
+
+// Crawl 并发爬取每个数据源一次，返回汇总报告，报告中数据源的顺序与names相同
+// fetch 通常为引擎的 Trigger（不读取缓存）或 FetchItem（优先读取缓存）
+func Crawl(ctx context.Context, fetch func(context.Context, string) ([]models.Item, error), names []string, opts ...ReportOption) *CrawlReport {
+	report := NewCrawlReport(opts...)
+
+	type result struct {
+		items    []models.Item
+		duration time.Duration
+		err      error
+	}
+	results := make([]result, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			items, err := fetch(ctx, name)
+			results[i] = result{items: items, duration: time.Since(start), err: err}
+		}()
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		report.Add(name, results[i].items, results[i].duration, results[i].err)
+	}
+	report.Finish()
+	return report
+}
@@ -0,0 +1,61 @@
+package crawler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+func TestCrawlReport(t *testing.T) {
+	fetch := func(ctx context.Context, name string) ([]models.Item, error) {
+		if name == "broken" {
+			return nil, errors.New("failed to fetch: 403")
+		}
+		var items []models.Item
+		for i := range 3 {
+			items = append(items, models.Item{Title: fmt.Sprintf("%s item %d", name, i), URL: "https://example.com"})
+		}
+		return items, nil
+	}
+
+	report := crawler.Crawl(context.Background(), fetch, []string{"ok", "broken"}, crawler.WithReportSampleSize(2))
+
+	if report.Succeeded != 1 || report.Failed != 1 || report.ItemCount != 3 {
+		t.Errorf("Expected 1 succeeded, 1 failed and 3 items, got %d, %d and %d", report.Succeeded, report.Failed, report.ItemCount)
+	}
+	if len(report.Sources) != 2 || report.Sources[0].Name != "ok" || report.Sources[1].Name != "broken" {
+		t.Fatalf("Expected sources in the order of names, got %+v", report.Sources)
+	}
+	if len(report.Sources[0].Sample) != 2 || report.Sources[0].ItemCount != 3 {
+		t.Errorf("Expected sample of 2 out of 3 items, got %+v", report.Sources[0])
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), "broken: failed to fetch: 403") {
+		t.Errorf("Expected error of the failed source, got %v", err)
+	}
+
+	text := report.String()
+	for _, want := range []string{"✓ ok", "✗ broken", "error: failed to fetch: 403", "1. ok item 0", "... and 1 more", "Total: 2 sources, 1 succeeded, 1 failed"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected text report to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("Failed to write JSON report: %v", err)
+	}
+	var decoded crawler.CrawlReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to decode JSON report: %v", err)
+	}
+	if decoded.Failed != 1 || decoded.Sources[1].Error != "failed to fetch: 403" {
+		t.Errorf("Expected JSON report to round-trip, got %+v", decoded)
+	}
+}
@@ -0,0 +1,44 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// Run 对数据源执行一次获取和解析，返回解析得到的数据
+// 数据源不需要注册到引擎，也不经过调度器和缓存，适用于命令行的一次性爬取和测试；
+// 数据源实现了 TimeoutSource 时使用其超时作为截止时间
+func Run(ctx context.Context, source Source) ([]models.Item, error) {
+	name := source.GetName()
+
+	ctx, cancel := withSourceTimeout(ctx, source, 0)
+	defer cancel()
+
+	content, err := source.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", name, err)
+	}
+
+	items, err := source.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+
+	return items, nil
+}
+
+// withSourceTimeout 返回带有单次爬取截止时间的上下文
+// 数据源没有设置超时时使用fallback，两者都不大于0时不设置截止时间
+func withSourceTimeout(ctx context.Context, source Source, fallback time.Duration) (context.Context, context.CancelFunc) {
+	timeout := fallback
+	if s, ok := source.(TimeoutSource); ok && s.GetTimeout() > 0 {
+		timeout = s.GetTimeout()
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
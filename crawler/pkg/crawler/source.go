@@ -2,6 +2,8 @@ package crawler
 
 import (
 	"context"
+	"net/url"
+	"time"
 
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
@@ -26,3 +28,90 @@ type Source interface {
 	// GetCategories 返回数据源的分类列表
 	GetCategories() []string
 }
+
+// TimeoutSource 可以设置超时的数据源，是 Source 的可选扩展
+// 爬取引擎以 GetTimeout 的返回值作为单次爬取（Fetch 和 Parse）的截止时间，返回0时使用引擎的默认值
+type TimeoutSource interface {
+	GetTimeout() time.Duration
+}
+
+// DescribedSource 提供简介和首页的数据源，是 Source 的可选扩展，用于在界面中展示数据源
+type DescribedSource interface {
+	// GetDescription 返回数据源的简介
+	GetDescription() string
+
+	// GetHomepage 返回数据源网站的首页，GetURL 通常是接口地址，不适合直接展示
+	GetHomepage() string
+}
+
+// LocalizedSource 提供国家或地区和语言的数据源，是 Source 的可选扩展
+type LocalizedSource interface {
+	// GetCountry 返回数据源所属的国家或地区代码（ISO 3166-1，如 CN、US），未知时返回空字符串
+	GetCountry() string
+
+	// GetLanguage 返回数据源内容的语言代码（BCP 47，如 zh、en），未知时返回空字符串
+	GetLanguage() string
+}
+
+// SourceMetadata 数据源的展示信息
+type SourceMetadata struct {
+	// Name 数据源名称
+	Name string `json:"name"`
+
+	// Description 简介
+	Description string `json:"description,omitempty"`
+
+	// Homepage 网站首页
+	Homepage string `json:"homepage,omitempty"`
+
+	// Country 国家或地区代码
+	Country string `json:"country,omitempty"`
+
+	// Language 内容的语言代码
+	Language string `json:"language,omitempty"`
+
+	// Categories 分类列表
+	Categories []string `json:"categories"`
+
+	// Interval 爬取间隔（秒）
+	Interval int `json:"interval"`
+}
+
+// Metadata 返回数据源的展示信息，包装的数据源（如 RecordingSource）返回被包装数据源的信息
+// 没有实现 DescribedSource 或首页为空时，以 GetURL 的站点根地址作为首页
+func Metadata(source Source) SourceMetadata {
+	metadata := SourceMetadata{
+		Name:       source.GetName(),
+		Categories: source.GetCategories(),
+		Interval:   source.GetInterval(),
+	}
+
+	for s := source; s != nil; s = unwrapSource(s) {
+		if described, ok := s.(DescribedSource); ok {
+			metadata.Description = described.GetDescription()
+			metadata.Homepage = described.GetHomepage()
+		}
+		if localized, ok := s.(LocalizedSource); ok {
+			metadata.Country = localized.GetCountry()
+			metadata.Language = localized.GetLanguage()
+		}
+		if metadata.Description != "" || metadata.Homepage != "" || metadata.Country != "" || metadata.Language != "" {
+			break
+		}
+	}
+
+	if metadata.Homepage == "" {
+		if u, err := url.Parse(source.GetURL()); err == nil && u.Scheme != "" && u.Host != "" {
+			metadata.Homepage = u.Scheme + "://" + u.Host + "/"
+		}
+	}
+	return metadata
+}
+
+// unwrapSource 返回包装的数据源内部的数据源，不是包装的数据源时返回nil
+func unwrapSource(source Source) Source {
+	if wrapper, ok := source.(interface{ Unwrap() Source }); ok {
+		return wrapper.Unwrap()
+	}
+	return nil
+}
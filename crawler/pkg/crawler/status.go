@@ -0,0 +1,222 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/crawler/pkg/scheduler"
+)
+
+// SourceStatus 数据源的运行状态
+type SourceStatus struct {
+	// Name 数据源名称
+	Name string `json:"name"`
+
+	// Categories 数据源的分类列表
+	Categories []string `json:"categories"`
+
+	// Description 数据源的简介
+	Description string `json:"description,omitempty"`
+
+	// Homepage 数据源网站的首页
+	Homepage string `json:"homepage,omitempty"`
+
+	// Country 数据源所属的国家或地区代码
+	Country string `json:"country,omitempty"`
+
+	// Language 数据源内容的语言代码
+	Language string `json:"language,omitempty"`
+
+	// Interval 爬取间隔（秒）
+	Interval int `json:"interval"`
+
+	// Paused 是否已暂停定时爬取
+	Paused bool `json:"paused"`
+
+	// LastFetch 最近一次爬取完成的时间，从未爬取时为零值
+	LastFetch time.Time `json:"last_fetch"`
+
+	// LastDuration 最近一次爬取的耗时
+	LastDuration time.Duration `json:"last_duration"`
+
+	// LastError 最近一次爬取的错误，成功时为空
+	LastError string `json:"last_error,omitempty"`
+
+	// ItemCount 最近一次成功爬取的数据项数量
+	ItemCount int `json:"item_count"`
+
+	// Fetches 累计爬取次数
+	Fetches int `json:"fetches"`
+
+	// Failures 累计失败次数
+	Failures int `json:"failures"`
+
+	// Trimmed 累计因超出每个数据源的数量上限而丢弃的数据项数量
+	Trimmed int `json:"trimmed"`
+
+	// Filtered 累计因不属于 WithLanguages 或 WithCountries 指定的语言和地区而丢弃的数据项数量
+	Filtered int `json:"filtered"`
+}
+
+// fetchStats 引擎记录的数据源爬取统计
+type fetchStats struct {
+	lastFetch    time.Time
+	lastDuration time.Duration
+	lastError    string
+	itemCount    int
+	fetches      int
+	failures     int
+	trimmed      int
+	filtered     int
+}
+
+// Status 返回所有已注册数据源的运行状态，按名称排序
+func (e *engineImpl) Status() []SourceStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	statuses := make([]SourceStatus, 0, len(e.sources))
+	for name, source := range e.sources {
+		metadata := Metadata(source)
+		status := SourceStatus{
+			Name:        name,
+			Categories:  metadata.Categories,
+			Description: metadata.Description,
+			Homepage:    metadata.Homepage,
+			Country:     metadata.Country,
+			Language:    metadata.Language,
+			Interval:    metadata.Interval,
+			Paused:      e.paused[name],
+		}
+		if stats, ok := e.stats[name]; ok {
+			status.LastFetch = stats.lastFetch
+			status.LastDuration = stats.lastDuration
+			status.LastError = stats.lastError
+			status.ItemCount = stats.itemCount
+			status.Fetches = stats.fetches
+			status.Failures = stats.failures
+			status.Trimmed = stats.trimmed
+			status.Filtered = stats.filtered
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// Trigger 立即爬取指定数据源，不读取缓存，结果会更新缓存并通知订阅者
+func (e *engineImpl) Trigger(ctx context.Context, sourceName string) ([]models.Item, error) {
+	e.mu.RLock()
+	source, exists := e.sources[sourceName]
+	e.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("source %s not found", sourceName)
+	}
+
+	ctx, cancel := withSourceTimeout(ctx, source, e.fetchTimeout)
+	defer cancel()
+
+	e.publish(Event{Type: EventFetchStarted, Source: sourceName})
+	start := time.Now()
+	items, err := Run(ctx, source)
+	e.recordFetch(sourceName, start, items, err)
+	if err != nil {
+		return nil, err
+	}
+
+	items = e.store(source, items, start)
+	e.notifySubscribers(sourceName, items)
+
+	return items, nil
+}
+
+// Pause 暂停数据源的定时爬取，FetchItem 和 Trigger 不受影响
+func (e *engineImpl) Pause(sourceName string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.sources[sourceName]; !exists {
+		return fmt.Errorf("source %s not found", sourceName)
+	}
+	if e.paused[sourceName] {
+		return nil
+	}
+	e.paused[sourceName] = true
+
+	if e.running {
+		if err := e.scheduler.RemoveTask(sourceName); err != nil && !errors.Is(err, scheduler.ErrTaskNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resume 恢复数据源的定时爬取
+func (e *engineImpl) Resume(sourceName string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	source, exists := e.sources[sourceName]
+	if !exists {
+		return fmt.Errorf("source %s not found", sourceName)
+	}
+	if !e.paused[sourceName] {
+		return nil
+	}
+	delete(e.paused, sourceName)
+
+	if e.running {
+		return e.scheduler.AddTask(&crawlTask{source: source, engine: e})
+	}
+	return nil
+}
+
+// recordFetch 记录一次爬取的结果并发布 EventFetchSucceeded 或 EventFetchFailed 事件
+func (e *engineImpl) recordFetch(sourceName string, start time.Time, items []models.Item, err error) {
+	e.publishFetch(sourceName, start, len(items), err)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// 爬取期间数据源被注销时不再记录
+	if _, exists := e.sources[sourceName]; !exists {
+		return
+	}
+
+	stats, ok := e.stats[sourceName]
+	if !ok {
+		stats = &fetchStats{}
+		e.stats[sourceName] = stats
+	}
+
+	now := time.Now()
+	stats.lastFetch = now
+	stats.lastDuration = now.Sub(start)
+	stats.fetches++
+	if err != nil {
+		stats.lastError = err.Error()
+		stats.failures++
+		return
+	}
+	stats.lastError = ""
+	stats.itemCount = len(items)
+}
+
+// recordStore 记录写入缓存前被过滤和截断的数据项数量
+func (e *engineImpl) recordStore(sourceName string, filtered, trimmed int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if stats, ok := e.stats[sourceName]; ok {
+		stats.filtered += filtered
+		stats.trimmed += trimmed
+	}
+}
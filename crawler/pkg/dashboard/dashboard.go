@@ -0,0 +1,313 @@
+// Package dashboard 提供爬取引擎的网页控制台，展示数据源状态和最近的数据，并可以立即爬取或暂停数据源
+package dashboard
+
+import (
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/logging"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// DefaultRecentItems 每个数据源默认保留的最近数据项数量
+const DefaultRecentItems = 20
+
+// Dashboard 爬取引擎的网页控制台
+// 页面和JSON接口的路由如下：
+//
+//	GET  /                             控制台页面，?source=<name> 查看数据源最近的数据
+//	GET  /api/sources                  所有数据源的运行状态
+//	GET  /api/sources/{name}/items     数据源最近的数据
+//	POST /api/sources/{name}/trigger   立即爬取数据源
+//	POST /api/sources/{name}/pause     暂停数据源的定时爬取
+//	POST /api/sources/{name}/resume    恢复数据源的定时爬取
+//
+// POST 接口需要通过 WithToken 设置访问令牌后才会开启，请求需携带 Authorization: Bearer <token>；
+// 所有接口都拒绝跨站的非安全请求，避免用户访问的其他网页代为提交
+type Dashboard struct {
+	engine    crawler.ControllableEngine
+	templates *template.Template
+
+	mu     sync.RWMutex
+	recent map[string][]models.Item
+	subs   map[string]chan []models.Item
+	done   chan struct{}
+	closed bool
+
+	basePath    string
+	recentLimit int
+	logger      logging.Logger
+
+	token string // 控制接口的访问令牌，为空时不开启控制接口
+}
+
+// Option 配置Dashboard的选项
+type Option func(*Dashboard)
+
+// WithBasePath 设置控制台挂载的路径前缀（如 /admin），用于生成页面中的链接
+func WithBasePath(basePath string) Option {
+	return func(d *Dashboard) {
+		d.basePath = strings.TrimSuffix(basePath, "/")
+	}
+}
+
+// WithRecentItems 设置每个数据源保留的最近数据项数量
+func WithRecentItems(n int) Option {
+	return func(d *Dashboard) {
+		d.recentLimit = n
+	}
+}
+
+// WithToken 设置立即爬取、暂停和恢复接口的访问令牌，未设置时这些接口返回403，控制台只能查看
+func WithToken(token string) Option {
+	return func(d *Dashboard) {
+		d.token = token
+	}
+}
+
+// WithLogger 设置日志记录器，默认不输出日志
+func WithLogger(logger logging.Logger) Option {
+	return func(d *Dashboard) {
+		d.logger = logging.OrNop(logger)
+	}
+}
+
+// pageData 控制台页面的模板数据
+type pageData struct {
+	BasePath string
+	Sources  []crawler.SourceStatus
+	Selected string
+	Items    []models.Item
+	Controls bool // 是否开启了控制接口，未开启时不显示操作按钮
+}
+
+// New 创建控制台，并订阅引擎中已注册的数据源以记录最近的数据
+// 创建后注册的数据源只展示运行状态，不再需要时调用 Close 取消订阅
+func New(engine crawler.ControllableEngine, opts ...Option) (*Dashboard, error) {
+	d := &Dashboard{
+		engine:      engine,
+		recent:      make(map[string][]models.Item),
+		subs:        make(map[string]chan []models.Item),
+		done:        make(chan struct{}),
+		recentLimit: DefaultRecentItems,
+		logger:      logging.Nop(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	templates, err := template.New("dashboard").Funcs(template.FuncMap{
+		"formatTime": formatTime,
+		"formatDuration": func(d time.Duration) string {
+			return d.Round(time.Millisecond).String()
+		},
+	}).ParseFS(templateFS, "templates/dashboard.html")
+	if err != nil {
+		return nil, fmt.Errorf("解析dashboard模板失败: %w", err)
+	}
+	d.templates = templates
+
+	for _, status := range engine.Status() {
+		name := status.Name
+		ch := make(chan []models.Item, 1)
+		if err := engine.Subscribe(name, ch); err != nil {
+			d.Close()
+			return nil, err
+		}
+		d.subs[name] = ch
+		go d.collect(name, ch)
+	}
+
+	return d, nil
+}
+
+// Close 取消对数据源的订阅
+func (d *Dashboard) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return
+	}
+	d.closed = true
+	close(d.done)
+
+	for name, ch := range d.subs {
+		d.engine.Unsubscribe(name, ch)
+	}
+	d.subs = nil
+}
+
+// collect 记录数据源最近的数据
+func (d *Dashboard) collect(name string, ch <-chan []models.Item) {
+	for {
+		select {
+		case <-d.done:
+			return
+		case items := <-ch:
+			if len(items) > d.recentLimit {
+				items = items[:d.recentLimit]
+			}
+			d.mu.Lock()
+			d.recent[name] = items
+			d.mu.Unlock()
+		}
+	}
+}
+
+// Recent 返回数据源最近一次爬取到的数据
+func (d *Dashboard) Recent(name string) []models.Item {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]models.Item(nil), d.recent[name]...)
+}
+
+// Handler 返回控制台的HTTP处理器
+func (d *Dashboard) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", d.handleIndex)
+	mux.HandleFunc("GET /api/sources", d.handleSources)
+	mux.HandleFunc("GET /api/sources/{name}/items", d.handleItems)
+	mux.HandleFunc("POST /api/sources/{name}/trigger", d.authorize(d.handleTrigger))
+	mux.HandleFunc("POST /api/sources/{name}/pause", d.authorize(d.handlePause))
+	mux.HandleFunc("POST /api/sources/{name}/resume", d.authorize(d.handleResume))
+	return http.NewCrossOriginProtection().Handler(mux)
+}
+
+// authorize 检查控制接口的访问令牌，未设置令牌时拒绝所有请求
+func (d *Dashboard) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if d.token == "" {
+			writeError(w, http.StatusForbidden, fmt.Errorf("control API is disabled, set a token with WithToken"))
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(d.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dashboard"`)
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("invalid or missing token"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleIndex 渲染控制台页面
+func (d *Dashboard) handleIndex(w http.ResponseWriter, r *http.Request) {
+	data := pageData{
+		BasePath: d.basePath,
+		Sources:  d.engine.Status(),
+		Selected: r.URL.Query().Get("source"),
+		Controls: d.token != "",
+	}
+	if data.Selected != "" {
+		data.Items = d.Recent(data.Selected)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := d.templates.ExecuteTemplate(w, "dashboard.html", data); err != nil {
+		d.logger.Error("failed to render dashboard", logging.KeyError, err)
+	}
+}
+
+// handleSources 返回所有数据源的运行状态
+func (d *Dashboard) handleSources(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, d.engine.Status())
+}
+
+// handleItems 返回数据源最近的数据
+func (d *Dashboard) handleItems(w http.ResponseWriter, r *http.Request) {
+	name, ok := d.sourceName(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, d.Recent(name))
+}
+
+// handleTrigger 立即爬取数据源
+func (d *Dashboard) handleTrigger(w http.ResponseWriter, r *http.Request) {
+	name, ok := d.sourceName(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := d.engine.Trigger(r.Context(), name)
+	if err != nil {
+		d.logger.Warn("failed to trigger source", logging.KeySource, name, logging.KeyError, err)
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"source": name, "items": len(items)})
+}
+
+// handlePause 暂停数据源的定时爬取
+func (d *Dashboard) handlePause(w http.ResponseWriter, r *http.Request) {
+	d.control(w, r, d.engine.Pause)
+}
+
+// handleResume 恢复数据源的定时爬取
+func (d *Dashboard) handleResume(w http.ResponseWriter, r *http.Request) {
+	d.control(w, r, d.engine.Resume)
+}
+
+// control 对数据源执行暂停或恢复操作，返回操作后的运行状态
+func (d *Dashboard) control(w http.ResponseWriter, r *http.Request, action func(string) error) {
+	name, ok := d.sourceName(w, r)
+	if !ok {
+		return
+	}
+	if err := action(name); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, status := range d.engine.Status() {
+		if status.Name == name {
+			writeJSON(w, http.StatusOK, status)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("source %s not found", name))
+}
+
+// sourceName 返回请求路径中的数据源名称，数据源未注册时返回404
+func (d *Dashboard) sourceName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.PathValue("name")
+	for _, status := range d.engine.Status() {
+		if status.Name == name {
+			return name, true
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("source %s not found", name))
+	return "", false
+}
+
+// writeJSON 以JSON格式写入响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 以JSON格式写入错误响应
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// formatTime 格式化时间，零值显示为 -
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
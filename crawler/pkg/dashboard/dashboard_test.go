@@ -0,0 +1,156 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// mockSource 是一个用于测试的模拟数据源
+type mockSource struct {
+	name  string
+	items []models.Item
+}
+
+func (m *mockSource) GetName() string                           { return m.name }
+func (m *mockSource) GetURL() string                            { return "" }
+func (m *mockSource) Fetch(ctx context.Context) ([]byte, error) { return nil, nil }
+func (m *mockSource) Parse(content []byte) ([]models.Item, error) {
+	return m.items, nil
+}
+func (m *mockSource) GetInterval() int        { return 60 }
+func (m *mockSource) GetCategories() []string { return []string{"科技"} }
+
+func TestDashboard(t *testing.T) {
+	memCache := crawler.NewMemoryCache(time.Hour)
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache)
+	source := &mockSource{name: "github", items: []models.Item{{ID: "1", Title: "Go 1.24"}}}
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New(engine, WithToken("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	server := httptest.NewServer(d.Handler())
+	defer server.Close()
+
+	request := func(method, path string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, server.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, _ := request(http.MethodPost, "/api/sources/github/trigger")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected trigger to succeed, got %d", resp.StatusCode)
+	}
+
+	// 最近的数据通过订阅异步记录
+	deadline := time.Now().Add(2 * time.Second)
+	for len(d.Recent("github")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	var items []models.Item
+	_, body := request(http.MethodGet, "/api/sources/github/items")
+	if err := json.Unmarshal([]byte(body), &items); err != nil || len(items) != 1 {
+		t.Fatalf("expected 1 recent item, got %s", body)
+	}
+
+	var status crawler.SourceStatus
+	_, body = request(http.MethodPost, "/api/sources/github/pause")
+	if err := json.Unmarshal([]byte(body), &status); err != nil || !status.Paused || status.Fetches != 1 {
+		t.Errorf("expected paused source with 1 fetch, got %s", body)
+	}
+
+	resp, body = request(http.MethodGet, "/?source=github")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "Go 1.24") || !strings.Contains(body, "已暂停") {
+		t.Errorf("expected page with recent items and paused status, got %d", resp.StatusCode)
+	}
+
+	if resp, _ := request(http.MethodPost, "/api/sources/unknown/trigger"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown source, got %d", resp.StatusCode)
+	}
+}
+
+func TestDashboardAuthorization(t *testing.T) {
+	memCache := crawler.NewMemoryCache(time.Hour)
+	defer memCache.Close()
+
+	engine := crawler.NewEngine(memCache)
+	if err := engine.RegisterSource(&mockSource{name: "github"}); err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(d *Dashboard, token string, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/sources/github/pause", nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		d.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	// 没有设置令牌时控制接口关闭，页面不显示操作按钮
+	readOnly, err := New(engine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer readOnly.Close()
+	if w := request(readOnly, "secret", nil); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without configured token, got %d", w.Code)
+	}
+	page := httptest.NewRecorder()
+	readOnly.Handler().ServeHTTP(page, httptest.NewRequest(http.MethodGet, "/", nil))
+	if page.Code != http.StatusOK || strings.Contains(page.Body.String(), `data-action="pause"`) {
+		t.Errorf("expected read-only page without control buttons, got %d", page.Code)
+	}
+
+	d, err := New(engine, WithToken("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	for _, token := range []string{"", "wrong"} {
+		if w := request(d, token, nil); w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401 for token %q, got %d", token, w.Code)
+		}
+	}
+	crossSite := http.Header{"Sec-Fetch-Site": {"cross-site"}, "Origin": {"https://evil.example"}}
+	if w := request(d, "secret", crossSite); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for cross-site request, got %d", w.Code)
+	}
+	for _, status := range engine.Status() {
+		if status.Paused {
+			t.Errorf("expected rejected requests not to pause %s", status.Name)
+		}
+	}
+	if w := request(d, "secret", http.Header{"Sec-Fetch-Site": {"same-origin"}}); w.Code != http.StatusOK {
+		t.Errorf("expected authorized same-origin request to succeed, got %d", w.Code)
+	}
+}
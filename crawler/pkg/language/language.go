@@ -0,0 +1,93 @@
+// Package language 根据文本使用的书写系统推断语言，并按BCP 47语言代码和地区代码匹配数据项
+//
+// 推断只能区分使用不同书写系统的语言：汉字为 zh，含假名为 ja，谚文为 ko，西里尔字母为 ru，
+// 阿拉伯字母为 ar，泰文为 th，拉丁字母为 en。拉丁字母被许多语言使用，
+// 因此 Detect 的结果为 en 时应优先使用数据源声明的语言。
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// detectLimit 推断语言时最多检查的字符数
+const detectLimit = 500
+
+// latinWeight 拉丁字母按单词计数，约每4个字母相当于一个汉字
+const latinWeight = 4
+
+// scripts 参与推断的书写系统及其对应的语言代码，假名单独处理
+var scripts = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Han, "zh"},
+	{unicode.Hangul, "ko"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Thai, "th"},
+	{unicode.Latin, "en"},
+}
+
+// Detect 推断文本的语言代码，没有可识别的文字时返回空字符串
+// 汉字中夹杂的英文单词不影响结果，含有一定比例假名的文本视为日文
+func Detect(text string) string {
+	counts := make([]int, len(scripts))
+	kana := 0
+	checked := 0
+	for _, r := range text {
+		if checked++; checked > detectLimit {
+			break
+		}
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			kana++
+			continue
+		}
+		for i, script := range scripts {
+			if unicode.Is(script.table, r) {
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	// 日文通常混用汉字和假名，假名不少于汉字的十分之一时视为日文
+	if han := counts[0]; kana > 0 && kana*10 >= han {
+		return "ja"
+	}
+
+	best, bestScore := "", 0
+	for i, script := range scripts {
+		score := counts[i] * latinWeight
+		if script.table == unicode.Latin {
+			score = counts[i]
+		}
+		if score > bestScore {
+			best, bestScore = script.language, score
+		}
+	}
+	return best
+}
+
+// Match 判断语言代码lang是否属于want，比较时不区分大小写，want 为主语言代码时匹配其所有变体，
+// 例如 zh 匹配 zh-CN 和 zh-Hant，zh-CN 只匹配 zh-CN
+func Match(lang, want string) bool {
+	lang, want = strings.TrimSpace(lang), strings.TrimSpace(want)
+	if lang == "" || want == "" {
+		return false
+	}
+	if strings.EqualFold(lang, want) {
+		return true
+	}
+	return len(lang) > len(want) && lang[len(want)] == '-' && strings.EqualFold(lang[:len(want)], want)
+}
+
+// MatchAny 判断语言代码lang是否属于wants中的任意一个
+func MatchAny(lang string, wants []string) bool {
+	for _, want := range wants {
+		if Match(lang, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		"央行宣布下调存款准备金率":                  "zh",
+		"OpenAI发布GPT-5模型":               "zh",
+		"東京で新しいサービスが始まりました":             "ja",
+		"삼성전자 신제품 발표":                   "ko",
+		"Новости экономики":             "ru",
+		"Show HN: A tiny Go web server": "en",
+		"2024-01-01 12:00":              "",
+	}
+	for text, expected := range cases {
+		assert.Equal(t, expected, Detect(text), text)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	assert.True(t, Match("zh-CN", "zh"), "主语言代码应匹配所有变体")
+	assert.True(t, Match("ZH", "zh"), "比较时不区分大小写")
+	assert.False(t, Match("zh", "zh-CN"))
+	assert.False(t, Match("zhx", "zh"))
+	assert.False(t, Match("", "zh"), "未知语言不匹配任何语言")
+	assert.True(t, MatchAny("en-US", []string{"zh", "en"}))
+}
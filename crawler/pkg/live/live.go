@@ -0,0 +1,325 @@
+// Package live 通过 Server-Sent Events 和 WebSocket 实时推送爬取引擎新获取的数据
+package live
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/crawler/pkg/taxonomy"
+	"github.com/sjzsdu/utils/logging"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// DefaultBufferSize 每个客户端默认缓冲的数据项数量，客户端读取过慢时丢弃新数据
+	DefaultBufferSize = 64
+
+	// DefaultHeartbeat SSE连接默认的心跳间隔，避免空闲连接被代理断开
+	DefaultHeartbeat = 30 * time.Second
+)
+
+// Hub 将爬取引擎的数据分发给通过HTTP连接的客户端
+// 客户端可以通过 source 和 category 查询参数（逗号分隔或重复出现）只接收指定数据源或分类的数据，
+// 每个数据项作为一条消息推送：SSE的事件类型为 item，WebSocket为一条JSON文本消息
+type Hub struct {
+	mu         sync.RWMutex
+	clients    map[*client]struct{}
+	categories map[string][]string
+	unsubs     []func()
+	done       chan struct{}
+	closed     bool
+
+	logger     logging.Logger
+	bufferSize int
+	heartbeat  time.Duration
+}
+
+// Option 配置Hub的选项
+type Option func(*Hub)
+
+// WithLogger 设置日志记录器，默认不输出日志
+func WithLogger(logger logging.Logger) Option {
+	return func(h *Hub) {
+		h.logger = logging.OrNop(logger)
+	}
+}
+
+// WithBufferSize 设置每个客户端缓冲的数据项数量
+func WithBufferSize(size int) Option {
+	return func(h *Hub) {
+		h.bufferSize = size
+	}
+}
+
+// WithHeartbeat 设置SSE连接的心跳间隔，不大于0时不发送心跳
+func WithHeartbeat(interval time.Duration) Option {
+	return func(h *Hub) {
+		h.heartbeat = interval
+	}
+}
+
+// client 一个已连接的客户端及其过滤条件
+type client struct {
+	sources    []string
+	categories []string
+	ch         chan models.Item
+}
+
+// NewHub 创建Hub实例
+func NewHub(opts ...Option) *Hub {
+	h := &Hub{
+		clients:    make(map[*client]struct{}),
+		categories: make(map[string][]string),
+		done:       make(chan struct{}),
+		logger:     logging.Nop(),
+		bufferSize: DefaultBufferSize,
+		heartbeat:  DefaultHeartbeat,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Attach 订阅引擎中数据源的更新并推送给客户端，数据源的分类用于按 category 过滤
+func (h *Hub) Attach(engine crawler.Engine, sources ...crawler.Source) error {
+	for _, source := range sources {
+		name := source.GetName()
+		ch := make(chan []models.Item, 1)
+		if err := engine.Subscribe(name, ch); err != nil {
+			return fmt.Errorf("subscribe %s: %w", name, err)
+		}
+
+		h.mu.Lock()
+		h.categories[name] = source.GetCategories()
+		h.unsubs = append(h.unsubs, func() {
+			engine.Unsubscribe(name, ch)
+		})
+		h.mu.Unlock()
+
+		go func() {
+			for {
+				select {
+				case <-h.done:
+					return
+				case items := <-ch:
+					h.Publish(name, items)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// Publish 将数据源的数据推送给订阅了该数据源或分类的客户端
+func (h *Hub) Publish(source string, items []models.Item) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	categories := h.categories[source]
+	for c := range h.clients {
+		if !c.matchSource(source) {
+			continue
+		}
+		for _, item := range items {
+			if !c.matchCategory(categories, item) {
+				continue
+			}
+			select {
+			case c.ch <- item:
+			default:
+				h.logger.Warn("live client is too slow, dropping item", logging.KeySource, source)
+			}
+		}
+	}
+}
+
+// Close 取消所有订阅并断开所有客户端
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+	close(h.done)
+
+	for _, unsubscribe := range h.unsubs {
+		unsubscribe()
+	}
+	h.unsubs = nil
+
+	for c := range h.clients {
+		close(c.ch)
+		delete(h.clients, c)
+	}
+}
+
+// Handler 返回HTTP处理器：/events 为SSE，/ws 为WebSocket
+func (h *Hub) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", h.ServeSSE)
+	mux.HandleFunc("/ws", h.ServeWebSocket)
+	return mux
+}
+
+// ServeSSE 以 Server-Sent Events 推送数据
+func (h *Hub) ServeSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	c, ok := h.register(r)
+	if !ok {
+		http.Error(w, "hub is closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.unregister(c)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var heartbeat <-chan time.Time
+	if h.heartbeat > 0 {
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat:
+			fmt.Fprint(w, ": ping\n\n")
+		case item, ok := <-c.ch:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(item)
+			if err != nil {
+				h.logger.Error("failed to encode item", logging.KeySource, item.Source, logging.KeyError, err)
+				continue
+			}
+			fmt.Fprintf(w, "event: item\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
+
+// ServeWebSocket 以WebSocket推送数据，客户端发送的消息会被忽略
+// 为方便不同来源的看板接入，不校验请求的 Origin
+func (h *Hub) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.register(r)
+	if !ok {
+		http.Error(w, "hub is closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.unregister(c)
+
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		// 读取并丢弃客户端消息，以便及时发现连接断开
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var msg string
+			for websocket.Message.Receive(ws, &msg) == nil {
+			}
+		}()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case item, ok := <-c.ch:
+				if !ok {
+					return
+				}
+				if err := websocket.JSON.Send(ws, item); err != nil {
+					h.logger.Debug("live websocket closed", logging.KeyError, err)
+					return
+				}
+			}
+		}
+	}}
+	server.ServeHTTP(w, r)
+}
+
+// register 按请求的查询参数注册客户端，Hub已关闭时返回false
+func (h *Hub) register(r *http.Request) (*client, bool) {
+	query := r.URL.Query()
+	c := &client{
+		sources:    splitParam(query["source"]),
+		categories: splitParam(query["category"]),
+		ch:         make(chan models.Item, h.bufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, false
+	}
+	h.clients[c] = struct{}{}
+	return c, true
+}
+
+// unregister 移除断开的客户端
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c)
+}
+
+// matchSource 判断客户端是否订阅了数据源
+func (c *client) matchSource(source string) bool {
+	return len(c.sources) == 0 || slices.Contains(c.sources, source)
+}
+
+// matchCategory 判断数据项是否属于客户端订阅的分类，数据源的分类和数据项自身的分类都会参与匹配
+func (c *client) matchCategory(categories []string, item models.Item) bool {
+	if len(c.categories) == 0 {
+		return true
+	}
+	if item.Category != "" && c.hasCategory(item.Category) {
+		return true
+	}
+	for _, category := range categories {
+		if c.hasCategory(category) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCategory 判断客户端是否订阅了分类，分类的规范ID、显示名称和别名视为同一个分类
+func (c *client) hasCategory(category string) bool {
+	return slices.ContainsFunc(c.categories, func(subscribed string) bool {
+		return taxonomy.Match(subscribed, category)
+	})
+}
+
+// splitParam 拆分查询参数中逗号分隔的值，忽略空值
+func splitParam(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
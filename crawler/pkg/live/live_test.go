@@ -0,0 +1,138 @@
+package live
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"golang.org/x/net/websocket"
+)
+
+// mockSource 是一个用于测试的模拟数据源
+type mockSource struct {
+	name       string
+	categories []string
+	items      []models.Item
+}
+
+func (m *mockSource) GetName() string                           { return m.name }
+func (m *mockSource) GetURL() string                            { return "" }
+func (m *mockSource) Fetch(ctx context.Context) ([]byte, error) { return nil, nil }
+func (m *mockSource) Parse(content []byte) ([]models.Item, error) {
+	return m.items, nil
+}
+func (m *mockSource) GetInterval() int        { return 60 }
+func (m *mockSource) GetCategories() []string { return m.categories }
+
+// waitClients 等待指定数量的客户端连接
+func waitClients(t *testing.T, h *Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		h.mu.RLock()
+		count := len(h.clients)
+		h.mu.RUnlock()
+		if count == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d clients", n)
+}
+
+func TestServeSSE(t *testing.T) {
+	h := NewHub()
+	defer h.Close()
+	h.categories["github"] = []string{"科技"}
+
+	server := httptest.NewServer(h.Handler())
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/events?category=科技")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	waitClients(t, h, 1)
+
+	// 只推送订阅分类的数据
+	h.Publish("weibo", []models.Item{{ID: "1", Source: "weibo"}})
+	h.Publish("github", []models.Item{{ID: "2", Source: "github"}})
+
+	reader := bufio.NewReader(resp.Body)
+	var event, data string
+	for data == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, ok := strings.CutPrefix(line, "event: "); ok {
+			event = strings.TrimSpace(v)
+		}
+		if v, ok := strings.CutPrefix(line, "data: "); ok {
+			data = strings.TrimSpace(v)
+		}
+	}
+
+	var item models.Item
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatal(err)
+	}
+	if event != "item" || item.ID != "2" {
+		t.Errorf("unexpected event %q with item %q", event, item.ID)
+	}
+}
+
+func TestServeWebSocket(t *testing.T) {
+	source := &mockSource{
+		name:       "github",
+		categories: []string{"科技"},
+		items:      []models.Item{{ID: "1", Title: "Go", Source: "github"}},
+	}
+	engine := crawler.NewEngine(crawler.NewMemoryCache(time.Minute))
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHub()
+	if err := h.Attach(engine, source); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(h.Handler())
+	defer server.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?source=github", "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	waitClients(t, h, 1)
+
+	// 引擎获取的数据推送给客户端
+	if _, err := engine.FetchItem(context.Background(), "github"); err != nil {
+		t.Fatal(err)
+	}
+	var item models.Item
+	if err := websocket.JSON.Receive(ws, &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Title != "Go" {
+		t.Errorf("expected item Go, got %q", item.Title)
+	}
+
+	// 关闭后断开客户端
+	h.Close()
+	if err := websocket.JSON.Receive(ws, &item); err == nil {
+		t.Error("expected connection to be closed")
+	}
+}
@@ -27,9 +27,33 @@ type Item struct {
 	// Images 图片链接列表
 	Images []string `json:"images"`
 
+	// Author 作者
+	Author string `json:"author"`
+
+	// Tags 标签列表
+	Tags []string `json:"tags"`
+
+	// Score 热度、点赞数等排序依据，数值越大越靠前，0 表示数据源未提供
+	Score float64 `json:"score"`
+
+	// ImageURL 封面或图标链接
+	ImageURL string `json:"image_url"`
+
+	// Extra 数据源特有的附加信息，例如回复数、浏览量
+	Extra map[string]any `json:"extra"`
+
+	// Language 内容的语言代码（BCP 47，如 zh、en），数据源没有设置时由引擎根据文本和数据源信息推断
+	Language string `json:"language,omitempty"`
+
+	// Country 数据项所属的国家或地区代码（ISO 3166-1，如 CN），数据源没有设置时由引擎使用数据源的信息
+	Country string `json:"country,omitempty"`
+
 	// PublishedAt 发布时间
 	PublishedAt time.Time `json:"published_at"`
 
+	// TimeEstimated PublishedAt 是否为估计值，例如数据源没有提供发布时间时使用的首次爬取时间
+	TimeEstimated bool `json:"time_estimated,omitempty"`
+
 	// CreatedAt 创建时间
 	CreatedAt time.Time `json:"created_at"`
 
@@ -3,9 +3,10 @@ package scheduler
 import (
 	"context"
 	"errors"
-	"fmt"
 	"sync"
 	"time"
+
+	"github.com/sjzsdu/utils/logging"
 )
 
 var (
@@ -37,8 +38,16 @@ type inMemoryScheduler struct {
 	// taskCtxs stores the context and cancel function for each task
 	taskCtxs map[string]context.CancelFunc
 
-	// mu 保护 tasks 和 taskCtxs 的并发访问
-	// mu protects concurrent access to tasks and taskCtxs
+	// onceTasks 存储尚未执行的一次性任务
+	// onceTasks stores pending one-shot tasks
+	onceTasks map[string]*onceTask
+
+	// onceCtxs 存储每个一次性任务的取消函数
+	// onceCtxs stores the cancel function for each one-shot task
+	onceCtxs map[string]context.CancelFunc
+
+	// mu 保护任务和上下文的并发访问
+	// mu protects concurrent access to tasks and contexts
 	mu sync.RWMutex
 
 	// ctx 调度器的上下文
@@ -56,19 +65,73 @@ type inMemoryScheduler struct {
 	// running 表示调度器是否正在运行
 	// running indicates whether the scheduler is running
 	running bool
+
+	// logger 日志记录器
+	// logger is the logger
+	logger logging.Logger
+
+	// overlap 默认的重叠处理方式
+	// overlap is the default overlap policy
+	overlap OverlapPolicy
+
+	// timeout 默认的最长执行时间，0 表示不限制
+	// timeout is the default max execution duration, 0 means unlimited
+	timeout time.Duration
+}
+
+// onceTask 一次性任务及其执行时间
+// onceTask is a one-shot task and its execution time
+type onceTask struct {
+	task Task
+	at   time.Time
+}
+
+// Option 配置内存调度器的选项
+// Option configures the in-memory scheduler
+type Option func(*inMemoryScheduler)
+
+// WithLogger 设置调度器的日志记录器，默认不输出日志
+// WithLogger sets the scheduler's logger, no logs are written by default
+func WithLogger(logger logging.Logger) Option {
+	return func(s *inMemoryScheduler) {
+		s.logger = logging.OrNop(logger)
+	}
+}
+
+// WithOverlapPolicy 设置任务的默认重叠处理方式，默认为 OverlapQueue
+// WithOverlapPolicy sets the default overlap policy of tasks, OverlapQueue by default
+func WithOverlapPolicy(policy OverlapPolicy) Option {
+	return func(s *inMemoryScheduler) {
+		s.overlap = policy
+	}
+}
+
+// WithTaskTimeout 设置任务的默认最长执行时间，默认不限制
+// WithTaskTimeout sets the default max execution duration of tasks, unlimited by default
+func WithTaskTimeout(timeout time.Duration) Option {
+	return func(s *inMemoryScheduler) {
+		s.timeout = timeout
+	}
 }
 
 // NewInMemoryScheduler 创建一个新的内存调度器
 // NewInMemoryScheduler creates a new in-memory scheduler
-func NewInMemoryScheduler() Scheduler {
+func NewInMemoryScheduler(opts ...Option) Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &inMemoryScheduler{
-		tasks:    make(map[string]Task),
-		taskCtxs: make(map[string]context.CancelFunc),
-		ctx:      ctx,
-		cancel:   cancel,
-		running:  false,
+	s := &inMemoryScheduler{
+		tasks:     make(map[string]Task),
+		taskCtxs:  make(map[string]context.CancelFunc),
+		onceTasks: make(map[string]*onceTask),
+		onceCtxs:  make(map[string]context.CancelFunc),
+		ctx:       ctx,
+		cancel:    cancel,
+		running:   false,
+		logger:    logging.Nop(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // AddTask 添加任务到调度器
@@ -99,7 +162,9 @@ func (s *inMemoryScheduler) RemoveTask(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.tasks[id]; !exists {
+	_, recurring := s.tasks[id]
+	_, once := s.onceTasks[id]
+	if !recurring && !once {
 		return ErrTaskNotFound
 	}
 
@@ -109,14 +174,48 @@ func (s *inMemoryScheduler) RemoveTask(id string) error {
 		cancel()
 		delete(s.taskCtxs, id)
 	}
+	if cancel, exists := s.onceCtxs[id]; exists {
+		cancel()
+		delete(s.onceCtxs, id)
+	}
 
 	// 删除任务
 	// Delete the task
 	delete(s.tasks, id)
+	delete(s.onceTasks, id)
 
 	return nil
 }
 
+// ScheduleOnce 在指定时间执行一次任务
+// ScheduleOnce executes the task once at the given time
+func (s *inMemoryScheduler) ScheduleOnce(task Task, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := task.ID()
+	if _, exists := s.onceTasks[id]; exists {
+		return ErrTaskExists
+	}
+
+	entry := &onceTask{task: task, at: at}
+	s.onceTasks[id] = entry
+
+	// 如果调度器正在运行，立即开始计时
+	// If the scheduler is running, start the timer immediately
+	if s.running {
+		s.startOnce(entry)
+	}
+
+	return nil
+}
+
+// ScheduleAfter 在延迟d后执行一次任务
+// ScheduleAfter executes the task once after the delay d
+func (s *inMemoryScheduler) ScheduleAfter(task Task, d time.Duration) error {
+	return s.ScheduleOnce(task, time.Now().Add(d))
+}
+
 // Start 启动调度器
 // Start starts the scheduler
 func (s *inMemoryScheduler) Start(ctx context.Context) error {
@@ -134,6 +233,9 @@ func (s *inMemoryScheduler) Start(ctx context.Context) error {
 	for _, task := range s.tasks {
 		s.startTask(task)
 	}
+	for _, entry := range s.onceTasks {
+		s.startOnce(entry)
+	}
 
 	return nil
 }
@@ -142,26 +244,31 @@ func (s *inMemoryScheduler) Start(ctx context.Context) error {
 // Stop stops the scheduler
 func (s *inMemoryScheduler) Stop() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	if !s.running {
+		s.mu.Unlock()
 		return ErrSchedulerStopped
 	}
 
 	s.running = false
 
-	// 取消所有任务的执行
-	// Cancel all tasks' execution
+	// 取消所有任务的执行，尚未执行的一次性任务保留到下次启动
+	// Cancel all tasks' execution, pending one-shot tasks are kept until the next start
 	for _, cancel := range s.taskCtxs {
 		cancel()
 	}
+	for _, cancel := range s.onceCtxs {
+		cancel()
+	}
 
 	// 清空任务上下文
 	// Clear task contexts
 	s.taskCtxs = make(map[string]context.CancelFunc)
+	s.onceCtxs = make(map[string]context.CancelFunc)
+	s.mu.Unlock()
 
-	// 等待所有任务完成
-	// Wait for all tasks to complete
+	// 释放锁后等待所有任务完成，正在执行的任务可能需要访问调度器
+	// Wait for all tasks to complete after unlocking, running tasks may need to access the scheduler
 	s.wg.Wait()
 
 	return nil
@@ -173,24 +280,29 @@ func (s *inMemoryScheduler) GetTask(id string) (Task, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	task, exists := s.tasks[id]
-	if !exists {
-		return nil, ErrTaskNotFound
+	if task, exists := s.tasks[id]; exists {
+		return task, nil
+	}
+	if entry, exists := s.onceTasks[id]; exists {
+		return entry.task, nil
 	}
 
-	return task, nil
+	return nil, ErrTaskNotFound
 }
 
-// ListTasks 列出所有任务
-// ListTasks lists all tasks
+// ListTasks 列出所有任务，包括尚未执行的一次性任务
+// ListTasks lists all tasks, including pending one-shot tasks
 func (s *inMemoryScheduler) ListTasks() []Task {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	tasks := make([]Task, 0, len(s.tasks))
+	tasks := make([]Task, 0, len(s.tasks)+len(s.onceTasks))
 	for _, task := range s.tasks {
 		tasks = append(tasks, task)
 	}
+	for _, entry := range s.onceTasks {
+		tasks = append(tasks, entry.task)
+	}
 
 	return tasks
 }
@@ -217,6 +329,7 @@ func (s *inMemoryScheduler) startTask(task Task) {
 		}()
 
 		interval := time.Duration(task.Interval()) * time.Second
+		trigger := s.newTrigger(taskCtx, task)
 
 		// 创建定时器
 		// Create a ticker
@@ -225,23 +338,132 @@ func (s *inMemoryScheduler) startTask(task Task) {
 
 		// 立即执行一次
 		// Execute immediately
-		s.executeTask(taskCtx, task)
+		trigger()
 
 		for {
 			select {
 			case <-taskCtx.Done():
 				return
 			case <-ticker.C:
-				s.executeTask(taskCtx, task)
+				trigger()
 			}
 		}
 	}()
 }
 
-// executeTask 执行单个任务
-// executeTask executes a single task
+// newTrigger 返回按任务的重叠处理方式在后台执行任务的函数
+// newTrigger returns a function that executes the task in the background according to its overlap policy
+func (s *inMemoryScheduler) newTrigger(ctx context.Context, task Task) func() {
+	policy := s.overlap
+	if t, ok := task.(OverlapTask); ok {
+		policy = t.OverlapPolicy()
+	}
+
+	var (
+		mu      sync.Mutex
+		running bool
+		pending bool
+	)
+
+	return func() {
+		if policy == OverlapAllow {
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.executeTask(ctx, task)
+			}()
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if running {
+			if policy == OverlapQueue {
+				pending = true
+				return
+			}
+			s.logger.Warn("task is still running, skipping tick", logging.KeyTask, task.ID())
+			return
+		}
+		running = true
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+
+			for {
+				s.executeTask(ctx, task)
+
+				// 执行期间有新的触发时继续执行一次
+				// Run once more if a tick arrived during the execution
+				mu.Lock()
+				if !pending || ctx.Err() != nil {
+					running, pending = false, false
+					mu.Unlock()
+					return
+				}
+				pending = false
+				mu.Unlock()
+			}
+		}()
+	}
+}
+
+// startOnce 启动一次性任务的计时，到时后移除并执行任务
+// startOnce starts the timer of a one-shot task, which is removed and executed when due
+func (s *inMemoryScheduler) startOnce(entry *onceTask) {
+	id := entry.task.ID()
+
+	taskCtx, cancel := context.WithCancel(s.ctx)
+	s.onceCtxs[id] = cancel
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		timer := time.NewTimer(time.Until(entry.at))
+		defer timer.Stop()
+
+		select {
+		case <-taskCtx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// 调度器停止或任务被移除、替换后不再执行
+		// Skip execution if the scheduler stopped or the task was removed or replaced
+		s.mu.Lock()
+		if taskCtx.Err() != nil || s.onceTasks[id] != entry {
+			s.mu.Unlock()
+			return
+		}
+		delete(s.onceTasks, id)
+		delete(s.onceCtxs, id)
+		s.mu.Unlock()
+
+		s.executeTask(taskCtx, entry.task)
+		cancel()
+	}()
+}
+
+// executeTask 执行单个任务，超过最长执行时间时取消任务的上下文
+// executeTask executes a single task, canceling its context once the max execution duration is exceeded
 func (s *inMemoryScheduler) executeTask(ctx context.Context, task Task) {
+	timeout := s.timeout
+	if t, ok := task.(TimeoutTask); ok && t.Timeout() > 0 {
+		timeout = t.Timeout()
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	if err := task.Execute(ctx); err != nil {
-		fmt.Printf("Failed to execute task %s: %v\n", task.ID(), err)
+		s.logger.Error("failed to execute task", logging.KeyTask, task.ID(), logging.KeyError, err)
+	} else if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		s.logger.Warn("task exceeded max execution duration", logging.KeyTask, task.ID(), "timeout", timeout)
 	}
 }
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"time"
 )
 
 // Task 定义了一个调度任务
@@ -20,6 +21,45 @@ type Task interface {
 	Interval() int
 }
 
+// OverlapPolicy 定义了任务执行时间超过间隔时对后续触发的处理方式
+// OverlapPolicy defines how ticks are handled while the previous execution is still running
+type OverlapPolicy int
+
+const (
+	// OverlapQueue 任务运行期间的触发合并为一次，在本次执行结束后立即执行
+	// OverlapQueue coalesces ticks during an execution into one run right after it finishes
+	OverlapQueue OverlapPolicy = iota
+
+	// OverlapSkip 跳过任务运行期间的触发
+	// OverlapSkip skips ticks while the task is running
+	OverlapSkip
+
+	// OverlapAllow 允许同一任务的多次执行重叠
+	// OverlapAllow lets executions of the same task overlap
+	OverlapAllow
+)
+
+// OverlapTask 可以由任务实现，用于覆盖调度器默认的重叠处理方式
+// OverlapTask can be implemented by tasks to override the scheduler's default overlap policy
+type OverlapTask interface {
+	Task
+
+	// OverlapPolicy 返回任务的重叠处理方式
+	// OverlapPolicy returns the task's overlap policy
+	OverlapPolicy() OverlapPolicy
+}
+
+// TimeoutTask 可以由任务实现，用于覆盖调度器默认的最长执行时间，超时后取消任务的上下文
+// TimeoutTask can be implemented by tasks to override the scheduler's default max execution duration,
+// the task's context is canceled once it is exceeded
+type TimeoutTask interface {
+	Task
+
+	// Timeout 返回任务的最长执行时间，0 表示使用调度器的默认值
+	// Timeout returns the task's max execution duration, 0 means the scheduler's default
+	Timeout() time.Duration
+}
+
 // Scheduler 定义了调度器的接口
 // Scheduler defines the scheduler interface
 type Scheduler interface {
@@ -27,10 +67,20 @@ type Scheduler interface {
 	// AddTask adds a task to the scheduler
 	AddTask(task Task) error
 
-	// RemoveTask 从调度器中移除任务
-	// RemoveTask removes a task from the scheduler
+	// RemoveTask 从调度器中移除任务，包括尚未执行的一次性任务
+	// RemoveTask removes a task from the scheduler, including pending one-shot tasks
 	RemoveTask(id string) error
 
+	// ScheduleOnce 在指定时间执行一次任务，执行后自动移除，任务的 Interval 被忽略；
+	// 时间已过时尽快执行，调度器未运行时等到启动后执行
+	// ScheduleOnce executes the task once at the given time and removes it afterwards, ignoring its Interval;
+	// past times run as soon as possible, and tasks wait for the scheduler to start if it is not running
+	ScheduleOnce(task Task, at time.Time) error
+
+	// ScheduleAfter 在延迟d后执行一次任务，等同于 ScheduleOnce(task, time.Now().Add(d))
+	// ScheduleAfter executes the task once after the delay d, equivalent to ScheduleOnce(task, time.Now().Add(d))
+	ScheduleAfter(task Task, d time.Duration) error
+
 	// Start 启动调度器
 	// Start starts the scheduler
 	Start(ctx context.Context) error
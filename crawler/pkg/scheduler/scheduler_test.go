@@ -227,3 +227,177 @@ func TestListTasks(t *testing.T) {
 		t.Errorf("Expected 1 task, got %d", len(tasks))
 	}
 }
+
+// TestScheduleOnce 一次性任务测试
+func TestScheduleOnce(t *testing.T) {
+	s := NewInMemoryScheduler()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	executed := make(chan bool, 2)
+	task := &mockTask{
+		id:       "test-once",
+		interval: 1,
+		executed: executed,
+	}
+
+	// 调度器启动前添加的一次性任务在启动后执行
+	if err := s.ScheduleOnce(task, time.Now().Add(50*time.Millisecond)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := s.ScheduleOnce(task, time.Now()); err != ErrTaskExists {
+		t.Errorf("Expected ErrTaskExists, got %v", err)
+	}
+	if _, err := s.GetTask("test-once"); err != nil {
+		t.Errorf("Expected pending task, got %v", err)
+	}
+
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer s.Stop()
+
+	select {
+	case <-executed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected task to execute, but it didn't")
+	}
+
+	// 一次性任务只执行一次，执行后被移除
+	select {
+	case <-executed:
+		t.Fatal("Expected task to execute only once")
+	case <-time.After(200 * time.Millisecond):
+	}
+	if _, err := s.GetTask("test-once"); err != ErrTaskNotFound {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+	if tasks := s.ListTasks(); len(tasks) != 0 {
+		t.Errorf("Expected 0 tasks, got %d", len(tasks))
+	}
+}
+
+// TestScheduleAfter 延迟任务测试
+func TestScheduleAfter(t *testing.T) {
+	s := NewInMemoryScheduler()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer s.Stop()
+
+	executed := make(chan bool, 1)
+	task := &mockTask{id: "test-after", executed: executed}
+	start := time.Now()
+	if err := s.ScheduleAfter(task, 100*time.Millisecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case <-executed:
+		if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+			t.Errorf("Expected task to wait 100ms, executed after %v", elapsed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected task to execute, but it didn't")
+	}
+
+	// 移除的延迟任务不会执行
+	removed := &mockTask{id: "test-removed", executed: make(chan bool, 1)}
+	if err := s.ScheduleAfter(removed, 100*time.Millisecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := s.RemoveTask("test-removed"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	select {
+	case <-removed.executed:
+		t.Fatal("Expected removed task not to execute")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+// blockingTask 执行时阻塞到 release 关闭或上下文取消的任务
+type blockingTask struct {
+	id       string
+	policy   OverlapPolicy
+	timeout  time.Duration
+	started  chan struct{}
+	release  chan struct{}
+	canceled chan error
+}
+
+func (t *blockingTask) ID() string                   { return t.id }
+func (t *blockingTask) Interval() int                { return 1 }
+func (t *blockingTask) OverlapPolicy() OverlapPolicy { return t.policy }
+func (t *blockingTask) Timeout() time.Duration       { return t.timeout }
+
+func (t *blockingTask) Execute(ctx context.Context) error {
+	t.started <- struct{}{}
+	select {
+	case <-t.release:
+		return nil
+	case <-ctx.Done():
+		t.canceled <- ctx.Err()
+		return ctx.Err()
+	}
+}
+
+// TestOverlapPolicy 任务重叠处理测试
+func TestOverlapPolicy(t *testing.T) {
+	tests := []struct {
+		policy OverlapPolicy
+		runs   int
+	}{
+		{OverlapSkip, 1},
+		{OverlapQueue, 2},
+		{OverlapAllow, 3},
+	}
+
+	for _, tt := range tests {
+		s := NewInMemoryScheduler().(*inMemoryScheduler)
+		task := &blockingTask{
+			id:      "test-overlap",
+			policy:  tt.policy,
+			started: make(chan struct{}, 3),
+			release: make(chan struct{}),
+		}
+
+		// 第一次执行未结束时再触发两次
+		trigger := s.newTrigger(context.Background(), task)
+		trigger()
+		<-task.started
+		trigger()
+		trigger()
+		close(task.release)
+		s.wg.Wait()
+
+		if runs := 1 + len(task.started); runs != tt.runs {
+			t.Errorf("policy %d: expected %d runs, got %d", tt.policy, tt.runs, runs)
+		}
+	}
+}
+
+// TestTaskTimeout 任务最长执行时间测试
+func TestTaskTimeout(t *testing.T) {
+	s := NewInMemoryScheduler(WithTaskTimeout(time.Hour)).(*inMemoryScheduler)
+	task := &blockingTask{
+		id:       "test-timeout",
+		timeout:  50 * time.Millisecond,
+		started:  make(chan struct{}, 1),
+		release:  make(chan struct{}),
+		canceled: make(chan error, 1),
+	}
+
+	// 任务自身的超时优先于调度器的默认值
+	start := time.Now()
+	s.executeTask(context.Background(), task)
+	if err := <-task.canceled; err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected task to be canceled after 50ms, took %v", elapsed)
+	}
+}
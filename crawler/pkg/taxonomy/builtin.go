@@ -0,0 +1,94 @@
+package taxonomy
+
+// 内置分类的规范ID
+const (
+	General       = "general"
+	News          = "news"
+	Politics      = "politics"
+	Finance       = "finance"
+	Tech          = "tech"
+	Programming   = "programming"
+	Computers     = "computers"
+	Apps          = "apps"
+	Products      = "products"
+	Papers        = "papers"
+	Entertainment = "entertainment"
+	Movies        = "movies"
+	Games         = "games"
+	Sports        = "sports"
+	Social        = "social"
+	Career        = "career"
+	Shopping      = "shopping"
+	Search        = "search"
+)
+
+// builtinCategories 内置数据源使用的分类
+var builtinCategories = []Category{
+	{ID: General, Names: map[string]string{"zh": "综合", "en": "General"}},
+	{ID: News, Names: map[string]string{"zh": "新闻", "en": "News"}},
+	{ID: Politics, Names: map[string]string{"zh": "时政", "en": "Politics"}, Aliases: []string{"政治"}},
+	{ID: Finance, Names: map[string]string{"zh": "财经", "en": "Finance"}, Aliases: []string{"金融", "economy"}},
+	{ID: Tech, Names: map[string]string{"zh": "科技", "en": "Technology"}},
+	{ID: Programming, Names: map[string]string{"zh": "编程", "en": "Programming"}, Aliases: []string{"开发", "dev"}},
+	{ID: Computers, Names: map[string]string{"zh": "电脑", "en": "Computers"}, Aliases: []string{"pc"}},
+	{ID: Apps, Names: map[string]string{"zh": "应用", "en": "Apps"}, Aliases: []string{"app"}},
+	{ID: Products, Names: map[string]string{"zh": "产品", "en": "Products"}},
+	{ID: Papers, Names: map[string]string{"zh": "论文", "en": "Papers"}, Aliases: []string{"学术", "research"}},
+	{ID: Entertainment, Names: map[string]string{"zh": "娱乐", "en": "Entertainment"}},
+	{ID: Movies, Names: map[string]string{"zh": "电影", "en": "Movies"}, Aliases: []string{"film"}},
+	{ID: Games, Names: map[string]string{"zh": "游戏", "en": "Games"}, Aliases: []string{"gaming"}},
+	{ID: Sports, Names: map[string]string{"zh": "体育", "en": "Sports"}},
+	{ID: Social, Names: map[string]string{"zh": "社交", "en": "Social"}},
+	{ID: Career, Names: map[string]string{"zh": "职场", "en": "Career"}, Aliases: []string{"求职", "jobs"}},
+	{ID: Shopping, Names: map[string]string{"zh": "购物", "en": "Shopping"}, Aliases: []string{"优惠", "deals"}},
+	{ID: Search, Names: map[string]string{"zh": "搜索", "en": "Search"}, Aliases: []string{"热搜"}},
+}
+
+// defaultTaxonomy 全局分类注册表，包含所有内置分类
+var defaultTaxonomy = func() *Taxonomy {
+	t, err := New(builtinCategories...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}()
+
+// Default 返回全局分类注册表，数据源注册表和爬取引擎使用它校验和匹配分类
+func Default() *Taxonomy {
+	return defaultTaxonomy
+}
+
+// Register 向全局分类注册表添加分类
+func Register(category Category) error {
+	return defaultTaxonomy.Register(category)
+}
+
+// Normalize 返回分类在全局分类注册表中的规范ID
+func Normalize(name string) (string, bool) {
+	return defaultTaxonomy.Normalize(name)
+}
+
+// Key 返回用于比较分类的键，见 Taxonomy.Key
+func Key(name string) string {
+	return defaultTaxonomy.Key(name)
+}
+
+// Match 判断两个分类名称是否表示同一个分类
+func Match(a, b string) bool {
+	return defaultTaxonomy.Match(a, b)
+}
+
+// DisplayName 返回分类在lang语言中的显示名称
+func DisplayName(name, lang string) string {
+	return defaultTaxonomy.DisplayName(name, lang)
+}
+
+// Validate 检查分类都已在全局分类注册表中注册
+func Validate(categories []string) error {
+	return defaultTaxonomy.Validate(categories)
+}
+
+// List 返回全局分类注册表中的所有分类
+func List() []Category {
+	return defaultTaxonomy.List()
+}
@@ -0,0 +1,195 @@
+// Package taxonomy 管理数据源分类的规范ID、别名和各语言的显示名称
+//
+// 数据源的 GetCategories 可以返回规范ID（如 tech）、任一语言的显示名称（如 科技）或别名，
+// 按分类订阅和筛选数据源时它们被视为同一个分类。内置分类覆盖了所有内置数据源，
+// 自定义分类可以通过 Register 添加。
+package taxonomy
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage 没有指定语言的显示名称时使用的语言
+const DefaultLanguage = "zh"
+
+// Category 分类
+type Category struct {
+	// ID 规范ID，使用小写英文，如 tech
+	ID string `json:"id"`
+
+	// Names 各语言的显示名称，键为语言代码，如 {"zh": "科技", "en": "Technology"}
+	Names map[string]string `json:"names"`
+
+	// Aliases 别名，匹配时不区分大小写
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Name 返回分类在lang语言中的显示名称，没有该语言时依次使用 DefaultLanguage 的名称和ID
+func (c Category) Name(lang string) string {
+	if name, ok := c.Names[lang]; ok {
+		return name
+	}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		if name, ok := c.Names[base]; ok {
+			return name
+		}
+	}
+	if name, ok := c.Names[DefaultLanguage]; ok {
+		return name
+	}
+	return c.ID
+}
+
+// clone 返回分类的副本，避免调用者修改注册表中的名称和别名
+func (c Category) clone() Category {
+	c.Names = maps.Clone(c.Names)
+	c.Aliases = slices.Clone(c.Aliases)
+	return c
+}
+
+// Taxonomy 分类注册表，可以安全地并发使用
+type Taxonomy struct {
+	mu         sync.RWMutex
+	categories map[string]Category
+	index      map[string]string // 规范ID、显示名称和别名（小写）到规范ID的映射
+}
+
+// New 创建包含指定分类的注册表，分类冲突时返回错误
+func New(categories ...Category) (*Taxonomy, error) {
+	t := &Taxonomy{
+		categories: make(map[string]Category),
+		index:      make(map[string]string),
+	}
+	for _, category := range categories {
+		if err := t.Register(category); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Register 添加分类，ID为空或ID、显示名称、别名已属于其他分类时返回错误；
+// 再次注册同一ID时合并显示名称和别名
+func (t *Taxonomy) Register(category Category) error {
+	id := key(category.ID)
+	if id == "" {
+		return fmt.Errorf("category id is empty")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	merged, exists := t.categories[id]
+	if !exists {
+		merged = Category{ID: id, Names: make(map[string]string)}
+	}
+
+	terms := []string{id}
+	for _, name := range category.Names {
+		terms = append(terms, name)
+	}
+	terms = append(terms, category.Aliases...)
+	for _, term := range terms {
+		if owner, ok := t.index[key(term)]; ok && owner != id {
+			return fmt.Errorf("category %q of %s is already used by %s", term, id, owner)
+		}
+	}
+
+	for lang, name := range category.Names {
+		merged.Names[lang] = name
+	}
+	for _, alias := range category.Aliases {
+		if _, ok := t.index[key(alias)]; !ok {
+			merged.Aliases = append(merged.Aliases, alias)
+		}
+	}
+	for _, term := range terms {
+		if k := key(term); k != "" {
+			t.index[k] = id
+		}
+	}
+	t.categories[id] = merged
+	return nil
+}
+
+// Lookup 按规范ID、显示名称或别名查找分类
+func (t *Taxonomy) Lookup(name string) (Category, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	id, ok := t.index[key(name)]
+	if !ok {
+		return Category{}, false
+	}
+	return t.categories[id].clone(), true
+}
+
+// Normalize 返回分类的规范ID，未知的分类返回去掉首尾空白并转为小写的原字符串和false
+func (t *Taxonomy) Normalize(name string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if id, ok := t.index[key(name)]; ok {
+		return id, true
+	}
+	return key(name), false
+}
+
+// Key 返回用于比较分类的键：已知分类为规范ID，未知分类为转为小写的原字符串
+func (t *Taxonomy) Key(name string) string {
+	id, _ := t.Normalize(name)
+	return id
+}
+
+// Match 判断两个分类名称是否表示同一个分类
+func (t *Taxonomy) Match(a, b string) bool {
+	return t.Key(a) == t.Key(b)
+}
+
+// DisplayName 返回分类在lang语言中的显示名称，未知的分类原样返回
+func (t *Taxonomy) DisplayName(name, lang string) string {
+	category, ok := t.Lookup(name)
+	if !ok {
+		return name
+	}
+	return category.Name(lang)
+}
+
+// Validate 检查分类都已注册，返回列出所有未知分类的错误
+func (t *Taxonomy) Validate(categories []string) error {
+	var unknown []string
+	for _, category := range categories {
+		if _, ok := t.Normalize(category); !ok {
+			unknown = append(unknown, category)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown categories: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// List 返回所有分类，按规范ID排序
+func (t *Taxonomy) List() []Category {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	categories := make([]Category, 0, len(t.categories))
+	for _, category := range t.categories {
+		categories = append(categories, category.clone())
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+	return categories
+}
+
+// key 返回用于索引的小写字符串
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,72 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxonomy(t *testing.T) {
+	tx, err := New(builtinCategories...)
+	if err != nil {
+		t.Fatalf("创建分类注册表失败: %v", err)
+	}
+
+	for _, name := range []string{"tech", "TECH", "科技", "Technology", " technology "} {
+		id, ok := tx.Normalize(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, Tech, id, name)
+	}
+	id, ok := tx.Normalize("热搜")
+	assert.True(t, ok)
+	assert.Equal(t, Search, id, "别名应映射到规范ID")
+
+	id, ok = tx.Normalize(" Unknown ")
+	assert.False(t, ok)
+	assert.Equal(t, "unknown", id)
+
+	assert.True(t, tx.Match("财经", "finance"))
+	assert.True(t, tx.Match("Custom", "custom"), "未知分类按小写比较")
+	assert.False(t, tx.Match("财经", "科技"))
+
+	assert.Equal(t, "Technology", tx.DisplayName("科技", "en"))
+	assert.Equal(t, "Technology", tx.DisplayName("科技", "en-US"))
+	assert.Equal(t, "科技", tx.DisplayName("tech", "fr"), "没有该语言时使用中文名称")
+	assert.Equal(t, "自定义", tx.DisplayName("自定义", "en"))
+
+	assert.NoError(t, tx.Validate([]string{"科技", "programming", "开发"}))
+	err = tx.Validate([]string{"科技", "foo", "bar"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "foo, bar")
+	}
+}
+
+func TestTaxonomyRegister(t *testing.T) {
+	tx, err := New(builtinCategories...)
+	if err != nil {
+		t.Fatalf("创建分类注册表失败: %v", err)
+	}
+
+	assert.Error(t, tx.Register(Category{}), "ID为空时应返回错误")
+	assert.Error(t, tx.Register(Category{ID: "fintech", Aliases: []string{"财经"}}), "别名与已有分类冲突时应返回错误")
+	_, ok := tx.Lookup("fintech")
+	assert.False(t, ok, "注册失败的分类不应被添加")
+
+	assert.NoError(t, tx.Register(Category{ID: "AI", Names: map[string]string{"zh": "人工智能"}}))
+	assert.NoError(t, tx.Register(Category{ID: "ai", Names: map[string]string{"en": "AI"}, Aliases: []string{"llm"}}))
+	category, ok := tx.Lookup("LLM")
+	if assert.True(t, ok) {
+		assert.Equal(t, "ai", category.ID)
+		assert.Equal(t, "人工智能", category.Name("zh"), "再次注册同一ID应合并显示名称")
+		assert.Equal(t, "AI", category.Name("en"))
+	}
+
+	category.Names["zh"] = "修改"
+	assert.Equal(t, "人工智能", tx.DisplayName("ai", "zh"), "修改返回的分类不应影响注册表")
+
+	list := tx.List()
+	assert.Len(t, list, len(builtinCategories)+1)
+	for i := 1; i < len(list); i++ {
+		assert.Less(t, list[i-1].ID, list[i].ID)
+	}
+}
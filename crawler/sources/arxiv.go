@@ -0,0 +1,209 @@
+package sources
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// defaultArxivCategories 未配置时订阅的arXiv分类
+var defaultArxivCategories = []string{"cs.AI", "cs.CL", "cs.LG"}
+
+// arxivVersion 匹配arXiv编号末尾的版本号，例如 v2
+var arxivVersion = regexp.MustCompile(`v\d+$`)
+
+// ArxivSource arXiv论文数据源
+// 该数据源通过arXiv API获取指定分类中最新提交的论文，摘要保存在 Item.Content 中；
+// 默认读取环境变量 ARXIV_CATEGORIES 中以逗号分隔的分类（例如 cs.AI,math.CO），也可以通过 SetArxivCategories 设置；
+// 通过 Registry.Configure 配置时支持 categories 和 max_results 两个键
+type ArxivSource struct {
+	BaseSource
+
+	// MaxResults 每次获取的论文数量
+	MaxResults int
+
+	mu         sync.RWMutex
+	categories []string
+}
+
+// ArxivFeed arXiv API返回的Atom feed
+type ArxivFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Entries []ArxivEntry `xml:"entry"`
+}
+
+// ArxivEntry arXiv API返回的论文条目
+type ArxivEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Summary   string `xml:"summary"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+	PrimaryCategory struct {
+		Term string `xml:"term,attr"`
+	} `xml:"http://arxiv.org/schemas/atom primary_category"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"http://www.w3.org/2005/Atom category"`
+}
+
+// NewArxivSource 创建arXiv论文数据源实例，categories为arXiv分类，为空时使用 cs.AI、cs.CL 和 cs.LG
+func NewArxivSource(categories ...string) *ArxivSource {
+	s := &ArxivSource{
+		BaseSource: BaseSource{
+			Name:       "arxiv",
+			URL:        "https://export.arxiv.org/api/query",
+			Interval:   3600, // 1小时爬取一次，arXiv每天更新一次，并要求控制请求频率
+			Categories: []string{"科技", "论文"},
+		},
+		MaxResults: 50,
+	}
+	s.SetArxivCategories(categories)
+	return s
+}
+
+// SetArxivCategories 设置订阅的arXiv分类，为空时使用默认分类
+func (s *ArxivSource) SetArxivCategories(categories []string) {
+	normalized := make([]string, 0, len(categories))
+	for _, category := range categories {
+		if category = strings.TrimSpace(category); category != "" {
+			normalized = append(normalized, category)
+		}
+	}
+	if len(normalized) == 0 {
+		normalized = append(normalized, defaultArxivCategories...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.categories = normalized
+}
+
+// Clone 返回数据源的副本
+func (s *ArxivSource) Clone() crawler.Source {
+	return &ArxivSource{BaseSource: s.BaseSource, MaxResults: s.MaxResults, categories: s.ArxivCategories()}
+}
+
+// ArxivCategories 返回订阅的arXiv分类
+func (s *ArxivSource) ArxivCategories() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.categories...)
+}
+
+// Configure 应用通用配置以及 categories（以逗号分隔的arXiv分类）和 max_results
+func (s *ArxivSource) Configure(config SourceConfig) error {
+	if err := s.BaseSource.Configure(config); err != nil {
+		return err
+	}
+	maxResults, err := config.Int("max_results", s.MaxResults)
+	if err != nil {
+		return err
+	}
+	if maxResults <= 0 {
+		return fmt.Errorf("invalid max_results: %d", maxResults)
+	}
+
+	s.MaxResults = maxResults
+	if config.Has("categories") {
+		s.SetArxivCategories(config.Strings("categories", nil))
+	}
+	return nil
+}
+
+// queryURL 返回按提交时间倒序查询订阅分类的API地址
+func (s *ArxivSource) queryURL() string {
+	categories := s.ArxivCategories()
+	terms := make([]string, len(categories))
+	for i, category := range categories {
+		terms[i] = "cat:" + category
+	}
+
+	query := url.Values{}
+	query.Set("search_query", strings.Join(terms, " OR "))
+	query.Set("sortBy", "submittedDate")
+	query.Set("sortOrder", "descending")
+	query.Set("max_results", fmt.Sprint(s.MaxResults))
+	return s.URL + "?" + query.Encode()
+}
+
+// Fetch 获取订阅分类中最新提交的论文
+func (s *ArxivSource) Fetch(ctx context.Context) ([]byte, error) {
+	return s.FetchURL(ctx, s.queryURL())
+}
+
+// Parse 解析arXiv API返回的Atom feed
+func (s *ArxivSource) Parse(content []byte) ([]models.Item, error) {
+	var feed ArxivFeed
+	if err := xml.Unmarshal(content, &feed); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.Item, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		// 论文编号去掉版本号，同一论文的新版本视为同一数据项
+		id := entry.ID
+		if i := strings.LastIndex(id, "/abs/"); i >= 0 {
+			id = id[i+len("/abs/"):]
+		}
+		id = arxivVersion.ReplaceAllString(id, "")
+		if id == "" {
+			continue
+		}
+
+		link := entry.ID
+		for _, l := range entry.Links {
+			if l.Rel == "alternate" && l.Href != "" {
+				link = l.Href
+				break
+			}
+		}
+
+		item := NewBaseItem(id, strings.Join(strings.Fields(entry.Title), " "), link, s.Name)
+		item.Content = strings.Join(strings.Fields(entry.Summary), " ")
+		item.Category = entry.PrimaryCategory.Term
+		authors := make([]string, len(entry.Authors))
+		for i, author := range entry.Authors {
+			authors[i] = author.Name
+		}
+		item.Author = strings.Join(authors, ", ")
+		for _, category := range entry.Categories {
+			item.Tags = append(item.Tags, category.Term)
+		}
+		if published, err := time.Parse(time.RFC3339, entry.Published); err == nil {
+			item.PublishedAt = published
+			item.CreatedAt = published
+		}
+		if updated, err := time.Parse(time.RFC3339, entry.Updated); err == nil {
+			item.UpdatedAt = updated
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func init() {
+	var categories []string
+	if env := os.Getenv("ARXIV_CATEGORIES"); env != "" {
+		categories = strings.Split(env, ",")
+	}
+	RegisterSource(NewArxivSource(categories...))
+}
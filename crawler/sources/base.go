@@ -1,15 +1,22 @@
 package sources
 
 import (
+	"cmp"
 	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/fetcher"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
+// DefaultTimeout 数据源没有设置 Timeout 时使用的请求超时
+const DefaultTimeout = 10 * time.Second
+
+// DefaultUserAgent 数据源没有设置 UserAgent 时发送的 User-Agent 请求头
+const DefaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
 // BaseSource 是所有数据源的基础实现
 type BaseSource struct {
 	Name       string
@@ -17,6 +24,19 @@ type BaseSource struct {
 	Interval   int
 	Client     *http.Client
 	Categories []string
+	// Timeout 超时，同时作为单次HTTP请求的超时和单次爬取的截止时间；
+	// 为0时请求超时使用 DefaultTimeout，截止时间使用爬取引擎的默认值；设置了 Client 时请求超时以 Client 为准
+	Timeout time.Duration
+	// Description、Homepage、Country 和 Language 在界面中展示的数据源信息，为空时使用内置数据源的信息
+	Description string
+	Homepage    string
+	Country     string
+	Language    string
+
+	// Cookie 和 UserAgent 请求时发送的请求头，UserAgent 为空时使用 DefaultUserAgent；
+	// 只对使用 FetchURL 发起请求的数据源生效，可以通过 Configure 设置
+	Cookie    string
+	UserAgent string
 }
 
 // GetName 返回数据源名称
@@ -29,6 +49,45 @@ func (s *BaseSource) GetURL() string {
 	return s.URL
 }
 
+// GetDescription 返回数据源的简介
+func (s *BaseSource) GetDescription() string {
+	return cmp.Or(s.Description, builtinMetadata[s.Name].description)
+}
+
+// GetHomepage 返回数据源网站的首页
+func (s *BaseSource) GetHomepage() string {
+	return cmp.Or(s.Homepage, builtinMetadata[s.Name].homepage)
+}
+
+// GetCountry 返回数据源所属的国家或地区代码
+func (s *BaseSource) GetCountry() string {
+	return cmp.Or(s.Country, builtinMetadata[s.Name].country)
+}
+
+// GetLanguage 返回数据源内容的语言代码
+func (s *BaseSource) GetLanguage() string {
+	return cmp.Or(s.Language, builtinMetadata[s.Name].language)
+}
+
+// SetClient 设置获取数据时使用的HTTP客户端，可用于配置代理和超时
+// 只对使用 BaseSource.Client 发起请求的数据源生效
+func (s *BaseSource) SetClient(client *http.Client) {
+	s.Client = client
+}
+
+// GetTimeout 返回设置的超时，为0表示未设置；爬取引擎以此作为单次爬取的截止时间
+func (s *BaseSource) GetTimeout() time.Duration {
+	return s.Timeout
+}
+
+// HTTPClient 返回获取数据时使用的HTTP客户端，没有设置 Client 时创建超时为 Timeout 的客户端
+func (s *BaseSource) HTTPClient() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return &http.Client{Timeout: cmp.Or(s.Timeout, DefaultTimeout)}
+}
+
 // GetInterval 返回爬取间隔（秒）
 func (s *BaseSource) GetInterval() int {
 	return s.Interval
@@ -36,22 +95,23 @@ func (s *BaseSource) GetInterval() int {
 
 // Fetch 获取数据源内容
 func (s *BaseSource) Fetch(ctx context.Context) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.GetURL(), nil)
+	return s.FetchURL(ctx, s.GetURL())
+}
+
+// FetchURL 使用数据源的HTTP客户端和请求头获取url的内容
+func (s *BaseSource) FetchURL(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// 设置默认的User-Agent
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-
-	client := s.Client
-	if client == nil {
-		client = &http.Client{
-			Timeout: 10 * time.Second,
-		}
+	req.Header.Set("User-Agent", cmp.Or(s.UserAgent, DefaultUserAgent))
+	req.Header.Set("Accept-Encoding", fetcher.AcceptEncoding)
+	if s.Cookie != "" {
+		req.Header.Set("Cookie", s.Cookie)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +121,7 @@ func (s *BaseSource) Fetch(ctx context.Context) ([]byte, error) {
 		return nil, fmt.Errorf("failed to fetch: %d", resp.StatusCode)
 	}
 
-	return io.ReadAll(resp.Body)
+	return fetcher.ReadBody(resp)
 }
 
 // Parse 解析获取到的内容，返回结构化数据
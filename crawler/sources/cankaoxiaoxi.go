@@ -1,7 +1,6 @@
 package sources
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -16,7 +15,7 @@ import (
 // 2. guandian - 观点相关新闻
 // 3. gj - 国际相关新闻
 type CankaoxiaoxiSource struct {
-	BaseSource
+	MultiURLSource
 	channels []string
 }
 
@@ -37,52 +36,53 @@ type CankaoxiaoxiResponse struct {
 
 // NewCankaoxiaoxiSource 创建参考消息数据源实例
 func NewCankaoxiaoxiSource() *CankaoxiaoxiSource {
-	return &CankaoxiaoxiSource{
-		BaseSource: BaseSource{
-			Name:       "cankaoxiaoxi",
-			URL:        "https://china.cankaoxiaoxi.com/json/channel/zhongguo/list.json",
-			Interval:   300, // 5分钟爬取一次
-			Categories: []string{"综合", "时政"},
+	s := &CankaoxiaoxiSource{
+		MultiURLSource: MultiURLSource{
+			BaseSource: BaseSource{
+				Name:       "cankaoxiaoxi",
+				URL:        "https://china.cankaoxiaoxi.com/",
+				Interval:   300, // 5分钟爬取一次
+				Categories: []string{"综合", "时政"},
+			},
 		},
 		channels: []string{"zhongguo", "guandian", "gj"},
 	}
+	for _, channel := range s.channels {
+		s.URLs = append(s.URLs, fmt.Sprintf("https://china.cankaoxiaoxi.com/json/channel/%s/list.json", channel))
+	}
+	s.ParsePart = s.parseChannel
+	return s
 }
 
-// Fetch 获取参考消息内容
-func (s *CankaoxiaoxiSource) Fetch(ctx context.Context) ([]byte, error) {
-	// 对于参考消息，我们需要从多个渠道获取数据，然后合并
-	// 但由于BaseSource的限制，我们只返回第一个渠道的数据
-	// 实际的多渠道获取将在Parse方法中实现
-	return s.BaseSource.Fetch(ctx)
-}
-
-// Parse 解析参考消息内容
+// Parse 解析三个渠道合并后的内容，按发布时间倒序排列
 func (s *CankaoxiaoxiSource) Parse(content []byte) ([]models.Item, error) {
-	allItems := make([]models.Item, 0)
+	items, err := s.MultiURLSource.Parse(content)
+	if err != nil {
+		return nil, err
+	}
 
-	// 首先解析默认渠道的数据
-	var defaultResp CankaoxiaoxiResponse
-	if err := json.Unmarshal(content, &defaultResp); err != nil {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].PublishedAt.After(items[j].PublishedAt)
+	})
+
+	return items, nil
+}
+
+// parseChannel 解析单个渠道的内容
+func (s *CankaoxiaoxiSource) parseChannel(url string, content []byte) ([]models.Item, error) {
+	var resp CankaoxiaoxiResponse
+	if err := json.Unmarshal(content, &resp); err != nil {
 		return nil, err
 	}
 
-	for _, item := range defaultResp.List {
+	items := make([]models.Item, 0, len(resp.List))
+	for _, item := range resp.List {
 		if modelsItem := s.convertToModelsItem(item); modelsItem != nil {
-			allItems = append(allItems, *modelsItem)
+			items = append(items, *modelsItem)
 		}
 	}
 
-	// 现在我们需要从其他渠道获取数据
-	// 注意：由于BaseSource的限制，我们无法在这里直接使用HTTP客户端
-	// 理想情况下，我们应该重构BaseSource以允许自定义Fetch逻辑
-	// 但为了保持与现有代码的兼容性，我们暂时只返回第一个渠道的数据
-
-	// 按日期排序
-	sort.Slice(allItems, func(i, j int) bool {
-		return allItems[i].PublishedAt.After(allItems[j].PublishedAt)
-	})
-
-	return allItems, nil
+	return items, nil
 }
 
 // convertToModelsItem 将参考消息条目转换为模型条目
@@ -4,18 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"sort"
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/fetcher"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
 // ClsSource 财联社数据源
 type ClsSource struct {
-	BaseSource
+	MultiURLSource
+
+	// kinds 接口地址到接口类型（telegraph、depth 或 hot）的映射，决定各部分内容的解析方式，与数据源名称无关
+	kinds map[string]string
+}
+
+// clsEndpoints 财联社各类型接口的地址
+var clsEndpoints = map[string]string{
+	"telegraph": "https://www.cls.cn/nodeapi/updateTelegraphList",
+	"depth":     "https://www.cls.cn/v3/depth/home/assembled/1000",
+	"hot":       "https://www.cls.cn/v2/article/hot/list",
 }
 
 // ClsItem 财联社数据项
@@ -68,46 +78,52 @@ func getClsSearchParams() url.Values {
 	return params
 }
 
-// NewClsTelegraphSource 创建财联社电报数据源实例
-func NewClsTelegraphSource() *ClsSource {
-	return &ClsSource{
-		BaseSource: BaseSource{
-			Name:       "cls-telegraph",
-			URL:        "https://www.cls.cn/nodeapi/updateTelegraphList",
-			Interval:   300, // 5分钟爬取一次
-			Categories: []string{"财经", "科技"},
+// newClsSource 创建请求kinds中各类型接口并合并结果的财联社数据源
+func newClsSource(name string, kinds ...string) *ClsSource {
+	s := &ClsSource{
+		MultiURLSource: MultiURLSource{
+			BaseSource: BaseSource{
+				Name:       name,
+				URL:        clsEndpoints[kinds[0]],
+				Interval:   300, // 5分钟爬取一次
+				Categories: []string{"财经", "科技"},
+			},
 		},
+		kinds: make(map[string]string, len(kinds)),
 	}
+	for _, kind := range kinds {
+		s.URLs = append(s.URLs, clsEndpoints[kind])
+		s.kinds[clsEndpoints[kind]] = kind
+	}
+	s.FetchPart = s.fetchEndpoint
+	s.ParsePart = s.parseEndpoint
+	return s
+}
+
+// NewClsTelegraphSource 创建财联社电报数据源实例
+func NewClsTelegraphSource() *ClsSource {
+	return newClsSource("cls-telegraph", "telegraph")
 }
 
 // NewClsDepthSource 创建财联社深度数据源实例
 func NewClsDepthSource() *ClsSource {
-	return &ClsSource{
-		BaseSource: BaseSource{
-			Name:       "cls-depth",
-			URL:        "https://www.cls.cn/v3/depth/home/assembled/1000",
-			Interval:   300, // 5分钟爬取一次
-			Categories: []string{"财经", "科技"},
-		},
-	}
+	return newClsSource("cls-depth", "depth")
 }
 
 // NewClsHotSource 创建财联社热点数据源实例
 func NewClsHotSource() *ClsSource {
-	return &ClsSource{
-		BaseSource: BaseSource{
-			Name:       "cls-hot",
-			URL:        "https://www.cls.cn/v2/article/hot/list",
-			Interval:   300, // 5分钟爬取一次
-			Categories: []string{"财经", "科技"},
-		},
-	}
+	return newClsSource("cls-hot", "hot")
+}
+
+// NewClsSource 创建合并电报、深度和热点三类接口的财联社数据源实例
+func NewClsSource() *ClsSource {
+	return newClsSource("cls", "telegraph", "depth", "hot")
 }
 
-// Fetch 获取财联社数据
-func (s *ClsSource) Fetch(ctx context.Context) ([]byte, error) {
+// fetchEndpoint 获取财联社单个接口的数据
+func (s *ClsSource) fetchEndpoint(ctx context.Context, endpoint string) ([]byte, error) {
 	// 创建URL并添加参数
-	u, err := url.Parse(s.URL)
+	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +132,6 @@ func (s *ClsSource) Fetch(ctx context.Context) ([]byte, error) {
 	u.RawQuery = getClsSearchParams().Encode()
 
 	// 创建HTTP请求
-	client := &http.Client{}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, err
@@ -126,7 +141,7 @@ func (s *ClsSource) Fetch(ctx context.Context) ([]byte, error) {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.114 Safari/537.36")
 
 	// 发送请求
-	resp, err := client.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -136,15 +151,15 @@ func (s *ClsSource) Fetch(ctx context.Context) ([]byte, error) {
 		return nil, fmt.Errorf("failed to fetch: %d", resp.StatusCode)
 	}
 
-	return io.ReadAll(resp.Body)
+	return fetcher.ReadBody(resp)
 }
 
-// Parse 解析财联社内容
-func (s *ClsSource) Parse(content []byte) ([]models.Item, error) {
+// parseEndpoint 按接口类型解析财联社单个接口的内容
+func (s *ClsSource) parseEndpoint(endpoint string, content []byte) ([]models.Item, error) {
 	var items []models.Item
 
-	switch s.Name {
-	case "cls-telegraph":
+	switch s.kinds[endpoint] {
+	case "telegraph":
 		var resp ClsTelegraphRes
 		if err := json.Unmarshal(content, &resp); err != nil {
 			return nil, err
@@ -169,7 +184,7 @@ func (s *ClsSource) Parse(content []byte) ([]models.Item, error) {
 			}
 		}
 
-	case "cls-depth":
+	case "depth":
 		var resp ClsDepthRes
 		if err := json.Unmarshal(content, &resp); err != nil {
 			return nil, err
@@ -196,7 +211,7 @@ func (s *ClsSource) Parse(content []byte) ([]models.Item, error) {
 			})
 		}
 
-	case "cls-hot":
+	case "hot":
 		var resp ClsHotRes
 		if err := json.Unmarshal(content, &resp); err != nil {
 			return nil, err
@@ -217,6 +232,9 @@ func (s *ClsSource) Parse(content []byte) ([]models.Item, error) {
 				PublishedAt: time.Now(),
 			})
 		}
+
+	default:
+		return nil, fmt.Errorf("unknown cls endpoint: %s", endpoint)
 	}
 
 	return items, nil
@@ -228,8 +246,6 @@ func init() {
 	RegisterSource(NewClsDepthSource())
 	RegisterSource(NewClsHotSource())
 
-	// 注册默认的cls数据源，指向telegraph
-	clsSource := NewClsTelegraphSource()
-	clsSource.Name = "cls"
-	RegisterSource(clsSource)
+	// 注册合并三类接口的cls数据源
+	RegisterSource(NewClsSource())
 }
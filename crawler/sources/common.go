@@ -1,19 +1,21 @@
 package sources
 
 import (
-	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/fetcher"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
-// readResponseBody 读取HTTP响应体
+// readResponseBody 读取HTTP响应体，解压并转换为UTF-8
 func readResponseBody(resp *http.Response) ([]byte, error) {
 	if resp.StatusCode != http.StatusOK {
 		return nil, ErrNonOkStatusCode
 	}
-	return io.ReadAll(resp.Body)
+	return fetcher.ReadBody(resp)
 }
 
 // NewBaseItem 创建基础的Item实例
@@ -30,6 +32,15 @@ func NewBaseItem(id, title, url, source string) models.Item {
 	}
 }
 
+// parseScore 解析字符串形式的热度值，解析失败时返回0
+func parseScore(value string) float64 {
+	score, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return score
+}
+
 // 定义一些常见的错误
 var (
 	ErrNonOkStatusCode = NewError("non-ok status code")
@@ -0,0 +1,135 @@
+package sources
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 所有数据源通用的配置键，由 BaseSource.Configure 处理
+const (
+	// ConfigCookie 请求时发送的 Cookie 请求头
+	ConfigCookie = "cookie"
+	// ConfigUserAgent 请求时发送的 User-Agent 请求头，为空时使用默认值
+	ConfigUserAgent = "user_agent"
+	// ConfigInterval 爬取间隔（秒）
+	ConfigInterval = "interval"
+	// ConfigTimeout 超时，使用 time.ParseDuration 的格式，例如 30s
+	ConfigTimeout = "timeout"
+)
+
+// SourceConfig 数据源的配置，例如API令牌、Cookie和数据源特有的选项
+// 通过 Registry.Configure 以编程方式或从爬虫的 schema 配置设置，
+// 数据源实现 Configurable 接口读取配置，不认识的键会被忽略
+type SourceConfig map[string]string
+
+// Configurable 可以接收 SourceConfig 的数据源实现的接口
+// 配置中没有的键应保持数据源原有的设置，值不合法时返回错误
+type Configurable interface {
+	Configure(config SourceConfig) error
+}
+
+// Has 返回配置中是否设置了key
+func (c SourceConfig) Has(key string) bool {
+	_, ok := c[key]
+	return ok
+}
+
+// String 返回key的值，未设置时返回fallback
+func (c SourceConfig) String(key, fallback string) string {
+	if value, ok := c[key]; ok {
+		return value
+	}
+	return fallback
+}
+
+// Strings 返回key中以逗号分隔的值，忽略空白项，未设置时返回fallback
+func (c SourceConfig) Strings(key string, fallback []string) []string {
+	value, ok := c[key]
+	if !ok {
+		return fallback
+	}
+
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Int 返回key的整数值，未设置时返回fallback
+func (c SourceConfig) Int(key string, fallback int) (int, error) {
+	value, ok := c[key]
+	if !ok {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fallback, fmt.Errorf("invalid integer for %s: %q", key, value)
+	}
+	return n, nil
+}
+
+// Bool 返回key的布尔值，支持 strconv.ParseBool 的写法，未设置时返回fallback
+func (c SourceConfig) Bool(key string, fallback bool) (bool, error) {
+	value, ok := c[key]
+	if !ok {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return fallback, fmt.Errorf("invalid boolean for %s: %q", key, value)
+	}
+	return b, nil
+}
+
+// Duration 返回key的时长，使用 time.ParseDuration 的格式，未设置时返回fallback
+func (c SourceConfig) Duration(key string, fallback time.Duration) (time.Duration, error) {
+	value, ok := c[key]
+	if !ok {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return fallback, fmt.Errorf("invalid duration for %s: %q", key, value)
+	}
+	return d, nil
+}
+
+// Clone 返回配置的副本
+func (c SourceConfig) Clone() SourceConfig {
+	if c == nil {
+		return nil
+	}
+	return maps.Clone(c)
+}
+
+// Configure 应用所有数据源通用的配置：cookie、user_agent、interval 和 timeout
+// cookie 和 user_agent 只对使用 BaseSource.FetchURL 发起请求的数据源生效；
+// 数据源实现自己的 Configure 时应先调用此方法
+func (s *BaseSource) Configure(config SourceConfig) error {
+	interval, err := config.Int(ConfigInterval, s.Interval)
+	if err != nil {
+		return err
+	}
+	if interval < 0 {
+		return fmt.Errorf("invalid interval: %d", interval)
+	}
+	timeout, err := config.Duration(ConfigTimeout, s.Timeout)
+	if err != nil {
+		return err
+	}
+	if timeout < 0 {
+		return fmt.Errorf("invalid timeout: %s", timeout)
+	}
+
+	s.Interval = interval
+	s.Timeout = timeout
+	s.Cookie = config.String(ConfigCookie, s.Cookie)
+	s.UserAgent = config.String(ConfigUserAgent, s.UserAgent)
+	return nil
+}
@@ -101,7 +101,6 @@ func NewCoolapkSource() *CoolapkSource {
 // Fetch 获取酷安数据
 func (s *CoolapkSource) Fetch(ctx context.Context) ([]byte, error) {
 	// 创建HTTP请求
-	client := &http.Client{}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
 	if err != nil {
 		return nil, err
@@ -116,7 +115,7 @@ func (s *CoolapkSource) Fetch(ctx context.Context) ([]byte, error) {
 	}
 
 	// 发送请求
-	resp, err := client.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
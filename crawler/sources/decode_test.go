@@ -0,0 +1,79 @@
+package sources_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/sjzsdu/utils/crawler/sources"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestFetchDecodesContent(t *testing.T) {
+	const text = "<title>联合早报</title>"
+	gbk, err := simplifiedchinese.GBK.NewEncoder().String(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metaGBK, err := simplifiedchinese.GBK.NewEncoder().String(`<meta charset="gb2312">` + text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte(gbk))
+	gz.Close()
+
+	var compressed bytes.Buffer
+	br := brotli.NewWriter(&compressed)
+	br.Write([]byte(text))
+	br.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gzip-gbk":
+			w.Header().Set("Content-Type", "text/html; charset=GBK")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipped.Bytes())
+		case "/brotli":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Content-Encoding", "br")
+			w.Write(compressed.Bytes())
+		case "/meta":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(metaGBK))
+		case "/undeclared":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(gbk))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/gzip-gbk", text},
+		{"/brotli", text},
+		{"/meta", `<meta charset="gb2312">` + text},
+		{"/undeclared", text},
+	}
+
+	source := &sources.BaseSource{Name: "decode"}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			content, err := source.FetchURL(context.Background(), server.URL+tt.path)
+			if err != nil {
+				t.Fatalf("FetchURL failed: %v", err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, content)
+			}
+		})
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/fetcher"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
@@ -72,20 +72,13 @@ func (s *DoubanSource) Fetch(ctx context.Context) ([]byte, error) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 
-	client := s.Client
-	if client == nil {
-		client = &http.Client{
-			Timeout: 10 * time.Second,
-		}
-	}
-
-	resp, err := client.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
+	return fetcher.ReadBody(resp)
 }
 
 // Parse 解析豆瓣热门电影内容
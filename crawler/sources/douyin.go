@@ -3,10 +3,10 @@ package sources
 import (
 	"context"
 	"encoding/json"
-	"io"
 	"net/http"
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/fetcher"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
@@ -45,11 +45,6 @@ func NewDouyinSource() *DouyinSource {
 
 // Fetch 获取抖音热门搜索数据
 func (s *DouyinSource) Fetch(ctx context.Context) ([]byte, error) {
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	// 创建请求
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
 	if err != nil {
@@ -74,13 +69,13 @@ func (s *DouyinSource) Fetch(ctx context.Context) ([]byte, error) {
 	req.Header.Set("Referer", "https://www.douyin.com/")
 
 	// 发送请求
-	resp, err := client.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
+	return fetcher.ReadBody(resp)
 }
 
 // Parse 解析抖音热门搜索内容
@@ -101,6 +96,7 @@ func (s *DouyinSource) Parse(content []byte) ([]models.Item, error) {
 			Title:       word.Word,
 			URL:         "https://www.douyin.com/hot/" + word.SentenceID,
 			Source:      s.Name,
+			Score:       parseScore(word.HotValue),
 			CreatedAt:   time.Now(),
 			PublishedAt: time.Now(),
 		})
@@ -119,17 +119,15 @@ func (s *KuaishouSource) Parse(content []byte) ([]models.Item, error) {
 		// 构建URL
 		searchURL := "https://www.kuaishou.com/search/video?searchKey=" + url.QueryEscape(name)
 
-		// 构建extra字段
-		extra := make(map[string]interface{})
-		if iconURL, ok := hotItem["iconUrl"].(string); ok && iconURL != "" {
-			extra["icon"] = iconURL
-		}
+		// 热榜图标
+		iconURL, _ := hotItem["iconUrl"].(string)
 
 		items = append(items, models.Item{
 			ID:          hotSearchWord,
 			Title:       name,
 			URL:         searchURL,
 			Source:      s.Name,
+			ImageURL:    iconURL,
 			CreatedAt:   time.Now(),
 			PublishedAt: time.Now(),
 		})
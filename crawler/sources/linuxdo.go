@@ -3,11 +3,11 @@ package sources
 import (
 	"context"
 	"encoding/json"
-	"io"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/fetcher"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
@@ -37,6 +37,22 @@ type LinuxdoTopic struct {
 	PinnedGlobally     bool      `json:"pinned_globally"`
 }
 
+// imageURL 返回话题的封面图片链接
+func (t LinuxdoTopic) imageURL() string {
+	if t.ImageURL == nil {
+		return ""
+	}
+	return *t.ImageURL
+}
+
+// extra 返回话题的回复数和帖子数
+func (t LinuxdoTopic) extra() map[string]any {
+	return map[string]any{
+		"reply_count": t.ReplyCount,
+		"posts_count": t.PostsCount,
+	}
+}
+
 // LinuxdoResponse LinuxDo响应
 type LinuxdoResponse struct {
 	TopicList struct {
@@ -95,20 +111,13 @@ func (s *LinuxdoHotSource) Fetch(ctx context.Context) ([]byte, error) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9")
 
-	client := s.Client
-	if client == nil {
-		client = &http.Client{
-			Timeout: 10 * time.Second,
-		}
-	}
-
-	resp, err := client.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
+	return fetcher.ReadBody(resp)
 }
 
 // Parse 解析LinuxDo热门话题内容
@@ -138,6 +147,9 @@ func (s *LinuxdoHotSource) Parse(content []byte) ([]models.Item, error) {
 			Title:       topic.Title,
 			URL:         "https://linux.do/t/topic/" + strconv.Itoa(topic.ID),
 			Source:      s.Name,
+			Score:       float64(topic.LikeCount),
+			ImageURL:    topic.imageURL(),
+			Extra:       topic.extra(),
 			CreatedAt:   time.Now(),
 			PublishedAt: time.Now(),
 		})
@@ -166,6 +178,9 @@ func (s *LinuxdoLatestSource) Parse(content []byte) ([]models.Item, error) {
 			Title:       topic.Title,
 			URL:         "https://linux.do/t/topic/" + strconv.Itoa(topic.ID),
 			Source:      s.Name,
+			Score:       float64(topic.LikeCount),
+			ImageURL:    topic.imageURL(),
+			Extra:       topic.extra(),
 			CreatedAt:   time.Now(),
 			PublishedAt: topic.CreatedAt,
 		})
@@ -0,0 +1,61 @@
+package sources
+
+// sourceMetadata 数据源在界面中展示的信息
+type sourceMetadata struct {
+	description string
+	homepage    string
+	country     string
+	language    string
+}
+
+// builtinMetadata 内置数据源的展示信息，按数据源名称索引；BaseSource 中设置的字段优先
+var builtinMetadata = map[string]sourceMetadata{
+	"36kr":             {"36氪快讯，科技与创投领域的实时资讯", "https://36kr.com/newsflashes", "CN", "zh"},
+	"arxiv":            {"arXiv 预印本论文的最新提交", "https://arxiv.org/", "US", "en"},
+	"baidu":            {"百度热搜榜", "https://top.baidu.com/board?tab=realtime", "CN", "zh"},
+	"bilibili":         {"哔哩哔哩热搜", "https://www.bilibili.com/", "CN", "zh"},
+	"cankaoxiaoxi":     {"参考消息的中国、观点和国际频道", "https://www.cankaoxiaoxi.com/", "CN", "zh"},
+	"chongbuluo":       {"虫部落论坛的热门帖子", "https://www.chongbuluo.com/", "CN", "zh"},
+	"cls":              {"财联社电报、深度和热门文章", "https://www.cls.cn/", "CN", "zh"},
+	"cls-telegraph":    {"财联社电报，财经市场的实时快讯", "https://www.cls.cn/telegraph", "CN", "zh"},
+	"cls-depth":        {"财联社深度文章", "https://www.cls.cn/depth", "CN", "zh"},
+	"cls-hot":          {"财联社热门文章", "https://www.cls.cn/", "CN", "zh"},
+	"coolapk":          {"酷安今日热门动态", "https://www.coolapk.com/", "CN", "zh"},
+	"douban":           {"豆瓣近期热门电影", "https://movie.douban.com/", "CN", "zh"},
+	"douyin":           {"抖音热榜", "https://www.douyin.com/", "CN", "zh"},
+	"fastbull":         {"快牛财经快讯", "https://www.fastbull.com/cn/express-news", "CN", "zh"},
+	"fastbull-express": {"快牛财经快讯", "https://www.fastbull.com/cn/express-news", "CN", "zh"},
+	"fastbull-news":    {"快牛财经新闻", "https://www.fastbull.com/cn/news", "CN", "zh"},
+	"gelonghui":        {"格隆汇财经资讯", "https://www.gelonghui.com/", "CN", "zh"},
+	"ghxi":             {"果核剥壳的软件和技术文章", "https://www.ghxi.com/", "CN", "zh"},
+	"github":           {"GitHub Trending 热门仓库", "https://github.com/trending", "US", "en"},
+	"hackernews":       {"Hacker News 首页的科技与创业讨论", "https://news.ycombinator.com/", "US", "en"},
+	"hupu":             {"虎扑热帖", "https://www.hupu.com/", "CN", "zh"},
+	"ifeng":            {"凤凰网热点新闻", "https://www.ifeng.com/", "CN", "zh"},
+	"ithome":           {"IT之家科技资讯", "https://www.ithome.com/", "CN", "zh"},
+	"jin10":            {"金十数据的财经快讯", "https://www.jin10.com/", "CN", "zh"},
+	"juejin":           {"稀土掘金的热门技术文章", "https://juejin.cn/hot/articles", "CN", "zh"},
+	"kaopu":            {"叩谱汇总的国际媒体新闻", "https://www.kaopu001.com/", "", "zh"},
+	"kuaishou":         {"快手热榜", "https://www.kuaishou.com/", "CN", "zh"},
+	"linuxdo":          {"LINUX DO 社区的最新话题", "https://linux.do/latest", "CN", "zh"},
+	"linuxdo-hot":      {"LINUX DO 社区的今日热门话题", "https://linux.do/top", "CN", "zh"},
+	"linuxdo-latest":   {"LINUX DO 社区的最新话题", "https://linux.do/latest", "CN", "zh"},
+	"mktnews":          {"MKTNews 财经快讯", "https://mktnews.net/", "", "zh"},
+	"nowcoder":         {"牛客网热门讨论", "https://www.nowcoder.com/", "CN", "zh"},
+	"pcbeta":           {"远景论坛的 Windows 相关帖子", "https://bbs.pcbeta.com/", "CN", "zh"},
+	"producthunt":      {"Product Hunt 每日热门产品", "https://www.producthunt.com/", "US", "en"},
+	"smzdm":            {"什么值得买的热门文章", "https://post.smzdm.com/", "CN", "zh"},
+	"solidot":          {"Solidot 科技资讯", "https://www.solidot.org/", "CN", "zh"},
+	"sputniknewscn":    {"俄罗斯卫星通讯社中文网的最新新闻", "https://sputniknews.cn/", "RU", "zh"},
+	"sspai":            {"少数派的热门文章", "https://sspai.com/", "CN", "zh"},
+	"steam":            {"Steam 商店的热门游戏", "https://store.steampowered.com/", "US", "en"},
+	"telegram":         {"Telegram 公开频道的消息", "https://telegram.org/", "", ""},
+	"tencent-hot":      {"腾讯新闻热点", "https://news.qq.com/", "CN", "zh"},
+	"tieba":            {"百度贴吧热议话题", "https://tieba.baidu.com/", "CN", "zh"},
+	"toutiao":          {"今日头条热榜", "https://www.toutiao.com/", "CN", "zh"},
+	"v2ex":             {"V2EX 社区的热门话题", "https://www.v2ex.com/", "CN", "zh"},
+	"weibo":            {"微博热搜榜", "https://s.weibo.com/top/summary", "CN", "zh"},
+	"xueqiu":           {"雪球热门讨论", "https://xueqiu.com/", "CN", "zh"},
+	"zaobao":           {"联合早报的即时新闻", "https://www.zaobao.com/", "SG", "zh"},
+	"zhihu":            {"知乎热榜", "https://www.zhihu.com/hot", "CN", "zh"},
+}
@@ -0,0 +1,93 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sjzsdu/utils/coroutine"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// MultiURLSource 需要请求多个URL的数据源基础实现，例如按频道分别提供接口的站点
+// Fetch 通过协程池并行获取全部URL，将各URL的内容合并为一份原始内容；
+// Parse 用 ParsePart 逐个解析后合并结果，并按ID去重
+type MultiURLSource struct {
+	BaseSource
+	URLs []string
+	// MaxWorkers 并行请求数，为0时同时请求全部URL
+	MaxWorkers int
+	// FetchPart 获取单个URL的内容，为nil时使用 BaseSource.FetchURL
+	FetchPart func(ctx context.Context, url string) ([]byte, error)
+	// ParsePart 解析单个URL的内容
+	ParsePart func(url string, content []byte) ([]models.Item, error)
+}
+
+// multiURLPart 合并内容中单个URL的部分
+type multiURLPart struct {
+	URL     string `json:"url"`
+	Content string `json:"content"`
+}
+
+// Fetch 并行获取全部URL的内容，部分URL失败时忽略失败的部分，全部失败时返回错误
+// 返回的内容是由URL和对应内容组成的JSON数组，录制和测试夹具也使用这个格式
+func (s *MultiURLSource) Fetch(ctx context.Context) ([]byte, error) {
+	fetch := s.FetchPart
+	if fetch == nil {
+		fetch = s.FetchURL
+	}
+	workers := s.MaxWorkers
+	if workers <= 0 {
+		workers = len(s.URLs)
+	}
+
+	results := coroutine.Map(ctx, workers, s.URLs, func(url string) ([]byte, error) {
+		return fetch(ctx, url)
+	})
+
+	parts := make([]multiURLPart, 0, len(results))
+	var errs []error
+	for _, result := range results {
+		url := s.URLs[result.Index]
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("fetch %s: %w", url, result.Err))
+			continue
+		}
+		parts = append(parts, multiURLPart{URL: url, Content: string(result.Value)})
+	}
+	if len(parts) == 0 {
+		if len(errs) == 0 {
+			return nil, ErrEmptyContent
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	return json.Marshal(parts)
+}
+
+// Parse 解析 Fetch 返回的合并内容，按URL顺序合并各部分的解析结果，ID相同的条目只保留第一个
+func (s *MultiURLSource) Parse(content []byte) ([]models.Item, error) {
+	var parts []multiURLPart
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.Item, 0)
+	seen := make(map[string]bool)
+	for _, part := range parts {
+		partItems, err := s.ParsePart(part.URL, []byte(part.Content))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", part.URL, err)
+		}
+		for _, item := range partItems {
+			if seen[item.ID] {
+				continue
+			}
+			seen[item.ID] = true
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,66 @@
+package sources_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/crawler/sources"
+)
+
+func TestMultiURLSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			w.Write([]byte("1,2"))
+		case "/b":
+			w.Write([]byte("2,3"))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	source := &sources.MultiURLSource{
+		BaseSource: sources.BaseSource{Name: "multi"},
+		URLs:       []string{server.URL + "/a", server.URL + "/broken", server.URL + "/b"},
+		ParsePart: func(url string, content []byte) ([]models.Item, error) {
+			var items []models.Item
+			for _, id := range content {
+				if id != ',' {
+					items = append(items, models.Item{ID: string(id), URL: url})
+				}
+			}
+			return items, nil
+		},
+	}
+
+	content, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	items, err := source.Parse(content)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := []string{"1", "2", "3"}
+	if len(items) != len(want) {
+		t.Fatalf("Expected %d items, got %d", len(want), len(items))
+	}
+	for i, id := range want {
+		if items[i].ID != id {
+			t.Errorf("Expected item %d to be %s, got %s", i, id, items[i].ID)
+		}
+	}
+	if items[1].URL != server.URL+"/a" {
+		t.Errorf("Expected duplicate item to come from the first URL, got %s", items[1].URL)
+	}
+
+	source.URLs = []string{server.URL + "/broken"}
+	if _, err := source.Fetch(context.Background()); err == nil {
+		t.Error("Expected error when all URLs fail")
+	}
+}
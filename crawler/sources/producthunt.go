@@ -2,6 +2,7 @@ package sources
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -13,10 +14,13 @@ import (
 
 // ProducthuntSource Product Hunt数据源
 // 该数据源从Product Hunt获取热门产品
-// 注意：需要设置环境变量PRODUCTHUNT_API_TOKEN
+// 注意：需要通过 api_token 配置或环境变量PRODUCTHUNT_API_TOKEN设置API令牌
 
 type ProducthuntSource struct {
 	BaseSource
+
+	// APIToken API令牌，为空时读取环境变量PRODUCTHUNT_API_TOKEN
+	APIToken string
 }
 
 // ProducthuntPost Product Hunt产品项
@@ -61,9 +65,18 @@ func NewProducthuntSource() *ProducthuntSource {
 	}
 }
 
+// Configure 应用通用配置以及 api_token
+func (s *ProducthuntSource) Configure(config SourceConfig) error {
+	if err := s.BaseSource.Configure(config); err != nil {
+		return err
+	}
+	s.APIToken = config.String("api_token", s.APIToken)
+	return nil
+}
+
 // Fetch 获取Product Hunt热门产品数据
 func (s *ProducthuntSource) Fetch(ctx context.Context) ([]byte, error) {
-	apiToken := os.Getenv("PRODUCTHUNT_API_TOKEN")
+	apiToken := cmp.Or(s.APIToken, os.Getenv("PRODUCTHUNT_API_TOKEN"))
 	if apiToken == "" {
 		return nil, nil // 如果没有API令牌，返回空结果
 	}
@@ -94,7 +107,6 @@ func (s *ProducthuntSource) Fetch(ctx context.Context) ([]byte, error) {
 	}
 
 	// 创建HTTP请求
-	client := &http.Client{}
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewBuffer(requestBody))
 	if err != nil {
 		return nil, err
@@ -106,7 +118,7 @@ func (s *ProducthuntSource) Fetch(ctx context.Context) ([]byte, error) {
 	req.Header.Set("Accept", "application/json")
 
 	// 发送请求
-	resp, err := client.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,171 @@
+package sources
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// DefaultRSSHubInstance 未配置时使用的RSSHub公共实例
+const DefaultRSSHubInstance = "https://rsshub.app"
+
+// RSSHubSource RSSHub数据源
+// 该数据源读取RSSHub实例中指定路由输出的RSS，不需要为每个网站单独编写解析器；
+// 实例地址默认读取环境变量 RSSHUB_URL，私有实例的访问密钥默认读取环境变量 RSSHUB_ACCESS_KEY，
+// 也可以通过 instance、route 和 access_key 配置设置。
+// RSSHub数据源不会自动注册，需要按路由创建后自行注册：
+//
+//	sources.RegisterSource(sources.NewRSSHubSource("github-trending", "/github/trending/daily/go"))
+type RSSHubSource struct {
+	BaseSource
+
+	// Instance RSSHub实例地址
+	Instance string
+	// Route RSSHub路由，例如 /github/trending/daily/go
+	Route string
+	// AccessKey 私有实例的访问密钥，作为 key 参数发送
+	AccessKey string
+}
+
+// RSSHubOption RSSHub数据源的配置选项
+type RSSHubOption func(*RSSHubSource)
+
+// WithRSSHubInstance 设置RSSHub实例地址
+func WithRSSHubInstance(instance string) RSSHubOption {
+	return func(s *RSSHubSource) {
+		s.Instance = instance
+	}
+}
+
+// WithRSSHubAccessKey 设置私有实例的访问密钥
+func WithRSSHubAccessKey(accessKey string) RSSHubOption {
+	return func(s *RSSHubSource) {
+		s.AccessKey = accessKey
+	}
+}
+
+// WithRSSHubInterval 设置爬取间隔（秒）
+func WithRSSHubInterval(interval int) RSSHubOption {
+	return func(s *RSSHubSource) {
+		s.Interval = interval
+	}
+}
+
+// WithRSSHubCategories 设置数据源的分类
+func WithRSSHubCategories(categories ...string) RSSHubOption {
+	return func(s *RSSHubSource) {
+		s.Categories = categories
+	}
+}
+
+// NewRSSHubSource 创建读取指定路由的RSSHub数据源实例，name为数据源名称
+func NewRSSHubSource(name, route string, opts ...RSSHubOption) *RSSHubSource {
+	s := &RSSHubSource{
+		BaseSource: BaseSource{
+			Name:       name,
+			Interval:   1800, // 30分钟爬取一次，RSSHub默认缓存路由结果
+			Categories: []string{"综合"},
+		},
+		Instance:  os.Getenv("RSSHUB_URL"),
+		Route:     route,
+		AccessKey: os.Getenv("RSSHUB_ACCESS_KEY"),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.Instance == "" {
+		s.Instance = DefaultRSSHubInstance
+	}
+	return s
+}
+
+// Configure 应用通用配置以及 instance、route 和 access_key
+func (s *RSSHubSource) Configure(config SourceConfig) error {
+	if err := s.BaseSource.Configure(config); err != nil {
+		return err
+	}
+	s.Instance = cmp.Or(config.String("instance", s.Instance), DefaultRSSHubInstance)
+	s.Route = config.String("route", s.Route)
+	s.AccessKey = config.String("access_key", s.AccessKey)
+	return nil
+}
+
+// GetURL 返回路由对应的RSS地址，不包含访问密钥
+func (s *RSSHubSource) GetURL() string {
+	return strings.TrimSuffix(s.Instance, "/") + "/" + strings.TrimPrefix(s.Route, "/")
+}
+
+// Fetch 获取路由输出的RSS
+func (s *RSSHubSource) Fetch(ctx context.Context) ([]byte, error) {
+	feedURL := s.GetURL()
+	if s.AccessKey != "" {
+		u, err := url.Parse(feedURL)
+		if err != nil {
+			return nil, err
+		}
+		query := u.Query()
+		query.Set("key", s.AccessKey)
+		u.RawQuery = query.Encode()
+		feedURL = u.String()
+	}
+
+	return s.FetchURL(ctx, feedURL)
+}
+
+// Parse 解析RSS内容，正文中的HTML转换为纯文本，图片保存在 Images 中
+func (s *RSSHubSource) Parse(content []byte) ([]models.Item, error) {
+	var feed RSSFeed
+	if err := xml.Unmarshal(content, &feed); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.Item, 0, len(feed.Channel.Items))
+	for _, rssItem := range feed.Channel.Items {
+		id := rssItem.GUID
+		if id == "" {
+			id = rssItem.Link
+		}
+		if id == "" || rssItem.Title == "" {
+			continue
+		}
+
+		item := NewBaseItem(id, strings.TrimSpace(rssItem.Title), rssItem.Link, s.Name)
+		item.Content = strings.TrimSpace(htmlToText(rssItem.Description))
+		item.Images = rssImages(rssItem.Description)
+		if len(item.Images) > 0 {
+			item.ImageURL = item.Images[0]
+		}
+		for _, layout := range []string{time.RFC1123, time.RFC1123Z} {
+			if pubTime, err := time.Parse(layout, rssItem.PubDate); err == nil {
+				item.PublishedAt = pubTime
+				break
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// rssImages 提取RSS正文HTML中的图片地址
+func rssImages(html string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+
+	var images []string
+	doc.Find("img[src]").Each(func(i int, img *goquery.Selection) {
+		if src, _ := img.Attr("src"); src != "" {
+			images = append(images, src)
+		}
+	})
+	return images
+}
@@ -2,15 +2,24 @@ package sources
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/taxonomy"
 )
 
 // Registry 是数据源注册表
 type Registry struct {
 	sources map[string]crawler.Source
 	mu      sync.RWMutex
+
+	// allowUnknownCategories 为true时不校验数据源的分类
+	allowUnknownCategories bool
+
+	// configs 通过 Configure 设置的数据源配置
+	configs map[string]SourceConfig
 }
 
 // registry 是全局数据源注册表实例
@@ -19,17 +28,24 @@ var (
 	once     sync.Once
 )
 
+// NewRegistry 创建空的数据源注册表，内置数据源只注册在 GetRegistry 返回的全局注册表中
+func NewRegistry() *Registry {
+	return &Registry{
+		sources: make(map[string]crawler.Source),
+		configs: make(map[string]SourceConfig),
+	}
+}
+
 // GetRegistry 获取全局数据源注册表实例
 func GetRegistry() *Registry {
 	once.Do(func() {
-		registry = &Registry{
-			sources: make(map[string]crawler.Source),
-		}
+		registry = NewRegistry()
 	})
 	return registry
 }
 
-// Register 注册数据源
+// Register 注册数据源，数据源的分类必须已在 taxonomy 中注册，
+// 使用自定义分类时先通过 taxonomy.Register 添加，或者调用 AllowUnknownCategories 关闭校验
 func (r *Registry) Register(source crawler.Source) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -38,11 +54,23 @@ func (r *Registry) Register(source crawler.Source) error {
 	if _, exists := r.sources[name]; exists {
 		return fmt.Errorf("source %s already registered", name)
 	}
+	if !r.allowUnknownCategories {
+		if err := taxonomy.Validate(source.GetCategories()); err != nil {
+			return fmt.Errorf("source %s: %w", name, err)
+		}
+	}
 
 	r.sources[name] = source
 	return nil
 }
 
+// AllowUnknownCategories 设置是否允许注册使用未知分类的数据源，默认不允许
+func (r *Registry) AllowUnknownCategories(allow bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.allowUnknownCategories = allow
+}
+
 // Get 获取数据源
 func (r *Registry) Get(name string) (crawler.Source, error) {
 	r.mu.RLock()
@@ -56,6 +84,67 @@ func (r *Registry) Get(name string) (crawler.Source, error) {
 	return source, nil
 }
 
+// Configure 为已注册的数据源设置配置，例如API令牌、Cookie和数据源特有的选项
+// 数据源需要实现 Configurable 接口，配置中没有的键保持数据源原有的设置；
+// 配置应用成功后保存在注册表中，可以通过 Config 读取
+func (r *Registry) Configure(name string, config map[string]string) error {
+	source, err := r.Get(name)
+	if err != nil {
+		return err
+	}
+
+	sourceConfig := SourceConfig(config).Clone()
+	if err := Configure(source, sourceConfig); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[name] = sourceConfig
+	return nil
+}
+
+// Configure 为数据源应用配置，不经过注册表，数据源需要实现 Configurable 接口
+// 常与 Clone 一起使用，为副本设置配置而不影响注册表中的数据源
+func Configure(source crawler.Source, config SourceConfig) error {
+	configurable, ok := source.(Configurable)
+	if !ok {
+		return fmt.Errorf("source %s does not accept configuration", source.GetName())
+	}
+	if err := configurable.Configure(config); err != nil {
+		return fmt.Errorf("source %s: %w", source.GetName(), err)
+	}
+	return nil
+}
+
+// Cloner 可以复制自身的数据源实现的接口，含有锁等不能直接复制的字段的数据源需要实现
+type Cloner interface {
+	Clone() crawler.Source
+}
+
+// Clone 返回数据源的副本，修改副本的配置和HTTP客户端不影响原数据源，例如为每个爬取引擎单独配置注册表中的数据源
+// 数据源实现了 Cloner 时使用其 Clone，否则复制指针指向的结构体；不是结构体指针的数据源返回错误
+func Clone(source crawler.Source) (crawler.Source, error) {
+	if cloner, ok := source.(Cloner); ok {
+		return cloner.Clone(), nil
+	}
+
+	value := reflect.ValueOf(source)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("source %s cannot be cloned", source.GetName())
+	}
+	clone := reflect.New(value.Elem().Type())
+	clone.Elem().Set(value.Elem())
+	return clone.Interface().(crawler.Source), nil
+}
+
+// Config 返回通过 Configure 为数据源设置的配置的副本，没有设置时返回nil
+func (r *Registry) Config(name string) SourceConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.configs[name].Clone()
+}
+
 // List 列出所有数据源
 func (r *Registry) List() []crawler.Source {
 	r.mu.RLock()
@@ -69,7 +158,21 @@ func (r *Registry) List() []crawler.Source {
 	return sources
 }
 
-// GetByCategory 根据类别获取数据源列表
+// ListMetadata 列出所有数据源的展示信息，按名称排序
+func (r *Registry) ListMetadata() []crawler.SourceMetadata {
+	sources := r.List()
+	metadata := make([]crawler.SourceMetadata, len(sources))
+	for i, source := range sources {
+		metadata[i] = crawler.Metadata(source)
+	}
+
+	sort.Slice(metadata, func(i, j int) bool {
+		return metadata[i].Name < metadata[j].Name
+	})
+	return metadata
+}
+
+// GetByCategory 根据类别获取数据源列表，类别可以是分类的规范ID、显示名称或别名
 func (r *Registry) GetByCategory(category string) []crawler.Source {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -77,7 +180,7 @@ func (r *Registry) GetByCategory(category string) []crawler.Source {
 	var result []crawler.Source
 	for _, source := range r.sources {
 		for _, c := range source.GetCategories() {
-			if c == category {
+			if taxonomy.Match(c, category) {
 				result = append(result, source)
 				break
 			}
@@ -87,7 +190,7 @@ func (r *Registry) GetByCategory(category string) []crawler.Source {
 	return result
 }
 
-// GetByCategories 根据多个类别获取数据源列表
+// GetByCategories 根据多个类别获取数据源列表，类别可以是分类的规范ID、显示名称或别名
 func (r *Registry) GetByCategories(categories []string) []crawler.Source {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -95,7 +198,7 @@ func (r *Registry) GetByCategories(categories []string) []crawler.Source {
 	// 创建类别映射，方便查找
 	categoryMap := make(map[string]bool)
 	for _, category := range categories {
-		categoryMap[category] = true
+		categoryMap[taxonomy.Key(category)] = true
 	}
 
 	// 用于去重的source名称映射
@@ -104,7 +207,7 @@ func (r *Registry) GetByCategories(categories []string) []crawler.Source {
 
 	for _, source := range r.sources {
 		for _, c := range source.GetCategories() {
-			if categoryMap[c] && !seenSources[source.GetName()] {
+			if categoryMap[taxonomy.Key(c)] && !seenSources[source.GetName()] {
 				result = append(result, source)
 				seenSources[source.GetName()] = true
 				break
@@ -136,3 +239,8 @@ func (r *Registry) GetSources(names []string) ([]crawler.Source, error) {
 func RegisterSource(source crawler.Source) error {
 	return GetRegistry().Register(source)
 }
+
+// ConfigureSource 为全局注册表中的数据源设置配置
+func ConfigureSource(name string, config map[string]string) error {
+	return GetRegistry().Configure(name, config)
+}
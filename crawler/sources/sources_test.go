@@ -0,0 +1,156 @@
+package sources_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/sources"
+	"github.com/sjzsdu/utils/crawler/sources/sourcetest"
+)
+
+// TestParseFixtures 用 testdata 中的夹具检查每个已注册数据源的解析结果，新增数据源时需要同时添加夹具和黄金文件
+func TestParseFixtures(t *testing.T) {
+	registered := sources.GetRegistry().List()
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].GetName() < registered[j].GetName()
+	})
+
+	for _, source := range registered {
+		name := source.GetName()
+		t.Run(name, func(t *testing.T) {
+			fixture, ok := sourcetest.FindFixture("testdata", name)
+			if !ok {
+				t.Fatalf("No fixture for source %s in testdata", name)
+			}
+			sourcetest.AssertParse(t, source, fixture)
+		})
+	}
+}
+
+// TestParseRSSHubFixture RSSHub数据源按路由动态创建、不在注册表中，单独检查其解析结果
+func TestParseRSSHubFixture(t *testing.T) {
+	source := sources.NewRSSHubSource("rsshub", "/github/trending/daily/go")
+	sourcetest.AssertParse(t, source, "testdata/rsshub.xml")
+}
+
+// TestMetadata 检查每个已注册数据源都有简介、首页和语言信息
+func TestMetadata(t *testing.T) {
+	for _, meta := range sources.GetRegistry().ListMetadata() {
+		if meta.Description == "" || meta.Homepage == "" {
+			t.Errorf("Source %s has no description or homepage", meta.Name)
+		}
+		if meta.Language == "" && meta.Name != "telegram" {
+			t.Errorf("Source %s has no language", meta.Name)
+		}
+	}
+
+	source := sources.NewRSSHubSource("rsshub", "/github/trending/daily/go")
+	source.Language = "en"
+	meta := crawler.Metadata(crawler.NewRecordingSource(source, t.TempDir()))
+	if meta.Language != "en" {
+		t.Errorf("Expected language of wrapped source, got %q", meta.Language)
+	}
+}
+
+func TestRegistryCategories(t *testing.T) {
+	registry := sources.NewRegistry()
+
+	source := sources.NewRSSHubSource("taxonomy-test", "/github/trending/daily/go")
+	source.Categories = []string{"科技", "taxonomy-test"}
+	if err := registry.Register(source); err == nil {
+		t.Errorf("Expected error registering source with unknown category")
+	}
+
+	registry.AllowUnknownCategories(true)
+	if err := registry.Register(source); err != nil {
+		t.Fatalf("Failed to register source with unknown categories allowed: %v", err)
+	}
+
+	// 规范ID、英文名称和中文名称都能匹配到使用中文分类的数据源
+	for _, category := range []string{"tech", "Technology", "科技"} {
+		found := false
+		for _, s := range registry.GetByCategory(category) {
+			found = found || s.GetName() == "taxonomy-test"
+		}
+		if !found {
+			t.Errorf("Expected GetByCategory(%q) to include taxonomy-test", category)
+		}
+	}
+}
+
+func TestRegistryConfigure(t *testing.T) {
+	var cookie, userAgent, key string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, userAgent, key = r.Header.Get("Cookie"), r.Header.Get("User-Agent"), r.URL.Query().Get("key")
+		w.Write([]byte(`<rss><channel></channel></rss>`))
+	}))
+	defer server.Close()
+
+	registry := sources.NewRegistry()
+	source := sources.NewRSSHubSource("config-test", "/feed", sources.WithRSSHubCategories("科技"))
+	if err := registry.Register(source); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+
+	config := map[string]string{
+		"instance":              server.URL,
+		"access_key":            "secret",
+		sources.ConfigCookie:    "session=1",
+		sources.ConfigUserAgent: "config-test",
+		sources.ConfigTimeout:   "5s",
+	}
+	if err := registry.Configure("config-test", config); err != nil {
+		t.Fatalf("Failed to configure source: %v", err)
+	}
+	if source.GetTimeout() != 5*time.Second || source.GetInterval() != 1800 {
+		t.Errorf("Expected timeout 5s and unchanged interval, got %s and %d", source.GetTimeout(), source.GetInterval())
+	}
+	if _, err := source.Fetch(context.Background()); err != nil {
+		t.Fatalf("Failed to fetch: %v", err)
+	}
+	if cookie != "session=1" || userAgent != "config-test" || key != "secret" {
+		t.Errorf("Expected configured cookie, user agent and access key, got %q, %q and %q", cookie, userAgent, key)
+	}
+
+	saved := registry.Config("config-test")
+	saved["instance"] = "changed"
+	if registry.Config("config-test")["instance"] != server.URL {
+		t.Errorf("Expected Config to return a copy")
+	}
+
+	if err := registry.Configure("config-test", map[string]string{sources.ConfigInterval: "soon"}); err == nil {
+		t.Errorf("Expected error for invalid interval")
+	}
+	if registry.Config("config-test")["instance"] != server.URL {
+		t.Errorf("Expected failed configuration not to be saved")
+	}
+	if err := registry.Configure("missing-source", config); err == nil {
+		t.Errorf("Expected error configuring unknown source")
+	}
+}
+
+func TestSourceConfig(t *testing.T) {
+	config := sources.SourceConfig{"channels": " a, ,b ", "enabled": "true", "count": "x"}
+
+	if got := config.Strings("channels", nil); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", got)
+	}
+	if got := config.Strings("missing", []string{"c"}); !slices.Equal(got, []string{"c"}) {
+		t.Errorf("Expected fallback [c], got %v", got)
+	}
+	if got, err := config.Bool("enabled", false); err != nil || !got {
+		t.Errorf("Expected true, got %v (%v)", got, err)
+	}
+	if got, err := config.Int("count", 3); err == nil || got != 3 {
+		t.Errorf("Expected error and fallback 3, got %d (%v)", got, err)
+	}
+	if got, err := config.Duration("missing", time.Minute); err != nil || got != time.Minute {
+		t.Errorf("Expected fallback 1m, got %s (%v)", got, err)
+	}
+}
@@ -0,0 +1,132 @@
+// Package sourcetest 提供数据源解析逻辑的黄金文件测试工具
+//
+// 测试夹具是数据源 Fetch 返回的原始内容（可以用 crawler.WithRecordDir 录制），
+// 黄金文件是夹具的期望解析结果，与夹具放在同一目录，文件名为夹具去掉扩展名后加上 .golden.json：
+//
+//	testdata/kuaishou.html
+//	testdata/kuaishou.golden.json
+//
+// 站点的HTML或JSON结构变化导致解析结果改变时测试失败；确认新的结果正确后，
+// 用 go test -update 重新生成黄金文件
+package sourcetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// goldenExt 黄金文件的扩展名
+const goldenExt = ".golden.json"
+
+var update = flag.Bool("update", false, "用解析结果重新生成黄金文件")
+
+// LoadFixture 读取测试夹具，文件不存在或读取失败时终止测试
+func LoadFixture(t testing.TB, path string) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+	return content
+}
+
+// FindFixture 在dir中查找数据源name的测试夹具，即文件名为 <name>.<扩展名> 且不是黄金文件的文件
+func FindFixture(dir, name string) (string, bool) {
+	matches, err := filepath.Glob(filepath.Join(dir, name+".*"))
+	if err != nil {
+		return "", false
+	}
+	for _, match := range matches {
+		if !strings.HasSuffix(match, goldenExt) {
+			return match, true
+		}
+	}
+	return "", false
+}
+
+// GoldenPath 返回夹具对应的黄金文件路径
+func GoldenPath(fixture string) string {
+	return strings.TrimSuffix(fixture, filepath.Ext(fixture)) + goldenExt
+}
+
+// AssertParse 用source解析夹具fixture，并将结果与黄金文件比较，返回解析结果
+// 解析期间以当前时间填充的时间字段会被置为零值，其余时间统一转换为UTC，使结果与测试的运行时间和时区无关；
+// 使用 -update 运行测试时用解析结果重写黄金文件
+func AssertParse(t testing.TB, source crawler.Source, fixture string) []models.Item {
+	t.Helper()
+
+	content := LoadFixture(t, fixture)
+	start := time.Now()
+	items, err := source.Parse(content)
+	end := time.Now()
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", fixture, err)
+	}
+	for i := range items {
+		normalizeTime(&items[i], start, end)
+	}
+
+	got, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal items: %v", err)
+	}
+	got = append(got, '\n')
+
+	golden := GoldenPath(fixture)
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return items
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to load golden file (run go test -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("parse result of %s does not match %s\n%s", fixture, golden, firstDiff(want, got))
+	}
+	return items
+}
+
+// normalizeTime 将落在解析期间的时间字段置为零值，其余时间转换为UTC
+func normalizeTime(item *models.Item, start, end time.Time) {
+	for _, t := range []*time.Time{&item.PublishedAt, &item.CreatedAt, &item.UpdatedAt} {
+		if !t.Before(start) && !t.After(end) {
+			*t = time.Time{}
+		} else if !t.IsZero() {
+			*t = t.UTC()
+		}
+	}
+}
+
+// firstDiff 返回两段文本第一处不同的行，用于定位解析结果的变化
+func firstDiff(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return "line " + strconv.Itoa(i+1) + ":\n  want: " + w + "\n  got:  " + g
+		}
+	}
+	return ""
+}
@@ -0,0 +1,200 @@
+package sources
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+)
+
+// telegramTitleLength 从消息正文生成标题时保留的最大字符数
+const telegramTitleLength = 80
+
+// telegramBackgroundURL 匹配图片元素 style 属性中的 background-image 地址
+var telegramBackgroundURL = regexp.MustCompile(`url\(['"]?([^'")]+)['"]?\)`)
+
+// TelegramSource Telegram公开频道数据源
+// 该数据源通过 t.me/s/<channel> 网页预览读取公开频道的消息，不需要Bot Token；
+// 默认读取环境变量 TELEGRAM_CHANNELS 中以逗号分隔的频道，也可以通过 SetChannels 或 channels 配置设置
+type TelegramSource struct {
+	BaseSource
+
+	mu       sync.RWMutex
+	channels []string
+}
+
+// NewTelegramSource 创建Telegram公开频道数据源实例，channels为频道用户名，可以带 @ 前缀
+func NewTelegramSource(channels ...string) *TelegramSource {
+	s := &TelegramSource{
+		BaseSource: BaseSource{
+			Name:       "telegram",
+			URL:        "https://t.me/s/",
+			Interval:   600, // 10分钟爬取一次
+			Categories: []string{"社交", "综合"},
+		},
+	}
+	s.SetChannels(channels)
+	return s
+}
+
+// SetChannels 设置读取的频道列表
+func (s *TelegramSource) SetChannels(channels []string) {
+	normalized := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		channel = strings.TrimPrefix(strings.TrimSpace(channel), "@")
+		if channel != "" {
+			normalized = append(normalized, channel)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels = normalized
+}
+
+// Configure 应用通用配置以及 channels（以逗号分隔的频道）
+func (s *TelegramSource) Configure(config SourceConfig) error {
+	if err := s.BaseSource.Configure(config); err != nil {
+		return err
+	}
+	if config.Has("channels") {
+		s.SetChannels(config.Strings("channels", nil))
+	}
+	return nil
+}
+
+// Clone 返回数据源的副本
+func (s *TelegramSource) Clone() crawler.Source {
+	return &TelegramSource{BaseSource: s.BaseSource, channels: s.Channels()}
+}
+
+// Channels 返回读取的频道列表
+func (s *TelegramSource) Channels() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.channels...)
+}
+
+// Fetch 依次获取每个频道的网页预览，返回拼接后的HTML
+// 部分频道获取失败时忽略这些频道，全部失败时返回错误
+func (s *TelegramSource) Fetch(ctx context.Context) ([]byte, error) {
+	channels := s.Channels()
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("telegram: no channels configured")
+	}
+
+	var buf bytes.Buffer
+	var errs []error
+	for _, channel := range channels {
+		content, err := s.FetchURL(ctx, s.URL+channel)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channel, err))
+			continue
+		}
+		buf.Write(content)
+	}
+
+	if len(errs) == len(channels) {
+		return nil, errors.Join(errs...)
+	}
+	return buf.Bytes(), nil
+}
+
+// Parse 解析频道网页预览中的消息
+// 每条消息的 data-post 属性为 <channel>/<id>，因此多个频道的页面拼接后仍可正确区分来源
+func (s *TelegramSource) Parse(content []byte) ([]models.Item, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.Item, 0)
+	doc.Find(".tgme_widget_message[data-post]").Each(func(i int, el *goquery.Selection) {
+		post, _ := el.Attr("data-post")
+		channel, _, ok := strings.Cut(post, "/")
+		if !ok {
+			return
+		}
+
+		// 保留正文中的换行，便于生成标题
+		var text string
+		if html, err := el.Find(".tgme_widget_message_text").First().Html(); err == nil {
+			text = strings.TrimSpace(htmlToText(html))
+		}
+
+		var images []string
+		el.Find(".tgme_widget_message_photo_wrap").Each(func(i int, photo *goquery.Selection) {
+			style, _ := photo.Attr("style")
+			if match := telegramBackgroundURL.FindStringSubmatch(style); match != nil {
+				images = append(images, match[1])
+			}
+		})
+
+		// 没有正文的图片消息使用频道名称作为标题
+		author := strings.TrimSpace(el.Find(".tgme_widget_message_owner_name").First().Text())
+		title := telegramTitle(text)
+		if title == "" {
+			if len(images) == 0 {
+				return
+			}
+			title = cmp.Or(author, post)
+		}
+
+		item := NewBaseItem(post, title, "https://t.me/"+post, s.Name)
+		item.Content = text
+		item.Category = channel
+		item.Images = images
+		item.Author = author
+		if len(images) > 0 {
+			item.ImageURL = images[0]
+		}
+		if datetime, ok := el.Find(".tgme_widget_message_date time").Attr("datetime"); ok {
+			if pubTime, err := time.Parse(time.RFC3339, datetime); err == nil {
+				item.PublishedAt = pubTime
+			}
+		}
+		items = append(items, item)
+	})
+
+	return items, nil
+}
+
+// htmlToText 将消息正文的HTML转换为纯文本，<br> 转换为换行
+func htmlToText(html string) string {
+	html = strings.NewReplacer("<br/>", "\n", "<br>", "\n", "<br />", "\n").Replace(html)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return html
+	}
+	return doc.Text()
+}
+
+// telegramTitle 取消息正文的第一行作为标题，过长时截断
+func telegramTitle(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	line = strings.TrimSpace(line)
+
+	runes := []rune(line)
+	if len(runes) > telegramTitleLength {
+		return string(runes[:telegramTitleLength]) + "..."
+	}
+	return line
+}
+
+func init() {
+	var channels []string
+	if env := os.Getenv("TELEGRAM_CHANNELS"); env != "" {
+		channels = strings.Split(env, ",")
+	}
+	RegisterSource(NewTelegramSource(channels...))
+}
@@ -3,10 +3,10 @@ package sources
 import (
 	"context"
 	"encoding/json"
-	"io"
 	"net/http"
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/fetcher"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
@@ -77,7 +77,6 @@ func NewTencentSource() *TencentSource {
 // Fetch 获取腾讯新闻数据
 func (s *TencentSource) Fetch(ctx context.Context) ([]byte, error) {
 	// 创建HTTP请求
-	client := &http.Client{}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
 	if err != nil {
 		return nil, err
@@ -87,13 +86,13 @@ func (s *TencentSource) Fetch(ctx context.Context) ([]byte, error) {
 	req.Header.Set("Referer", "https://news.qq.com/")
 
 	// 发送请求
-	resp, err := client.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := fetcher.ReadBody(resp)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1 @@
+var newest = [{"id":"20250318101500123100","time":"2025-03-18 10:15:00","type":0,"data":{"pic":"","title":"","source":"","content":"【欧元区3月ZEW经济景气指数】前值24.2，预期29.5，公布值39.8。","source_link":"","lock":false,"vip_level":0},"important":1,"tags":[],"channel":[1],"remark":[]},{"id":"20250318101000456200","time":"2025-03-18 10:10:00","type":0,"data":{"title":"","content":"<b>国内商品期货午盘收盘多数上涨</b>","lock":false,"vip_level":0},"important":0,"tags":[],"channel":[1,3],"remark":[]},{"id":"20250318100500789300","time":"2025-03-18 10:05:00","type":0,"data":{"content":"VIP专属内容","lock":true,"vip_level":1},"important":0,"tags":[],"channel":[5],"remark":[]},{"id":"20250318100000000400","time":"2025-03-18 10:00:00","type":1,"data":{"lock":false,"vip_level":0},"important":0,"tags":[],"channel":[1],"remark":[]}];
\ No newline at end of file
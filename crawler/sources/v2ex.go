@@ -2,6 +2,7 @@ package sources
 
 import (
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/sjzsdu/utils/crawler/pkg/models"
@@ -52,7 +53,7 @@ func (s *V2EXSource) Parse(content []byte) ([]models.Item, error) {
 	now := time.Now()
 	for _, topic := range topics {
 		items = append(items, models.Item{
-			ID:          string(rune(topic.ID)),
+			ID:          strconv.Itoa(topic.ID),
 			Title:       topic.Title,
 			URL:         topic.URL,
 			Content:     topic.Content,
@@ -3,11 +3,11 @@ package sources
 import (
 	"context"
 	"encoding/json"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/sjzsdu/utils/crawler/internal/fetcher"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
 )
 
@@ -58,7 +58,7 @@ func (s *XueqiuSource) Fetch(ctx context.Context) ([]byte, error) {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
 
 	// 发送请求获取cookie
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -83,13 +83,13 @@ func (s *XueqiuSource) Fetch(ctx context.Context) ([]byte, error) {
 	req2.Header.Set("Cookie", cookieStr)
 
 	// 发送请求获取股票数据
-	resp2, err := http.DefaultClient.Do(req2)
+	resp2, err := s.HTTPClient().Do(req2)
 	if err != nil {
 		return nil, err
 	}
 	defer resp2.Body.Close()
 
-	return io.ReadAll(resp2.Body)
+	return fetcher.ReadBody(resp2)
 }
 
 // Parse 解析雪球热门股票内容
@@ -2,13 +2,10 @@ package sources
 
 import (
 	"bytes"
-	"io/ioutil"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/sjzsdu/utils/crawler/pkg/models"
-	"golang.org/x/text/encoding/simplifiedchinese"
-	"golang.org/x/text/transform"
 )
 
 // ZaobaoSource 联合早报数据源
@@ -31,14 +28,8 @@ func NewZaobaoSource() *ZaobaoSource {
 
 // Parse 解析联合早报新闻内容
 func (s *ZaobaoSource) Parse(content []byte) ([]models.Item, error) {
-	// 处理GB2312编码
-	reader := transform.NewReader(bytes.NewReader(content), simplifiedchinese.GB18030.NewDecoder())
-	decodedContent, err := ioutil.ReadAll(reader)
-	if err != nil {
-		return nil, err
-	}
-
-	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(decodedContent))
+	// GB2312编码的页面在获取时已经转换为UTF-8
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
 	if err != nil {
 		return nil, err
 	}
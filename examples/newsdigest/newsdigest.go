@@ -0,0 +1,378 @@
+// Package newsdigest 将爬虫、搜索和通知组合为新闻摘要服务：
+// 订阅爬取引擎的新数据，依次经过去重、关键词过滤和搜索补充相关报道，最后合并为摘要发送到通知渠道
+//
+//	engine := crawler.NewEngine(crawler.NewMemoryCache(time.Minute))
+//	engine.RegisterSource(sources.NewHackerNewsSource())
+//
+//	service, err := newsdigest.NewDigestService(newsdigest.Config{
+//	    Engine:   engine,
+//	    Keywords: []string{"Go", "Rust"},
+//	    Search:   searchClient,
+//	    Notifier: telegramNotifier,
+//	    Digest:   notifier.DigestOptions{Interval: time.Hour, MaxItems: 20},
+//	})
+//	service.Start(ctx)
+//	engine.Start(ctx)
+//	defer service.Stop()
+package newsdigest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/coroutine"
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/logging"
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/search"
+)
+
+// 默认配置
+const (
+	DefaultSearchLimit   = 3
+	DefaultSearchWorkers = 4
+	DefaultDedupeTTL     = 24 * time.Hour
+
+	// subscriberBuffer 订阅通道的缓冲大小
+	subscriberBuffer = 10
+)
+
+// Config 新闻摘要服务的配置
+type Config struct {
+	// Engine 提供数据的爬取引擎，数据源由调用者注册，引擎也由调用者启动和停止
+	Engine crawler.ControllableEngine
+	// Sources 订阅的数据源名称，为空时订阅所有数据源
+	Sources []string
+
+	// Keywords 标题或内容包含任一关键词（不区分大小写）的数据项才会发送，为空时不过滤
+	Keywords []string
+	// ExcludeKeywords 标题或内容包含任一关键词的数据项不发送，优先于 Keywords
+	ExcludeKeywords []string
+
+	// Search 用于补充相关报道的搜索客户端，为nil时不补充
+	Search *search.Client
+	// SearchLimit 每个数据项补充的相关报道数量，不大于0时使用 DefaultSearchLimit
+	SearchLimit int
+	// SearchWorkers 同时进行的搜索数量，不大于0时使用 DefaultSearchWorkers
+	SearchWorkers int
+
+	// Notifier 发送摘要的通知器，会被 notifier.Digest 包装
+	Notifier notifier.Notifier
+	// Digest 摘要的合并策略，Interval 和 MaxItems 都不大于0时只在 Flush 和 Stop 时发送
+	Digest notifier.DigestOptions
+
+	// DedupeTTL 已发送数据项的记忆时间，期间再次出现的数据项不会重复发送，不大于0时使用 DefaultDedupeTTL
+	DedupeTTL time.Duration
+
+	// Logger 日志记录器，默认不输出日志
+	Logger logging.Logger
+}
+
+// DigestItem 摘要中的一条新闻，实现了 notifier.MessageItem 接口
+type DigestItem struct {
+	Item models.Item
+	// Related 搜索到的相关报道，未开启搜索或搜索失败时为空
+	Related []search.SearchResult
+}
+
+// Title 获取标题
+func (i DigestItem) Title() string {
+	return i.Item.Title
+}
+
+// URL 获取链接
+func (i DigestItem) URL() string {
+	return i.Item.URL
+}
+
+// Source 获取数据源名称，用于通知标题模板和按数据源分组
+func (i DigestItem) Source() string {
+	return i.Item.Source
+}
+
+// Category 获取分类，用于按分类分组
+func (i DigestItem) Category() string {
+	return i.Item.Category
+}
+
+// Content 获取内容，有相关报道时附在内容之后
+func (i DigestItem) Content() string {
+	if len(i.Related) == 0 {
+		return i.Item.Content
+	}
+
+	var b strings.Builder
+	if i.Item.Content != "" {
+		b.WriteString(i.Item.Content)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("相关报道:")
+	for _, result := range i.Related {
+		fmt.Fprintf(&b, "\n- %s %s", result.Title, result.URL)
+	}
+	return b.String()
+}
+
+// DigestService 新闻摘要服务
+type DigestService struct {
+	config Config
+	digest *notifier.Digest
+	logger logging.Logger
+
+	mu     sync.Mutex
+	seen   map[string]time.Time // 已发送数据项的键和发送时间
+	subs   map[string]chan []models.Item
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDigestService 创建新闻摘要服务，Engine 和 Notifier 必须设置
+func NewDigestService(config Config) (*DigestService, error) {
+	if config.Engine == nil {
+		return nil, fmt.Errorf("未设置爬取引擎")
+	}
+	if config.Notifier == nil {
+		return nil, fmt.Errorf("未设置通知器")
+	}
+	if config.SearchLimit <= 0 {
+		config.SearchLimit = DefaultSearchLimit
+	}
+	if config.SearchWorkers <= 0 {
+		config.SearchWorkers = DefaultSearchWorkers
+	}
+	if config.DedupeTTL <= 0 {
+		config.DedupeTTL = DefaultDedupeTTL
+	}
+
+	logger := logging.OrNop(config.Logger)
+	digest := notifier.NewDigest(config.Notifier, config.Digest)
+	digest.SetLogger(logger)
+
+	return &DigestService{
+		config: config,
+		digest: digest,
+		logger: logger,
+		seen:   make(map[string]time.Time),
+	}, nil
+}
+
+// Start 订阅爬取引擎，之后引擎每次爬取到的数据都会经过处理后加入摘要；重复调用不会重复订阅
+func (s *DigestService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.subs = make(map[string]chan []models.Item)
+	subscribe := func(key string, fn func(chan<- []models.Item) error) error {
+		ch := make(chan []models.Item, subscriberBuffer)
+		if err := fn(ch); err != nil {
+			return err
+		}
+		s.subs[key] = ch
+		s.wg.Add(1)
+		go s.consume(ctx, ch)
+		return nil
+	}
+
+	var err error
+	if len(s.config.Sources) == 0 {
+		err = subscribe("", s.config.Engine.SubscribeAll)
+	} else {
+		for _, name := range s.config.Sources {
+			err = subscribe(name, func(ch chan<- []models.Item) error {
+				return s.config.Engine.Subscribe(name, ch)
+			})
+			if err != nil {
+				break
+			}
+		}
+	}
+	s.cancel = cancel
+	if err != nil {
+		s.unsubscribe()
+		s.cancel = nil
+		return fmt.Errorf("订阅数据源失败: %w", err)
+	}
+	return nil
+}
+
+// Stop 取消订阅并发送摘要中剩余的消息，之后服务不能再启动
+func (s *DigestService) Stop() error {
+	s.mu.Lock()
+	s.unsubscribe()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return s.digest.Close()
+}
+
+// unsubscribe 取消所有订阅并停止处理协程，调用时需持有锁
+func (s *DigestService) unsubscribe() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	for key, ch := range s.subs {
+		if key == "" {
+			s.config.Engine.UnsubscribeAll(ch)
+		} else {
+			s.config.Engine.Unsubscribe(key, ch)
+		}
+	}
+	s.subs = nil
+}
+
+// consume 处理订阅通道中的数据，直到ctx取消
+func (s *DigestService) consume(ctx context.Context, ch <-chan []models.Item) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case items := <-ch:
+			if _, err := s.Process(ctx, items); err != nil {
+				s.logger.Error("处理新闻摘要失败", logging.KeyError, err)
+			}
+		}
+	}
+}
+
+// Process 处理一批数据项：去重、关键词过滤、搜索补充相关报道，然后加入摘要，返回加入摘要的数量
+// 订阅引擎时会自动调用，也可以直接传入从其他途径获得的数据项
+func (s *DigestService) Process(ctx context.Context, items []models.Item) (int, error) {
+	items = s.filter(s.dedupe(items))
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	digestItems := s.enrich(ctx, items)
+	messages := make([]notifier.MessageItem, len(digestItems))
+	for i, item := range digestItems {
+		messages[i] = item
+	}
+	if _, err := s.digest.Send(ctx, messages); err != nil {
+		return 0, err
+	}
+	return len(messages), nil
+}
+
+// Flush 立即发送摘要中累积的消息
+func (s *DigestService) Flush(ctx context.Context) error {
+	_, err := s.digest.Flush(ctx)
+	return err
+}
+
+// dedupe 去掉在 DedupeTTL 内已处理过的数据项，同一批中重复的数据项只保留第一个
+func (s *DigestService) dedupe(items []models.Item) []models.Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, seenAt := range s.seen {
+		if now.Sub(seenAt) > s.config.DedupeTTL {
+			delete(s.seen, key)
+		}
+	}
+
+	var fresh []models.Item
+	for _, item := range items {
+		key := itemKey(item)
+		if _, ok := s.seen[key]; ok {
+			continue
+		}
+		s.seen[key] = now
+		fresh = append(fresh, item)
+	}
+	return fresh
+}
+
+// filter 按关键词过滤数据项
+func (s *DigestService) filter(items []models.Item) []models.Item {
+	if len(s.config.Keywords) == 0 && len(s.config.ExcludeKeywords) == 0 {
+		return items
+	}
+
+	var matched []models.Item
+	for _, item := range items {
+		text := strings.ToLower(item.Title + "\n" + item.Content)
+		if containsAny(text, s.config.ExcludeKeywords) {
+			continue
+		}
+		if len(s.config.Keywords) > 0 && !containsAny(text, s.config.Keywords) {
+			continue
+		}
+		matched = append(matched, item)
+	}
+	return matched
+}
+
+// enrich 以标题搜索每个数据项的相关报道，搜索失败的数据项不带相关报道
+func (s *DigestService) enrich(ctx context.Context, items []models.Item) []DigestItem {
+	if s.config.Search == nil {
+		digestItems := make([]DigestItem, len(items))
+		for i, item := range items {
+			digestItems[i] = DigestItem{Item: item}
+		}
+		return digestItems
+	}
+
+	results := coroutine.Map(ctx, s.config.SearchWorkers, items, func(item models.Item) (DigestItem, error) {
+		related, err := s.config.Search.Search(ctx, item.Title, s.config.SearchLimit+1)
+		if err != nil {
+			return DigestItem{Item: item}, err
+		}
+		return DigestItem{Item: item, Related: relatedResults(item, related, s.config.SearchLimit)}, nil
+	})
+
+	digestItems := make([]DigestItem, len(items))
+	for i, result := range results {
+		digestItems[i] = result.Value
+		if result.Err != nil {
+			digestItems[i] = DigestItem{Item: items[i]}
+			s.logger.Warn("搜索相关报道失败", logging.KeySource, items[i].Source, logging.KeyError, result.Err)
+		}
+	}
+	return digestItems
+}
+
+// relatedResults 去掉与数据项本身链接相同的结果，最多保留limit个
+func relatedResults(item models.Item, results []search.SearchResult, limit int) []search.SearchResult {
+	var related []search.SearchResult
+	for _, result := range results {
+		if result.URL == item.URL || result.Filtered != "" {
+			continue
+		}
+		related = append(related, result)
+		if len(related) == limit {
+			break
+		}
+	}
+	return related
+}
+
+// itemKey 返回用于判断数据项是否重复的键
+func itemKey(item models.Item) string {
+	if item.ID != "" {
+		return item.Source + "/" + item.ID
+	}
+	return item.URL
+}
+
+// containsAny 判断小写文本是否包含任一关键词
+func containsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package newsdigest
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/search"
+)
+
+// mockSource 返回固定数据项的数据源
+type mockSource struct {
+	items []models.Item
+}
+
+func (m *mockSource) GetName() string                             { return "mock" }
+func (m *mockSource) GetURL() string                              { return "https://example.com" }
+func (m *mockSource) Fetch(ctx context.Context) ([]byte, error)   { return nil, nil }
+func (m *mockSource) Parse(content []byte) ([]models.Item, error) { return m.items, nil }
+func (m *mockSource) GetInterval() int                            { return 3600 }
+func (m *mockSource) GetCategories() []string                     { return []string{"tech"} }
+
+// mockSearchEngine 以查询生成结果的搜索引擎
+type mockSearchEngine struct{}
+
+func (mockSearchEngine) Name() string { return "mock" }
+
+func (mockSearchEngine) Search(ctx context.Context, query string, limit int) ([]search.SearchResult, error) {
+	return []search.SearchResult{
+		{Title: query, URL: "https://example.com/go"},
+		{Title: query + " 分析", URL: "https://news.example.com/1"},
+		{Title: query + " 评论", URL: "https://news.example.com/2"},
+	}, nil
+}
+
+// recordingNotifier 记录收到的消息
+type recordingNotifier struct {
+	mu    sync.Mutex
+	items []notifier.MessageItem
+}
+
+func (n *recordingNotifier) Name() string    { return "recording" }
+func (n *recordingNotifier) IsEnabled() bool { return true }
+
+func (n *recordingNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.items = append(n.items, items...)
+	return &notifier.NotificationResult{Status: notifier.StatusSuccess, TotalCount: len(items), SuccessCount: len(items)}, nil
+}
+
+func (n *recordingNotifier) sent() []notifier.MessageItem {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]notifier.MessageItem(nil), n.items...)
+}
+
+func TestDigestService(t *testing.T) {
+	source := &mockSource{items: []models.Item{
+		{ID: "1", Title: "Go 1.25 发布", URL: "https://example.com/go", Source: "mock"},
+		{ID: "2", Title: "Rust 新版本", URL: "https://example.com/rust", Source: "mock"},
+		{ID: "3", Title: "Go 广告", URL: "https://example.com/ad", Source: "mock"},
+		{ID: "4", Title: "天气预报", URL: "https://example.com/weather", Source: "mock"},
+	}}
+	engine := crawler.NewEngine(crawler.NewMemoryCache(time.Minute))
+	if err := engine.RegisterSource(source); err != nil {
+		t.Fatalf("注册数据源失败: %v", err)
+	}
+
+	client := search.NewClient()
+	client.RegisterEngine(mockSearchEngine{})
+	if err := client.SetDefaultEngine("mock"); err != nil {
+		t.Fatalf("设置默认搜索引擎失败: %v", err)
+	}
+
+	recorder := &recordingNotifier{}
+	service, err := NewDigestService(Config{
+		Engine:          engine,
+		Keywords:        []string{"go", "rust"},
+		ExcludeKeywords: []string{"广告"},
+		Search:          client,
+		SearchLimit:     1,
+		Notifier:        recorder,
+	})
+	if err != nil {
+		t.Fatalf("创建服务失败: %v", err)
+	}
+	if err := service.Start(context.Background()); err != nil {
+		t.Fatalf("启动服务失败: %v", err)
+	}
+
+	// 两次爬取到相同的数据项，第二次应被去重
+	for range 2 {
+		if _, err := engine.Trigger(context.Background(), "mock"); err != nil {
+			t.Fatalf("爬取数据源失败: %v", err)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, recorder.sent(), "摘要应在 Stop 时才发送")
+
+	assert.NoError(t, service.Stop())
+	sent := recorder.sent()
+	if !assert.Len(t, sent, 2, "应只发送匹配关键词且不重复的数据项") {
+		return
+	}
+	assert.Equal(t, "Go 1.25 发布", sent[0].Title())
+	assert.Equal(t, "Rust 新版本", sent[1].Title())
+
+	// 与数据项链接相同的搜索结果不作为相关报道
+	content := sent[0].Content()
+	assert.Contains(t, content, "https://news.example.com/1")
+	assert.NotContains(t, content, "https://news.example.com/2", "相关报道数量应受 SearchLimit 限制")
+	assert.Equal(t, 1, strings.Count(content, "\n- "))
+}
+
+func TestNewDigestServiceValidation(t *testing.T) {
+	_, err := NewDigestService(Config{Notifier: &recordingNotifier{}})
+	assert.Error(t, err, "未设置爬取引擎时应返回错误")
+
+	_, err = NewDigestService(Config{Engine: crawler.NewEngine(crawler.NewMemoryCache(time.Minute))})
+	assert.Error(t, err, "未设置通知器时应返回错误")
+}
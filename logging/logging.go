@@ -0,0 +1,90 @@
+// Package logging 提供各个包共用的结构化日志接口
+// 爬取引擎、调度器、通知器和Markdown服务都通过 Logger 输出日志，默认不输出任何内容，
+// 通过 New 可以接入标准库的 slog，也可以自行实现 Logger 接入其他日志库
+package logging
+
+import (
+	"log/slog"
+)
+
+// 常用的日志字段名
+const (
+	// KeySource 数据源名称
+	KeySource = "source"
+	// KeyChannel 通知渠道名称
+	KeyChannel = "channel"
+	// KeyTask 调度任务ID
+	KeyTask = "task"
+	// KeyError 错误信息
+	KeyError = "error"
+)
+
+// Logger 结构化日志接口，args为交替的键值对，与 slog.Logger 的用法相同
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	// With 返回附带了固定字段的Logger
+	With(args ...any) Logger
+}
+
+// slogLogger 基于 slog.Logger 的实现
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// New 使用 slog.Logger 创建Logger，logger为nil时使用 slog.Default()
+func New(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+// Debug 输出调试日志
+func (l *slogLogger) Debug(msg string, args ...any) {
+	l.logger.Debug(msg, args...)
+}
+
+// Info 输出信息日志
+func (l *slogLogger) Info(msg string, args ...any) {
+	l.logger.Info(msg, args...)
+}
+
+// Warn 输出警告日志
+func (l *slogLogger) Warn(msg string, args ...any) {
+	l.logger.Warn(msg, args...)
+}
+
+// Error 输出错误日志
+func (l *slogLogger) Error(msg string, args ...any) {
+	l.logger.Error(msg, args...)
+}
+
+// With 返回附带了固定字段的Logger
+func (l *slogLogger) With(args ...any) Logger {
+	return &slogLogger{logger: l.logger.With(args...)}
+}
+
+// nop 不输出任何日志的实现
+type nop struct{}
+
+// Nop 返回不输出任何日志的Logger，是各个包的默认值
+func Nop() Logger {
+	return nop{}
+}
+
+func (nop) Debug(msg string, args ...any) {}
+func (nop) Info(msg string, args ...any)  {}
+func (nop) Warn(msg string, args ...any)  {}
+func (nop) Error(msg string, args ...any) {}
+func (nop) With(args ...any) Logger       { return nop{} }
+
+// OrNop logger为nil时返回 Nop()，用于处理未注入Logger的情况
+func OrNop(logger Logger) Logger {
+	if logger == nil {
+		return Nop()
+	}
+	return logger
+}
@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	logger.With(KeyChannel, "ops").Error("发送失败", KeyError, "timeout")
+	logger.Debug("调试信息")
+
+	output := buf.String()
+	if !strings.Contains(output, "level=ERROR") || !strings.Contains(output, "channel=ops") || !strings.Contains(output, "error=timeout") {
+		t.Errorf("期望输出带字段的错误日志，实际为: %s", output)
+	}
+	if strings.Contains(output, "调试信息") {
+		t.Errorf("低于日志级别的日志不应输出，实际为: %s", output)
+	}
+}
+
+func TestNop(t *testing.T) {
+	logger := OrNop(nil)
+	logger.With(KeySource, "test").Info("不会输出")
+
+	if _, ok := logger.(nop); !ok {
+		t.Errorf("期望nil时返回Nop，实际为: %T", logger)
+	}
+}
@@ -0,0 +1,134 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// AdmonitionTitles 提示框类型及其默认标题
+var AdmonitionTitles = map[string]string{
+	"note":      "备注",
+	"tip":       "提示",
+	"info":      "信息",
+	"important": "重要",
+	"warning":   "警告",
+	"caution":   "注意",
+	"danger":    "危险",
+}
+
+var (
+	// admonitionOpenPattern 匹配容器语法的起始行，如 ::: warning 自定义标题
+	admonitionOpenPattern = regexp.MustCompile(`^ {0,3}(:{3,})\s*([A-Za-z]+)\s*(.*?)\s*$`)
+	// admonitionClosePattern 匹配容器语法的结束行
+	admonitionClosePattern = regexp.MustCompile(`^ {0,3}(:{3,})\s*$`)
+	// alertPattern 匹配GitHub风格提示的首行，如 > [!NOTE]
+	alertPattern = regexp.MustCompile(`^ {0,3}>\s*\[!([A-Za-z]+)\]\s*$`)
+)
+
+// RenderAdmonitions 将提示框语法转换为带样式的HTML容器，支持两种写法：
+//
+//	::: warning 可选标题
+//	内容
+//	:::
+//
+//	> [!NOTE]
+//	> 内容
+//
+// 容器内的内容仍按Markdown渲染，可以嵌套；代码块中的内容和未知的类型保持原样
+func (r *MarkdownRenderer) RenderAdmonitions(content string) string {
+	lines := strings.Split(content, "\n")
+	result := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		if _, end, ok := parseFencedCodeBlock(lines, i); ok {
+			result = append(result, lines[i:end+1]...)
+			i = end
+			continue
+		}
+
+		if kind, title, end, ok := parseContainerAdmonition(lines, i); ok {
+			inner := r.RenderAdmonitions(strings.Join(lines[i+1:end], "\n"))
+			result = append(result, admonitionHTML(kind, title, inner))
+			i = end
+			continue
+		}
+
+		if kind, end, ok := parseAlertAdmonition(lines, i); ok {
+			body := make([]string, 0, end-i)
+			for _, line := range lines[i+1 : end+1] {
+				line = strings.TrimPrefix(strings.TrimLeft(line, " "), ">")
+				body = append(body, strings.TrimPrefix(line, " "))
+			}
+			inner := r.RenderAdmonitions(strings.Join(body, "\n"))
+			result = append(result, admonitionHTML(kind, "", inner))
+			i = end
+			continue
+		}
+
+		result = append(result, lines[i])
+	}
+	return strings.Join(result, "\n")
+}
+
+// parseContainerAdmonition 解析从第start行开始的 ::: 容器，返回类型、标题和结束行
+// 结束行的冒号数不少于起始行，内部可以嵌套冒号更少或相同的容器
+func parseContainerAdmonition(lines []string, start int) (string, string, int, bool) {
+	match := admonitionOpenPattern.FindStringSubmatch(lines[start])
+	if match == nil {
+		return "", "", 0, false
+	}
+	kind := strings.ToLower(match[2])
+	if _, ok := AdmonitionTitles[kind]; !ok {
+		return "", "", 0, false
+	}
+
+	depth := 0
+	for i := start + 1; i < len(lines); i++ {
+		if _, end, ok := parseFencedCodeBlock(lines, i); ok {
+			i = end
+			continue
+		}
+		if admonitionOpenPattern.MatchString(lines[i]) {
+			depth++
+			continue
+		}
+		if closing := admonitionClosePattern.FindStringSubmatch(lines[i]); closing != nil {
+			if depth == 0 && len(closing[1]) >= len(match[1]) {
+				return kind, match[3], i, true
+			}
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// parseAlertAdmonition 解析从第start行开始的GitHub风格提示，返回类型和最后一个引用行
+func parseAlertAdmonition(lines []string, start int) (string, int, bool) {
+	match := alertPattern.FindStringSubmatch(lines[start])
+	if match == nil {
+		return "", 0, false
+	}
+	kind := strings.ToLower(match[1])
+	if _, ok := AdmonitionTitles[kind]; !ok {
+		return "", 0, false
+	}
+
+	end := start
+	for end+1 < len(lines) && strings.HasPrefix(strings.TrimLeft(lines[end+1], " "), ">") {
+		end++
+	}
+	return kind, end, true
+}
+
+// admonitionHTML 返回提示框的HTML，起止标签与内容之间用空行分隔，使内容仍按Markdown渲染
+func admonitionHTML(kind, title, inner string) string {
+	if title == "" {
+		title = AdmonitionTitles[kind]
+	}
+	return `<div class="admonition admonition-` + kind + `">` + "\n" +
+		`<p class="admonition-title">` + html.EscapeString(title) + `</p>` + "\n\n" +
+		inner + "\n\n" +
+		`</div>`
+}
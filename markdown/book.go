@@ -0,0 +1,700 @@
+package markdown
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sjzsdu/utils/logging"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// BookFormat 书籍的导出格式
+type BookFormat string
+
+const (
+	// BookFormatHTML 单个HTML页面，可以通过浏览器打印
+	BookFormatHTML BookFormat = "html"
+	// BookFormatPDF 通过外部命令将HTML转换为PDF
+	BookFormatPDF BookFormat = "pdf"
+	// BookFormatEPUB EPUB 3电子书
+	BookFormatEPUB BookFormat = "epub"
+)
+
+// DefaultBookLanguage 未配置语言时书籍使用的语言代码
+const DefaultBookLanguage = "zh"
+
+// bookImageDir 书中本地图片相对书籍页面的目录
+const bookImageDir = "images"
+
+// bookImagePattern 匹配渲染后章节中引用的本地图片
+var bookImagePattern = regexp.MustCompile(`<img[^>]*\ssrc="` + bookImageDir + `(/[^"]+)"`)
+
+// bookTemplate 单页HTML书籍的模板
+var bookTemplate = template.Must(template.ParseFS(templateFS, "templates/book.html"))
+
+// BookOptions 将多篇文档合并为一本书的选项
+type BookOptions struct {
+	// Title 书名，默认为站点标题
+	Title string
+	// Author 作者，为空时封面不显示作者
+	Author string
+	// Language 书籍的语言代码，默认为 DefaultBookLanguage
+	Language string
+	// Cover 封面图片在项目树中的路径，为空时封面只显示书名和作者
+	Cover string
+	// Documents 按章节顺序排列的文档路径，为空时按路径顺序包含项目树中所有非草稿文档
+	Documents []string
+	// InlineImages 是否将章节中的本地图片以data URL嵌入，开启后导出的HTML不依赖 images 目录，
+	// 封面在HTML中同样以data URL嵌入
+	InlineImages bool
+	// PDFCommand 将HTML转换为PDF的命令及参数，参数中的 {input} 和 {output} 会被替换为HTML文件和PDF文件的路径，
+	// {dir} 会被替换为存放HTML和图片的临时目录；为空时依次在PATH中查找 Chromium、Chrome 和 wkhtmltopdf
+	PDFCommand []string
+	// ServePDF 是否允许通过服务器的 /book?format=pdf 导出PDF，默认关闭；
+	// 转换时会在服务器上运行外部命令，只应在可信的环境中开启，直接调用 Book.WritePDF 不受影响
+	ServePDF bool
+	// MaxPDFConversions 服务器同时进行的PDF转换数量，小于等于0时使用1，超过时返回503
+	MaxPDFConversions int
+}
+
+// Book 由多篇文档合并成的书籍，每篇文档为一章
+type Book struct {
+	Title    string
+	Author   string
+	Language string
+	// Cover 封面图片，没有配置封面时为nil
+	Cover *BookImage
+	// Chapters 按顺序排列的章节
+	Chapters []BookChapter
+	// Images 章节中引用的本地图片
+	Images []BookImage
+	// Math 书中是否包含公式，包含时HTML页面加载MathJax
+	Math bool
+
+	inlineImages bool // HTML中的封面是否以data URL嵌入
+}
+
+// BookChapter 书籍中的一章
+type BookChapter struct {
+	// ID 章节的锚点，如 chapter-1
+	ID string
+	// Path 文档在项目树中的路径
+	Path  string
+	Title string
+	// Content 渲染后的HTML，本地图片以 images/ 开头的相对路径引用
+	Content template.HTML
+}
+
+// BookImage 书中使用的本地图片
+type BookImage struct {
+	// Path 图片在项目树中的路径，以/开头
+	Path        string
+	ContentType string
+	Data        []byte
+}
+
+// href 返回图片相对书籍页面的URL
+func (i BookImage) href() string {
+	return (&url.URL{Path: bookImageDir + i.Path}).EscapedPath()
+}
+
+// BuildBook 按选项将文档合并为一本书，每篇文档为一章，章节标题优先使用front matter中的title
+// 指定的文档不存在时返回错误；包含整个项目树时跳过 draft: true 的文档
+func (s *MarkdownServer) BuildBook(proj ProjectTree, options BookOptions) (*Book, error) {
+	book := &Book{
+		Title:    options.Title,
+		Author:   options.Author,
+		Language: options.Language,
+
+		inlineImages: options.InlineImages,
+	}
+	if book.Title == "" {
+		book.Title = s.siteTitle
+	}
+	if book.Language == "" {
+		book.Language = DefaultBookLanguage
+	}
+
+	paths := options.Documents
+	if len(paths) == 0 {
+		files, err := s.getMarkdownFiles(proj)
+		if err != nil {
+			return nil, fmt.Errorf("获取文件列表失败: %v", err)
+		}
+		for _, file := range files {
+			paths = append(paths, file.RelativePath)
+		}
+	}
+
+	processOptions := DefaultProcessOptions()
+	processOptions.ImageURLPrefix = bookImageDir
+	processOptions.CodeCopyButton = false
+	processOptions.InlineImages = options.InlineImages
+	processOptions.ImageTree = proj
+
+	var imagePaths []string
+	seenImages := make(map[string]bool)
+	for _, filePath := range paths {
+		filePath = "/" + strings.TrimPrefix(filePath, "/")
+		content, currentDir, err := s.readDocument(proj, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("读取章节 %s 失败: %v", filePath, err)
+		}
+
+		meta, body := parseFrontMatter(string(content))
+		if meta.Draft && len(options.Documents) == 0 {
+			continue
+		}
+		title := meta.Title
+		if title == "" {
+			title, _ = s.renderer.ExtractTitleAndDescription(body)
+		}
+
+		htmlContent, err := RenderHTML(string(s.renderer.ProcessContentWithOptions(body, currentDir, processOptions)))
+		if err != nil {
+			return nil, fmt.Errorf("渲染章节 %s 失败: %v", filePath, err)
+		}
+		book.Chapters = append(book.Chapters, BookChapter{
+			ID:      fmt.Sprintf("chapter-%d", len(book.Chapters)+1),
+			Path:    filePath,
+			Title:   title,
+			Content: template.HTML(htmlContent),
+		})
+		book.Math = book.Math || ContainsMath(body)
+
+		for _, match := range bookImagePattern.FindAllStringSubmatch(htmlContent, -1) {
+			imagePath, err := url.PathUnescape(html.UnescapeString(match[1]))
+			if err != nil || seenImages[imagePath] {
+				continue
+			}
+			seenImages[imagePath] = true
+			imagePaths = append(imagePaths, imagePath)
+		}
+	}
+	if len(book.Chapters) == 0 {
+		return nil, fmt.Errorf("没有可以合并的文档")
+	}
+
+	if options.Cover != "" {
+		cover, err := readBookImage(proj, options.Cover)
+		if err != nil {
+			return nil, fmt.Errorf("读取封面失败: %v", err)
+		}
+		book.Cover = &cover
+	}
+
+	// 图片缺失不影响其余内容，与页面中显示为损坏的图片一致
+	for _, imagePath := range imagePaths {
+		image, err := readBookImage(proj, imagePath)
+		if err != nil {
+			s.logger.Warn("读取书籍图片失败", "path", imagePath, logging.KeyError, err)
+			continue
+		}
+		book.Images = append(book.Images, image)
+	}
+
+	return book, nil
+}
+
+// readBookImage 读取项目树中的图片，只支持 mimeTypes 中的图片类型
+func readBookImage(proj ProjectTree, imagePath string) (BookImage, error) {
+	data, contentType, err := readImage(proj, imagePath)
+	if err != nil {
+		return BookImage{}, err
+	}
+	return BookImage{Path: path.Clean("/" + imagePath), ContentType: contentType, Data: data}, nil
+}
+
+// bookPage 单页HTML书籍的模板数据
+type bookPage struct {
+	*Book
+	Cover    template.URL // 封面图片的URL，可能为data URL
+	Contents string       // 目录的标题
+	Print    bool         // 是否在加载完成后自动打开打印对话框
+}
+
+// WriteHTML 将书籍写为单个HTML页面，包含封面、目录和章节，打印时每章从新的一页开始
+// 没有开启 BookOptions.InlineImages 时页面以 images/ 开头的相对路径引用图片，可以通过 WriteImages 将图片写到页面所在的目录
+func (b *Book) WriteHTML(w io.Writer) error {
+	return b.writeHTML(w, false)
+}
+
+// writeHTML 将书籍写为单个HTML页面，print为true时页面加载完成后自动打开打印对话框
+func (b *Book) writeHTML(w io.Writer, print bool) error {
+	page := bookPage{Book: b, Contents: b.contentsTitle(), Print: print}
+	if b.Cover != nil {
+		page.Cover = template.URL(b.Cover.href())
+		if b.inlineImages {
+			page.Cover = template.URL(dataURL(b.Cover.ContentType, b.Cover.Data))
+		}
+	}
+	if err := bookTemplate.Execute(w, page); err != nil {
+		return fmt.Errorf("模板渲染失败: %v", err)
+	}
+	return nil
+}
+
+// contentsTitle 按书籍语言返回目录的标题
+func (b *Book) contentsTitle() string {
+	if strings.HasPrefix(strings.ToLower(b.Language), "zh") {
+		return "目录"
+	}
+	return "Contents"
+}
+
+// WriteImages 将封面和章节中的图片写到dir下的 images 目录，保持在项目树中的相对路径
+func (b *Book) WriteImages(dir string) error {
+	for _, image := range b.images() {
+		target := filepath.Join(dir, bookImageDir, filepath.FromSlash(image.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("创建图片目录失败: %v", err)
+		}
+		if err := os.WriteFile(target, image.Data, 0o644); err != nil {
+			return fmt.Errorf("写入图片失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// images 返回封面和章节中的所有图片，封面同时被章节引用时只返回一次
+func (b *Book) images() []BookImage {
+	images := b.Images
+	if b.Cover != nil {
+		for _, image := range images {
+			if image.Path == b.Cover.Path {
+				return images
+			}
+		}
+		images = append([]BookImage{*b.Cover}, images...)
+	}
+	return images
+}
+
+// pdfCommands 未配置 PDFCommand 时依次查找的HTML转PDF命令
+// wkhtmltopdf 只允许读取临时目录中的文件，避免文档中的HTML引用服务器上的其他文件
+var pdfCommands = [][]string{
+	{"chromium", "--headless", "--disable-gpu", "--no-pdf-header-footer", "--print-to-pdf={output}", "file://{input}"},
+	{"chromium-browser", "--headless", "--disable-gpu", "--no-pdf-header-footer", "--print-to-pdf={output}", "file://{input}"},
+	{"google-chrome", "--headless", "--disable-gpu", "--no-pdf-header-footer", "--print-to-pdf={output}", "file://{input}"},
+	{"wkhtmltopdf", "--allow", "{dir}", "{input}", "{output}"},
+}
+
+// findPDFCommand 返回PATH中第一个可用的HTML转PDF命令，都不可用时返回nil
+func findPDFCommand() []string {
+	for _, command := range pdfCommands {
+		if _, err := exec.LookPath(command[0]); err == nil {
+			return sandboxArgs(command, os.Geteuid() == 0)
+		}
+	}
+	return nil
+}
+
+// sandboxArgs 以root身份运行（例如在容器中）时为Chromium和Chrome加上 --no-sandbox，它们不能以root身份启用沙箱
+func sandboxArgs(command []string, root bool) []string {
+	if !root || command[0] == "wkhtmltopdf" {
+		return command
+	}
+	return slices.Insert(slices.Clone(command), 1, "--no-sandbox")
+}
+
+// WritePDF 将书籍写为HTML后调用外部命令转换为PDF，command 为空时自动查找可用的命令，参数格式见 BookOptions.PDFCommand
+func (b *Book) WritePDF(ctx context.Context, w io.Writer, command []string) error {
+	if len(command) == 0 {
+		command = findPDFCommand()
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("未找到HTML转PDF的命令，请安装 Chromium 或 wkhtmltopdf，或者使用HTML格式在浏览器中打印")
+	}
+
+	dir, err := os.MkdirTemp("", "markdown-book-")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input, output := filepath.Join(dir, "book.html"), filepath.Join(dir, "book.pdf")
+	var page bytes.Buffer
+	if err := b.WriteHTML(&page); err != nil {
+		return err
+	}
+	if err := os.WriteFile(input, page.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("写入HTML失败: %v", err)
+	}
+	if err := b.WriteImages(dir); err != nil {
+		return err
+	}
+
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = strings.NewReplacer("{input}", input, "{output}", output, "{dir}", dir).Replace(arg)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("执行 %s 失败: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+
+	pdf, err := os.ReadFile(output)
+	if err != nil {
+		return fmt.Errorf("读取生成的PDF失败: %v", err)
+	}
+	_, err = w.Write(pdf)
+	return err
+}
+
+// epubContainer EPUB中指向包文件的 META-INF/container.xml
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// epubStyle EPUB中章节共用的样式
+const epubStyle = `body { line-height: 1.6; }
+img { max-width: 100%; }
+pre { white-space: pre-wrap; background: #f3f4f6; padding: 0.5em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d1d5db; padding: 0.25em 0.5em; }
+.cover { text-align: center; }
+.cover img { max-height: 70%; }
+`
+
+// epubPage EPUB中的一个XHTML页面，XML声明会被html/template转义，由 Book.epubPage 写入
+var epubPage = template.Must(template.New("epub").Parse(`<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{.Language}}" lang="{{.Language}}">
+<head>
+<meta charset="UTF-8"/>
+<title>{{.Title}}</title>
+<link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+{{.Body}}
+</body>
+</html>
+`))
+
+// epubPackage EPUB包文件 content.opf
+type epubPackage struct {
+	XMLName          xml.Name `xml:"http://www.idpf.org/2007/opf package"`
+	Version          string   `xml:"version,attr"`
+	UniqueIdentifier string   `xml:"unique-identifier,attr"`
+	Metadata         struct {
+		DC         string `xml:"xmlns:dc,attr"`
+		Identifier struct {
+			ID    string `xml:"id,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"dc:identifier"`
+		Title    string     `xml:"dc:title"`
+		Creator  string     `xml:"dc:creator,omitempty"`
+		Language string     `xml:"dc:language"`
+		Meta     []epubMeta `xml:"meta"`
+	} `xml:"metadata"`
+	Manifest []epubItem `xml:"manifest>item"`
+	Spine    epubSpine  `xml:"spine"`
+}
+
+// epubMeta 包文件元数据中的meta元素，EPUB 3使用property，封面使用EPUB 2兼容的name和content
+type epubMeta struct {
+	Name     string `xml:"name,attr,omitempty"`
+	Content  string `xml:"content,attr,omitempty"`
+	Property string `xml:"property,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+// epubItem 包文件清单中的一个文件
+type epubItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr,omitempty"`
+}
+
+// epubSpine 包文件中的阅读顺序
+type epubSpine struct {
+	Toc      string        `xml:"toc,attr"`
+	ItemRefs []epubItemRef `xml:"itemref"`
+}
+
+// epubItemRef 阅读顺序中的一个文件
+type epubItemRef struct {
+	IDRef string `xml:"idref,attr"`
+}
+
+// epubNCX 兼容EPUB 2阅读器的目录 toc.ncx
+type epubNCX struct {
+	XMLName xml.Name `xml:"http://www.daisy.org/z3986/2005/ncx/ ncx"`
+	Version string   `xml:"version,attr"`
+	UID     struct {
+		Name    string `xml:"name,attr"`
+		Content string `xml:"content,attr"`
+	} `xml:"head>meta"`
+	DocTitle  string         `xml:"docTitle>text"`
+	NavPoints []epubNavPoint `xml:"navMap>navPoint"`
+}
+
+// epubNavPoint toc.ncx 中的一个目录项
+type epubNavPoint struct {
+	ID        string `xml:"id,attr"`
+	PlayOrder int    `xml:"playOrder,attr"`
+	Label     string `xml:"navLabel>text"`
+	Content   struct {
+		Src string `xml:"src,attr"`
+	} `xml:"content"`
+}
+
+// WriteEPUB 将书籍写为EPUB 3电子书，包含封面页、目录和每章一个XHTML文件，图片打包在电子书中
+func (b *Book) WriteEPUB(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	// mimetype 必须是第一个文件且不压缩
+	mimetype, err := archive.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("写入EPUB失败: %v", err)
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return fmt.Errorf("写入EPUB失败: %v", err)
+	}
+
+	files := map[string][]byte{
+		"META-INF/container.xml": []byte(epubContainer),
+		"OEBPS/style.css":        []byte(epubStyle),
+	}
+	order := []string{"META-INF/container.xml", "OEBPS/style.css"}
+	add := func(name string, content []byte) {
+		files[name] = content
+		order = append(order, name)
+	}
+
+	pkg := &epubPackage{Version: "3.0", UniqueIdentifier: "book-id"}
+	pkg.Spine.Toc = "ncx"
+	manifest := func(item epubItem, spine bool) {
+		pkg.Manifest = append(pkg.Manifest, item)
+		if spine {
+			pkg.Spine.ItemRefs = append(pkg.Spine.ItemRefs, epubItemRef{IDRef: item.ID})
+		}
+	}
+	manifest(epubItem{ID: "style", Href: "style.css", MediaType: "text/css"}, false)
+
+	// 封面页
+	var cover strings.Builder
+	cover.WriteString(`<section class="cover" epub:type="cover">`)
+	if b.Cover != nil {
+		fmt.Fprintf(&cover, `<img src="%s" alt="%s"/>`, html.EscapeString(b.Cover.href()), html.EscapeString(b.Title))
+	}
+	fmt.Fprintf(&cover, `<h1>%s</h1>`, html.EscapeString(b.Title))
+	if b.Author != "" {
+		fmt.Fprintf(&cover, `<p>%s</p>`, html.EscapeString(b.Author))
+	}
+	cover.WriteString(`</section>`)
+	page, err := b.epubPage(b.Title, cover.String())
+	if err != nil {
+		return err
+	}
+	add("OEBPS/cover.xhtml", page)
+	manifest(epubItem{ID: "cover", Href: "cover.xhtml", MediaType: "application/xhtml+xml"}, true)
+
+	// 目录
+	var nav strings.Builder
+	fmt.Fprintf(&nav, `<nav epub:type="toc" id="toc"><h1>%s</h1><ol>`, html.EscapeString(b.contentsTitle()))
+	for _, chapter := range b.Chapters {
+		fmt.Fprintf(&nav, `<li><a href="%s.xhtml">%s</a></li>`, chapter.ID, html.EscapeString(chapter.Title))
+	}
+	nav.WriteString(`</ol></nav>`)
+	if page, err = b.epubPage(b.contentsTitle(), nav.String()); err != nil {
+		return err
+	}
+	add("OEBPS/nav.xhtml", page)
+	manifest(epubItem{ID: "nav", Href: "nav.xhtml", MediaType: "application/xhtml+xml", Properties: "nav"}, true)
+
+	ncx := &epubNCX{Version: "2005-1", DocTitle: b.Title}
+	for i, chapter := range b.Chapters {
+		body, err := toXHTML(string(chapter.Content))
+		if err != nil {
+			return fmt.Errorf("转换章节 %s 失败: %v", chapter.Path, err)
+		}
+		if page, err = b.epubPage(chapter.Title, body); err != nil {
+			return err
+		}
+		add("OEBPS/"+chapter.ID+".xhtml", page)
+		manifest(epubItem{ID: chapter.ID, Href: chapter.ID + ".xhtml", MediaType: "application/xhtml+xml"}, true)
+
+		point := epubNavPoint{ID: chapter.ID, PlayOrder: i + 1, Label: chapter.Title}
+		point.Content.Src = chapter.ID + ".xhtml"
+		ncx.NavPoints = append(ncx.NavPoints, point)
+	}
+
+	var coverID string
+	for i, image := range b.images() {
+		item := epubItem{ID: fmt.Sprintf("image-%d", i+1), Href: image.href(), MediaType: image.ContentType}
+		if b.Cover != nil && image.Path == b.Cover.Path {
+			item.Properties, coverID = "cover-image", item.ID
+		}
+		add("OEBPS/"+bookImageDir+image.Path, image.Data)
+		manifest(item, false)
+	}
+
+	identifier := b.identifier()
+	ncx.UID.Name, ncx.UID.Content = "dtb:uid", identifier
+	ncxContent, err := xml.MarshalIndent(ncx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成EPUB目录失败: %v", err)
+	}
+	add("OEBPS/toc.ncx", append([]byte(xml.Header), ncxContent...))
+	manifest(epubItem{ID: "ncx", Href: "toc.ncx", MediaType: "application/x-dtbncx+xml"}, false)
+
+	pkg.Metadata.DC = "http://purl.org/dc/elements/1.1/"
+	pkg.Metadata.Identifier.ID = "book-id"
+	pkg.Metadata.Identifier.Value = identifier
+	pkg.Metadata.Title = b.Title
+	pkg.Metadata.Creator = b.Author
+	pkg.Metadata.Language = b.Language
+	pkg.Metadata.Meta = append(pkg.Metadata.Meta, epubMeta{Property: "dcterms:modified", Value: time.Now().UTC().Format("2006-01-02T15:04:05Z")})
+	if coverID != "" {
+		pkg.Metadata.Meta = append(pkg.Metadata.Meta, epubMeta{Name: "cover", Content: coverID})
+	}
+	opf, err := xml.MarshalIndent(pkg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成EPUB包文件失败: %v", err)
+	}
+	add("OEBPS/content.opf", append([]byte(xml.Header), opf...))
+
+	for _, name := range order {
+		file, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("写入EPUB失败: %v", err)
+		}
+		if _, err := file.Write(files[name]); err != nil {
+			return fmt.Errorf("写入EPUB失败: %v", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("写入EPUB失败: %v", err)
+	}
+	return nil
+}
+
+// epubPage 生成EPUB中的XHTML页面，body 需要是合法的XHTML
+func (b *Book) epubPage(title, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	err := epubPage.Execute(&buf, struct {
+		Language string
+		Title    string
+		Body     template.HTML
+	}{b.Language, title, template.HTML(body)})
+	if err != nil {
+		return nil, fmt.Errorf("生成EPUB页面失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// identifier 根据书名和章节路径生成稳定的书籍标识
+func (b *Book) identifier() string {
+	hash := sha256.New()
+	io.WriteString(hash, b.Title)
+	for _, chapter := range b.Chapters {
+		io.WriteString(hash, "\x00"+chapter.Path)
+	}
+	sum := hash.Sum(nil)
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// toXHTML 将HTML片段规范化为XHTML：补全未闭合的标签、自闭合空元素并转义文本
+func toXHTML(fragment string) (string, error) {
+	body := &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), body)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	for _, node := range nodes {
+		if err := html.Render(&buf, node); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// HandleBook 将配置的文档合并为一本书返回，每篇文档为一章
+// URL格式: /book?format=html|pdf|epub，默认为html；html格式支持 mode=print 在渲染完成后自动打开打印对话框；
+// pdf格式需要开启 BookOptions.ServePDF，转换失败的详细原因只记录在日志中
+func (s *MarkdownServer) HandleBook(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
+	format := BookFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = BookFormatHTML
+	}
+	if format != BookFormatHTML && format != BookFormatPDF && format != BookFormatEPUB {
+		http.Error(w, fmt.Sprintf("不支持的书籍格式: %s", format), http.StatusBadRequest)
+		return nil
+	}
+
+	if format == BookFormatPDF {
+		if s.pdfSlots == nil {
+			http.Error(w, "服务器未开启PDF导出，请使用HTML格式在浏览器中打印", http.StatusForbidden)
+			return nil
+		}
+		select {
+		case s.pdfSlots <- struct{}{}:
+			defer func() { <-s.pdfSlots }()
+		default:
+			w.Header().Set("Retry-After", "10")
+			http.Error(w, "PDF导出繁忙，请稍后重试", http.StatusServiceUnavailable)
+			return nil
+		}
+	}
+
+	book, err := s.BuildBook(proj, s.book)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	var contentType string
+	switch format {
+	case BookFormatHTML:
+		_, printMode := viewMode(r)
+		contentType = "text/html; charset=utf-8"
+		err = book.writeHTML(&buf, printMode)
+	case BookFormatPDF:
+		contentType = "application/pdf"
+		err = book.WritePDF(r.Context(), &buf, s.book.PDFCommand)
+	case BookFormatEPUB:
+		contentType = "application/epub+zip"
+		err = book.WriteEPUB(&buf)
+	}
+	if err != nil && format == BookFormatPDF {
+		// 转换命令的输出可能包含服务器上的路径等信息，不返回给客户端
+		s.logger.Error("导出PDF失败", logging.KeyError, err)
+		http.Error(w, "导出PDF失败", http.StatusInternalServerError)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if format != BookFormatHTML {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": book.Title + "." + string(format)}))
+	}
+	w.Write(buf.Bytes())
+	return nil
+}
@@ -0,0 +1,28 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSandboxArgs(t *testing.T) {
+	chromium := pdfCommands[0]
+	if args := sandboxArgs(chromium, false); slices.Contains(args, "--no-sandbox") {
+		t.Errorf("非root用户不应关闭沙箱，实际为: %v", args)
+	}
+	args := sandboxArgs(chromium, true)
+	if len(args) < 2 || args[1] != "--no-sandbox" {
+		t.Errorf("root用户应关闭Chromium的沙箱，实际为: %v", args)
+	}
+	if slices.Contains(chromium, "--no-sandbox") {
+		t.Error("不应修改内置的命令")
+	}
+
+	wkhtmltopdf := pdfCommands[len(pdfCommands)-1]
+	if args := sandboxArgs(wkhtmltopdf, true); slices.Contains(args, "--no-sandbox") {
+		t.Errorf("wkhtmltopdf不需要关闭沙箱，实际为: %v", args)
+	}
+	if slices.Contains(wkhtmltopdf, "--enable-local-file-access") {
+		t.Error("wkhtmltopdf不应允许读取任意本地文件")
+	}
+}
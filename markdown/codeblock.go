@@ -0,0 +1,119 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+)
+
+// DecorateCodeBlocks 将围栏代码块转换为带语言标签、行号和复制按钮的HTML
+// 转换后的HTML写在一行内，换行以 &#10; 表示，避免代码中的空行中断Markdown的HTML块；
+// Mermaid代码块和未闭合的代码块保持原样，复制按钮的点击由页面脚本处理
+func (r *MarkdownRenderer) DecorateCodeBlocks(content string, options ProcessOptions) string {
+	if !options.CodeLanguageLabels && !options.CodeLineNumbers && !options.CodeCopyButton {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	result := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		block, end, ok := parseFencedCodeBlock(lines, i)
+		if !ok || block.language == "mermaid" {
+			result = append(result, lines[i])
+			continue
+		}
+		result = append(result, block.html(options))
+		i = end
+	}
+	return strings.Join(result, "\n")
+}
+
+// fencedCodeBlock 围栏代码块
+type fencedCodeBlock struct {
+	indent   string
+	language string
+	lines    []string
+}
+
+// parseFencedCodeBlock 解析从第start行开始的围栏代码块，返回代码块和结束围栏所在的行
+func parseFencedCodeBlock(lines []string, start int) (fencedCodeBlock, int, bool) {
+	marker, ok := isFenceLine(lines[start])
+	if !ok {
+		return fencedCodeBlock{}, 0, false
+	}
+
+	opening := lines[start]
+	trimmed := strings.TrimLeft(opening, " ")
+	indent := len(opening) - len(trimmed)
+	length := len(trimmed) - len(strings.TrimLeft(trimmed, marker[:1]))
+	info := strings.TrimSpace(trimmed[length:])
+	// 反引号围栏的信息字符串中不能包含反引号
+	if marker == "```" && strings.Contains(info, "`") {
+		return fencedCodeBlock{}, 0, false
+	}
+
+	block := fencedCodeBlock{indent: opening[:indent]}
+	if fields := strings.Fields(info); len(fields) > 0 {
+		block.language = strings.ToLower(fields[0])
+	}
+
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		closing := strings.TrimLeft(line, " ")
+		if len(line)-len(closing) <= 3 && strings.HasPrefix(closing, strings.Repeat(marker[:1], length)) &&
+			strings.TrimSpace(strings.TrimLeft(closing, marker[:1])) == "" {
+			return block, i, true
+		}
+
+		// 去掉与开始围栏相同的缩进
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		block.lines = append(block.lines, line)
+	}
+	return fencedCodeBlock{}, 0, false
+}
+
+// html 返回代码块的HTML
+func (b fencedCodeBlock) html(options ProcessOptions) string {
+	var sb strings.Builder
+	sb.WriteString(b.indent)
+	sb.WriteString(`<div class="code-block">`)
+
+	label := options.CodeLanguageLabels && b.language != ""
+	if label || options.CodeCopyButton {
+		sb.WriteString(`<div class="code-block-header">`)
+		if label {
+			sb.WriteString(`<span class="code-lang">` + html.EscapeString(b.language) + `</span>`)
+		}
+		if options.CodeCopyButton {
+			sb.WriteString(`<button type="button" class="code-copy" title="复制代码">复制</button>`)
+		}
+		sb.WriteString(`</div>`)
+	}
+
+	sb.WriteString(`<pre><code`)
+	var classes []string
+	if b.language != "" {
+		classes = append(classes, "language-"+html.EscapeString(b.language))
+	}
+	if options.CodeLineNumbers {
+		classes = append(classes, "line-numbers")
+	}
+	if len(classes) > 0 {
+		sb.WriteString(` class="` + strings.Join(classes, " ") + `"`)
+	}
+	sb.WriteString(`>`)
+
+	for i, line := range b.lines {
+		if i > 0 {
+			sb.WriteString("&#10;")
+		}
+		line = html.EscapeString(line)
+		if options.CodeLineNumbers {
+			line = `<span class="code-line">` + line + `</span>`
+		}
+		sb.WriteString(line)
+	}
+	sb.WriteString(`</code></pre></div>`)
+	return sb.String()
+}
@@ -0,0 +1,198 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxDiffEdits DiffLines 允许的最大编辑距离（插入行数加删除行数），
+// 搜索轨迹占用的内存与编辑距离的平方成正比，超过时返回 ErrDiffTooLarge
+const MaxDiffEdits = 2000
+
+// DiffOp 表示差异行的操作类型
+type DiffOp string
+
+const (
+	// DiffEqual 两侧相同的行
+	DiffEqual DiffOp = "equal"
+	// DiffInsert 仅在新文档中存在的行
+	DiffInsert DiffOp = "insert"
+	// DiffDelete 仅在旧文档中存在的行
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine 表示统一差异格式中的一行
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+	// OldLine 旧文档中的行号（从1开始），插入行为0
+	OldLine int
+	// NewLine 新文档中的行号（从1开始），删除行为0
+	NewLine int
+}
+
+// DiffRow 表示并排差异视图中的一行，左侧为旧文档，右侧为新文档
+type DiffRow struct {
+	Left  *DiffLine
+	Right *DiffLine
+}
+
+// DiffLines 按行比较两个文本，返回统一差异格式的结果
+// 使用Myers算法计算最短编辑脚本，编辑距离超过 MaxDiffEdits 时返回 ErrDiffTooLarge
+func DiffLines(a, b string) ([]DiffLine, error) {
+	oldLines := splitLines(a)
+	newLines := splitLines(b)
+
+	ops, ok := myersDiff(oldLines, newLines, MaxDiffEdits)
+	if !ok {
+		return nil, fmt.Errorf("%w: 超过 %d 行改动", ErrDiffTooLarge, MaxDiffEdits)
+	}
+
+	result := make([]DiffLine, 0, len(ops))
+	oldIndex, newIndex := 0, 0
+	for _, op := range ops {
+		switch op {
+		case DiffEqual:
+			result = append(result, DiffLine{Op: op, Text: oldLines[oldIndex], OldLine: oldIndex + 1, NewLine: newIndex + 1})
+			oldIndex++
+			newIndex++
+		case DiffDelete:
+			result = append(result, DiffLine{Op: op, Text: oldLines[oldIndex], OldLine: oldIndex + 1})
+			oldIndex++
+		case DiffInsert:
+			result = append(result, DiffLine{Op: op, Text: newLines[newIndex], NewLine: newIndex + 1})
+			newIndex++
+		}
+	}
+
+	return result, nil
+}
+
+// SideBySide 将统一差异结果转换为并排视图
+// 相邻的删除行和插入行会配对显示在同一行
+func SideBySide(lines []DiffLine) []DiffRow {
+	var rows []DiffRow
+	for i := 0; i < len(lines); {
+		if lines[i].Op == DiffEqual {
+			rows = append(rows, DiffRow{Left: &lines[i], Right: &lines[i]})
+			i++
+			continue
+		}
+
+		// 收集连续的删除和插入块
+		var deleted, inserted []*DiffLine
+		for ; i < len(lines) && lines[i].Op == DiffDelete; i++ {
+			deleted = append(deleted, &lines[i])
+		}
+		for ; i < len(lines) && lines[i].Op == DiffInsert; i++ {
+			inserted = append(inserted, &lines[i])
+		}
+
+		for j := 0; j < len(deleted) || j < len(inserted); j++ {
+			var row DiffRow
+			if j < len(deleted) {
+				row.Left = deleted[j]
+			}
+			if j < len(inserted) {
+				row.Right = inserted[j]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// splitLines 将文本拆分为行，忽略末尾的换行符
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// myersDiff 计算从a到b的最短编辑脚本，编辑距离超过limit时返回false
+func myersDiff(a, b []string, limit int) ([]DiffOp, bool) {
+	n, m := len(a), len(b)
+	maxEdits := min(n+m, limit)
+	if n+m == 0 {
+		return nil, true
+	}
+
+	offset := maxEdits
+	v := make([]int, 2*maxEdits+2)
+	var trace [][]int
+
+	for d := 0; d <= maxEdits; d++ {
+		// 仅保存本轮可能访问到的对角线范围，降低内存占用
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrackDiff(trace, a, b), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// backtrackDiff 根据Myers算法的搜索轨迹回溯出编辑操作序列
+// trace[d] 保存第d轮开始前对角线 -d..d 的状态
+func backtrackDiff(trace [][]int, a, b []string) []DiffOp {
+	x, y := len(a), len(b)
+	var ops []DiffOp
+
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[d+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, DiffEqual)
+			x--
+			y--
+		}
+
+		if x == prevX {
+			ops = append(ops, DiffInsert)
+		} else {
+			ops = append(ops, DiffDelete)
+		}
+		x, y = prevX, prevY
+	}
+
+	// 第0轮只包含公共前缀
+	for x > 0 && y > 0 {
+		ops = append(ops, DiffEqual)
+		x--
+		y--
+	}
+
+	// 回溯得到的是逆序结果
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
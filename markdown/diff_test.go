@@ -0,0 +1,85 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formatDiff 将差异结果格式化为 "+行"、"-行"、" 行" 形式，便于比较
+func formatDiff(lines []DiffLine) string {
+	var parts []string
+	for _, line := range lines {
+		prefix := map[DiffOp]string{DiffEqual: " ", DiffInsert: "+", DiffDelete: "-"}[line.Op]
+		parts = append(parts, fmt.Sprintf("%s%s@%d,%d", prefix, line.Text, line.OldLine, line.NewLine))
+	}
+	return strings.Join(parts, "|")
+}
+
+func TestDiffLines(t *testing.T) {
+	cases := []struct {
+		name, a, b, expected string
+	}{
+		{"相同", "a\nb\n", "a\nb", " a@1,1| b@2,2"},
+		{"插入", "a\nc", "a\nb\nc", " a@1,1|+b@0,2| c@2,3"},
+		{"删除", "a\nb\nc", "a\nc", " a@1,1|-b@2,0| c@3,2"},
+		{"替换", "a\nb\nc", "a\nx\nc", " a@1,1|-b@2,0|+x@0,2| c@3,3"},
+		{"新文档", "", "a\r\nb\r\n", "+a@0,1|+b@0,2"},
+		{"清空", "a", "", "-a@1,0"},
+		{"都为空", "", "", ""},
+	}
+	for _, c := range cases {
+		lines, err := DiffLines(c.a, c.b)
+		if err != nil {
+			t.Fatalf("%s: 对比失败: %v", c.name, err)
+		}
+		if got := formatDiff(lines); got != c.expected {
+			t.Errorf("%s: 期望 %q，实际为 %q", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	lines, _ := DiffLines("a\nb\nc\nd", "a\nx\ny\nd")
+	rows := SideBySide(lines)
+	if len(rows) != 4 {
+		t.Fatalf("期望 4 行，实际为 %d 行", len(rows))
+	}
+	if rows[1].Left.Text != "b" || rows[1].Right.Text != "x" || rows[2].Left.Text != "c" || rows[2].Right.Text != "y" {
+		t.Errorf("期望删除行和插入行配对显示，实际为 %+v %+v", rows[1], rows[2])
+	}
+}
+
+func TestDiffLinesTooLarge(t *testing.T) {
+	var a, b strings.Builder
+	for i := 0; i <= MaxDiffEdits/2; i++ {
+		fmt.Fprintf(&a, "old %d\n", i)
+		fmt.Fprintf(&b, "new %d\n", i)
+	}
+	if _, err := DiffLines(a.String(), b.String()); !errors.Is(err, ErrDiffTooLarge) {
+		t.Errorf("期望返回 ErrDiffTooLarge，实际为 %v", err)
+	}
+
+	// 行数多但改动少时仍然可以对比
+	large := strings.Repeat("same\n", 10*MaxDiffEdits)
+	lines, err := DiffLines(large+"old\n", large+"new\n")
+	if err != nil || len(lines) != 10*MaxDiffEdits+2 {
+		t.Errorf("期望对比成功，实际为 %d 行, %v", len(lines), err)
+	}
+}
+
+func TestReadDocumentVersionPaths(t *testing.T) {
+	options := DefaultServerOptions()
+	options.GitRoot = t.TempDir()
+	s, err := NewMarkdownServer(NewMarkdownManager(), NewMarkdownRenderer(), options)
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	for _, filePath := range []string{"/go.mod", ".env", "/.github/a.md", "/docs/../.git/x.md"} {
+		if _, err := s.readDocumentVersion(context.Background(), nil, filePath, "HEAD"); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("%s 期望返回 ErrInvalidPath，实际为 %v", filePath, err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DirTree 基于本地目录的项目树，实现ProjectTree接口
+// 节点路径使用以 / 开头的斜杠路径，相对于根目录；以 . 开头的隐藏文件和目录以及符号链接既不会被遍历，也不能通过 FindNode 访问
+type DirTree struct {
+	root string
+}
+
+// NewDirTree 创建以指定目录为根的项目树
+func NewDirTree(root string) (*DirTree, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("解析目录路径失败: %w", err)
+	}
+
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s 不是目录", root)
+	}
+
+	return &DirTree{root: absRoot}, nil
+}
+
+// Root 返回项目树的根目录
+func (t *DirTree) Root() string {
+	return t.root
+}
+
+// FindNode 根据路径查找节点，路径不能超出根目录，不能包含隐藏文件、隐藏目录或符号链接
+func (t *DirTree) FindNode(nodePath string) (NodeInfo, error) {
+	// 先按斜杠路径清理，确保 .. 不会超出根目录
+	cleaned := path.Clean("/" + filepath.ToSlash(nodePath))
+
+	filePath, err := resolveInRoot(t.root, cleaned)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPath, nodePath)
+		}
+		return nil, err
+	}
+
+	return &dirNode{tree: t, path: cleaned, info: info}, nil
+}
+
+// resolveInRoot 将清理过的斜杠路径解析为root下的本地路径，
+// 路径中不能有以 . 开头的部分，已存在的部分不能是符号链接，避免访问隐藏文件或通过链接访问root之外的文件
+func resolveInRoot(root, cleaned string) (string, error) {
+	filePath, exists := root, true
+	for _, segment := range strings.Split(strings.TrimPrefix(cleaned, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, ".") {
+			return "", fmt.Errorf("%w: %s", ErrInvalidPath, cleaned)
+		}
+		filePath = filepath.Join(filePath, segment)
+		if !exists {
+			continue
+		}
+		info, err := os.Lstat(filePath)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			exists = false
+		case err != nil:
+			return "", fmt.Errorf("读取路径失败: %w", err)
+		case info.Mode()&fs.ModeSymlink != 0:
+			return "", fmt.Errorf("%w: %s 包含符号链接", ErrInvalidPath, cleaned)
+		}
+	}
+	return filePath, nil
+}
+
+// Visit 遍历项目树中的所有节点，depth为节点相对根目录的层级，根目录为0
+func (t *DirTree) Visit(visitor func(path string, node NodeInfo, depth int) error) error {
+	return filepath.WalkDir(t.root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(t.root, filePath)
+		if err != nil {
+			return err
+		}
+		if rel != "." && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		nodePath := path.Clean("/" + filepath.ToSlash(rel))
+		depth := 0
+		if rel != "." {
+			depth = strings.Count(nodePath, "/")
+		}
+		return visitor(nodePath, &dirNode{tree: t, path: nodePath, info: info}, depth)
+	})
+}
+
+// dirNode 本地目录中的文件或目录节点
+type dirNode struct {
+	tree *DirTree
+	path string
+	info os.FileInfo
+}
+
+// GetName 获取节点名称
+func (n *dirNode) GetName() string {
+	return n.info.Name()
+}
+
+// GetPath 获取节点路径
+func (n *dirNode) GetPath() string {
+	return n.path
+}
+
+// IsDir 判断是否为目录
+func (n *dirNode) IsDir() bool {
+	return n.info.IsDir()
+}
+
+// GetFileInfo 获取文件信息
+func (n *dirNode) GetFileInfo() os.FileInfo {
+	return n.info
+}
+
+// ReadContent 读取节点内容
+func (n *dirNode) ReadContent() ([]byte, error) {
+	if n.IsDir() {
+		return nil, fmt.Errorf("%s 是目录", n.path)
+	}
+	return os.ReadFile(filepath.Join(n.tree.root, filepath.FromSlash(n.path)))
+}
+
+// Open 打开文件内容，调用方负责关闭
+func (n *dirNode) Open() (io.ReadSeekCloser, error) {
+	if n.IsDir() {
+		return nil, fmt.Errorf("%s 是目录", n.path)
+	}
+	return os.Open(filepath.Join(n.tree.root, filepath.FromSlash(n.path)))
+}
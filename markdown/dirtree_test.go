@@ -0,0 +1,79 @@
+package markdown
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirTreeFindNode(t *testing.T) {
+	base := t.TempDir()
+	root, outside := filepath.Join(base, "root"), filepath.Join(base, "outside")
+	files := map[string]string{
+		"root/docs/a.md":    "# A",
+		"root/.env":         "SECRET=1",
+		"root/.git/config":  "[core]",
+		"root/notes.txt":    "notes",
+		"outside/secret.md": "secret",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(base, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+	}
+	symlinks := os.Symlink(outside, filepath.Join(root, "link")) == nil &&
+		os.Symlink(filepath.Join(outside, "secret.md"), filepath.Join(root, "secret.md")) == nil
+
+	tree, err := NewDirTree(root)
+	if err != nil {
+		t.Fatalf("创建项目树失败: %v", err)
+	}
+
+	for _, nodePath := range []string{"/", "/docs", "/docs/a.md", "/../../docs/a.md", "/notes.txt"} {
+		if _, err := tree.FindNode(nodePath); err != nil {
+			t.Errorf("期望找到 %s，实际为 %v", nodePath, err)
+		}
+	}
+	invalid := []string{"/.env", "/.git/config", "/docs/../.env", "/missing.md"}
+	if symlinks {
+		invalid = append(invalid, "/link/secret.md", "/secret.md", "/link")
+	}
+	for _, nodePath := range invalid {
+		if _, err := tree.FindNode(nodePath); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("%s 期望返回 ErrInvalidPath，实际为 %v", nodePath, err)
+		}
+	}
+
+	var visited []string
+	tree.Visit(func(nodePath string, node NodeInfo, depth int) error {
+		visited = append(visited, nodePath)
+		return nil
+	})
+	expected := []string{"/", "/docs", "/docs/a.md", "/notes.txt"}
+	if len(visited) != len(expected) {
+		t.Fatalf("期望遍历 %v，实际为 %v", expected, visited)
+	}
+	for i := range expected {
+		if visited[i] != expected[i] {
+			t.Errorf("期望遍历 %v，实际为 %v", expected, visited)
+			break
+		}
+	}
+
+	// 文档接口只读取Markdown文件
+	s, err := NewMarkdownServer(NewMarkdownManager(), NewMarkdownRenderer(), DefaultServerOptions())
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	if _, _, err := s.readDocument(tree, "/notes.txt"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("读取非Markdown文件期望返回 ErrInvalidPath，实际为 %v", err)
+	}
+	if content, _, err := s.readDocument(tree, "/docs/a.md"); err != nil || string(content) != "# A" {
+		t.Errorf("读取文档失败: %q, %v", content, err)
+	}
+}
@@ -0,0 +1,134 @@
+package markdown
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// MaxDocumentSize 通过文档管理接口写入的文档的最大字节数
+const MaxDocumentSize = 10 << 20
+
+// documentResult 文档管理接口成功时返回的文档信息
+type documentResult struct {
+	Path string `json:"path"` // 文档路径
+	URL  string `json:"url"`  // 文档的查看地址
+}
+
+// documentMove 移动或重命名文档的请求，Path 和 Name 只能设置一个
+type documentMove struct {
+	Path string `json:"path,omitempty"` // 移动到的新路径
+	Name string `json:"name,omitempty"` // 同一目录下的新文件名
+}
+
+// HandleDocuments 处理文档管理请求，请求需携带 Authorization: Bearer [AdminToken]，
+// 服务器的Manager需要实现 DocumentManager，例如 FileManager
+// URL格式: /api/docs/[文件路径]
+//   - POST 新建文档，请求体为Markdown内容，文档已存在时返回409
+//   - PUT 更新已存在的文档，请求体为Markdown内容
+//   - DELETE 删除文档
+//   - PATCH 移动或重命名文档，请求体为 {"path": "/新路径.md"} 或 {"name": "新文件名.md"}
+func (s *MarkdownServer) HandleDocuments(w http.ResponseWriter, r *http.Request) error {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="markdown"`)
+		http.Error(w, "未授权", http.StatusUnauthorized)
+		return nil
+	}
+
+	manager, ok := s.manager.(DocumentManager)
+	if !ok {
+		http.Error(w, "文档管理器不支持修改文档", http.StatusNotImplemented)
+		return nil
+	}
+
+	docPath := strings.TrimPrefix(r.URL.Path, "/api/docs")
+	if docPath == "" || docPath == "/" {
+		http.Error(w, "文件路径不能为空", http.StatusBadRequest)
+		return nil
+	}
+
+	var err error
+	status := http.StatusOK
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+		var content []byte
+		content, err = io.ReadAll(http.MaxBytesReader(w, r.Body, MaxDocumentSize))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("读取请求内容失败: %v", err), http.StatusRequestEntityTooLarge)
+			return nil
+		}
+		if r.Method == http.MethodPost {
+			status = http.StatusCreated
+			err = manager.Create(docPath, string(content))
+		} else {
+			err = manager.UpdateContent(docPath, string(content))
+		}
+	case http.MethodDelete:
+		if err = manager.DeleteContent(docPath); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+	case http.MethodPatch:
+		var move documentMove
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxDocumentSize)).Decode(&move); err != nil {
+			http.Error(w, fmt.Sprintf("解析请求内容失败: %v", err), http.StatusBadRequest)
+			return nil
+		}
+		switch {
+		case (move.Path == "") == (move.Name == ""):
+			http.Error(w, "path 和 name 必须设置其中一个", http.StatusBadRequest)
+			return nil
+		case move.Path != "":
+			if err = manager.Move(docPath, move.Path); err == nil {
+				docPath = move.Path
+			}
+		default:
+			var renamed string
+			if renamed, err = manager.Rename(docPath, move.Name); err == nil {
+				docPath = renamed
+			}
+		}
+	default:
+		w.Header().Set("Allow", "POST, PUT, DELETE, PATCH")
+		http.Error(w, "不支持的请求方法", http.StatusMethodNotAllowed)
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, ErrInvalidPath):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil
+	case errors.Is(err, ErrContentNotFound):
+		http.Error(w, fmt.Sprintf("文档不存在: %s", docPath), http.StatusNotFound)
+		return nil
+	case errors.Is(err, ErrContentExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return nil
+	case err != nil:
+		return err
+	}
+
+	docPath = path.Clean("/" + docPath)
+	s.logger.Info("文档已修改", "method", r.Method, "path", docPath)
+	result := documentResult{Path: docPath, URL: s.basePath + "/view" + docPath}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if status == http.StatusCreated {
+		w.Header().Set("Location", result.URL)
+	}
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(result)
+}
+
+// authorized 判断请求是否携带了正确的管理令牌，没有设置令牌时拒绝所有请求
+func (s *MarkdownServer) authorized(r *http.Request) bool {
+	if s.adminToken == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
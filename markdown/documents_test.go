@@ -0,0 +1,117 @@
+package markdown
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestDocumentServer 创建使用 FileManager 和管理令牌的服务器
+func newTestDocumentServer(t *testing.T) (*MarkdownServer, *FileManager, string) {
+	t.Helper()
+	m, outside := newTestFileManager(t)
+	options := DefaultServerOptions()
+	options.AdminToken = "secret-token"
+	s, err := NewMarkdownServer(m, NewMarkdownRenderer(), options)
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	return s, m, outside
+}
+
+// docRequest 发送文档管理请求，token 为空时不携带令牌
+func docRequest(t *testing.T, s *MarkdownServer, method, docPath, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, "/api/docs"+docPath, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	if err := s.HandleDocuments(w, r); err != nil {
+		t.Fatalf("%s %s 失败: %v", method, docPath, err)
+	}
+	return w
+}
+
+func TestHandleDocumentsAuth(t *testing.T) {
+	s, m, _ := newTestDocumentServer(t)
+
+	for _, token := range []string{"", "wrong-token"} {
+		w := docRequest(t, s, http.MethodPost, "/a.md", "# A", token)
+		if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("令牌 %q 期望返回401，实际为 %d", token, w.Code)
+		}
+	}
+	if _, ok := m.GetContent("/a.md"); ok {
+		t.Error("未授权的请求不应新建文档")
+	}
+
+	// 没有设置令牌时拒绝所有请求
+	s.adminToken = ""
+	if w := docRequest(t, s, http.MethodPost, "/a.md", "# A", "secret-token"); w.Code != http.StatusUnauthorized {
+		t.Errorf("没有设置令牌时期望返回401，实际为 %d", w.Code)
+	}
+}
+
+func TestHandleDocuments(t *testing.T) {
+	s, m, outside := newTestDocumentServer(t)
+	const token = "secret-token"
+
+	w := docRequest(t, s, http.MethodPost, "/docs/a.md", "# A", token)
+	if w.Code != http.StatusCreated || w.Header().Get("Location") != "/view/docs/a.md" {
+		t.Fatalf("新建文档期望返回201和Location，实际为 %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if w := docRequest(t, s, http.MethodPost, "/docs/a.md", "# A2", token); w.Code != http.StatusConflict {
+		t.Errorf("重复新建期望返回409，实际为 %d", w.Code)
+	}
+	if w := docRequest(t, s, http.MethodPut, "/missing.md", "# M", token); w.Code != http.StatusNotFound {
+		t.Errorf("更新不存在的文档期望返回404，实际为 %d", w.Code)
+	}
+
+	if w := docRequest(t, s, http.MethodPost, "/b.md", "# B", token); w.Code != http.StatusCreated {
+		t.Fatalf("新建文档失败: %d", w.Code)
+	}
+	if w := docRequest(t, s, http.MethodPatch, "/docs/a.md", `{"path": "/b.md"}`, token); w.Code != http.StatusConflict {
+		t.Errorf("移动到已存在的文档期望返回409，实际为 %d", w.Code)
+	}
+	if w := docRequest(t, s, http.MethodPatch, "/docs/a.md", `{"path": "/b.md", "name": "c.md"}`, token); w.Code != http.StatusBadRequest {
+		t.Errorf("同时设置path和name期望返回400，实际为 %d", w.Code)
+	}
+
+	w = docRequest(t, s, http.MethodPatch, "/docs/a.md", `{"name": "renamed.md"}`, token)
+	var result documentResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("重命名失败: %d %v", w.Code, err)
+	}
+	if result.Path != "/docs/renamed.md" || result.URL != "/view/docs/renamed.md" {
+		t.Errorf("重命名后期望返回新路径，实际为 %+v", result)
+	}
+	if content, ok := m.GetContent("/docs/renamed.md"); !ok || content != "# A" {
+		t.Errorf("重命名后期望读取到原内容，实际为 %q, %v", content, ok)
+	}
+
+	for _, docPath := range []string{"/.git/x.md", "/docs/.hidden.md", "/a.txt"} {
+		if w := docRequest(t, s, http.MethodPost, docPath, "x", token); w.Code != http.StatusBadRequest {
+			t.Errorf("%s 期望返回400，实际为 %d", docPath, w.Code)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(m.Root(), "link")); err == nil {
+		if w := docRequest(t, s, http.MethodPost, "/link/x.md", "x", token); w.Code != http.StatusBadRequest {
+			t.Errorf("符号链接目录期望返回400，实际为 %d", w.Code)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "x.md")); err == nil {
+			t.Error("不应在根目录之外新建文件")
+		}
+	}
+
+	if w := docRequest(t, s, http.MethodDelete, "/docs/renamed.md", "", token); w.Code != http.StatusNoContent {
+		t.Errorf("删除文档期望返回204，实际为 %d", w.Code)
+	}
+	if w := docRequest(t, s, http.MethodDelete, "/docs/renamed.md", "", token); w.Code != http.StatusNotFound {
+		t.Errorf("删除不存在的文档期望返回404，实际为 %d", w.Code)
+	}
+}
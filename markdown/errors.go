@@ -5,6 +5,8 @@ import "errors"
 // 定义包内使用的错误类型
 var (
 	ErrContentNotFound = errors.New("markdown content not found")
+	ErrContentExists   = errors.New("markdown content already exists")
 	ErrInvalidPath     = errors.New("invalid markdown path")
 	ErrRenderFailed    = errors.New("markdown render failed")
+	ErrDiffTooLarge    = errors.New("markdown diff too large")
 )
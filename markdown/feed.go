@@ -0,0 +1,307 @@
+package markdown
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSiteTitle 未配置 SiteTitle 时RSS订阅使用的标题
+const DefaultSiteTitle = "Markdown 文档中心"
+
+// feedItemLimit RSS订阅中最多包含的文档数
+const feedItemLimit = 50
+
+// frontMatter 文档开头的YAML元数据中用到的字段
+type frontMatter struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Date        string `yaml:"date"`
+	Updated     string `yaml:"updated"`
+	LastMod     string `yaml:"lastmod"`
+	Draft       bool   `yaml:"draft"`
+}
+
+// frontMatterDateLayouts front matter中支持的日期格式
+var frontMatterDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// parseFrontMatter 解析文档开头以 --- 包围的YAML元数据，返回元数据和去掉元数据后的正文
+// 没有元数据或元数据无法解析时返回零值和原内容
+func parseFrontMatter(content string) (frontMatter, string) {
+	var meta frontMatter
+	normalized := strings.TrimPrefix(strings.ReplaceAll(content, "\r\n", "\n"), "\ufeff")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return meta, content
+	}
+
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return meta, content
+	}
+	body := rest[end+len("\n---"):]
+	if i := strings.IndexByte(body, '\n'); i >= 0 {
+		if strings.TrimSpace(body[:i]) != "" {
+			return meta, content
+		}
+		body = body[i+1:]
+	} else if strings.TrimSpace(body) != "" {
+		return meta, content
+	} else {
+		body = ""
+	}
+
+	if err := yaml.Unmarshal([]byte(rest[:end]), &meta); err != nil {
+		return frontMatter{}, content
+	}
+	return meta, body
+}
+
+// parseFrontMatterDate 按支持的格式解析日期，无法解析时返回零值
+func parseFrontMatterDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range frontMatterDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// siteDocument 站点地图和RSS订阅中的一篇文档
+type siteDocument struct {
+	Path        string
+	Title       string
+	Description string
+	Published   time.Time // front matter中的date，没有时使用修改时间
+	Updated     time.Time // front matter中的updated/lastmod，没有时使用发布时间
+}
+
+// siteDocuments 遍历项目树收集所有markdown文档，跳过 draft: true 的文档
+func (s *MarkdownServer) siteDocuments(proj ProjectTree) ([]siteDocument, error) {
+	var docs []siteDocument
+
+	err := proj.Visit(func(filePath string, node NodeInfo, depth int) error {
+		if node.IsDir() || !strings.HasSuffix(strings.ToLower(node.GetName()), ".md") {
+			return nil
+		}
+
+		content, err := node.ReadContent()
+		if err != nil {
+			return nil
+		}
+		meta, body := parseFrontMatter(string(content))
+		if meta.Draft {
+			return nil
+		}
+
+		doc := siteDocument{
+			Path:        "/" + strings.TrimPrefix(filePath, "/"),
+			Title:       meta.Title,
+			Description: meta.Description,
+			Published:   parseFrontMatterDate(meta.Date),
+			Updated:     parseFrontMatterDate(meta.Updated),
+		}
+		if doc.Updated.IsZero() {
+			doc.Updated = parseFrontMatterDate(meta.LastMod)
+		}
+
+		title, description := s.renderer.ExtractTitleAndDescription(body)
+		if doc.Title == "" {
+			doc.Title = title
+		}
+		if doc.Title == "" {
+			doc.Title = node.GetName()
+		}
+		if doc.Description == "" {
+			doc.Description = description
+		}
+
+		// 没有front matter日期时使用文件修改时间
+		if doc.Published.IsZero() {
+			if info := node.GetFileInfo(); info != nil {
+				doc.Published = info.ModTime()
+			}
+		}
+		if doc.Updated.IsZero() || doc.Updated.Before(doc.Published) {
+			doc.Updated = doc.Published
+		}
+
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Path < docs[j].Path
+	})
+	return docs, nil
+}
+
+// siteURL 返回站点的绝对地址（含挂载前缀），配置了 SiteURL 时按原样返回，否则根据请求推断；
+// 只有开启 TrustProxy 时才使用 X-Forwarded-* 请求头
+func (s *MarkdownServer) siteURL(r *http.Request) string {
+	if s.siteBaseURL != "" {
+		return s.siteBaseURL
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	if s.trustProxy {
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+		}
+		if forwarded := r.Header.Get("X-Forwarded-Host"); forwarded != "" {
+			host = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	return scheme + "://" + host + s.basePath
+}
+
+// documentURL 返回文档查看页面的绝对地址
+func documentURL(siteURL, filePath string) string {
+	u := url.URL{Path: path.Join("/view", filePath)}
+	return siteURL + u.EscapedPath()
+}
+
+// sitemapURLSet 站点地图的根元素
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemapURL 站点地图中的一个地址
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// HandleSitemap 生成包含所有markdown文档的 sitemap.xml，lastmod 优先使用front matter中的日期
+func (s *MarkdownServer) HandleSitemap(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
+	docs, err := s.siteDocuments(proj)
+	if err != nil {
+		return fmt.Errorf("获取markdown文件失败: %v", err)
+	}
+
+	siteURL := s.siteURL(r)
+	urlset := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	urlset.URLs = append(urlset.URLs, sitemapURL{Loc: siteURL + "/"})
+	for _, doc := range docs {
+		item := sitemapURL{Loc: documentURL(siteURL, doc.Path)}
+		if !doc.Updated.IsZero() {
+			item.LastMod = doc.Updated.UTC().Format(time.RFC3339)
+		}
+		urlset.URLs = append(urlset.URLs, item)
+	}
+
+	return writeXML(w, urlset)
+}
+
+// rssFeed RSS 2.0 订阅的根元素
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel RSS频道
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssItem RSS条目
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description,omitempty"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+}
+
+// rssGUID RSS条目的唯一标识
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// HandleFeed 生成最近发布的markdown文档的RSS订阅 feed.xml，按发布时间倒序
+func (s *MarkdownServer) HandleFeed(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
+	docs, err := s.siteDocuments(proj)
+	if err != nil {
+		return fmt.Errorf("获取markdown文件失败: %v", err)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].Published.After(docs[j].Published)
+	})
+	if len(docs) > feedItemLimit {
+		docs = docs[:feedItemLimit]
+	}
+
+	siteURL := s.siteURL(r)
+	channel := rssChannel{
+		Title:       s.siteTitle,
+		Link:        siteURL + "/",
+		Description: s.siteTitle,
+	}
+	for _, doc := range docs {
+		link := documentURL(siteURL, doc.Path)
+		item := rssItem{
+			Title:       doc.Title,
+			Link:        link,
+			GUID:        rssGUID{Value: link, IsPermaLink: true},
+			Description: doc.Description,
+		}
+		if !doc.Published.IsZero() {
+			item.PubDate = doc.Published.Format(time.RFC1123Z)
+		}
+		channel.Items = append(channel.Items, item)
+	}
+	if len(docs) > 0 && !docs[0].Published.IsZero() {
+		channel.LastBuildDate = docs[0].Published.Format(time.RFC1123Z)
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	return writeXML(w, rssFeed{Version: "2.0", Channel: channel})
+}
+
+// writeXML 输出带XML声明的文档，未设置Content-Type时使用 application/xml
+func writeXML(w http.ResponseWriter, v any) error {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	}
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("生成XML失败: %v", err)
+	}
+	return nil
+}
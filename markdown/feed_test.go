@@ -0,0 +1,97 @@
+package markdown
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSiteURL(t *testing.T) {
+	forwarded := map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "evil.example, proxy"}
+	cases := []struct {
+		name     string
+		options  func(*ServerOptions)
+		expected string
+	}{
+		{"请求地址", func(o *ServerOptions) {}, "http://docs.local/docs"},
+		{"信任代理", func(o *ServerOptions) { o.TrustProxy = true }, "https://evil.example/docs"},
+		{"配置地址", func(o *ServerOptions) { o.SiteURL = "https://example.com/docs/" }, "https://example.com/docs"},
+	}
+	for _, c := range cases {
+		options := DefaultServerOptions()
+		options.BasePath = "/docs"
+		c.options(&options)
+		s, err := NewMarkdownServer(NewMarkdownManager(), NewMarkdownRenderer(), options)
+		if err != nil {
+			t.Fatalf("%s: 创建服务器失败: %v", c.name, err)
+		}
+		r := httptest.NewRequest("GET", "http://docs.local/docs/sitemap.xml", nil)
+		for key, value := range forwarded {
+			r.Header.Set(key, value)
+		}
+		if got := s.siteURL(r); got != c.expected {
+			t.Errorf("%s: 期望站点地址为 %s，实际为 %s", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestSitemapAndFeed(t *testing.T) {
+	root := t.TempDir()
+	docs := map[string]string{
+		"a.md":          "---\ntitle: 第一篇\ndate: 2024-01-02\n---\n# A",
+		"guide/b c.md":  "---\ntitle: 第二篇\ndate: 2024-03-04\n---\n# B",
+		"draft.md":      "---\ndraft: true\n---\n# Draft",
+		"guide/img.png": "png",
+	}
+	for name, content := range docs {
+		filePath := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(filePath), 0o755)
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+	}
+	tree, err := NewDirTree(root)
+	if err != nil {
+		t.Fatalf("创建项目树失败: %v", err)
+	}
+	options := DefaultServerOptions()
+	options.SiteURL = "https://example.com/docs"
+	s, err := NewMarkdownServer(NewMarkdownManager(), NewMarkdownRenderer(), options)
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	if err := s.HandleSitemap(w, httptest.NewRequest("GET", "/sitemap.xml", nil), tree); err != nil {
+		t.Fatalf("生成站点地图失败: %v", err)
+	}
+	sitemap := w.Body.String()
+	for _, expected := range []string{
+		"<loc>https://example.com/docs/</loc>",
+		"<loc>https://example.com/docs/view/a.md</loc>",
+		"<loc>https://example.com/docs/view/guide/b%20c.md</loc>",
+		"<lastmod>2024-03-04T00:00:00Z</lastmod>",
+	} {
+		if !strings.Contains(sitemap, expected) {
+			t.Errorf("站点地图期望包含 %s，实际为: %s", expected, sitemap)
+		}
+	}
+	if strings.Contains(sitemap, "draft.md") || strings.Contains(sitemap, "img.png") {
+		t.Errorf("站点地图不应包含草稿和非Markdown文件: %s", sitemap)
+	}
+
+	w = httptest.NewRecorder()
+	if err := s.HandleFeed(w, httptest.NewRequest("GET", "/feed.xml", nil), tree); err != nil {
+		t.Fatalf("生成RSS订阅失败: %v", err)
+	}
+	feed := w.Body.String()
+	first, second := strings.Index(feed, "<title>第二篇</title>"), strings.Index(feed, "<title>第一篇</title>")
+	if first < 0 || second < 0 || first > second {
+		t.Errorf("RSS订阅期望按发布时间倒序包含两篇文档，实际为: %s", feed)
+	}
+	if !strings.Contains(feed, "<link>https://example.com/docs/</link>") {
+		t.Errorf("RSS订阅期望链接到站点地址，实际为: %s", feed)
+	}
+}
@@ -0,0 +1,288 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DocumentManager 支持新建、移动和重命名文档的Manager，服务器的文档管理接口需要此接口
+type DocumentManager interface {
+	Manager
+	// Create 新建文档，文档已存在时返回 ErrContentExists
+	Create(path, content string) error
+	// Move 将文档移动到新路径，目标已存在时返回 ErrContentExists
+	Move(from, to string) error
+	// Rename 在同一目录下重命名文档，返回新路径
+	Rename(path, name string) (string, error)
+}
+
+// FileManager 基于本地目录的Manager实现，文档保存为根目录下的Markdown文件
+// 路径为以 / 开头的斜杠路径，与 DirTree 的节点路径相同；只能管理 .md 和 .markdown 文件，
+// 不能访问根目录之外和以 . 开头的隐藏文件或目录，路径中的符号链接会被拒绝，避免通过链接访问根目录之外的文件
+type FileManager struct {
+	root string
+	mu   sync.Mutex // 串行化写操作，避免并发的新建和移动互相覆盖
+}
+
+// NewFileManager 创建以指定目录为根的文档管理器
+func NewFileManager(root string) (*FileManager, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("解析目录路径失败: %w", err)
+	}
+
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s 不是目录", root)
+	}
+
+	return &FileManager{root: absRoot}, nil
+}
+
+// Root 返回文档管理器的根目录
+func (m *FileManager) Root() string {
+	return m.root
+}
+
+// AddContent 写入文档，文档不存在时新建，存在时覆盖
+func (m *FileManager) AddContent(docPath, content string) error {
+	filePath, err := m.resolve(docPath)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return writeFileAtomic(filePath, content)
+}
+
+// GetContent 读取文档内容
+func (m *FileManager) GetContent(docPath string) (string, bool) {
+	filePath, err := m.resolve(docPath)
+	if err != nil {
+		return "", false
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+// UpdateContent 更新已存在的文档，不存在时返回 ErrContentNotFound
+func (m *FileManager) UpdateContent(docPath, content string) error {
+	filePath, err := m.resolve(docPath)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := requireFile(filePath); err != nil {
+		return err
+	}
+	return writeFileAtomic(filePath, content)
+}
+
+// DeleteContent 删除文档，不存在时返回 ErrContentNotFound；删除后不会清理空目录
+func (m *FileManager) DeleteContent(docPath string) error {
+	filePath, err := m.resolve(docPath)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := requireFile(filePath); err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil {
+		return fmt.Errorf("删除文档失败: %w", err)
+	}
+	return nil
+}
+
+// ListContents 列出根目录下所有文档的路径，跳过隐藏文件和目录
+func (m *FileManager) ListContents() []string {
+	var paths []string
+	m.walk(func(docPath, filePath string) {
+		paths = append(paths, docPath)
+	})
+	return paths
+}
+
+// GetAllContent 读取根目录下所有文档，读取失败的文档会被跳过
+func (m *FileManager) GetAllContent() map[string]string {
+	contents := make(map[string]string)
+	m.walk(func(docPath, filePath string) {
+		if content, err := os.ReadFile(filePath); err == nil {
+			contents[docPath] = string(content)
+		}
+	})
+	return contents
+}
+
+// Clear 删除根目录下所有的Markdown文档，其他文件和目录保持不变
+func (m *FileManager) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.walk(func(docPath, filePath string) {
+		os.Remove(filePath)
+	})
+}
+
+// Create 新建文档，自动创建所在目录，文档已存在时返回 ErrContentExists
+func (m *FileManager) Create(docPath, content string) error {
+	filePath, err := m.resolve(docPath)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrContentExists, docPath)
+		}
+		return fmt.Errorf("创建文档失败: %w", err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		os.Remove(filePath)
+		return fmt.Errorf("写入文档失败: %w", err)
+	}
+	return file.Close()
+}
+
+// Move 将文档移动到新路径，自动创建目标目录，目标已存在时返回 ErrContentExists
+func (m *FileManager) Move(from, to string) error {
+	fromPath, err := m.resolve(from)
+	if err != nil {
+		return err
+	}
+	toPath, err := m.resolve(to)
+	if err != nil {
+		return err
+	}
+	if fromPath == toPath {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := requireFile(fromPath); err != nil {
+		return err
+	}
+	if _, err := os.Lstat(toPath); err == nil {
+		return fmt.Errorf("%w: %s", ErrContentExists, to)
+	}
+	if err := os.MkdirAll(filepath.Dir(toPath), 0o755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.Rename(fromPath, toPath); err != nil {
+		return fmt.Errorf("移动文档失败: %w", err)
+	}
+	return nil
+}
+
+// Rename 在同一目录下重命名文档，name 为新的文件名，不能包含路径分隔符
+func (m *FileManager) Rename(docPath, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("%w: 文件名 %q 无效", ErrInvalidPath, name)
+	}
+	newPath := path.Join(path.Dir(path.Clean("/"+filepath.ToSlash(docPath))), name)
+	if err := m.Move(docPath, newPath); err != nil {
+		return "", err
+	}
+	return newPath, nil
+}
+
+// resolve 校验文档路径并返回对应的本地文件路径，路径中已存在的部分不能是符号链接
+func (m *FileManager) resolve(docPath string) (string, error) {
+	cleaned := path.Clean("/" + filepath.ToSlash(docPath))
+	if cleaned == "/" || !isMarkdownFile(cleaned) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPath, docPath)
+	}
+	return resolveInRoot(m.root, cleaned)
+}
+
+// walk 遍历根目录下的Markdown文档，跳过隐藏文件和目录
+func (m *FileManager) walk(visit func(docPath, filePath string)) {
+	filepath.WalkDir(m.root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(m.root, filePath)
+		if err != nil || rel == "." {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() && isMarkdownFile(entry.Name()) {
+			visit("/"+filepath.ToSlash(rel), filePath)
+		}
+		return nil
+	})
+}
+
+// isMarkdownFile 判断文件名是否为Markdown文件
+func isMarkdownFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// requireFile 检查文件是否存在，不存在时返回 ErrContentNotFound
+func requireFile(filePath string) error {
+	info, err := os.Stat(filePath)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && info.IsDir()) {
+		return ErrContentNotFound
+	}
+	return err
+}
+
+// writeFileAtomic 先写入同目录下的临时文件再重命名，避免写入中途失败时留下不完整的文档
+func writeFileAtomic(filePath, content string) error {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	temp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.WriteString(content); err != nil {
+		temp.Close()
+		return fmt.Errorf("写入文档失败: %w", err)
+	}
+	if err := temp.Chmod(0o644); err != nil {
+		temp.Close()
+		return fmt.Errorf("写入文档失败: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("写入文档失败: %w", err)
+	}
+	if err := os.Rename(temp.Name(), filePath); err != nil {
+		return fmt.Errorf("写入文档失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package markdown
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestFileManager 创建以临时目录为根的文档管理器，同时返回根目录之外的目录
+func newTestFileManager(t *testing.T) (*FileManager, string) {
+	t.Helper()
+	base := t.TempDir()
+	root, outside := filepath.Join(base, "root"), filepath.Join(base, "outside")
+	for _, dir := range []string{root, outside} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+	}
+	m, err := NewFileManager(root)
+	if err != nil {
+		t.Fatalf("创建文档管理器失败: %v", err)
+	}
+	return m, outside
+}
+
+func TestFileManagerPaths(t *testing.T) {
+	m, outside := newTestFileManager(t)
+
+	// 路径按根目录清理，../ 不能跳出根目录
+	if err := m.Create("/../../escape.md", "# Escape"); err != nil {
+		t.Fatalf("新建文档失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(m.Root(), "escape.md")); err != nil {
+		t.Errorf("期望文档写入根目录: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "..", "escape.md")); err == nil {
+		t.Error("文档不应写到根目录之外")
+	}
+
+	for _, docPath := range []string{"/", "/a.txt", "/.git/config.md", "/a/.hidden/b.md", "/.draft.md"} {
+		if err := m.Create(docPath, "x"); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("%s 期望返回 ErrInvalidPath，实际为 %v", docPath, err)
+		}
+	}
+}
+
+func TestFileManagerSymlinks(t *testing.T) {
+	m, outside := newTestFileManager(t)
+	secret := filepath.Join(outside, "secret.md")
+	if err := os.WriteFile(secret, []byte("secret"), 0o644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(m.Root(), "link")); err != nil {
+		t.Skipf("不支持符号链接: %v", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(m.Root(), "secret.md")); err != nil {
+		t.Fatalf("创建符号链接失败: %v", err)
+	}
+
+	for _, docPath := range []string{"/link/secret.md", "/secret.md"} {
+		if _, ok := m.GetContent(docPath); ok {
+			t.Errorf("不应通过符号链接 %s 读取根目录之外的文件", docPath)
+		}
+		if err := m.UpdateContent(docPath, "changed"); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("更新 %s 期望返回 ErrInvalidPath，实际为 %v", docPath, err)
+		}
+		if err := m.DeleteContent(docPath); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("删除 %s 期望返回 ErrInvalidPath，实际为 %v", docPath, err)
+		}
+	}
+	if err := m.Create("/link/new.md", "x"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("在符号链接目录中新建期望返回 ErrInvalidPath，实际为 %v", err)
+	}
+	if err := m.Create("/a.md", "x"); err != nil {
+		t.Fatalf("新建文档失败: %v", err)
+	}
+	if err := m.Move("/a.md", "/link/moved.md"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("移动到符号链接目录期望返回 ErrInvalidPath，实际为 %v", err)
+	}
+
+	if content, _ := os.ReadFile(secret); string(content) != "secret" {
+		t.Errorf("根目录之外的文件被修改: %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "new.md")); err == nil {
+		t.Error("不应在根目录之外新建文件")
+	}
+	if paths := m.ListContents(); len(paths) != 1 || paths[0] != "/a.md" {
+		t.Errorf("期望只列出 /a.md，实际为 %v", paths)
+	}
+}
+
+func TestFileManagerCreateMoveRename(t *testing.T) {
+	m, _ := newTestFileManager(t)
+
+	if err := m.Create("/docs/a.md", "A"); err != nil {
+		t.Fatalf("新建文档失败: %v", err)
+	}
+	if err := m.Create("/docs/a.md", "A2"); !errors.Is(err, ErrContentExists) {
+		t.Errorf("重复新建期望返回 ErrContentExists，实际为 %v", err)
+	}
+	if err := m.Create("/b.md", "B"); err != nil {
+		t.Fatalf("新建文档失败: %v", err)
+	}
+	if err := m.Move("/docs/a.md", "/b.md"); !errors.Is(err, ErrContentExists) {
+		t.Errorf("移动到已存在的文档期望返回 ErrContentExists，实际为 %v", err)
+	}
+	if err := m.Move("/missing.md", "/c.md"); !errors.Is(err, ErrContentNotFound) {
+		t.Errorf("移动不存在的文档期望返回 ErrContentNotFound，实际为 %v", err)
+	}
+
+	if err := m.Move("/docs/a.md", "/archive/2024/a.md"); err != nil {
+		t.Fatalf("移动文档失败: %v", err)
+	}
+	if content, ok := m.GetContent("/archive/2024/a.md"); !ok || content != "A" {
+		t.Errorf("移动后期望读取到原内容，实际为 %q, %v", content, ok)
+	}
+
+	renamed, err := m.Rename("/archive/2024/a.md", "renamed.md")
+	if err != nil || renamed != "/archive/2024/renamed.md" {
+		t.Fatalf("重命名期望返回 /archive/2024/renamed.md，实际为 %q, %v", renamed, err)
+	}
+	if _, ok := m.GetContent("/archive/2024/a.md"); ok {
+		t.Error("重命名后原文档不应存在")
+	}
+	if _, err := m.Rename(renamed, "b.md"); err != nil {
+		t.Errorf("重命名到同目录下不存在的文件名失败: %v", err)
+	}
+	for _, name := range []string{"", "../b.md", `sub\b.md`, ".hidden.md", "b.txt"} {
+		if _, err := m.Rename("/b.md", name); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("文件名 %q 期望返回 ErrInvalidPath，实际为 %v", name, err)
+		}
+	}
+}
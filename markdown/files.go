@@ -0,0 +1,166 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultFileTypes 默认允许通过 /files 访问的附件扩展名
+var DefaultFileTypes = []string{
+	"pdf", "zip", "tar", "gz", "7z",
+	"mp4", "webm", "mov", "mp3", "wav", "ogg",
+	"txt", "csv", "json", "xml", "yaml", "yml",
+	"doc", "docx", "xls", "xlsx", "ppt", "pptx",
+}
+
+// 常用附件类型的MIME映射，未列出的类型按扩展名由 mime.TypeByExtension 推断
+var fileMimeTypes = map[string]string{
+	"pdf":  "application/pdf",
+	"zip":  "application/zip",
+	"gz":   "application/gzip",
+	"7z":   "application/x-7z-compressed",
+	"mp4":  "video/mp4",
+	"webm": "video/webm",
+	"mov":  "video/quicktime",
+	"mp3":  "audio/mpeg",
+	"wav":  "audio/wav",
+	"ogg":  "audio/ogg",
+	"txt":  "text/plain; charset=utf-8",
+	"csv":  "text/csv; charset=utf-8",
+	"yaml": "text/yaml; charset=utf-8",
+	"yml":  "text/yaml; charset=utf-8",
+}
+
+// fileLinkPattern 匹配Markdown链接语法（不包括图片）
+var fileLinkPattern = regexp.MustCompile(`(^|[^!])\[([^\]]*)\]\(([^)\s]+)((?:\s+"[^"]*")?)\)`)
+
+// HandleFiles 处理Markdown文档中链接的附件请求，只允许访问 FileTypes 中的文件类型
+// 支持Range请求（如视频拖动播放）；查询参数 download=1 时以附件形式下载
+func (s *MarkdownServer) HandleFiles(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
+	// URL格式: /files/[文件路径]?download=1
+	filePath := strings.TrimPrefix(r.URL.Path, "/files")
+	if filePath == "" || filePath == "/" {
+		http.Error(w, "文件路径不能为空", http.StatusBadRequest)
+		return nil
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), ".")
+	if !s.fileTypes[ext] {
+		http.Error(w, fmt.Sprintf("不允许访问的文件类型: %s", ext), http.StatusForbidden)
+		return nil
+	}
+
+	node, err := proj.FindNode(filePath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("文件不存在: %v", err), http.StatusNotFound)
+		return nil
+	}
+	if node.IsDir() {
+		http.Error(w, "路径不是文件", http.StatusBadRequest)
+		return nil
+	}
+
+	content, err := openNode(node)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("读取文件失败: %v", err), http.StatusInternalServerError)
+		return nil
+	}
+	defer content.Close()
+
+	contentType, ok := fileMimeTypes[ext]
+	if !ok {
+		contentType = mime.TypeByExtension("." + ext)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.URL.Query().Get("download") != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", node.GetName()))
+	}
+
+	var modTime time.Time
+	if info := node.GetFileInfo(); info != nil {
+		modTime = info.ModTime()
+	}
+
+	// ServeContent 处理Range、If-Modified-Since等条件请求
+	http.ServeContent(w, r, node.GetName(), modTime, content)
+	return nil
+}
+
+// NodeOpener 可以按需打开文件内容的节点实现的接口，DirTree 的节点实现了此接口
+// 通过 /files 访问附件时优先使用 Open，Range请求只读取需要的部分，不必把整个文件读入内存
+type NodeOpener interface {
+	// Open 打开节点内容，调用方负责关闭
+	Open() (io.ReadSeekCloser, error)
+}
+
+// openNode 打开节点内容，节点没有实现 NodeOpener 时读取全部内容
+func openNode(node NodeInfo) (io.ReadSeekCloser, error) {
+	if opener, ok := node.(NodeOpener); ok {
+		return opener.Open()
+	}
+	content, err := node.ReadContent()
+	if err != nil {
+		return nil, err
+	}
+	return nopSeekCloser{bytes.NewReader(content)}, nil
+}
+
+// nopSeekCloser 为 io.ReadSeeker 添加空的 Close 方法
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+// Close 不做任何操作
+func (nopSeekCloser) Close() error {
+	return nil
+}
+
+// convertLocalFileLinks 将指向本地附件的链接转换为以prefix开头的服务器路径
+// 只转换扩展名在fileTypes中的相对路径或绝对路径，外部链接和锚点保持不变
+func (r *MarkdownRenderer) convertLocalFileLinks(content, currentDir, prefix string, fileTypes []string) string {
+	if prefix == "" || len(fileTypes) == 0 {
+		return content
+	}
+
+	allowed := make(map[string]bool, len(fileTypes))
+	for _, ext := range fileTypes {
+		allowed[strings.ToLower(strings.TrimPrefix(ext, "."))] = true
+	}
+
+	return fileLinkPattern.ReplaceAllStringFunc(content, func(match string) string {
+		parts := fileLinkPattern.FindStringSubmatch(match)
+		before, text, target, title := parts[1], parts[2], parts[3], parts[4]
+
+		lower := strings.ToLower(target)
+		if strings.Contains(lower, "://") || strings.HasPrefix(lower, "mailto:") || strings.HasPrefix(target, "#") {
+			return match
+		}
+
+		// 去掉查询参数和锚点后判断扩展名，转换后保留原来的查询参数和锚点（如视频的 #t=10）
+		filePath := strings.SplitN(strings.SplitN(target, "#", 2)[0], "?", 2)[0]
+		suffix := target[len(filePath):]
+		ext := strings.TrimPrefix(strings.ToLower(path.Ext(filePath)), ".")
+		if !allowed[ext] {
+			return match
+		}
+
+		if !strings.HasPrefix(filePath, "/") {
+			filePath = path.Join(filepath.ToSlash(currentDir), filePath)
+		}
+		filePath = path.Clean("/" + filePath)
+
+		return before + "[" + text + "](" + prefix + filePath + suffix + title + ")"
+	})
+}
@@ -0,0 +1,28 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// htmlConverter 服务端Markdown转HTML转换器，启用GFM扩展以与页面中的marked.js保持一致
+var htmlConverter = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(
+		// 保留内容中的原始HTML（包括注入的标题锚点）
+		html.WithUnsafe(),
+	),
+)
+
+// RenderHTML 将Markdown内容转换为HTML片段
+func RenderHTML(content string) (string, error) {
+	var buf bytes.Buffer
+	if err := htmlConverter.Convert([]byte(content), &buf); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrRenderFailed, err)
+	}
+	return buf.String(), nil
+}
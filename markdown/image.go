@@ -0,0 +1,115 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // 注册GIF解码器
+	"image/jpeg"
+	"image/png"
+)
+
+// maxImageDimension 缩放参数允许的最大宽高，避免生成过大的图片
+const maxImageDimension = 4096
+
+// resizableImageTypes 支持服务端缩放的图片类型
+var resizableImageTypes = map[string]bool{
+	"jpg":  true,
+	"jpeg": true,
+	"png":  true,
+	"gif":  true,
+}
+
+// resizeImage 将图片等比缩小到 maxWidth x maxHeight 范围内，为0的边不做限制
+// 图片已经小于目标尺寸时返回原始内容，返回结果的MIME类型
+func resizeImage(content []byte, maxWidth, maxHeight int) ([]byte, string, error) {
+	src, format, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", fmt.Errorf("解码图片失败: %v", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := fitImageSize(bounds.Dx(), bounds.Dy(), maxWidth, maxHeight)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return content, mimeTypes[format], nil
+	}
+
+	dst := scaleImage(src, width, height)
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	default:
+		// GIF缩放后只保留第一帧，统一输出为PNG
+		format = "png"
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("编码图片失败: %v", err)
+	}
+
+	return buf.Bytes(), mimeTypes[format], nil
+}
+
+// fitImageSize 计算等比缩小后的尺寸，不会放大图片
+func fitImageSize(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && height > maxHeight {
+		if s := float64(maxHeight) / float64(height); s < scale {
+			scale = s
+		}
+	}
+	if scale >= 1 {
+		return width, height
+	}
+
+	newWidth := max(int(float64(width)*scale+0.5), 1)
+	newHeight := max(int(float64(height)*scale+0.5), 1)
+	return newWidth, newHeight
+}
+
+// scaleImage 使用区域平均算法缩小图片
+func scaleImage(src image.Image, width, height int) *image.RGBA {
+	// 先转换为RGBA以便直接访问像素
+	bounds := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max((y+1)*srcHeight/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max((x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(rgba.Pix[offset])
+					g += uint64(rgba.Pix[offset+1])
+					b += uint64(rgba.Pix[offset+2])
+					a += uint64(rgba.Pix[offset+3])
+					offset += 4
+					count++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / count)
+			dst.Pix[offset+1] = uint8(g / count)
+			dst.Pix[offset+2] = uint8(b / count)
+			dst.Pix[offset+3] = uint8(a / count)
+		}
+	}
+
+	return dst
+}
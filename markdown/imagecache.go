@@ -0,0 +1,150 @@
+package markdown
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// DefaultImageCacheSize 缩略图磁盘缓存的默认容量（100MB）
+const DefaultImageCacheSize int64 = 100 << 20
+
+// imageCacheKeyPattern 由 ImageCacheKey 生成的缓存键的格式，缓存只管理文件名符合此格式的文件
+var imageCacheKeyPattern = regexp.MustCompile(`^[0-9a-f]{32}_[0-9]+x[0-9]+$`)
+
+// ImageCache 基于磁盘的LRU缩略图缓存
+// 缓存总大小超过上限时，优先淘汰最久未访问的文件；目录中文件名不是缓存键的文件不会被载入、统计或删除
+type ImageCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List               // 访问顺序，队首为最近访问
+	entries map[string]*list.Element // 缓存键到链表节点的映射
+}
+
+// imageCacheEntry 缓存条目
+type imageCacheEntry struct {
+	key  string
+	size int64
+}
+
+// NewImageCache 创建缩略图缓存，目录中已有的缓存文件会按修改时间载入，其他文件保持不变
+func NewImageCache(dir string, maxBytes int64) (*ImageCache, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultImageCacheSize
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建图片缓存目录失败: %v", err)
+	}
+
+	c := &ImageCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+
+	// 载入已有的缓存文件，最近修改的排在前面
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取图片缓存目录失败: %v", err)
+	}
+	var infos []os.FileInfo
+	for _, entry := range dirEntries {
+		if !entry.Type().IsRegular() || !imageCacheKeyPattern.MatchString(entry.Name()) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().After(infos[j].ModTime())
+	})
+	for _, info := range infos {
+		c.entries[info.Name()] = c.order.PushBack(&imageCacheEntry{key: info.Name(), size: info.Size()})
+		c.size += info.Size()
+	}
+	c.evict()
+
+	return c, nil
+}
+
+// ImageCacheKey 根据图片内容和缩放参数生成缓存键
+func ImageCacheKey(content []byte, width, height int) string {
+	hash := sha256.Sum256(content)
+	return fmt.Sprintf("%s_%dx%d", hex.EncodeToString(hash[:16]), width, height)
+}
+
+// Get 读取缓存内容
+func (c *ImageCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		// 文件已被外部删除，移除缓存记录
+		c.remove(elem)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return data, true
+}
+
+// Put 写入缓存内容，必要时淘汰旧条目；key 必须是 ImageCacheKey 生成的缓存键
+func (c *ImageCache) Put(key string, data []byte) error {
+	if !imageCacheKeyPattern.MatchString(key) {
+		return fmt.Errorf("无效的图片缓存键: %q", key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.WriteFile(filepath.Join(c.dir, key), data, 0644); err != nil {
+		return fmt.Errorf("写入图片缓存失败: %v", err)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*imageCacheEntry)
+		c.size += int64(len(data)) - entry.size
+		entry.size = int64(len(data))
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[key] = c.order.PushFront(&imageCacheEntry{key: key, size: int64(len(data))})
+		c.size += int64(len(data))
+	}
+
+	c.evict()
+	return nil
+}
+
+// evict 淘汰最久未访问的条目，直到总大小不超过上限
+func (c *ImageCache) evict() {
+	for c.size > c.maxBytes && c.order.Len() > 0 {
+		elem := c.order.Back()
+		os.Remove(filepath.Join(c.dir, elem.Value.(*imageCacheEntry).key))
+		c.remove(elem)
+	}
+}
+
+// remove 移除缓存记录
+func (c *ImageCache) remove(elem *list.Element) {
+	entry := elem.Value.(*imageCacheEntry)
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
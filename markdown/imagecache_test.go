@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	foreign := map[string]string{"notes.txt": "keep", "photo.png": "keep", "0123_10x10": "keep"}
+	for name, content := range foreign {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+	}
+
+	cache, err := NewImageCache(dir, 25)
+	if err != nil {
+		t.Fatalf("创建图片缓存失败: %v", err)
+	}
+	keys := []string{
+		ImageCacheKey([]byte("a"), 100, 100),
+		ImageCacheKey([]byte("b"), 100, 100),
+		ImageCacheKey([]byte("c"), 100, 100),
+	}
+	for _, key := range keys[:2] {
+		if err := cache.Put(key, bytes.Repeat([]byte("x"), 10)); err != nil {
+			t.Fatalf("写入缓存失败: %v", err)
+		}
+	}
+	// 访问第一个条目后写入第三个，超出容量时淘汰最久未访问的第二个
+	if _, ok := cache.Get(keys[0]); !ok {
+		t.Fatal("期望命中缓存")
+	}
+	if err := cache.Put(keys[2], bytes.Repeat([]byte("x"), 10)); err != nil {
+		t.Fatalf("写入缓存失败: %v", err)
+	}
+	if _, ok := cache.Get(keys[1]); ok {
+		t.Error("期望淘汰最久未访问的条目")
+	}
+	for _, key := range []string{keys[0], keys[2]} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("期望保留条目 %s", key)
+		}
+	}
+
+	if err := cache.Put("../escape", []byte("x")); err == nil {
+		t.Error("期望拒绝无效的缓存键")
+	}
+
+	// 重新载入时只管理缓存文件，容量再小也不会删除其他文件
+	if _, err := NewImageCache(dir, 1); err != nil {
+		t.Fatalf("重新创建图片缓存失败: %v", err)
+	}
+	for name, content := range foreign {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != content {
+			t.Errorf("缓存目录中的其他文件 %s 被修改或删除: %v", name, err)
+		}
+	}
+	for _, key := range keys {
+		if _, err := os.Stat(filepath.Join(dir, key)); err == nil {
+			t.Errorf("期望淘汰超出容量的缓存文件 %s", key)
+		}
+	}
+}
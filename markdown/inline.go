@@ -0,0 +1,65 @@
+package markdown
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// inlineLocalImages 将本地图片引用替换为从项目树读取的data URL，无法读取的图片转换为以prefix开头的服务器路径
+func (r *MarkdownRenderer) inlineLocalImages(content, currentDir string, proj ProjectTree, prefix string) string {
+	if prefix == "" {
+		prefix = "/images"
+	}
+
+	// 同一文档中多次引用的图片只读取一次
+	dataURLs := make(map[string]string)
+	return r.rewriteLocalImages(content, currentDir, func(resolvedPath string) string {
+		if src, ok := dataURLs[resolvedPath]; ok {
+			return src
+		}
+		src, err := ImageDataURL(proj, resolvedPath)
+		if err != nil {
+			src = prefix + resolvedPath
+		}
+		dataURLs[resolvedPath] = src
+		return src
+	})
+}
+
+// ImageDataURL 读取项目树中的图片，返回以base64编码的data URL，只支持 mimeTypes 中的图片类型
+func ImageDataURL(proj ProjectTree, imagePath string) (string, error) {
+	content, contentType, err := readImage(proj, imagePath)
+	if err != nil {
+		return "", err
+	}
+	return dataURL(contentType, content), nil
+}
+
+// dataURL 返回以base64编码的data URL
+func dataURL(contentType string, content []byte) string {
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(content)
+}
+
+// readImage 读取项目树中的图片，返回图片内容和MIME类型，只支持 mimeTypes 中的图片类型
+func readImage(proj ProjectTree, imagePath string) ([]byte, string, error) {
+	imagePath = path.Clean("/" + imagePath)
+	contentType, ok := mimeTypes[strings.TrimPrefix(strings.ToLower(path.Ext(imagePath)), ".")]
+	if !ok {
+		return nil, "", fmt.Errorf("不支持的图片类型: %s", imagePath)
+	}
+
+	node, err := proj.FindNode(imagePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("图片不存在: %v", err)
+	}
+	if node.IsDir() {
+		return nil, "", fmt.Errorf("路径不是图片文件: %s", imagePath)
+	}
+	content, err := node.ReadContent()
+	if err != nil {
+		return nil, "", fmt.Errorf("读取图片失败: %v", err)
+	}
+	return content, contentType, nil
+}
@@ -0,0 +1,223 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 公式占位符由Unicode私用区字符组成，不会被其他转换步骤当作Markdown语法处理，生成标题锚点时也会被忽略
+const (
+	mathPlaceholderStart = '\uE000'
+	mathPlaceholderEnd   = '\uE001'
+	mathPlaceholderDigit = 0xE010
+)
+
+// mathFormula 文档中的一个公式
+type mathFormula struct {
+	tex     string
+	display bool
+}
+
+// ContainsMath 判断Markdown内容中是否包含 $...$ 或 $$...$$ 公式，代码块和行内代码中的内容不计算在内
+func ContainsMath(content string) bool {
+	_, formulas := protectMath(content)
+	return len(formulas) > 0
+}
+
+// protectMath 将公式替换为占位符，返回替换后的内容和按顺序排列的公式
+func protectMath(content string) (string, []mathFormula) {
+	if !strings.Contains(content, "$") {
+		return content, nil
+	}
+
+	var formulas []mathFormula
+	lines := strings.Split(content, "\n")
+	result := make([]string, 0, len(lines))
+	var chunk []string
+	flush := func() {
+		if len(chunk) > 0 {
+			result = append(result, protectMathChunk(strings.Join(chunk, "\n"), &formulas))
+			chunk = chunk[:0]
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		if _, end, ok := parseFencedCodeBlock(lines, i); ok {
+			flush()
+			result = append(result, lines[i:end+1]...)
+			i = end
+			continue
+		}
+		chunk = append(chunk, lines[i])
+	}
+	flush()
+
+	return strings.Join(result, "\n"), formulas
+}
+
+// protectMathChunk 替换一段不含围栏代码块的内容中的公式，行内代码和转义的 \$ 保持原样
+// $$...$$ 可以跨行；$...$ 必须在同一行内，起始$后和结束$前不能是空白，结束$后不能是数字（避免把金额当作公式）
+func protectMathChunk(chunk string, formulas *[]mathFormula) string {
+	var sb strings.Builder
+	for i := 0; i < len(chunk); {
+		switch c := chunk[i]; {
+		case c == '\\' && i+1 < len(chunk):
+			sb.WriteString(chunk[i : i+2])
+			i += 2
+
+		case c == '`':
+			// 跳过行内代码
+			n := 1
+			for i+n < len(chunk) && chunk[i+n] == '`' {
+				n++
+			}
+			fence := chunk[i : i+n]
+			if end := strings.Index(chunk[i+n:], fence); end >= 0 {
+				end += i + n + n
+				sb.WriteString(chunk[i:end])
+				i = end
+			} else {
+				sb.WriteString(fence)
+				i += n
+			}
+
+		case strings.HasPrefix(chunk[i:], "$$"):
+			end := findMathClose(chunk, i+2, "$$")
+			tex := ""
+			if end >= 0 {
+				tex = strings.TrimSpace(chunk[i+2 : end])
+			}
+			if tex == "" {
+				sb.WriteString("$$")
+				i += 2
+				continue
+			}
+			sb.WriteString(addFormula(formulas, mathFormula{tex: tex, display: true}))
+			i = end + 2
+
+		case c == '$':
+			end := findInlineMathClose(chunk, i+1)
+			if end < 0 {
+				sb.WriteByte(c)
+				i++
+				continue
+			}
+			sb.WriteString(addFormula(formulas, mathFormula{tex: chunk[i+1 : end]}))
+			i = end + 1
+
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// findMathClose 从start开始查找未转义的结束定界符，找不到时返回-1
+func findMathClose(chunk string, start int, delimiter string) int {
+	for i := start; i < len(chunk); i++ {
+		if chunk[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(chunk[i:], delimiter) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findInlineMathClose 查找行内公式的结束$，找不到、不满足规则或中间出现行内代码时返回-1
+func findInlineMathClose(chunk string, start int) int {
+	if start >= len(chunk) || isMathSpace(chunk[start]) || chunk[start] == '$' {
+		return -1
+	}
+	for i := start; i < len(chunk) && chunk[i] != '\n'; i++ {
+		switch chunk[i] {
+		case '`':
+			return -1
+		case '\\':
+			i++
+		case '$':
+			if isMathSpace(chunk[i-1]) {
+				continue
+			}
+			if i+1 < len(chunk) && chunk[i+1] >= '0' && chunk[i+1] <= '9' {
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// isMathSpace 判断是否为空白字符
+func isMathSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// addFormula 记录公式并返回对应的占位符
+func addFormula(formulas *[]mathFormula, formula mathFormula) string {
+	*formulas = append(*formulas, formula)
+
+	var sb strings.Builder
+	sb.WriteRune(mathPlaceholderStart)
+	for _, d := range strconv.Itoa(len(*formulas) - 1) {
+		sb.WriteRune(mathPlaceholderDigit + d - '0')
+	}
+	sb.WriteRune(mathPlaceholderEnd)
+	return sb.String()
+}
+
+// restoreMath 将占位符替换为公式的HTML
+func restoreMath(content string, formulas []mathFormula) string {
+	if len(formulas) == 0 {
+		return content
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.IndexRune(content, mathPlaceholderStart)
+		if start < 0 {
+			break
+		}
+		end := strings.IndexRune(content[start:], mathPlaceholderEnd)
+		if end < 0 {
+			break
+		}
+		end += start
+
+		index := 0
+		for _, d := range content[start+len(string(mathPlaceholderStart)) : end] {
+			index = index*10 + int(d-mathPlaceholderDigit)
+		}
+		sb.WriteString(content[:start])
+		if index >= 0 && index < len(formulas) {
+			sb.WriteString(formulas[index].html())
+		}
+		content = content[end+len(string(mathPlaceholderEnd)):]
+	}
+	sb.WriteString(content)
+	return sb.String()
+}
+
+// html 返回公式的HTML，使用 \(...\) 和 \[...\] 作为定界符交给页面中的MathJax渲染
+// 公式中的标点和换行写成字符实体，避免被Markdown解析为强调、转义或段落分隔
+func (f mathFormula) html() string {
+	class, tex := "math math-inline", `\(`+f.tex+`\)`
+	if f.display {
+		class, tex = "math math-display", `\[`+f.tex+`\]`
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<span class="` + class + `">`)
+	for _, r := range tex {
+		if r == '\n' || (r < 0x80 && strings.ContainsRune("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", r)) {
+			sb.WriteString("&#" + strconv.Itoa(int(r)) + ";")
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteString(`</span>`)
+	return sb.String()
+}
@@ -0,0 +1,174 @@
+package markdown
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsPath 开启指标时暴露Prometheus指标的路径
+const MetricsPath = "/metrics"
+
+// serverMetrics Markdown服务器的Prometheus指标
+type serverMetrics struct {
+	registry *prometheus.Registry
+
+	requests      *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+	pageViews     *prometheus.CounterVec
+	renderSeconds prometheus.Histogram
+	imageCache    *prometheus.CounterVec
+}
+
+// newServerMetrics 创建指标并注册到registry，registry为nil时创建新的Registry
+func newServerMetrics(registry *prometheus.Registry) (*serverMetrics, error) {
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+	}
+
+	m := &serverMetrics{
+		registry: registry,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "markdown_http_requests_total",
+			Help: "HTTP请求数，按路由和状态码统计",
+		}, []string{"route", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "markdown_http_request_duration_seconds",
+			Help:    "HTTP请求的处理耗时",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route"}),
+		pageViews: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "markdown_page_views_total",
+			Help: "文档页面的浏览次数",
+		}, []string{"path"}),
+		renderSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "markdown_render_duration_seconds",
+			Help:    "Markdown文档的渲染耗时",
+			Buckets: prometheus.DefBuckets,
+		}),
+		imageCache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "markdown_image_cache_requests_total",
+			Help: "缩略图缓存的查询次数，result为hit或miss",
+		}, []string{"result"}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.requests, m.latency, m.pageViews, m.renderSeconds, m.imageCache} {
+		if err := registry.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// handler 返回暴露指标的HTTP处理器
+func (m *serverMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// observeRequest 记录一次HTTP请求
+func (m *serverMetrics) observeRequest(route string, status int, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(route, strconv.Itoa(status)).Inc()
+	m.latency.WithLabelValues(route).Observe(elapsed.Seconds())
+}
+
+// observePageView 记录一次文档浏览
+func (m *serverMetrics) observePageView(path string) {
+	if m == nil {
+		return
+	}
+	m.pageViews.WithLabelValues(path).Inc()
+}
+
+// observeRender 记录一次文档渲染的耗时
+func (m *serverMetrics) observeRender(elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.renderSeconds.Observe(elapsed.Seconds())
+}
+
+// observeImageCache 记录一次缩略图缓存查询
+func (m *serverMetrics) observeImageCache(hit bool) {
+	if m == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.imageCache.WithLabelValues(result).Inc()
+}
+
+// statusRecorder 记录响应状态码的ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader 记录状态码
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write 未调用WriteHeader时状态码为200
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// Unwrap 返回原始的ResponseWriter，供 http.ResponseController 使用
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// instrument 为处理器添加访问日志和请求指标，都未开启时原样返回
+func (s *MarkdownServer) instrument(next http.Handler) http.Handler {
+	if !s.accessLog && s.metrics == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		elapsed := time.Since(start)
+		s.metrics.observeRequest(routeLabel(r.URL.Path), status, elapsed)
+		if s.accessLog {
+			s.logger.Info("HTTP请求", "method", r.Method, "path", s.basePath+r.URL.Path, "status", status, "latency", elapsed)
+		}
+	})
+}
+
+// routes 指标中使用的路由，其他路径统计为 other，避免标签数量随请求路径无限增长
+var routes = []string{"/list", "/view", "/raw", "/raw-content", "/api/markdown", "/api/html", "/api/outline", "/diff",
+	"/images", "/files", "/sitemap.xml", "/feed.xml", MetricsPath}
+
+// routeLabel 返回请求路径对应的路由标签
+func routeLabel(path string) string {
+	if path == "/" {
+		return "/"
+	}
+	for _, route := range routes {
+		if path == route || strings.HasPrefix(path, route+"/") {
+			return route
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,90 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OutlineNode 文档大纲中的一个标题，子标题按层级嵌套
+type OutlineNode struct {
+	Level    int            `json:"level"`
+	Text     string         `json:"text"`
+	Anchor   string         `json:"anchor"`
+	Line     int            `json:"line"`
+	URL      string         `json:"url,omitempty"`
+	Children []*OutlineNode `json:"children,omitempty"`
+}
+
+// Outline 文档大纲
+type Outline struct {
+	Path     string         `json:"path"`
+	Title    string         `json:"title,omitempty"`
+	URL      string         `json:"url,omitempty"`
+	Headings []*OutlineNode `json:"headings"`
+}
+
+// BuildOutline 将Markdown内容中的标题组织为树形大纲，锚点与页面中生成的锚点一致
+// 级别跳跃的标题（如 # 之后直接是 ###）作为最近的上级标题的子节点
+func BuildOutline(content string) []*OutlineNode {
+	slugger := NewSlugger()
+	roots := []*OutlineNode{}
+	var stack []*OutlineNode
+	for _, heading := range ExtractHeadings(content) {
+		node := &OutlineNode{
+			Level:  heading.Level,
+			Text:   heading.Text,
+			Anchor: slugger.Slug(heading.Text),
+			Line:   heading.Line,
+		}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= node.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, node)
+	}
+	return roots
+}
+
+// HandleOutline 以JSON返回文档的标题树，包含锚点、行号和跳转到标题的绝对链接，
+// 供编辑器集成和通知中的"打开到指定标题"链接使用
+// URL格式: /api/outline/[文件路径]
+func (s *MarkdownServer) HandleOutline(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
+	filePath := strings.TrimPrefix(r.URL.Path, "/api/outline")
+	if filePath == "" || filePath == "/" {
+		return fmt.Errorf("文件路径不能为空")
+	}
+
+	content, _, err := s.readDocument(proj, filePath)
+	if err != nil {
+		return err
+	}
+
+	title, _ := s.renderer.ExtractTitleAndDescription(string(content))
+	outline := Outline{
+		Path:     filePath,
+		Title:    title,
+		URL:      documentURL(s.siteURL(r), filePath),
+		Headings: BuildOutline(string(content)),
+	}
+	setOutlineURLs(outline.Headings, outline.URL)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(outline)
+}
+
+// setOutlineURLs 为大纲中的每个标题设置跳转链接
+func setOutlineURLs(nodes []*OutlineNode, documentURL string) {
+	for _, node := range nodes {
+		node.URL = documentURL + "#" + node.Anchor
+		setOutlineURLs(node.Children, documentURL)
+	}
+}
@@ -28,8 +28,31 @@ type ProcessOptions struct {
 	SanitizeMermaid bool
 	// ConvertImages 是否转换图片路径
 	ConvertImages bool
+	// HeadingAnchors 是否为标题生成与GitHub兼容的锚点
+	HeadingAnchors bool
+	// ImageURLPrefix 本地图片转换后的URL前缀，默认为 /images
+	ImageURLPrefix string
 	// ImagePathConverter 自定义图片路径转换器
 	ImagePathConverter func(content, currentDir string) string
+	// FileURLPrefix 本地附件链接转换后的URL前缀（如 /files），为空时不转换附件链接
+	FileURLPrefix string
+	// FileTypes 需要转换链接的附件扩展名（不含点）
+	FileTypes []string
+	// EnableMath 是否识别 $...$ 和 $$...$$ 公式，公式不会被其他转换步骤修改，并输出为MathJax可以渲染的HTML
+	EnableMath bool
+	// Admonitions 是否将 ::: note 和 > [!NOTE] 语法渲染为提示框
+	Admonitions bool
+	// CodeLanguageLabels 是否在代码块上方显示语言标签
+	CodeLanguageLabels bool
+	// CodeLineNumbers 是否为代码块显示行号
+	CodeLineNumbers bool
+	// CodeCopyButton 是否为代码块添加复制按钮
+	CodeCopyButton bool
+	// InlineImages 是否从 ImageTree 读取本地图片并以data URL嵌入，用于导出文件和邮件等无法访问 /images 路径的场景；
+	// 开启时优先于 ImageURLPrefix 和 ImagePathConverter，无法读取的图片仍按 ImageURLPrefix 转换
+	InlineImages bool
+	// ImageTree 嵌入图片时读取图片的项目树
+	ImageTree ProjectTree
 }
 
 // DefaultProcessOptions 返回默认的处理选项
@@ -37,6 +60,14 @@ func DefaultProcessOptions() ProcessOptions {
 	return ProcessOptions{
 		SanitizeMermaid: true,
 		ConvertImages:   true,
+		HeadingAnchors:  true,
+		ImageURLPrefix:  "/images",
+		EnableMath:      true,
+		Admonitions:     true,
+
+		CodeLanguageLabels: true,
+		CodeLineNumbers:    true,
+		CodeCopyButton:     true,
 	}
 }
 
@@ -213,6 +244,18 @@ func (r *MarkdownRenderer) sanitizeMermaidClassName(line string) string {
 
 // ConvertLocalImagesToServerPath 将本地图片引用转换为服务器路径
 func (r *MarkdownRenderer) ConvertLocalImagesToServerPath(content, currentDir string) string {
+	return r.convertLocalImages(content, currentDir, "/images")
+}
+
+// convertLocalImages 将本地图片引用转换为以prefix开头的服务器路径
+func (r *MarkdownRenderer) convertLocalImages(content, currentDir, prefix string) string {
+	return r.rewriteLocalImages(content, currentDir, func(resolvedPath string) string {
+		return prefix + resolvedPath
+	})
+}
+
+// rewriteLocalImages 将本地图片引用替换为rewrite返回的地址，rewrite的参数为图片相对项目根目录的路径，以/开头
+func (r *MarkdownRenderer) rewriteLocalImages(content, currentDir string, rewrite func(resolvedPath string) string) string {
 	// 使用简单的字符串处理，避免复杂正则表达式
 	var result strings.Builder
 
@@ -277,7 +320,7 @@ func (r *MarkdownRenderer) ConvertLocalImagesToServerPath(content, currentDir st
 				}
 
 				// 转换为/images/路径
-				result.WriteString("![" + altText + "](/images" + resolvedPath + ")")
+				result.WriteString("![" + altText + "](" + rewrite(resolvedPath) + ")")
 			}
 		} else {
 			// 不是图片语法，直接写入
@@ -373,6 +416,12 @@ func (r *MarkdownRenderer) ProcessContent(content, currentDir string) template.H
 func (r *MarkdownRenderer) ProcessContentWithOptions(content, currentDir string, options ProcessOptions) template.HTML {
 	processedContent := content
 
+	// 先将公式替换为占位符，避免被后续步骤当作Markdown语法修改
+	var formulas []mathFormula
+	if options.EnableMath {
+		processedContent, formulas = protectMath(processedContent)
+	}
+
 	// 根据选项处理内容
 	if options.SanitizeMermaid {
 		// 修复Mermaid图表中的语法问题
@@ -380,14 +429,41 @@ func (r *MarkdownRenderer) ProcessContentWithOptions(content, currentDir string,
 	}
 
 	if options.ConvertImages {
-		if options.ImagePathConverter != nil {
+		if options.InlineImages && options.ImageTree != nil {
+			// 将本地图片嵌入为data URL
+			processedContent = r.inlineLocalImages(processedContent, currentDir, options.ImageTree, options.ImageURLPrefix)
+		} else if options.ImagePathConverter != nil {
 			// 使用自定义图片路径转换器
 			processedContent = options.ImagePathConverter(processedContent, currentDir)
 		} else {
 			// 使用默认的图片路径转换
-			processedContent = r.ConvertLocalImagesToServerPath(processedContent, currentDir)
+			prefix := options.ImageURLPrefix
+			if prefix == "" {
+				prefix = "/images"
+			}
+			processedContent = r.convertLocalImages(processedContent, currentDir, prefix)
 		}
 	}
 
+	if options.FileURLPrefix != "" {
+		// 将指向本地附件的链接转换为服务器路径
+		processedContent = r.convertLocalFileLinks(processedContent, currentDir, options.FileURLPrefix, options.FileTypes)
+	}
+
+	if options.Admonitions {
+		// 提示框内的标题和代码块交给后续步骤处理
+		processedContent = r.RenderAdmonitions(processedContent)
+	}
+
+	if options.HeadingAnchors {
+		// 为标题注入锚点，保证为GitHub编写的文内链接可用
+		processedContent = r.AddHeadingAnchors(processedContent)
+	}
+
+	// 为代码块添加语言标签、行号和复制按钮
+	processedContent = r.DecorateCodeBlocks(processedContent, options)
+
+	processedContent = restoreMath(processedContent, formulas)
+
 	return template.HTML(processedContent)
 }
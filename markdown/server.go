@@ -1,17 +1,26 @@
 package markdown
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"html/template"
 	"net"
 	"net/http"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sjzsdu/utils/logging"
 )
 
 // NodeInfo 定义了节点的基本信息接口
@@ -49,6 +58,25 @@ type MarkdownFile struct {
 	Description  string // 从 MD 文件中提取的描述（第一段文字）
 }
 
+// viewData 文档查看页面的模板数据
+type viewData struct {
+	BasePath      string
+	FilePath      string
+	Content       template.HTML
+	RawPath       string
+	MarkdownFiles []MarkdownFile
+	// DocPath 文档在导出接口中使用的路径
+	DocPath string
+	// ReaderMode 是否为阅读模式（隐藏导航元素）
+	ReaderMode bool
+	// PrintMode 是否在渲染完成后自动打开打印对话框
+	PrintMode bool
+	// Math 文档中是否包含公式，包含时页面加载MathJax
+	Math bool
+	// Languages 语言切换菜单，文档没有翻译时为空
+	Languages []LanguageLink
+}
+
 // ServerOptions 定义Markdown服务器选项
 type ServerOptions struct {
 	// TemplatesDir 模板文件目录
@@ -57,6 +85,42 @@ type ServerOptions struct {
 	ShowContentOnly bool
 	// CustomTemplates 自定义模板
 	CustomTemplates *template.Template
+	// BasePath 服务挂载的路径前缀（如 /docs），用于嵌入已有的路由中
+	BasePath string
+	// ImageCacheDir 缩略图缓存目录，默认为用户缓存目录（无法获取时为系统临时目录）下的 markdown-image-cache；
+	// 缓存只会删除自己生成的缩略图文件，目录中的其他文件保持不变
+	ImageCacheDir string
+	// ImageCacheSize 缩略图缓存容量上限（字节），默认为 DefaultImageCacheSize
+	ImageCacheSize int64
+	// FileTypes 允许通过 /files 访问的附件扩展名（不含点），为nil时使用 DefaultFileTypes
+	FileTypes []string
+	// SiteURL 站点的公开地址（如 https://docs.example.com），用于生成 sitemap.xml 和 feed.xml 中的绝对链接，
+	// 按原样使用，服务挂载在路径前缀下时需要包含该前缀（如 https://example.com/docs）；为空时根据请求的Host推断
+	SiteURL string
+	// TrustProxy 未设置 SiteURL 时是否使用 X-Forwarded-Proto 和 X-Forwarded-Host 推断站点地址，
+	// 只应在服务部署于会覆盖这些请求头的反向代理之后时开启，否则客户端可以伪造生成的链接
+	TrustProxy bool
+	// SiteTitle RSS订阅的标题，默认为 DefaultSiteTitle
+	SiteTitle string
+	// GitRoot 项目树根目录对应的Git仓库路径，设置后 /diff 接口支持对比文档的历史版本
+	GitRoot string
+	// DefaultLanguage 没有语言后缀的默认文档（如 README.md）使用的语言代码，用于匹配 Accept-Language；
+	// 同目录下的 README.en.md 等文件作为该文档的翻译显示在语言切换菜单中
+	DefaultLanguage string
+	// Logger 日志记录器，默认不输出日志
+	Logger logging.Logger
+	// AccessLog 是否通过Logger记录每个请求的方法、路径、状态码和耗时
+	AccessLog bool
+	// Metrics 是否开启Prometheus指标（请求数、耗时、页面浏览、渲染耗时和缩略图缓存命中），
+	// 开启后通过 /metrics 暴露
+	Metrics bool
+	// MetricsRegistry 指标注册到的Registry，设置后同时开启指标；为nil时使用服务器自己的Registry
+	MetricsRegistry *prometheus.Registry
+	// Book 通过 /book 接口导出书籍时使用的选项，默认按路径顺序包含所有非草稿文档
+	Book BookOptions
+	// AdminToken 文档管理接口的访问令牌，设置后开启 /api/docs 接口，请求需携带 Authorization: Bearer [令牌]；
+	// 服务器的Manager需要实现 DocumentManager，并与项目树使用同一目录
+	AdminToken string
 }
 
 // DefaultServerOptions 返回默认的服务器选项
@@ -75,6 +139,45 @@ type MarkdownServer struct {
 	markdownContent string
 	showContentOnly bool
 	projectTree     ProjectTree // 项目树接口
+	gitRoot         string      // Git仓库根目录
+	defaultLanguage string      // 默认文档的语言代码
+	basePath        string      // 服务挂载的路径前缀
+
+	imageCacheDir  string
+	imageCacheSize int64
+	imageCache     *ImageCache // 缩略图缓存，首次缩放图片时创建
+	imageCacheOnce sync.Once
+
+	fileTypes    map[string]bool // 允许通过 /files 访问的附件扩展名
+	fileTypeList []string
+
+	siteBaseURL string // 站点的公开地址，包含挂载前缀，不以/结尾
+	siteTitle   string
+	book        BookOptions // 导出书籍的选项
+
+	logger    logging.Logger
+	accessLog bool
+	metrics   *serverMetrics // 未开启指标时为nil
+
+	adminToken string // 文档管理接口的访问令牌，为空时不开启
+
+	pdfSlots chan struct{} // 限制同时进行的PDF转换数量，未开启PDF导出时为nil
+
+	trustProxy bool // 是否信任反向代理设置的 X-Forwarded-* 请求头
+}
+
+// diffData 文档对比页面的模板数据
+type diffData struct {
+	BasePath   string
+	NameA      string
+	NameB      string
+	Mode       string
+	Lines      []DiffLine
+	Rows       []DiffRow
+	Added      int
+	Removed    int
+	SplitURL   string
+	UnifiedURL string
 }
 
 // 常用图片类型的MIME映射
@@ -176,14 +279,67 @@ func NewMarkdownServer(manager Manager, renderer Renderer, options ...ServerOpti
 			return nil, fmt.Errorf("解析view模板失败: %v", err)
 		}
 
+		// 最后解析diff模板
+		tmplContentDiff, err := templateFS.ReadFile(fmt.Sprintf("%s/diff.html", opt.TemplatesDir))
+		if err != nil {
+			return nil, fmt.Errorf("读取diff.html模板失败: %v", err)
+		}
+
+		if _, err := viewTmpl.New("diff").Parse(string(tmplContentDiff)); err != nil {
+			return nil, fmt.Errorf("解析diff模板失败: %v", err)
+		}
+
 		templates = viewTmpl
 	}
 
+	fileTypes := opt.FileTypes
+	if fileTypes == nil {
+		fileTypes = DefaultFileTypes
+	}
+	allowedFileTypes := make(map[string]bool, len(fileTypes))
+	for _, ext := range fileTypes {
+		allowedFileTypes[strings.ToLower(strings.TrimPrefix(ext, "."))] = true
+	}
+
+	siteTitle := opt.SiteTitle
+	if siteTitle == "" {
+		siteTitle = DefaultSiteTitle
+	}
+
+	var metrics *serverMetrics
+	if opt.Metrics || opt.MetricsRegistry != nil {
+		var err error
+		if metrics, err = newServerMetrics(opt.MetricsRegistry); err != nil {
+			return nil, fmt.Errorf("注册Prometheus指标失败: %v", err)
+		}
+	}
+
+	var pdfSlots chan struct{}
+	if opt.Book.ServePDF {
+		pdfSlots = make(chan struct{}, max(opt.Book.MaxPDFConversions, 1))
+	}
+
 	return &MarkdownServer{
 		manager:         manager,
 		renderer:        renderer,
 		templates:       templates,
 		showContentOnly: opt.ShowContentOnly,
+		gitRoot:         opt.GitRoot,
+		defaultLanguage: opt.DefaultLanguage,
+		basePath:        normalizeBasePath(opt.BasePath),
+		imageCacheDir:   opt.ImageCacheDir,
+		imageCacheSize:  opt.ImageCacheSize,
+		fileTypes:       allowedFileTypes,
+		fileTypeList:    fileTypes,
+		siteBaseURL:     strings.TrimSuffix(opt.SiteURL, "/"),
+		siteTitle:       siteTitle,
+		book:            opt.Book,
+		logger:          logging.OrNop(opt.Logger),
+		accessLog:       opt.AccessLog,
+		metrics:         metrics,
+		adminToken:      opt.AdminToken,
+		pdfSlots:        pdfSlots,
+		trustProxy:      opt.TrustProxy,
 	}, nil
 }
 
@@ -206,10 +362,12 @@ func (s *MarkdownServer) HandleMarkdownList(w http.ResponseWriter, r *http.Reque
 	}
 
 	data := struct {
-		Files []MarkdownFile
-		Total int
+		BasePath string
+		Files    []MarkdownFile
+		Total    int
 	}{}
 
+	data.BasePath = s.basePath
 	data.Files = markdownFiles
 	data.Total = len(markdownFiles)
 
@@ -222,98 +380,177 @@ func (s *MarkdownServer) HandleMarkdownList(w http.ResponseWriter, r *http.Reque
 }
 
 // HandleMarkdownView 处理markdown文件查看页面
+// 支持查询参数 mode=reader 进入阅读模式，mode=print 进入阅读模式并自动打印
 func (s *MarkdownServer) HandleMarkdownView(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
 	// 从URL中提取文件路径
 	filePath := strings.TrimPrefix(r.URL.Path, "/view")
 	if filePath == "" || filePath == "/" {
-		http.Redirect(w, r, "/", http.StatusFound)
+		http.Redirect(w, r, s.basePath+"/", http.StatusFound)
 		return nil
 	}
 
-	// 检查是否是通过--content参数提供的文档
-	if s.markdownContent != "" {
-		// 获取默认文件名
-		defaultFileName := "/document.md"
-		// 尝试从内容中提取标题作为文件名
-		title, _ := s.renderer.ExtractTitleAndDescription(s.markdownContent)
-		if title != "" {
-			// 将标题转换为有效的文件名
-			fileName := strings.ToLower(title)
-			fileName = strings.ReplaceAll(fileName, " ", "-")
-			// 移除特殊字符
-			fileName = regexp.MustCompile(`[^a-z0-9\-]`).ReplaceAllString(fileName, "")
-			if fileName != "" {
-				defaultFileName = "/" + fileName + ".md"
-			}
+	// 文档有翻译时按 lang 参数或 Accept-Language 选择显示的版本
+	var languages []LanguageLink
+	if !s.isContentDocument(filePath) {
+		filePath, languages = s.negotiateTranslation(r, proj, filePath)
+	}
+
+	// 读取文件内容（确保获取最新内容）
+	content, currentDir, err := s.readDocument(proj, filePath)
+	if err != nil {
+		return err
+	}
+
+	// 处理Markdown内容，修复Mermaid图表中的语法问题
+	processedContent := s.processContent(string(content), currentDir)
+
+	// 获取所有markdown文件列表
+	var markdownFiles []MarkdownFile
+	if s.isContentDocument(filePath) {
+		if !s.showContentOnly {
+			markdownFiles, _ = s.getMarkdownFiles(proj)
 		}
+	} else {
+		markdownFiles, err = s.getMarkdownFiles(proj)
+		if err != nil {
+			return fmt.Errorf("获取文件列表失败: %v", err)
+		}
+	}
 
-		// 如果请求的是这个特殊文档
-		if filePath == defaultFileName {
-			// 处理Markdown内容，修复Mermaid图表中的语法问题
-			processedContent := s.renderer.ProcessContent(s.markdownContent, "./")
+	data := viewData{
+		BasePath:      s.basePath,
+		FilePath:      filePath,
+		Content:       processedContent,
+		RawPath:       s.basePath + "/raw" + filePath,
+		MarkdownFiles: markdownFiles,
+		DocPath:       filePath,
+		Math:          ContainsMath(string(content)),
+		Languages:     languages,
+	}
+	data.ReaderMode, data.PrintMode = viewMode(r)
 
-			// 获取最新项目树
-			var markdownFiles []MarkdownFile
-			if !s.showContentOnly {
-				markdownFiles, _ = s.getMarkdownFiles(proj)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if len(languages) > 0 {
+		w.Header().Set("Vary", "Accept-Language")
+		for _, link := range languages {
+			if link.Active && link.Lang != "" {
+				w.Header().Set("Content-Language", link.Lang)
 			}
+		}
+	}
+	if err := s.templates.ExecuteTemplate(w, "view", data); err != nil {
+		return fmt.Errorf("模板渲染失败: %v", err)
+	}
 
-			data := struct {
-				FilePath      string
-				Content       template.HTML
-				RawPath       string
-				MarkdownFiles []MarkdownFile
-			}{}
-
-			data.FilePath = filePath
-			data.Content = processedContent
-			data.RawPath = "/raw" + filePath
-			data.MarkdownFiles = markdownFiles
-
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-			if err := s.templates.ExecuteTemplate(w, "view", data); err != nil {
-				return fmt.Errorf("模板渲染失败: %v", err)
-			}
+	s.metrics.observePageView(data.DocPath)
+	return nil
+}
 
-			return nil
-		}
+// HandleExportMarkdown 以纯文本形式返回markdown原文，用于"复制为Markdown"
+// URL格式: /api/markdown/[文件路径]
+func (s *MarkdownServer) HandleExportMarkdown(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
+	filePath := strings.TrimPrefix(r.URL.Path, "/api/markdown")
+	if filePath == "" || filePath == "/" {
+		return fmt.Errorf("文件路径不能为空")
 	}
 
-	// 查找文件节点
-	node, err := proj.FindNode(filePath)
+	content, _, err := s.readDocument(proj, filePath)
 	if err != nil {
-		return fmt.Errorf("文件不存在: %v", err)
+		return err
 	}
 
-	// 读取文件内容（确保获取最新内容）
-	content, err := node.ReadContent()
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Write(content)
+	return nil
+}
+
+// HandleExportHTML 返回服务端渲染的HTML片段，用于"复制为HTML"或嵌入其他页面
+// URL格式: /api/html/[文件路径]?inline_images=true，inline_images 为true时本地图片以data URL嵌入，适合用于邮件等离线场景
+func (s *MarkdownServer) HandleExportHTML(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
+	filePath := strings.TrimPrefix(r.URL.Path, "/api/html")
+	if filePath == "" || filePath == "/" {
+		return fmt.Errorf("文件路径不能为空")
+	}
+
+	content, currentDir, err := s.readDocument(proj, filePath)
 	if err != nil {
-		return fmt.Errorf("读取文件失败: %v", err)
+		return err
 	}
 
-	// 处理Markdown内容，修复Mermaid图表中的语法问题
-	processedContent := s.renderer.ProcessContent(string(content), filepath.Dir(filePath))
+	inlineImages, _ := strconv.ParseBool(r.URL.Query().Get("inline_images"))
+	options := s.processOptions()
+	options.InlineImages = inlineImages
+	options.ImageTree = proj
 
-	// 获取所有markdown文件列表
-	markdownFiles, err := s.getMarkdownFiles(proj)
+	htmlContent, err := RenderHTML(string(s.processContentWithOptions(string(content), currentDir, options)))
 	if err != nil {
-		return fmt.Errorf("获取文件列表失败: %v", err)
+		return fmt.Errorf("渲染HTML失败: %v", err)
 	}
 
-	data := struct {
-		FilePath      string
-		Content       template.HTML
-		RawPath       string
-		MarkdownFiles []MarkdownFile
-	}{}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(htmlContent))
+	return nil
+}
+
+// HandleMarkdownDiff 处理两个markdown文档的对比页面
+// URL格式: /diff?a=[文件路径]&b=[文件路径]&mode=split|unified
+// 配置了GitRoot时可通过 a_ref、b_ref 参数指定Git版本，b 为空时与 a 为同一文件
+func (s *MarkdownServer) HandleMarkdownDiff(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
+	query := r.URL.Query()
+	pathA, pathB := query.Get("a"), query.Get("b")
+	refA, refB := query.Get("a_ref"), query.Get("b_ref")
+	if pathA == "" {
+		return fmt.Errorf("缺少对比文档路径参数 a")
+	}
+	if pathB == "" {
+		pathB = pathA
+	}
+	if pathA == pathB && refA == refB {
+		return fmt.Errorf("对比的两个文档不能相同")
+	}
+
+	contentA, err := s.readDocumentVersion(r.Context(), proj, pathA, refA)
+	if err != nil {
+		return err
+	}
+	contentB, err := s.readDocumentVersion(r.Context(), proj, pathB, refB)
+	if err != nil {
+		return err
+	}
+
+	mode := query.Get("mode")
+	if mode != "unified" {
+		mode = "split"
+	}
 
-	data.FilePath = filePath
-	data.Content = processedContent
-	data.RawPath = "/raw" + filePath
-	data.MarkdownFiles = markdownFiles
+	lines, err := DiffLines(string(contentA), string(contentB))
+	if err != nil {
+		return err
+	}
+	data := diffData{
+		BasePath: s.basePath,
+		NameA:    documentLabel(pathA, refA),
+		NameB:    documentLabel(pathB, refB),
+		Mode:     mode,
+		Lines:    lines,
+		Rows:     SideBySide(lines),
+	}
+	for _, line := range lines {
+		switch line.Op {
+		case DiffInsert:
+			data.Added++
+		case DiffDelete:
+			data.Removed++
+		}
+	}
+
+	query.Set("mode", "split")
+	data.SplitURL = s.basePath + "/diff?" + query.Encode()
+	query.Set("mode", "unified")
+	data.UnifiedURL = s.basePath + "/diff?" + query.Encode()
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := s.templates.ExecuteTemplate(w, "view", data); err != nil {
+	if err := s.templates.ExecuteTemplate(w, "diff", data); err != nil {
 		return fmt.Errorf("模板渲染失败: %v", err)
 	}
 
@@ -329,45 +566,21 @@ func (s *MarkdownServer) HandleMarkdownRaw(w http.ResponseWriter, r *http.Reques
 	}
 
 	// 检查是否是通过--content参数提供的文档
-	if s.markdownContent != "" {
-		// 获取默认文件名
-		defaultFileName := "/document.md"
-		// 尝试从内容中提取标题作为文件名
-		title, _ := s.renderer.ExtractTitleAndDescription(s.markdownContent)
-		if title != "" {
-			// 将标题转换为有效的文件名
-			fileName := strings.ToLower(title)
-			fileName = strings.ReplaceAll(fileName, " ", "-")
-			// 移除特殊字符
-			fileName = regexp.MustCompile(`[^a-z0-9\-]`).ReplaceAllString(fileName, "")
-			if fileName != "" {
-				defaultFileName = "/" + fileName + ".md"
-			}
-		}
-
-		// 如果请求的是这个特殊文档
-		if filePath == defaultFileName {
-			// 从文件路径中提取文件名
-			fileName := filepath.Base(filePath)
-
-			// 设置响应头，支持文件下载
-			w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
-			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
-			w.Write([]byte(s.markdownContent))
-			return nil
-		}
-	}
-
-	// 查找文件节点
-	node, err := proj.FindNode(filePath)
-	if err != nil {
-		return fmt.Errorf("文件不存在: %v", err)
+	if s.isContentDocument(filePath) {
+		// 从文件路径中提取文件名
+		fileName := filepath.Base(filePath)
+
+		// 设置响应头，支持文件下载
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		w.Write([]byte(s.markdownContent))
+		return nil
 	}
 
 	// 读取文件内容（确保获取最新内容）
-	content, err := node.ReadContent()
+	content, _, err := s.readDocument(proj, filePath)
 	if err != nil {
-		return fmt.Errorf("读取文件失败: %v", err)
+		return err
 	}
 
 	// 从文件路径中提取文件名
@@ -389,7 +602,7 @@ func (s *MarkdownServer) HandleMarkdownRaw(w http.ResponseWriter, r *http.Reques
 // HandleMarkdownContent 处理直接提供的markdown内容
 func (s *MarkdownServer) HandleMarkdownContent(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
 	// 处理Markdown内容，修复Mermaid图表中的语法问题
-	processedContent := s.renderer.ProcessContent(s.markdownContent, "./")
+	processedContent := s.processContent(s.markdownContent, "./")
 
 	// 准备数据
 	var markdownFiles []MarkdownFile
@@ -398,23 +611,23 @@ func (s *MarkdownServer) HandleMarkdownContent(w http.ResponseWriter, r *http.Re
 		markdownFiles, _ = s.getMarkdownFiles(proj)
 	}
 
-	data := struct {
-		FilePath      string
-		Content       template.HTML
-		RawPath       string
-		MarkdownFiles []MarkdownFile
-	}{}
-
-	data.FilePath = "直接提供的内容"
-	data.Content = processedContent
-	data.RawPath = "/raw-content" // 设置一个固定路径用于下载
-	data.MarkdownFiles = markdownFiles
+	data := viewData{
+		BasePath:      s.basePath,
+		FilePath:      "直接提供的内容",
+		Content:       processedContent,
+		RawPath:       s.basePath + "/raw-content", // 设置一个固定路径用于下载
+		MarkdownFiles: markdownFiles,
+		DocPath:       s.contentDocumentPath(),
+		Math:          ContainsMath(s.markdownContent),
+	}
+	data.ReaderMode, data.PrintMode = viewMode(r)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := s.templates.ExecuteTemplate(w, "view", data); err != nil {
 		return fmt.Errorf("模板渲染失败: %v", err)
 	}
 
+	s.metrics.observePageView(data.DocPath)
 	return nil
 }
 
@@ -428,15 +641,28 @@ func (s *MarkdownServer) HandleRawContentDownload(w http.ResponseWriter, r *http
 }
 
 // HandleImages 处理Markdown文档中的本地图片请求
+// 支持查询参数 w、h 在服务端等比缩小图片，缩放结果会缓存到磁盘
 func (s *MarkdownServer) HandleImages(w http.ResponseWriter, r *http.Request, proj ProjectTree) error {
 	// 从URL中提取图片文件路径
-	// URL格式: /images/[图片路径]
+	// URL格式: /images/[图片路径]?w=[最大宽度]&h=[最大高度]
 	imagePath := strings.TrimPrefix(r.URL.Path, "/images")
 	if imagePath == "" || imagePath == "/" {
 		http.Error(w, "图片路径不能为空", http.StatusBadRequest)
 		return nil
 	}
 
+	// 解析缩放参数
+	width, err := parseImageDimension(r.URL.Query().Get("w"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("宽度参数无效: %v", err), http.StatusBadRequest)
+		return nil
+	}
+	height, err := parseImageDimension(r.URL.Query().Get("h"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("高度参数无效: %v", err), http.StatusBadRequest)
+		return nil
+	}
+
 	// 查找图片文件节点
 	node, err := proj.FindNode(imagePath)
 	if err != nil {
@@ -459,11 +685,34 @@ func (s *MarkdownServer) HandleImages(w http.ResponseWriter, r *http.Request, pr
 
 	// 设置正确的Content-Type
 	contentType := "application/octet-stream"
-	if ext := strings.ToLower(filepath.Ext(node.GetName())); ext != "" {
-		if mime, ok := mimeTypes[ext[1:]]; ok {
-			contentType = mime
-		}
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(node.GetName())), ".")
+	if mime, ok := mimeTypes[ext]; ok {
+		contentType = mime
+	}
+
+	// 仅对支持的格式进行缩放，其他格式直接返回原图
+	resize := (width > 0 || height > 0) && resizableImageTypes[ext]
+	if !resize {
+		width, height = 0, 0
 	}
+
+	// 基于内容和缩放参数生成ETag，内容未变化时返回304
+	cacheKey := ImageCacheKey(content, width, height)
+	etag := `"` + cacheKey + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if info := node.GetFileInfo(); info != nil {
+		w.Header().Set("Last-Modified", info.ModTime().UTC().Format(http.TimeFormat))
+	}
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	if resize {
+		content, contentType = s.resizedImage(content, contentType, cacheKey, width, height)
+	}
+
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
 
@@ -472,6 +721,79 @@ func (s *MarkdownServer) HandleImages(w http.ResponseWriter, r *http.Request, pr
 	return nil
 }
 
+// resizedImage 返回缩放后的图片，优先读取缓存；缩放失败时返回原图
+func (s *MarkdownServer) resizedImage(content []byte, contentType, cacheKey string, width, height int) ([]byte, string) {
+	cache := s.thumbnailCache()
+	if cache != nil {
+		data, ok := cache.Get(cacheKey)
+		s.metrics.observeImageCache(ok)
+		if ok {
+			return data, http.DetectContentType(data)
+		}
+	}
+
+	data, resizedType, err := resizeImage(content, width, height)
+	if err != nil {
+		s.logger.Debug("图片缩放失败，返回原图", "key", cacheKey, logging.KeyError, err)
+		return content, contentType
+	}
+
+	if cache != nil {
+		cache.Put(cacheKey, data)
+	}
+	return data, resizedType
+}
+
+// thumbnailCache 返回缩略图缓存，创建失败时返回nil（不使用缓存）
+func (s *MarkdownServer) thumbnailCache() *ImageCache {
+	s.imageCacheOnce.Do(func() {
+		dir := s.imageCacheDir
+		if dir == "" {
+			base, err := os.UserCacheDir()
+			if err != nil {
+				base = os.TempDir()
+			}
+			dir = filepath.Join(base, "markdown-image-cache")
+		}
+		cache, err := NewImageCache(dir, s.imageCacheSize)
+		if err != nil {
+			s.logger.Warn("创建缩略图缓存失败，不使用缓存", "dir", dir, logging.KeyError, err)
+			return
+		}
+		s.imageCache = cache
+	})
+	return s.imageCache
+}
+
+// parseImageDimension 解析图片宽高参数，空字符串表示不限制
+func parseImageDimension(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("必须为正整数: %s", value)
+	}
+	if n > maxImageDimension {
+		return 0, fmt.Errorf("不能超过 %d: %s", maxImageDimension, value)
+	}
+	return n, nil
+}
+
+// etagMatches 判断If-None-Match请求头是否包含指定的ETag
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
 // Handler 返回配置好的HTTP处理器
 func (s *MarkdownServer) Handler() http.Handler {
 	mux := http.NewServeMux()
@@ -553,6 +875,52 @@ func (s *MarkdownServer) Handler() http.Handler {
 		}
 	})
 
+	// 导出markdown原文和HTML片段
+	mux.HandleFunc("/api/markdown/", func(w http.ResponseWriter, r *http.Request) {
+		if s.projectTree == nil {
+			http.Error(w, "项目树未初始化", http.StatusInternalServerError)
+			return
+		}
+		if err := s.HandleExportMarkdown(w, r, s.projectTree); err != nil {
+			http.Error(w, fmt.Sprintf("导出Markdown失败: %v", err), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	mux.HandleFunc("/api/html/", func(w http.ResponseWriter, r *http.Request) {
+		if s.projectTree == nil {
+			http.Error(w, "项目树未初始化", http.StatusInternalServerError)
+			return
+		}
+		if err := s.HandleExportHTML(w, r, s.projectTree); err != nil {
+			http.Error(w, fmt.Sprintf("导出HTML失败: %v", err), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	mux.HandleFunc("/api/outline/", func(w http.ResponseWriter, r *http.Request) {
+		if s.projectTree == nil {
+			http.Error(w, "项目树未初始化", http.StatusInternalServerError)
+			return
+		}
+		if err := s.HandleOutline(w, r, s.projectTree); err != nil {
+			http.Error(w, fmt.Sprintf("获取文档大纲失败: %v", err), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	// 文档对比
+	mux.HandleFunc("/diff", func(w http.ResponseWriter, r *http.Request) {
+		if s.projectTree == nil {
+			http.Error(w, "项目树未初始化", http.StatusInternalServerError)
+			return
+		}
+		if err := s.HandleMarkdownDiff(w, r, s.projectTree); err != nil {
+			http.Error(w, fmt.Sprintf("文档对比失败: %v", err), http.StatusBadRequest)
+			return
+		}
+	})
+
 	// 本地图片访问
 	mux.HandleFunc("/images/", func(w http.ResponseWriter, r *http.Request) {
 		if s.projectTree == nil {
@@ -565,7 +933,82 @@ func (s *MarkdownServer) Handler() http.Handler {
 		}
 	})
 
-	return mux
+	// 文档中链接的附件
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		if s.projectTree == nil {
+			http.Error(w, "项目树未初始化", http.StatusInternalServerError)
+			return
+		}
+		if err := s.HandleFiles(w, r, s.projectTree); err != nil {
+			http.Error(w, fmt.Sprintf("处理文件失败: %v", err), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	// 将文档合并为一本书导出
+	mux.HandleFunc("/book", func(w http.ResponseWriter, r *http.Request) {
+		if s.projectTree == nil {
+			http.Error(w, "项目树未初始化", http.StatusInternalServerError)
+			return
+		}
+		if err := s.HandleBook(w, r, s.projectTree); err != nil {
+			http.Error(w, fmt.Sprintf("导出书籍失败: %v", err), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	// 站点地图和RSS订阅
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		if s.projectTree == nil {
+			http.Error(w, "项目树未初始化", http.StatusInternalServerError)
+			return
+		}
+		if err := s.HandleSitemap(w, r, s.projectTree); err != nil {
+			http.Error(w, fmt.Sprintf("生成站点地图失败: %v", err), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		if s.projectTree == nil {
+			http.Error(w, "项目树未初始化", http.StatusInternalServerError)
+			return
+		}
+		if err := s.HandleFeed(w, r, s.projectTree); err != nil {
+			http.Error(w, fmt.Sprintf("生成RSS订阅失败: %v", err), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	// 文档管理，需要设置访问令牌
+	if s.adminToken != "" {
+		mux.HandleFunc("/api/docs/", func(w http.ResponseWriter, r *http.Request) {
+			if err := s.HandleDocuments(w, r); err != nil {
+				http.Error(w, fmt.Sprintf("修改文档失败: %v", err), http.StatusInternalServerError)
+				return
+			}
+		})
+	}
+
+	// Prometheus指标
+	if s.metrics != nil {
+		mux.Handle(MetricsPath, s.metrics.handler())
+	}
+
+	handler := s.instrument(mux)
+	if s.basePath == "" {
+		return handler
+	}
+
+	// 挂载在子路径下时，去掉路径前缀后再交给内部路由处理
+	stripped := http.StripPrefix(s.basePath, handler)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == s.basePath {
+			http.Redirect(w, r, s.basePath+"/", http.StatusMovedPermanently)
+			return
+		}
+		stripped.ServeHTTP(w, r)
+	})
 }
 
 // StartServer 启动Markdown文档服务（已过时，建议使用Handler()方法）
@@ -584,14 +1027,14 @@ func (s *MarkdownServer) StartServer(port int) error {
 		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", currentPort))
 		if err != nil {
 			// 端口被占用，尝试下一个
-			fmt.Printf("端口 %d 已被占用，尝试下一个端口...\n", currentPort)
+			s.logger.Info("端口已被占用，尝试下一个端口", "port", currentPort)
 			continue
 		}
 
 		// 端口可用，关闭监听器并使用该端口启动服务器
 		listener.Close()
 
-		fmt.Printf("正在启动Markdown文档服务，端口: %d\n", currentPort)
+		s.logger.Info("正在启动Markdown文档服务", "port", currentPort)
 
 		server = &http.Server{
 			Addr:    fmt.Sprintf(":%d", currentPort),
@@ -601,12 +1044,11 @@ func (s *MarkdownServer) StartServer(port int) error {
 		// 启动服务器（使用goroutine避免阻塞）
 		go func(p int) {
 			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				fmt.Printf("服务器运行失败: %v\n", err)
+				s.logger.Error("服务器运行失败", "port", p, logging.KeyError, err)
 			}
 		}(currentPort)
 
-		fmt.Printf("Markdown文档服务已启动: http://localhost:%d\n", currentPort)
-		fmt.Println("按 Ctrl+C 停止服务...")
+		s.logger.Info("Markdown文档服务已启动，按 Ctrl+C 停止服务", "url", fmt.Sprintf("http://localhost:%d", currentPort))
 		break
 	}
 
@@ -618,7 +1060,7 @@ func (s *MarkdownServer) StartServer(port int) error {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	<-quit
-	fmt.Println("\n正在关闭Markdown文档服务...")
+	s.logger.Info("正在关闭Markdown文档服务")
 
 	return nil
 }
@@ -664,26 +1106,14 @@ func (s *MarkdownServer) getMarkdownFiles(proj ProjectTree) ([]MarkdownFile, err
 
 	// 如果提供了markdown内容，将其添加到文件列表
 	if s.markdownContent != "" {
-		// 使用默认文件名
-		defaultFileName := "document.md"
-		// 尝试从内容中提取标题作为文件名
+		defaultPath := s.contentDocumentPath()
 		title, _ := s.renderer.ExtractTitleAndDescription(s.markdownContent)
-		if title != "" {
-			// 将标题转换为有效的文件名
-			fileName := strings.ToLower(title)
-			fileName = strings.ReplaceAll(fileName, " ", "-")
-			// 移除特殊字符
-			fileName = regexp.MustCompile(`[^a-z0-9\-]`).ReplaceAllString(fileName, "")
-			if fileName != "" {
-				defaultFileName = fileName + ".md"
-			}
-		}
 
 		// 添加到文件列表，确保RelativePath以斜杠开头
 		file := MarkdownFile{
-			Path:         "/" + defaultFileName,
-			Name:         defaultFileName,
-			RelativePath: "/" + defaultFileName,
+			Path:         defaultPath,
+			Name:         strings.TrimPrefix(defaultPath, "/"),
+			RelativePath: defaultPath,
 			Size:         int64(len(s.markdownContent)),
 			Title:        title,
 		}
@@ -698,3 +1128,132 @@ func (s *MarkdownServer) getMarkdownFiles(proj ProjectTree) ([]MarkdownFile, err
 
 	return markdownFiles, nil
 }
+
+// contentDocumentPath 返回通过--content参数提供的文档对应的虚拟路径
+// 优先使用从内容中提取的标题生成文件名，默认为 /document.md
+func (s *MarkdownServer) contentDocumentPath() string {
+	defaultFileName := "/document.md"
+	title, _ := s.renderer.ExtractTitleAndDescription(s.markdownContent)
+	if title != "" {
+		// 将标题转换为有效的文件名
+		fileName := strings.ToLower(title)
+		fileName = strings.ReplaceAll(fileName, " ", "-")
+		// 移除特殊字符
+		fileName = regexp.MustCompile(`[^a-z0-9\-]`).ReplaceAllString(fileName, "")
+		if fileName != "" {
+			defaultFileName = "/" + fileName + ".md"
+		}
+	}
+	return defaultFileName
+}
+
+// isContentDocument 判断路径是否指向通过--content参数提供的文档
+func (s *MarkdownServer) isContentDocument(filePath string) bool {
+	return s.markdownContent != "" && filePath == s.contentDocumentPath()
+}
+
+// readDocument 读取指定路径的markdown文档内容，返回内容及其所在目录
+func (s *MarkdownServer) readDocument(proj ProjectTree, filePath string) ([]byte, string, error) {
+	if s.isContentDocument(filePath) {
+		return []byte(s.markdownContent), "./", nil
+	}
+	// 只读取Markdown文档，其他文件只能通过 /files 按允许的扩展名访问
+	if !isMarkdownFile(filePath) {
+		return nil, "", fmt.Errorf("文件不存在: %w: %s", ErrInvalidPath, filePath)
+	}
+
+	// 查找文件节点
+	node, err := proj.FindNode(filePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("文件不存在: %v", err)
+	}
+
+	content, err := node.ReadContent()
+	if err != nil {
+		return nil, "", fmt.Errorf("读取文件失败: %v", err)
+	}
+
+	return content, filepath.Dir(filePath), nil
+}
+
+// readDocumentVersion 读取文档的指定Git版本，ref 为空时读取当前内容
+func (s *MarkdownServer) readDocumentVersion(ctx context.Context, proj ProjectTree, filePath, ref string) ([]byte, error) {
+	if !strings.HasPrefix(filePath, "/") {
+		filePath = "/" + filePath
+	}
+
+	if ref == "" {
+		content, _, err := s.readDocument(proj, filePath)
+		return content, err
+	}
+
+	if s.gitRoot == "" {
+		return nil, fmt.Errorf("未配置Git仓库目录，无法读取版本 %s", ref)
+	}
+	// 与读取当前内容相同，只允许读取非隐藏路径下的Markdown文档
+	filePath = path.Clean(filePath)
+	if !isMarkdownFile(filePath) || strings.Contains(filePath, "/.") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPath, filePath)
+	}
+	// 拒绝以-开头的引用，避免被解析为git命令参数
+	if strings.HasPrefix(ref, "-") || strings.ContainsAny(ref, " :") {
+		return nil, fmt.Errorf("无效的Git版本: %s", ref)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", s.gitRoot, "show", ref+":"+strings.TrimPrefix(filePath, "/"))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("读取Git版本 %s:%s 失败: %v", ref, filePath, err)
+	}
+
+	return output, nil
+}
+
+// documentLabel 返回对比页面中显示的文档名称
+func documentLabel(filePath, ref string) string {
+	if ref == "" {
+		return filePath
+	}
+	return filePath + "@" + ref
+}
+
+// processContent 使用服务器配置处理Markdown内容，图片和附件路径会带上挂载前缀
+func (s *MarkdownServer) processContent(content, currentDir string) template.HTML {
+	return s.processContentWithOptions(content, currentDir, s.processOptions())
+}
+
+// processOptions 返回服务器配置对应的处理选项
+func (s *MarkdownServer) processOptions() ProcessOptions {
+	options := DefaultProcessOptions()
+	options.ImageURLPrefix = s.basePath + "/images"
+	options.FileURLPrefix = s.basePath + "/files"
+	options.FileTypes = s.fileTypeList
+	return options
+}
+
+// processContentWithOptions 使用指定选项处理Markdown内容并记录渲染耗时
+func (s *MarkdownServer) processContentWithOptions(content, currentDir string, options ProcessOptions) template.HTML {
+	start := time.Now()
+	defer func() { s.metrics.observeRender(time.Since(start)) }()
+	return s.renderer.ProcessContentWithOptions(content, currentDir, options)
+}
+
+// normalizeBasePath 规范化路径前缀：以/开头、不以/结尾，根路径返回空字符串
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// viewMode 从请求参数中解析页面显示模式，返回是否为阅读模式和打印模式
+func viewMode(r *http.Request) (readerMode bool, printMode bool) {
+	switch r.URL.Query().Get("mode") {
+	case "reader":
+		return true, false
+	case "print":
+		return true, true
+	}
+	return false, false
+}
@@ -0,0 +1,228 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Heading 表示Markdown文档中的一个标题
+type Heading struct {
+	// Level 标题级别（1-6）
+	Level int
+	// Text 标题的纯文本内容
+	Text string
+	// Line 标题所在行号（从1开始）
+	Line int
+}
+
+var (
+	// headingLinkPattern 匹配标题中的链接和图片语法
+	headingLinkPattern = regexp.MustCompile(`(!?)\[([^\]]*)\]\([^)]*\)`)
+	// headingTagPattern 匹配标题中的HTML标签
+	headingTagPattern = regexp.MustCompile(`<[^>]+>`)
+	// headingUnderscorePattern 匹配下划线形式的强调语法
+	headingUnderscorePattern = regexp.MustCompile(`(^|[^\p{L}\p{N}])_{1,2}([^_]+?)_{1,2}([^\p{L}\p{N}]|$)`)
+	// setextUnderlinePattern 匹配Setext风格标题的下划线
+	setextUnderlinePattern = regexp.MustCompile(`^(=+|-+)\s*$`)
+)
+
+// Slugger 生成与GitHub兼容的标题锚点ID
+// 同一文档中重复的标题会依次追加 -1、-2 等后缀，与GitHub的行为保持一致
+type Slugger struct {
+	occurrences map[string]int
+}
+
+// NewSlugger 创建新的锚点生成器
+func NewSlugger() *Slugger {
+	return &Slugger{
+		occurrences: make(map[string]int),
+	}
+}
+
+// Slug 为标题文本生成唯一的锚点ID
+func (s *Slugger) Slug(text string) string {
+	result := GitHubSlug(text)
+	original := result
+
+	// 与github-slugger一致：冲突时递增原始slug的计数并追加后缀
+	for {
+		if _, exists := s.occurrences[result]; !exists {
+			break
+		}
+		s.occurrences[original]++
+		result = original + "-" + strconv.Itoa(s.occurrences[original])
+	}
+	s.occurrences[result] = 0
+
+	return result
+}
+
+// Reset 清空已生成的锚点记录，用于开始处理新文档
+func (s *Slugger) Reset() {
+	s.occurrences = make(map[string]int)
+}
+
+// GitHubSlug 按GitHub的规则将标题文本转换为锚点ID（不处理重复）
+// 规则：转为小写，移除标点和符号，保留字母（含中日韩文字）、数字、下划线和连字符，空格替换为连字符
+func GitHubSlug(text string) string {
+	var result strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ':
+			result.WriteRune('-')
+		case r == '-' || r == '_':
+			result.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) || unicode.Is(unicode.Pc, r):
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// headingPlainText 将标题中的行内Markdown语法转换为渲染后的纯文本
+func headingPlainText(text string) string {
+	// 图片不产生文本，链接只保留文字部分
+	text = headingLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := headingLinkPattern.FindStringSubmatch(match)
+		if parts[1] == "!" {
+			return ""
+		}
+		return parts[2]
+	})
+	text = headingTagPattern.ReplaceAllString(text, "")
+	text = headingUnderscorePattern.ReplaceAllString(text, "$1$2$3")
+	text = strings.ReplaceAll(text, "`", "")
+	return strings.TrimSpace(text)
+}
+
+// parseATXHeading 解析ATX风格的标题行（# 标题），返回级别和标题文本
+func parseATXHeading(line string) (int, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	// 超过3个空格缩进的是代码块
+	if len(line)-len(trimmed) > 3 {
+		return 0, "", false
+	}
+
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+
+	// 去掉可选的结尾#序列
+	rest = strings.TrimSpace(rest)
+	if stripped := strings.TrimRight(rest, "#"); stripped != rest {
+		if stripped == "" || strings.HasSuffix(stripped, " ") || strings.HasSuffix(stripped, "\t") {
+			rest = strings.TrimSpace(stripped)
+		}
+	}
+
+	return level, rest, true
+}
+
+// isFenceLine 判断是否为围栏代码块的起止行，返回围栏标记
+func isFenceLine(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return "", false
+	}
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, marker) {
+			return marker, true
+		}
+	}
+	return "", false
+}
+
+// isSetextCandidate 判断一行文本能否作为Setext标题的文本行
+func isSetextCandidate(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return false
+	}
+	// 列表、引用、表格和HTML块不会构成Setext标题
+	for _, prefix := range []string{"- ", "* ", "+ ", ">", "|", "<", "#"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// scanHeadings 扫描Markdown内容中的标题，跳过代码块
+// visit 回调接收标题信息和该标题文本所在行的索引
+func scanHeadings(lines []string, visit func(heading Heading, index int, setext bool)) {
+	fence := ""
+	for i, line := range lines {
+		if marker, ok := isFenceLine(line); ok {
+			if fence == "" {
+				fence = marker
+			} else if marker == fence && strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), marker[:1])) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		if level, text, ok := parseATXHeading(line); ok {
+			visit(Heading{Level: level, Text: headingPlainText(text), Line: i + 1}, i, false)
+			continue
+		}
+
+		// Setext标题：仅处理前一行为空行的单行段落
+		if i+1 < len(lines) && setextUnderlinePattern.MatchString(strings.TrimSpace(lines[i+1])) &&
+			isSetextCandidate(line) && (i == 0 || strings.TrimSpace(lines[i-1]) == "") {
+			level := 2
+			if strings.HasPrefix(strings.TrimSpace(lines[i+1]), "=") {
+				level = 1
+			}
+			visit(Heading{Level: level, Text: headingPlainText(line), Line: i + 1}, i, true)
+		}
+	}
+}
+
+// ExtractHeadings 提取Markdown内容中的所有标题
+func ExtractHeadings(content string) []Heading {
+	var headings []Heading
+	scanHeadings(strings.Split(content, "\n"), func(heading Heading, index int, setext bool) {
+		headings = append(headings, heading)
+	})
+	return headings
+}
+
+// AddHeadingAnchors 为Markdown中的标题注入与GitHub兼容的锚点
+// 锚点以 <a id="..."></a> 的形式插入标题文本开头，页面脚本会将其提升为标题元素的ID
+func (r *MarkdownRenderer) AddHeadingAnchors(content string) string {
+	lines := strings.Split(content, "\n")
+	slugger := NewSlugger()
+
+	scanHeadings(lines, func(heading Heading, index int, setext bool) {
+		anchor := `<a id="` + slugger.Slug(heading.Text) + `"></a>`
+		line := lines[index]
+		if setext {
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			lines[index] = line[:indent] + anchor + line[indent:]
+			return
+		}
+
+		// 在 # 标记之后插入锚点
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		level := heading.Level
+		rest := strings.TrimLeft(trimmed[level:], " \t")
+		lines[index] = line[:indent] + strings.Repeat("#", level) + " " + anchor + rest
+	})
+
+	return strings.Join(lines, "\n")
+}
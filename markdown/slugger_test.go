@@ -0,0 +1,84 @@
+package markdown
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGitHubSlug(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello World", "hello-world"},
+		{"API: v2.0 (beta)!", "api-v20-beta"},
+		{"snake_case and kebab-case", "snake_case-and-kebab-case"},
+		{"快速开始 Quick Start", "快速开始-quick-start"},
+		{"  leading space", "--leading-space"},
+		{"C++ & Go", "c--go"},
+	}
+	for _, tt := range tests {
+		if got := GitHubSlug(tt.text); got != tt.want {
+			t.Errorf("GitHubSlug(%q) = %q，期望 %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSluggerDedupe(t *testing.T) {
+	tests := []struct {
+		name     string
+		headings []string
+		want     []string
+	}{
+		{"重复标题依次追加后缀", []string{"用法", "用法", "用法"}, []string{"用法", "用法-1", "用法-2"}},
+		// 与github-slugger一致：已有的 foo-1 不会被重复使用
+		{"后缀与已有标题冲突", []string{"foo", "foo-1", "foo"}, []string{"foo", "foo-1", "foo-2"}},
+		{"标题本身带后缀", []string{"foo-1", "foo", "foo"}, []string{"foo-1", "foo", "foo-2"}},
+		{"大小写和标点不同", []string{"Install", "install!", "INSTALL"}, []string{"install", "install-1", "install-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slugger := NewSlugger()
+			var got []string
+			for _, heading := range tt.headings {
+				got = append(got, slugger.Slug(heading))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("期望 %v，实际为 %v", tt.want, got)
+			}
+
+			// Reset 后重新计数
+			slugger.Reset()
+			if slug := slugger.Slug(tt.headings[0]); slug != tt.want[0] {
+				t.Errorf("Reset 后期望 %q，实际为 %q", tt.want[0], slug)
+			}
+		})
+	}
+}
+
+func TestAddHeadingAnchors(t *testing.T) {
+	content := strings.Join([]string{
+		"# 简介",
+		"```",
+		"# 代码块中的注释",
+		"```",
+		"## 简介",
+		"",
+		"Setext 标题",
+		"---",
+	}, "\n")
+
+	got := strings.Split(NewMarkdownRenderer().AddHeadingAnchors(content), "\n")
+	want := map[int]string{
+		0: `# <a id="简介"></a>简介`,
+		2: "# 代码块中的注释",
+		4: `## <a id="简介-1"></a>简介`,
+		6: `<a id="setext-标题"></a>Setext 标题`,
+	}
+	for index, line := range want {
+		if got[index] != line {
+			t.Errorf("第 %d 行期望 %q，实际为 %q", index+1, line, got[index])
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package markdown
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// translationLangPattern 翻译文件名中语言代码的格式，如 en、zh-TW、pt-BR
+var translationLangPattern = regexp.MustCompile(`^[a-z]{2}(-[A-Za-z]{2,4})?$`)
+
+// LanguageLink 语言切换菜单中的一项
+type LanguageLink struct {
+	// Lang 语言代码，默认文档为 ServerOptions.DefaultLanguage，未设置时为空
+	Lang string
+	// Name 菜单中显示的名称
+	Name string
+	// URL 切换到该语言的链接
+	URL string
+	// Active 是否为当前显示的语言
+	Active bool
+}
+
+// translation 文档的一个语言版本
+type translation struct {
+	lang string
+	path string
+}
+
+// splitTranslation 拆分翻译文件的路径，/docs/README.en.md 返回 /docs/README.md 和 en；
+// 不是翻译文件时返回原路径和空字符串
+func splitTranslation(filePath string) (string, string) {
+	ext := path.Ext(filePath)
+	stem := strings.TrimSuffix(filePath, ext)
+	lang := strings.TrimPrefix(path.Ext(stem), ".")
+	if lang == "" || !translationLangPattern.MatchString(lang) {
+		return filePath, ""
+	}
+	if _, err := language.Parse(lang); err != nil {
+		return filePath, ""
+	}
+	return strings.TrimSuffix(stem, "."+lang) + ext, lang
+}
+
+// findTranslations 查找默认文档basePath的所有翻译，按语言代码排序
+func findTranslations(proj ProjectTree, basePath string) []translation {
+	dir := path.Dir(basePath)
+	var translations []translation
+	proj.Visit(func(nodePath string, node NodeInfo, depth int) error {
+		if node.IsDir() || path.Dir(nodePath) != dir {
+			return nil
+		}
+		if base, lang := splitTranslation(nodePath); lang != "" && base == basePath {
+			translations = append(translations, translation{lang: lang, path: nodePath})
+		}
+		return nil
+	})
+	sort.Slice(translations, func(i, j int) bool {
+		return translations[i].lang < translations[j].lang
+	})
+	return translations
+}
+
+// negotiateTranslation 选择要显示的文档版本，返回文档路径和语言切换菜单
+// 优先使用 ?lang= 参数，其次按 Accept-Language 匹配；请求的路径本身是翻译文件且没有 lang 参数时直接显示该文件。
+// 没有匹配的翻译时显示默认文档，文档没有翻译时菜单为空
+func (s *MarkdownServer) negotiateTranslation(r *http.Request, proj ProjectTree, filePath string) (string, []LanguageLink) {
+	basePath, requestedLang := splitTranslation(filePath)
+	translations := findTranslations(proj, basePath)
+	if len(translations) == 0 {
+		return filePath, nil
+	}
+
+	selected := translation{lang: s.defaultLanguage, path: basePath}
+	if requestedLang != "" {
+		selected = translation{lang: requestedLang, path: filePath}
+	}
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		selected = s.matchTranslation(lang, basePath, translations)
+	} else if requestedLang == "" {
+		if accept := r.Header.Get("Accept-Language"); accept != "" {
+			selected = s.matchTranslation(accept, basePath, translations)
+		}
+	}
+
+	// 默认文档放在菜单的第一项
+	all := append([]translation{{lang: s.defaultLanguage, path: basePath}}, translations...)
+	links := make([]LanguageLink, 0, len(all))
+	for _, t := range all {
+		link := LanguageLink{
+			Lang:   t.lang,
+			Name:   languageName(t.lang),
+			URL:    s.basePath + "/view" + basePath,
+			Active: t.path == selected.path,
+		}
+		if t.lang != "" {
+			link.URL += "?lang=" + url.QueryEscape(t.lang)
+		}
+		links = append(links, link)
+	}
+	return selected.path, links
+}
+
+// matchTranslation 按语言偏好（lang 参数或 Accept-Language 请求头）选择翻译，没有匹配时返回默认文档
+func (s *MarkdownServer) matchTranslation(preference, basePath string, translations []translation) translation {
+	fallback := translation{lang: s.defaultLanguage, path: basePath}
+	preferred, _, err := language.ParseAcceptLanguage(preference)
+	if err != nil || len(preferred) == 0 {
+		return fallback
+	}
+
+	// 第一项为默认文档的语言，没有匹配时返回第一项
+	defaultTag := language.Und
+	if s.defaultLanguage != "" {
+		defaultTag = language.Make(s.defaultLanguage)
+	}
+	tags := []language.Tag{defaultTag}
+	for _, t := range translations {
+		tags = append(tags, language.Make(t.lang))
+	}
+
+	_, index, confidence := language.NewMatcher(tags).Match(preferred...)
+	if confidence == language.No || index == 0 {
+		return fallback
+	}
+	return translations[index-1]
+}
+
+// languageName 返回语言在切换菜单中显示的名称，使用该语言自身的名称，如 English、日本語
+func languageName(lang string) string {
+	if lang == "" {
+		return "默认"
+	}
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return lang
+	}
+	if name := display.Self.Name(tag); name != "" {
+		return name
+	}
+	return lang
+}
@@ -0,0 +1,246 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AuditItem 审计记录中的消息，只保留标题和链接
+type AuditItem struct {
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+}
+
+// AuditRecord 一次发送尝试的审计记录
+type AuditRecord struct {
+	Time         time.Time          `json:"time"`               // 开始发送的时间
+	Channel      string             `json:"channel"`            // 注册名称
+	Type         string             `json:"type"`               // 通知器类型，即 Notifier.Name()
+	Items        []AuditItem        `json:"items"`              // 发送的消息
+	Status       NotificationStatus `json:"status"`             // 发送结果
+	SuccessCount int                `json:"success_count"`      // 成功发送的消息数
+	Error        string             `json:"error,omitempty"`    // 发送失败的原因
+	Duration     time.Duration      `json:"duration"`           // 发送的耗时
+	TraceID      string             `json:"trace_id,omitempty"` // 发送span的追踪ID，没有记录span时为空
+}
+
+// AuditQuery 审计记录的查询条件，零值的字段不参与过滤
+type AuditQuery struct {
+	Channel  string             // 注册名称
+	Status   NotificationStatus // 发送结果
+	Since    time.Time          // 不早于该时间
+	Until    time.Time          // 早于该时间
+	Contains string             // 任一消息的标题或链接包含该文本，用于排查某条消息为什么没有收到
+	Limit    int                // 最多返回的记录数，保留最新的记录，0表示不限制
+}
+
+// Match 判断记录是否满足查询条件，不考虑 Limit
+func (q AuditQuery) Match(record AuditRecord) bool {
+	if q.Channel != "" && record.Channel != q.Channel {
+		return false
+	}
+	if q.Status != "" && record.Status != q.Status {
+		return false
+	}
+	if !q.Since.IsZero() && record.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !record.Time.Before(q.Until) {
+		return false
+	}
+	if q.Contains != "" {
+		for _, item := range record.Items {
+			if strings.Contains(item.Title, q.Contains) || strings.Contains(item.URL, q.Contains) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// AuditLog 通知审计日志，NotifierManager 通过 SetAuditLog 设置后记录每一次发送尝试
+// 内置 JSONLAuditLog，需要其他存储（例如数据库）时实现此接口
+type AuditLog interface {
+	// Record 记录一次发送尝试，需要可以并发调用
+	Record(ctx context.Context, record AuditRecord) error
+	// Query 按时间顺序返回满足条件的记录
+	Query(ctx context.Context, query AuditQuery) ([]AuditRecord, error)
+}
+
+// SetAuditLog 设置审计日志，之后每次通过 SendToAll 或 SendToSpecific 发送都会写入一条记录，为nil时不记录
+// 摘要模式等实现了 FlushReporter 的通知器，累积消息和实际合并发送各写入一条记录；
+// 写入失败只记录警告日志，不影响发送结果；Close 会关闭实现了 io.Closer 的审计日志
+func (m *NotifierManager) SetAuditLog(log AuditLog) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.audit = log
+}
+
+// ReplaceAuditLog 替换审计日志，被替换的审计日志实现了 io.Closer 时会被关闭，可用于配置热更新
+func (m *NotifierManager) ReplaceAuditLog(log AuditLog) error {
+	m.mu.Lock()
+	previous := m.audit
+	m.audit = log
+	m.mu.Unlock()
+
+	if previous == nil {
+		return nil
+	}
+	if t := reflect.TypeOf(previous); t == reflect.TypeOf(log) && t.Comparable() && previous == log {
+		return nil
+	}
+	if closer, ok := previous.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// QueryAudit 查询审计日志，没有设置审计日志时返回错误
+func (m *NotifierManager) QueryAudit(ctx context.Context, query AuditQuery) ([]AuditRecord, error) {
+	m.mu.RLock()
+	audit := m.audit
+	m.mu.RUnlock()
+
+	if audit == nil {
+		return nil, errors.New("没有设置审计日志")
+	}
+	return audit.Query(ctx, query)
+}
+
+// recordAudit 将一次发送尝试写入审计日志
+func (m *NotifierManager) recordAudit(ctx context.Context, n NamedNotifier, items []MessageItem, start time.Time, result *NotificationResult, sendErr error) {
+	m.mu.RLock()
+	audit, logger := m.audit, m.logger
+	m.mu.RUnlock()
+	if audit == nil {
+		return
+	}
+
+	record := AuditRecord{
+		Time:     start,
+		Channel:  n.Name,
+		Type:     n.Notifier.Name(),
+		Items:    make([]AuditItem, len(items)),
+		Status:   StatusSuccess,
+		Duration: time.Since(start),
+	}
+	for i, item := range items {
+		record.Items[i] = AuditItem{Title: item.Title(), URL: item.URL()}
+	}
+	if result != nil {
+		record.Status = result.Status
+		record.SuccessCount = result.SuccessCount
+		record.Error = result.Error
+	}
+	if sendErr != nil {
+		record.Status = StatusFailed
+		record.Error = sendErr.Error()
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		record.TraceID = spanContext.TraceID().String()
+	}
+
+	if err := audit.Record(ctx, record); err != nil {
+		logger.Warn("写入审计日志失败", logging.KeyChannel, n.Name, logging.KeyError, err)
+	}
+}
+
+// JSONLAuditLog 以JSON Lines格式追加写入文件的审计日志，每条记录一行，可以安全地并发使用
+type JSONLAuditLog struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// NewJSONLAuditLog 打开或创建审计日志文件，新记录追加到文件末尾
+func NewJSONLAuditLog(path string) (*JSONLAuditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	return &JSONLAuditLog{path: path, file: file}, nil
+}
+
+// Record 追加一条记录
+func (l *JSONLAuditLog) Record(ctx context.Context, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("序列化审计记录失败: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return errors.New("审计日志已关闭")
+	}
+	if _, err := l.file.Write(data); err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// Query 读取文件中满足条件的记录，无法解析的行会被跳过
+func (l *JSONLAuditLog) Query(ctx context.Context, query AuditQuery) ([]AuditRecord, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	defer file.Close()
+
+	return readAuditRecords(ctx, file, query)
+}
+
+// Close 关闭审计日志文件
+func (l *JSONLAuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// readAuditRecords 从JSON Lines格式的r中读取满足条件的记录，超过 Limit 时保留最新的记录
+func readAuditRecords(ctx context.Context, r io.Reader, query AuditQuery) ([]AuditRecord, error) {
+	var records []AuditRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || !query.Match(record) {
+			continue
+		}
+		records = append(records, record)
+		if query.Limit > 0 && len(records) > 2*query.Limit {
+			records = append(records[:0], records[len(records)-query.Limit:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取审计日志失败: %w", err)
+	}
+
+	if query.Limit > 0 && len(records) > query.Limit {
+		records = records[len(records)-query.Limit:]
+	}
+	return records, nil
+}
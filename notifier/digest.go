@@ -0,0 +1,212 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/logging"
+)
+
+// DigestOptions 摘要模式的配置
+type DigestOptions struct {
+	// Interval 定时合并发送的间隔，不大于0时不定时发送
+	Interval time.Duration
+	// MaxItems 累积的消息达到该数量时立即发送，不大于0时不按数量发送
+	MaxItems int
+}
+
+// FlushHook 摘要合并发送后的回调，items为实际发送的消息，result和err为底层通知器的发送结果
+type FlushHook func(ctx context.Context, items []MessageItem, start time.Time, result *NotificationResult, err error)
+
+// FlushReporter 由延迟发送消息的通知器实现，通过回调报告实际发送的结果
+// NotifierManager 注册此类通知器时会设置回调，将实际发送写入审计日志
+type FlushReporter interface {
+	SetFlushHook(hook FlushHook)
+}
+
+// Digest 摘要模式的通知器，将多次 Send 的消息累积起来，按时间或数量合并后发送到底层通知器，减少通知打扰
+// Interval 和 MaxItems 都不大于0时只在调用 Flush 或 Close 时发送；合并发送失败的消息不会重试
+type Digest struct {
+	notifier Notifier
+	options  DigestOptions
+
+	mu      sync.Mutex
+	pending []MessageItem
+	closed  bool
+	logger  logging.Logger
+	// grouping 最近一次 Send 的上下文携带的分组方式，合并发送时沿用
+	grouping GroupBy
+	// title 最近一次 Send 的上下文携带的标题模板，合并发送时沿用
+	title *TitleTemplate
+	// flushHook 每次发送到底层通知器后调用，为nil时不调用
+	flushHook FlushHook
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewDigest 创建包装了底层通知器的摘要通知器，配置了 Interval 时启动定时发送
+// 不再使用时需要调用 Close 停止定时发送并发送剩余的消息
+func NewDigest(notifier Notifier, options DigestOptions) *Digest {
+	d := &Digest{
+		notifier: notifier,
+		options:  options,
+		logger:   logging.Nop(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	if options.Interval > 0 {
+		go d.run()
+	} else {
+		close(d.done)
+	}
+	return d
+}
+
+// Name 返回底层通知器的名称
+func (d *Digest) Name() string {
+	return d.notifier.Name()
+}
+
+// IsEnabled 检查底层通知器是否启用
+func (d *Digest) IsEnabled() bool {
+	return d.notifier.IsEnabled()
+}
+
+// HealthCheck 检查底层通知器的健康状态
+func (d *Digest) HealthCheck(ctx context.Context) error {
+	return CheckHealth(ctx, d.notifier)
+}
+
+// SetLogger 设置日志记录器，同时注入到支持日志的底层通知器
+func (d *Digest) SetLogger(logger logging.Logger) {
+	d.mu.Lock()
+	d.logger = logging.OrNop(logger)
+	d.mu.Unlock()
+
+	if setter, ok := d.notifier.(LoggerSetter); ok {
+		setter.SetLogger(logger)
+	}
+}
+
+// SetFlushHook 设置合并发送后的回调，关闭后直接发送的消息同样会回调
+func (d *Digest) SetFlushHook(hook FlushHook) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.flushHook = hook
+}
+
+// Send 累积消息，达到 MaxItems 时立即合并发送并返回发送结果，否则返回 StatusPending 的结果
+// 关闭后的 Send 直接发送到底层通知器
+func (d *Digest) Send(ctx context.Context, items []MessageItem) (*NotificationResult, error) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return d.deliver(ctx, items)
+	}
+
+	d.pending = append(d.pending, items...)
+	if by := GroupingFromContext(ctx); by != GroupNone {
+		d.grouping = by
+	}
+	if tmpl := titleTemplateFromContext(ctx); tmpl != nil {
+		d.title = tmpl
+	}
+	full := d.options.MaxItems > 0 && len(d.pending) >= d.options.MaxItems
+	d.mu.Unlock()
+
+	if full {
+		return d.Flush(ctx)
+	}
+
+	now := time.Now()
+	return &NotificationResult{
+		Channel:    d.Name(),
+		Status:     StatusPending,
+		TotalCount: len(items),
+		StartAt:    now,
+		EndAt:      now,
+	}, nil
+}
+
+// Pending 返回尚未发送的消息数量
+func (d *Digest) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pending)
+}
+
+// Flush 立即合并发送累积的消息，没有累积的消息时返回nil
+// 累积消息时的上下文携带了分组方式或标题模板的，合并后的消息按该方式分组、用该模板渲染标题
+func (d *Digest) Flush(ctx context.Context) (*NotificationResult, error) {
+	d.mu.Lock()
+	items := d.pending
+	d.pending = nil
+	grouping := d.grouping
+	title := d.title
+	d.mu.Unlock()
+
+	if len(items) == 0 {
+		return nil, nil
+	}
+	if titleTemplateFromContext(ctx) == nil {
+		ctx = WithTitleTemplate(ctx, title)
+	}
+	ctx, items = applyGrouping(ctx, grouping, items)
+	return d.deliver(ctx, items)
+}
+
+// deliver 发送到底层通知器，并将结果报告给回调
+func (d *Digest) deliver(ctx context.Context, items []MessageItem) (*NotificationResult, error) {
+	d.mu.Lock()
+	hook := d.flushHook
+	d.mu.Unlock()
+
+	start := time.Now()
+	result, err := d.notifier.Send(ctx, items)
+	if hook != nil {
+		hook(ctx, items, start, result, err)
+	}
+	return result, err
+}
+
+// Close 停止定时发送，并发送剩余的消息
+func (d *Digest) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	d.mu.Unlock()
+
+	close(d.stop)
+	<-d.done
+
+	_, err := d.Flush(context.Background())
+	return err
+}
+
+// run 定时合并发送累积的消息
+func (d *Digest) run() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			if _, err := d.Flush(context.Background()); err != nil {
+				d.mu.Lock()
+				logger := d.logger
+				d.mu.Unlock()
+				logger.Error("摘要通知发送失败", logging.KeyError, err)
+			}
+		}
+	}
+}
@@ -18,6 +18,9 @@ import (
 	"github.com/sjzsdu/utils/notifier"
 )
 
+// MaxMessageBytes 钉钉单条消息正文的最大字节数，超过时接口会拒绝消息
+const MaxMessageBytes = 20000
+
 // DingtalkNotifierConfig 钉钉通知器配置
 type DingtalkNotifierConfig struct {
 	Enabled     bool   `yaml:"enabled" json:"enabled"`
@@ -25,6 +28,7 @@ type DingtalkNotifierConfig struct {
 	Secret      string `yaml:"secret,omitempty" json:"secret,omitempty"`
 	Proxy       string `yaml:"proxy,omitempty" json:"proxy,omitempty"`
 	MessageType string `yaml:"message_type" json:"message_type"`
+	Locale      string `yaml:"locale,omitempty" json:"locale,omitempty"`
 }
 
 // IsEnabled 检查是否启用
@@ -88,6 +92,11 @@ func (n *DingtalkNotifier) IsEnabled() bool {
 	return n.config.IsEnabled()
 }
 
+// locale 返回通知内容使用的语言
+func (n *DingtalkNotifier) locale() notifier.Locale {
+	return notifier.Locale(n.config.Locale)
+}
+
 // Send 发送通知
 func (n *DingtalkNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
 	result := &notifier.NotificationResult{
@@ -103,8 +112,29 @@ func (n *DingtalkNotifier) Send(ctx context.Context, items []notifier.MessageIte
 		return result, nil
 	}
 
+	// 按消息大小拆分批次，依次发送
+	batches := notifier.SplitBatches(items, 0, MaxMessageBytes, func(batch []notifier.MessageItem) int {
+		return len(n.formatContent(notifier.FormatTitle(ctx, n.locale(), batch), batch))
+	})
+	for _, batch := range batches {
+		if err := n.sendBatch(ctx, batch); err != nil {
+			result.Status = notifier.StatusFailed
+			result.Error = err.Error()
+			result.EndAt = time.Now()
+			return result, err
+		}
+		result.SuccessCount += len(batch)
+	}
+
+	result.Status = notifier.StatusSuccess
+	result.EndAt = time.Now()
+	return result, nil
+}
+
+// sendBatch 将一批消息格式化为一条钉钉消息发送
+func (n *DingtalkNotifier) sendBatch(ctx context.Context, items []notifier.MessageItem) error {
 	// 格式化标题
-	title := notifier.FormatNotificationTitle(items)
+	title := notifier.FormatTitle(ctx, n.locale(), items)
 	var messageBody string
 	var err error
 
@@ -115,67 +145,46 @@ func (n *DingtalkNotifier) Send(ctx context.Context, items []notifier.MessageIte
 	default:
 		messageBody, err = n.formatTextMessage(title, items)
 	}
-
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = err.Error()
-		result.EndAt = time.Now()
-		return result, err
+		return err
 	}
 
 	// 构建请求URL
 	requestURL, err := n.buildRequestURL()
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = err.Error()
-		result.EndAt = time.Now()
-		return result, err
+		return err
 	}
 
 	// 创建请求
 	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewBufferString(messageBody))
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = fmt.Errorf("创建请求失败: %w", err).Error()
-		result.EndAt = time.Now()
-		return result, err
+		return fmt.Errorf("创建请求失败: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	// 发送请求
+	notifier.InjectTraceContext(ctx, req.Header)
 	resp, err := n.client.Do(req)
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = fmt.Errorf("发送请求失败: %w", err).Error()
-		result.EndAt = time.Now()
-		return result, err
+		return fmt.Errorf("发送请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = fmt.Errorf("读取响应失败: %w", err).Error()
-		result.EndAt = time.Now()
-		return result, err
+		return fmt.Errorf("读取响应失败: %w", err)
 	}
 
 	// 检查响应状态
 	if resp.StatusCode != http.StatusOK {
-		result.Status = notifier.StatusFailed
-		result.Error = fmt.Errorf("请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody)).Error()
-		result.EndAt = time.Now()
-		return result, err
+		return fmt.Errorf("请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
 	}
 
 	// 解析响应
 	var respData map[string]interface{}
 	if err := json.Unmarshal(respBody, &respData); err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = fmt.Errorf("解析响应失败: %w", err).Error()
-		result.EndAt = time.Now()
-		return result, err
+		return fmt.Errorf("解析响应失败: %w", err)
 	}
 
 	// 检查错误码
@@ -184,35 +193,42 @@ func (n *DingtalkNotifier) Send(ctx context.Context, items []notifier.MessageIte
 		if message, ok := respData["errmsg"].(string); ok {
 			msg = message
 		}
-		result.Status = notifier.StatusFailed
-		result.Error = fmt.Errorf("钉钉返回错误: %s (errcode: %.0f)", msg, errcode).Error()
-		result.EndAt = time.Now()
-		return result, err
+		return fmt.Errorf("钉钉返回错误: %s (errcode: %.0f)", msg, errcode)
 	}
 
-	result.Status = notifier.StatusSuccess
-	result.SuccessCount = len(items)
-	result.EndAt = time.Now()
-	return result, nil
+	return nil
 }
 
-// formatTextMessage 格式化文本消息
-func (n *DingtalkNotifier) formatTextMessage(title string, items []notifier.MessageItem) (string, error) {
+// formatContent 根据消息类型格式化消息正文
+func (n *DingtalkNotifier) formatContent(title string, items []notifier.MessageItem) string {
+	if n.config.MessageType == "markdown" {
+		return n.markdownContent(title, items)
+	}
+	return n.textContent(title, items)
+}
+
+// textContent 格式化文本消息的正文
+func (n *DingtalkNotifier) textContent(title string, items []notifier.MessageItem) string {
 	var content strings.Builder
 	content.WriteString(title)
 	content.WriteString("\n\n")
 
 	for i, item := range items {
 		content.WriteString(fmt.Sprintf("%d. %s\n", i+1, item.Title()))
-		content.WriteString(fmt.Sprintf("   链接: %s\n", item.URL()))
-		content.WriteString(fmt.Sprintf("   内容: %s\n", item.Content()))
+		content.WriteString(fmt.Sprintf("   %s: %s\n", n.locale().T(notifier.MsgLink), item.URL()))
+		content.WriteString(fmt.Sprintf("   %s: %s\n", n.locale().T(notifier.MsgContent), item.Content()))
 		content.WriteString("\n")
 	}
 
+	return content.String()
+}
+
+// formatTextMessage 格式化文本消息，正文超过大小限制时截断
+func (n *DingtalkNotifier) formatTextMessage(title string, items []notifier.MessageItem) (string, error) {
 	msg := DingtalkMessage{
 		Msgtype: "text",
 	}
-	msg.Text.Content = content.String()
+	msg.Text.Content = notifier.TruncateBytes(n.textContent(title, items), MaxMessageBytes)
 
 	jsonData, err := json.Marshal(msg)
 	if err != nil {
@@ -222,22 +238,27 @@ func (n *DingtalkNotifier) formatTextMessage(title string, items []notifier.Mess
 	return string(jsonData), nil
 }
 
-// formatMarkdownMessage 格式化Markdown消息
-func (n *DingtalkNotifier) formatMarkdownMessage(title string, items []notifier.MessageItem) (string, error) {
+// markdownContent 格式化Markdown消息的正文
+func (n *DingtalkNotifier) markdownContent(title string, items []notifier.MessageItem) string {
 	var content strings.Builder
 	content.WriteString(fmt.Sprintf("# %s\n\n", title))
 
 	for i, item := range items {
 		content.WriteString(fmt.Sprintf("## %d. [%s](%s)\n", i+1, item.Title(), item.URL()))
-		content.WriteString(fmt.Sprintf("- **内容**: %s\n", item.Content()))
+		content.WriteString(fmt.Sprintf("- **%s**: %s\n", n.locale().T(notifier.MsgContent), item.Content()))
 		content.WriteString("\n")
 	}
 
+	return content.String()
+}
+
+// formatMarkdownMessage 格式化Markdown消息，正文超过大小限制时截断
+func (n *DingtalkNotifier) formatMarkdownMessage(title string, items []notifier.MessageItem) (string, error) {
 	msg := DingtalkMessage{
 		Msgtype: "markdown",
 	}
 	msg.Markdown.Title = title
-	msg.Markdown.Text = content.String()
+	msg.Markdown.Text = notifier.TruncateBytes(n.markdownContent(title, items), MaxMessageBytes)
 
 	jsonData, err := json.Marshal(msg)
 	if err != nil {
@@ -1,7 +1,12 @@
 package dingtalk
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/notifier/notifiertest"
 )
 
 // MockMessageItem 用于测试的模拟消息项
@@ -62,3 +67,69 @@ func TestNewNotifier(t *testing.T) {
 	}
 
 }
+
+// 测试按消息大小拆分批次
+func TestSendSplitsBySize(t *testing.T) {
+	server := notifiertest.NewServer(t)
+	n, err := NewNotifier(&DingtalkNotifierConfig{
+		Enabled:     true,
+		WebhookURL:  server.URL(),
+		MessageType: "markdown",
+	})
+	if err != nil {
+		t.Fatalf("创建钉钉通知器失败: %v", err)
+	}
+
+	var items []notifier.MessageItem
+	for _, title := range []string{"a", "b", "c", "d"} {
+		items = append(items, notifiertest.NewItem(title, "https://example.com/"+title, strings.Repeat("长", 3000)))
+	}
+	items = append(items, notifiertest.NewItem("e", "https://example.com/e", strings.Repeat("长", 10000)))
+
+	result, err := n.Send(t.Context(), items)
+	if err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	notifiertest.AssertStatus(t, result, notifier.StatusSuccess)
+	if result.SuccessCount != len(items) {
+		t.Errorf("期望成功发送 %d 条，实际为 %d", len(items), result.SuccessCount)
+	}
+	// 每条约9000字节，两条一批；超长的最后一条单独成批并被截断
+	notifiertest.AssertRequests(t, server, 3)
+
+	for i, req := range server.Requests() {
+		var msg DingtalkMessage
+		if err := json.Unmarshal(req.Body, &msg); err != nil {
+			t.Fatalf("解析第 %d 个请求失败: %v", i+1, err)
+		}
+		if len(msg.Markdown.Text) > MaxMessageBytes {
+			t.Errorf("第 %d 个请求正文为 %d 字节，超过限制 %d", i+1, len(msg.Markdown.Text), MaxMessageBytes)
+		}
+	}
+}
+
+// 测试拆分批次和截断
+func TestSplitBatches(t *testing.T) {
+	items := notifiertest.Items("a", "b", "c", "d", "e")
+	measure := func(batch []notifier.MessageItem) int { return len(batch) * 10 }
+
+	batches := notifier.SplitBatches(items, 2, 25, measure)
+	if len(batches) != 3 || len(batches[0]) != 2 || len(batches[2]) != 1 {
+		t.Errorf("按条数拆分的批次不正确: %v", batches)
+	}
+	batches = notifier.SplitBatches(items, 0, 35, measure)
+	if len(batches) != 2 || len(batches[0]) != 3 {
+		t.Errorf("按大小拆分的批次不正确: %v", batches)
+	}
+	batches = notifier.SplitBatches(items, 0, 5, measure)
+	if len(batches) != len(items) {
+		t.Errorf("超过限制的单条消息应该单独成批，实际为 %d 批", len(batches))
+	}
+
+	if got := notifier.TruncateBytes("你好世界", 8); got != "你..." {
+		t.Errorf("按字节截断结果不正确: %q", got)
+	}
+	if got := notifier.TruncateUTF16("😀😀😀😀", 7); got != "😀😀..." {
+		t.Errorf("按UTF-16截断结果不正确: %q", got)
+	}
+}
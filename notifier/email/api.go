@@ -0,0 +1,255 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sjzsdu/utils/notifier"
+)
+
+// 各服务商API的默认地址
+const (
+	sendGridEndpoint  = "https://api.sendgrid.com"
+	mailgunEndpoint   = "https://api.mailgun.net"
+	mailgunEUEndpoint = "https://api.eu.mailgun.net"
+)
+
+// endpoint 返回配置的API地址，未配置时返回defaultURL
+func (n *EmailNotifier) endpoint(defaultURL string) string {
+	if n.config.Endpoint != "" {
+		return strings.TrimRight(n.config.Endpoint, "/")
+	}
+	return defaultURL
+}
+
+// sendGridAddress SendGrid的邮箱地址
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+// sendGridAddresses 将邮箱地址列表转换为SendGrid的格式
+func sendGridAddresses(addresses []string) []sendGridAddress {
+	result := make([]sendGridAddress, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, sendGridAddress{Email: address})
+	}
+	return result
+}
+
+// sendSendGrid 通过SendGrid v3 API发送邮件
+func (n *EmailNotifier) sendSendGrid(ctx context.Context, msg message) error {
+	contentType := "text/plain"
+	if msg.HTML {
+		contentType = "text/html"
+	}
+
+	type personalization struct {
+		To  []sendGridAddress `json:"to"`
+		CC  []sendGridAddress `json:"cc,omitempty"`
+		BCC []sendGridAddress `json:"bcc,omitempty"`
+	}
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	body, err := json.Marshal(struct {
+		Personalizations []personalization `json:"personalizations"`
+		From             sendGridAddress   `json:"from"`
+		Subject          string            `json:"subject"`
+		Content          []content         `json:"content"`
+	}{
+		Personalizations: []personalization{{
+			To:  sendGridAddresses(n.config.To),
+			CC:  sendGridAddresses(n.config.CC),
+			BCC: sendGridAddresses(n.config.BCC),
+		}},
+		From:    sendGridAddress{Email: n.config.From},
+		Subject: msg.Subject,
+		Content: []content{{Type: contentType, Value: msg.Body}},
+	})
+	if err != nil {
+		return fmt.Errorf("JSON序列化失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint(sendGridEndpoint)+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.config.APIKey)
+
+	return n.do(ctx, req)
+}
+
+// sendMailgun 通过Mailgun API发送邮件
+func (n *EmailNotifier) sendMailgun(ctx context.Context, msg message) error {
+	form := url.Values{}
+	form.Set("from", n.config.From)
+	for _, to := range n.config.To {
+		form.Add("to", to)
+	}
+	for _, cc := range n.config.CC {
+		form.Add("cc", cc)
+	}
+	for _, bcc := range n.config.BCC {
+		form.Add("bcc", bcc)
+	}
+	form.Set("subject", msg.Subject)
+	if msg.HTML {
+		form.Set("html", msg.Body)
+	} else {
+		form.Set("text", msg.Body)
+	}
+
+	defaultURL := mailgunEndpoint
+	if strings.EqualFold(n.config.Region, "eu") {
+		defaultURL = mailgunEUEndpoint
+	}
+	apiURL := fmt.Sprintf("%s/v3/%s/messages", n.endpoint(defaultURL), url.PathEscape(n.config.Domain))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("api", n.config.APIKey)
+
+	return n.do(ctx, req)
+}
+
+// sesContent SES邮件内容的字段
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+// sendSES 通过Amazon SES v2 API发送邮件，请求使用AWS Signature Version 4签名
+func (n *EmailNotifier) sendSES(ctx context.Context, msg message) error {
+	bodyContent := map[string]sesContent{}
+	if msg.HTML {
+		bodyContent["Html"] = sesContent{Data: msg.Body, Charset: "UTF-8"}
+	} else {
+		bodyContent["Text"] = sesContent{Data: msg.Body, Charset: "UTF-8"}
+	}
+
+	type destination struct {
+		ToAddresses  []string `json:"ToAddresses"`
+		CcAddresses  []string `json:"CcAddresses,omitempty"`
+		BccAddresses []string `json:"BccAddresses,omitempty"`
+	}
+	type simple struct {
+		Subject sesContent            `json:"Subject"`
+		Body    map[string]sesContent `json:"Body"`
+	}
+	body, err := json.Marshal(struct {
+		FromEmailAddress string            `json:"FromEmailAddress"`
+		Destination      destination       `json:"Destination"`
+		Content          map[string]simple `json:"Content"`
+	}{
+		FromEmailAddress: n.config.From,
+		Destination: destination{
+			ToAddresses:  n.config.To,
+			CcAddresses:  n.config.CC,
+			BccAddresses: n.config.BCC,
+		},
+		Content: map[string]simple{
+			"Simple": {
+				Subject: sesContent{Data: msg.Subject, Charset: "UTF-8"},
+				Body:    bodyContent,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("JSON序列化失败: %w", err)
+	}
+
+	defaultURL := fmt.Sprintf("https://email.%s.amazonaws.com", n.config.Region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint(defaultURL)+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	signAWSRequest(req, body, n.config.AccessKey, n.config.SecretKey, n.config.Region, "ses", time.Now())
+
+	return n.do(ctx, req)
+}
+
+// do 发送API请求并检查响应状态码
+func (n *EmailNotifier) do(ctx context.Context, req *http.Request) error {
+	notifier.InjectTraceContext(ctx, req.Header)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s 请求失败，状态码: %d, 响应: %s", n.config.Provider, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// signAWSRequest 按AWS Signature Version 4为请求添加签名头
+func signAWSRequest(req *http.Request, body []byte, accessKey, secretKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// sha256Hex 返回数据的SHA256十六进制摘要
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 计算HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
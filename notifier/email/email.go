@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/mail"
 	"net/smtp"
 	"strings"
@@ -12,9 +13,19 @@ import (
 	"github.com/sjzsdu/utils/notifier"
 )
 
+// 支持的邮件服务商
+const (
+	ProviderSMTP     = "smtp"
+	ProviderSendGrid = "sendgrid"
+	ProviderMailgun  = "mailgun"
+	ProviderSES      = "ses"
+)
+
 // EmailNotifierConfig 邮件通知器配置
+// Provider 为空或 smtp 时通过SMTP发送；无法访问外部SMTP端口的环境可以使用 sendgrid、mailgun 或 ses 的HTTP API
 type EmailNotifierConfig struct {
 	Enabled     bool     `yaml:"enabled" json:"enabled"`
+	Provider    string   `yaml:"provider,omitempty" json:"provider,omitempty"` // smtp, sendgrid, mailgun, ses
 	SMTPHost    string   `yaml:"smtp_host" json:"smtp_host"`
 	SMTPPort    int      `yaml:"smtp_port" json:"smtp_port"`
 	Username    string   `yaml:"username" json:"username"`
@@ -26,16 +37,46 @@ type EmailNotifierConfig struct {
 	UseTLS      bool     `yaml:"use_tls" json:"use_tls"`
 	UseSSL      bool     `yaml:"use_ssl" json:"use_ssl"`
 	MessageType string   `yaml:"message_type" json:"message_type"`
+	Locale      string   `yaml:"locale,omitempty" json:"locale,omitempty"`
+
+	APIKey    string `yaml:"api_key,omitempty" json:"api_key,omitempty"`       // SendGrid、Mailgun的API密钥
+	Domain    string `yaml:"domain,omitempty" json:"domain,omitempty"`         // Mailgun的发信域名
+	AccessKey string `yaml:"access_key,omitempty" json:"access_key,omitempty"` // SES的AccessKey ID
+	SecretKey string `yaml:"secret_key,omitempty" json:"secret_key,omitempty"` // SES的Secret Access Key
+	Region    string `yaml:"region,omitempty" json:"region,omitempty"`         // SES的区域；Mailgun为 eu 时使用欧洲区域
+	Endpoint  string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`     // API地址，为空时使用服务商的默认地址
 }
 
 // IsEnabled 检查是否启用
 func (c *EmailNotifierConfig) IsEnabled() bool {
-	return c.Enabled && c.SMTPHost != "" && c.Username != "" && c.Password != "" && len(c.To) > 0
+	if !c.Enabled || len(c.To) == 0 {
+		return false
+	}
+	switch c.Provider {
+	case "", ProviderSMTP:
+		return c.SMTPHost != "" && c.Username != "" && c.Password != ""
+	case ProviderSendGrid:
+		return c.APIKey != ""
+	case ProviderMailgun:
+		return c.APIKey != "" && c.Domain != ""
+	case ProviderSES:
+		return c.AccessKey != "" && c.SecretKey != "" && c.Region != ""
+	default:
+		return false
+	}
 }
 
 // EmailNotifier 邮件通知器
 type EmailNotifier struct {
 	config *EmailNotifierConfig
+	client *http.Client
+}
+
+// message 格式化后的邮件，各服务商使用相同的格式化结果
+type message struct {
+	Subject string
+	Body    string
+	HTML    bool
 }
 
 // NewNotifier 创建邮件通知器
@@ -57,6 +98,7 @@ func NewNotifier(cfg *EmailNotifierConfig) (*EmailNotifier, error) {
 
 	return &EmailNotifier{
 		config: cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
 	}, nil
 }
 
@@ -70,6 +112,11 @@ func (n *EmailNotifier) IsEnabled() bool {
 	return n.config.IsEnabled()
 }
 
+// locale 返回通知内容使用的语言
+func (n *EmailNotifier) locale() notifier.Locale {
+	return notifier.Locale(n.config.Locale)
+}
+
 // Send 发送通知
 func (n *EmailNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
 	result := &notifier.NotificationResult{
@@ -86,16 +133,17 @@ func (n *EmailNotifier) Send(ctx context.Context, items []notifier.MessageItem)
 	}
 
 	// 格式化标题
-	title := notifier.FormatNotificationTitle(items)
-	var messageBody string
+	msg := message{Subject: notifier.FormatTitle(ctx, n.locale(), items)}
+	groups := notifier.FormatGroups(ctx, items)
 	var err error
 
 	// 根据消息类型格式化内容
 	switch n.config.MessageType {
 	case "html":
-		messageBody, err = n.formatHTMLMessage(title, items)
+		msg.Body, err = n.formatHTMLMessage(msg.Subject, items, groups)
+		msg.HTML = true
 	default:
-		messageBody, err = n.formatTextMessage(title, items)
+		msg.Body, err = n.formatTextMessage(items, groups)
 	}
 
 	if err != nil {
@@ -106,7 +154,7 @@ func (n *EmailNotifier) Send(ctx context.Context, items []notifier.MessageItem)
 	}
 
 	// 发送邮件
-	if err := n.sendEmail(messageBody); err != nil {
+	if err := n.sendEmail(ctx, msg); err != nil {
 		result.Status = notifier.StatusFailed
 		result.Error = err.Error()
 		result.EndAt = time.Now()
@@ -119,58 +167,54 @@ func (n *EmailNotifier) Send(ctx context.Context, items []notifier.MessageItem)
 	return result, nil
 }
 
-// formatTextMessage 格式化文本消息
-func (n *EmailNotifier) formatTextMessage(title string, items []notifier.MessageItem) (string, error) {
+// formatTextMessage 格式化文本消息，groups 不为nil时每组前添加分组标题，序号在各组间连续
+func (n *EmailNotifier) formatTextMessage(items []notifier.MessageItem, groups []notifier.ItemGroup) (string, error) {
 	var content strings.Builder
-	content.WriteString(fmt.Sprintf("Subject: %s\n", title))
-	content.WriteString(fmt.Sprintf("From: %s\n", n.config.From))
-	content.WriteString(fmt.Sprintf("To: %s\n", strings.Join(n.config.To, ", ")))
-	if len(n.config.CC) > 0 {
-		content.WriteString(fmt.Sprintf("CC: %s\n", strings.Join(n.config.CC, ", ")))
-	}
-	content.WriteString("Content-Type: text/plain; charset=utf-8\n")
-	content.WriteString("\n")
-
-	content.WriteString(notifier.FormatNotificationSummary(items))
+	content.WriteString(n.locale().FormatSummary(items))
 	content.WriteString("\n\n")
 
-	for i, item := range items {
-		content.WriteString(fmt.Sprintf("%d. %s\n", i+1, item.Title()))
-		content.WriteString(fmt.Sprintf("   链接: %s\n", item.URL()))
-		content.WriteString(fmt.Sprintf("   内容: %s\n", item.Content()))
-		content.WriteString("\n")
+	index := 0
+	for _, group := range sections(items, groups) {
+		if groups != nil {
+			content.WriteString(fmt.Sprintf("== %s ==\n\n", group.Header(n.locale())))
+		}
+		for _, item := range group.Items {
+			index++
+			content.WriteString(fmt.Sprintf("%d. %s\n", index, item.Title()))
+			content.WriteString(fmt.Sprintf("   %s: %s\n", n.locale().T(notifier.MsgLink), item.URL()))
+			content.WriteString(fmt.Sprintf("   %s: %s\n", n.locale().T(notifier.MsgContent), item.Content()))
+			content.WriteString("\n")
+		}
 	}
 
 	return content.String(), nil
 }
 
-// formatHTMLMessage 格式化HTML消息
-func (n *EmailNotifier) formatHTMLMessage(title string, items []notifier.MessageItem) (string, error) {
+// formatHTMLMessage 格式化HTML消息，groups 不为nil时每组前添加一行分组标题，序号在各组间连续
+func (n *EmailNotifier) formatHTMLMessage(title string, items []notifier.MessageItem, groups []notifier.ItemGroup) (string, error) {
 	var content strings.Builder
-	content.WriteString(fmt.Sprintf("Subject: %s\n", title))
-	content.WriteString(fmt.Sprintf("From: %s\n", n.config.From))
-	content.WriteString(fmt.Sprintf("To: %s\n", strings.Join(n.config.To, ", ")))
-	if len(n.config.CC) > 0 {
-		content.WriteString(fmt.Sprintf("CC: %s\n", strings.Join(n.config.CC, ", ")))
-	}
-	content.WriteString("Content-Type: text/html; charset=utf-8\n")
-	content.WriteString("\n")
-
 	content.WriteString("<html><body>")
 	content.WriteString(fmt.Sprintf("<h1>%s</h1>", title))
-	content.WriteString(fmt.Sprintf("<p>%s</p>", notifier.FormatNotificationSummary(items)))
+	content.WriteString(fmt.Sprintf("<p>%s</p>", n.locale().FormatSummary(items)))
 
 	content.WriteString("<table border='1' cellpadding='5' cellspacing='0' style='border-collapse: collapse; width: 100%;'>")
 	content.WriteString("<tr style='background-color: #f2f2f2;'>")
-	content.WriteString("<th>序号</th><th>标题</th><th>内容</th>")
+	content.WriteString(fmt.Sprintf("<th>%s</th><th>%s</th><th>%s</th>", n.locale().T(notifier.MsgIndex), n.locale().T(notifier.MsgItemTitle), n.locale().T(notifier.MsgContent)))
 	content.WriteString("</tr>")
 
-	for i, item := range items {
-		content.WriteString("<tr>")
-		content.WriteString(fmt.Sprintf("<td>%d</td>", i+1))
-		content.WriteString(fmt.Sprintf("<td><a href='%s'>%s</a></td>", item.URL(), item.Title()))
-		content.WriteString(fmt.Sprintf("<td>%s</td>", item.Content()))
-		content.WriteString("</tr>")
+	index := 0
+	for _, group := range sections(items, groups) {
+		if groups != nil {
+			content.WriteString(fmt.Sprintf("<tr style='background-color: #fafafa;'><th colspan='3' style='text-align: left;'>%s</th></tr>", group.Header(n.locale())))
+		}
+		for _, item := range group.Items {
+			index++
+			content.WriteString("<tr>")
+			content.WriteString(fmt.Sprintf("<td>%d</td>", index))
+			content.WriteString(fmt.Sprintf("<td><a href='%s'>%s</a></td>", item.URL(), item.Title()))
+			content.WriteString(fmt.Sprintf("<td>%s</td>", item.Content()))
+			content.WriteString("</tr>")
+		}
 	}
 
 	content.WriteString("</table>")
@@ -179,8 +223,51 @@ func (n *EmailNotifier) formatHTMLMessage(title string, items []notifier.Message
 	return content.String(), nil
 }
 
-// sendEmail 发送邮件
-func (n *EmailNotifier) sendEmail(emailContent string) error {
+// sections 返回正文中依次列出的分组，groups 为nil即不分组时所有消息为一组
+func sections(items []notifier.MessageItem, groups []notifier.ItemGroup) []notifier.ItemGroup {
+	if groups == nil {
+		return []notifier.ItemGroup{{Items: items}}
+	}
+	return groups
+}
+
+// sendEmail 按配置的服务商发送邮件
+func (n *EmailNotifier) sendEmail(ctx context.Context, msg message) error {
+	switch n.config.Provider {
+	case "", ProviderSMTP:
+		return n.sendSMTP(msg)
+	case ProviderSendGrid:
+		return n.sendSendGrid(ctx, msg)
+	case ProviderMailgun:
+		return n.sendMailgun(ctx, msg)
+	case ProviderSES:
+		return n.sendSES(ctx, msg)
+	default:
+		return fmt.Errorf("不支持的邮件服务商: %s", n.config.Provider)
+	}
+}
+
+// mimeMessage 构建SMTP发送的邮件内容
+func (n *EmailNotifier) mimeMessage(msg message) string {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Subject: %s\n", msg.Subject))
+	content.WriteString(fmt.Sprintf("From: %s\n", n.config.From))
+	content.WriteString(fmt.Sprintf("To: %s\n", strings.Join(n.config.To, ", ")))
+	if len(n.config.CC) > 0 {
+		content.WriteString(fmt.Sprintf("CC: %s\n", strings.Join(n.config.CC, ", ")))
+	}
+	if msg.HTML {
+		content.WriteString("Content-Type: text/html; charset=utf-8\n")
+	} else {
+		content.WriteString("Content-Type: text/plain; charset=utf-8\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(msg.Body)
+	return content.String()
+}
+
+// sendSMTP 通过SMTP发送邮件
+func (n *EmailNotifier) sendSMTP(msg message) error {
 	// 准备收件人列表
 	recipients := make([]string, 0)
 	recipients = append(recipients, n.config.To...)
@@ -194,7 +281,7 @@ func (n *EmailNotifier) sendEmail(emailContent string) error {
 	auth := smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.SMTPHost)
 
 	// 发送邮件
-	return smtp.SendMail(smtpAddr, auth, n.config.From, recipients, []byte(emailContent))
+	return smtp.SendMail(smtpAddr, auth, n.config.From, recipients, []byte(n.mimeMessage(msg)))
 }
 
 // RegisterNotifier 注册邮件通知器
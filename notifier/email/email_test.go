@@ -1,7 +1,19 @@
 package email
 
 import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/sjzsdu/utils/notifier"
 )
 
 // MockMessageItem 用于测试的模拟消息项
@@ -123,3 +135,185 @@ func TestEmailNotifierConfig_IsEnabled(t *testing.T) {
 		t.Error("禁用的配置应该返回false")
 	}
 }
+
+// 测试通过HTTP API发送邮件
+func TestAPIProviders(t *testing.T) {
+	tests := []struct {
+		provider string
+		path     string
+		auth     string
+		body     string
+	}{
+		{ProviderSendGrid, "/v3/mail/send", "Bearer key", `"subject"`},
+		{ProviderMailgun, "/v3/mg.example.com/messages", "Basic ", "to=recipient@example.com"},
+		{ProviderSES, "/v2/email/outbound-emails", "AWS4-HMAC-SHA256 Credential=AKID/", `"FromEmailAddress":"test@example.com"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			var gotPath, gotAuth, gotBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotPath, gotAuth, gotBody = r.URL.Path, r.Header.Get("Authorization"), string(body)
+				if r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+					gotBody, _ = url.QueryUnescape(gotBody)
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer server.Close()
+
+			config := &EmailNotifierConfig{
+				Enabled:   true,
+				Provider:  tt.provider,
+				From:      "test@example.com",
+				To:        []string{"recipient@example.com"},
+				APIKey:    "key",
+				Domain:    "mg.example.com",
+				AccessKey: "AKID",
+				SecretKey: "secret",
+				Region:    "us-east-1",
+				Endpoint:  server.URL,
+			}
+			if !config.IsEnabled() {
+				t.Fatal("启用的配置应该返回true")
+			}
+			n, err := NewNotifier(config)
+			if err != nil {
+				t.Fatalf("创建邮件通知器失败: %v", err)
+			}
+
+			items := []notifier.MessageItem{&MockMessageItem{mockTitle: "Go 1.24", mockURL: "https://go.dev"}}
+			if _, err := n.Send(context.Background(), items); err != nil {
+				t.Fatalf("发送邮件失败: %v", err)
+			}
+			if gotPath != tt.path {
+				t.Errorf("期望请求路径 %s，实际为 %s", tt.path, gotPath)
+			}
+			if !strings.HasPrefix(gotAuth, tt.auth) {
+				t.Errorf("期望认证头以 %q 开头，实际为 %q", tt.auth, gotAuth)
+			}
+			if !strings.Contains(gotBody, tt.body) || !strings.Contains(gotBody, "Go 1.24") {
+				t.Errorf("请求体缺少预期内容: %s", gotBody)
+			}
+		})
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	var gotMethod, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	config := &EmailNotifierConfig{
+		Enabled:  true,
+		Provider: ProviderSendGrid,
+		From:     "test@example.com",
+		To:       []string{"recipient@example.com"},
+		APIKey:   "key",
+		Endpoint: server.URL,
+	}
+	n, err := NewNotifier(config)
+	if err != nil {
+		t.Fatalf("创建邮件通知器失败: %v", err)
+	}
+	if err := n.HealthCheck(context.Background()); err != nil {
+		t.Errorf("健康检查失败: %v", err)
+	}
+	if gotMethod != http.MethodGet || gotPath != "/v3/scopes" {
+		t.Errorf("期望请求 GET /v3/scopes，实际为 %s %s", gotMethod, gotPath)
+	}
+
+	config.APIKey = "wrong"
+	if err := n.HealthCheck(context.Background()); err == nil {
+		t.Error("API密钥无效时健康检查应该失败")
+	}
+}
+
+func TestHealthCheckSMTP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer listener.Close()
+
+	// 模拟只支持 AUTH PLAIN 的SMTP服务器，记录收到的命令
+	commands := make(chan string, 20)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, commands)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	config := &EmailNotifierConfig{
+		Enabled:  true,
+		SMTPHost: host,
+		SMTPPort: portNum,
+		Username: "user",
+		Password: "pass",
+		From:     "test@example.com",
+		To:       []string{"recipient@example.com"},
+	}
+	n, err := NewNotifier(config)
+	if err != nil {
+		t.Fatalf("创建邮件通知器失败: %v", err)
+	}
+	if err := n.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("健康检查失败: %v", err)
+	}
+	var got []string
+	for len(commands) > 0 {
+		got = append(got, <-commands)
+	}
+	if strings.Join(got, ",") != "EHLO,AUTH,NOOP,QUIT" {
+		t.Errorf("期望依次发送 EHLO、AUTH、NOOP、QUIT，实际为 %v", got)
+	}
+
+	config.Password = "wrong"
+	if err := n.HealthCheck(context.Background()); err == nil || !strings.Contains(err.Error(), "认证失败") {
+		t.Errorf("密码错误时应返回认证失败，实际为: %v", err)
+	}
+}
+
+// serveSMTP 处理一个SMTP连接，用户名和密码为 user 和 pass
+func serveSMTP(conn net.Conn, commands chan<- string) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		commands <- verb
+		switch verb {
+		case "EHLO":
+			tp.PrintfLine("250-localhost")
+			tp.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			if arg == "PLAIN "+base64.StdEncoding.EncodeToString([]byte("\x00user\x00pass")) {
+				tp.PrintfLine("235 Authentication succeeded")
+			} else {
+				tp.PrintfLine("535 Authentication failed")
+			}
+		case "NOOP":
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HealthCheck 检查邮件服务是否可用以及凭据是否有效，不会发送邮件
+// SMTP 连接服务器后按 smtp.SendMail 的方式协商TLS并认证，再发送NOOP；
+// HTTP API 服务商请求只读接口校验API密钥
+func (n *EmailNotifier) HealthCheck(ctx context.Context) error {
+	switch n.config.Provider {
+	case "", ProviderSMTP:
+		return n.checkSMTP(ctx)
+	case ProviderSendGrid:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.endpoint(sendGridEndpoint)+"/v3/scopes", nil)
+		if err != nil {
+			return fmt.Errorf("创建请求失败: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+n.config.APIKey)
+		return n.do(ctx, req)
+	case ProviderMailgun:
+		defaultURL := mailgunEndpoint
+		if strings.EqualFold(n.config.Region, "eu") {
+			defaultURL = mailgunEUEndpoint
+		}
+		apiURL := fmt.Sprintf("%s/v3/domains/%s", n.endpoint(defaultURL), url.PathEscape(n.config.Domain))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+		if err != nil {
+			return fmt.Errorf("创建请求失败: %w", err)
+		}
+		req.SetBasicAuth("api", n.config.APIKey)
+		return n.do(ctx, req)
+	case ProviderSES:
+		defaultURL := fmt.Sprintf("https://email.%s.amazonaws.com", n.config.Region)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.endpoint(defaultURL)+"/v2/email/account", nil)
+		if err != nil {
+			return fmt.Errorf("创建请求失败: %w", err)
+		}
+		signAWSRequest(req, nil, n.config.AccessKey, n.config.SecretKey, n.config.Region, "ses", time.Now())
+		return n.do(ctx, req)
+	default:
+		return fmt.Errorf("不支持的邮件服务商: %s", n.config.Provider)
+	}
+}
+
+// checkSMTP 连接SMTP服务器，服务器支持时升级到TLS并认证，然后发送NOOP
+func (n *EmailNotifier) checkSMTP(ctx context.Context) error {
+	addr := net.JoinHostPort(n.config.SMTPHost, strconv.Itoa(n.config.SMTPPort))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("连接SMTP服务器失败: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.config.SMTPHost)
+	if err != nil {
+		return fmt.Errorf("SMTP握手失败: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.SMTPHost}); err != nil {
+			return fmt.Errorf("SMTP TLS协商失败: %w", err)
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok {
+		auth := smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP认证失败: %w", err)
+		}
+	}
+	if err := client.Noop(); err != nil {
+		return fmt.Errorf("SMTP NOOP失败: %w", err)
+	}
+	return client.Quit()
+}
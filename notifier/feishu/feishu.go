@@ -25,6 +25,7 @@ type FeishuNotifierConfig struct {
 	Secret      string `yaml:"secret,omitempty" json:"secret,omitempty"`
 	Proxy       string `yaml:"proxy,omitempty" json:"proxy,omitempty"`
 	MessageType string `yaml:"message_type" json:"message_type"`
+	Locale      string `yaml:"locale,omitempty" json:"locale,omitempty"`
 }
 
 // IsEnabled 检查是否启用
@@ -94,6 +95,11 @@ func (n *FeishuNotifier) IsEnabled() bool {
 	return n.config.IsEnabled()
 }
 
+// locale 返回通知内容使用的语言
+func (n *FeishuNotifier) locale() notifier.Locale {
+	return notifier.Locale(n.config.Locale)
+}
+
 // Send 发送通知
 func (n *FeishuNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
 	result := &notifier.NotificationResult{
@@ -110,7 +116,7 @@ func (n *FeishuNotifier) Send(ctx context.Context, items []notifier.MessageItem)
 	}
 
 	// 格式化标题
-	title := notifier.FormatNotificationTitle(items)
+	title := notifier.FormatTitle(ctx, n.locale(), items)
 	var messageBody string
 	var err error
 
@@ -151,6 +157,7 @@ func (n *FeishuNotifier) Send(ctx context.Context, items []notifier.MessageItem)
 	req.Header.Set("Content-Type", "application/json")
 
 	// 发送请求
+	notifier.InjectTraceContext(ctx, req.Header)
 	resp, err := n.client.Do(req)
 	if err != nil {
 		result.Status = notifier.StatusFailed
@@ -212,8 +219,8 @@ func (n *FeishuNotifier) formatTextMessage(title string, items []notifier.Messag
 
 	for i, item := range items {
 		content.WriteString(fmt.Sprintf("%d. %s\n", i+1, item.Title()))
-		content.WriteString(fmt.Sprintf("   链接: %s\n", item.URL()))
-		content.WriteString(fmt.Sprintf("   内容: %s\n", item.Content()))
+		content.WriteString(fmt.Sprintf("   %s: %s\n", n.locale().T(notifier.MsgLink), item.URL()))
+		content.WriteString(fmt.Sprintf("   %s: %s\n", n.locale().T(notifier.MsgContent), item.Content()))
 		content.WriteString("\n")
 	}
 
@@ -237,7 +244,7 @@ func (n *FeishuNotifier) formatMarkdownMessage(title string, items []notifier.Me
 
 	for i, item := range items {
 		content.WriteString(fmt.Sprintf("## %d. [%s](%s)\n", i+1, item.Title(), item.URL()))
-		content.WriteString(fmt.Sprintf("- **内容**: %s\n", item.Content()))
+		content.WriteString(fmt.Sprintf("- **%s**: %s\n", n.locale().T(notifier.MsgContent), item.Content()))
 		content.WriteString("\n")
 	}
 
@@ -264,7 +271,7 @@ func (n *FeishuNotifier) formatPostMessage(title string, items []notifier.Messag
 	summaryRow := []map[string]string{
 		{
 			"tag":  "text",
-			"text": notifier.FormatNotificationSummary(items),
+			"text": n.locale().FormatSummary(items),
 		},
 	}
 	post.ZhCN.Content = append(post.ZhCN.Content, summaryRow)
@@ -291,7 +298,7 @@ func (n *FeishuNotifier) formatPostMessage(title string, items []notifier.Messag
 		contentRow := []map[string]string{
 			{
 				"tag":  "text",
-				"text": fmt.Sprintf("   内容: %s", item.Content()),
+				"text": fmt.Sprintf("   %s: %s", n.locale().T(notifier.MsgContent), item.Content()),
 			},
 		}
 		post.ZhCN.Content = append(post.ZhCN.Content, contentRow)
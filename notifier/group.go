@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+)
+
+// GroupBy 消息正文中消息项的分组方式
+type GroupBy string
+
+const (
+	// GroupNone 不分组，按发送时的顺序列出消息项
+	GroupNone GroupBy = ""
+	// GroupBySource 按 SourceItem 的来源分组
+	GroupBySource GroupBy = "source"
+	// GroupByCategory 按 CategoryItem 的分类分组
+	GroupByCategory GroupBy = "category"
+)
+
+// Validate 检查分组方式是否受支持
+func (g GroupBy) Validate() error {
+	switch g {
+	case GroupNone, GroupBySource, GroupByCategory:
+		return nil
+	default:
+		return fmt.Errorf("不支持的分组方式: %s", g)
+	}
+}
+
+// CategoryItem 可以提供分类的消息项，按分类分组时使用
+type CategoryItem interface {
+	MessageItem
+	// Category 返回消息的分类
+	Category() string
+}
+
+// ItemGroup 一组消息项
+type ItemGroup struct {
+	// Name 分组名称，即来源或分类；消息项没有来源或分类时为空
+	Name string
+	// Items 组内的消息项，保持发送时的相对顺序
+	Items []MessageItem
+}
+
+// Header 返回按locale格式化的分组标题，包含组内的消息数量
+func (g ItemGroup) Header(locale Locale) string {
+	name := g.Name
+	if name == "" {
+		name = locale.T(MsgOtherGroup)
+	}
+	return locale.T(MsgGroupHeader, name, len(g.Items))
+}
+
+// GroupItems 按分组方式将消息项分组，分组按首次出现的顺序排列，没有来源或分类的消息项归入最后一个名称为空的分组
+// by 为 GroupNone 时返回nil
+func GroupItems(items []MessageItem, by GroupBy) []ItemGroup {
+	if by == GroupNone || len(items) == 0 {
+		return nil
+	}
+
+	var groups []ItemGroup
+	var others []MessageItem
+	index := make(map[string]int)
+	for _, item := range items {
+		name := groupName(item, by)
+		if name == "" {
+			others = append(others, item)
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, ItemGroup{Name: name})
+		}
+		groups[i].Items = append(groups[i].Items, item)
+	}
+	if len(others) > 0 {
+		groups = append(groups, ItemGroup{Items: others})
+	}
+	return groups
+}
+
+// groupName 返回消息项在分组方式下的分组名称
+func groupName(item MessageItem, by GroupBy) string {
+	switch by {
+	case GroupBySource:
+		if s, ok := item.(SourceItem); ok {
+			return s.Source()
+		}
+	case GroupByCategory:
+		if c, ok := item.(CategoryItem); ok {
+			return c.Category()
+		}
+	}
+	return ""
+}
+
+// groupingKey 上下文中分组方式的键
+type groupingKey struct{}
+
+// WithGrouping 返回携带分组方式的上下文，使用该上下文发送时支持分组的通知器在正文中按组列出消息项，
+// 每组前带有分组名称和数量；by 为 GroupNone 时返回原上下文
+func WithGrouping(ctx context.Context, by GroupBy) context.Context {
+	if by == GroupNone {
+		return ctx
+	}
+	return context.WithValue(ctx, groupingKey{}, by)
+}
+
+// GroupingFromContext 返回上下文携带的分组方式，没有时返回 GroupNone
+func GroupingFromContext(ctx context.Context) GroupBy {
+	by, _ := ctx.Value(groupingKey{}).(GroupBy)
+	return by
+}
+
+// FormatGroups 按上下文携带的分组方式将消息项分组，没有分组方式时返回nil
+// 通知器格式化正文时应使用该函数，返回nil时按原顺序列出消息项
+func FormatGroups(ctx context.Context, items []MessageItem) []ItemGroup {
+	return GroupItems(items, GroupingFromContext(ctx))
+}
+
+// applyGrouping 将分组方式放入上下文并按分组重新排列消息项，
+// 使不支持分组的通知器也能让同组的消息项相邻；上下文已携带分组方式时以上下文为准
+func applyGrouping(ctx context.Context, by GroupBy, items []MessageItem) (context.Context, []MessageItem) {
+	if existing := GroupingFromContext(ctx); existing != GroupNone {
+		by = existing
+	} else {
+		ctx = WithGrouping(ctx, by)
+	}
+
+	groups := GroupItems(items, by)
+	if groups == nil {
+		return ctx, items
+	}
+	ordered := make([]MessageItem, 0, len(items))
+	for _, group := range groups {
+		ordered = append(ordered, group.Items...)
+	}
+	return ctx, ordered
+}
@@ -0,0 +1,81 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/logging"
+)
+
+// ErrHealthCheckUnsupported 通知器没有实现健康检查
+var ErrHealthCheckUnsupported = errors.New("通知器不支持健康检查")
+
+// HealthChecker 支持健康检查的通知器
+// HealthCheck 在不发送消息的情况下检查服务是否可达、凭据是否有效，例如 Telegram 的 getMe 和 SMTP 的 NOOP
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// CheckHealth 检查通知器的健康状态，通知器没有实现 HealthChecker 时返回 ErrHealthCheckUnsupported
+func CheckHealth(ctx context.Context, notifier Notifier) error {
+	checker, ok := notifier.(HealthChecker)
+	if !ok {
+		return ErrHealthCheckUnsupported
+	}
+	return checker.HealthCheck(ctx)
+}
+
+// ChannelHealth 通知渠道的健康状态
+type ChannelHealth struct {
+	Channel   string        `json:"channel"`         // 注册名称
+	Type      string        `json:"type"`            // 通知器类型，即 Notifier.Name()
+	Supported bool          `json:"supported"`       // 通知器是否支持健康检查，不支持时 Healthy 为true
+	Healthy   bool          `json:"healthy"`         // 检查是否通过
+	Error     string        `json:"error,omitempty"` // 检查失败的原因
+	Latency   time.Duration `json:"latency"`         // 检查的耗时
+}
+
+// CheckAll 并发检查所有通知渠道，返回按注册顺序排列的健康状态
+// 有渠道检查失败时同时返回合并后的错误，可以在启动时用来校验凭据；不支持健康检查的渠道视为健康
+func (m *NotifierManager) CheckAll(ctx context.Context) ([]ChannelHealth, error) {
+	notifiers := m.snapshot()
+	m.mu.RLock()
+	logger := m.logger
+	m.mu.RUnlock()
+
+	statuses := make([]ChannelHealth, len(notifiers))
+	var wg sync.WaitGroup
+	for i, n := range notifiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := CheckHealth(ctx, n.Notifier)
+			status := ChannelHealth{
+				Channel:   n.Name,
+				Type:      n.Notifier.Name(),
+				Supported: !errors.Is(err, ErrHealthCheckUnsupported),
+				Latency:   time.Since(start),
+			}
+			status.Healthy = err == nil || !status.Supported
+			if status.Supported && err != nil {
+				status.Error = err.Error()
+				logger.Warn("通知渠道健康检查失败", logging.KeyChannel, n.Name, logging.KeyError, err)
+			}
+			statuses[i] = status
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, status := range statuses {
+		if !status.Healthy {
+			errs = append(errs, fmt.Errorf("%s: %s", status.Channel, status.Error))
+		}
+	}
+	return statuses, errors.Join(errs...)
+}
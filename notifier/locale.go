@@ -0,0 +1,157 @@
+package notifier
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Locale 通知内容使用的语言，例如 zh-CN、en-US
+type Locale string
+
+const (
+	// LocaleZhCN 简体中文
+	LocaleZhCN Locale = "zh-CN"
+	// LocaleEnUS 美式英语
+	LocaleEnUS Locale = "en-US"
+
+	// DefaultLocale 未配置语言或语言未注册时使用的语言
+	DefaultLocale = LocaleZhCN
+)
+
+// 字符串表的键，带格式化参数的键在注释中说明参数
+const (
+	// MsgTitle 通知标题，参数为资讯数量
+	MsgTitle = "title"
+	// MsgEmptyTitle 没有资讯时的通知标题
+	MsgEmptyTitle = "empty_title"
+	// MsgSummary 通知摘要，参数为资讯数量
+	MsgSummary = "summary"
+	// MsgEmptySummary 没有资讯时的通知摘要
+	MsgEmptySummary = "empty_summary"
+	// MsgLink 链接字段的名称
+	MsgLink = "link"
+	// MsgContent 内容字段的名称
+	MsgContent = "content"
+	// MsgItemTitle 标题字段的名称
+	MsgItemTitle = "item_title"
+	// MsgIndex 序号字段的名称
+	MsgIndex = "index"
+	// MsgViewOriginal 查看原文的链接文字
+	MsgViewOriginal = "view_original"
+	// MsgSentAt 发送时间，参数为格式化后的时间
+	MsgSentAt = "sent_at"
+	// MsgMoreItems 未显示的资讯，参数为未显示的数量
+	MsgMoreItems = "more_items"
+	// MsgGroupHeader 分组标题，参数为分组名称和组内的资讯数量
+	MsgGroupHeader = "group_header"
+	// MsgOtherGroup 没有来源或分类的资讯所在分组的名称
+	MsgOtherGroup = "other_group"
+)
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[Locale]map[string]string{
+		LocaleZhCN: {
+			MsgTitle:        "📊 趋势雷达: %d条资讯",
+			MsgEmptyTitle:   "空的趋势雷达通知",
+			MsgSummary:      "共 %d 条资讯",
+			MsgEmptySummary: "暂无新资讯",
+			MsgLink:         "链接",
+			MsgContent:      "内容",
+			MsgItemTitle:    "标题",
+			MsgIndex:        "序号",
+			MsgViewOriginal: "查看原文",
+			MsgSentAt:       "发送时间: %s",
+			MsgMoreItems:    "... 还有 %d 条资讯未显示",
+			MsgGroupHeader:  "%s（%d条）",
+			MsgOtherGroup:   "其他",
+		},
+		LocaleEnUS: {
+			MsgTitle:        "📊 Trend Radar: %d items",
+			MsgEmptyTitle:   "Empty Trend Radar notification",
+			MsgSummary:      "%d items in total",
+			MsgEmptySummary: "No new items",
+			MsgLink:         "Link",
+			MsgContent:      "Content",
+			MsgItemTitle:    "Title",
+			MsgIndex:        "No.",
+			MsgViewOriginal: "Read more",
+			MsgSentAt:       "Sent at: %s",
+			MsgMoreItems:    "... %d more items not shown",
+			MsgGroupHeader:  "%s (%d)",
+			MsgOtherGroup:   "Other",
+		},
+	}
+)
+
+// RegisterLocale 注册语言的字符串表，已注册的语言会合并覆盖对应的键；未包含的键使用默认语言的字符串
+func RegisterLocale(locale Locale, messages map[string]string) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+
+	table := make(map[string]string, len(messages))
+	for key, message := range locales[locale] {
+		table[key] = message
+	}
+	for key, message := range messages {
+		table[key] = message
+	}
+	locales[locale] = table
+}
+
+// Locales 返回已注册的语言，按名称排序
+func Locales() []Locale {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+
+	result := make([]Locale, 0, len(locales))
+	for locale := range locales {
+		result = append(result, locale)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// IsRegistered 判断语言是否已注册
+func (l Locale) IsRegistered() bool {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	_, ok := locales[l]
+	return ok
+}
+
+// T 返回键对应的字符串，args非空时按 fmt.Sprintf 格式化
+// 语言未注册或缺少该键时使用默认语言，默认语言也没有时返回键本身
+func (l Locale) T(key string, args ...any) string {
+	localesMu.RLock()
+	message, ok := locales[l][key]
+	if !ok {
+		message, ok = locales[DefaultLocale][key]
+	}
+	localesMu.RUnlock()
+
+	if !ok {
+		message = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// FormatTitle 格式化通知标题
+func (l Locale) FormatTitle(items []MessageItem) string {
+	if len(items) == 0 {
+		return l.T(MsgEmptyTitle)
+	}
+	return l.T(MsgTitle, len(items))
+}
+
+// FormatSummary 格式化通知摘要
+func (l Locale) FormatSummary(items []MessageItem) string {
+	if len(items) == 0 {
+		return l.T(MsgEmptySummary)
+	}
+	return l.T(MsgSummary, len(items))
+}
@@ -2,52 +2,235 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"slices"
 	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/logging"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // 注意：核心类型定义已移至types.go文件
 
 // NotifierManager 通知管理器
 type NotifierManager struct {
-	notifiers []Notifier
-	wg        sync.WaitGroup
+	mu        sync.RWMutex
+	notifiers []NamedNotifier
+	logger    logging.Logger
+	tracer    trace.Tracer
+	grouping  GroupBy
+
+	// audit 记录每次发送尝试的审计日志，为nil时不记录
+	audit AuditLog
+}
+
+// NamedNotifier 带注册名称的通知器，同一类型的通知器可以用不同的名称注册多个实例
+type NamedNotifier struct {
+	Name     string
+	Notifier Notifier
 }
 
 // NewNotifierManager 创建通知管理器
 func NewNotifierManager() (*NotifierManager, error) {
 	manager := &NotifierManager{
-		notifiers: make([]Notifier, 0),
+		notifiers: make([]NamedNotifier, 0),
+		logger:    logging.Nop(),
+		tracer:    noop.NewTracerProvider().Tracer(tracerName),
 	}
 
 	return manager, nil
 }
 
-// RegisterNotifier 注册通知器
+// RegisterNotifier 注册通知器，name为空时使用通知器的Name()
+// 同一类型的通知器可以用不同的名称注册多个实例，例如两个Telegram群组
 func (m *NotifierManager) RegisterNotifier(name string, notifier Notifier) {
 	if notifier != nil && notifier.IsEnabled() {
-		m.notifiers = append(m.notifiers, notifier)
+		if name == "" {
+			name = notifier.Name()
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		n := NamedNotifier{Name: name, Notifier: notifier}
+		m.injectLogger(n)
+		m.installFlushHook(n)
+		m.notifiers = append(m.notifiers, n)
+	}
+}
+
+// SetLogger 设置日志记录器，默认不输出日志
+// 发送结果以通知渠道的注册名称为字段记录；实现了 LoggerSetter 的通知器同样会获得带渠道字段的Logger
+func (m *NotifierManager) SetLogger(logger logging.Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.logger = logging.OrNop(logger)
+	for _, n := range m.notifiers {
+		m.injectLogger(n)
+	}
+}
+
+// SetGrouping 设置消息正文中消息项的分组方式，默认不分组
+// 发送前消息项会按分组重新排列，支持分组的通知器（如Telegram、邮件）还会在每组前加上分组名称和数量；
+// 发送时上下文已通过 WithGrouping 携带分组方式的以上下文为准
+func (m *NotifierManager) SetGrouping(by GroupBy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.grouping = by
+}
+
+// injectLogger 向支持日志的通知器注入带渠道字段的Logger，调用时需持有锁
+func (m *NotifierManager) injectLogger(n NamedNotifier) {
+	if setter, ok := n.Notifier.(LoggerSetter); ok {
+		setter.SetLogger(m.logger.With(logging.KeyChannel, n.Name))
+	}
+}
+
+// installFlushHook 为延迟发送的通知器设置回调，将实际发送写入审计日志
+func (m *NotifierManager) installFlushHook(n NamedNotifier) {
+	if reporter, ok := n.Notifier.(FlushReporter); ok {
+		reporter.SetFlushHook(func(ctx context.Context, items []MessageItem, start time.Time, result *NotificationResult, err error) {
+			m.recordAudit(ctx, n, items, start, result, err)
+		})
+	}
+}
+
+// ReplaceNotifiers 原子地替换全部通知器，未启用的通知器会被忽略
+// 正在进行的发送继续使用替换前的通知器，之后的发送使用新的通知器，可用于配置热更新；
+// 被替换的通知器实现了 io.Closer 时会被关闭，例如发送摘要模式中累积的消息
+func (m *NotifierManager) ReplaceNotifiers(notifiers []NamedNotifier) {
+	enabled := make([]NamedNotifier, 0, len(notifiers))
+	for _, n := range notifiers {
+		if n.Notifier != nil && n.Notifier.IsEnabled() {
+			if n.Name == "" {
+				n.Name = n.Notifier.Name()
+			}
+			enabled = append(enabled, n)
+		}
+	}
+
+	m.mu.Lock()
+	for _, n := range enabled {
+		m.injectLogger(n)
+		m.installFlushHook(n)
+	}
+	previous := m.notifiers
+	m.notifiers = enabled
+	logger := m.logger
+	m.mu.Unlock()
+
+	for _, n := range previous {
+		reused := slices.ContainsFunc(enabled, func(e NamedNotifier) bool {
+			return sameNotifier(e.Notifier, n.Notifier)
+		})
+		if reused {
+			continue
+		}
+		if closer, ok := n.Notifier.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				logger.Error("关闭通知器失败", logging.KeyChannel, n.Name, logging.KeyError, err)
+			}
+		}
 	}
 }
 
-// SendToAll 发送到所有启用的通知渠道
+// Close 关闭所有实现了 io.Closer 的通知器，例如发送摘要模式中累积的消息，然后关闭审计日志
+func (m *NotifierManager) Close() error {
+	var errs []error
+	for _, n := range m.snapshot() {
+		if closer, ok := n.Notifier.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", n.Name, err))
+			}
+		}
+	}
+
+	m.mu.RLock()
+	audit := m.audit
+	m.mu.RUnlock()
+	if closer, ok := audit.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("审计日志: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sameNotifier 判断两个通知器是否为同一实例，不可比较的类型视为不同实例
+func sameNotifier(a, b Notifier) bool {
+	t := reflect.TypeOf(a)
+	return t != nil && t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// GetNotifier 按注册名称获取通知器
+func (m *NotifierManager) GetNotifier(name string) (Notifier, bool) {
+	for _, n := range m.snapshot() {
+		if n.Name == name {
+			return n.Notifier, true
+		}
+	}
+	return nil, false
+}
+
+// snapshot 返回当前通知器列表，替换时不会修改返回的切片
+func (m *NotifierManager) snapshot() []NamedNotifier {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.notifiers
+}
+
+// send 通过指定的通知器发送消息并记录日志、span和审计日志
+func (m *NotifierManager) send(ctx context.Context, n NamedNotifier, items []MessageItem) (*NotificationResult, error) {
+	m.mu.RLock()
+	logger := m.logger.With(logging.KeyChannel, n.Name)
+	grouping := m.grouping
+	m.mu.RUnlock()
+
+	ctx, items = applyGrouping(ctx, grouping, items)
+	ctx, span := m.startSpan(ctx, n, items)
+	start := time.Now()
+	result, err := n.Notifier.Send(ctx, items)
+	endSpan(span, result, err)
+	// 延迟发送的通知器通过回调记录实际发送，这里只记录被累积的消息，避免重复记录
+	if _, deferred := n.Notifier.(FlushReporter); !deferred || (err == nil && result != nil && result.Status == StatusPending) {
+		m.recordAudit(ctx, n, items, start, result, err)
+	}
+	if err != nil {
+		logger.Error("通知发送失败", "items", len(items), logging.KeyError, err)
+		return result, err
+	}
+	logger.Debug("通知发送成功", "items", len(items))
+	return result, nil
+}
+
+// SendToAll 发送到所有启用的通知渠道，返回的结果以注册名称为键
 func (m *NotifierManager) SendToAll(items []MessageItem) (map[string]*NotificationResult, error) {
-	ctx := context.Background()
+	return m.SendToAllContext(context.Background(), items)
+}
+
+// SendToAllContext 与 SendToAll 相同，发送span作为ctx中span的子span，追踪上下文会传递到通知器的HTTP请求
+func (m *NotifierManager) SendToAllContext(ctx context.Context, items []MessageItem) (map[string]*NotificationResult, error) {
+	notifiers := m.snapshot()
 	// 如果没有通知渠道，直接返回空结果
-	if len(m.notifiers) == 0 {
+	if len(notifiers) == 0 {
 		return make(map[string]*NotificationResult), nil
 	}
 
 	results := make(map[string]*NotificationResult)
 	errs := make([]error, 0)
-	resultsChan := make(chan *NotificationResult, len(m.notifiers))
-	errsChan := make(chan error, len(m.notifiers))
+	resultsChan := make(chan namedResult, len(notifiers))
+	errsChan := make(chan error, len(notifiers))
 
 	// 并发发送通知
-	for _, notifier := range m.notifiers {
-		m.wg.Add(1)
-		go func(n Notifier) {
-			defer m.wg.Done()
+	var wg sync.WaitGroup
+	for _, notifier := range notifiers {
+		wg.Add(1)
+		go func(n NamedNotifier) {
+			defer wg.Done()
 
 			// 检查上下文是否已取消
 			select {
@@ -55,24 +238,24 @@ func (m *NotifierManager) SendToAll(items []MessageItem) (map[string]*Notificati
 				errsChan <- ctx.Err()
 				return
 			default:
-				result, err := n.Send(ctx, items)
+				result, err := m.send(ctx, n, items)
 				if err != nil {
-					errsChan <- fmt.Errorf("%s 发送失败: %w", n.Name(), err)
+					errsChan <- fmt.Errorf("%s 发送失败: %w", n.Name, err)
 					return
 				}
-				resultsChan <- result
+				resultsChan <- namedResult{Name: n.Name, Result: result}
 			}
 		}(notifier)
 	}
 
 	// 等待所有通知发送完成
-	m.wg.Wait()
+	wg.Wait()
 	close(resultsChan)
 	close(errsChan)
 
 	// 收集结果
 	for result := range resultsChan {
-		results[result.Channel] = result
+		results[result.Name] = result.Result
 	}
 
 	// 收集错误
@@ -88,29 +271,46 @@ func (m *NotifierManager) SendToAll(items []MessageItem) (map[string]*Notificati
 	return results, nil
 }
 
-// SendToSpecific 发送到指定的通知渠道
+// namedResult 带注册名称的发送结果
+type namedResult struct {
+	Name   string
+	Result *NotificationResult
+}
+
+// SendToSpecific 发送到指定的通知渠道，channel为注册名称；没有同名实例时按通知器类型匹配第一个
 func (m *NotifierManager) SendToSpecific(channel string, items []MessageItem) (*NotificationResult, error) {
-	ctx := context.Background()
+	return m.SendToSpecificContext(context.Background(), channel, items)
+}
+
+// SendToSpecificContext 与 SendToSpecific 相同，发送span作为ctx中span的子span
+func (m *NotifierManager) SendToSpecificContext(ctx context.Context, channel string, items []MessageItem) (*NotificationResult, error) {
+	notifiers := m.snapshot()
 	// 如果没有通知渠道，直接返回错误
-	if len(m.notifiers) == 0 {
+	if len(notifiers) == 0 {
 		return nil, fmt.Errorf("没有启用任何通知渠道")
 	}
 
-	for _, notifier := range m.notifiers {
-		if notifier.Name() == channel && notifier.IsEnabled() {
-			return notifier.Send(ctx, items)
+	for _, notifier := range notifiers {
+		if notifier.Name == channel && notifier.Notifier.IsEnabled() {
+			return m.send(ctx, notifier, items)
+		}
+	}
+	for _, notifier := range notifiers {
+		if notifier.Notifier.Name() == channel && notifier.Notifier.IsEnabled() {
+			return m.send(ctx, notifier, items)
 		}
 	}
 
 	return nil, fmt.Errorf("通知渠道 %s 未启用或不存在", channel)
 }
 
-// GetEnabledChannels 获取已启用的通知渠道
+// GetEnabledChannels 获取已启用的通知渠道的注册名称
 func (m *NotifierManager) GetEnabledChannels() []string {
-	channels := make([]string, 0, len(m.notifiers))
-	for _, notifier := range m.notifiers {
-		if notifier.IsEnabled() {
-			channels = append(channels, notifier.Name())
+	notifiers := m.snapshot()
+	channels := make([]string, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		if notifier.Notifier.IsEnabled() {
+			channels = append(channels, notifier.Name)
 		}
 	}
 	return channels
@@ -0,0 +1,68 @@
+package notifiertest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sjzsdu/utils/notifier"
+)
+
+// AssertSent 断言模拟通知器一共发送了n条消息
+func AssertSent(t testing.TB, m *MockNotifier, n int) {
+	t.Helper()
+	if got := len(m.Items()); got != n {
+		t.Errorf("%s: 期望发送 %d 条消息，实际发送 %d 条", m.Name(), n, got)
+	}
+}
+
+// AssertNotSent 断言模拟通知器没有发送任何消息
+func AssertNotSent(t testing.TB, m *MockNotifier) {
+	t.Helper()
+	if calls := m.Calls(); len(calls) != 0 {
+		t.Errorf("%s: 期望没有发送消息，实际发送了 %d 次: %v", m.Name(), len(calls), m.Titles())
+	}
+}
+
+// AssertTitles 断言模拟通知器按顺序发送了指定标题的消息
+func AssertTitles(t testing.TB, m *MockNotifier, titles ...string) {
+	t.Helper()
+	if got := m.Titles(); !slices.Equal(got, titles) {
+		t.Errorf("%s: 期望发送的消息标题为 %v，实际为 %v", m.Name(), titles, got)
+	}
+}
+
+// AssertStatus 断言通知结果的状态
+func AssertStatus(t testing.TB, result *notifier.NotificationResult, status notifier.NotificationStatus) {
+	t.Helper()
+	if result == nil {
+		t.Errorf("期望通知状态为 %s，实际结果为nil", status)
+		return
+	}
+	if result.Status != status {
+		t.Errorf("%s: 期望通知状态为 %s，实际为 %s（%s）", result.Channel, status, result.Status, result.Error)
+	}
+}
+
+// AssertRequests 断言模拟服务器一共收到了n个请求
+func AssertRequests(t testing.TB, s *Server, n int) {
+	t.Helper()
+	if got := len(s.Requests()); got != n {
+		t.Errorf("期望模拟服务器收到 %d 个请求，实际收到 %d 个", n, got)
+	}
+}
+
+// AssertBodyContains 断言模拟服务器最后收到的请求体包含所有指定的内容
+func AssertBodyContains(t testing.TB, s *Server, substrs ...string) {
+	t.Helper()
+	req, ok := s.LastRequest()
+	if !ok {
+		t.Errorf("期望模拟服务器收到请求，实际没有收到")
+		return
+	}
+	for _, substr := range substrs {
+		if !strings.Contains(string(req.Body), substr) {
+			t.Errorf("期望请求体包含 %q，实际为: %s", substr, req.Body)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package notifiertest 提供测试通知器的工具：记录发送内容的 MockNotifier、模拟Webhook类渠道的HTTP服务器以及断言函数
+// 嵌入 NotifierManager 的应用可以用它测试通知的路由和格式，而不需要真实的渠道凭证
+package notifiertest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/notifier"
+)
+
+// Item 测试用的消息项
+type Item struct {
+	title   string
+	url     string
+	content string
+}
+
+// NewItem 创建测试用的消息项
+func NewItem(title, url, content string) *Item {
+	return &Item{title: title, url: url, content: content}
+}
+
+// Items 按标题创建一组测试用的消息项，链接和内容由标题生成
+func Items(titles ...string) []notifier.MessageItem {
+	items := make([]notifier.MessageItem, 0, len(titles))
+	for _, title := range titles {
+		items = append(items, NewItem(title, "https://example.com/"+title, title+" content"))
+	}
+	return items
+}
+
+// Title 获取标题
+func (i *Item) Title() string {
+	return i.title
+}
+
+// URL 获取链接
+func (i *Item) URL() string {
+	return i.url
+}
+
+// Content 获取内容
+func (i *Item) Content() string {
+	return i.content
+}
+
+// MockNotifier 记录每次发送内容的模拟通知器，可以安全地并发使用
+type MockNotifier struct {
+	name string
+
+	mu      sync.Mutex
+	enabled bool
+	err     error
+	calls   [][]notifier.MessageItem
+}
+
+// NewMockNotifier 创建启用的模拟通知器，name为 Name() 的返回值
+func NewMockNotifier(name string) *MockNotifier {
+	return &MockNotifier{name: name, enabled: true}
+}
+
+// Name 返回通知器名称
+func (m *MockNotifier) Name() string {
+	return m.name
+}
+
+// IsEnabled 检查是否启用
+func (m *MockNotifier) IsEnabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.enabled
+}
+
+// SetEnabled 设置是否启用，禁用的通知器不会被 NotifierManager 注册
+func (m *MockNotifier) SetEnabled(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = enabled
+}
+
+// FailWith 设置 Send 返回的错误，err为nil时恢复正常发送；失败的发送同样会被记录
+func (m *MockNotifier) FailWith(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// Send 记录发送的消息，返回成功的结果或 FailWith 设置的错误
+func (m *MockNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, append([]notifier.MessageItem(nil), items...))
+	err := m.err
+	m.mu.Unlock()
+
+	now := time.Now()
+	result := &notifier.NotificationResult{
+		Channel:    m.name,
+		Status:     notifier.StatusSuccess,
+		TotalCount: len(items),
+		StartAt:    now,
+		EndAt:      now,
+	}
+	if err != nil {
+		result.Status = notifier.StatusFailed
+		result.Error = err.Error()
+		return result, err
+	}
+	result.SuccessCount = len(items)
+	return result, nil
+}
+
+// Calls 返回每次调用 Send 时传入的消息
+func (m *MockNotifier) Calls() [][]notifier.MessageItem {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]notifier.MessageItem(nil), m.calls...)
+}
+
+// Items 按发送顺序返回所有发送过的消息
+func (m *MockNotifier) Items() []notifier.MessageItem {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []notifier.MessageItem
+	for _, call := range m.calls {
+		items = append(items, call...)
+	}
+	return items
+}
+
+// Titles 按发送顺序返回所有发送过的消息标题
+func (m *MockNotifier) Titles() []string {
+	items := m.Items()
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title())
+	}
+	return titles
+}
+
+// Reset 清空发送记录
+func (m *MockNotifier) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
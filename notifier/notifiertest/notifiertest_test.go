@@ -0,0 +1,369 @@
+package notifiertest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/notifier/notifiertest"
+	"github.com/sjzsdu/utils/notifier/webhook"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMockNotifier(t *testing.T) {
+	manager, _ := notifier.NewNotifierManager()
+
+	ops := notifiertest.NewMockNotifier("telegram")
+	dev := notifiertest.NewMockNotifier("telegram")
+	dev.FailWith(errors.New("网络错误"))
+	manager.RegisterNotifier("ops", ops)
+	manager.RegisterNotifier("dev", dev)
+
+	result, err := manager.SendToSpecific("ops", notifiertest.Items("a", "b"))
+	if err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	notifiertest.AssertStatus(t, result, notifier.StatusSuccess)
+	notifiertest.AssertTitles(t, ops, "a", "b")
+	notifiertest.AssertNotSent(t, dev)
+
+	if _, err := manager.SendToSpecific("dev", notifiertest.Items("c")); err == nil {
+		t.Error("期望返回FailWith设置的错误")
+	}
+	notifiertest.AssertSent(t, dev, 1)
+
+	ops.Reset()
+	notifiertest.AssertNotSent(t, ops)
+}
+
+func TestServer(t *testing.T) {
+	server := notifiertest.NewServer(t)
+
+	webhookNotifier, err := webhook.NewNotifier(&webhook.WebhookNotifierConfig{
+		Enabled: true,
+		URL:     server.URL() + "/hook",
+	})
+	if err != nil {
+		t.Fatalf("创建Webhook通知器失败: %v", err)
+	}
+
+	result, err := webhookNotifier.Send(t.Context(), notifiertest.Items("Go 1.24"))
+	if err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	notifiertest.AssertStatus(t, result, notifier.StatusSuccess)
+	notifiertest.AssertRequests(t, server, 1)
+	notifiertest.AssertBodyContains(t, server, "Go 1.24", "https://example.com/Go 1.24")
+
+	req, _ := server.LastRequest()
+	if req.Method != http.MethodPost || req.Path != "/hook" {
+		t.Errorf("期望请求 POST /hook，实际为 %s %s", req.Method, req.Path)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	server := notifiertest.NewServer(t)
+	webhookNotifier, err := webhook.NewNotifier(&webhook.WebhookNotifierConfig{
+		Enabled: true,
+		URL:     server.URL(),
+	})
+	if err != nil {
+		t.Fatalf("创建Webhook通知器失败: %v", err)
+	}
+
+	manager, _ := notifier.NewNotifierManager()
+	manager.SetTracerProvider(provider)
+	manager.RegisterNotifier("ops", webhookNotifier)
+
+	ctx, parent := provider.Tracer("test").Start(t.Context(), "digest")
+	if _, err := manager.SendToAllContext(ctx, notifiertest.Items("a", "b")); err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "notifier.send" {
+		t.Fatalf("期望记录 notifier.send 和父span，实际为 %d 个span", len(spans))
+	}
+	span := spans[0]
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("期望发送span是调用方span的子span")
+	}
+	attrs := make(map[string]string)
+	for _, attr := range span.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	want := map[string]string{
+		"notifier.channel": "ops",
+		"notifier.type":    "webhook",
+		"notifier.items":   "2",
+		"notifier.status":  "success",
+	}
+	for key, value := range want {
+		if attrs[key] != value {
+			t.Errorf("期望属性 %s 为 %s，实际为 %s", key, value, attrs[key])
+		}
+	}
+
+	req, _ := server.LastRequest()
+	traceparent := req.Header.Get("Traceparent")
+	if !strings.Contains(traceparent, span.SpanContext().TraceID().String()) {
+		t.Errorf("期望请求头包含追踪上下文，实际为 %q", traceparent)
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	healthy := notifiertest.NewServer(t)
+	broken := notifiertest.NewServer(t)
+	broken.Respond(http.StatusForbidden, "")
+
+	manager, _ := notifier.NewNotifierManager()
+	manager.RegisterNotifier("mock", notifiertest.NewMockNotifier("mock"))
+	for name, server := range map[string]*notifiertest.Server{"healthy": healthy, "broken": broken} {
+		n, err := webhook.NewNotifier(&webhook.WebhookNotifierConfig{Enabled: true, URL: server.URL() + "/hook"})
+		if err != nil {
+			t.Fatalf("创建Webhook通知器失败: %v", err)
+		}
+		// 包装为摘要通知器后仍然可以检查底层通知器
+		manager.RegisterNotifier(name, notifier.NewDigest(n, notifier.DigestOptions{}))
+	}
+
+	statuses, err := manager.CheckAll(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("期望返回broken渠道的错误，实际为: %v", err)
+	}
+	got := make(map[string]notifier.ChannelHealth)
+	for _, status := range statuses {
+		got[status.Channel] = status
+	}
+	if s := got["mock"]; s.Supported || !s.Healthy {
+		t.Errorf("不支持健康检查的渠道应视为健康: %+v", s)
+	}
+	if s := got["healthy"]; !s.Supported || !s.Healthy || s.Type != "webhook" {
+		t.Errorf("期望healthy渠道检查通过: %+v", s)
+	}
+	if s := got["broken"]; s.Healthy || !strings.Contains(s.Error, "403") {
+		t.Errorf("期望broken渠道检查失败: %+v", s)
+	}
+
+	req, _ := healthy.LastRequest()
+	if req.Method != http.MethodHead {
+		t.Errorf("期望健康检查发送HEAD请求，实际为 %s", req.Method)
+	}
+}
+
+func TestTitleTemplate(t *testing.T) {
+	tmpl, err := notifier.ParseTitleTemplate(`{{.Default}} | {{.Count}}条 {{join .Sources "、"}} {{.Date.Format "2006"}}`)
+	if err != nil {
+		t.Fatalf("解析标题模板失败: %v", err)
+	}
+	if _, err := notifier.ParseTitleTemplate("{{.Missing}}"); err == nil {
+		t.Error("引用不存在的字段时应返回错误")
+	}
+
+	items := []notifier.MessageItem{sourceItem{"a", "weibo"}, sourceItem{"b", "zhihu"}, sourceItem{"c", "weibo"}}
+	if title := notifier.FormatTitle(t.Context(), notifier.LocaleEnUS, items); title != "📊 Trend Radar: 3 items" {
+		t.Errorf("没有标题模板时应使用默认标题，实际为: %s", title)
+	}
+
+	ctx := notifier.WithTitleTemplate(t.Context(), tmpl)
+	title := notifier.FormatTitle(ctx, notifier.LocaleEnUS, items)
+	expected := "📊 Trend Radar: 3 items | 3条 weibo、zhihu " + time.Now().Format("2006")
+	if title != expected {
+		t.Errorf("期望标题 %q，实际为 %q", expected, title)
+	}
+
+	// 摘要合并发送时沿用累积消息时的标题模板
+	recorder := &titleNotifier{}
+	digest := notifier.NewDigest(recorder, notifier.DigestOptions{})
+	digest.Send(ctx, items)
+	if _, err := digest.Flush(t.Context()); err != nil {
+		t.Fatalf("合并发送失败: %v", err)
+	}
+	if recorder.title != expected {
+		t.Errorf("期望合并发送的标题 %q，实际为 %q", expected, recorder.title)
+	}
+}
+
+// titleNotifier 记录最近一次发送的标题的通知器
+type titleNotifier struct {
+	title string
+}
+
+func (n *titleNotifier) Name() string    { return "title" }
+func (n *titleNotifier) IsEnabled() bool { return true }
+
+func (n *titleNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
+	n.title = notifier.FormatTitle(ctx, notifier.LocaleEnUS, items)
+	return &notifier.NotificationResult{Status: notifier.StatusSuccess}, nil
+}
+
+// sourceItem 带数据源名称的消息项
+type sourceItem struct {
+	title  string
+	source string
+}
+
+func (i sourceItem) Title() string   { return i.title }
+func (i sourceItem) URL() string     { return "" }
+func (i sourceItem) Content() string { return "" }
+func (i sourceItem) Source() string  { return i.source }
+
+func TestGrouping(t *testing.T) {
+	items := []notifier.MessageItem{
+		groupItem{"a", "weibo", "社会"},
+		groupItem{"b", "zhihu", "科技"},
+		notifiertest.NewItem("c", "", ""),
+		groupItem{"d", "weibo", "科技"},
+	}
+
+	groups := notifier.GroupItems(items, notifier.GroupByCategory)
+	if len(groups) != 3 {
+		t.Fatalf("期望3个分组，实际为 %d", len(groups))
+	}
+	if groups[1].Name != "科技" || len(groups[1].Items) != 2 {
+		t.Errorf("期望第二组为科技分类的2条消息，实际为 %+v", groups[1])
+	}
+	if header := groups[2].Header(notifier.LocaleEnUS); header != "Other (1)" {
+		t.Errorf("没有分类的消息应归入其他分组，实际标题为 %s", header)
+	}
+	if notifier.GroupItems(items, notifier.GroupNone) != nil {
+		t.Error("不分组时应返回nil")
+	}
+	if err := notifier.GroupBy("date").Validate(); err == nil {
+		t.Error("不支持的分组方式应返回错误")
+	}
+
+	manager, _ := notifier.NewNotifierManager()
+	mock := notifiertest.NewMockNotifier("mock")
+	digested := notifiertest.NewMockNotifier("digest")
+	digest := notifier.NewDigest(digested, notifier.DigestOptions{})
+	manager.RegisterNotifier("mock", mock)
+	manager.RegisterNotifier("digest", digest)
+	manager.SetGrouping(notifier.GroupBySource)
+
+	// 发送前按数据源重新排列，没有来源的消息排在最后
+	if _, err := manager.SendToSpecific("mock", items); err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	notifiertest.AssertTitles(t, mock, "a", "d", "b", "c")
+
+	// 摘要合并发送时沿用累积消息时的分组方式
+	manager.SendToSpecific("digest", items[:2])
+	manager.SendToSpecific("digest", items[2:])
+	if _, err := digest.Flush(t.Context()); err != nil {
+		t.Fatalf("合并发送失败: %v", err)
+	}
+	notifiertest.AssertTitles(t, digested, "a", "d", "b", "c")
+}
+
+// groupItem 带数据源名称和分类的消息项
+type groupItem struct {
+	title    string
+	source   string
+	category string
+}
+
+func (i groupItem) Title() string    { return i.title }
+func (i groupItem) URL() string      { return "" }
+func (i groupItem) Content() string  { return "" }
+func (i groupItem) Source() string   { return i.source }
+func (i groupItem) Category() string { return i.category }
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := notifier.NewJSONLAuditLog(path)
+	if err != nil {
+		t.Fatalf("创建审计日志失败: %v", err)
+	}
+
+	manager, _ := notifier.NewNotifierManager()
+	manager.SetAuditLog(audit)
+	defer manager.Close()
+
+	ops := notifiertest.NewMockNotifier("telegram")
+	dev := notifiertest.NewMockNotifier("webhook")
+	dev.FailWith(errors.New("网络错误"))
+	manager.RegisterNotifier("ops", ops)
+	manager.RegisterNotifier("dev", dev)
+
+	manager.SendToAll(notifiertest.Items("Go 1.24", "Rust 2024"))
+	manager.SendToSpecific("ops", notifiertest.Items("Python 3.13"))
+
+	records, err := manager.QueryAudit(t.Context(), notifier.AuditQuery{})
+	if err != nil {
+		t.Fatalf("查询审计日志失败: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("期望3条审计记录，实际为: %+v", records)
+	}
+
+	failed, _ := manager.QueryAudit(t.Context(), notifier.AuditQuery{Status: notifier.StatusFailed})
+	if len(failed) != 1 || failed[0].Channel != "dev" || failed[0].Type != "webhook" || !strings.Contains(failed[0].Error, "网络错误") {
+		t.Errorf("期望dev渠道的失败记录，实际为: %+v", failed)
+	}
+
+	found, _ := manager.QueryAudit(t.Context(), notifier.AuditQuery{Channel: "ops", Contains: "Rust"})
+	if len(found) != 1 || len(found[0].Items) != 2 || found[0].Items[1].URL != "https://example.com/Rust 2024" {
+		t.Errorf("期望按渠道和消息内容查到一条记录，实际为: %+v", found)
+	}
+
+	latest, _ := manager.QueryAudit(t.Context(), notifier.AuditQuery{Channel: "ops", Limit: 1})
+	if len(latest) != 1 || latest[0].Items[0].Title != "Python 3.13" {
+		t.Errorf("期望Limit保留最新的记录，实际为: %+v", latest)
+	}
+}
+
+func TestAuditLogDigest(t *testing.T) {
+	audit, err := notifier.NewJSONLAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("创建审计日志失败: %v", err)
+	}
+
+	manager, _ := notifier.NewNotifierManager()
+	manager.SetAuditLog(audit)
+
+	digested := notifiertest.NewMockNotifier("telegram")
+	manager.RegisterNotifier("digest", notifier.NewDigest(digested, notifier.DigestOptions{MaxItems: 3}))
+
+	// 累积的消息记录为待发送，达到数量时的合并发送只记录一次
+	manager.SendToSpecific("digest", notifiertest.Items("a", "b"))
+	manager.SendToSpecific("digest", notifiertest.Items("c"))
+	// 关闭时发送剩余消息同样写入审计日志
+	manager.SendToSpecific("digest", notifiertest.Items("d"))
+	if err := manager.Close(); err != nil {
+		t.Fatalf("关闭失败: %v", err)
+	}
+
+	records, err := audit.Query(t.Context(), notifier.AuditQuery{})
+	if err != nil {
+		t.Fatalf("查询审计日志失败: %v", err)
+	}
+	var statuses []notifier.NotificationStatus
+	for _, record := range records {
+		statuses = append(statuses, record.Status)
+	}
+	expected := []notifier.NotificationStatus{notifier.StatusPending, notifier.StatusSuccess, notifier.StatusPending, notifier.StatusSuccess}
+	if !slices.Equal(statuses, expected) {
+		t.Fatalf("期望审计记录状态为 %v，实际为: %v", expected, statuses)
+	}
+	if len(records[1].Items) != 3 || len(records[3].Items) != 1 || records[3].Items[0].Title != "d" {
+		t.Errorf("期望合并发送的记录包含实际发送的消息，实际为: %+v", records)
+	}
+}
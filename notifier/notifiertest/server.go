@@ -0,0 +1,105 @@
+package notifiertest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Request 模拟服务器收到的请求
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// Server 模拟Webhook类渠道（钉钉、飞书、企业微信、Webhook、ntfy等）的HTTP服务器，记录收到的请求
+// 将通知器配置中的地址设置为 URL() 即可拦截发送的内容
+type Server struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []Request
+	status   int
+	body     string
+}
+
+// NewServer 启动模拟服务器，默认对所有请求返回 200 和 {"errcode":0,"ok":true}
+// 测试结束时自动关闭服务器
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		status: http.StatusOK,
+		body:   `{"errcode":0,"ok":true}`,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// handle 记录请求并返回设置的响应
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	status, respBody := s.status, s.body
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	io.WriteString(w, respBody)
+}
+
+// URL 返回模拟服务器的地址
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Client 返回访问模拟服务器的HTTP客户端
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Respond 设置之后的请求返回的状态码和响应内容
+func (s *Server) Respond(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	s.body = body
+}
+
+// Requests 返回收到的所有请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest 返回最后一次收到的请求，没有请求时第二个返回值为false
+func (s *Server) LastRequest() (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return Request{}, false
+	}
+	return s.requests[len(s.requests)-1], true
+}
+
+// Reset 清空请求记录
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
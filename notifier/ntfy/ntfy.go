@@ -25,6 +25,9 @@ func validatePhoneNumber(phone string) bool {
 	return match
 }
 
+// MaxMessageBytes ntfy单条消息正文的最大字节数，超过时服务端会将消息转为附件
+const MaxMessageBytes = 4096
+
 // NtfyNotifierConfig ntfy通知器配置
 type NtfyNotifierConfig struct {
 	Enabled   bool   `yaml:"enabled" json:"enabled"`
@@ -35,6 +38,7 @@ type NtfyNotifierConfig struct {
 	ClickURL  string `yaml:"click_url,omitempty" json:"click_url,omitempty"`
 	Priority  string `yaml:"priority,omitempty" json:"priority,omitempty"`
 	Proxy     string `yaml:"proxy,omitempty" json:"proxy,omitempty"`
+	Locale    string `yaml:"locale,omitempty" json:"locale,omitempty"`
 }
 
 // IsEnabled 检查是否启用
@@ -111,6 +115,11 @@ func (n *NtfyNotifier) IsEnabled() bool {
 	return n.config.Enabled && n.config.Topic != ""
 }
 
+// locale 返回通知内容使用的语言
+func (n *NtfyNotifier) locale() notifier.Locale {
+	return notifier.Locale(n.config.Locale)
+}
+
 // Send 发送通知
 func (n *NtfyNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
 	result := &notifier.NotificationResult{
@@ -127,43 +136,43 @@ func (n *NtfyNotifier) Send(ctx context.Context, items []notifier.MessageItem) (
 		return result, nil
 	}
 
-	// 格式化消息
-	_, err := n.FormatMessage(items)
-	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = err.Error()
-		result.EndAt = time.Now()
-		return result, err
+	// 按条数和消息大小拆分批次，依次发送
+	batches := notifier.SplitBatches(items, n.GetMaxBatchSize(), MaxMessageBytes, func(batch []notifier.MessageItem) int {
+		return len(n.formatMessageContent(batch))
+	})
+	for _, batch := range batches {
+		if err := n.sendBatch(ctx, batch); err != nil {
+			result.Status = notifier.StatusFailed
+			result.Error = err.Error()
+			result.EndAt = time.Now()
+			return result, err
+		}
+		result.SuccessCount += len(batch)
 	}
 
-	result.Status = notifier.StatusSuccess // 修改为直接使用Success状态，因为我们没有StatusPartial
+	result.Status = notifier.StatusSuccess
+	result.EndAt = time.Now()
+
+	return result, nil
+}
 
-	// 为简化实现，这里不使用sendBatch函数，直接发送
-	// 为所有消息格式化一条消息
-	batchMessage, err := n.FormatMessage(items)
+// sendBatch 将一批消息格式化为一条通知发送
+func (n *NtfyNotifier) sendBatch(ctx context.Context, items []notifier.MessageItem) error {
+	batchMessage, err := n.formatMessage(ctx, items)
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = err.Error()
-		result.EndAt = time.Now()
-		return result, err
+		return err
 	}
 
 	// 获取API URL
 	apiURL, err := n.getAPIURL()
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = err.Error()
-		result.EndAt = time.Now()
-		return result, err
+		return err
 	}
 
 	// 创建请求
 	req, err := n.createRequest(apiURL, batchMessage)
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = err.Error()
-		result.EndAt = time.Now()
-		return result, err
+		return err
 	}
 
 	// 添加认证信息
@@ -172,31 +181,24 @@ func (n *NtfyNotifier) Send(ctx context.Context, items []notifier.MessageItem) (
 	}
 
 	// 发送请求
+	notifier.InjectTraceContext(ctx, req.Header)
 	resp, err := n.client.Do(req.WithContext(ctx))
 	if err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = fmt.Errorf("发送请求失败: %w", err).Error()
-		result.EndAt = time.Now()
-		return result, err
+		return fmt.Errorf("发送请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 检查响应
-	if err := n.checkResponse(resp); err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = err.Error()
-		result.EndAt = time.Now()
-		return result, err
-	}
-
-	result.SuccessCount = len(items)
-	result.EndAt = time.Now()
-
-	return result, nil
+	return n.checkResponse(resp)
 }
 
 // FormatMessage 格式化消息
 func (n *NtfyNotifier) FormatMessage(items []notifier.MessageItem) (string, error) {
+	return n.formatMessage(context.Background(), items)
+}
+
+// formatMessage 格式化消息，ctx 携带的标题模板会覆盖默认标题
+func (n *NtfyNotifier) formatMessage(ctx context.Context, items []notifier.MessageItem) (string, error) {
 	if len(items) == 0 {
 		return "", errors.New("没有要发送的内容")
 	}
@@ -204,13 +206,13 @@ func (n *NtfyNotifier) FormatMessage(items []notifier.MessageItem) (string, erro
 	// 对于ntfy，我们需要将消息序列化为JSON
 	message := &NtfyMessage{
 		Topic:    n.config.Topic,
-		Title:    notifier.FormatNotificationTitle(items),
+		Title:    notifier.FormatTitle(ctx, n.locale(), items),
 		Priority: n.getPriority(),
 		Tags:     n.getTags(items),
 	}
 
-	// 格式化消息内容
-	message.Message = n.formatMessageContent(items)
+	// 格式化消息内容，单条消息超过大小限制时截断
+	message.Message = notifier.TruncateBytes(n.formatMessageContent(items), MaxMessageBytes)
 
 	// 添加点击链接（如果有）
 	if n.config.ClickURL != "" {
@@ -231,7 +233,7 @@ func (n *NtfyNotifier) formatMessageContent(items []notifier.MessageItem) string
 	var content strings.Builder
 
 	// 添加摘要
-	content.WriteString(fmt.Sprintf("%s\n\n", notifier.FormatNotificationSummary(items)))
+	content.WriteString(fmt.Sprintf("%s\n\n", n.locale().FormatSummary(items)))
 
 	// 添加资讯列表（限制数量避免过长）
 	maxItems := 5
@@ -244,29 +246,20 @@ func (n *NtfyNotifier) formatMessageContent(items []notifier.MessageItem) string
 		icon := "📄"
 
 		// 资讯标题
-		// 直接使用字符串切片截断，避免依赖外部函数
-		maxTitleLength := 80
-		title := item.Title()
-		if len(title) > maxTitleLength {
-			title = title[:maxTitleLength-3] + "..."
-		}
+		title := notifier.TruncateBytes(item.Title(), 80)
 		content.WriteString(fmt.Sprintf("%s %s\n", icon, title))
 
 		// 资讯链接
-		content.WriteString(fmt.Sprintf("链接: %s\n", item.URL()))
+		content.WriteString(fmt.Sprintf("%s: %s\n", n.locale().T(notifier.MsgLink), item.URL()))
 
 		// 资讯内容
-		maxContentLength := 100
-		contentStr := item.Content()
-		if len(contentStr) > maxContentLength {
-			contentStr = contentStr[:maxContentLength-3] + "..."
-		}
-		content.WriteString(fmt.Sprintf("内容: %s\n\n", contentStr))
+		contentStr := notifier.TruncateBytes(item.Content(), 100)
+		content.WriteString(fmt.Sprintf("%s: %s\n\n", n.locale().T(notifier.MsgContent), contentStr))
 	}
 
 	// 如果有更多资讯，添加提示
 	if len(items) > maxItems {
-		content.WriteString(fmt.Sprintf("... 还有 %d 条资讯未显示", len(items)-maxItems))
+		content.WriteString(n.locale().T(notifier.MsgMoreItems, len(items)-maxItems))
 	}
 
 	return content.String()
@@ -361,6 +354,67 @@ func (n *NtfyNotifier) checkResponse(resp *http.Response) error {
 	return nil
 }
 
+// HealthCheck 请求服务器的 /v1/health 接口检查服务是否可用，配置了用户名和密码时同时校验认证信息
+func (n *NtfyNotifier) HealthCheck(ctx context.Context) error {
+	apiURL, err := n.getAPIURL()
+	if err != nil {
+		return err
+	}
+	baseURL := strings.TrimSuffix(apiURL, n.config.Topic)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"v1/health", nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	var health struct {
+		Healthy bool `json:"healthy"`
+	}
+	if err := n.getJSON(req, &health); err != nil {
+		return err
+	}
+	if !health.Healthy {
+		return errors.New("ntfy服务器不可用")
+	}
+
+	if n.config.Username == "" || n.config.Password == "" {
+		return nil
+	}
+	// /<topic>/auth 在认证信息无效或没有权限时返回401或403
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/auth", nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.SetBasicAuth(n.config.Username, n.config.Password)
+	var auth struct {
+		Success bool `json:"success"`
+	}
+	if err := n.getJSON(req, &auth); err != nil {
+		return fmt.Errorf("认证失败: %w", err)
+	}
+	if !auth.Success {
+		return errors.New("认证失败")
+	}
+	return nil
+}
+
+// getJSON 发送请求并将响应解析到v中，状态码不是200时返回错误
+func (n *NtfyNotifier) getJSON(req *http.Request, v any) error {
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
 // getPriority 获取优先级
 func (n *NtfyNotifier) getPriority() string {
 	switch n.config.Priority {
@@ -0,0 +1,183 @@
+// Package receiver 为接收 Webhook 通知的 Go 服务提供签名验证和负载解析
+//
+// Handler 是一个 http.Handler，验证 webhook 通知器生成的 HMAC-SHA256 签名，
+// 将请求体解析为 webhook.WebhookPayload 后交给回调函数处理：
+//
+//	handler := receiver.NewHandler(receiver.Options{Secret: "xxx"},
+//		func(ctx context.Context, payload *webhook.WebhookPayload) error {
+//			for _, item := range payload.Items {
+//				log.Println(item.Title, item.URL)
+//			}
+//			return nil
+//		})
+//	http.Handle("/hooks/news", handler)
+package receiver
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sjzsdu/utils/logging"
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/notifier/webhook"
+)
+
+// DefaultMaxBodySize 默认允许的最大请求体，单位为字节
+const DefaultMaxBodySize = 1 << 20
+
+// 验证和解析请求时返回的错误
+var (
+	ErrMissingSignature = errors.New("缺少请求签名")
+	ErrInvalidSignature = errors.New("请求签名无效")
+	ErrExpired          = errors.New("通知已过期")
+	ErrBodyTooLarge     = errors.New("请求体过大")
+)
+
+// Options 接收端的配置
+type Options struct {
+	// Secret 与发送端 WebhookNotifierConfig.Secret 相同的密钥，为空时不验证签名
+	Secret string
+	// MaxAge 负载时间戳与当前时间允许的最大差值，用于拒绝重放的旧请求，不大于0时不检查
+	MaxAge time.Duration
+	// MaxBodySize 允许的最大请求体，单位为字节，不大于0时使用 DefaultMaxBodySize
+	MaxBodySize int64
+}
+
+// HandleFunc 处理验证通过的通知，返回错误时响应 500，发送端会按配置重试
+type HandleFunc func(ctx context.Context, payload *webhook.WebhookPayload) error
+
+// Handler 接收 webhook 通知器请求的 http.Handler
+// 签名无效或通知过期时响应 401，请求体无法解析时响应 400，处理成功时响应 204
+type Handler struct {
+	options Options
+	handle  HandleFunc
+	logger  logging.Logger
+}
+
+// NewHandler 创建接收通知的Handler
+func NewHandler(options Options, handle HandleFunc) *Handler {
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = DefaultMaxBodySize
+	}
+	return &Handler{
+		options: options,
+		handle:  handle,
+		logger:  logging.Nop(),
+	}
+}
+
+// SetLogger 设置日志记录器，默认不输出日志
+func (h *Handler) SetLogger(logger logging.Logger) {
+	h.logger = logging.OrNop(logger)
+}
+
+// ServeHTTP 验证并解析请求，交给回调函数处理
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, err := Decode(r, h.options)
+	if err != nil {
+		h.logger.Warn("拒绝Webhook请求", "remote", r.RemoteAddr, logging.KeyError, err)
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+
+	if err := h.handle(r.Context(), payload); err != nil {
+		h.logger.Error("处理Webhook通知失败", "items", len(payload.Items), logging.KeyError, err)
+		http.Error(w, "处理通知失败", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// statusCode 返回解析错误对应的HTTP状态码
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrExpired):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Decode 读取请求体，按options验证签名和时间戳并解析为 WebhookPayload，可用于自定义的Handler
+func Decode(r *http.Request, options Options) (*webhook.WebhookPayload, error) {
+	maxBodySize := options.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取请求体失败: %w", err)
+	}
+	if int64(len(body)) > maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+
+	if options.Secret != "" {
+		if err := Verify(options.Secret, body, r.Header.Get(webhook.SignatureHeader)); err != nil {
+			return nil, err
+		}
+	}
+
+	var payload webhook.WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("JSON解析失败: %w", err)
+	}
+
+	if options.MaxAge > 0 {
+		age := time.Since(time.Unix(payload.Timestamp, 0))
+		if age > options.MaxAge || age < -options.MaxAge {
+			return nil, ErrExpired
+		}
+	}
+
+	return &payload, nil
+}
+
+// Verify 验证signature是否为用secret对body计算的签名
+func Verify(secret string, body []byte, signature string) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(webhook.Sign(secret, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// MessageItems 将负载中的消息项转换为 notifier.MessageItem，可直接转发给其他通知器
+func MessageItems(payload *webhook.WebhookPayload) []notifier.MessageItem {
+	items := make([]notifier.MessageItem, 0, len(payload.Items))
+	for _, item := range payload.Items {
+		items = append(items, messageItem{item: item})
+	}
+	return items
+}
+
+// messageItem 实现 notifier.MessageItem 的消息项
+type messageItem struct {
+	item webhook.WebhookMessageItem
+}
+
+// Title 获取标题
+func (m messageItem) Title() string {
+	return m.item.Title
+}
+
+// URL 获取链接
+func (m messageItem) URL() string {
+	return m.item.URL
+}
+
+// Content 获取内容
+func (m messageItem) Content() string {
+	return m.item.Content
+}
@@ -0,0 +1,83 @@
+package receiver_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/notifier/notifiertest"
+	"github.com/sjzsdu/utils/notifier/receiver"
+	"github.com/sjzsdu/utils/notifier/webhook"
+)
+
+func TestHandler(t *testing.T) {
+	received := make(chan *webhook.WebhookPayload, 1)
+	handler := receiver.NewHandler(receiver.Options{Secret: "s3cret", MaxAge: time.Minute},
+		func(ctx context.Context, payload *webhook.WebhookPayload) error {
+			received <- payload
+			return nil
+		})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	webhookNotifier, err := webhook.NewNotifier(&webhook.WebhookNotifierConfig{
+		Enabled: true,
+		URL:     server.URL,
+		Secret:  "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("创建Webhook通知器失败: %v", err)
+	}
+
+	result, err := webhookNotifier.Send(t.Context(), notifiertest.Items("a", "b"))
+	if err != nil {
+		t.Fatalf("发送通知失败: %v", err)
+	}
+	notifiertest.AssertStatus(t, result, notifier.StatusSuccess)
+
+	payload := <-received
+	items := receiver.MessageItems(payload)
+	if len(items) != 2 || items[0].Title() != "a" || items[1].URL() != "https://example.com/b" {
+		t.Errorf("解析的消息项不正确: %+v", payload.Items)
+	}
+}
+
+func TestHandlerRejects(t *testing.T) {
+	handler := receiver.NewHandler(receiver.Options{Secret: "s3cret", MaxAge: time.Minute},
+		func(ctx context.Context, payload *webhook.WebhookPayload) error {
+			t.Error("验证失败的请求不应该被处理")
+			return nil
+		})
+
+	body := `{"title":"t","items":[],"timestamp":` + strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10) + `}`
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		status    int
+	}{
+		{"缺少签名", `{"title":"t"}`, "", http.StatusUnauthorized},
+		{"签名无效", `{"title":"t"}`, webhook.Sign("wrong", []byte(`{"title":"t"}`)), http.StatusUnauthorized},
+		{"已过期", body, webhook.Sign("s3cret", []byte(body)), http.StatusUnauthorized},
+		{"无法解析", `not json`, webhook.Sign("s3cret", []byte(`not json`)), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set(webhook.SignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("期望状态码 %d，实际为 %d", tt.status, rec.Code)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/sjzsdu/utils/logging"
 	"github.com/sjzsdu/utils/notifier"
 )
 
@@ -21,6 +22,7 @@ type SMSNotifierConfig struct {
 	TemplateID   string   `yaml:"template_id" json:"template_id"`
 	Signature    string   `yaml:"signature" json:"signature"`
 	CustomAPIURL string   `yaml:"custom_api_url" json:"custom_api_url"` // 自定义API地址
+	Locale       string   `yaml:"locale,omitempty" json:"locale,omitempty"` // 通知内容的语言，默认为 zh-CN
 }
 
 // IsEnabled 检查是否启用
@@ -33,6 +35,7 @@ func (c *SMSNotifierConfig) IsEnabled() bool {
 // SMSNotifier 短信通知器
 type SMSNotifier struct {
 	config *SMSNotifierConfig
+	logger logging.Logger
 }
 
 // NewNotifier 创建短信通知器
@@ -50,9 +53,15 @@ func NewNotifier(cfg *SMSNotifierConfig) (*SMSNotifier, error) {
 
 	return &SMSNotifier{
 		config: cfg,
+		logger: logging.Nop(),
 	}, nil
 }
 
+// SetLogger 设置日志记录器，默认不输出日志
+func (n *SMSNotifier) SetLogger(logger logging.Logger) {
+	n.logger = logging.OrNop(logger)
+}
+
 // Name 返回通知器名称
 func (n *SMSNotifier) Name() string {
 	return "sms"
@@ -63,6 +72,11 @@ func (n *SMSNotifier) IsEnabled() bool {
 	return n.config.IsEnabled()
 }
 
+// locale 返回通知内容使用的语言
+func (n *SMSNotifier) locale() notifier.Locale {
+	return notifier.Locale(n.config.Locale)
+}
+
 // Send 发送通知
 func (n *SMSNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
 	result := &notifier.NotificationResult{
@@ -79,7 +93,7 @@ func (n *SMSNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*
 	}
 
 	// 短信内容需要简洁，只包含最重要的信息
-	summary := notifier.FormatNotificationSummary(items)
+	summary := n.locale().FormatSummary(items)
 
 	// 截取适当长度的内容（短信有长度限制）
 	maxLength := 400
@@ -134,7 +148,7 @@ func (n *SMSNotifier) sendAliyunSMS(ctx context.Context, phoneNumber, message st
 	// _, err := client.SendSms(request)
 
 	// 模拟实现
-	fmt.Printf("[阿里云] 向 %s 发送短信: %s\n", phoneNumber, message)
+	n.logger.Info("模拟发送短信", "provider", "aliyun", "phone", phoneNumber, "message", message)
 	return nil
 }
 
@@ -153,7 +167,7 @@ func (n *SMSNotifier) sendTencentSMS(ctx context.Context, phoneNumber, message s
 	// _, err := client.SendSms(request)
 
 	// 模拟实现
-	fmt.Printf("[腾讯云] 向 %s 发送短信: %s\n", phoneNumber, message)
+	n.logger.Info("模拟发送短信", "provider", "tencent", "phone", phoneNumber, "message", message)
 	return nil
 }
 
@@ -171,7 +185,7 @@ func (n *SMSNotifier) sendAWSSMS(ctx context.Context, phoneNumber, message strin
 	// _, err := svc.Publish(params)
 
 	// 模拟实现
-	fmt.Printf("[AWS] 向 %s 发送短信: %s\n", phoneNumber, message)
+	n.logger.Info("模拟发送短信", "provider", "aws", "phone", phoneNumber, "message", message)
 	return nil
 }
 
@@ -181,7 +195,7 @@ func (n *SMSNotifier) sendCustomSMS(ctx context.Context, phoneNumber, message st
 	// 实际使用时需要根据API文档进行HTTP请求
 
 	// 模拟实现
-	fmt.Printf("[自定义API] 向 %s 发送短信: %s\n", phoneNumber, message)
+	n.logger.Info("模拟发送短信", "provider", "custom", "phone", phoneNumber, "message", message)
 	return nil
 }
 
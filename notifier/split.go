@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// truncateSuffix 截断文本时追加的后缀
+const truncateSuffix = "..."
+
+// SplitBatches 将items拆分为按顺序发送的多批消息
+// 每批最多maxItems条，并且measure计算的格式化结果大小不超过limit；maxItems或limit不大于0时不按该条件拆分。
+// 单条消息格式化后仍超过limit时单独成为一批，由通知器截断
+func SplitBatches(items []MessageItem, maxItems, limit int, measure func([]MessageItem) int) [][]MessageItem {
+	var batches [][]MessageItem
+	var current []MessageItem
+	for _, item := range items {
+		full := maxItems > 0 && len(current) >= maxItems
+		if !full && len(current) > 0 && limit > 0 {
+			full = measure(append(current[:len(current):len(current)], item)) > limit
+		}
+		if full {
+			batches = append(batches, current)
+			current = nil
+		}
+		current = append(current, item)
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// TruncateBytes 将text截断为不超过limit字节，不会截断UTF-8字符，截断时以"..."结尾
+func TruncateBytes(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	end := max(limit-len(truncateSuffix), 0)
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end] + truncateSuffix
+}
+
+// UTF16Len 返回text的UTF-16编码长度，Telegram等按UTF-16代码单元计算消息长度
+func UTF16Len(text string) int {
+	n := 0
+	for _, r := range text {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// TruncateUTF16 将text截断为UTF-16编码长度不超过limit，截断时以"..."结尾
+func TruncateUTF16(text string, limit int) string {
+	if UTF16Len(text) <= limit {
+		return text
+	}
+	budget := max(limit-len(truncateSuffix), 0)
+	n := 0
+	for i, r := range text {
+		n += utf16.RuneLen(r)
+		if n > budget {
+			return text[:i] + truncateSuffix
+		}
+	}
+	return text
+}
@@ -15,13 +15,17 @@ import (
 	"github.com/sjzsdu/utils/notifier"
 )
 
+// MaxMessageLength Telegram单条消息的最大长度，按UTF-16代码单元计算
+const MaxMessageLength = 4096
+
 // TelegramNotifierConfig Telegram通知器配置
 type TelegramNotifierConfig struct {
-	Enabled   bool   `json:"enabled"`
-	BotToken  string `json:"bot_token"`
-	ChatID    string `json:"chat_id"`
-	Proxy     string `json:"proxy,omitempty"`
-	ParseMode string `json:"parse_mode,omitempty"`
+	Enabled   bool   `yaml:"enabled" json:"enabled"`
+	BotToken  string `yaml:"bot_token" json:"bot_token"`
+	ChatID    string `yaml:"chat_id" json:"chat_id"`
+	Proxy     string `yaml:"proxy,omitempty" json:"proxy,omitempty"`
+	ParseMode string `yaml:"parse_mode,omitempty" json:"parse_mode,omitempty"`
+	Locale    string `yaml:"locale,omitempty" json:"locale,omitempty"`
 }
 
 // IsEnabled 检查是否启用
@@ -89,6 +93,11 @@ func (n *TelegramNotifier) IsEnabled() bool {
 	return n.config.Enabled && n.config.BotToken != "" && n.config.ChatID != ""
 }
 
+// locale 返回通知内容使用的语言
+func (n *TelegramNotifier) locale() notifier.Locale {
+	return notifier.Locale(n.config.Locale)
+}
+
 // Send 发送通知
 func (n *TelegramNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
 	result := &notifier.NotificationResult{
@@ -105,153 +114,229 @@ func (n *TelegramNotifier) Send(ctx context.Context, items []notifier.MessageIte
 		return result, nil
 	}
 
-	// 直接在Send方法中格式化消息
-	var batchMessage string
-	// 根据解析模式选择格式化方法
-	if n.getParseMode() == "MarkdownV2" {
-		batchMessage = n.formatMarkdownV2Message(items)
-	} else if n.getParseMode() == "HTML" {
-		batchMessage = n.formatHTMLMessage(items)
-	} else {
-		// 默认使用Markdown
-		batchMessage = n.formatMarkdownMessage(items)
+	// 按条数和消息长度拆分批次，依次发送
+	batches := notifier.SplitBatches(items, n.GetMaxBatchSize(), MaxMessageLength, func(batch []notifier.MessageItem) int {
+		return notifier.UTF16Len(n.formatMessage(ctx, batch))
+	})
+	for _, batch := range batches {
+		if err := n.sendBatch(ctx, batch); err != nil {
+			result.Status = notifier.StatusFailed
+			result.Error = err.Error()
+			result.EndAt = time.Now()
+			return result, err
+		}
+		result.SuccessCount += len(batch)
 	}
 
+	result.Status = notifier.StatusSuccess
+	result.EndAt = time.Now()
+	return result, nil
+}
+
+// sendBatch 将一批消息格式化为一条Telegram消息发送
+func (n *TelegramNotifier) sendBatch(ctx context.Context, items []notifier.MessageItem) error {
 	// 构建API URL
 	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", n.config.BotToken)
 
+	// 单条消息格式化后仍超过长度限制时，截断格式化后的文本会留下未闭合的实体或未转义的字符，
+	// Telegram会拒绝解析，因此改为发送截断后的纯文本
+	text, parseMode := n.formatMessage(ctx, items), n.getParseMode()
+	if notifier.UTF16Len(text) > MaxMessageLength {
+		text, parseMode = notifier.TruncateUTF16(n.formatTextMessage(ctx, items), MaxMessageLength), ""
+	}
+
 	// 创建消息
 	telegramMsg := &TelegramMessage{
 		ChatID:    n.config.ChatID,
-		Text:      batchMessage,
-		ParseMode: n.getParseMode(),
+		Text:      text,
+		ParseMode: parseMode,
 		// TelegramConfig中没有DisableWebPagePreview字段，使用默认值true
 		DisableWebPagePreview: true,
 	}
 
-	// 发送请求
-	if err := n.sendRequest(ctx, apiURL, telegramMsg); err != nil {
-		result.Status = notifier.StatusFailed
-		result.Error = err.Error()
-		result.EndAt = time.Now()
-		return result, err
+	return n.sendRequest(ctx, apiURL, telegramMsg)
+}
+
+// formatMessage 根据解析模式格式化消息，ctx 携带的标题模板会覆盖默认标题
+func (n *TelegramNotifier) formatMessage(ctx context.Context, items []notifier.MessageItem) string {
+	title := notifier.FormatTitle(ctx, n.locale(), items)
+	sections := n.sections(ctx, items)
+	switch n.getParseMode() {
+	case "MarkdownV2":
+		return n.formatMarkdownV2Message(title, items, sections)
+	case "HTML":
+		return n.formatHTMLMessage(title, items, sections)
+	default:
+		// 默认使用Markdown
+		return n.formatMarkdownMessage(title, items, sections)
 	}
+}
 
-	result.Status = notifier.StatusSuccess
-	result.SuccessCount = len(items)
-	result.EndAt = time.Now()
-	return result, nil
+// formatTextMessage 格式化不使用解析模式的纯文本消息，可以在任意位置截断
+func (n *TelegramNotifier) formatTextMessage(ctx context.Context, items []notifier.MessageItem) string {
+	var content strings.Builder
+
+	content.WriteString(notifier.FormatTitle(ctx, n.locale(), items) + "\n\n")
+	content.WriteString(n.locale().FormatSummary(items) + "\n\n")
+
+	sections := n.sections(ctx, items)
+	for k, section := range sections {
+		if section.header != "" {
+			content.WriteString(fmt.Sprintf("📂 %s\n\n", section.header))
+		}
+		for i, item := range section.items {
+			content.WriteString(fmt.Sprintf("📄 %s\n%s\n%s\n", n.truncateText(item.Title(), 100), item.URL(), n.truncateText(item.Content(), 200)))
+			if i < len(section.items)-1 || k < len(sections)-1 {
+				content.WriteString("\n---\n\n")
+			}
+		}
+	}
+
+	content.WriteString("\n" + n.locale().T(notifier.MsgSentAt, time.Now().Format("2006-01-02 15:04:05")))
+	return content.String()
+}
+
+// section 消息正文中的一段资讯，分组时每组一段
+type section struct {
+	// header 分组标题，不分组时为空
+	header string
+	items  []notifier.MessageItem
+}
+
+// sections 按ctx携带的分组方式将资讯分段，不分组时只有一段
+func (n *TelegramNotifier) sections(ctx context.Context, items []notifier.MessageItem) []section {
+	groups := notifier.FormatGroups(ctx, items)
+	if groups == nil {
+		return []section{{items: items}}
+	}
+	sections := make([]section, len(groups))
+	for i, group := range groups {
+		sections[i] = section{header: group.Header(n.locale()), items: group.Items}
+	}
+	return sections
 }
 
 // formatMarkdownMessage 格式化Markdown消息
-func (n *TelegramNotifier) formatMarkdownMessage(items []notifier.MessageItem) string {
+func (n *TelegramNotifier) formatMarkdownMessage(title string, items []notifier.MessageItem, sections []section) string {
 	var content strings.Builder
 
 	// 添加标题
-	content.WriteString(fmt.Sprintf("*%s*\n\n", notifier.FormatNotificationTitle(items)))
+	content.WriteString(fmt.Sprintf("*%s*\n\n", title))
 
 	// 添加摘要
-	content.WriteString(fmt.Sprintf("_%s_\n\n", notifier.FormatNotificationSummary(items)))
+	content.WriteString(fmt.Sprintf("_%s_\n\n", n.locale().FormatSummary(items)))
 
-	// 添加资讯列表
-	for i, item := range items {
-		icon := "📄"
+	// 添加资讯列表，分组时每组前添加分组标题
+	for k, section := range sections {
+		if section.header != "" {
+			content.WriteString(fmt.Sprintf("*📂 %s*\n\n", section.header))
+		}
+		for i, item := range section.items {
+			icon := "📄"
 
-		// 资讯标题
-		content.WriteString(fmt.Sprintf("*%s %s*\n", icon, n.truncateText(item.Title(), 100)))
+			// 资讯标题
+			content.WriteString(fmt.Sprintf("*%s %s*\n", icon, n.truncateText(item.Title(), 100)))
 
-		// 资讯链接
-		content.WriteString(fmt.Sprintf("[%s](%s)\n", "查看原文", item.URL()))
+			// 资讯链接
+			content.WriteString(fmt.Sprintf("[%s](%s)\n", n.locale().T(notifier.MsgViewOriginal), item.URL()))
 
-		// 资讯内容
-		content.WriteString(fmt.Sprintf("%s\n", n.truncateText(item.Content(), 200)))
+			// 资讯内容
+			content.WriteString(fmt.Sprintf("%s\n", n.truncateText(item.Content(), 200)))
 
-		// 非最后一条添加分隔线
-		if i < len(items)-1 {
-			content.WriteString("\n---\n\n")
+			// 非最后一条添加分隔线
+			if i < len(section.items)-1 || k < len(sections)-1 {
+				content.WriteString("\n---\n\n")
+			}
 		}
 	}
 
 	// 添加底部信息
-	content.WriteString(fmt.Sprintf("\n*发送时间: %s*", time.Now().Format("2006-01-02 15:04:05")))
+	content.WriteString(fmt.Sprintf("\n*%s*", n.locale().T(notifier.MsgSentAt, time.Now().Format("2006-01-02 15:04:05"))))
 
 	return content.String()
 }
 
 // formatMarkdownV2Message 格式化MarkdownV2消息（需要转义特殊字符）
-func (n *TelegramNotifier) formatMarkdownV2Message(items []notifier.MessageItem) string {
+func (n *TelegramNotifier) formatMarkdownV2Message(title string, items []notifier.MessageItem, sections []section) string {
 	var content strings.Builder
 
 	// 添加标题
-	content.WriteString(fmt.Sprintf("*%s*\n\n", n.escapeMarkdownV2(notifier.FormatNotificationTitle(items))))
+	content.WriteString(fmt.Sprintf("*%s*\n\n", n.escapeMarkdownV2(title)))
 
 	// 添加摘要
-	content.WriteString(fmt.Sprintf("_%s_\n\n", n.escapeMarkdownV2(notifier.FormatNotificationSummary(items))))
+	content.WriteString(fmt.Sprintf("_%s_\n\n", n.escapeMarkdownV2(n.locale().FormatSummary(items))))
 
-	// 添加资讯列表
-	for i, item := range items {
-		icon := "📄"
+	// 添加资讯列表，分组时每组前添加分组标题
+	for k, section := range sections {
+		if section.header != "" {
+			content.WriteString(fmt.Sprintf("*📂 %s*\n\n", n.escapeMarkdownV2(section.header)))
+		}
+		for i, item := range section.items {
+			icon := "📄"
 
-		// 资讯标题
-		content.WriteString(fmt.Sprintf("*%s %s*\n", icon, n.escapeMarkdownV2(n.truncateText(item.Title(), 100))))
+			// 资讯标题
+			content.WriteString(fmt.Sprintf("*%s %s*\n", icon, n.escapeMarkdownV2(n.truncateText(item.Title(), 100))))
 
-		// 资讯链接
-		content.WriteString(fmt.Sprintf("[%s](%s)\n", "查看原文", item.URL()))
+			// 资讯链接
+			content.WriteString(fmt.Sprintf("[%s](%s)\n", n.escapeMarkdownV2(n.locale().T(notifier.MsgViewOriginal)), escapeMarkdownV2URL(item.URL())))
 
-		// 资讯内容
-		content.WriteString(fmt.Sprintf("%s\n", n.escapeMarkdownV2(n.truncateText(item.Content(), 200))))
+			// 资讯内容
+			content.WriteString(fmt.Sprintf("%s\n", n.escapeMarkdownV2(n.truncateText(item.Content(), 200))))
 
-		// 非最后一条添加分隔线
-		if i < len(items)-1 {
-			content.WriteString("\n---\n\n")
+			// 非最后一条添加分隔线
+			if i < len(section.items)-1 || k < len(sections)-1 {
+				content.WriteString("\n\\-\\-\\-\n\n")
+			}
 		}
 	}
 
 	// 添加底部信息
-	content.WriteString(fmt.Sprintf("\n*发送时间: %s*", n.escapeMarkdownV2(time.Now().Format("2006-01-02 15:04:05"))))
+	content.WriteString(fmt.Sprintf("\n*%s*", n.escapeMarkdownV2(n.locale().T(notifier.MsgSentAt, time.Now().Format("2006-01-02 15:04:05")))))
 
 	return content.String()
 }
 
 // truncateText 截断文本
 func (n *TelegramNotifier) truncateText(text string, maxLength int) string {
-	if len(text) <= maxLength {
-		return text
-	}
-	return text[:maxLength-3] + "..."
+	return notifier.TruncateBytes(text, maxLength)
 }
 
 // formatHTMLMessage 格式化HTML消息
-func (n *TelegramNotifier) formatHTMLMessage(items []notifier.MessageItem) string {
+func (n *TelegramNotifier) formatHTMLMessage(title string, items []notifier.MessageItem, sections []section) string {
 	var content strings.Builder
 
 	// 添加标题
-	content.WriteString(fmt.Sprintf("<b>%s</b>\n\n", n.escapeHTML(notifier.FormatNotificationTitle(items))))
+	content.WriteString(fmt.Sprintf("<b>%s</b>\n\n", n.escapeHTML(title)))
 
 	// 添加摘要
-	content.WriteString(fmt.Sprintf("<i>%s</i>\n\n", n.escapeHTML(notifier.FormatNotificationSummary(items))))
+	content.WriteString(fmt.Sprintf("<i>%s</i>\n\n", n.escapeHTML(n.locale().FormatSummary(items))))
 
-	// 添加资讯列表
-	for i, item := range items {
-		icon := "📄"
+	// 添加资讯列表，分组时每组前添加分组标题
+	for k, section := range sections {
+		if section.header != "" {
+			content.WriteString(fmt.Sprintf("<b>📂 %s</b>\n\n", n.escapeHTML(section.header)))
+		}
+		for i, item := range section.items {
+			icon := "📄"
 
-		// 资讯标题
-		content.WriteString(fmt.Sprintf("<b>%s %s</b>\n", icon, n.escapeHTML(n.truncateText(item.Title(), 100))))
+			// 资讯标题
+			content.WriteString(fmt.Sprintf("<b>%s %s</b>\n", icon, n.escapeHTML(n.truncateText(item.Title(), 100))))
 
-		// 资讯链接
-		content.WriteString(fmt.Sprintf("<a href='%s'>查看原文</a>\n", item.URL()))
+			// 资讯链接
+			content.WriteString(fmt.Sprintf("<a href=\"%s\">%s</a>\n", n.escapeHTML(item.URL()), n.escapeHTML(n.locale().T(notifier.MsgViewOriginal))))
 
-		// 资讯内容
-		content.WriteString(fmt.Sprintf("<p>%s</p>\n", n.escapeHTML(n.truncateText(item.Content(), 200))))
+			// 资讯内容，Telegram不支持 <p> 和 <hr> 标签
+			content.WriteString(fmt.Sprintf("%s\n", n.escapeHTML(n.truncateText(item.Content(), 200))))
 
-		// 非最后一条添加分隔线
-		if i < len(items)-1 {
-			content.WriteString("\n<hr>\n\n")
+			// 非最后一条添加分隔线
+			if i < len(section.items)-1 || k < len(sections)-1 {
+				content.WriteString("\n---\n\n")
+			}
 		}
 	}
 
 	// 添加底部信息
-	content.WriteString(fmt.Sprintf("\n<b>发送时间: %s</b>", n.escapeHTML(time.Now().Format("2006-01-02 15:04:05"))))
+	content.WriteString(fmt.Sprintf("\n<b>%s</b>", n.escapeHTML(n.locale().T(notifier.MsgSentAt, time.Now().Format("2006-01-02 15:04:05")))))
 
 	return content.String()
 }
@@ -285,6 +370,7 @@ func (n *TelegramNotifier) sendRequest(ctx context.Context, url string, message
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	// 发送请求
+	notifier.InjectTraceContext(ctx, req.Header)
 	resp, err := n.client.Do(req.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("发送请求失败: %w", err)
@@ -316,9 +402,36 @@ func (n *TelegramNotifier) sendRequest(ctx context.Context, url string, message
 	return nil
 }
 
-// getParseMode 获取解析模式
+// HealthCheck 调用 getMe 检查Bot Token是否有效，不会发送消息
+func (n *TelegramNotifier) HealthCheck(ctx context.Context) error {
+	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/getMe", n.config.BotToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response TelegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("解析响应失败，状态码: %d: %w", resp.StatusCode, err)
+	}
+	if !response.OK {
+		return fmt.Errorf("Bot Token无效: %s (错误码: %d)", response.Description, response.ErrorCode)
+	}
+	return nil
+}
+
+// getParseMode 获取解析模式，未配置时使用HTML
 func (n *TelegramNotifier) getParseMode() string {
-	// TelegramConfig中没有ParseMode字段，使用默认值HTML
+	switch n.config.ParseMode {
+	case "MarkdownV2", "Markdown":
+		return n.config.ParseMode
+	}
 	return "HTML"
 }
 
@@ -341,6 +454,11 @@ func (n *TelegramNotifier) escapeMarkdownV2(text string) string {
 	return escaped.String()
 }
 
+// escapeMarkdownV2URL 转义MarkdownV2链接地址中的特殊字符，链接地址中只需要转义 ) 和 \
+func escapeMarkdownV2URL(url string) string {
+	return strings.NewReplacer(`\`, `\\`, ")", `\)`).Replace(url)
+}
+
 // escapeHTML 转义HTML特殊字符
 func (n *TelegramNotifier) escapeHTML(text string) string {
 	replacer := strings.NewReplacer(
@@ -0,0 +1,106 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sjzsdu/utils/notifier"
+)
+
+// mockItem 用于测试的消息项
+type mockItem struct {
+	title, url, content string
+}
+
+func (m mockItem) Title() string   { return m.title }
+func (m mockItem) URL() string     { return m.url }
+func (m mockItem) Content() string { return m.content }
+
+// roundTripFunc 记录请求并返回成功响应的Transport
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTestNotifier 创建将请求内容写入sent的通知器
+func newTestNotifier(t *testing.T, parseMode string, sent *[]map[string]string) *TelegramNotifier {
+	n, err := NewTelegramNotifier(&TelegramNotifierConfig{Enabled: true, BotToken: "token", ChatID: "1", ParseMode: parseMode})
+	if err != nil {
+		t.Fatalf("创建Telegram通知器失败: %v", err)
+	}
+	n.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var data map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+			t.Errorf("解析请求内容失败: %v", err)
+		}
+		*sent = append(*sent, data)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"ok":true}`))}, nil
+	})
+	return n
+}
+
+func TestSendOversizedItem(t *testing.T) {
+	item := mockItem{
+		title:   "标题 *粗体* [链接](x) <b>",
+		url:     "https://example.com/?q=" + strings.Repeat("a.b_c", 1000),
+		content: "内容",
+	}
+
+	for _, parseMode := range []string{"HTML", "Markdown", "MarkdownV2"} {
+		var sent []map[string]string
+		n := newTestNotifier(t, parseMode, &sent)
+		if _, err := n.Send(context.Background(), []notifier.MessageItem{item}); err != nil {
+			t.Fatalf("%s: 发送失败: %v", parseMode, err)
+		}
+		if len(sent) != 1 {
+			t.Fatalf("%s: 期望发送1条消息，实际为%d条", parseMode, len(sent))
+		}
+
+		text := sent[0]["text"]
+		if notifier.UTF16Len(text) > MaxMessageLength {
+			t.Errorf("%s: 消息长度%d超过限制", parseMode, notifier.UTF16Len(text))
+		}
+		if mode, ok := sent[0]["parse_mode"]; ok {
+			t.Errorf("%s: 超长消息应以纯文本发送，实际解析模式为 %s", parseMode, mode)
+		}
+		if !strings.HasSuffix(text, "...") || !strings.Contains(text, item.title) {
+			t.Errorf("%s: 期望保留原始标题并截断，实际为: %.200s", parseMode, text)
+		}
+	}
+}
+
+func TestSendParseModes(t *testing.T) {
+	items := []notifier.MessageItem{
+		mockItem{title: "Go 1.24 发布", url: "https://go.dev/doc/go1.24_(notes)", content: "新版本."},
+		mockItem{title: "第二条", url: "https://example.com/?a=1&b=2", content: "<内容>"},
+	}
+
+	cases := map[string][]string{
+		"HTML":       {`<a href="https://example.com/?a=1&amp;b=2">`, "&lt;内容&gt;"},
+		"MarkdownV2": {`Go 1\.24 发布`, `(https://go.dev/doc/go1.24_(notes\))`, `新版本\.`, `\-\-\-`},
+	}
+	for parseMode, expected := range cases {
+		var sent []map[string]string
+		n := newTestNotifier(t, parseMode, &sent)
+		if _, err := n.Send(context.Background(), items); err != nil {
+			t.Fatalf("%s: 发送失败: %v", parseMode, err)
+		}
+		if len(sent) != 1 || sent[0]["parse_mode"] != parseMode {
+			t.Fatalf("%s: 期望以%s发送1条消息，实际为: %v", parseMode, parseMode, sent)
+		}
+		text := sent[0]["text"]
+		for _, want := range expected {
+			if !strings.Contains(text, want) {
+				t.Errorf("%s: 期望消息包含 %q，实际为: %s", parseMode, want, text)
+			}
+		}
+		if strings.Contains(text, "<p>") || strings.Contains(text, "<hr>") {
+			t.Errorf("%s: 消息包含Telegram不支持的标签: %s", parseMode, text)
+		}
+	}
+}
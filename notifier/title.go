@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// SourceItem 可以提供来源名称的消息项，标题模板中的 .Sources 由它得到
+type SourceItem interface {
+	MessageItem
+	// Source 返回消息的来源，例如数据源名称
+	Source() string
+}
+
+// TitleData 标题模板可以使用的数据
+type TitleData struct {
+	// Count 消息数量
+	Count int
+	// Sources 消息的来源，按首次出现的顺序去重，只包含实现了 SourceItem 的消息
+	Sources []string
+	// Date 发送时间
+	Date time.Time
+	// Default 按通知器语言格式化的默认标题
+	Default string
+}
+
+// TitleTemplate 通知标题模板，使用 text/template 语法，数据为 TitleData
+// 除了内置函数外还可以使用 join，例如 "{{.Count}}条新闻 - {{join .Sources \"、\"}} {{.Date.Format \"01-02\"}}"
+type TitleTemplate struct {
+	tmpl *template.Template
+}
+
+// titleFuncs 标题模板可以使用的函数
+var titleFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// ParseTitleTemplate 解析标题模板，text 为空时返回nil，表示使用默认标题
+func ParseTitleTemplate(text string) (*TitleTemplate, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("title").Funcs(titleFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析标题模板失败: %w", err)
+	}
+	// 用示例数据执行一次，提前发现引用了不存在字段的模板
+	if err := tmpl.Execute(&strings.Builder{}, TitleData{Count: 1, Sources: []string{"source"}, Date: time.Now()}); err != nil {
+		return nil, fmt.Errorf("标题模板无效: %w", err)
+	}
+	return &TitleTemplate{tmpl: tmpl}, nil
+}
+
+// Execute 用消息列表渲染标题，defaultTitle 作为模板中的 .Default
+func (t *TitleTemplate) Execute(items []MessageItem, defaultTitle string) (string, error) {
+	data := TitleData{
+		Count:   len(items),
+		Sources: itemSources(items),
+		Date:    time.Now(),
+		Default: defaultTitle,
+	}
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// titleTemplateKey 上下文中标题模板的键
+type titleTemplateKey struct{}
+
+// WithTitleTemplate 返回携带标题模板的上下文，使用该上下文发送时通知器用模板渲染标题，替代默认的标题格式
+// tmpl 为nil时返回原上下文
+func WithTitleTemplate(ctx context.Context, tmpl *TitleTemplate) context.Context {
+	if tmpl == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, titleTemplateKey{}, tmpl)
+}
+
+// titleTemplateFromContext 返回上下文携带的标题模板，没有时返回nil
+func titleTemplateFromContext(ctx context.Context) *TitleTemplate {
+	tmpl, _ := ctx.Value(titleTemplateKey{}).(*TitleTemplate)
+	return tmpl
+}
+
+// FormatTitle 格式化通知标题：上下文携带了标题模板时用模板渲染，否则或渲染失败时使用locale的默认标题
+// 通知器格式化标题时应使用该函数，以支持按路由或调用方覆盖标题
+func FormatTitle(ctx context.Context, locale Locale, items []MessageItem) string {
+	title := locale.FormatTitle(items)
+	tmpl := titleTemplateFromContext(ctx)
+	if tmpl == nil {
+		return title
+	}
+	if rendered, err := tmpl.Execute(items, title); err == nil && rendered != "" {
+		return rendered
+	}
+	return title
+}
+
+// itemSources 返回消息的来源，按首次出现的顺序去重
+func itemSources(items []MessageItem) []string {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		s, ok := item.(SourceItem)
+		if !ok || s.Source() == "" || seen[s.Source()] {
+			continue
+		}
+		seen[s.Source()] = true
+		sources = append(sources, s.Source())
+	}
+	return sources
+}
@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName 创建Tracer时使用的instrumentation名称
+const tracerName = "github.com/sjzsdu/utils/notifier"
+
+// 发送span的属性名
+const (
+	AttrChannel      = attribute.Key("notifier.channel")
+	AttrType         = attribute.Key("notifier.type")
+	AttrItems        = attribute.Key("notifier.items")
+	AttrStatus       = attribute.Key("notifier.status")
+	AttrSuccessCount = attribute.Key("notifier.success_count")
+)
+
+// SetTracerProvider 设置创建发送span的TracerProvider，默认不记录span
+// 每次发送记录一个名为 notifier.send 的span，包含渠道、消息数和发送结果；
+// 传入 otel.GetTracerProvider() 可以使用全局设置的TracerProvider
+func (m *NotifierManager) SetTracerProvider(provider trace.TracerProvider) {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tracer = provider.Tracer(tracerName)
+}
+
+// startSpan 开始一次发送的span
+func (m *NotifierManager) startSpan(ctx context.Context, n NamedNotifier, items []MessageItem) (context.Context, trace.Span) {
+	m.mu.RLock()
+	tracer := m.tracer
+	m.mu.RUnlock()
+
+	return tracer.Start(ctx, "notifier.send",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttrChannel.String(n.Name),
+			AttrType.String(n.Notifier.Name()),
+			AttrItems.Int(len(items)),
+		),
+	)
+}
+
+// endSpan 记录发送结果并结束span
+func endSpan(span trace.Span, result *NotificationResult, err error) {
+	if result != nil {
+		span.SetAttributes(
+			AttrStatus.String(string(result.Status)),
+			AttrSuccessCount.Int(result.SuccessCount),
+		)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectTraceContext 用全局的TextMapPropagator将ctx中的追踪上下文写入请求头
+// 通知器发起HTTP请求前调用，使接收端的服务可以关联到同一条链路；没有设置全局Propagator时不写入任何请求头
+func InjectTraceContext(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
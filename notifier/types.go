@@ -3,6 +3,8 @@ package notifier
 import (
 	"context"
 	"time"
+
+	"github.com/sjzsdu/utils/logging"
 )
 
 // MessageItem 消息项接口
@@ -51,6 +53,12 @@ type Notifier interface {
 	Send(ctx context.Context, items []MessageItem) (*NotificationResult, error)
 }
 
+// LoggerSetter 支持注入日志记录器的通知器
+// 注册到 NotifierManager 后会获得带有渠道字段的Logger
+type LoggerSetter interface {
+	SetLogger(logger logging.Logger)
+}
+
 // NotifierFactory 通知器工厂函数类型
 type NotifierFactory func(config NotifierConfig) (Notifier, error)
 
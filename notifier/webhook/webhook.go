@@ -3,6 +3,9 @@ package webhook
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,9 +13,13 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/sjzsdu/utils/logging"
 	"github.com/sjzsdu/utils/notifier"
 )
 
+// SignatureHeader 配置了密钥时携带请求签名的请求头
+const SignatureHeader = "X-Webhook-Signature"
+
 // WebhookNotifierConfig Webhook通知器配置
 type WebhookNotifierConfig struct {
 	Enabled       bool              `yaml:"enabled" json:"enabled"`
@@ -24,6 +31,7 @@ type WebhookNotifierConfig struct {
 	RetryInterval int               `yaml:"retry_interval,omitempty" json:"retry_interval,omitempty"` // 重试间隔（秒）
 	ContentType   string            `yaml:"content_type,omitempty" json:"content_type,omitempty"`
 	Secret        string            `yaml:"secret,omitempty" json:"secret,omitempty"` // 用于签名的密钥
+	Locale        string            `yaml:"locale,omitempty" json:"locale,omitempty"` // 通知内容的语言，默认为 zh-CN
 }
 
 // IsEnabled 检查是否启用
@@ -35,6 +43,7 @@ func (c *WebhookNotifierConfig) IsEnabled() bool {
 type WebhookNotifier struct {
 	config *WebhookNotifierConfig
 	client *http.Client
+	logger logging.Logger
 }
 
 // NewNotifier 创建Webhook通知器
@@ -76,9 +85,15 @@ func NewNotifier(cfg *WebhookNotifierConfig) (*WebhookNotifier, error) {
 	return &WebhookNotifier{
 		config: cfg,
 		client: client,
+		logger: logging.Nop(),
 	}, nil
 }
 
+// SetLogger 设置日志记录器，默认不输出日志
+func (n *WebhookNotifier) SetLogger(logger logging.Logger) {
+	n.logger = logging.OrNop(logger)
+}
+
 // Name 返回通知器名称
 func (n *WebhookNotifier) Name() string {
 	return "webhook"
@@ -89,6 +104,11 @@ func (n *WebhookNotifier) IsEnabled() bool {
 	return n.config.IsEnabled()
 }
 
+// locale 返回通知内容使用的语言
+func (n *WebhookNotifier) locale() notifier.Locale {
+	return notifier.Locale(n.config.Locale)
+}
+
 // Send 发送通知
 func (n *WebhookNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
 	result := &notifier.NotificationResult{
@@ -105,7 +125,7 @@ func (n *WebhookNotifier) Send(ctx context.Context, items []notifier.MessageItem
 	}
 
 	// 构建payload
-	payload, err := n.buildPayload(items)
+	payload, err := n.buildPayload(ctx, items)
 	if err != nil {
 		result.Status = notifier.StatusFailed
 		result.Error = err.Error()
@@ -142,8 +162,8 @@ type WebhookMessageItem struct {
 	Content string `json:"content"`
 }
 
-// buildPayload 构建请求payload
-func (n *WebhookNotifier) buildPayload(items []notifier.MessageItem) ([]byte, error) {
+// buildPayload 构建请求payload，ctx 携带的标题模板会覆盖默认标题
+func (n *WebhookNotifier) buildPayload(ctx context.Context, items []notifier.MessageItem) ([]byte, error) {
 	// 创建消息项数组
 	webhookItems := make([]WebhookMessageItem, 0, len(items))
 	for _, item := range items {
@@ -156,8 +176,8 @@ func (n *WebhookNotifier) buildPayload(items []notifier.MessageItem) ([]byte, er
 
 	// 创建payload
 	payload := WebhookPayload{
-		Title:     notifier.FormatNotificationTitle(items),
-		Summary:   notifier.FormatNotificationSummary(items),
+		Title:     notifier.FormatTitle(ctx, n.locale(), items),
+		Summary:   n.locale().FormatSummary(items),
 		Items:     webhookItems,
 		Timestamp: time.Now().Unix(),
 	}
@@ -184,6 +204,7 @@ func (n *WebhookNotifier) sendRequest(ctx context.Context, payload []byte) error
 		if err == nil {
 			return nil
 		}
+		n.logger.Warn("Webhook请求失败", "attempt", attempt+1, logging.KeyError, err)
 	}
 
 	return fmt.Errorf("达到最大重试次数: %w", err)
@@ -209,10 +230,11 @@ func (n *WebhookNotifier) doRequest(ctx context.Context, payload []byte) error {
 	// 如果配置了密钥，添加签名头
 	if n.config.Secret != "" {
 		signature := n.generateSignature(payload)
-		req.Header.Set("X-Webhook-Signature", signature)
+		req.Header.Set(SignatureHeader, signature)
 	}
 
 	// 发送请求
+	notifier.InjectTraceContext(ctx, req.Header)
 	resp, err := n.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("发送请求失败: %w", err)
@@ -233,18 +255,48 @@ func (n *WebhookNotifier) doRequest(ctx context.Context, payload []byte) error {
 	return nil
 }
 
+// HealthCheck 向Webhook地址发送HEAD请求检查是否可达，不会重试
+// 许多Webhook只接受POST，因此405等其他客户端错误视为可达；服务端错误以及401、403、404视为不可用
+func (n *WebhookNotifier) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, n.config.URL, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("User-Agent", "notifier-webhook-client/1.0")
+	for key, value := range n.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500, resp.StatusCode == http.StatusUnauthorized,
+		resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // generateSignature 生成请求签名
 func (n *WebhookNotifier) generateSignature(payload []byte) string {
-	// 这里实现签名逻辑，例如HMAC SHA256
-	// 实际项目中应根据接收端的要求实现正确的签名算法
-	// 这里返回一个简单的示例
-	return fmt.Sprintf("sha256=%x", payload)
+	return Sign(n.config.Secret, payload)
+}
+
+// Sign 用secret计算payload的HMAC-SHA256签名，格式为 sha256=<十六进制摘要>，接收端可用 receiver 包验证
+func Sign(secret string, payload []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(payload)
+	return "sha256=" + hex.EncodeToString(h.Sum(nil))
 }
 
 // FormatMessage 格式化消息
 func (n *WebhookNotifier) FormatMessage(items []notifier.MessageItem) (string, error) {
 	// 构建payload
-	payload, err := n.buildPayload(items)
+	payload, err := n.buildPayload(context.Background(), items)
 	if err != nil {
 		return "", err
 	}
@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/sjzsdu/utils/notifier"
@@ -164,3 +165,35 @@ func TestFormatTextMessage(t *testing.T) {
 		t.Error("格式化的文本消息为空")
 	}
 }
+
+// 测试格式化消息 - 英文
+func TestFormatMessageLocale(t *testing.T) {
+	config := &WebhookNotifierConfig{
+		Enabled: true,
+		URL:     "https://example.com/webhook",
+		Method:  "POST",
+		Locale:  "en-US",
+	}
+
+	webhookNotifier, _ := NewNotifier(config)
+
+	items := []notifier.MessageItem{
+		&MockMessageItem{
+			mockTitle:   "Go 1.24",
+			mockURL:     "https://example.com",
+			mockContent: "release notes",
+		},
+	}
+
+	message, err := webhookNotifier.FormatMessage(items)
+	if err != nil {
+		t.Fatalf("格式化英文消息失败: %v", err)
+	}
+
+	if !strings.Contains(message, "Trend Radar: 1 items") || !strings.Contains(message, "1 items in total") {
+		t.Errorf("期望消息使用英文标题和摘要，实际为: %s", message)
+	}
+	if strings.Contains(message, "趋势雷达") {
+		t.Errorf("英文消息中不应包含中文标题: %s", message)
+	}
+}
@@ -27,6 +27,7 @@ type WecomNotifierConfig struct {
 	ToTag       string `yaml:"to_tag,omitempty" json:"to_tag,omitempty"`
 	Proxy       string `yaml:"proxy,omitempty" json:"proxy,omitempty"`
 	MessageType string `yaml:"message_type" json:"message_type"`
+	Locale      string `yaml:"locale,omitempty" json:"locale,omitempty"`
 }
 
 // IsEnabled 检查是否启用
@@ -100,6 +101,11 @@ func (n *WecomNotifier) IsEnabled() bool {
 	return n.config.IsEnabled()
 }
 
+// locale 返回通知内容使用的语言
+func (n *WecomNotifier) locale() notifier.Locale {
+	return notifier.Locale(n.config.Locale)
+}
+
 // Send 发送通知
 func (n *WecomNotifier) Send(ctx context.Context, items []notifier.MessageItem) (*notifier.NotificationResult, error) {
 	result := &notifier.NotificationResult{
@@ -116,7 +122,7 @@ func (n *WecomNotifier) Send(ctx context.Context, items []notifier.MessageItem)
 	}
 
 	// 格式化标题
-	title := notifier.FormatNotificationTitle(items)
+	title := notifier.FormatTitle(ctx, n.locale(), items)
 
 	// 直接在Send方法中格式化消息
 	var messageBody string
@@ -148,6 +154,7 @@ func (n *WecomNotifier) Send(ctx context.Context, items []notifier.MessageItem)
 	req.Header.Set("Content-Type", "application/json")
 
 	// 发送请求
+	notifier.InjectTraceContext(ctx, req.Header)
 	resp, err := n.client.Do(req)
 	if err != nil {
 		result.Status = notifier.StatusFailed
@@ -209,8 +216,8 @@ func (n *WecomNotifier) formatTextMessage(title string, items []notifier.Message
 
 	for i, item := range items {
 		content.WriteString(fmt.Sprintf("%d. %s\n", i+1, item.Title()))
-		content.WriteString(fmt.Sprintf("   链接: %s\n", item.URL()))
-		content.WriteString(fmt.Sprintf("   内容: %s\n", item.Content()))
+		content.WriteString(fmt.Sprintf("   %s: %s\n", n.locale().T(notifier.MsgLink), item.URL()))
+		content.WriteString(fmt.Sprintf("   %s: %s\n", n.locale().T(notifier.MsgContent), item.Content()))
 		content.WriteString("\n")
 	}
 
@@ -239,7 +246,7 @@ func (n *WecomNotifier) formatMarkdownMessage(title string, items []notifier.Mes
 
 	for i, item := range items {
 		content.WriteString(fmt.Sprintf("## %d. [%s](%s)\n", i+1, item.Title(), item.URL()))
-		content.WriteString(fmt.Sprintf("- **内容**: %s\n", item.Content()))
+		content.WriteString(fmt.Sprintf("- **%s**: %s\n", n.locale().T(notifier.MsgContent), item.Content()))
 		content.WriteString("\n")
 	}
 
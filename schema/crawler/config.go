@@ -0,0 +1,106 @@
+package crawler
+
+import (
+	"time"
+
+	notifierschema "github.com/sjzsdu/utils/schema/notifier"
+)
+
+// Config 爬虫配置文件结构体
+type Config struct {
+	// Sources 启用的数据源及单个数据源的覆盖配置
+	Sources SourcesConfig `yaml:"sources" json:"sources"`
+	// Cache 缓存配置
+	Cache CacheConfig `yaml:"cache" json:"cache"`
+	// Proxy 所有数据源默认使用的代理地址
+	Proxy string `yaml:"proxy" json:"proxy"`
+	// Timeout 所有数据源默认的请求超时，例如 10s
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	// Languages 只保留这些语言的数据项，例如 [zh]，为空时不过滤
+	Languages []string `yaml:"languages" json:"languages"`
+	// Countries 只保留这些国家或地区的数据项，例如 [CN]，为空时不过滤
+	Countries []string `yaml:"countries" json:"countries"`
+	// Notifier 通知配置，为空时不发送通知
+	Notifier *NotifierConfig `yaml:"notifier" json:"notifier"`
+}
+
+// SourcesConfig 数据源选择配置
+// Enabled 和 Categories 都为空时启用所有已注册的数据源，否则启用两者的并集
+type SourcesConfig struct {
+	// Enabled 按名称启用的数据源
+	Enabled []string `yaml:"enabled" json:"enabled"`
+	// Categories 按分类启用的数据源
+	Categories []string `yaml:"categories" json:"categories"`
+	// Disabled 排除的数据源，优先级高于 Enabled 和 Categories
+	Disabled []string `yaml:"disabled" json:"disabled"`
+	// Overrides 按数据源名称覆盖的配置
+	Overrides map[string]SourceOverride `yaml:"overrides" json:"overrides"`
+}
+
+// SourceOverride 单个数据源的覆盖配置，零值表示使用默认配置
+type SourceOverride struct {
+	// Interval 爬取间隔（秒）
+	Interval int `yaml:"interval" json:"interval"`
+	// Proxy 代理地址
+	Proxy string `yaml:"proxy" json:"proxy"`
+	// Timeout 请求超时
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	// Config 数据源配置，例如API令牌、Cookie和数据源特有的选项，通过 sources.Registry.Configure 应用；
+	// 值中可以使用 ${VAR} 引用环境变量，避免将密钥写在配置文件中
+	Config map[string]string `yaml:"config" json:"config"`
+}
+
+// 支持的缓存后端
+const (
+	// CacheMemory 内存缓存，默认值
+	CacheMemory = "memory"
+	// CacheNone 不缓存，每次都重新获取
+	CacheNone = "none"
+)
+
+// DefaultCleanupInterval 内存缓存清理过期数据的默认间隔
+const DefaultCleanupInterval = time.Hour
+
+// CacheConfig 缓存配置
+type CacheConfig struct {
+	// Backend 缓存后端，memory 或 none，为空时使用 memory
+	Backend string `yaml:"backend" json:"backend"`
+	// CleanupInterval 内存缓存清理过期数据的间隔，为0时使用 DefaultCleanupInterval
+	CleanupInterval time.Duration `yaml:"cleanup_interval" json:"cleanup_interval"`
+	// MaxBytes 内存缓存的内存预算（字节），超出后按最近最少使用的顺序淘汰，为0时不限制
+	MaxBytes int64 `yaml:"max_bytes" json:"max_bytes"`
+	// MaxItems 每个数据源每次爬取最多保留的数据项数量，为0时不限制
+	MaxItems int `yaml:"max_items" json:"max_items"`
+	// ItemTTL 爬取结果在缓存中的过期时间，为0时使用数据源的爬取间隔
+	ItemTTL time.Duration `yaml:"item_ttl" json:"item_ttl"`
+}
+
+// NotifierConfig 通知配置
+// 通知渠道可以直接写在该节点下（与 schema/notifier 的配置格式相同），也可以通过 file 引用单独的通知配置文件
+//
+//	notifier:
+//	  file: notifier.yaml
+//	  routes:
+//	    - sources: [hackernews]
+//	      channels: [ops-telegram]
+type NotifierConfig struct {
+	notifierschema.Config `yaml:",inline"`
+
+	// File 通知配置文件路径，设置后忽略内联的通知渠道
+	File string `yaml:"file" json:"file"`
+	// Routes 通知路由规则，为空时所有数据源的新数据发送到所有渠道
+	Routes []RouteConfig `yaml:"routes" json:"routes"`
+}
+
+// RouteConfig 通知路由规则，将匹配的数据源的新数据发送到指定渠道
+type RouteConfig struct {
+	// Sources 匹配的数据源名称
+	Sources []string `yaml:"sources" json:"sources"`
+	// Categories 匹配的数据源分类，与 Sources 都为空时匹配所有数据源
+	Categories []string `yaml:"categories" json:"categories"`
+	// Channels 发送的通知渠道名称，为空时发送到所有渠道
+	Channels []string `yaml:"channels" json:"channels"`
+	// Title 通知标题模板，为空时使用通知器默认的标题，语法见 notifier.TitleTemplate，
+	// 例如 "{{.Count}}条新闻 - {{join .Sources \"、\"}}"；摘要模式的渠道合并发送时使用默认标题
+	Title string `yaml:"title" json:"title"`
+}
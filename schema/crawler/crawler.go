@@ -0,0 +1,398 @@
+package crawler
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/crawler/sources"
+	"github.com/sjzsdu/utils/logging"
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/schema/internal/loader"
+	notifierschema "github.com/sjzsdu/utils/schema/notifier"
+)
+
+// EngineSchema 管理爬取引擎的schema
+type EngineSchema struct {
+	mu     sync.RWMutex
+	config Config
+	// dir 配置文件所在目录，用于解析 notifier.file 的相对路径
+	dir    string
+	logger logging.Logger
+}
+
+// NewEngineSchema 创建EngineSchema实例
+func NewEngineSchema() *EngineSchema {
+	return &EngineSchema{}
+}
+
+// SetLogger 设置创建的引擎和通知器使用的日志记录器，默认不输出日志
+func (s *EngineSchema) SetLogger(logger logging.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger = logger
+}
+
+// LoadFromFile 从配置文件加载配置，配置值中的 ${VAR} 和 ${VAR:-default} 会从环境变量展开
+// 根据扩展名选择格式：.json 为JSON，.toml 为TOML，其余按YAML解析
+func (s *EngineSchema) LoadFromFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	s.mu.Lock()
+	s.dir = filepath.Dir(filePath)
+	s.mu.Unlock()
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		return s.LoadFromJSON(content)
+	case ".toml":
+		return s.LoadFromTOML(content)
+	}
+
+	if err := s.unmarshal(content); err != nil {
+		return fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromBytes 从字节数组加载配置，配置值中的环境变量引用同样会被展开
+func (s *EngineSchema) LoadFromBytes(data []byte) error {
+	if err := s.unmarshal(data); err != nil {
+		return fmt.Errorf("解析配置失败: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromJSON 从JSON加载配置，字段名与YAML配置相同
+func (s *EngineSchema) LoadFromJSON(data []byte) error {
+	if !json.Valid(data) {
+		return fmt.Errorf("解析JSON配置失败: 格式无效")
+	}
+
+	// JSON是YAML的子集，统一按YAML解析以复用字段标签和环境变量展开
+	if err := s.unmarshal(data); err != nil {
+		return fmt.Errorf("解析JSON配置失败: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromTOML 从TOML加载配置，字段名与YAML配置相同
+func (s *EngineSchema) LoadFromTOML(data []byte) error {
+	// 转换为YAML后解析，复用字段标签和环境变量展开
+	converted, err := loader.TOMLToYAML(data)
+	if err != nil {
+		return fmt.Errorf("解析TOML配置失败: %w", err)
+	}
+	if err := s.unmarshal(converted); err != nil {
+		return fmt.Errorf("解析TOML配置失败: %w", err)
+	}
+
+	return nil
+}
+
+// unmarshal 将YAML格式的配置合并到当前配置
+func (s *EngineSchema) unmarshal(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return loader.UnmarshalYAML(data, &s.config)
+}
+
+// currentConfig 返回当前配置
+func (s *EngineSchema) currentConfig() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// CreateEngine 根据配置创建爬取引擎，创建前会先校验配置
+// 配置了通知时，引擎启动后会将各数据源的新数据按路由规则发送到对应的通知渠道；
+// 数据源配置、代理和超时应用在引擎使用的数据源副本上，不会修改全局注册表中的数据源实例
+func (s *EngineSchema) CreateEngine() (crawler.ControllableEngine, error) {
+	config := s.currentConfig()
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	dir, logger := s.dir, logging.OrNop(s.logger)
+	s.mu.RUnlock()
+
+	cache, closeCache := newCache(config.Cache)
+	engine := &configuredEngine{
+		ControllableEngine: crawler.NewEngine(cache,
+			crawler.WithLogger(logger),
+			crawler.WithMaxItemsPerSource(config.Cache.MaxItems),
+			crawler.WithItemTTL(config.Cache.ItemTTL),
+			crawler.WithLanguages(config.Languages...),
+			crawler.WithCountries(config.Countries...),
+		),
+		closeCache: closeCache,
+		logger:     logger,
+	}
+	fail := func(err error) (crawler.ControllableEngine, error) {
+		closeCache()
+		return nil, err
+	}
+
+	selected, err := selectSources(config.Sources)
+	if err != nil {
+		return fail(err)
+	}
+	for _, source := range selected {
+		source, err := configureSource(config, source)
+		if err != nil {
+			return fail(err)
+		}
+		if err := engine.RegisterSource(source); err != nil {
+			return fail(err)
+		}
+		engine.sources = append(engine.sources, source)
+	}
+
+	if config.Notifier != nil {
+		manager, err := createNotifierManager(config.Notifier, dir)
+		if err != nil {
+			return fail(err)
+		}
+		manager.SetLogger(logger)
+		routes, err := resolveRoutes(config.Notifier.Routes, manager)
+		if err != nil {
+			return fail(err)
+		}
+		engine.manager = manager
+		engine.routes = routes
+	}
+
+	return engine, nil
+}
+
+// SourceNames 返回配置启用的数据源名称，按名称排序
+func (s *EngineSchema) SourceNames() ([]string, error) {
+	selected, err := selectSources(s.currentConfig().Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(selected))
+	for i, source := range selected {
+		names[i] = source.GetName()
+	}
+	return names, nil
+}
+
+// LoadAndCreateEngine 从配置文件加载配置并创建爬取引擎
+func LoadAndCreateEngine(filePath string) (crawler.ControllableEngine, error) {
+	schema := NewEngineSchema()
+	if err := schema.LoadFromFile(filePath); err != nil {
+		return nil, err
+	}
+
+	return schema.CreateEngine()
+}
+
+// newCache 根据配置创建缓存，返回的函数用于释放缓存的资源
+func newCache(config CacheConfig) (crawler.Cache, func()) {
+	if config.Backend == CacheNone {
+		return noCache{}, func() {}
+	}
+
+	interval := config.CleanupInterval
+	if interval <= 0 {
+		interval = DefaultCleanupInterval
+	}
+	cache := crawler.NewMemoryCache(interval, crawler.WithCacheMaxBytes(config.MaxBytes))
+	var once sync.Once
+	return cache, func() {
+		once.Do(cache.Close)
+	}
+}
+
+// noCache 不缓存任何数据的缓存实现
+type noCache struct{}
+
+// Get 始终返回空数据
+func (noCache) Get(key string) ([]models.Item, error) {
+	return nil, nil
+}
+
+// Set 忽略写入的数据
+func (noCache) Set(key string, items []models.Item, expiration time.Duration) error {
+	return nil
+}
+
+// Delete 不做任何操作
+func (noCache) Delete(key string) error {
+	return nil
+}
+
+// Clear 不做任何操作
+func (noCache) Clear() error {
+	return nil
+}
+
+// MGet 始终返回空数据
+func (noCache) MGet(keys []string) (map[string][]models.Item, error) {
+	return map[string][]models.Item{}, nil
+}
+
+// MSet 忽略写入的数据
+func (noCache) MSet(entries map[string][]models.Item, expiration time.Duration) error {
+	return nil
+}
+
+// DeleteByPrefix 不做任何操作
+func (noCache) DeleteByPrefix(prefix string) error {
+	return nil
+}
+
+// Keys 始终返回空列表
+func (noCache) Keys(pattern string) ([]string, error) {
+	return nil, nil
+}
+
+// selectSources 从全局注册表中选出配置启用的数据源，按名称排序
+func selectSources(config SourcesConfig) ([]crawler.Source, error) {
+	registry := sources.GetRegistry()
+
+	var selected []crawler.Source
+	if len(config.Enabled) == 0 && len(config.Categories) == 0 {
+		selected = registry.List()
+	} else {
+		enabled, err := registry.GetSources(config.Enabled)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(enabled, registry.GetByCategories(config.Categories)...)
+	}
+
+	seen := make(map[string]bool, len(selected))
+	result := make([]crawler.Source, 0, len(selected))
+	for _, source := range selected {
+		name := source.GetName()
+		if seen[name] || slices.Contains(config.Disabled, name) {
+			continue
+		}
+		seen[name] = true
+		result = append(result, source)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetName() < result[j].GetName()
+	})
+	return result, nil
+}
+
+// overriddenSource 覆盖了爬取间隔的数据源
+type overriddenSource struct {
+	crawler.Source
+	interval int
+}
+
+// GetInterval 返回覆盖后的爬取间隔（秒）
+func (s *overriddenSource) GetInterval() int {
+	return s.interval
+}
+
+// Unwrap 返回被包装的数据源，使 crawler.Metadata 能读取其简介、语言和地区等信息
+func (s *overriddenSource) Unwrap() crawler.Source {
+	return s.Source
+}
+
+// GetTimeout 返回被包装数据源的超时
+func (s *overriddenSource) GetTimeout() time.Duration {
+	if source, ok := s.Source.(crawler.TimeoutSource); ok {
+		return source.GetTimeout()
+	}
+	return 0
+}
+
+// configureSource 为数据源应用数据源配置以及代理、超时和爬取间隔配置
+// 需要修改数据源时先复制，全局注册表中的数据源保持不变，多个引擎的配置互不影响
+func configureSource(config Config, source crawler.Source) (crawler.Source, error) {
+	override := config.Sources.Overrides[source.GetName()]
+	proxy := cmp.Or(override.Proxy, config.Proxy)
+	timeout := cmp.Or(override.Timeout, config.Timeout)
+	if len(override.Config) > 0 || proxy != "" || timeout > 0 {
+		clone, err := sources.Clone(source)
+		if err != nil {
+			return nil, err
+		}
+		source = clone
+	}
+
+	if len(override.Config) > 0 {
+		if err := sources.Configure(source, sources.SourceConfig(override.Config).Clone()); err != nil {
+			return nil, err
+		}
+	}
+
+	if proxy != "" || timeout > 0 {
+		if setter, ok := source.(interface{ SetClient(*http.Client) }); ok {
+			client, err := newHTTPClient(proxy, timeout)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", source.GetName(), err)
+			}
+			setter.SetClient(client)
+		}
+	}
+
+	if override.Interval > 0 {
+		return &overriddenSource{Source: source, interval: override.Interval}, nil
+	}
+	return source, nil
+}
+
+// newHTTPClient 创建使用指定代理和超时的HTTP客户端
+func newHTTPClient(proxy string, timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("解析代理地址失败: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if timeout <= 0 {
+		timeout = sources.DefaultTimeout
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+// createNotifierManager 根据通知配置创建NotifierManager，dir用于解析配置文件的相对路径
+func createNotifierManager(config *NotifierConfig, dir string) (*notifier.NotifierManager, error) {
+	if config.File == "" {
+		manager, err := notifierschema.NewManagerSchemaFromConfig(config.Config).CreateNotifierManager()
+		if err != nil {
+			return nil, fmt.Errorf("创建通知器失败: %w", err)
+		}
+		return manager, nil
+	}
+
+	path := config.File
+	if !filepath.IsAbs(path) && dir != "" {
+		path = filepath.Join(dir, path)
+	}
+	manager, err := notifierschema.LoadAndCreateNotifierManager(path)
+	if err != nil {
+		return nil, fmt.Errorf("创建通知器失败: %w", err)
+	}
+	return manager, nil
+}
@@ -0,0 +1,289 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/crawler/pkg/taxonomy"
+	"github.com/sjzsdu/utils/crawler/sources"
+)
+
+// testSource 用于测试的数据源，返回固定的数据项
+type testSource struct {
+	sources.BaseSource
+	items []models.Item
+}
+
+func (s *testSource) Fetch(ctx context.Context) ([]byte, error) {
+	return nil, nil
+}
+
+func (s *testSource) Parse(content []byte) ([]models.Item, error) {
+	return s.items, nil
+}
+
+func init() {
+	if err := taxonomy.Register(taxonomy.Category{ID: "schema-test"}); err != nil {
+		panic(err)
+	}
+	for _, name := range []string{"schema-test-a", "schema-test-b"} {
+		sources.RegisterSource(&testSource{
+			BaseSource: sources.BaseSource{
+				Name:       name,
+				Interval:   3600,
+				Categories: []string{"schema-test"},
+			},
+			items: []models.Item{{ID: name + "-1", Title: name + " item"}},
+		})
+	}
+	sources.RegisterSource(&testSource{
+		BaseSource: sources.BaseSource{
+			Name:        "schema-test-cn",
+			Interval:    3600,
+			Categories:  []string{"科技"},
+			Description: "schema test source in China",
+			Country:     "CN",
+			Language:    "zh",
+		},
+		items: []models.Item{{ID: "schema-test-cn-1", Title: "schema test item"}},
+	})
+}
+
+func TestEngineSchema_SelectSources(t *testing.T) {
+	selected, err := selectSources(SourcesConfig{
+		Categories: []string{"schema-test"},
+		Disabled:   []string{"schema-test-b"},
+	})
+	if err != nil {
+		t.Fatalf("选择数据源失败: %v", err)
+	}
+	if len(selected) != 1 || selected[0].GetName() != "schema-test-a" {
+		t.Errorf("期望只选中schema-test-a，实际为: %v", selected)
+	}
+
+	config := Config{Sources: SourcesConfig{Overrides: map[string]SourceOverride{
+		"schema-test-a": {Interval: 60},
+	}}}
+	source, err := configureSource(config, selected[0])
+	if err != nil {
+		t.Fatalf("配置数据源失败: %v", err)
+	}
+	if source.GetInterval() != 60 {
+		t.Errorf("期望爬取间隔被覆盖为60，实际为: %d", source.GetInterval())
+	}
+}
+
+func TestEngineSchema_SourceConfig(t *testing.T) {
+	source, err := sources.GetRegistry().Get("schema-test-b")
+	if err != nil {
+		t.Fatalf("获取数据源失败: %v", err)
+	}
+
+	config := Config{Sources: SourcesConfig{Overrides: map[string]SourceOverride{
+		"schema-test-b": {Config: map[string]string{"cookie": "session=1"}},
+	}}}
+	configured, err := configureSource(config, source)
+	if err != nil {
+		t.Fatalf("配置数据源失败: %v", err)
+	}
+	if cookie := configured.(*testSource).Cookie; cookie != "session=1" {
+		t.Errorf("期望Cookie为session=1，实际为: %q", cookie)
+	}
+	// 配置只作用于引擎使用的副本，全局注册表中的数据源不变
+	if cookie := source.(*testSource).Cookie; cookie != "" {
+		t.Errorf("期望注册表中的数据源不被修改，实际Cookie为: %q", cookie)
+	}
+	if saved := sources.GetRegistry().Config("schema-test-b"); saved != nil {
+		t.Errorf("期望不修改注册表保存的配置，实际为: %v", saved)
+	}
+
+	configured, err = configureSource(Config{Timeout: time.Second}, source)
+	if err != nil {
+		t.Fatalf("配置数据源失败: %v", err)
+	}
+	if configured.(*testSource).Client == nil || source.(*testSource).Client != nil {
+		t.Error("期望只为副本设置HTTP客户端")
+	}
+
+	config.Sources.Overrides["schema-test-b"] = SourceOverride{Config: map[string]string{"timeout": "later"}}
+	if _, err := configureSource(config, source); err == nil {
+		t.Error("期望不合法的配置值返回错误")
+	}
+}
+
+func TestEngineSchema_Validate(t *testing.T) {
+	config := `
+sources:
+  enabled: [schema-test-a, missing-source]
+  overrides:
+    schema-test-a:
+      interval: -1
+
+cache:
+  backend: redis
+  max_items: -1
+
+proxy: "not a proxy"
+
+notifier:
+  telegram:
+    enabled: true
+    bot_token: "token"
+  routes:
+    - sources: [missing-source]
+      title: "{{.Missing}}"
+`
+
+	schema := NewEngineSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	err := schema.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回ValidationError，实际为: %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = true
+	}
+	expected := []string{
+		"sources.enabled[1]",
+		"sources.overrides.schema-test-a.interval",
+		"cache.backend",
+		"cache.max_items",
+		"proxy",
+		"notifier.telegram.chat_id",
+		"notifier.routes[0].sources[0]",
+		"notifier.routes[0].title",
+	}
+	for _, field := range expected {
+		if !fields[field] {
+			t.Errorf("期望包含字段 %s 的错误，实际为: %v", field, err)
+		}
+	}
+	if len(validationErr.Fields) != len(expected) {
+		t.Errorf("期望%d个字段错误，实际为: %v", len(expected), err)
+	}
+
+	if _, err := schema.CreateEngine(); err == nil {
+		t.Error("配置不合法时CreateEngine应返回错误")
+	}
+}
+
+func TestLoadAndCreateEngine_Routes(t *testing.T) {
+	bodies := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	notifierConfig := `
+instances:
+  - name: hook
+    type: webhook
+    url: "` + server.URL + `"
+`
+	if err := os.WriteFile(filepath.Join(dir, "notifier.yaml"), []byte(notifierConfig), 0644); err != nil {
+		t.Fatalf("写入通知配置文件失败: %v", err)
+	}
+
+	config := `
+sources:
+  enabled: [schema-test-a, schema-test-b]
+
+cache:
+  backend: none
+
+notifier:
+  file: notifier.yaml
+  routes:
+    - sources: [schema-test-a]
+      channels: [hook]
+      title: "{{.Count}}条新数据 - {{join .Sources \",\"}}"
+`
+	configPath := filepath.Join(dir, "crawler.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	engine, err := LoadAndCreateEngine(configPath)
+	if err != nil {
+		t.Fatalf("创建爬取引擎失败: %v", err)
+	}
+	if err := engine.Start(context.Background()); err != nil {
+		t.Fatalf("启动爬取引擎失败: %v", err)
+	}
+	defer engine.Stop()
+
+	select {
+	case body := <-bodies:
+		if !strings.Contains(body, "schema-test-a item") {
+			t.Errorf("期望通知schema-test-a的数据，实际为: %s", body)
+		}
+		if !strings.Contains(body, `"title":"1条新数据 - schema-test-a"`) {
+			t.Errorf("期望使用路由的标题模板，实际为: %s", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("等待通知超时")
+	}
+
+	// schema-test-b 没有匹配的路由，不应发送通知
+	select {
+	case body := <-bodies:
+		t.Errorf("不应收到其他通知，实际为: %s", body)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	items, err := engine.FetchItem(context.Background(), "schema-test-b")
+	if err != nil || len(items) != 1 {
+		t.Errorf("期望获取schema-test-b的1条数据，实际为: %v, %v", items, err)
+	}
+}
+
+func TestCreateEngine_OverrideKeepsMetadata(t *testing.T) {
+	schema := NewEngineSchema()
+	config := `
+sources:
+  enabled: [schema-test-cn]
+  overrides:
+    schema-test-cn:
+      interval: 7200
+
+cache:
+  backend: none
+
+countries: [CN]
+`
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	engine, err := schema.CreateEngine()
+	if err != nil {
+		t.Fatalf("创建爬取引擎失败: %v", err)
+	}
+
+	status := engine.Status()
+	if len(status) != 1 || status[0].Interval != 7200 || status[0].Country != "CN" || status[0].Description == "" {
+		t.Errorf("期望覆盖爬取间隔后保留数据源的地区和简介，实际为: %+v", status)
+	}
+
+	// 按地区过滤时，覆盖了爬取间隔的数据源的数据项不应被丢弃
+	items, err := engine.FetchItem(context.Background(), "schema-test-cn")
+	if err != nil || len(items) != 1 || items[0].Country != "CN" || items[0].Language != "zh" {
+		t.Errorf("期望获取1条CN地区的数据，实际为: %+v, %v", items, err)
+	}
+}
@@ -0,0 +1,266 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/sjzsdu/utils/crawler/pkg/crawler"
+	"github.com/sjzsdu/utils/crawler/pkg/models"
+	"github.com/sjzsdu/utils/crawler/pkg/taxonomy"
+	"github.com/sjzsdu/utils/logging"
+	"github.com/sjzsdu/utils/notifier"
+)
+
+// subscriberBuffer 路由订阅通道的缓冲大小
+const subscriberBuffer = 10
+
+// route 解析后的路由规则
+type route struct {
+	sources    []string
+	categories []string
+	// channels 为空时发送到所有渠道
+	channels []string
+	// title 通知标题模板，为nil时使用默认标题
+	title *notifier.TitleTemplate
+}
+
+// target 数据源的通知目标，由匹配的所有路由规则合并而来
+type target struct {
+	// all 为true时发送到所有渠道，使用 title 作为标题模板
+	all   bool
+	title *notifier.TitleTemplate
+	// channels 发送的渠道，titles 为各渠道的标题模板，同一渠道匹配多条路由时使用第一条路由的模板
+	channels []string
+	titles   map[string]*notifier.TitleTemplate
+}
+
+// matches 判断数据源是否匹配路由规则
+func (r route) matches(source crawler.Source) bool {
+	if len(r.sources) == 0 && len(r.categories) == 0 {
+		return true
+	}
+	if slices.Contains(r.sources, source.GetName()) {
+		return true
+	}
+	for _, category := range source.GetCategories() {
+		if slices.ContainsFunc(r.categories, func(c string) bool { return taxonomy.Match(c, category) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRoutes 解析路由规则并检查引用的渠道已注册，没有路由规则时所有数据源发送到所有渠道
+func resolveRoutes(configs []RouteConfig, manager *notifier.NotifierManager) ([]route, error) {
+	if len(configs) == 0 {
+		return []route{{}}, nil
+	}
+
+	routes := make([]route, len(configs))
+	for i, config := range configs {
+		for j, channel := range config.Channels {
+			if _, ok := manager.GetNotifier(channel); !ok {
+				return nil, fmt.Errorf("notifier.routes[%d].channels[%d]: 通知渠道 %q 未启用或不存在", i, j, channel)
+			}
+		}
+		title, err := notifier.ParseTitleTemplate(config.Title)
+		if err != nil {
+			return nil, fmt.Errorf("notifier.routes[%d].title: %w", i, err)
+		}
+		routes[i] = route{sources: config.Sources, categories: config.Categories, channels: config.Channels, title: title}
+	}
+	return routes, nil
+}
+
+// configuredEngine 按配置创建的爬取引擎，在引擎之上负责通知路由和缓存的释放
+type configuredEngine struct {
+	crawler.ControllableEngine
+
+	sources    []crawler.Source
+	manager    *notifier.NotifierManager
+	routes     []route
+	closeCache func()
+	logger     logging.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	subs   map[string]chan []models.Item
+}
+
+// Start 订阅需要通知的数据源后启动爬取引擎
+func (e *configuredEngine) Start(ctx context.Context) error {
+	e.mu.Lock()
+	if e.manager != nil && e.cancel == nil {
+		routeCtx, cancel := context.WithCancel(ctx)
+		e.cancel = cancel
+		e.subs = make(map[string]chan []models.Item)
+
+		for _, source := range e.sources {
+			t, ok := e.targetFor(source)
+			if !ok {
+				continue
+			}
+
+			name := source.GetName()
+			ch := make(chan []models.Item, subscriberBuffer)
+			if err := e.Subscribe(name, ch); err != nil {
+				e.mu.Unlock()
+				return err
+			}
+			e.subs[name] = ch
+
+			e.wg.Add(1)
+			go e.forward(routeCtx, source, ch, t)
+		}
+	}
+	e.mu.Unlock()
+
+	return e.ControllableEngine.Start(ctx)
+}
+
+// Stop 停止爬取引擎和通知路由，发送摘要模式中累积的通知，并释放缓存
+func (e *configuredEngine) Stop() error {
+	err := e.ControllableEngine.Stop()
+
+	e.mu.Lock()
+	if e.cancel != nil {
+		e.cancel()
+		for name, ch := range e.subs {
+			e.Unsubscribe(name, ch)
+		}
+		e.subs = nil
+	}
+	e.mu.Unlock()
+
+	e.wg.Wait()
+	if e.manager != nil {
+		if err := e.manager.Close(); err != nil {
+			e.logger.Error("failed to close notifiers", logging.KeyError, err)
+		}
+	}
+	e.closeCache()
+	return err
+}
+
+// targetFor 返回数据源的通知目标，ok为false表示不需要通知
+func (e *configuredEngine) targetFor(source crawler.Source) (t target, ok bool) {
+	t.titles = make(map[string]*notifier.TitleTemplate)
+	for _, r := range e.routes {
+		if !r.matches(source) {
+			continue
+		}
+		ok = true
+		if len(r.channels) == 0 && !t.all {
+			t.all = true
+			t.title = r.title
+		}
+		for _, channel := range r.channels {
+			if !slices.Contains(t.channels, channel) {
+				t.channels = append(t.channels, channel)
+				t.titles[channel] = r.title
+			}
+		}
+	}
+	return t, ok
+}
+
+// forward 将数据源的新数据发送到通知渠道
+// 只发送与上一次结果相比新出现的数据项，避免每次爬取都重复通知；
+// 数据项没有来源或分类时使用数据源的名称和第一个分类，用于标题模板和消息分组
+func (e *configuredEngine) forward(ctx context.Context, source crawler.Source, ch <-chan []models.Item, t target) {
+	defer e.wg.Done()
+
+	name := source.GetName()
+	var category string
+	if categories := source.GetCategories(); len(categories) > 0 {
+		category = categories[0]
+	}
+
+	var previous map[string]bool
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case items := <-ch:
+			current := make(map[string]bool, len(items))
+			var messages []notifier.MessageItem
+			for _, item := range items {
+				key := itemKey(item)
+				current[key] = true
+				if !previous[key] {
+					if item.Source == "" {
+						item.Source = name
+					}
+					if item.Category == "" {
+						item.Category = category
+					}
+					messages = append(messages, itemMessage{item: item})
+				}
+			}
+			previous = current
+
+			if len(messages) > 0 {
+				e.send(name, messages, t)
+			}
+		}
+	}
+}
+
+// send 发送通知，发送失败时记录错误日志
+func (e *configuredEngine) send(sourceName string, messages []notifier.MessageItem, t target) {
+	if t.all {
+		ctx := notifier.WithTitleTemplate(context.Background(), t.title)
+		if _, err := e.manager.SendToAllContext(ctx, messages); err != nil {
+			e.logger.Error("failed to notify items", logging.KeySource, sourceName, logging.KeyError, err)
+		}
+		return
+	}
+
+	for _, channel := range t.channels {
+		ctx := notifier.WithTitleTemplate(context.Background(), t.titles[channel])
+		if _, err := e.manager.SendToSpecificContext(ctx, channel, messages); err != nil {
+			e.logger.Error("failed to notify items", logging.KeySource, sourceName, logging.KeyChannel, channel, logging.KeyError, err)
+		}
+	}
+}
+
+// itemKey 返回用于判断数据项是否重复的键
+func itemKey(item models.Item) string {
+	if item.ID != "" {
+		return item.ID
+	}
+	return item.URL
+}
+
+// itemMessage 将爬取的数据项适配为通知消息
+type itemMessage struct {
+	item models.Item
+}
+
+// Title 获取标题
+func (m itemMessage) Title() string {
+	return m.item.Title
+}
+
+// URL 获取链接
+func (m itemMessage) URL() string {
+	return m.item.URL
+}
+
+// Content 获取内容
+func (m itemMessage) Content() string {
+	return m.item.Content
+}
+
+// Source 获取数据源名称，用于标题模板和按数据源分组
+func (m itemMessage) Source() string {
+	return m.item.Source
+}
+
+// Category 获取分类，用于按分类分组
+func (m itemMessage) Category() string {
+	return m.item.Category
+}
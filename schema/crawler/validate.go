@@ -0,0 +1,120 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/sjzsdu/utils/crawler/sources"
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/schema/internal/validate"
+	notifierschema "github.com/sjzsdu/utils/schema/notifier"
+)
+
+// FieldError 单个配置字段的校验错误
+type FieldError = validate.FieldError
+
+// ValidationError 配置校验错误，包含所有不合法的字段
+type ValidationError = validate.ValidationError
+
+// validSource 检查数据源已注册
+func validSource(v *validate.Validator, field, name string) {
+	if _, err := sources.GetRegistry().Get(name); err != nil {
+		v.Add(field, fmt.Sprintf("数据源 %q 不存在", name))
+	}
+}
+
+// validSourceConfig 检查设置了配置的数据源可以接收配置
+func validSourceConfig(v *validate.Validator, field, name string, config map[string]string) {
+	if len(config) == 0 {
+		return
+	}
+	source, err := sources.GetRegistry().Get(name)
+	if err != nil {
+		return
+	}
+	if _, ok := source.(sources.Configurable); !ok {
+		v.Add(field, fmt.Sprintf("数据源 %q 不支持配置", name))
+	}
+}
+
+// Validate 校验配置，一次返回全部问题
+// 返回的错误为 *ValidationError，其中记录了每个问题的字段路径；
+// 内联的通知渠道配置同样会被校验，字段路径以 notifier. 开头
+func (s *EngineSchema) Validate() error {
+	return validateConfig(s.currentConfig())
+}
+
+// validateConfig 校验爬虫配置
+func validateConfig(c Config) error {
+	v := &validate.Validator{}
+
+	for i, name := range c.Sources.Enabled {
+		validSource(v, fmt.Sprintf("sources.enabled[%d]", i), name)
+	}
+	for i, name := range c.Sources.Disabled {
+		validSource(v, fmt.Sprintf("sources.disabled[%d]", i), name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Sources.Overrides)) {
+		override := c.Sources.Overrides[name]
+		field := "sources.overrides." + name
+		validSource(v, field, name)
+		v.NonNegative(field+".interval", int64(override.Interval))
+		v.NonNegative(field+".timeout", int64(override.Timeout))
+		v.Proxy(field+".proxy", override.Proxy)
+		validSourceConfig(v, field+".config", name, override.Config)
+	}
+	if len(v.Errs) == 0 {
+		if selected, err := selectSources(c.Sources); err == nil && len(selected) == 0 {
+			v.Add("sources", "没有启用任何数据源")
+		}
+	}
+
+	switch c.Cache.Backend {
+	case "", CacheMemory, CacheNone:
+	default:
+		v.Add("cache.backend", fmt.Sprintf("不支持的缓存后端 %q", c.Cache.Backend))
+	}
+	v.NonNegative("cache.cleanup_interval", int64(c.Cache.CleanupInterval))
+	v.NonNegative("cache.max_bytes", c.Cache.MaxBytes)
+	v.NonNegative("cache.max_items", int64(c.Cache.MaxItems))
+	v.NonNegative("cache.item_ttl", int64(c.Cache.ItemTTL))
+
+	v.Proxy("proxy", c.Proxy)
+	v.NonNegative("timeout", int64(c.Timeout))
+
+	if c.Notifier != nil {
+		validateNotifier(v, c.Notifier)
+	}
+
+	return v.Err()
+}
+
+// validateNotifier 校验通知配置和路由规则
+func validateNotifier(v *validate.Validator, c *NotifierConfig) {
+	if c.File == "" {
+		err := notifierschema.NewManagerSchemaFromConfig(c.Config).Validate()
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			for _, field := range validationErr.Fields {
+				v.Add("notifier."+field.Field, field.Message)
+			}
+		}
+	}
+
+	for i, route := range c.Routes {
+		field := fmt.Sprintf("notifier.routes[%d]", i)
+		for j, name := range route.Sources {
+			validSource(v, fmt.Sprintf("%s.sources[%d]", field, j), name)
+		}
+		for j, channel := range route.Channels {
+			if channel == "" {
+				v.Add(fmt.Sprintf("%s.channels[%d]", field, j), "不能为空")
+			}
+		}
+		if _, err := notifier.ParseTitleTemplate(route.Title); err != nil {
+			v.Add(field+".title", err.Error())
+		}
+	}
+}
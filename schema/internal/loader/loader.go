@@ -0,0 +1,65 @@
+// Package loader 提供schema包共用的配置解析工具
+package loader
+
+import (
+	"os"
+	"regexp"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// envPattern 匹配 ${VAR} 和 ${VAR:-default} 形式的环境变量引用
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// ExpandEnv 展开字符串中的环境变量引用
+// 支持 ${VAR} 和 ${VAR:-default} 两种写法，变量未设置或为空时使用默认值；
+// 不展开 $VAR 形式，避免误伤密码等包含 $ 的配置值
+func ExpandEnv(s string) string {
+	return envPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := envPattern.FindStringSubmatch(match)
+		if value := os.Getenv(parts[1]); value != "" {
+			return value
+		}
+		return parts[3]
+	})
+}
+
+// UnmarshalYAML 解析YAML并展开所有字符串值中的环境变量引用
+// 在解析后的节点上展开，环境变量的值不会被当作YAML语法解释
+func UnmarshalYAML(data []byte, out any) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	if node.Kind == 0 {
+		// 空文档
+		return nil
+	}
+
+	expandNode(&node)
+	return node.Decode(out)
+}
+
+// TOMLToYAML 将TOML转换为YAML，使TOML配置可以复用YAML的字段标签和环境变量展开
+func TOMLToYAML(data []byte) ([]byte, error) {
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(raw)
+}
+
+// expandNode 递归展开节点中标量值的环境变量引用
+func expandNode(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && envPattern.MatchString(node.Value) {
+		node.Value = ExpandEnv(node.Value)
+		// 展开后的值按内容重新推断类型，使 ${PORT:-587} 可以解析为整数
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+	}
+	for _, child := range node.Content {
+		expandNode(child)
+	}
+}
@@ -0,0 +1,99 @@
+// Package validate 提供schema包共用的配置校验工具
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FieldError 单个配置字段的校验错误
+type FieldError struct {
+	// Field 字段路径，例如 telegram.bot_token
+	Field string
+	// Message 错误说明
+	Message string
+}
+
+// Error 实现error接口
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError 配置校验错误，包含所有不合法的字段
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error 实现error接口
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return fmt.Sprintf("配置校验失败: %s", strings.Join(messages, "; "))
+}
+
+// Validator 收集校验错误
+type Validator struct {
+	Errs []FieldError
+}
+
+// Add 记录一个字段错误
+func (v *Validator) Add(field, message string) {
+	v.Errs = append(v.Errs, FieldError{Field: field, Message: message})
+}
+
+// Required 检查字符串字段不为空
+func (v *Validator) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, "不能为空")
+	}
+}
+
+// RequiredList 检查列表字段不为空
+func (v *Validator) RequiredList(field string, values []string) {
+	if len(values) == 0 {
+		v.Add(field, "至少需要一个值")
+	}
+}
+
+// URL 检查字段为http或https地址，required为false时允许为空
+func (v *Validator) URL(field, value string, required bool) {
+	if value == "" {
+		if required {
+			v.Add(field, "不能为空")
+		}
+		return
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		v.Add(field, "不是有效的http(s)地址")
+	}
+}
+
+// Proxy 检查代理地址，允许为空
+func (v *Validator) Proxy(field, value string) {
+	if value == "" {
+		return
+	}
+	if parsed, err := url.Parse(value); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		v.Add(field, "不是有效的代理地址")
+	}
+}
+
+// NonNegative 检查数值字段不为负数
+func (v *Validator) NonNegative(field string, value int64) {
+	if value < 0 {
+		v.Add(field, "不能为负数")
+	}
+}
+
+// Err 返回收集到的校验错误，没有错误时返回nil
+func (v *Validator) Err() error {
+	if len(v.Errs) > 0 {
+		return &ValidationError{Fields: v.Errs}
+	}
+	return nil
+}
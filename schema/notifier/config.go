@@ -1,11 +1,19 @@
 package notifier
 
 import (
+	"fmt"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sjzsdu/utils/notifier"
 	"github.com/sjzsdu/utils/notifier/dingtalk"
 	"github.com/sjzsdu/utils/notifier/email"
 	"github.com/sjzsdu/utils/notifier/feishu"
 	"github.com/sjzsdu/utils/notifier/ntfy"
 	"github.com/sjzsdu/utils/notifier/sms"
+	"github.com/sjzsdu/utils/notifier/telegram"
 	"github.com/sjzsdu/utils/notifier/webhook"
 	"github.com/sjzsdu/utils/notifier/wecom"
 )
@@ -17,6 +25,145 @@ type Config struct {
 	Feishu   *feishu.FeishuNotifierConfig     `yaml:"feishu" json:"feishu"`
 	NTFY     *ntfy.NtfyNotifierConfig         `yaml:"ntfy" json:"ntfy"`
 	SMS      *sms.SMSNotifierConfig           `yaml:"sms" json:"sms"`
+	Telegram *telegram.TelegramNotifierConfig `yaml:"telegram" json:"telegram"`
 	Webhook  *webhook.WebhookNotifierConfig   `yaml:"webhook" json:"webhook"`
 	Wecom    *wecom.WecomNotifierConfig       `yaml:"wecom" json:"wecom"`
+
+	// Instances 命名的通知器实例，同一类型可以配置多个，例如两个Telegram群组
+	Instances []InstanceConfig `yaml:"instances" json:"instances"`
+
+	// Digest 按渠道名称配置的摘要模式，配置了的渠道累积消息后按时间或数量合并发送
+	Digest map[string]DigestConfig `yaml:"digest" json:"digest"`
+
+	// GroupBy 消息正文中资讯的分组方式，source 按数据源、category 按分类分组，为空时不分组
+	GroupBy notifier.GroupBy `yaml:"group_by" json:"group_by"`
+
+	// AuditLog 审计日志文件，以JSON Lines格式记录每次发送尝试，为空时不记录
+	AuditLog string `yaml:"audit_log" json:"audit_log"`
+}
+
+// DigestConfig 单个渠道的摘要模式配置，interval 和 max_items 至少配置一个：
+//
+//	digest:
+//	  telegram:
+//	    interval: 30m
+//	    max_items: 20
+type DigestConfig struct {
+	// Interval 定时合并发送的间隔
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	// MaxItems 累积的消息达到该数量时立即发送
+	MaxItems int `yaml:"max_items" json:"max_items"`
+}
+
+// InstanceConfig 命名的通知器实例配置
+// 除name和type外，其余字段与对应类型的渠道配置相同，enabled默认为true：
+//
+//	instances:
+//	  - name: ops-telegram
+//	    type: telegram
+//	    bot_token: "${OPS_BOT_TOKEN}"
+//	    chat_id: "-100123"
+type InstanceConfig struct {
+	// Name 实例名称，用于在NotifierManager中查找和路由
+	Name string
+	// Type 通知器类型，例如 telegram、webhook
+	Type string
+
+	node yaml.Node
+}
+
+// UnmarshalYAML 解析实例的名称和类型，保留原始节点用于按类型解析具体配置
+func (c *InstanceConfig) UnmarshalYAML(value *yaml.Node) error {
+	var header struct {
+		Name string `yaml:"name"`
+		Type string `yaml:"type"`
+	}
+	if err := value.Decode(&header); err != nil {
+		return err
+	}
+
+	c.Name = header.Name
+	c.Type = header.Type
+	c.node = *value
+	return nil
+}
+
+// InstanceTypes 支持配置为命名实例的通知器类型
+var InstanceTypes = []string{"dingtalk", "email", "feishu", "ntfy", "sms", "telegram", "webhook", "wecom"}
+
+// decode 按类型解析实例的具体配置
+func (c *InstanceConfig) decode() (any, error) {
+	var config any
+	switch c.Type {
+	case "dingtalk":
+		config = &dingtalk.DingtalkNotifierConfig{Enabled: true}
+	case "email":
+		config = &email.EmailNotifierConfig{Enabled: true}
+	case "feishu":
+		config = &feishu.FeishuNotifierConfig{Enabled: true}
+	case "ntfy":
+		config = &ntfy.NtfyNotifierConfig{Enabled: true}
+	case "sms":
+		config = &sms.SMSNotifierConfig{Enabled: true}
+	case "telegram":
+		config = &telegram.TelegramNotifierConfig{Enabled: true}
+	case "webhook":
+		config = &webhook.WebhookNotifierConfig{Enabled: true}
+	case "wecom":
+		config = &wecom.WecomNotifierConfig{Enabled: true}
+	default:
+		return nil, fmt.Errorf("不支持的通知器类型 %q", c.Type)
+	}
+
+	if err := c.node.Decode(config); err != nil {
+		return nil, fmt.Errorf("解析实例配置失败: %w", err)
+	}
+	return config, nil
+}
+
+// channelConfig 单个渠道的配置
+type channelConfig struct {
+	// name 注册到NotifierManager的名称
+	name string
+	// field 配置中的字段路径，用于校验错误
+	field string
+	// config 具体的渠道配置，为各通知器包中的配置结构体指针
+	config any
+}
+
+// channels 返回按类型配置的渠道和所有命名实例，无法解析的实例以字段错误的形式返回
+func (c Config) channels() ([]channelConfig, []FieldError) {
+	var channels []channelConfig
+	add := func(name string, config any, ok bool) {
+		if ok {
+			channels = append(channels, channelConfig{name: name, field: name, config: config})
+		}
+	}
+	add("dingtalk", c.Dingtalk, c.Dingtalk != nil)
+	add("email", c.Email, c.Email != nil)
+	add("feishu", c.Feishu, c.Feishu != nil)
+	add("ntfy", c.NTFY, c.NTFY != nil)
+	add("sms", c.SMS, c.SMS != nil)
+	add("telegram", c.Telegram, c.Telegram != nil)
+	add("webhook", c.Webhook, c.Webhook != nil)
+	add("wecom", c.Wecom, c.Wecom != nil)
+
+	var errs []FieldError
+	for i := range c.Instances {
+		instance := &c.Instances[i]
+		field := fmt.Sprintf("instances[%d]", i)
+		if !slices.Contains(InstanceTypes, instance.Type) {
+			errs = append(errs, FieldError{Field: field + ".type", Message: fmt.Sprintf("不支持的通知器类型 %q", instance.Type)})
+			continue
+		}
+
+		config, err := instance.decode()
+		if err != nil {
+			errs = append(errs, FieldError{Field: field, Message: err.Error()})
+			continue
+		}
+		channels = append(channels, channelConfig{name: instance.Name, field: field, config: config})
+	}
+
+	return channels, errs
 }
@@ -0,0 +1,12 @@
+package notifier
+
+import (
+	"github.com/sjzsdu/utils/schema/internal/loader"
+)
+
+// ExpandEnv 展开字符串中的环境变量引用
+// 支持 ${VAR} 和 ${VAR:-default} 两种写法，变量未设置或为空时使用默认值；
+// 不展开 $VAR 形式，避免误伤密码等包含 $ 的配置值
+func ExpandEnv(s string) string {
+	return loader.ExpandEnv(s)
+}
@@ -1,10 +1,12 @@
 package notifier
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-
-	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/sjzsdu/utils/notifier"
 	"github.com/sjzsdu/utils/notifier/dingtalk"
@@ -12,12 +14,15 @@ import (
 	"github.com/sjzsdu/utils/notifier/feishu"
 	"github.com/sjzsdu/utils/notifier/ntfy"
 	"github.com/sjzsdu/utils/notifier/sms"
+	"github.com/sjzsdu/utils/notifier/telegram"
 	"github.com/sjzsdu/utils/notifier/webhook"
 	"github.com/sjzsdu/utils/notifier/wecom"
+	"github.com/sjzsdu/utils/schema/internal/loader"
 )
 
 // ManagerSchema 管理通知器的schema
 type ManagerSchema struct {
+	mu     sync.RWMutex
 	config Config
 }
 
@@ -26,100 +31,189 @@ func NewManagerSchema() *ManagerSchema {
 	return &ManagerSchema{}
 }
 
-// LoadFromFile 从配置文件加载配置
+// NewManagerSchemaFromConfig 使用已解析的配置创建ManagerSchema，
+// 用于通知器配置嵌入在其他配置文件中的场景
+func NewManagerSchemaFromConfig(config Config) *ManagerSchema {
+	return &ManagerSchema{config: config}
+}
+
+// LoadFromFile 从配置文件加载配置，配置值中的 ${VAR} 和 ${VAR:-default} 会从环境变量展开
+// 根据扩展名选择格式：.json 为JSON，.toml 为TOML，其余按YAML解析
 func (s *ManagerSchema) LoadFromFile(filePath string) error {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("读取配置文件失败: %w", err)
 	}
 
-	if err := yaml.Unmarshal(content, &s.config); err != nil {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		return s.LoadFromJSON(content)
+	case ".toml":
+		return s.LoadFromTOML(content)
+	}
+
+	if err := s.unmarshal(content); err != nil {
 		return fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
 	return nil
 }
 
-// LoadFromBytes 从字节数组加载配置
+// LoadFromBytes 从字节数组加载配置，配置值中的环境变量引用同样会被展开
 func (s *ManagerSchema) LoadFromBytes(data []byte) error {
-	if err := yaml.Unmarshal(data, &s.config); err != nil {
+	if err := s.unmarshal(data); err != nil {
 		return fmt.Errorf("解析配置失败: %w", err)
 	}
 
 	return nil
 }
 
-// CreateNotifierManager 根据配置创建NotifierManager
+// LoadFromJSON 从JSON加载配置，字段名与YAML配置相同
+func (s *ManagerSchema) LoadFromJSON(data []byte) error {
+	if !json.Valid(data) {
+		return fmt.Errorf("解析JSON配置失败: 格式无效")
+	}
+
+	// JSON是YAML的子集，统一按YAML解析以复用字段标签和环境变量展开
+	if err := s.unmarshal(data); err != nil {
+		return fmt.Errorf("解析JSON配置失败: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromTOML 从TOML加载配置，字段名与YAML配置相同
+func (s *ManagerSchema) LoadFromTOML(data []byte) error {
+	// 转换为YAML后解析，复用字段标签和环境变量展开
+	converted, err := loader.TOMLToYAML(data)
+	if err != nil {
+		return fmt.Errorf("解析TOML配置失败: %w", err)
+	}
+	if err := s.unmarshal(converted); err != nil {
+		return fmt.Errorf("解析TOML配置失败: %w", err)
+	}
+
+	return nil
+}
+
+// unmarshal 将YAML格式的配置合并到当前配置
+func (s *ManagerSchema) unmarshal(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return loader.UnmarshalYAML(data, &s.config)
+}
+
+// currentConfig 返回当前配置
+func (s *ManagerSchema) currentConfig() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// CreateNotifierManager 根据配置创建NotifierManager，创建前会先校验配置
 func (s *ManagerSchema) CreateNotifierManager() (*notifier.NotifierManager, error) {
-	manager, err := notifier.NewNotifierManager()
+	config := s.currentConfig()
+	notifiers, err := createNotifiers(config)
 	if err != nil {
 		return nil, err
 	}
 
-	// 创建并注册钉钉通知器
-	if s.config.Dingtalk != nil {
-		dingtalkNotifier, err := dingtalk.NewNotifier(s.config.Dingtalk)
+	manager, err := notifier.NewNotifierManager()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notifiers {
+		manager.RegisterNotifier(n.Name, n.Notifier)
+	}
+	manager.SetGrouping(config.GroupBy)
+	if config.AuditLog != "" {
+		audit, err := notifier.NewJSONLAuditLog(config.AuditLog)
 		if err != nil {
-			return nil, fmt.Errorf("创建钉钉通知器失败: %w", err)
+			return nil, err
 		}
-		manager.RegisterNotifier("dingtalk", dingtalkNotifier)
+		manager.SetAuditLog(audit)
 	}
 
-	// 创建并注册邮件通知器
-	if s.config.Email != nil {
-		emailNotifier, err := email.NewNotifier(s.config.Email)
+	return manager, nil
+}
+
+// createNotifiers 校验配置并创建所有配置了的通知器
+func createNotifiers(config Config) ([]notifier.NamedNotifier, error) {
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	channels, _ := config.channels()
+
+	notifiers := make([]notifier.NamedNotifier, 0, len(channels))
+	for _, channel := range channels {
+		n, err := newChannelNotifier(channel.config)
 		if err != nil {
-			return nil, fmt.Errorf("创建邮件通知器失败: %w", err)
+			return nil, fmt.Errorf("%s: %w", channel.name, err)
 		}
-		manager.RegisterNotifier("email", emailNotifier)
+		if digest, ok := config.Digest[channel.name]; ok && n.IsEnabled() {
+			n = notifier.NewDigest(n, notifier.DigestOptions{Interval: digest.Interval, MaxItems: digest.MaxItems})
+		}
+		notifiers = append(notifiers, notifier.NamedNotifier{Name: channel.name, Notifier: n})
 	}
 
-	// 创建并注册飞书通知器
-	if s.config.Feishu != nil {
-		feishuNotifier, err := feishu.NewNotifier(s.config.Feishu)
+	return notifiers, nil
+}
+
+// newChannelNotifier 根据渠道配置的类型创建对应的通知器
+func newChannelNotifier(config any) (notifier.Notifier, error) {
+	switch c := config.(type) {
+	case *dingtalk.DingtalkNotifierConfig:
+		n, err := dingtalk.NewNotifier(c)
+		if err != nil {
+			return nil, fmt.Errorf("创建钉钉通知器失败: %w", err)
+		}
+		return n, nil
+	case *email.EmailNotifierConfig:
+		n, err := email.NewNotifier(c)
+		if err != nil {
+			return nil, fmt.Errorf("创建邮件通知器失败: %w", err)
+		}
+		return n, nil
+	case *feishu.FeishuNotifierConfig:
+		n, err := feishu.NewNotifier(c)
 		if err != nil {
 			return nil, fmt.Errorf("创建飞书通知器失败: %w", err)
 		}
-		manager.RegisterNotifier("feishu", feishuNotifier)
-	}
-
-	// 创建并注册NTFY通知器
-	if s.config.NTFY != nil {
-		ntfyNotifier, err := ntfy.NewNtfyNotifier(s.config.NTFY)
+		return n, nil
+	case *ntfy.NtfyNotifierConfig:
+		n, err := ntfy.NewNtfyNotifier(c)
 		if err != nil {
 			return nil, fmt.Errorf("创建NTFY通知器失败: %w", err)
 		}
-		manager.RegisterNotifier("ntfy", ntfyNotifier)
-	}
-
-	// 创建并注册短信通知器
-	if s.config.SMS != nil {
-		smsNotifier, err := sms.NewNotifier(s.config.SMS)
+		return n, nil
+	case *sms.SMSNotifierConfig:
+		n, err := sms.NewNotifier(c)
 		if err != nil {
 			return nil, fmt.Errorf("创建短信通知器失败: %w", err)
 		}
-		manager.RegisterNotifier("sms", smsNotifier)
-	}
-
-	// 创建并注册Webhook通知器
-	if s.config.Webhook != nil {
-		webhookNotifier, err := webhook.NewNotifier(s.config.Webhook)
+		return n, nil
+	case *telegram.TelegramNotifierConfig:
+		n, err := telegram.NewTelegramNotifier(c)
+		if err != nil {
+			return nil, fmt.Errorf("创建Telegram通知器失败: %w", err)
+		}
+		return n, nil
+	case *webhook.WebhookNotifierConfig:
+		n, err := webhook.NewNotifier(c)
 		if err != nil {
 			return nil, fmt.Errorf("创建Webhook通知器失败: %w", err)
 		}
-		manager.RegisterNotifier("webhook", webhookNotifier)
-	}
-
-	// 创建并注册企业微信通知器
-	if s.config.Wecom != nil {
-		wecomNotifier, err := wecom.NewNotifier(s.config.Wecom)
+		return n, nil
+	case *wecom.WecomNotifierConfig:
+		n, err := wecom.NewNotifier(c)
 		if err != nil {
 			return nil, fmt.Errorf("创建企业微信通知器失败: %w", err)
 		}
-		manager.RegisterNotifier("wecom", wecomNotifier)
+		return n, nil
+	default:
+		return nil, fmt.Errorf("不支持的通知器配置类型 %T", config)
 	}
-
-	return manager, nil
 }
 
 // LoadAndCreateNotifierManager 从配置文件加载配置并创建NotifierManager
@@ -1,8 +1,18 @@
 package notifier
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/sjzsdu/utils/notifier"
 )
 
 const testConfig = `
@@ -103,3 +113,494 @@ dingtalk:
 		t.Errorf("期望获取到dingtalk渠道，实际获取到: %s", channels[0])
 	}
 }
+
+func TestManagerSchema_ExpandEnv(t *testing.T) {
+	t.Setenv("TEST_DINGTALK_SECRET", "secret_from_env")
+	t.Setenv("TEST_EMAIL_PASSWORD", "pa$$word")
+	t.Setenv("TEST_SMTP_PORT", "465")
+
+	config := `
+dingtalk:
+  enabled: true
+  webhook_url: "${TEST_DINGTALK_WEBHOOK:-https://oapi.dingtalk.com/robot/send?access_token=default}"
+  secret: "${TEST_DINGTALK_SECRET}"
+  message_type: "text"
+
+email:
+  enabled: false
+  smtp_host: "smtp.example.com"
+  smtp_port: ${TEST_SMTP_PORT:-587}
+  password: "${TEST_EMAIL_PASSWORD}"
+`
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	if got := schema.config.Dingtalk.Secret; got != "secret_from_env" {
+		t.Errorf("期望secret从环境变量展开为 secret_from_env，实际为: %s", got)
+	}
+	if got := schema.config.Dingtalk.WebhookURL; got != "https://oapi.dingtalk.com/robot/send?access_token=default" {
+		t.Errorf("环境变量未设置时应使用默认值，实际为: %s", got)
+	}
+	if got := schema.config.Email.SMTPPort; got != 465 {
+		t.Errorf("期望smtp_port展开为465，实际为: %d", got)
+	}
+	if got := schema.config.Email.Password; got != "pa$$word" {
+		t.Errorf("环境变量的值不应被再次展开，实际为: %s", got)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("TEST_EXPAND_SET", "value")
+	t.Setenv("TEST_EXPAND_EMPTY", "")
+
+	cases := map[string]string{
+		"${TEST_EXPAND_SET}":               "value",
+		"prefix-${TEST_EXPAND_SET}-suffix": "prefix-value-suffix",
+		"${TEST_EXPAND_UNSET}":             "",
+		"${TEST_EXPAND_UNSET:-default}":    "default",
+		"${TEST_EXPAND_EMPTY:-default}":    "default",
+		"$TEST_EXPAND_SET":                 "$TEST_EXPAND_SET",
+	}
+	for input, expected := range cases {
+		if got := ExpandEnv(input); got != expected {
+			t.Errorf("ExpandEnv(%q) = %q，期望 %q", input, got, expected)
+		}
+	}
+}
+
+func TestManagerSchema_LoadFromJSON(t *testing.T) {
+	config := `{
+  "telegram": {
+    "enabled": true,
+    "bot_token": "test_token",
+    "chat_id": "12345"
+  }
+}`
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromJSON([]byte(config)); err != nil {
+		t.Fatalf("从JSON加载配置失败: %v", err)
+	}
+
+	manager, err := schema.CreateNotifierManager()
+	if err != nil {
+		t.Fatalf("创建NotifierManager失败: %v", err)
+	}
+	if channels := manager.GetEnabledChannels(); len(channels) != 1 || channels[0] != "telegram" {
+		t.Errorf("期望获取到telegram渠道，实际获取到: %v", channels)
+	}
+
+	if err := schema.LoadFromJSON([]byte("{invalid")); err == nil {
+		t.Error("无效的JSON应返回错误")
+	}
+}
+
+func TestManagerSchema_LoadFromTOML(t *testing.T) {
+	t.Setenv("TEST_TOML_PORT", "465")
+
+	config := `
+[email]
+enabled = true
+smtp_host = "smtp.example.com"
+smtp_port = "${TEST_TOML_PORT:-587}"
+username = "test@example.com"
+password = "test_password"
+from = "test@example.com"
+to = ["user@example.com"]
+`
+
+	filePath := t.TempDir() + "/config.toml"
+	if err := os.WriteFile(filePath, []byte(config), 0644); err != nil {
+		t.Fatalf("创建临时配置文件失败: %v", err)
+	}
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromFile(filePath); err != nil {
+		t.Fatalf("从TOML文件加载配置失败: %v", err)
+	}
+	if schema.config.Email == nil || schema.config.Email.SMTPPort != 465 {
+		t.Fatalf("期望smtp_port为465，实际配置为: %+v", schema.config.Email)
+	}
+	if len(schema.config.Email.To) != 1 || schema.config.Email.To[0] != "user@example.com" {
+		t.Errorf("期望收件人为 user@example.com，实际为: %v", schema.config.Email.To)
+	}
+}
+
+func TestManagerSchema_Validate(t *testing.T) {
+	config := `
+dingtalk:
+  enabled: true
+  webhook_url: "not a url"
+
+telegram:
+  enabled: true
+  bot_token: "token"
+
+sms:
+  enabled: true
+  provider: "unknown"
+
+email:
+  enabled: true
+  provider: "mailgun"
+  api_key: "key"
+  from: "news@example.com"
+  to: ["user@example.com"]
+
+feishu:
+  enabled: false
+`
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	err := schema.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回ValidationError，实际为: %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = true
+	}
+	for _, expected := range []string{"dingtalk.webhook_url", "telegram.chat_id", "sms.provider", "sms.phone_numbers", "email.domain"} {
+		if !fields[expected] {
+			t.Errorf("期望包含字段 %s 的错误，实际为: %v", expected, err)
+		}
+	}
+	if len(validationErr.Fields) != 5 {
+		t.Errorf("期望5个字段错误，实际为: %v", err)
+	}
+
+	if _, err := schema.CreateNotifierManager(); err == nil {
+		t.Error("配置不合法时CreateNotifierManager应返回错误")
+	}
+}
+
+func TestManagerSchema_Instances(t *testing.T) {
+	config := `
+telegram:
+  enabled: true
+  bot_token: "token"
+  chat_id: "12345"
+
+instances:
+  - name: ops-telegram
+    type: telegram
+    bot_token: "ops-token"
+    chat_id: "-100"
+  - name: alerts-webhook
+    type: webhook
+    url: "https://example.com/hook"
+  - name: disabled-webhook
+    type: webhook
+    url: "https://example.com/disabled"
+    enabled: false
+`
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	manager, err := schema.CreateNotifierManager()
+	if err != nil {
+		t.Fatalf("创建NotifierManager失败: %v", err)
+	}
+
+	channels := manager.GetEnabledChannels()
+	expected := []string{"telegram", "ops-telegram", "alerts-webhook"}
+	if len(channels) != len(expected) {
+		t.Fatalf("期望启用的渠道为 %v，实际为: %v", expected, channels)
+	}
+	for i, name := range expected {
+		if channels[i] != name {
+			t.Errorf("期望启用的渠道为 %v，实际为: %v", expected, channels)
+			break
+		}
+	}
+
+	n, ok := manager.GetNotifier("ops-telegram")
+	if !ok || n.Name() != "telegram" {
+		t.Errorf("期望按名称找到telegram实例，实际为: %v, %v", n, ok)
+	}
+	if _, ok := manager.GetNotifier("disabled-webhook"); ok {
+		t.Error("未启用的实例不应注册")
+	}
+}
+
+func TestManagerSchema_ValidateInstances(t *testing.T) {
+	config := `
+telegram:
+  enabled: true
+  bot_token: "token"
+  chat_id: "12345"
+
+instances:
+  - name: telegram
+    type: telegram
+    bot_token: "token"
+  - type: webhook
+    url: "https://example.com/hook"
+  - name: unknown
+    type: pigeon
+`
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	err := schema.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回ValidationError，实际为: %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = true
+	}
+	for _, expected := range []string{"instances[0].name", "instances[0].chat_id", "instances[1].name", "instances[2].type"} {
+		if !fields[expected] {
+			t.Errorf("期望包含字段 %s 的错误，实际为: %v", expected, err)
+		}
+	}
+}
+
+func TestManagerSchema_Watch(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := tempDir + "/config.yaml"
+	initial := `
+dingtalk:
+  enabled: true
+  webhook_url: "https://oapi.dingtalk.com/robot/send?access_token=test"
+`
+	if err := os.WriteFile(filePath, []byte(initial), 0644); err != nil {
+		t.Fatalf("创建临时配置文件失败: %v", err)
+	}
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromFile(filePath); err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	manager, err := schema.CreateNotifierManager()
+	if err != nil {
+		t.Fatalf("创建NotifierManager失败: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan error, 10)
+	go schema.Watch(ctx, filePath, manager, WatchOptions{
+		Interval: 10 * time.Millisecond,
+		OnReload: func(err error) {
+			reloads <- err
+		},
+	})
+
+	waitReload := func() error {
+		select {
+		case err := <-reloads:
+			return err
+		case <-time.After(2 * time.Second):
+			t.Fatal("等待配置重新加载超时")
+			return nil
+		}
+	}
+	// 等待Watch读取初始配置
+	time.Sleep(50 * time.Millisecond)
+
+	// 不合法的配置不会替换通知器
+	invalid := `
+telegram:
+  enabled: true
+  bot_token: "token"
+`
+	if err := os.WriteFile(filePath, []byte(invalid), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	if err := waitReload(); err == nil {
+		t.Error("不合法的配置应返回错误")
+	}
+	if channels := manager.GetEnabledChannels(); len(channels) != 1 || channels[0] != "dingtalk" {
+		t.Errorf("配置不合法时应保留原有通知器，实际为: %v", channels)
+	}
+
+	// 合法的配置替换通知器
+	valid := `
+telegram:
+  enabled: true
+  bot_token: "token"
+  chat_id: "12345"
+audit_log: %q
+`
+	auditPath := tempDir + "/audit.jsonl"
+	if err := os.WriteFile(filePath, []byte(fmt.Sprintf(valid, auditPath)), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	if err := waitReload(); err != nil {
+		t.Fatalf("重新加载配置失败: %v", err)
+	}
+	if channels := manager.GetEnabledChannels(); len(channels) != 1 || channels[0] != "telegram" {
+		t.Errorf("期望通知器替换为telegram，实际为: %v", channels)
+	}
+
+	// 新增的 audit_log 在重新加载后生效
+	if _, err := manager.QueryAudit(ctx, notifier.AuditQuery{}); err != nil {
+		t.Errorf("期望重新加载后启用审计日志: %v", err)
+	}
+	if _, err := os.Stat(auditPath); err != nil {
+		t.Errorf("期望创建审计日志文件: %v", err)
+	}
+	manager.Close()
+}
+
+// testMessage 测试用的通知消息
+type testMessage string
+
+func (m testMessage) Title() string   { return string(m) }
+func (m testMessage) URL() string     { return "https://example.com/" + string(m) }
+func (m testMessage) Content() string { return "" }
+
+func TestManagerSchema_Digest(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	config := fmt.Sprintf(`
+webhook:
+  enabled: true
+  url: %q
+digest:
+  webhook:
+    max_items: 3
+`, server.URL)
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+	manager, err := schema.CreateNotifierManager()
+	if err != nil {
+		t.Fatalf("创建NotifierManager失败: %v", err)
+	}
+
+	// 未达到数量时只累积消息
+	result, err := manager.SendToSpecific("webhook", []notifier.MessageItem{testMessage("a"), testMessage("b")})
+	if err != nil || result.Status != notifier.StatusPending {
+		t.Fatalf("期望消息被累积，实际为: %+v, %v", result, err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("期望未发送请求，实际发送了 %d 次", requests.Load())
+	}
+
+	// 达到数量时合并发送
+	if _, err := manager.SendToSpecific("webhook", []notifier.MessageItem{testMessage("c")}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("期望合并发送1次，实际发送了 %d 次", requests.Load())
+	}
+
+	// 关闭时发送剩余的消息
+	manager.SendToSpecific("webhook", []notifier.MessageItem{testMessage("d")})
+	if err := manager.Close(); err != nil {
+		t.Fatalf("关闭失败: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("期望关闭时发送剩余消息，实际发送了 %d 次", requests.Load())
+	}
+}
+
+func TestManagerSchema_ValidateDigest(t *testing.T) {
+	config := `
+webhook:
+  enabled: true
+  url: "https://example.com/hook"
+digest:
+  webhook: {}
+  pigeon:
+    interval: 10m
+`
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	err := schema.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回ValidationError，实际为: %v", err)
+	}
+	if len(validationErr.Fields) != 2 || validationErr.Fields[0].Field != "digest.pigeon" || validationErr.Fields[1].Field != "digest.webhook" {
+		t.Errorf("期望 digest.pigeon 和 digest.webhook 的错误，实际为: %v", err)
+	}
+}
+
+func TestManagerSchema_ValidateLocale(t *testing.T) {
+	config := `
+webhook:
+  enabled: true
+  url: "https://example.com/hook"
+  locale: "fr-FR"
+ntfy:
+  enabled: true
+  topic: "news"
+  locale: "en-US"
+`
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	err := schema.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回ValidationError，实际为: %v", err)
+	}
+	if len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != "webhook.locale" {
+		t.Errorf("期望只有 webhook.locale 的错误，实际为: %v", err)
+	}
+}
+
+func TestManagerSchema_GroupBy(t *testing.T) {
+	config := `
+webhook:
+  enabled: true
+  url: "https://example.com/hook"
+group_by: date
+`
+
+	schema := NewManagerSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	err := schema.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回ValidationError，实际为: %v", err)
+	}
+	if len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != "group_by" {
+		t.Errorf("期望只有 group_by 的错误，实际为: %v", err)
+	}
+
+	if err := schema.LoadFromBytes([]byte(strings.Replace(config, "date", "source", 1))); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+	if _, err := schema.CreateNotifierManager(); err != nil {
+		t.Errorf("group_by 为 source 时应能创建NotifierManager: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package notifier
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/sjzsdu/utils/notifier"
+	"github.com/sjzsdu/utils/notifier/dingtalk"
+	"github.com/sjzsdu/utils/notifier/email"
+	"github.com/sjzsdu/utils/notifier/feishu"
+	"github.com/sjzsdu/utils/notifier/ntfy"
+	"github.com/sjzsdu/utils/notifier/sms"
+	"github.com/sjzsdu/utils/notifier/telegram"
+	"github.com/sjzsdu/utils/notifier/webhook"
+	"github.com/sjzsdu/utils/notifier/wecom"
+	"github.com/sjzsdu/utils/schema/internal/validate"
+)
+
+// FieldError 单个配置字段的校验错误
+type FieldError = validate.FieldError
+
+// ValidationError 配置校验错误，包含所有不合法的字段
+type ValidationError = validate.ValidationError
+
+// Validate 校验所有已启用渠道的配置，一次返回全部问题
+// 返回的错误为 *ValidationError，其中记录了每个问题的字段路径；未启用的渠道不做校验
+func (s *ManagerSchema) Validate() error {
+	return validateConfig(s.currentConfig())
+}
+
+// validateConfig 校验配置中所有已启用的渠道
+func validateConfig(c Config) error {
+	v := &validate.Validator{}
+
+	channels, errs := c.channels()
+	v.Errs = append(v.Errs, errs...)
+
+	names := make(map[string]string, len(channels))
+	for _, channel := range channels {
+		if channel.name == "" {
+			v.Add(channel.field+".name", "不能为空")
+		} else if previous, ok := names[channel.name]; ok {
+			v.Add(channel.field+".name", fmt.Sprintf("名称 %q 与 %s 重复", channel.name, previous))
+		} else {
+			names[channel.name] = channel.field
+		}
+
+		validateChannel(v, channel.field, channel.config)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Digest)) {
+		digest, field := c.Digest[name], "digest."+name
+		if _, ok := names[name]; !ok {
+			v.Add(field, fmt.Sprintf("通知渠道 %q 未配置", name))
+		}
+		if digest.Interval < 0 {
+			v.Add(field+".interval", "不能为负数")
+		}
+		if digest.MaxItems < 0 {
+			v.Add(field+".max_items", "不能为负数")
+		}
+		if digest.Interval <= 0 && digest.MaxItems <= 0 {
+			v.Add(field, "interval 和 max_items 至少需要配置一个")
+		}
+	}
+
+	if err := c.GroupBy.Validate(); err != nil {
+		v.Add("group_by", err.Error())
+	}
+
+	return v.Err()
+}
+
+// validateChannel 校验单个已启用渠道的配置，field为字段路径前缀
+func validateChannel(v *validate.Validator, field string, config any) {
+	switch c := config.(type) {
+	case *dingtalk.DingtalkNotifierConfig:
+		if c.Enabled {
+			v.URL(field+".webhook_url", c.WebhookURL, true)
+			v.Proxy(field+".proxy", c.Proxy)
+			validateLocale(v, field+".locale", c.Locale)
+		}
+
+	case *email.EmailNotifierConfig:
+		if c.Enabled {
+			switch c.Provider {
+			case "", email.ProviderSMTP:
+				v.Required(field+".smtp_host", c.SMTPHost)
+				if c.SMTPPort <= 0 || c.SMTPPort > 65535 {
+					v.Add(field+".smtp_port", "必须在1-65535之间")
+				}
+				v.Required(field+".username", c.Username)
+				v.Required(field+".password", c.Password)
+			case email.ProviderSendGrid:
+				v.Required(field+".api_key", c.APIKey)
+			case email.ProviderMailgun:
+				v.Required(field+".api_key", c.APIKey)
+				v.Required(field+".domain", c.Domain)
+			case email.ProviderSES:
+				v.Required(field+".access_key", c.AccessKey)
+				v.Required(field+".secret_key", c.SecretKey)
+				v.Required(field+".region", c.Region)
+			default:
+				v.Add(field+".provider", fmt.Sprintf("不支持的服务商 %q", c.Provider))
+			}
+			v.URL(field+".endpoint", c.Endpoint, false)
+			v.RequiredList(field+".to", c.To)
+			validateLocale(v, field+".locale", c.Locale)
+		}
+
+	case *feishu.FeishuNotifierConfig:
+		if c.Enabled {
+			v.URL(field+".webhook_url", c.WebhookURL, true)
+			v.Proxy(field+".proxy", c.Proxy)
+			validateLocale(v, field+".locale", c.Locale)
+		}
+
+	case *ntfy.NtfyNotifierConfig:
+		if c.Enabled {
+			v.Required(field+".topic", c.Topic)
+			v.URL(field+".server_url", c.ServerURL, false)
+			v.Proxy(field+".proxy", c.Proxy)
+			validateLocale(v, field+".locale", c.Locale)
+		}
+
+	case *sms.SMSNotifierConfig:
+		if c.Enabled {
+			switch c.Provider {
+			case "":
+				v.Add(field+".provider", "不能为空")
+			case "custom":
+				v.URL(field+".custom_api_url", c.CustomAPIURL, true)
+			case "aliyun", "tencent", "aws":
+				v.Required(field+".access_key", c.AccessKey)
+				v.Required(field+".secret_key", c.SecretKey)
+			default:
+				v.Add(field+".provider", fmt.Sprintf("不支持的服务商 %q", c.Provider))
+			}
+			v.RequiredList(field+".phone_numbers", c.PhoneNumbers)
+			validateLocale(v, field+".locale", c.Locale)
+		}
+
+	case *telegram.TelegramNotifierConfig:
+		if c.Enabled {
+			v.Required(field+".bot_token", c.BotToken)
+			v.Required(field+".chat_id", c.ChatID)
+			v.Proxy(field+".proxy", c.Proxy)
+			validateLocale(v, field+".locale", c.Locale)
+		}
+
+	case *webhook.WebhookNotifierConfig:
+		if c.Enabled {
+			v.URL(field+".url", c.URL, true)
+			if c.Timeout < 0 {
+				v.Add(field+".timeout", "不能为负数")
+			}
+			if c.RetryCount < 0 {
+				v.Add(field+".retry_count", "不能为负数")
+			}
+			validateLocale(v, field+".locale", c.Locale)
+		}
+
+	case *wecom.WecomNotifierConfig:
+		if c.Enabled {
+			v.URL(field+".webhook_url", c.WebhookURL, true)
+			v.Proxy(field+".proxy", c.Proxy)
+			validateLocale(v, field+".locale", c.Locale)
+		}
+	}
+}
+
+// validateLocale 校验通知内容的语言，未配置时使用默认语言
+func validateLocale(v *validate.Validator, field, locale string) {
+	if locale != "" && !notifier.Locale(locale).IsRegistered() {
+		v.Add(field, fmt.Sprintf("不支持的语言 %q，可选值为 %v", locale, notifier.Locales()))
+	}
+}
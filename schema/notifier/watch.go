@@ -0,0 +1,112 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sjzsdu/utils/notifier"
+)
+
+// DefaultWatchInterval 检查配置文件变化的默认间隔
+const DefaultWatchInterval = 2 * time.Second
+
+// WatchOptions 配置热更新的选项
+type WatchOptions struct {
+	// Interval 检查配置文件变化的间隔，小于等于0时使用 DefaultWatchInterval
+	Interval time.Duration
+	// OnReload 每次检测到变化并尝试重新加载后调用，err为nil表示新配置已生效
+	// 新配置解析或校验失败时保留原有的通知器，错误通过该回调报告
+	OnReload func(err error)
+}
+
+// Watch 监听配置文件的变化，文件内容改变后重新加载并校验配置，
+// 校验通过后原子地替换manager中的通知器，宿主程序无需重启
+// Watch 会阻塞直到上下文取消，通常在单独的goroutine中调用
+func (s *ManagerSchema) Watch(ctx context.Context, path string, manager *notifier.NotifierManager, opts WatchOptions) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	last, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			// 编辑器保存时文件可能短暂不存在，等待下一次检查
+			continue
+		}
+		if bytes.Equal(content, last) {
+			continue
+		}
+		last = content
+
+		err = s.reload(path, manager)
+		if opts.OnReload != nil {
+			opts.OnReload(err)
+		}
+	}
+}
+
+// reload 从文件加载新配置，校验通过后替换通知器和当前配置
+// audit_log 改变时打开新的审计日志文件并关闭原来的，改为空时停止记录
+func (s *ManagerSchema) reload(path string, manager *notifier.NotifierManager) error {
+	next := NewManagerSchema()
+	if err := next.LoadFromFile(path); err != nil {
+		return err
+	}
+
+	config := next.currentConfig()
+	if err := validateConfig(config); err != nil {
+		return err
+	}
+
+	previous := s.currentConfig()
+	var audit *notifier.JSONLAuditLog
+	if config.AuditLog != previous.AuditLog && config.AuditLog != "" {
+		var err error
+		if audit, err = notifier.NewJSONLAuditLog(config.AuditLog); err != nil {
+			return err
+		}
+	}
+
+	notifiers, err := createNotifiers(config)
+	if err != nil {
+		if audit != nil {
+			audit.Close()
+		}
+		return err
+	}
+
+	manager.ReplaceNotifiers(notifiers)
+	manager.SetGrouping(config.GroupBy)
+	if config.AuditLog != previous.AuditLog {
+		var log notifier.AuditLog
+		if audit != nil {
+			log = audit
+		}
+		if err := manager.ReplaceAuditLog(log); err != nil {
+			return fmt.Errorf("关闭原审计日志失败: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.config = config
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,165 @@
+package search
+
+import (
+	"time"
+
+	"github.com/sjzsdu/utils/search"
+)
+
+// Config 搜索配置文件结构体
+type Config struct {
+	// Default 默认搜索引擎，为空时使用 Fallback 中的第一个，再为空时使用第一个启用的搜索引擎
+	Default string `yaml:"default" json:"default"`
+	// Fallback 备用搜索引擎，搜索失败时按顺序尝试
+	Fallback []string `yaml:"fallback" json:"fallback"`
+	// Timeout 所有搜索引擎默认的超时时间（秒）
+	Timeout int `yaml:"timeout" json:"timeout"`
+	// Cache 搜索结果缓存配置
+	Cache CacheConfig `yaml:"cache" json:"cache"`
+	// Filter 搜索结果的后处理配置
+	Filter FilterConfig `yaml:"filter" json:"filter"`
+	// Expansion 查询扩展配置
+	Expansion ExpansionConfig `yaml:"expansion" json:"expansion"`
+	// Engines 各搜索引擎的配置
+	Engines EnginesConfig `yaml:"engines" json:"engines"`
+}
+
+// EnginesConfig 各搜索引擎的配置，未配置或未启用的搜索引擎不会注册
+type EnginesConfig struct {
+	Bing   *EngineConfig `yaml:"bing" json:"bing"`
+	Baidu  *EngineConfig `yaml:"baidu" json:"baidu"`
+	Google *EngineConfig `yaml:"google" json:"google"`
+
+	// HTMLFallback 抓取搜索结果网页的搜索引擎，只在没有启用任何API搜索引擎时注册，名称为 htmlfallback
+	HTMLFallback *HTMLFallbackConfig `yaml:"html_fallback" json:"html_fallback"`
+}
+
+// HTMLFallbackConfig 网页抓取搜索引擎的配置，需要显式开启：
+//
+//	engines:
+//	  html_fallback:
+//	    enabled: true
+//	    providers: [duckduckgo, bing]
+//	    min_interval: 10s
+//	    cache_ttl: 1h
+type HTMLFallbackConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Providers 按顺序尝试的搜索结果页面，可选 duckduckgo 和 bing，为空时两者都使用
+	Providers []string `yaml:"providers,omitempty" json:"providers,omitempty"`
+	// MinInterval 对同一站点两次请求的最小间隔，为0时使用默认值
+	MinInterval time.Duration `yaml:"min_interval,omitempty" json:"min_interval,omitempty"`
+	// CacheTTL 抓取结果的缓存时间，为0时使用默认值
+	CacheTTL time.Duration `yaml:"cache_ttl,omitempty" json:"cache_ttl,omitempty"`
+	// UserAgent 请求使用的User-Agent，为空时使用默认值
+	UserAgent string `yaml:"user_agent,omitempty" json:"user_agent,omitempty"`
+	// Timeout 超时时间（秒），为0时使用全局的超时时间
+	Timeout int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// EngineConfig 单个搜索引擎的配置
+type EngineConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// APIKey API密钥，为空时从搜索引擎对应的环境变量获取
+	APIKey string `yaml:"api_key" json:"api_key"`
+	// SearchEngineID Google的Search Engine ID，为空时从 GOOGLE_CSE_ID 环境变量获取
+	SearchEngineID string `yaml:"search_engine_id,omitempty" json:"search_engine_id,omitempty"`
+	// SecretKey 百度智能云的Secret Key，配置后 api_key 作为Access Key使用AK/SK签名鉴权；为空时从 BAIDU_SECRET_KEY 环境变量获取
+	SecretKey string `yaml:"secret_key,omitempty" json:"secret_key,omitempty"`
+	// WebSearchFallback 百度千帆智能搜索失败时改用百度搜索API
+	WebSearchFallback bool `yaml:"web_search_fallback,omitempty" json:"web_search_fallback,omitempty"`
+	// CredentialsFile Google服务账号的JSON密钥文件，配置后使用OAuth访问令牌鉴权，不再需要 api_key
+	CredentialsFile string `yaml:"credentials_file,omitempty" json:"credentials_file,omitempty"`
+	// QuotaProject Google计费和配额使用的项目
+	QuotaProject string `yaml:"quota_project,omitempty" json:"quota_project,omitempty"`
+	// Market Bing的市场代码，如 zh-CN、en-US
+	Market string `yaml:"market,omitempty" json:"market,omitempty"`
+	// Freshness Bing结果的时效：Day、Week、Month，或 2024-01-01..2024-03-31 形式的日期范围
+	Freshness string `yaml:"freshness,omitempty" json:"freshness,omitempty"`
+	// ResponseFilter Bing返回的结果类型，如 [Webpages, News]
+	ResponseFilter []string `yaml:"response_filter,omitempty" json:"response_filter,omitempty"`
+	// SafeSearch Bing的成人内容过滤级别：Off、Moderate、Strict
+	SafeSearch string `yaml:"safe_search,omitempty" json:"safe_search,omitempty"`
+	// Timeout 超时时间（秒），为0时使用全局的超时时间
+	Timeout int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Headers 自定义请求头
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// CacheConfig 搜索结果缓存配置
+type CacheConfig struct {
+	// TTL 缓存有效期，为0时不缓存
+	TTL time.Duration `yaml:"ttl" json:"ttl"`
+	// MaxEntries 最大缓存条目数，为0时不限制
+	MaxEntries int `yaml:"max_entries" json:"max_entries"`
+}
+
+// FilterConfig 搜索结果的后处理配置，对所有搜索引擎的结果生效
+type FilterConfig struct {
+	// ExcludeDomains 排除的域名，同时排除其子域名
+	ExcludeDomains []string `yaml:"exclude_domains,omitempty" json:"exclude_domains,omitempty"`
+	// Language 只保留该语言的结果，如 zh、en
+	Language string `yaml:"language,omitempty" json:"language,omitempty"`
+	// StripTracking 移除结果URL中的跟踪参数
+	StripTracking bool `yaml:"strip_tracking,omitempty" json:"strip_tracking,omitempty"`
+	// DedupeTitles 去除标题重复的结果
+	DedupeTitles bool `yaml:"dedupe_titles,omitempty" json:"dedupe_titles,omitempty"`
+}
+
+// ExpansionConfig 查询扩展配置，配置了同义词时除原查询外还会用同义词替换后的查询搜索并合并结果：
+//
+//	expansion:
+//	  synonyms_file: synonyms.txt
+//	  synonyms:
+//	    - [人工智能, AI, artificial intelligence]
+//	  max_queries: 2
+type ExpansionConfig struct {
+	// Synonyms 同义词组，每组中的词互为同义词
+	Synonyms [][]string `yaml:"synonyms,omitempty" json:"synonyms,omitempty"`
+	// SynonymsFile 同义词词典文件，每行一组以逗号分隔的同义词，与 Synonyms 合并使用
+	SynonymsFile string `yaml:"synonyms_file,omitempty" json:"synonyms_file,omitempty"`
+	// MaxQueries 除原查询外最多额外发出的扩展查询数，为0时使用默认值
+	MaxQueries int `yaml:"max_queries,omitempty" json:"max_queries,omitempty"`
+}
+
+// engineConfig 单个搜索引擎的名称和配置
+type engineConfig struct {
+	name   string
+	config *EngineConfig
+}
+
+// engines 按注册顺序返回所有已启用的搜索引擎
+func (c EnginesConfig) engines() []engineConfig {
+	var engines []engineConfig
+	for _, engine := range []engineConfig{
+		{name: "bing", config: c.Bing},
+		{name: "baidu", config: c.Baidu},
+		{name: "google", config: c.Google},
+	} {
+		if engine.config != nil && engine.config.Enabled {
+			engines = append(engines, engine)
+		}
+	}
+	return engines
+}
+
+// htmlFallback 返回是否使用网页抓取搜索引擎，只在开启且没有启用任何API搜索引擎时使用
+func (c EnginesConfig) htmlFallback() bool {
+	return c.HTMLFallback != nil && c.HTMLFallback.Enabled && len(c.engines()) == 0
+}
+
+// defaultEngine 返回实际使用的默认搜索引擎
+func (c Config) defaultEngine() string {
+	if c.Default != "" {
+		return c.Default
+	}
+	if len(c.Fallback) > 0 {
+		return c.Fallback[0]
+	}
+	if engines := c.Engines.engines(); len(engines) > 0 {
+		return engines[0].name
+	}
+	if c.Engines.htmlFallback() {
+		return search.HTMLFallbackName
+	}
+	return ""
+}
@@ -0,0 +1,271 @@
+package search
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sjzsdu/utils/schema/internal/loader"
+	"github.com/sjzsdu/utils/search"
+)
+
+// apiKeyEnv 各搜索引擎API密钥对应的环境变量，与search包中的默认值一致
+var apiKeyEnv = map[string]string{
+	"bing":   "BING_API_KEY",
+	"baidu":  "BAIDU_API_KEY",
+	"google": "GOOGLE_API_KEY",
+}
+
+// googleCSEEnv Google Search Engine ID对应的环境变量
+const googleCSEEnv = "GOOGLE_CSE_ID"
+
+// ClientSchema 管理搜索客户端的schema
+type ClientSchema struct {
+	mu     sync.RWMutex
+	config Config
+}
+
+// NewClientSchema 创建ClientSchema实例
+func NewClientSchema() *ClientSchema {
+	return &ClientSchema{}
+}
+
+// LoadFromFile 从配置文件加载配置，配置值中的 ${VAR} 和 ${VAR:-default} 会从环境变量展开
+// 根据扩展名选择格式：.json 为JSON，.toml 为TOML，其余按YAML解析
+func (s *ClientSchema) LoadFromFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		return s.LoadFromJSON(content)
+	case ".toml":
+		return s.LoadFromTOML(content)
+	}
+
+	if err := s.unmarshal(content); err != nil {
+		return fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromBytes 从字节数组加载配置，配置值中的环境变量引用同样会被展开
+func (s *ClientSchema) LoadFromBytes(data []byte) error {
+	if err := s.unmarshal(data); err != nil {
+		return fmt.Errorf("解析配置失败: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromJSON 从JSON加载配置，字段名与YAML配置相同
+func (s *ClientSchema) LoadFromJSON(data []byte) error {
+	if !json.Valid(data) {
+		return fmt.Errorf("解析JSON配置失败: 格式无效")
+	}
+
+	// JSON是YAML的子集，统一按YAML解析以复用字段标签和环境变量展开
+	if err := s.unmarshal(data); err != nil {
+		return fmt.Errorf("解析JSON配置失败: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromTOML 从TOML加载配置，字段名与YAML配置相同
+func (s *ClientSchema) LoadFromTOML(data []byte) error {
+	// 转换为YAML后解析，复用字段标签和环境变量展开
+	converted, err := loader.TOMLToYAML(data)
+	if err != nil {
+		return fmt.Errorf("解析TOML配置失败: %w", err)
+	}
+	if err := s.unmarshal(converted); err != nil {
+		return fmt.Errorf("解析TOML配置失败: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromEnv 启用环境变量中配置了API密钥的搜索引擎
+// 已在配置中出现的搜索引擎保持不变，Google需要同时设置 GOOGLE_API_KEY 和 GOOGLE_CSE_ID；
+// SEARCH_HTML_FALLBACK 为true时开启网页抓取搜索引擎，只在没有启用任何API搜索引擎时使用
+func (s *ClientSchema) LoadFromEnv() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enable := func(engine **EngineConfig, ok bool) {
+		if *engine == nil && ok {
+			*engine = &EngineConfig{Enabled: true}
+		}
+	}
+	enable(&s.config.Engines.Bing, os.Getenv(apiKeyEnv["bing"]) != "")
+	enable(&s.config.Engines.Baidu, os.Getenv(apiKeyEnv["baidu"]) != "")
+	enable(&s.config.Engines.Google, os.Getenv(apiKeyEnv["google"]) != "" && os.Getenv(googleCSEEnv) != "")
+	if s.config.Engines.HTMLFallback == nil && search.HTMLFallbackEnabled() {
+		s.config.Engines.HTMLFallback = &HTMLFallbackConfig{Enabled: true}
+	}
+}
+
+// unmarshal 将YAML格式的配置合并到当前配置
+func (s *ClientSchema) unmarshal(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return loader.UnmarshalYAML(data, &s.config)
+}
+
+// currentConfig 返回当前配置
+func (s *ClientSchema) currentConfig() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// CreateClient 根据配置创建搜索客户端，创建前会先校验配置
+func (s *ClientSchema) CreateClient() (*search.Client, error) {
+	config := s.currentConfig()
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	client := search.NewClient()
+	for _, engine := range config.Engines.engines() {
+		searchEngine, err := newEngine(engine.name, engine.config, config.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		client.RegisterEngine(searchEngine)
+	}
+	if config.Engines.htmlFallback() {
+		client.RegisterEngine(newHTMLFallback(config.Engines.HTMLFallback, config.Timeout))
+	}
+
+	if err := client.SetDefaultEngine(config.defaultEngine()); err != nil {
+		return nil, err
+	}
+	if err := client.SetFallback(config.Fallback...); err != nil {
+		return nil, err
+	}
+	client.SetCache(config.Cache.TTL, config.Cache.MaxEntries)
+	client.SetFilter(search.FilterOptions{
+		ExcludeDomains: config.Filter.ExcludeDomains,
+		Language:       config.Filter.Language,
+		StripTracking:  config.Filter.StripTracking,
+		DedupeTitles:   config.Filter.DedupeTitles,
+	})
+	if err := setExpansion(client, config.Expansion); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// setExpansion 按配置开启查询扩展，没有配置同义词时不开启
+func setExpansion(client *search.Client, config ExpansionConfig) error {
+	if len(config.Synonyms) == 0 && config.SynonymsFile == "" {
+		return nil
+	}
+
+	synonyms := search.NewSynonyms()
+	if config.SynonymsFile != "" {
+		file, err := os.Open(config.SynonymsFile)
+		if err != nil {
+			return fmt.Errorf("打开同义词词典失败: %w", err)
+		}
+		defer file.Close()
+		if synonyms, err = search.LoadSynonyms(file); err != nil {
+			return err
+		}
+	}
+	for _, group := range config.Synonyms {
+		synonyms.Add(group...)
+	}
+
+	client.SetExpansion(search.ExpansionOptions{Synonyms: synonyms, MaxQueries: config.MaxQueries})
+	return nil
+}
+
+// newEngine 根据配置创建搜索引擎，timeout为全局的超时时间（秒）
+func newEngine(name string, config *EngineConfig, timeout int) (search.SearchEngine, error) {
+	var opts []search.SearchOption
+	if timeout := cmp.Or(config.Timeout, timeout); timeout > 0 {
+		opts = append(opts, search.WithTimeout(timeout))
+	}
+	if len(config.Headers) > 0 {
+		opts = append(opts, search.WithHeaders(config.Headers))
+	}
+
+	switch name {
+	case "bing":
+		if config.Market != "" {
+			opts = append(opts, search.WithMarket(config.Market))
+		}
+		if config.Freshness != "" {
+			opts = append(opts, search.WithFreshness(search.BingFreshness(config.Freshness)))
+		}
+		if len(config.ResponseFilter) > 0 {
+			filters := make([]search.BingResponseFilter, 0, len(config.ResponseFilter))
+			for _, filter := range config.ResponseFilter {
+				filters = append(filters, search.BingResponseFilter(filter))
+			}
+			opts = append(opts, search.WithResponseFilter(filters...))
+		}
+		if config.SafeSearch != "" {
+			opts = append(opts, search.WithSafeSearch(search.BingSafeSearch(config.SafeSearch)))
+		}
+		return search.NewBingSearch(config.APIKey, opts...), nil
+	case "baidu":
+		if config.SecretKey != "" {
+			opts = append(opts, search.WithOtherKey(config.SecretKey))
+		}
+		opts = append(opts, search.WithWebSearchFallback(config.WebSearchFallback))
+		return search.NewBaiduSearch(config.APIKey, opts...), nil
+	default:
+		if config.CredentialsFile != "" {
+			tokenSource, err := search.NewServiceAccountTokenSourceFromFile(config.CredentialsFile)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, search.WithTokenSource(tokenSource))
+		}
+		if config.QuotaProject != "" {
+			opts = append(opts, search.WithQuotaProject(config.QuotaProject))
+		}
+		return search.NewGoogleSearch(config.APIKey, config.SearchEngineID, opts...), nil
+	}
+}
+
+// newHTMLFallback 根据配置创建网页抓取搜索引擎，timeout为全局的超时时间（秒）
+func newHTMLFallback(config *HTMLFallbackConfig, timeout int) search.SearchEngine {
+	options := search.HTMLFallbackOptions{
+		Enabled:     config.Enabled,
+		MinInterval: config.MinInterval,
+		CacheTTL:    config.CacheTTL,
+		UserAgent:   config.UserAgent,
+	}
+	for _, provider := range config.Providers {
+		options.Providers = append(options.Providers, search.HTMLFallbackProvider(provider))
+	}
+
+	var opts []search.SearchOption
+	if timeout := cmp.Or(config.Timeout, timeout); timeout > 0 {
+		opts = append(opts, search.WithTimeout(timeout))
+	}
+	return search.NewHTMLFallbackSearch(options, opts...)
+}
+
+// LoadAndCreateClient 从配置文件加载配置并创建搜索客户端
+func LoadAndCreateClient(filePath string) (*search.Client, error) {
+	schema := NewClientSchema()
+	if err := schema.LoadFromFile(filePath); err != nil {
+		return nil, err
+	}
+
+	return schema.CreateClient()
+}
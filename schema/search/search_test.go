@@ -0,0 +1,446 @@
+package search
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sjzsdu/utils/search"
+)
+
+// fakeEngine 用于测试的搜索引擎
+type fakeEngine struct {
+	name    string
+	err     error
+	calls   int
+	results []search.SearchResult
+}
+
+func (e *fakeEngine) Name() string {
+	return e.name
+}
+
+func (e *fakeEngine) Search(ctx context.Context, query string, limit int) ([]search.SearchResult, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.results != nil {
+		return e.results, nil
+	}
+	return []search.SearchResult{{Title: e.name + ": " + query}}, nil
+}
+
+func TestClientSchema_CreateClient(t *testing.T) {
+	config := `
+default: google
+fallback: [bing]
+timeout: 10
+
+cache:
+  ttl: 10m
+  max_entries: 100
+
+engines:
+  bing:
+    enabled: true
+    api_key: "bing-key"
+  baidu:
+    enabled: false
+  google:
+    enabled: true
+    api_key: "google-key"
+    search_engine_id: "cse-id"
+    timeout: 5
+`
+
+	schema := NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	client, err := schema.CreateClient()
+	if err != nil {
+		t.Fatalf("创建搜索客户端失败: %v", err)
+	}
+
+	engines := client.ListEngines()
+	slices.Sort(engines)
+	if !slices.Equal(engines, []string{"bing", "google"}) {
+		t.Errorf("期望注册bing和google，实际为: %v", engines)
+	}
+	if client.DefaultEngine() != "google" {
+		t.Errorf("期望默认搜索引擎为google，实际为: %s", client.DefaultEngine())
+	}
+
+	// 替换为测试用的搜索引擎，验证备用搜索引擎和缓存
+	google := &fakeEngine{name: "google", err: errors.New("quota exceeded")}
+	bing := &fakeEngine{name: "bing"}
+	client.RegisterEngine(google)
+	client.RegisterEngine(bing)
+
+	for i := 0; i < 2; i++ {
+		results, err := client.Search(context.Background(), "golang", 10)
+		if err != nil {
+			t.Fatalf("搜索失败: %v", err)
+		}
+		if len(results) != 1 || results[0].Title != "bing: golang" {
+			t.Errorf("期望返回bing的结果，实际为: %v", results)
+		}
+	}
+	if bing.calls != 1 {
+		t.Errorf("期望第二次搜索命中缓存，bing被调用了%d次", bing.calls)
+	}
+
+	response, err := client.SearchWithMetadata(context.Background(), "golang", 10)
+	if err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	if response.Requested != "google" || response.Engine != "bing" || !response.Fallback() {
+		t.Errorf("期望由备用搜索引擎bing返回结果，实际为: %+v", response)
+	}
+	if !response.Cached || len(response.Errors) != 1 || response.Latency <= 0 {
+		t.Errorf("期望结果来自缓存并记录google的错误和耗时，实际为: %+v", response)
+	}
+	if len(response.Results) != 1 || response.Results[0].Rank != 1 {
+		t.Errorf("期望结果排名从1开始，实际为: %v", response.Results)
+	}
+}
+
+func TestClientSchema_LoadFromEnv(t *testing.T) {
+	t.Setenv("BING_API_KEY", "")
+	t.Setenv("BAIDU_API_KEY", "baidu-key")
+	t.Setenv("GOOGLE_API_KEY", "google-key")
+	t.Setenv("GOOGLE_CSE_ID", "")
+
+	schema := NewClientSchema()
+	schema.LoadFromEnv()
+
+	client, err := schema.CreateClient()
+	if err != nil {
+		t.Fatalf("创建搜索客户端失败: %v", err)
+	}
+	if engines := client.ListEngines(); !slices.Equal(engines, []string{"baidu"}) {
+		t.Errorf("期望只启用baidu，实际为: %v", engines)
+	}
+	if client.DefaultEngine() != "baidu" {
+		t.Errorf("期望默认搜索引擎为baidu，实际为: %s", client.DefaultEngine())
+	}
+}
+
+func TestClientSchema_Validate(t *testing.T) {
+	t.Setenv("BING_API_KEY", "")
+	t.Setenv("GOOGLE_CSE_ID", "")
+
+	config := `
+default: baidu
+fallback: [bing, google]
+
+cache:
+  ttl: -1s
+
+engines:
+  bing:
+    enabled: true
+    secret_key: "bing-secret"
+  google:
+    enabled: true
+    api_key: "google-key"
+`
+
+	schema := NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	err := schema.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回ValidationError，实际为: %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = true
+	}
+	expected := []string{"engines.bing.api_key", "engines.bing.secret_key", "engines.google.search_engine_id", "default", "cache.ttl"}
+	for _, field := range expected {
+		if !fields[field] {
+			t.Errorf("期望包含字段 %s 的错误，实际为: %v", field, err)
+		}
+	}
+	if len(validationErr.Fields) != len(expected) {
+		t.Errorf("期望%d个字段错误，实际为: %v", len(expected), err)
+	}
+
+	if _, err := schema.CreateClient(); err == nil {
+		t.Error("配置不合法时CreateClient应返回错误")
+	}
+}
+
+func TestClientSchema_Filter(t *testing.T) {
+	config := `
+filter:
+  exclude_domains: [spam.com]
+  language: zh-CN
+  strip_tracking: true
+  dedupe_titles: true
+engines:
+  bing:
+    enabled: true
+    api_key: "bing-key"
+`
+
+	schema := NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+	client, err := schema.CreateClient()
+	if err != nil {
+		t.Fatalf("创建搜索客户端失败: %v", err)
+	}
+
+	client.RegisterEngine(&fakeEngine{name: "bing", results: []search.SearchResult{
+		{Title: "Go 1.24 发布", URL: "https://go.dev/blog?utm_source=bing&id=1"},
+		{Title: "Go 1.24  发布", URL: "https://www.go.dev/news"},
+		{Title: "Go 1.24 released", URL: "https://go.dev/en"},
+		{Title: "Go 语言教程", URL: "https://news.spam.com/go"},
+	}})
+
+	results, err := client.Search(context.Background(), "go", 10)
+	if err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "https://go.dev/blog?id=1" {
+		t.Errorf("期望只保留第一条结果并移除跟踪参数，实际为: %v", results)
+	}
+}
+
+func TestClientSchema_GoogleServiceAccount(t *testing.T) {
+	t.Setenv("GOOGLE_API_KEY", "")
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	credentials, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "search@example.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+	})
+	credentialsFile := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(credentialsFile, credentials, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := fmt.Sprintf(`
+engines:
+  google:
+    enabled: true
+    search_engine_id: "cse-id"
+    credentials_file: %q
+    quota_project: "my-project"
+`, credentialsFile)
+
+	schema := NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+	if _, err := schema.CreateClient(); err != nil {
+		t.Fatalf("使用服务账号时不应要求API密钥: %v", err)
+	}
+
+	schema = NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(strings.Replace(config, credentialsFile, credentialsFile+".missing", 1))); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+	var validationErr *ValidationError
+	if err := schema.Validate(); !errors.As(err, &validationErr) || len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != "engines.google.credentials_file" {
+		t.Errorf("期望 engines.google.credentials_file 的错误，实际为: %v", err)
+	}
+}
+
+func TestClientSchema_ValidateBing(t *testing.T) {
+	config := `
+engines:
+  bing:
+    enabled: true
+    api_key: "bing-key"
+    market: zh-CN
+    freshness: 2024-01-01..2024-13-01
+    response_filter: [Webpages, Music]
+    safe_search: Strict
+  baidu:
+    enabled: true
+    api_key: "baidu-key"
+    market: zh-CN
+`
+
+	schema := NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+
+	err := schema.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回ValidationError，实际为: %v", err)
+	}
+
+	var fields []string
+	for _, field := range validationErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	expected := []string{"engines.bing.freshness", "engines.bing.response_filter[1]", "engines.baidu"}
+	if !slices.Equal(fields, expected) {
+		t.Errorf("期望字段错误 %v，实际为: %v", expected, err)
+	}
+}
+
+// queryEngine 按查询返回结果并记录收到的查询，可以并发调用
+type queryEngine struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (e *queryEngine) Name() string {
+	return "bing"
+}
+
+func (e *queryEngine) Search(ctx context.Context, query string, limit int) ([]search.SearchResult, error) {
+	e.mu.Lock()
+	e.queries = append(e.queries, query)
+	e.mu.Unlock()
+	return []search.SearchResult{
+		{Title: query, URL: "https://example.com/" + query},
+		{Title: "共同结果", URL: "https://example.com/shared"},
+	}, nil
+}
+
+func TestClientSchema_Expansion(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "synonyms.txt")
+	if err := os.WriteFile(file, []byte("# 模型\n大模型, LLM\n"), 0o644); err != nil {
+		t.Fatalf("写入同义词词典失败: %v", err)
+	}
+
+	config := fmt.Sprintf(`
+expansion:
+  synonyms_file: %q
+  synonyms:
+    - [人工智能, AI]
+  max_queries: 2
+engines:
+  bing:
+    enabled: true
+    api_key: "bing-key"
+`, file)
+
+	schema := NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+	client, err := schema.CreateClient()
+	if err != nil {
+		t.Fatalf("创建搜索客户端失败: %v", err)
+	}
+	engine := &queryEngine{}
+	client.RegisterEngine(engine)
+
+	results, err := client.Search(context.Background(), "AI 大模型", 10)
+	if err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	slices.Sort(engine.queries)
+	if expected := []string{"AI LLM", "AI 大模型", "人工智能 大模型"}; !slices.Equal(engine.queries, expected) {
+		t.Errorf("期望发出原查询和两个扩展查询 %v，实际为: %v", expected, engine.queries)
+	}
+	if len(results) != 4 || results[0].Title != "AI 大模型" {
+		t.Errorf("期望原查询的结果在前且共同结果只出现一次，实际为: %v", results)
+	}
+
+	// 上下文关闭扩展时只发出原查询
+	engine.queries = nil
+	if _, err := client.Search(search.WithoutExpansion(context.Background()), "AI", 10); err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	if !slices.Equal(engine.queries, []string{"AI"}) {
+		t.Errorf("期望只发出原查询，实际为: %v", engine.queries)
+	}
+
+	schema.config.Expansion = ExpansionConfig{Synonyms: [][]string{{"AI"}}, SynonymsFile: filepath.Join(dir, "missing.txt"), MaxQueries: -1}
+	var validationErr *ValidationError
+	if err := schema.Validate(); !errors.As(err, &validationErr) || len(validationErr.Fields) != 3 {
+		t.Errorf("期望同义词组、词典文件和扩展查询数的错误，实际为: %v", err)
+	}
+}
+
+func TestClientSchema_HTMLFallback(t *testing.T) {
+	t.Setenv("BING_API_KEY", "")
+	t.Setenv("BAIDU_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+	t.Setenv("GOOGLE_CSE_ID", "")
+	t.Setenv(search.HTMLFallbackEnv, "true")
+
+	schema := NewClientSchema()
+	schema.LoadFromEnv()
+	client, err := schema.CreateClient()
+	if err != nil {
+		t.Fatalf("创建搜索客户端失败: %v", err)
+	}
+	if engines := client.ListEngines(); !slices.Equal(engines, []string{search.HTMLFallbackName}) {
+		t.Errorf("没有API密钥时期望只启用htmlfallback，实际为: %v", engines)
+	}
+	if client.DefaultEngine() != search.HTMLFallbackName {
+		t.Errorf("期望默认搜索引擎为htmlfallback，实际为: %s", client.DefaultEngine())
+	}
+
+	// 启用了API搜索引擎时不使用网页抓取
+	config := `
+engines:
+  bing:
+    enabled: true
+    api_key: "bing-key"
+  html_fallback:
+    enabled: true
+`
+	schema = NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+	client, err = schema.CreateClient()
+	if err != nil {
+		t.Fatalf("创建搜索客户端失败: %v", err)
+	}
+	if engines := client.ListEngines(); !slices.Equal(engines, []string{"bing"}) {
+		t.Errorf("有API搜索引擎时期望只启用bing，实际为: %v", engines)
+	}
+
+	config = `
+engines:
+  html_fallback:
+    enabled: true
+    providers: [duckduckgo, google]
+    min_interval: -1s
+`
+	schema = NewClientSchema()
+	if err := schema.LoadFromBytes([]byte(config)); err != nil {
+		t.Fatalf("从字节数组加载配置失败: %v", err)
+	}
+	var validationErr *ValidationError
+	if err := schema.Validate(); !errors.As(err, &validationErr) || len(validationErr.Fields) != 2 {
+		t.Errorf("期望providers[1]和min_interval两个字段错误，实际为: %v", err)
+	}
+}
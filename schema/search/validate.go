@@ -0,0 +1,156 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sjzsdu/utils/schema/internal/validate"
+	"github.com/sjzsdu/utils/search"
+)
+
+// FieldError 单个配置字段的校验错误
+type FieldError = validate.FieldError
+
+// ValidationError 配置校验错误，包含所有不合法的字段
+type ValidationError = validate.ValidationError
+
+// Validate 校验配置，一次返回全部问题
+// 返回的错误为 *ValidationError，其中记录了每个问题的字段路径；未启用的搜索引擎不做校验
+func (s *ClientSchema) Validate() error {
+	return validateConfig(s.currentConfig())
+}
+
+// validateConfig 校验搜索配置
+func validateConfig(c Config) error {
+	v := &validate.Validator{}
+
+	engines := c.Engines.engines()
+	if len(engines) == 0 && !c.Engines.htmlFallback() {
+		v.Add("engines", "至少需要启用一个搜索引擎")
+	}
+
+	enabled := make(map[string]bool, len(engines))
+	if c.Engines.htmlFallback() {
+		enabled[search.HTMLFallbackName] = true
+	}
+	if config := c.Engines.HTMLFallback; config != nil && config.Enabled {
+		validateHTMLFallback(v, config)
+	}
+	for _, engine := range engines {
+		enabled[engine.name] = true
+		field := "engines." + engine.name
+
+		envKey := apiKeyEnv[engine.name]
+		if engine.config.APIKey == "" && engine.config.CredentialsFile == "" && os.Getenv(envKey) == "" {
+			v.Add(field+".api_key", fmt.Sprintf("不能为空，也可以通过环境变量 %s 设置", envKey))
+		}
+		if engine.name == "google" && engine.config.SearchEngineID == "" && os.Getenv(googleCSEEnv) == "" {
+			v.Add(field+".search_engine_id", fmt.Sprintf("不能为空，也可以通过环境变量 %s 设置", googleCSEEnv))
+		}
+		if engine.name == "google" {
+			if engine.config.CredentialsFile != "" {
+				if _, err := os.Stat(engine.config.CredentialsFile); err != nil {
+					v.Add(field+".credentials_file", fmt.Sprintf("无法读取: %v", err))
+				}
+			}
+		} else {
+			if engine.config.CredentialsFile != "" {
+				v.Add(field+".credentials_file", "只有google支持")
+			}
+			if engine.config.QuotaProject != "" {
+				v.Add(field+".quota_project", "只有google支持")
+			}
+		}
+		if engine.name == "bing" {
+			validateBing(v, field, engine.config)
+		} else if engine.config.Market != "" || engine.config.Freshness != "" || len(engine.config.ResponseFilter) > 0 || engine.config.SafeSearch != "" {
+			v.Add(field, "market、freshness、response_filter 和 safe_search 只有bing支持")
+		}
+		if engine.name != "baidu" {
+			if engine.config.SecretKey != "" {
+				v.Add(field+".secret_key", "只有baidu支持")
+			}
+			if engine.config.WebSearchFallback {
+				v.Add(field+".web_search_fallback", "只有baidu支持")
+			}
+		}
+		v.NonNegative(field+".timeout", int64(engine.config.Timeout))
+	}
+
+	if c.Default != "" && !enabled[c.Default] {
+		v.Add("default", fmt.Sprintf("搜索引擎 %q 未启用", c.Default))
+	}
+	for i, name := range c.Fallback {
+		if !enabled[name] {
+			v.Add(fmt.Sprintf("fallback[%d]", i), fmt.Sprintf("搜索引擎 %q 未启用", name))
+		}
+	}
+
+	v.NonNegative("timeout", int64(c.Timeout))
+	v.NonNegative("cache.ttl", int64(c.Cache.TTL))
+	v.NonNegative("cache.max_entries", int64(c.Cache.MaxEntries))
+
+	for i, group := range c.Expansion.Synonyms {
+		if len(group) < 2 {
+			v.Add(fmt.Sprintf("expansion.synonyms[%d]", i), "至少需要两个同义词")
+		}
+	}
+	if c.Expansion.SynonymsFile != "" {
+		if _, err := os.Stat(c.Expansion.SynonymsFile); err != nil {
+			v.Add("expansion.synonyms_file", fmt.Sprintf("无法读取: %v", err))
+		}
+	}
+	v.NonNegative("expansion.max_queries", int64(c.Expansion.MaxQueries))
+
+	return v.Err()
+}
+
+// validateBing 校验Bing特有的搜索参数
+func validateBing(v *validate.Validator, field string, config *EngineConfig) {
+	switch search.BingFreshness(config.Freshness) {
+	case "", search.BingFreshnessDay, search.BingFreshnessWeek, search.BingFreshnessMonth:
+	default:
+		from, to, isRange := strings.Cut(config.Freshness, "..")
+		if !validDate(from) || (isRange && !validDate(to)) {
+			v.Add(field+".freshness", fmt.Sprintf("不支持的时效 %q，可选值为 Day、Week、Month 或 YYYY-MM-DD..YYYY-MM-DD", config.Freshness))
+		}
+	}
+
+	for i, filter := range config.ResponseFilter {
+		switch search.BingResponseFilter(filter) {
+		case search.BingResponseWebpages, search.BingResponseNews, search.BingResponseImages,
+			search.BingResponseVideos, search.BingResponseEntities:
+		default:
+			v.Add(fmt.Sprintf("%s.response_filter[%d]", field, i), fmt.Sprintf("不支持的结果类型 %q", filter))
+		}
+	}
+
+	switch search.BingSafeSearch(config.SafeSearch) {
+	case "", search.BingSafeSearchOff, search.BingSafeSearchModerate, search.BingSafeSearchStrict:
+	default:
+		v.Add(field+".safe_search", fmt.Sprintf("不支持的过滤级别 %q，可选值为 Off、Moderate、Strict", config.SafeSearch))
+	}
+}
+
+// validateHTMLFallback 校验网页抓取搜索引擎的配置
+func validateHTMLFallback(v *validate.Validator, config *HTMLFallbackConfig) {
+	field := "engines.html_fallback"
+	for i, provider := range config.Providers {
+		switch search.HTMLFallbackProvider(provider) {
+		case search.HTMLFallbackDuckDuckGo, search.HTMLFallbackBing:
+		default:
+			v.Add(fmt.Sprintf("%s.providers[%d]", field, i), fmt.Sprintf("不支持的搜索结果页面 %q，可选值为 duckduckgo、bing", provider))
+		}
+	}
+	v.NonNegative(field+".min_interval", int64(config.MinInterval))
+	v.NonNegative(field+".cache_ttl", int64(config.CacheTTL))
+	v.NonNegative(field+".timeout", int64(config.Timeout))
+}
+
+// validDate 判断是否为 YYYY-MM-DD 格式的日期
+func validDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -12,14 +13,26 @@ import (
 	"time"
 )
 
+// 百度搜索API的地址
+const (
+	// baiduQianfanSearchURL 千帆智能搜索生成API，返回生成的回答和引用的网页
+	baiduQianfanSearchURL = "https://qianfan.baidubce.com/v2/ai_search/chat/completions"
+	// baiduWebSearchURL 百度搜索API，只返回网页搜索结果
+	baiduWebSearchURL = "https://qianfan.baidubce.com/v2/ai_search/web_search"
+)
+
 // BaiduSearch 实现baidu搜索引擎
 type BaiduSearch struct {
-	apiKey  string
-	timeout int
-	headers map[string]string
+	apiKey            string
+	secretKey         string
+	timeout           int
+	headers           map[string]string
+	webSearchFallback bool
 }
 
 // NewBaiduSearch 创建baidu搜索引擎实例
+// 通过 WithOtherKey 设置Secret Key（或设置 BAIDU_SECRET_KEY 环境变量）时，apiKey作为Access Key使用AK/SK签名鉴权，
+// 否则apiKey作为千帆的API Key使用Bearer鉴权
 func NewBaiduSearch(apiKey string, opts ...SearchOption) *BaiduSearch {
 	// 如果未提供API密钥，从环境变量获取
 	if apiKey == "" {
@@ -27,8 +40,9 @@ func NewBaiduSearch(apiKey string, opts ...SearchOption) *BaiduSearch {
 	}
 
 	cfg := &SearchConfig{
-		APIKey:  apiKey,
-		Timeout: 30, // 默认30秒超时，百度API可能较慢
+		APIKey:   apiKey,
+		OtherKey: os.Getenv("BAIDU_SECRET_KEY"),
+		Timeout:  30, // 默认30秒超时，百度API可能较慢
 	}
 
 	for _, opt := range opts {
@@ -36,9 +50,11 @@ func NewBaiduSearch(apiKey string, opts ...SearchOption) *BaiduSearch {
 	}
 
 	return &BaiduSearch{
-		apiKey:  cfg.APIKey,
-		timeout: cfg.Timeout,
-		headers: cfg.Headers,
+		apiKey:            cfg.APIKey,
+		secretKey:         cfg.OtherKey,
+		timeout:           cfg.Timeout,
+		headers:           cfg.Headers,
+		webSearchFallback: cfg.WebSearchFallback,
 	}
 }
 
@@ -48,26 +64,27 @@ func (b *BaiduSearch) Name() string {
 }
 
 // Search 执行搜索并返回结果
+// 开启了 WithWebSearchFallback 时，千帆智能搜索失败后改用百度搜索API
 func (b *BaiduSearch) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
 	// 尝试百度千帆AI搜索API
 	results, err := b.searchWithBaiduQianfanAPI(ctx, query, limit)
-	if err != nil {
-		// 如果千帆API失败，可以在这里添加备用的百度搜索API
+	if err == nil {
+		return results, nil
+	}
+	if !b.webSearchFallback || ctx.Err() != nil {
 		return nil, fmt.Errorf("百度搜索失败: %v", err)
 	}
+
+	// 千帆API失败时使用百度搜索API
+	results, fallbackErr := b.searchWithBaiduWebSearchAPI(ctx, query, limit)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("百度搜索失败: %w", errors.Join(err, fmt.Errorf("百度搜索API: %v", fallbackErr)))
+	}
 	return results, nil
 }
 
 // searchWithBaiduQianfanAPI 使用百度千帆AI搜索API
 func (b *BaiduSearch) searchWithBaiduQianfanAPI(ctx context.Context, query string, limit int) ([]SearchResult, error) {
-	// 构建API URL
-	searchURL := "https://qianfan.baidubce.com/v2/ai_search/chat/completions"
-
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: time.Duration(b.timeout) * time.Second,
-	}
-
 	// 构建请求数据
 	requestData := map[string]interface{}{
 		"messages": []map[string]string{
@@ -91,6 +108,37 @@ func (b *BaiduSearch) searchWithBaiduQianfanAPI(ctx context.Context, query strin
 		"search_recency_filter": "year",
 	}
 
+	return b.post(ctx, baiduQianfanSearchURL, requestData, limit)
+}
+
+// searchWithBaiduWebSearchAPI 使用百度搜索API，不生成回答，只返回网页搜索结果
+func (b *BaiduSearch) searchWithBaiduWebSearchAPI(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	requestData := map[string]interface{}{
+		"messages": []map[string]string{
+			{
+				"content": query,
+				"role":    "user",
+			},
+		},
+		"search_source": "baidu_search_v2",
+		"resource_type_filter": []map[string]interface{}{
+			{
+				"type":  "web",
+				"top_k": limit,
+			},
+		},
+	}
+
+	return b.post(ctx, baiduWebSearchURL, requestData, limit)
+}
+
+// post 发送搜索请求并解析结果
+func (b *BaiduSearch) post(ctx context.Context, searchURL string, requestData map[string]interface{}, limit int) ([]SearchResult, error) {
+	// 创建HTTP客户端
+	client := &http.Client{
+		Timeout: time.Duration(b.timeout) * time.Second,
+	}
+
 	jsonData, err := json.Marshal(requestData)
 	if err != nil {
 		return nil, fmt.Errorf("序列化请求数据失败: %v", err)
@@ -105,13 +153,19 @@ func (b *BaiduSearch) searchWithBaiduQianfanAPI(ctx context.Context, query strin
 	// 设置请求头
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+b.apiKey)
 
 	// 添加自定义请求头
 	for k, v := range b.headers {
 		httpReq.Header.Set(k, v)
 	}
 
+	// 设置鉴权信息，签名需要包含最终的请求头
+	if b.secretKey != "" {
+		signBCERequest(httpReq, b.apiKey, b.secretKey, time.Now())
+	} else {
+		httpReq.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
 	// 执行请求
 	resp, err := client.Do(httpReq)
 	if err != nil {
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bceExpirationSeconds 百度智能云签名的有效期（秒）
+const bceExpirationSeconds = 1800
+
+// signBCERequest 按百度智能云的 bce-auth-v1 规范使用AK/SK为请求签名，设置 x-bce-date 和 Authorization 请求头
+// 参与签名的请求头为 host、content-type 以及 x-bce- 开头的请求头
+func signBCERequest(req *http.Request, accessKey, secretKey string, now time.Time) {
+	timestamp := now.UTC().Format("2006-01-02T15:04:05Z")
+	req.Header.Set("X-Bce-Date", timestamp)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-bce-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	canonicalHeaders := make([]string, 0, len(headers))
+	for name, value := range headers {
+		names = append(names, name)
+		canonicalHeaders = append(canonicalHeaders, bceEncode(name, true)+":"+bceEncode(value, true))
+	}
+	sort.Strings(names)
+	sort.Strings(canonicalHeaders)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		bceEncode(req.URL.Path, false),
+		bceCanonicalQuery(req),
+		strings.Join(canonicalHeaders, "\n"),
+	}, "\n")
+
+	authPrefix := fmt.Sprintf("bce-auth-v1/%s/%s/%d", accessKey, timestamp, bceExpirationSeconds)
+	signingKey := hmacSHA256Hex(secretKey, authPrefix)
+	signature := hmacSHA256Hex(signingKey, canonicalRequest)
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s/%s/%s", authPrefix, strings.Join(names, ";"), signature))
+}
+
+// bceCanonicalQuery 返回按参数名排序并编码的查询字符串
+func bceCanonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for key, values := range query {
+		if strings.EqualFold(key, "authorization") {
+			continue
+		}
+		for _, value := range values {
+			params = append(params, bceEncode(key, true)+"="+bceEncode(value, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// bceEncode 按RFC 3986编码，字母、数字和 -_.~ 不编码；encodeSlash为false时 / 也不编码
+func bceEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// hmacSHA256Hex 返回HMAC-SHA256的十六进制摘要
+func hmacSHA256Hex(key, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -8,14 +8,96 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // BingSearch 实现bing搜索引擎
 type BingSearch struct {
-	apiKey  string
-	timeout int
-	headers map[string]string
+	apiKey         string
+	timeout        int
+	headers        map[string]string
+	market         string
+	freshness      BingFreshness
+	responseFilter []BingResponseFilter
+	safeSearch     BingSafeSearch
+}
+
+// BingFreshness Bing搜索结果的时效，也可以用 BingFreshnessRange 指定日期范围
+type BingFreshness string
+
+const (
+	// BingFreshnessDay 过去24小时
+	BingFreshnessDay BingFreshness = "Day"
+	// BingFreshnessWeek 过去7天
+	BingFreshnessWeek BingFreshness = "Week"
+	// BingFreshnessMonth 过去30天
+	BingFreshnessMonth BingFreshness = "Month"
+)
+
+// BingFreshnessRange 返回from到to（包含）之间的日期范围，to为零值时只返回from当天
+func BingFreshnessRange(from, to time.Time) BingFreshness {
+	if to.IsZero() {
+		return BingFreshness(from.Format("2006-01-02"))
+	}
+	return BingFreshness(from.Format("2006-01-02") + ".." + to.Format("2006-01-02"))
+}
+
+// BingResponseFilter Bing返回的结果类型
+type BingResponseFilter string
+
+const (
+	// BingResponseWebpages 网页
+	BingResponseWebpages BingResponseFilter = "Webpages"
+	// BingResponseNews 新闻
+	BingResponseNews BingResponseFilter = "News"
+	// BingResponseImages 图片
+	BingResponseImages BingResponseFilter = "Images"
+	// BingResponseVideos 视频
+	BingResponseVideos BingResponseFilter = "Videos"
+	// BingResponseEntities 实体
+	BingResponseEntities BingResponseFilter = "Entities"
+)
+
+// BingSafeSearch Bing的成人内容过滤级别
+type BingSafeSearch string
+
+const (
+	// BingSafeSearchOff 不过滤
+	BingSafeSearchOff BingSafeSearch = "Off"
+	// BingSafeSearchModerate 过滤成人图片和视频，Bing的默认级别
+	BingSafeSearchModerate BingSafeSearch = "Moderate"
+	// BingSafeSearchStrict 过滤所有成人内容
+	BingSafeSearchStrict BingSafeSearch = "Strict"
+)
+
+// WithMarket 设置Bing搜索的市场代码，如 zh-CN、en-US
+func WithMarket(market string) SearchOption {
+	return func(cfg *SearchConfig) {
+		cfg.Market = market
+	}
+}
+
+// WithFreshness 设置Bing搜索结果的时效
+func WithFreshness(freshness BingFreshness) SearchOption {
+	return func(cfg *SearchConfig) {
+		cfg.Freshness = freshness
+	}
+}
+
+// WithResponseFilter 设置Bing返回的结果类型，目前会解析网页和新闻结果
+func WithResponseFilter(filters ...BingResponseFilter) SearchOption {
+	return func(cfg *SearchConfig) {
+		cfg.ResponseFilter = filters
+	}
+}
+
+// WithSafeSearch 设置Bing的成人内容过滤级别
+func WithSafeSearch(safeSearch BingSafeSearch) SearchOption {
+	return func(cfg *SearchConfig) {
+		cfg.SafeSearch = safeSearch
+	}
 }
 
 // NewBingSearch 创建bing搜索引擎实例
@@ -35,9 +117,13 @@ func NewBingSearch(apiKey string, opts ...SearchOption) *BingSearch {
 	}
 
 	return &BingSearch{
-		apiKey:  cfg.APIKey,
-		timeout: cfg.Timeout,
-		headers: cfg.Headers,
+		apiKey:         cfg.APIKey,
+		timeout:        cfg.Timeout,
+		headers:        cfg.Headers,
+		market:         cfg.Market,
+		freshness:      cfg.Freshness,
+		responseFilter: cfg.ResponseFilter,
+		safeSearch:     cfg.SafeSearch,
 	}
 }
 
@@ -49,8 +135,7 @@ func (b *BingSearch) Name() string {
 // Search 执行搜索并返回结果
 func (b *BingSearch) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
 	// 构建API URL
-	searchURL := fmt.Sprintf("https://api.bing.microsoft.com/v7.0/search?q=%s&count=%d",
-		url.QueryEscape(query), limit)
+	searchURL := "https://api.bing.microsoft.com/v7.0/search?" + b.searchParams(query, limit).Encode()
 
 	// 创建HTTP客户端
 	client := &http.Client{
@@ -98,6 +183,59 @@ func (b *BingSearch) Search(ctx context.Context, query string, limit int) ([]Sea
 	return results, nil
 }
 
+// SearchWithOptions 使用本次设置的市场、时效、结果类型和过滤级别覆盖创建时的参数执行搜索，其他选项被忽略
+func (b *BingSearch) SearchWithOptions(ctx context.Context, query string, limit int, opts ...SearchOption) ([]SearchResult, error) {
+	return b.withOptions(opts).Search(ctx, query, limit)
+}
+
+// withOptions 返回用opts中设置了的Bing参数覆盖后的副本
+func (b *BingSearch) withOptions(opts []SearchOption) *BingSearch {
+	cfg := &SearchConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	search := *b
+	if cfg.Market != "" {
+		search.market = cfg.Market
+	}
+	if cfg.Freshness != "" {
+		search.freshness = cfg.Freshness
+	}
+	if len(cfg.ResponseFilter) > 0 {
+		search.responseFilter = cfg.ResponseFilter
+	}
+	if cfg.SafeSearch != "" {
+		search.safeSearch = cfg.SafeSearch
+	}
+	return &search
+}
+
+// searchParams 返回搜索请求的查询参数
+func (b *BingSearch) searchParams(query string, limit int) url.Values {
+	params := url.Values{
+		"q":     {query},
+		"count": {strconv.Itoa(limit)},
+	}
+	if b.market != "" {
+		params.Set("mkt", b.market)
+	}
+	if b.freshness != "" {
+		params.Set("freshness", string(b.freshness))
+	}
+	if len(b.responseFilter) > 0 {
+		filters := make([]string, 0, len(b.responseFilter))
+		for _, filter := range b.responseFilter {
+			filters = append(filters, string(filter))
+		}
+		params.Set("responseFilter", strings.Join(filters, ","))
+	}
+	if b.safeSearch != "" {
+		params.Set("safeSearch", string(b.safeSearch))
+	}
+	return params
+}
+
 // parseBingSearchResults 解析Bing搜索结果
 func parseBingSearchResults(data []byte, limit int) ([]SearchResult, error) {
 	var response struct {
@@ -108,6 +246,13 @@ func parseBingSearchResults(data []byte, limit int) ([]SearchResult, error) {
 				Snippet string `json:"snippet"`
 			} `json:"value"`
 		} `json:"webPages"`
+		News struct {
+			Value []struct {
+				Name        string `json:"name"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"value"`
+		} `json:"news"`
 	}
 
 	if err := json.Unmarshal(data, &response); err != nil {
@@ -126,5 +271,90 @@ func parseBingSearchResults(data []byte, limit int) ([]SearchResult, error) {
 		})
 	}
 
+	// 通过 responseFilter 请求新闻时，新闻结果排在网页结果之后
+	for _, item := range response.News.Value {
+		if len(results) >= limit {
+			break
+		}
+		results = append(results, SearchResult{
+			Title:   item.Name,
+			URL:     item.URL,
+			Snippet: item.Description,
+		})
+	}
+
 	return results, nil
 }
+
+// Suggest 使用Bing Autosuggest API返回搜索建议
+func (b *BingSearch) Suggest(ctx context.Context, prefix string) ([]string, error) {
+	suggestURL := fmt.Sprintf("https://api.bing.microsoft.com/v7.0/Suggestions?q=%s", url.QueryEscape(prefix))
+	if b.market != "" {
+		suggestURL += "&mkt=" + url.QueryEscape(b.market)
+	}
+
+	client := &http.Client{
+		Timeout: time.Duration(b.timeout) * time.Second,
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", suggestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+
+	httpReq.Header.Set("Ocp-Apim-Subscription-Key", b.apiKey)
+	for k, v := range b.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP请求失败，状态码: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应内容失败: %v", err)
+	}
+
+	suggestions, err := parseBingSuggestions(body)
+	if err != nil {
+		return nil, fmt.Errorf("解析搜索建议失败: %v", err)
+	}
+
+	return suggestions, nil
+}
+
+// parseBingSuggestions 解析Bing搜索建议
+func parseBingSuggestions(data []byte) ([]string, error) {
+	var response struct {
+		SuggestionGroups []struct {
+			SearchSuggestions []struct {
+				DisplayText string `json:"displayText"`
+				Query       string `json:"query"`
+			} `json:"searchSuggestions"`
+		} `json:"suggestionGroups"`
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]string, 0)
+	for _, group := range response.SuggestionGroups {
+		for _, suggestion := range group.SearchSuggestions {
+			text := suggestion.DisplayText
+			if text == "" {
+				text = suggestion.Query
+			}
+			suggestions = append(suggestions, text)
+		}
+	}
+
+	return suggestions, nil
+}
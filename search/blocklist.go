@@ -0,0 +1,202 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Blocklist 域名黑名单和白名单，用于排除内容农场、垃圾站点等低质量结果
+// 规则为域名时同时匹配其子域名；规则含 * 时按通配符匹配完整的主机名，如 *.example.com、spam-*.net。
+// 匹配白名单的域名不会被黑名单排除。可以同时使用本地规则和定期刷新的远程列表
+type Blocklist struct {
+	client *http.Client
+
+	mu     sync.RWMutex
+	block  []string
+	allow  []string
+	remote map[string]remoteList
+}
+
+// remoteList 从远程地址加载的规则列表
+type remoteList struct {
+	allow    bool
+	patterns []string
+}
+
+// NewBlocklist 创建域名黑名单，allow为白名单规则
+func NewBlocklist(block, allow []string) *Blocklist {
+	b := &Blocklist{
+		client: &http.Client{Timeout: 30 * time.Second},
+		remote: make(map[string]remoteList),
+	}
+	b.Block(block...)
+	b.Allow(allow...)
+	return b
+}
+
+// Block 添加黑名单规则
+func (b *Blocklist) Block(patterns ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.block = append(b.block, normalizePatterns(patterns)...)
+}
+
+// Allow 添加白名单规则
+func (b *Blocklist) Allow(patterns ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.allow = append(b.allow, normalizePatterns(patterns)...)
+}
+
+// Blocked 判断URL的域名是否被排除，无法解析的URL不会被排除
+func (b *Blocklist) Blocked(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if matchAny(host, b.allow) {
+		return false
+	}
+	for _, list := range b.remote {
+		if list.allow && matchAny(host, list.patterns) {
+			return false
+		}
+	}
+	if matchAny(host, b.block) {
+		return true
+	}
+	for _, list := range b.remote {
+		if !list.allow && matchAny(host, list.patterns) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadRemote 从listURL加载规则列表，allow为true时作为白名单
+// 列表每行一条规则，忽略空行和 # 开头的注释，兼容 hosts 文件格式（如 0.0.0.0 spam.com）；
+// 再次加载同一地址时替换之前的规则，加载失败时保留之前的规则
+func (b *Blocklist) LoadRemote(ctx context.Context, listURL string, allow bool) error {
+	patterns, err := b.fetchList(ctx, listURL)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remote[listURL] = remoteList{allow: allow, patterns: patterns}
+	return nil
+}
+
+// Refresh 重新加载所有远程列表，返回第一个加载失败的错误
+func (b *Blocklist) Refresh(ctx context.Context) error {
+	b.mu.RLock()
+	lists := make(map[string]bool, len(b.remote))
+	for listURL, list := range b.remote {
+		lists[listURL] = list.allow
+	}
+	b.mu.RUnlock()
+
+	var firstErr error
+	for listURL, allow := range lists {
+		if err := b.LoadRemote(ctx, listURL, allow); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// AutoRefresh 每隔interval重新加载远程列表，直到ctx取消
+func (b *Blocklist) AutoRefresh(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				b.Refresh(ctx)
+			}
+		}
+	}()
+}
+
+// fetchList 下载并解析规则列表
+func (b *Blocklist) fetchList(ctx context.Context, listURL string) ([]string, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", listURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP请求失败，状态码: %d", resp.StatusCode)
+	}
+	return parseList(resp.Body)
+}
+
+// parseList 解析规则列表
+func parseList(r io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		// hosts 文件格式的第一列为IP地址
+		patterns = append(patterns, fields[len(fields)-1])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取列表失败: %v", err)
+	}
+	return normalizePatterns(patterns), nil
+}
+
+// normalizePatterns 将规则转换为小写并去掉开头的点
+func normalizePatterns(patterns []string) []string {
+	normalized := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(pattern)), ".")
+		if pattern != "" {
+			normalized = append(normalized, pattern)
+		}
+	}
+	return normalized
+}
+
+// matchAny 判断主机名是否匹配任意一条规则
+func matchAny(host string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "*") {
+			if ok, _ := path.Match(pattern, host); ok {
+				return true
+			}
+			continue
+		}
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMatchAny(t *testing.T) {
+	patterns := []string{"example.com", "*.spam.net", "ads-*.org"}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"www.example.com", true},
+		{"a.b.example.com", true},
+		{"notexample.com", false},
+		{"example.com.cn", false},
+		{"x.spam.net", true},
+		{"spam.net", false},
+		{"ads-tracker.org", true},
+		{"ads.org", false},
+		{"sub.ads-tracker.org", false},
+	}
+	for _, tt := range tests {
+		if got := matchAny(tt.host, patterns); got != tt.want {
+			t.Errorf("matchAny(%q) = %v，期望 %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{"每行一条", "spam.com\nFarm.example.org\n", []string{"spam.com", "farm.example.org"}},
+		{"hosts格式", "0.0.0.0 spam.com\n127.0.0.1\tads.net  # 广告\n", []string{"spam.com", "ads.net"}},
+		{"注释和空行", "# 标题\n\n   \n.leading.dot.com # 注释\n", []string{"leading.dot.com"}},
+		{"通配符", "*.content-farm.io\n", []string{"*.content-farm.io"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseList(strings.NewReader(tt.list))
+			if err != nil {
+				t.Fatalf("解析列表失败: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("期望 %v，实际为 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBlocklistAllow(t *testing.T) {
+	blocklist := NewBlocklist([]string{"example.com"}, []string{"docs.example.com"})
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://www.example.com/a", true},
+		{"https://docs.example.com/a", false},
+		{"https://api.docs.example.com/a", false},
+		{"https://other.org/", false},
+		{"not a url", false},
+	}
+	for _, tt := range tests {
+		if got := blocklist.Blocked(tt.url); got != tt.want {
+			t.Errorf("Blocked(%q) = %v，期望 %v", tt.url, got, tt.want)
+		}
+	}
+}
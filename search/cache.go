@@ -0,0 +1,81 @@
+package search
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// resultCache 带过期时间和容量上限的搜索结果缓存，超出容量时淘汰最久未使用的条目
+type resultCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   list.List // 按最近使用排序的 *cacheEntry，队首为最近使用
+}
+
+// cacheEntry 缓存条目
+type cacheEntry struct {
+	key       string
+	results   []SearchResult
+	expiresAt time.Time
+}
+
+// newResultCache 创建搜索结果缓存，maxEntries小于等于0时不限制容量
+func newResultCache(ttl time.Duration, maxEntries int) *resultCache {
+	return &resultCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// cacheKey 生成缓存键，options为按次生效的搜索引擎参数的缓存键
+func cacheKey(engine, query string, limit int, options string) string {
+	return fmt.Sprintf("%s\x00%d\x00%s\x00%s", engine, limit, options, query)
+}
+
+// get 获取未过期的缓存结果
+func (c *resultCache) get(key string) ([]SearchResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.results, true
+}
+
+// set 写入缓存结果
+func (c *resultCache) set(key string, results []SearchResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.results = results
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, results: results, expiresAt: expiresAt})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -2,13 +2,22 @@ package search
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 )
 
 // Client 定义搜索客户端
 type Client struct {
 	engines       map[string]SearchEngine
 	defaultEngine string
+	fallback      []string
+	cache         *resultCache
+	filter        FilterOptions
+	screenshots   ScreenshotOptions
+	highlight     HighlightOptions
+	expansion     ExpansionOptions
+	usage         *UsageTracker
 }
 
 // NewClient 创建搜索客户端实例
@@ -32,8 +41,43 @@ func (c *Client) SetDefaultEngine(name string) error {
 	return nil
 }
 
+// DefaultEngine 返回默认搜索引擎名称
+func (c *Client) DefaultEngine() string {
+	return c.defaultEngine
+}
+
+// SetFallback 设置备用搜索引擎，Search 使用的搜索引擎失败时按顺序尝试备用搜索引擎
+func (c *Client) SetFallback(names ...string) error {
+	for _, name := range names {
+		if _, ok := c.engines[name]; !ok {
+			return fmt.Errorf("搜索引擎 %s 未注册", name)
+		}
+	}
+	c.fallback = names
+	return nil
+}
+
+// SetCache 开启搜索结果缓存，相同搜索引擎、查询和数量限制的结果在ttl内直接返回
+// maxEntries为缓存的最大条目数，超出时淘汰最久未使用的结果，小于等于0时不限制；ttl小于等于0时关闭缓存
+func (c *Client) SetCache(ttl time.Duration, maxEntries int) {
+	if ttl <= 0 {
+		c.cache = nil
+		return
+	}
+	c.cache = newResultCache(ttl, maxEntries)
+}
+
 // Search 执行搜索
+// opts 中的搜索引擎参数（如 WithMarket）只对本次搜索生效，搜索引擎需要实现 OptionsSearcher，否则返回错误；
+// 设置了备用搜索引擎时，指定的搜索引擎失败后会按顺序尝试备用搜索引擎，全部失败时返回所有错误
 func (c *Client) Search(ctx context.Context, query string, limit int, opts ...SearchOption) ([]SearchResult, error) {
+	response, err := c.searchWithMetadata(ctx, query, limit, opts...)
+	return response.Results, err
+}
+
+// searchWithMetadata 执行搜索并记录元数据，返回的response不为nil，出错时其中的结果与 Search 返回的相同
+func (c *Client) searchWithMetadata(ctx context.Context, query string, limit int, opts ...SearchOption) (*SearchResponse, error) {
+	start := time.Now()
 	cfg := &SearchConfig{
 		Engine: c.defaultEngine,
 	}
@@ -42,19 +86,122 @@ func (c *Client) Search(ctx context.Context, query string, limit int, opts ...Se
 		opt(cfg)
 	}
 
+	response := &SearchResponse{Query: query, Requested: cfg.Engine}
+	done := func(engine string, results []SearchResult, cached bool) *SearchResponse {
+		response.Engine, response.Results, response.Cached = engine, results, cached
+		response.Latency = time.Since(start)
+		return response
+	}
+
 	// 如果没有指定搜索引擎且没有默认搜索引擎，返回错误
 	if cfg.Engine == "" {
-		return nil, fmt.Errorf("未指定搜索引擎且没有设置默认搜索引擎")
+		return response, fmt.Errorf("未指定搜索引擎且没有设置默认搜索引擎")
 	}
 
 	// 获取搜索引擎
 	engine, ok := c.engines[cfg.Engine]
 	if !ok {
-		return nil, fmt.Errorf("搜索引擎 %s 未注册", cfg.Engine)
+		return response, fmt.Errorf("搜索引擎 %s 未注册", cfg.Engine)
 	}
 
 	// 执行搜索
-	return engine.Search(ctx, query, limit)
+	results, highlightQuery, cached, err := c.expandedSearch(ctx, engine, query, limit, opts)
+	if err == nil || len(c.fallback) == 0 {
+		return done(engine.Name(), c.postProcess(ctx, highlightQuery, results), cached), err
+	}
+
+	// 依次尝试备用搜索引擎
+	errs := []error{fmt.Errorf("%s: %w", engine.Name(), err)}
+	for _, name := range c.fallback {
+		if name == cfg.Engine {
+			continue
+		}
+		results, highlightQuery, cached, err := c.expandedSearch(ctx, c.engines[name], query, limit, opts)
+		if err == nil {
+			response.Errors = errorStrings(errs)
+			return done(name, c.postProcess(ctx, highlightQuery, results), cached), nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	response.Errors = errorStrings(errs)
+	return done("", nil, false), errors.Join(errs...)
+}
+
+// search 使用指定的搜索引擎搜索，开启缓存时优先返回缓存的结果，cached表示结果来自缓存
+func (c *Client) search(ctx context.Context, engine SearchEngine, query string, limit int, opts []SearchOption) ([]SearchResult, bool, error) {
+	cfg := &SearchConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	options := cfg.engineOptionsKey()
+
+	if c.cache == nil {
+		results, err := engineSearch(ctx, engine, query, limit, options, opts)
+		c.recordUsage(ctx, engine, query, results, false, err)
+		return results, false, err
+	}
+
+	key := cacheKey(engine.Name(), query, limit, options)
+	if results, ok := c.cache.get(key); ok {
+		c.recordUsage(ctx, engine, query, results, true, nil)
+		return results, true, nil
+	}
+	results, err := engineSearch(ctx, engine, query, limit, options, opts)
+	c.recordUsage(ctx, engine, query, results, false, err)
+	if err != nil {
+		return nil, false, err
+	}
+	c.cache.set(key, results)
+	return results, false, nil
+}
+
+// engineSearch 调用搜索引擎搜索，options不为空时通过 OptionsSearcher 传入本次的搜索引擎参数
+func engineSearch(ctx context.Context, engine SearchEngine, query string, limit int, options string, opts []SearchOption) ([]SearchResult, error) {
+	if options == "" {
+		return engine.Search(ctx, query, limit)
+	}
+	searcher, ok := engine.(OptionsSearcher)
+	if !ok {
+		return nil, fmt.Errorf("搜索引擎 %s 不支持按次设置搜索参数", engine.Name())
+	}
+	return searcher.SearchWithOptions(ctx, query, limit, opts...)
+}
+
+// recordUsage 设置了用量统计时记录一次搜索
+func (c *Client) recordUsage(ctx context.Context, engine SearchEngine, query string, results []SearchResult, cached bool, err error) {
+	if c.usage != nil {
+		c.usage.record(ctx, engine.Name(), query, len(results), cached, err)
+	}
+}
+
+// Suggest 返回搜索建议，可以通过 WithEngine 指定搜索引擎，默认使用默认搜索引擎
+// 搜索引擎需要实现 Suggester 接口，目前支持bing和google
+func (c *Client) Suggest(ctx context.Context, prefix string, opts ...SearchOption) ([]string, error) {
+	cfg := &SearchConfig{
+		Engine: c.defaultEngine,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.Engine == "" {
+		return nil, fmt.Errorf("未指定搜索引擎且没有设置默认搜索引擎")
+	}
+
+	engine, ok := c.engines[cfg.Engine]
+	if !ok {
+		return nil, fmt.Errorf("搜索引擎 %s 未注册", cfg.Engine)
+	}
+
+	suggester, ok := engine.(Suggester)
+	if !ok {
+		return nil, fmt.Errorf("搜索引擎 %s 不支持搜索建议", cfg.Engine)
+	}
+	return suggester.Suggest(ctx, prefix)
 }
 
 // SearchWithEngine 指定搜索引擎执行搜索
@@ -66,7 +213,16 @@ func (c *Client) SearchWithEngine(ctx context.Context, engineName, query string,
 	}
 
 	// 执行搜索
-	return engine.Search(ctx, query, limit)
+	results, highlightQuery, _, err := c.expandedSearch(ctx, engine, query, limit, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.postProcess(ctx, highlightQuery, results), nil
+}
+
+// postProcess 对搜索结果进行过滤、高亮和截图，query为高亮使用的查询
+func (c *Client) postProcess(ctx context.Context, query string, results []SearchResult) []SearchResult {
+	return c.screenshots.capture(ctx, c.highlight.apply(query, c.filter.apply(results)))
 }
 
 // ListEngines 返回已注册的搜索引擎列表
@@ -80,7 +236,8 @@ func (c *Client) ListEngines() []string {
 
 // NewDefaultClient 创建默认配置的搜索客户端
 // 包含所有支持的搜索引擎
-// 如果未提供API密钥，将尝试从环境变量获取
+// 如果未提供API密钥，将尝试从环境变量获取；所有API密钥都不可用且环境变量 SEARCH_HTML_FALLBACK 为true时，
+// 额外注册网页抓取搜索引擎 htmlfallback 并作为默认搜索引擎
 func NewDefaultClient(bingAPIKey, baiduAPIKey, googleAPIKey, googleSearchEngineID string, opts ...SearchOption) (*Client, error) {
 	client := NewClient()
 
@@ -96,6 +253,13 @@ func NewDefaultClient(bingAPIKey, baiduAPIKey, googleAPIKey, googleSearchEngineI
 	google := NewGoogleSearch(googleAPIKey, googleSearchEngineID, opts...)
 	client.RegisterEngine(google)
 
+	// 没有任何API密钥且通过环境变量开启了网页抓取时，注册网页抓取搜索引擎并作为默认搜索引擎
+	if bing.apiKey == "" && baidu.apiKey == "" && ((google.apiKey == "" && google.tokenSource == nil) || google.searchEngineId == "") && HTMLFallbackEnabled() {
+		client.RegisterEngine(NewHTMLFallbackSearch(HTMLFallbackOptions{Enabled: true}, opts...))
+		client.defaultEngine = HTMLFallbackName
+		return client, nil
+	}
+
 	// 如果没有注册任何搜索引擎，返回错误
 	if len(client.engines) == 0 {
 		return nil, fmt.Errorf("未注册任何搜索引擎")
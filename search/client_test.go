@@ -0,0 +1,83 @@
+package search
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubEngine 记录调用次数的搜索引擎
+type stubEngine struct {
+	name  string
+	calls atomic.Int32
+}
+
+func (e *stubEngine) Name() string { return e.name }
+
+func (e *stubEngine) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	e.calls.Add(1)
+	return []SearchResult{{Title: query, URL: "https://example.com/" + e.name}}, nil
+}
+
+// optionsEngine 记录每次搜索使用的市场代码的搜索引擎
+type optionsEngine struct {
+	stubEngine
+	markets []string
+}
+
+func (e *optionsEngine) SearchWithOptions(ctx context.Context, query string, limit int, opts ...SearchOption) ([]SearchResult, error) {
+	cfg := &SearchConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	e.markets = append(e.markets, cfg.Market)
+	return e.Search(ctx, query, limit)
+}
+
+func TestClientSearchOptions(t *testing.T) {
+	engine := &optionsEngine{stubEngine: stubEngine{name: "opts"}}
+	plain := &stubEngine{name: "plain"}
+	client := NewClient()
+	client.RegisterEngine(engine)
+	client.RegisterEngine(plain)
+	client.SetDefaultEngine("opts")
+	client.SetCache(time.Minute, 0)
+
+	ctx := context.Background()
+	if _, err := client.Search(ctx, "golang", 10, WithMarket("en-US")); err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	if _, err := client.Search(ctx, "golang", 10, WithMarket("zh-CN")); err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	// 参数相同的搜索命中缓存，不带参数时调用 Search
+	client.Search(ctx, "golang", 10, WithMarket("zh-CN"))
+	client.Search(ctx, "golang", 10)
+
+	if len(engine.markets) != 2 || engine.markets[0] != "en-US" || engine.markets[1] != "zh-CN" {
+		t.Errorf("期望按次传入市场代码且不同参数分别缓存，实际为: %v", engine.markets)
+	}
+	if engine.calls.Load() != 3 {
+		t.Errorf("期望搜索引擎被调用3次，实际为 %d", engine.calls.Load())
+	}
+
+	// 不支持按次参数的搜索引擎返回错误
+	if _, err := client.Search(ctx, "golang", 10, WithEngine("plain"), WithFreshness(BingFreshnessDay)); err == nil {
+		t.Error("不支持按次参数的搜索引擎应返回错误")
+	}
+	if plain.calls.Load() != 0 {
+		t.Errorf("期望不调用不支持参数的搜索引擎，实际调用了 %d 次", plain.calls.Load())
+	}
+}
+
+func TestBingSearchWithOptions(t *testing.T) {
+	bing := NewBingSearch("key", WithMarket("zh-CN"), WithSafeSearch(BingSafeSearchStrict))
+	params := bing.withOptions([]SearchOption{WithMarket("en-US")}).searchParams("golang", 10)
+	if params.Get("mkt") != "en-US" || params.Get("safeSearch") != "Strict" {
+		t.Errorf("期望覆盖市场代码并保留其他参数，实际为: %v", params)
+	}
+	if bing.market != "zh-CN" {
+		t.Errorf("按次参数不应修改搜索引擎，实际市场代码为 %s", bing.market)
+	}
+}
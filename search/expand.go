@@ -0,0 +1,227 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/sjzsdu/utils/coroutine"
+)
+
+// DefaultMaxExpansions 查询扩展默认最多额外发出的查询数
+const DefaultMaxExpansions = 3
+
+// Synonyms 同义词词典，每组中的词互为同义词，可以混合中英文，例如 {"人工智能", "AI", "artificial intelligence"}
+// 可以安全地并发使用
+type Synonyms struct {
+	mu     sync.RWMutex
+	groups [][]string
+}
+
+// NewSynonyms 创建包含指定同义词组的词典
+func NewSynonyms(groups ...[]string) *Synonyms {
+	s := &Synonyms{}
+	for _, group := range groups {
+		s.Add(group...)
+	}
+	return s
+}
+
+// LoadSynonyms 从文本读取同义词词典，每行一组以逗号分隔的同义词，空行和 # 开头的行被忽略：
+//
+//	# 人工智能
+//	人工智能, AI, artificial intelligence
+//	大模型, LLM, large language model
+func LoadSynonyms(r io.Reader) (*Synonyms, error) {
+	s := &Synonyms{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		s.Add(strings.Split(strings.ReplaceAll(line, "，", ","), ",")...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取同义词词典失败: %w", err)
+	}
+	return s, nil
+}
+
+// Add 添加一组同义词，少于两个非空词的组被忽略
+func (s *Synonyms) Add(terms ...string) {
+	var group []string
+	for _, term := range terms {
+		if term = strings.Join(strings.Fields(term), " "); term != "" {
+			group = append(group, term)
+		}
+	}
+	if len(group) < 2 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups = append(s.groups, group)
+}
+
+// Expand 返回将查询中的一个词替换为其同义词得到的扩展查询，最多maxQueries个，不包含原查询；maxQueries不大于0时不限制
+// 词的匹配规则与高亮相同：不区分大小写，英文单词需要在单词边界处匹配，中文等文字按子串匹配
+func (s *Synonyms) Expand(query string, maxQueries int) []string {
+	queries, _ := s.expand(query, maxQueries)
+	return queries
+}
+
+// expand 返回扩展查询和扩展查询中使用的同义词
+func (s *Synonyms) expand(query string, maxQueries int) (queries, synonyms []string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := map[string]bool{strings.ToLower(query): true}
+	for _, group := range s.groups {
+		for _, term := range group {
+			spans := FindTerms(query, []string{term})
+			if len(spans) == 0 {
+				continue
+			}
+			// 组内先匹配到的词被替换为其余的同义词
+			for _, synonym := range group {
+				candidate := replaceSpans(query, spans, synonym)
+				if strings.EqualFold(synonym, term) || seen[strings.ToLower(candidate)] {
+					continue
+				}
+				seen[strings.ToLower(candidate)] = true
+				queries = append(queries, candidate)
+				synonyms = append(synonyms, synonym)
+				if maxQueries > 0 && len(queries) >= maxQueries {
+					return queries, synonyms
+				}
+			}
+			break
+		}
+	}
+	return queries, synonyms
+}
+
+// replaceSpans 将文本中所有匹配的部分替换为replacement
+func replaceSpans(text string, spans []Span, replacement string) string {
+	var b strings.Builder
+	offset := 0
+	for _, span := range spans {
+		b.WriteString(text[offset:span.Start])
+		b.WriteString(replacement)
+		offset = span.End
+	}
+	b.WriteString(text[offset:])
+	return b.String()
+}
+
+// ExpansionOptions 查询扩展的选项
+type ExpansionOptions struct {
+	// Synonyms 同义词词典，为nil时不扩展查询
+	Synonyms *Synonyms
+	// MaxQueries 除原查询外最多额外发出的扩展查询数，不大于0时使用 DefaultMaxExpansions
+	MaxQueries int
+}
+
+// SetExpansion 开启查询扩展，Search 和 SearchWithEngine 除原查询外还会用同义词替换后的查询搜索，
+// 并将结果按URL去重后交替合并，提高监控类场景的召回率；传入零值时关闭查询扩展
+// 原查询失败时按原有规则返回错误或尝试备用搜索引擎，扩展查询失败时忽略其结果；
+// 扩展会增加搜索引擎的调用次数，可以通过 WithoutExpansion 为单次搜索关闭
+func (c *Client) SetExpansion(options ExpansionOptions) {
+	c.expansion = options
+}
+
+// noExpansionKey 上下文中关闭查询扩展的键
+type noExpansionKey struct{}
+
+// WithoutExpansion 返回关闭查询扩展的上下文，用该上下文搜索时只发出原查询
+func WithoutExpansion(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noExpansionKey{}, true)
+}
+
+// expand 返回需要额外发出的扩展查询和其中使用的同义词，没有开启扩展或上下文关闭了扩展时返回nil
+func (o ExpansionOptions) expand(ctx context.Context, query string) (queries, synonyms []string) {
+	if o.Synonyms == nil {
+		return nil, nil
+	}
+	if disabled, _ := ctx.Value(noExpansionKey{}).(bool); disabled {
+		return nil, nil
+	}
+	maxQueries := o.MaxQueries
+	if maxQueries <= 0 {
+		maxQueries = DefaultMaxExpansions
+	}
+	return o.Synonyms.expand(query, maxQueries)
+}
+
+// expandedSearch 用原查询和扩展查询并发搜索，合并后最多返回limit个结果
+// 返回的highlightQuery在原查询之后加上扩展使用的同义词，用于高亮；cached表示所有查询的结果都来自缓存
+func (c *Client) expandedSearch(ctx context.Context, engine SearchEngine, query string, limit int, opts []SearchOption) (results []SearchResult, highlightQuery string, cached bool, err error) {
+	expansions, synonyms := c.expansion.expand(ctx, query)
+	if len(expansions) == 0 {
+		results, cached, err := c.search(ctx, engine, query, limit, opts)
+		return results, query, cached, err
+	}
+
+	// searchBatch 单个查询的结果
+	type searchBatch struct {
+		results []SearchResult
+		cached  bool
+	}
+
+	queries := append([]string{query}, expansions...)
+	batches := coroutine.Map(ctx, len(queries), queries, func(q string) (searchBatch, error) {
+		results, cached, err := c.search(ctx, engine, q, limit, opts)
+		return searchBatch{results: results, cached: cached}, err
+	})
+	if batches[0].Err != nil {
+		return nil, query, false, batches[0].Err
+	}
+
+	lists := make([][]SearchResult, 0, len(batches))
+	cached = true
+	for _, batch := range batches {
+		if batch.Err == nil {
+			lists = append(lists, batch.Value.results)
+			cached = cached && batch.Value.cached
+		}
+	}
+
+	highlight := []string{query}
+	for _, synonym := range synonyms {
+		highlight = append(highlight, `"`+synonym+`"`)
+	}
+	return mergeResults(lists, limit), strings.Join(highlight, " "), cached, nil
+}
+
+// mergeResults 交替合并多组结果并按URL去重（没有URL的结果不去重），最多返回limit个，limit不大于0时不限制
+func mergeResults(lists [][]SearchResult, limit int) []SearchResult {
+	var merged []SearchResult
+	seen := make(map[string]bool)
+	for i := 0; ; i++ {
+		added := false
+		for _, list := range lists {
+			if i >= len(list) {
+				continue
+			}
+			added = true
+			if url := list[i].URL; url != "" {
+				if seen[url] {
+					continue
+				}
+				seen[url] = true
+			}
+			merged = append(merged, list[i])
+			if limit > 0 && len(merged) >= limit {
+				return merged
+			}
+		}
+		if !added {
+			return merged
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMergeResults(t *testing.T) {
+	a := []SearchResult{{URL: "a1"}, {URL: "shared"}, {URL: "a3"}}
+	b := []SearchResult{{URL: "shared"}, {URL: "b2"}}
+	c := []SearchResult{{URL: "c1"}, {Title: "no url"}, {Title: "no url"}}
+
+	tests := []struct {
+		name  string
+		lists [][]SearchResult
+		limit int
+		want  []string
+	}{
+		{"交替合并并按URL去重", [][]SearchResult{a, b}, 0, []string{"a1", "shared", "b2", "a3"}},
+		{"原查询的结果优先", [][]SearchResult{b, a}, 0, []string{"shared", "a1", "b2", "a3"}},
+		{"没有URL的结果不去重", [][]SearchResult{c}, 0, []string{"c1", "no url", "no url"}},
+		{"最多返回limit个", [][]SearchResult{a, b, c}, 4, []string{"a1", "shared", "c1", "b2"}},
+		{"没有结果", nil, 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, result := range mergeResults(tt.lists, tt.limit) {
+				got = append(got, cmp.Or(result.URL, result.Title))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("期望 %v，实际为 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSynonymsExpand(t *testing.T) {
+	synonyms, err := LoadSynonyms(strings.NewReader("# 注释\n人工智能, AI，artificial intelligence\n\n大模型, LLM\n"))
+	if err != nil {
+		t.Fatalf("读取同义词词典失败: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		max   int
+		want  []string
+	}{
+		{"AI 芯片", 0, []string{"人工智能 芯片", "artificial intelligence 芯片"}},
+		{"人工智能与大模型", 0, []string{"AI与大模型", "artificial intelligence与大模型", "人工智能与LLM"}},
+		{"AI 芯片", 1, []string{"人工智能 芯片"}},
+		{"SAID 芯片", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := synonyms.Expand(tt.query, tt.max); !slices.Equal(got, tt.want) {
+			t.Errorf("Expand(%q, %d) = %q，期望 %q", tt.query, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestClientExpansion(t *testing.T) {
+	engine := &stubEngine{name: "stub"}
+	client := NewClient()
+	client.RegisterEngine(engine)
+	client.SetDefaultEngine("stub")
+	client.SetExpansion(ExpansionOptions{Synonyms: NewSynonyms([]string{"AI", "人工智能"})})
+
+	results, err := client.Search(context.Background(), "AI", 10)
+	if err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	// 原查询和扩展查询的结果URL相同，合并后只保留原查询的结果
+	if len(results) != 1 || results[0].Title != "AI" || engine.calls.Load() != 2 {
+		t.Errorf("期望合并原查询和扩展查询的结果，实际为 %+v，调用 %d 次", results, engine.calls.Load())
+	}
+
+	client.Search(WithoutExpansion(context.Background()), "AI", 10)
+	if engine.calls.Load() != 3 {
+		t.Errorf("WithoutExpansion 时只应发出原查询，实际调用 %d 次", engine.calls.Load())
+	}
+}
@@ -0,0 +1,161 @@
+package search
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// FilterOptions 搜索结果的后处理选项，对所有搜索引擎的结果生效
+// 过滤在缓存之后进行，过滤后的结果数量可能少于limit
+type FilterOptions struct {
+	// ExcludeDomains 排除的域名，同时排除其子域名，例如 example.com 会排除 www.example.com
+	ExcludeDomains []string
+	// Blocklist 域名黑名单和白名单，支持通配符和远程列表，可以在多个客户端之间共享
+	Blocklist *Blocklist
+	// Language 只保留标题和摘要为该语言的结果，支持 zh、ja、ko、ru、ar 以及使用拉丁字母的 en，
+	// 可以带地区后缀（如 zh-CN）；为空时不按语言过滤
+	Language string
+	// StripTracking 移除结果URL中的跟踪参数，如 utm_source、gclid、fbclid
+	StripTracking bool
+	// DedupeTitles 去除标题重复的结果，只保留第一个
+	DedupeTitles bool
+	// KeepFiltered 保留被过滤的结果，并在 SearchResult.Filtered 中标记过滤原因，用于排查和展示
+	KeepFiltered bool
+}
+
+// FilterReason 结果被过滤的原因
+type FilterReason string
+
+const (
+	// FilterExcludedDomain 属于 ExcludeDomains 中的域名
+	FilterExcludedDomain FilterReason = "excluded_domain"
+	// FilterBlocked 被 Blocklist 排除
+	FilterBlocked FilterReason = "blocked"
+	// FilterLanguage 语言与 Language 不符
+	FilterLanguage FilterReason = "language"
+	// FilterDuplicateTitle 标题与之前的结果重复
+	FilterDuplicateTitle FilterReason = "duplicate_title"
+)
+
+// trackingParams 常见的跟踪参数，utm_ 开头的参数同样会被移除
+var trackingParams = []string{
+	"gclid", "dclid", "fbclid", "msclkid", "yclid", "igshid",
+	"mc_cid", "mc_eid", "_hsenc", "_hsmi", "spm", "from_source",
+}
+
+// SetFilter 设置搜索结果的后处理选项，传入零值时关闭后处理
+func (c *Client) SetFilter(options FilterOptions) {
+	c.filter = options
+}
+
+// apply 按选项过滤和改写搜索结果，不修改传入的切片
+func (f FilterOptions) apply(results []SearchResult) []SearchResult {
+	if len(f.ExcludeDomains) == 0 && f.Blocklist == nil && f.Language == "" && !f.StripTracking && !f.DedupeTitles {
+		return results
+	}
+
+	language, _, _ := strings.Cut(strings.ToLower(f.Language), "-")
+	titles := make(map[string]bool)
+	filtered := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		result.Filtered = f.reason(result, language, titles)
+		if result.Filtered != "" && !f.KeepFiltered {
+			continue
+		}
+		if f.StripTracking {
+			result.URL = stripTracking(result.URL)
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
+// reason 返回结果被过滤的原因，保留的结果返回空字符串；titles记录已保留结果的标题
+func (f FilterOptions) reason(result SearchResult, language string, titles map[string]bool) FilterReason {
+	if f.excluded(result.URL) {
+		return FilterExcludedDomain
+	}
+	if f.Blocklist != nil && f.Blocklist.Blocked(result.URL) {
+		return FilterBlocked
+	}
+	if language != "" && detectLanguage(result.Title+" "+result.Snippet) != language {
+		return FilterLanguage
+	}
+	if f.DedupeTitles {
+		title := strings.ToLower(strings.Join(strings.Fields(result.Title), " "))
+		if titles[title] {
+			return FilterDuplicateTitle
+		}
+		titles[title] = true
+	}
+	return ""
+}
+
+// excluded 判断URL是否属于排除的域名
+func (f FilterOptions) excluded(rawURL string) bool {
+	if len(f.ExcludeDomains) == 0 {
+		return false
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range f.ExcludeDomains {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripTracking 移除URL中的跟踪参数，无法解析的URL原样返回
+func stripTracking(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || slices.Contains(trackingParams, strings.ToLower(key)) {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// detectLanguage 根据文字的书写系统粗略判断语言
+// 含假名判断为 ja，含谚文判断为 ko，否则按汉字、西里尔字母、阿拉伯字母和拉丁字母中最多的一种判断
+// 中文里常夹杂英文名词，一个汉字按两个字母计数
+func detectLanguage(text string) string {
+	counts := make(map[string]int)
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			return "ja"
+		case unicode.Is(unicode.Hangul, r):
+			return "ko"
+		case unicode.Is(unicode.Han, r):
+			counts["zh"] += 2
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Latin, r):
+			counts["en"]++
+		}
+	}
+
+	language, best := "", 0
+	for _, candidate := range []string{"zh", "ru", "ar", "en"} {
+		if counts[candidate] > best {
+			language, best = candidate, counts[candidate]
+		}
+	}
+	return language
+}
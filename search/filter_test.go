@@ -0,0 +1,81 @@
+package search
+
+import "testing"
+
+func TestFilterOptions(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Go 语言教程", URL: "https://go.dev/doc?utm_source=x&id=1"},
+		{Title: "Go 语言教程", URL: "https://mirror.dev/doc"},
+		{Title: "广告页面内容", URL: "https://www.ads.example.com/"},
+		{Title: "English only article", URL: "https://blog.test/a?gclid=abc"},
+	}
+
+	tests := []struct {
+		name    string
+		options FilterOptions
+		want    []string
+	}{
+		{"零值不过滤", FilterOptions{}, []string{"https://go.dev/doc?utm_source=x&id=1", "https://mirror.dev/doc", "https://www.ads.example.com/", "https://blog.test/a?gclid=abc"}},
+		{"排除子域名", FilterOptions{ExcludeDomains: []string{".Example.com"}}, []string{"https://go.dev/doc?utm_source=x&id=1", "https://mirror.dev/doc", "https://blog.test/a?gclid=abc"}},
+		{"按语言过滤", FilterOptions{Language: "zh-CN"}, []string{"https://go.dev/doc?utm_source=x&id=1", "https://mirror.dev/doc", "https://www.ads.example.com/"}},
+		{"标题去重", FilterOptions{DedupeTitles: true}, []string{"https://go.dev/doc?utm_source=x&id=1", "https://www.ads.example.com/", "https://blog.test/a?gclid=abc"}},
+		{"移除跟踪参数", FilterOptions{StripTracking: true, Language: "en"}, []string{"https://blog.test/a"}},
+		{"黑名单", FilterOptions{Blocklist: NewBlocklist([]string{"*.dev"}, []string{"go.dev"})}, []string{"https://go.dev/doc?utm_source=x&id=1", "https://www.ads.example.com/", "https://blog.test/a?gclid=abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := tt.options.apply(results)
+			if len(filtered) != len(tt.want) {
+				t.Fatalf("期望 %d 个结果，实际为 %+v", len(tt.want), filtered)
+			}
+			for i, result := range filtered {
+				if result.URL != tt.want[i] {
+					t.Errorf("第 %d 个结果期望 %s，实际为 %s", i, tt.want[i], result.URL)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterKeepFiltered(t *testing.T) {
+	results := []SearchResult{
+		{Title: "a", URL: "https://spam.com/"},
+		{Title: "b", URL: "https://ok.com/"},
+		{Title: "B", URL: "https://ok.org/"},
+	}
+	filtered := FilterOptions{ExcludeDomains: []string{"spam.com"}, DedupeTitles: true, KeepFiltered: true}.apply(results)
+
+	want := []FilterReason{FilterExcludedDomain, "", FilterDuplicateTitle}
+	if len(filtered) != len(want) {
+		t.Fatalf("期望保留所有结果，实际为 %+v", filtered)
+	}
+	for i, result := range filtered {
+		if result.Filtered != want[i] {
+			t.Errorf("第 %d 个结果期望过滤原因 %q，实际为 %q", i, want[i], result.Filtered)
+		}
+	}
+	if results[0].Filtered != "" {
+		t.Error("不应修改传入的结果")
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Go 语言的并发模型", "zh"},
+		{"使用 Kubernetes 部署和管理容器服务", "zh"},
+		{"プログラミング入門", "ja"},
+		{"한국어 뉴스", "ko"},
+		{"Новости технологий", "ru"},
+		{"أخبار التقنية", "ar"},
+		{"Hello world", "en"},
+		{"12345", ""},
+	}
+	for _, tt := range tests {
+		if got := detectLanguage(tt.text); got != tt.want {
+			t.Errorf("detectLanguage(%q) = %q，期望 %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -17,9 +17,12 @@ type GoogleSearch struct {
 	searchEngineId string
 	timeout        int
 	headers        map[string]string
+	tokenSource    TokenSource
+	quotaProject   string
 }
 
 // NewGoogleSearch 创建google搜索引擎实例
+// 通过 WithTokenSource 设置OAuth访问令牌（如服务账号）时不再需要API密钥
 func NewGoogleSearch(apiKey, searchEngineId string, opts ...SearchOption) *GoogleSearch {
 	// 如果未提供API密钥，从环境变量获取
 	if apiKey == "" {
@@ -45,6 +48,8 @@ func NewGoogleSearch(apiKey, searchEngineId string, opts ...SearchOption) *Googl
 		searchEngineId: searchEngineId,
 		timeout:        cfg.Timeout,
 		headers:        cfg.Headers,
+		tokenSource:    cfg.TokenSource,
+		quotaProject:   cfg.QuotaProject,
 	}
 }
 
@@ -55,9 +60,12 @@ func (g *GoogleSearch) Name() string {
 
 // Search 执行搜索并返回结果
 func (g *GoogleSearch) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
-	// 构建API URL
-	searchURL := fmt.Sprintf("https://www.googleapis.com/customsearch/v1?key=%s&cx=%s&q=%s&num=%d",
-		g.apiKey, g.searchEngineId, url.QueryEscape(query), limit)
+	// 构建API URL，使用访问令牌时不需要API密钥
+	searchURL := fmt.Sprintf("https://www.googleapis.com/customsearch/v1?cx=%s&q=%s&num=%d",
+		g.searchEngineId, url.QueryEscape(query), limit)
+	if g.tokenSource == nil {
+		searchURL += "&key=" + url.QueryEscape(g.apiKey)
+	}
 
 	// 创建HTTP客户端
 	client := &http.Client{
@@ -73,6 +81,18 @@ func (g *GoogleSearch) Search(ctx context.Context, query string, limit int) ([]S
 	// 设置请求头
 	httpReq.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.114 Safari/537.36")
 
+	// 设置鉴权信息和配额项目
+	if g.tokenSource != nil {
+		token, err := g.tokenSource.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取访问令牌失败: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	if g.quotaProject != "" {
+		httpReq.Header.Set("X-Goog-User-Project", g.quotaProject)
+	}
+
 	// 添加自定义请求头
 	for k, v := range g.headers {
 		httpReq.Header.Set(k, v)
@@ -133,3 +153,62 @@ func parseGoogleSearchResults(data []byte, limit int) ([]SearchResult, error) {
 
 	return results, nil
 }
+
+// Suggest 使用Google suggestqueries接口返回搜索建议，该接口不需要API密钥
+func (g *GoogleSearch) Suggest(ctx context.Context, prefix string) ([]string, error) {
+	suggestURL := fmt.Sprintf("https://suggestqueries.google.com/complete/search?client=firefox&ie=utf-8&oe=utf-8&q=%s",
+		url.QueryEscape(prefix))
+
+	client := &http.Client{
+		Timeout: time.Duration(g.timeout) * time.Second,
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", suggestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+
+	httpReq.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.114 Safari/537.36")
+	for k, v := range g.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP请求失败，状态码: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应内容失败: %v", err)
+	}
+
+	suggestions, err := parseGoogleSuggestions(body)
+	if err != nil {
+		return nil, fmt.Errorf("解析搜索建议失败: %v", err)
+	}
+
+	return suggestions, nil
+}
+
+// parseGoogleSuggestions 解析Google搜索建议，响应格式为 ["查询", ["建议1", "建议2", ...]]
+func parseGoogleSuggestions(data []byte) ([]string, error) {
+	var response []json.RawMessage
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	if len(response) < 2 {
+		return nil, fmt.Errorf("响应格式不正确")
+	}
+
+	var suggestions []string
+	if err := json.Unmarshal(response[1], &suggestions); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,212 @@
+package search
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GoogleCSEScope Custom Search JSON API的OAuth授权范围
+const GoogleCSEScope = "https://www.googleapis.com/auth/cse"
+
+// TokenSource 提供OAuth访问令牌，用于代替API密钥鉴权
+type TokenSource interface {
+	// Token 返回当前有效的访问令牌
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken 返回固定访问令牌的 TokenSource，令牌由调用方负责刷新
+func StaticToken(token string) TokenSource {
+	return staticToken(token)
+}
+
+// staticToken 固定的访问令牌
+type staticToken string
+
+// Token 返回固定的访问令牌
+func (t staticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// serviceAccountKey 服务账号密钥文件中用到的字段
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// serviceAccountTokenSource 使用服务账号签发JWT换取访问令牌，令牌过期前自动刷新
+type serviceAccountTokenSource struct {
+	key        serviceAccountKey
+	privateKey *rsa.PrivateKey
+	scopes     []string
+	client     *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewServiceAccountTokenSource 根据服务账号的JSON密钥创建 TokenSource，scopes为空时使用 GoogleCSEScope
+func NewServiceAccountTokenSource(jsonKey []byte, scopes ...string) (TokenSource, error) {
+	var key serviceAccountKey
+	if err := json.Unmarshal(jsonKey, &key); err != nil {
+		return nil, fmt.Errorf("解析服务账号密钥失败: %w", err)
+	}
+	if key.Type != "" && key.Type != "service_account" {
+		return nil, fmt.Errorf("不支持的凭证类型: %s", key.Type)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("服务账号密钥缺少 client_email 或 private_key")
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	privateKey, err := parseRSAPrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scopes) == 0 {
+		scopes = []string{GoogleCSEScope}
+	}
+	return &serviceAccountTokenSource{
+		key:        key,
+		privateKey: privateKey,
+		scopes:     scopes,
+		client:     &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// NewServiceAccountTokenSourceFromFile 从服务账号的JSON密钥文件创建 TokenSource
+func NewServiceAccountTokenSourceFromFile(path string, scopes ...string) (TokenSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取服务账号密钥文件失败: %w", err)
+	}
+	return NewServiceAccountTokenSource(data, scopes...)
+}
+
+// parseRSAPrivateKey 解析PEM格式的RSA私钥，支持PKCS#8和PKCS#1
+func parseRSAPrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("服务账号私钥不是PEM格式")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("服务账号私钥不是RSA私钥")
+		}
+		return rsaKey, nil
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析服务账号私钥失败: %w", err)
+	}
+	return key, nil
+}
+
+// Token 返回缓存的访问令牌，即将过期时重新换取
+func (s *serviceAccountTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	assertion, err := s.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.key.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("获取访问令牌失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("读取响应内容失败: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("获取访问令牌失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("解析访问令牌失败: %v", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("响应中没有访问令牌")
+	}
+
+	// 提前一分钟刷新，避免请求途中过期
+	s.token = token.AccessToken
+	s.expires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return s.token, nil
+}
+
+// assertion 生成用于换取访问令牌的RS256签名JWT
+func (s *serviceAccountTokenSource) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": s.key.PrivateKeyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   s.key.ClientEmail,
+		"scope": strings.Join(s.scopes, " "),
+		"aud":   s.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("签名JWT失败: %w", err)
+	}
+	return unsigned + "." + encoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,199 @@
+package search
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 默认的高亮标签
+const (
+	DefaultHighlightPre  = "<b>"
+	DefaultHighlightPost = "</b>"
+)
+
+// HighlightOptions 搜索结果高亮的选项
+type HighlightOptions struct {
+	// Enabled 为true时在结果中标记查询词的位置，并生成高亮的HTML
+	Enabled bool
+	// Pre 和 Post 包裹匹配词的HTML标签，为空时使用 DefaultHighlightPre 和 DefaultHighlightPost
+	Pre  string
+	Post string
+}
+
+// Highlight 查询词在搜索结果标题和摘要中的匹配位置
+type Highlight struct {
+	Title   []Span `json:"title,omitempty"`   // 标题中的匹配
+	Snippet []Span `json:"snippet,omitempty"` // 摘要中的匹配
+
+	TitleHTML   string `json:"title_html"`   // HTML转义后的标题，匹配词用高亮标签包裹
+	SnippetHTML string `json:"snippet_html"` // HTML转义后的摘要，匹配词用高亮标签包裹
+}
+
+// Span 文本中的一处匹配，Start 和 End 为字节偏移，text[Start:End] 即匹配的原文
+type Span struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Term  string `json:"term"` // 匹配的查询词
+}
+
+// SetHighlight 设置搜索结果高亮，开启后 Search 和 SearchWithEngine 返回的结果带有 Highlight
+// 高亮在过滤之后进行且不会被缓存；传入零值时关闭高亮
+func (c *Client) SetHighlight(options HighlightOptions) {
+	c.highlight = options
+}
+
+// apply 为结果标记查询词，返回新的切片，不修改传入的结果
+func (o HighlightOptions) apply(query string, results []SearchResult) []SearchResult {
+	if !o.Enabled || len(results) == 0 {
+		return results
+	}
+	return HighlightResults(query, results, o.Pre, o.Post)
+}
+
+// HighlightResults 为每个结果标记查询词在标题和摘要中的位置并生成高亮的HTML，返回新的切片，不修改传入的结果
+// pre 和 post 为包裹匹配词的标签，为空时使用 DefaultHighlightPre 和 DefaultHighlightPost
+func HighlightResults(query string, results []SearchResult, pre, post string) []SearchResult {
+	if pre == "" && post == "" {
+		pre, post = DefaultHighlightPre, DefaultHighlightPost
+	}
+
+	terms := QueryTerms(query)
+	highlighted := make([]SearchResult, len(results))
+	for i, result := range results {
+		title, snippet := FindTerms(result.Title, terms), FindTerms(result.Snippet, terms)
+		result.Highlight = &Highlight{
+			Title:       title,
+			Snippet:     snippet,
+			TitleHTML:   HighlightHTML(result.Title, title, pre, post),
+			SnippetHTML: HighlightHTML(result.Snippet, snippet, pre, post),
+		}
+		highlighted[i] = result
+	}
+	return highlighted
+}
+
+// QueryTerms 从查询中提取用于高亮的词：按空白分隔，双引号中的短语作为一个词，忽略排除词（-开头）、
+// 带冒号的运算符（如 site:example.com）以及 OR、AND 等逻辑运算符，重复的词只保留一个
+func QueryTerms(query string) []string {
+	var terms []string
+	add := func(term string) {
+		term = strings.Join(strings.Fields(term), " ")
+		if term == "" || slices.ContainsFunc(terms, func(t string) bool { return strings.EqualFold(t, term) }) {
+			return
+		}
+		terms = append(terms, term)
+	}
+
+	for query != "" {
+		query = strings.TrimLeftFunc(query, unicode.IsSpace)
+		if phrase, ok := strings.CutPrefix(query, `"`); ok {
+			phrase, query, _ = strings.Cut(phrase, `"`)
+			add(phrase)
+			continue
+		}
+
+		field := query
+		if i := strings.IndexFunc(query, unicode.IsSpace); i >= 0 {
+			field, query = query[:i], query[i:]
+		} else {
+			query = ""
+		}
+		if strings.HasPrefix(field, "-") || strings.Contains(field, ":") {
+			continue
+		}
+		if field == "OR" || field == "AND" || field == "NOT" || field == "|" {
+			continue
+		}
+		add(strings.Trim(field, "\"'“”‘’()+"))
+	}
+	return terms
+}
+
+// FindTerms 返回词在文本中的所有匹配，不区分大小写，按位置排序且互不重叠
+// 同一位置匹配多个词时取最长的词；由英文字母或数字开头或结尾的词需要在单词边界处匹配，
+// 例如 go 不匹配 google，中文等没有空格分词的文字不受此限制
+func FindTerms(text string, terms []string) []Span {
+	if text == "" || len(terms) == 0 {
+		return nil
+	}
+
+	var spans []Span
+	for i := 0; i < len(text); {
+		best := Span{Start: i, End: -1}
+		for _, term := range terms {
+			if end := matchTerm(text, i, term); end > best.End {
+				best.End, best.Term = end, term
+			}
+		}
+		if best.End > i {
+			spans = append(spans, best)
+			i = best.End
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return spans
+}
+
+// matchTerm 判断词是否从text的第start个字节开始匹配，匹配时返回结束位置，否则返回-1
+func matchTerm(text string, start int, term string) int {
+	if term == "" {
+		return -1
+	}
+	end := start
+	for _, want := range term {
+		if end >= len(text) {
+			return -1
+		}
+		got, size := utf8.DecodeRuneInString(text[end:])
+		if got != want && unicode.ToLower(got) != unicode.ToLower(want) {
+			return -1
+		}
+		end += size
+	}
+
+	// 英文单词需要在单词边界处匹配
+	first, _ := utf8.DecodeRuneInString(term)
+	if isWordRune(first) && start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		if isWordRune(before) {
+			return -1
+		}
+	}
+	last, _ := utf8.DecodeLastRuneInString(term)
+	if isWordRune(last) && end < len(text) {
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if isWordRune(after) {
+			return -1
+		}
+	}
+	return end
+}
+
+// isWordRune 判断字符是否为组成英文单词的字母或数字
+func isWordRune(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// HighlightHTML 对文本进行HTML转义，并用pre和post包裹匹配的部分
+// spans 需要按位置排序且互不重叠，例如 FindTerms 的返回值
+func HighlightHTML(text string, spans []Span, pre, post string) string {
+	var b strings.Builder
+	offset := 0
+	for _, span := range spans {
+		if span.Start < offset || span.End > len(text) || span.Start >= span.End {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[offset:span.Start]))
+		b.WriteString(pre)
+		b.WriteString(html.EscapeString(text[span.Start:span.End]))
+		b.WriteString(post)
+		offset = span.End
+	}
+	b.WriteString(html.EscapeString(text[offset:]))
+	return b.String()
+}
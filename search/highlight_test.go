@@ -0,0 +1,71 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"golang 并发", []string{"golang", "并发"}},
+		{`"machine learning" -ads site:example.com`, []string{"machine learning"}},
+		{"Go OR go AND rust", []string{"Go", "rust"}},
+		{"(人工智能) +芯片", []string{"人工智能", "芯片"}},
+	}
+	for _, tt := range tests {
+		if got := QueryTerms(tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("QueryTerms(%q) = %q，期望 %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFindTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  []Span
+	}{
+		{"中文按子串匹配", "学习人工智能技术", []string{"人工智能"}, []Span{{Start: 6, End: 18, Term: "人工智能"}}},
+		{"中英混合", "AI芯片与Go语言", []string{"芯片", "go"}, []Span{{Start: 2, End: 8, Term: "芯片"}, {Start: 11, End: 13, Term: "go"}}},
+		{"英文需要单词边界", "google go gopher", []string{"go"}, []Span{{Start: 7, End: 9, Term: "go"}}},
+		{"不区分大小写", "Golang GOLANG", []string{"golang"}, []Span{{Start: 0, End: 6, Term: "golang"}, {Start: 7, End: 13, Term: "golang"}}},
+		{"同一位置取最长的词", "大模型应用", []string{"大模型", "大模型应用"}, []Span{{Start: 0, End: 15, Term: "大模型应用"}}},
+		{"没有匹配", "你好", []string{"世界"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindTerms(tt.text, tt.terms)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("期望 %+v，实际为 %+v", tt.want, got)
+			}
+			// 偏移为字节偏移，切片即为匹配的原文
+			for _, span := range got {
+				if matched := tt.text[span.Start:span.End]; !equalFoldTerm(matched, span.Term) {
+					t.Errorf("text[%d:%d] = %q，与匹配词 %q 不符", span.Start, span.End, matched, span.Term)
+				}
+			}
+		})
+	}
+}
+
+// equalFoldTerm 不区分大小写比较匹配的原文和词
+func equalFoldTerm(text, term string) bool {
+	return matchTerm(text, 0, term) == len(text)
+}
+
+func TestHighlightHTML(t *testing.T) {
+	text := "<AI>与人工智能"
+	spans := FindTerms(text, []string{"ai", "人工智能"})
+	if got := HighlightHTML(text, spans, "<em>", "</em>"); got != "&lt;<em>AI</em>&gt;与<em>人工智能</em>" {
+		t.Errorf("高亮HTML不符: %s", got)
+	}
+
+	results := HighlightResults("人工智能", []SearchResult{{Title: "人工智能", Snippet: "无关"}}, "", "")
+	if h := results[0].Highlight; h == nil || h.TitleHTML != "<b>人工智能</b>" || h.SnippetHTML != "无关" || len(h.Snippet) != 0 {
+		t.Errorf("期望使用默认标签高亮标题，实际为 %+v", h)
+	}
+}
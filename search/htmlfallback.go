@@ -0,0 +1,421 @@
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// HTMLFallbackName 网页抓取搜索引擎的名称
+const HTMLFallbackName = "htmlfallback"
+
+// HTMLFallbackEnv 开启网页抓取搜索引擎的环境变量，值为 true 或 1 时开启
+const HTMLFallbackEnv = "SEARCH_HTML_FALLBACK"
+
+const (
+	// DefaultHTMLFallbackInterval 对同一站点两次搜索请求的默认最小间隔
+	DefaultHTMLFallbackInterval = 10 * time.Second
+	// DefaultHTMLFallbackCacheTTL 抓取结果的默认缓存时间
+	DefaultHTMLFallbackCacheTTL = time.Hour
+	// DefaultHTMLFallbackUserAgent 默认的User-Agent，如实表明请求来自本库而不是浏览器
+	DefaultHTMLFallbackUserAgent = "sjzsdu-utils-search/1.0 (+https://github.com/sjzsdu/utils)"
+)
+
+// htmlFallbackMaxLimit 只抓取第一页结果，单次搜索最多返回的结果数量
+const htmlFallbackMaxLimit = 10
+
+// htmlFallbackCacheEntries 抓取结果缓存的最大条目数
+const htmlFallbackCacheEntries = 500
+
+// htmlFallbackMaxBody 读取搜索结果页面和robots.txt的最大字节数
+const htmlFallbackMaxBody = 2 << 20
+
+// robotsTTL robots.txt 的缓存时间
+const robotsTTL = 24 * time.Hour
+
+const (
+	// robotsRetryInterval 获取robots.txt失败后第一次重试前的等待时间，之后每次失败加倍
+	robotsRetryInterval = time.Minute
+	// robotsMaxRetryInterval 获取robots.txt失败后重试的最长等待时间
+	robotsMaxRetryInterval = 30 * time.Minute
+)
+
+// HTMLFallbackProvider 网页抓取使用的搜索结果页面
+type HTMLFallbackProvider string
+
+const (
+	// HTMLFallbackDuckDuckGo DuckDuckGo的无脚本HTML页面
+	HTMLFallbackDuckDuckGo HTMLFallbackProvider = "duckduckgo"
+	// HTMLFallbackBing Bing的搜索结果页面
+	HTMLFallbackBing HTMLFallbackProvider = "bing"
+)
+
+// ErrHTMLFallbackDisabled 未开启网页抓取时搜索返回的错误
+var ErrHTMLFallbackDisabled = errors.New("网页抓取搜索未开启，请设置 HTMLFallbackOptions.Enabled 或环境变量 " + HTMLFallbackEnv)
+
+// ErrDisallowedByRobots 站点的robots.txt不允许抓取搜索结果页面时返回的错误
+var ErrDisallowedByRobots = errors.New("robots.txt 不允许抓取")
+
+// HTMLFallbackOptions 网页抓取搜索引擎的选项
+type HTMLFallbackOptions struct {
+	Enabled     bool                   // 是否开启，默认关闭，抓取前请确认符合所用站点的服务条款
+	Providers   []HTMLFallbackProvider // 按顺序尝试的搜索结果页面，默认先DuckDuckGo后Bing
+	MinInterval time.Duration          // 对同一站点两次搜索请求的最小间隔，小于等于0时使用 DefaultHTMLFallbackInterval
+	CacheTTL    time.Duration          // 抓取结果的缓存时间，小于等于0时使用 DefaultHTMLFallbackCacheTTL
+	UserAgent   string                 // 请求使用的User-Agent，为空时使用 DefaultHTMLFallbackUserAgent
+}
+
+// HTMLFallbackSearch 抓取搜索结果网页的搜索引擎，供没有配置任何API密钥的用户使用
+// 每次抓取前检查站点的robots.txt，对同一站点限制请求频率，并缓存抓取结果；
+// 页面结构变化时可能解析不到结果，需要稳定的搜索服务时请使用API搜索引擎
+type HTMLFallbackSearch struct {
+	options HTMLFallbackOptions
+	timeout int
+	headers map[string]string
+	cache   *resultCache
+
+	mu     sync.Mutex
+	next   map[string]time.Time    // 各站点下一次允许请求的时间
+	robots map[string]*robotsEntry // 各站点的robots.txt规则
+}
+
+// robotsEntry 缓存的robots.txt规则
+type robotsEntry struct {
+	rules     *robotsRules
+	expiresAt time.Time
+	failures  int // 连续获取失败的次数，用于计算重试间隔
+}
+
+// htmlFallbackEndpoints 各搜索结果页面的地址
+var htmlFallbackEndpoints = map[HTMLFallbackProvider]string{
+	HTMLFallbackDuckDuckGo: "https://html.duckduckgo.com/html/",
+	HTMLFallbackBing:       "https://www.bing.com/search",
+}
+
+// NewHTMLFallbackSearch 创建网页抓取搜索引擎实例，opts 支持 WithTimeout 和 WithHeaders
+// options.Enabled 为false时创建的实例在搜索时返回 ErrHTMLFallbackDisabled
+func NewHTMLFallbackSearch(options HTMLFallbackOptions, opts ...SearchOption) *HTMLFallbackSearch {
+	cfg := &SearchConfig{
+		Timeout: 15, // 默认15秒超时
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if len(options.Providers) == 0 {
+		options.Providers = []HTMLFallbackProvider{HTMLFallbackDuckDuckGo, HTMLFallbackBing}
+	} else {
+		options.Providers = slices.Clone(options.Providers)
+	}
+	if options.MinInterval <= 0 {
+		options.MinInterval = DefaultHTMLFallbackInterval
+	}
+	if options.CacheTTL <= 0 {
+		options.CacheTTL = DefaultHTMLFallbackCacheTTL
+	}
+	if options.UserAgent == "" {
+		options.UserAgent = DefaultHTMLFallbackUserAgent
+	}
+
+	return &HTMLFallbackSearch{
+		options: options,
+		timeout: cfg.Timeout,
+		headers: cfg.Headers,
+		cache:   newResultCache(options.CacheTTL, htmlFallbackCacheEntries),
+		next:    make(map[string]time.Time),
+		robots:  make(map[string]*robotsEntry),
+	}
+}
+
+// HTMLFallbackEnabled 判断环境变量 HTMLFallbackEnv 是否开启了网页抓取搜索
+func HTMLFallbackEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(HTMLFallbackEnv))
+	return enabled
+}
+
+// Name 返回搜索引擎名称
+func (h *HTMLFallbackSearch) Name() string {
+	return HTMLFallbackName
+}
+
+// Info 返回网页抓取搜索的能力，只抓取第一页网页结果，不支持时效和市场
+func (h *HTMLFallbackSearch) Info() EngineInfo {
+	return EngineInfo{
+		Name:     h.Name(),
+		Modes:    []SearchMode{SearchModeWeb},
+		MaxLimit: htmlFallbackMaxLimit,
+	}
+}
+
+// Search 执行搜索并返回结果，按顺序尝试各搜索结果页面，全部失败时返回所有错误
+func (h *HTMLFallbackSearch) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	if !h.options.Enabled {
+		return nil, ErrHTMLFallbackDisabled
+	}
+	if limit <= 0 || limit > htmlFallbackMaxLimit {
+		limit = htmlFallbackMaxLimit
+	}
+
+	key := cacheKey(HTMLFallbackName, query, limit, "")
+	if results, ok := h.cache.get(key); ok {
+		return results, nil
+	}
+
+	var errs []error
+	for _, provider := range h.options.Providers {
+		results, err := h.searchProvider(ctx, provider, query, limit)
+		if err == nil {
+			h.cache.set(key, results)
+			return results, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// searchProvider 抓取并解析一个搜索结果页面
+func (h *HTMLFallbackSearch) searchProvider(ctx context.Context, provider HTMLFallbackProvider, query string, limit int) ([]SearchResult, error) {
+	endpoint, ok := htmlFallbackEndpoints[provider]
+	if !ok {
+		return nil, fmt.Errorf("不支持的搜索结果页面 %q", provider)
+	}
+
+	params := url.Values{"q": {query}}
+	if provider == HTMLFallbackBing {
+		params.Set("count", strconv.Itoa(limit))
+	}
+	pageURL, err := url.Parse(endpoint + "?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("构建请求地址失败: %v", err)
+	}
+
+	rules, err := h.robotsRules(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if !rules.allowed(pageURL.RequestURI()) {
+		return nil, ErrDisallowedByRobots
+	}
+
+	if err := h.wait(ctx, pageURL.Host); err != nil {
+		return nil, err
+	}
+
+	body, err := h.get(ctx, pageURL.String(), "text/html")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(body, htmlFallbackMaxBody))
+	if err != nil {
+		return nil, fmt.Errorf("解析搜索结果页面失败: %v", err)
+	}
+
+	if provider == HTMLFallbackBing {
+		return parseBingHTML(doc, limit), nil
+	}
+	return parseDuckDuckGoHTML(doc, limit), nil
+}
+
+// wait 等待直到允许再次请求host，保证对同一站点的请求间隔不小于 MinInterval
+func (h *HTMLFallbackSearch) wait(ctx context.Context, host string) error {
+	h.mu.Lock()
+	at := time.Now()
+	if next := h.next[host]; next.After(at) {
+		at = next
+	}
+	h.next[host] = at.Add(h.options.MinInterval)
+	h.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// robotsRules 返回站点的robots.txt规则，获取成功后每个站点每天最多获取一次
+// 按RFC 9309，robots.txt 不存在（4xx）时允许抓取，服务器错误或网络错误时禁止抓取，
+// 并在 robotsRetryInterval 后重试，连续失败时重试间隔加倍，最长为 robotsMaxRetryInterval
+func (h *HTMLFallbackSearch) robotsRules(ctx context.Context, pageURL *url.URL) (*robotsRules, error) {
+	h.mu.Lock()
+	entry, ok := h.robots[pageURL.Host]
+	h.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.rules, nil
+	}
+
+	failures := 0
+	if ok && entry.rules.disallowAll {
+		failures = entry.failures
+	}
+
+	robotsURL := pageURL.Scheme + "://" + pageURL.Host + "/robots.txt"
+	rules := &robotsRules{disallowAll: true}
+	body, err := h.get(ctx, robotsURL, "text/plain")
+	var statusErr *htmlStatusError
+	switch {
+	case err == nil:
+		rules = parseRobots(io.LimitReader(body, htmlFallbackMaxBody), h.agentName())
+		body.Close()
+	case errors.As(err, &statusErr) && statusErr.code >= 400 && statusErr.code < 500:
+		rules = &robotsRules{}
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	}
+
+	ttl := robotsTTL
+	if rules.disallowAll {
+		ttl = min(robotsRetryInterval<<min(failures, 10), robotsMaxRetryInterval)
+		failures++
+	} else {
+		failures = 0
+	}
+
+	h.mu.Lock()
+	h.robots[pageURL.Host] = &robotsEntry{rules: rules, expiresAt: time.Now().Add(ttl), failures: failures}
+	h.mu.Unlock()
+	return rules, nil
+}
+
+// agentName 返回User-Agent的产品名，用于匹配robots.txt中的分组
+func (h *HTMLFallbackSearch) agentName() string {
+	name, _, _ := strings.Cut(h.options.UserAgent, "/")
+	return strings.TrimSpace(name)
+}
+
+// htmlStatusError 请求返回了非200的状态码
+type htmlStatusError struct {
+	code int
+}
+
+func (e *htmlStatusError) Error() string {
+	return fmt.Sprintf("HTTP请求失败，状态码: %d", e.code)
+}
+
+// get 发送GET请求，状态码为200时返回响应内容
+func (h *HTMLFallbackSearch) get(ctx context.Context, rawURL, accept string) (io.ReadCloser, error) {
+	// 创建HTTP客户端
+	client := &http.Client{
+		Timeout: time.Duration(h.timeout) * time.Second,
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+
+	// 添加自定义请求头，User-Agent 始终使用选项中的值
+	for k, v := range h.headers {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("User-Agent", h.options.UserAgent)
+	httpReq.Header.Set("Accept", accept)
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &htmlStatusError{code: resp.StatusCode}
+	}
+	return resp.Body, nil
+}
+
+// parseDuckDuckGoHTML 解析DuckDuckGo的HTML搜索结果，跳过广告
+func parseDuckDuckGoHTML(doc *goquery.Document, limit int) []SearchResult {
+	var results []SearchResult
+	doc.Find(".result").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		if s.HasClass("result--ad") {
+			return true
+		}
+		link := s.Find("a.result__a").First()
+		href, _ := link.Attr("href")
+		result := SearchResult{
+			Title:   strings.TrimSpace(link.Text()),
+			URL:     duckDuckGoTarget(href),
+			Snippet: strings.TrimSpace(s.Find(".result__snippet").First().Text()),
+		}
+		if result.Title != "" && result.URL != "" {
+			results = append(results, result)
+		}
+		return len(results) < limit
+	})
+	return results
+}
+
+// duckDuckGoTarget 还原DuckDuckGo跳转链接中的目标地址
+func duckDuckGoTarget(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if strings.HasSuffix(u.Host, "duckduckgo.com") && strings.HasPrefix(u.Path, "/l/") {
+		return u.Query().Get("uddg")
+	}
+	if u.Scheme == "" {
+		u.Scheme = "https"
+	}
+	return u.String()
+}
+
+// parseBingHTML 解析Bing的搜索结果页面
+func parseBingHTML(doc *goquery.Document, limit int) []SearchResult {
+	var results []SearchResult
+	doc.Find("li.b_algo").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		link := s.Find("h2 a").First()
+		href, _ := link.Attr("href")
+		result := SearchResult{
+			Title:   strings.TrimSpace(link.Text()),
+			URL:     bingTarget(href),
+			Snippet: strings.TrimSpace(s.Find(".b_caption p").First().Text()),
+		}
+		if result.Title != "" && result.URL != "" {
+			results = append(results, result)
+		}
+		return len(results) < limit
+	})
+	return results
+}
+
+// bingTarget 还原Bing跳转链接（/ck/a?u=a1<base64>）中的目标地址
+func bingTarget(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if strings.HasSuffix(u.Host, "bing.com") && strings.HasPrefix(u.Path, "/ck/") {
+		encoded := strings.TrimPrefix(u.Query().Get("u"), "a1")
+		target, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+		if err != nil {
+			return ""
+		}
+		return string(target)
+	}
+	return href
+}
@@ -0,0 +1,133 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// loadHTMLFixture 读取 testdata 中保存的搜索结果页面
+func loadHTMLFixture(t *testing.T, name string) *goquery.Document {
+	t.Helper()
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("打开夹具失败: %v", err)
+	}
+	defer file.Close()
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatalf("解析夹具失败: %v", err)
+	}
+	return doc
+}
+
+// expectedFallbackResults 两个夹具中的自然搜索结果，广告不应出现在结果中
+var expectedFallbackResults = []SearchResult{
+	{Title: "The Go Programming Language", URL: "https://go.dev/"},
+	{Title: "Standard library - Go Packages", URL: "https://pkg.go.dev/std", Snippet: "Standard library packages of the Go programming language."},
+	{Title: "Go (programming language) - Wikipedia", URL: "https://en.wikipedia.org/wiki/Go_(programming_language)", Snippet: "Go is a high-level general purpose programming language designed at Google."},
+}
+
+func TestParseFallbackHTML(t *testing.T) {
+	parsers := map[string]func(*goquery.Document, int) []SearchResult{
+		"duckduckgo.html": parseDuckDuckGoHTML,
+		"bing.html":       parseBingHTML,
+	}
+	firstSnippets := map[string]string{
+		"duckduckgo.html": "Go is an open source programming language that makes it simple to build secure, scalable systems.",
+		"bing.html":       "WEBGo is an open source programming language supported by Google.",
+	}
+
+	for name, parse := range parsers {
+		results := parse(loadHTMLFixture(t, name), htmlFallbackMaxLimit)
+		if len(results) != len(expectedFallbackResults) {
+			t.Fatalf("%s: 期望 %d 个结果，实际为 %d 个: %+v", name, len(expectedFallbackResults), len(results), results)
+		}
+		for i, expected := range expectedFallbackResults {
+			if i == 0 {
+				expected.Snippet = firstSnippets[name]
+			}
+			if results[i].Title != expected.Title || results[i].URL != expected.URL || results[i].Snippet != expected.Snippet {
+				t.Errorf("%s: 第 %d 个结果期望为 %+v，实际为 %+v", name, i+1, expected, results[i])
+			}
+		}
+
+		if limited := parse(loadHTMLFixture(t, name), 2); len(limited) != 2 {
+			t.Errorf("%s: limit 为 2 时期望 2 个结果，实际为 %d 个", name, len(limited))
+		}
+	}
+}
+
+func TestHTMLFallbackRobots(t *testing.T) {
+	robotsStatus, robotsRequests := http.StatusInternalServerError, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		robotsRequests++
+		if robotsStatus != http.StatusOK {
+			w.WriteHeader(robotsStatus)
+			return
+		}
+		w.Write([]byte("User-agent: *\nDisallow: /\n\nUser-agent: sjzsdu-utils-search\nDisallow: /html/\nAllow: /search\n"))
+	}))
+	defer server.Close()
+
+	h := NewHTMLFallbackSearch(HTMLFallbackOptions{Enabled: true})
+	pageURL, _ := url.Parse(server.URL + "/search?q=go")
+	host := pageURL.Host
+	expire := func() {
+		h.robots[host].expiresAt = time.Now().Add(-time.Second)
+	}
+
+	// 服务器错误时禁止抓取，重试间隔从 robotsRetryInterval 开始加倍
+	for i, interval := range []time.Duration{robotsRetryInterval, 2 * robotsRetryInterval, 4 * robotsRetryInterval} {
+		rules, err := h.robotsRules(context.Background(), pageURL)
+		if err != nil {
+			t.Fatalf("获取robots.txt规则失败: %v", err)
+		}
+		if rules.allowed("/search?q=go") {
+			t.Errorf("第 %d 次失败后期望禁止抓取", i+1)
+		}
+		if remaining := time.Until(h.robots[host].expiresAt); remaining > interval || remaining < interval-time.Minute/2 {
+			t.Errorf("第 %d 次失败后期望在 %s 后重试，实际为 %s", i+1, interval, remaining)
+		}
+		expire()
+	}
+
+	// 缓存期内不会再次请求
+	if _, err := h.robotsRules(context.Background(), pageURL); err != nil {
+		t.Fatalf("获取robots.txt规则失败: %v", err)
+	}
+	requests := robotsRequests
+	if _, err := h.robotsRules(context.Background(), pageURL); err != nil || robotsRequests != requests {
+		t.Errorf("缓存期内不应再次请求robots.txt")
+	}
+	if remaining := time.Until(h.robots[host].expiresAt); remaining > robotsMaxRetryInterval {
+		t.Errorf("重试间隔不应超过 %s，实际为 %s", robotsMaxRetryInterval, remaining)
+	}
+
+	// 获取成功后按规则判断并缓存一天，失败次数清零
+	robotsStatus = http.StatusOK
+	expire()
+	rules, err := h.robotsRules(context.Background(), pageURL)
+	if err != nil {
+		t.Fatalf("获取robots.txt规则失败: %v", err)
+	}
+	if !rules.allowed("/search?q=go") || rules.allowed("/html/?q=go") {
+		t.Errorf("期望按针对本客户端的分组允许 /search 并禁止 /html/")
+	}
+	if entry := h.robots[host]; entry.failures != 0 || time.Until(entry.expiresAt) < robotsTTL-time.Minute {
+		t.Errorf("获取成功后期望缓存 %s 且失败次数清零，实际为 %s 和 %d", robotsTTL, time.Until(entry.expiresAt), entry.failures)
+	}
+
+	// robots.txt 不存在时允许抓取
+	robotsStatus = http.StatusNotFound
+	expire()
+	if rules, err := h.robotsRules(context.Background(), pageURL); err != nil || !rules.allowed("/html/?q=go") {
+		t.Errorf("robots.txt 不存在时期望允许抓取")
+	}
+}
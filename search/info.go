@@ -0,0 +1,109 @@
+package search
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SearchMode 搜索引擎可以返回的结果类型
+type SearchMode string
+
+const (
+	// SearchModeWeb 网页
+	SearchModeWeb SearchMode = "web"
+	// SearchModeNews 新闻
+	SearchModeNews SearchMode = "news"
+	// SearchModeImages 图片
+	SearchModeImages SearchMode = "images"
+)
+
+// EngineInfo 搜索引擎声明的能力，通用的调用方可以据此调整界面和搜索选项
+type EngineInfo struct {
+	Name string `json:"name"` // 搜索引擎名称
+
+	Modes      []SearchMode `json:"modes"`      // 支持的结果类型
+	MaxLimit   int          `json:"max_limit"`  // 单次搜索最多返回的结果数量，0表示未声明
+	Pagination bool         `json:"pagination"` // 是否支持翻页获取更多结果
+
+	Freshness []string `json:"freshness,omitempty"` // 支持的时效过滤值，为空表示不支持按时效过滤
+	DateRange bool     `json:"date_range"`          // 是否支持按日期范围过滤
+	Locales   []string `json:"locales,omitempty"`   // 可以指定的市场或语言代码，为空表示不支持指定
+
+	Suggestions bool `json:"suggestions"` // 是否支持搜索建议
+}
+
+// SupportsMode 判断是否支持指定的结果类型
+func (i EngineInfo) SupportsMode(mode SearchMode) bool {
+	return slices.Contains(i.Modes, mode)
+}
+
+// InfoProvider 可以声明自身能力的搜索引擎实现的接口
+type InfoProvider interface {
+	// Info 返回搜索引擎的能力
+	Info() EngineInfo
+}
+
+// EngineInfo 返回搜索引擎声明的能力，name为空时使用默认搜索引擎
+// 搜索引擎没有实现 InfoProvider 时只声明支持网页搜索，以及是否实现了 Suggester
+func (c *Client) EngineInfo(name string) (EngineInfo, error) {
+	if name == "" {
+		name = c.defaultEngine
+	}
+	if name == "" {
+		return EngineInfo{}, fmt.Errorf("未指定搜索引擎且没有设置默认搜索引擎")
+	}
+	engine, ok := c.engines[name]
+	if !ok {
+		return EngineInfo{}, fmt.Errorf("搜索引擎 %s 未注册", name)
+	}
+
+	if provider, ok := engine.(InfoProvider); ok {
+		info := provider.Info()
+		if info.Name == "" {
+			info.Name = engine.Name()
+		}
+		return info, nil
+	}
+	_, suggests := engine.(Suggester)
+	return EngineInfo{Name: engine.Name(), Modes: []SearchMode{SearchModeWeb}, Suggestions: suggests}, nil
+}
+
+// bingMarkets Bing搜索支持的市场代码
+var bingMarkets = []string{
+	"es-AR", "en-AU", "de-AT", "nl-BE", "fr-BE", "pt-BR", "en-CA", "fr-CA", "es-CL", "da-DK",
+	"fi-FI", "fr-FR", "de-DE", "zh-HK", "en-IN", "en-ID", "it-IT", "ja-JP", "ko-KR", "en-MY",
+	"es-MX", "nl-NL", "en-NZ", "no-NO", "zh-CN", "pl-PL", "en-PH", "ru-RU", "en-ZA", "es-ES",
+	"sv-SE", "fr-CH", "de-CH", "zh-TW", "tr-TR", "en-GB", "en-US", "es-US",
+}
+
+// Info 返回Bing搜索的能力：通过 WithResponseFilter 返回网页和新闻，通过 WithFreshness 和 WithMarket 过滤
+func (b *BingSearch) Info() EngineInfo {
+	return EngineInfo{
+		Name:        b.Name(),
+		Modes:       []SearchMode{SearchModeWeb, SearchModeNews},
+		MaxLimit:    50,
+		Freshness:   []string{string(BingFreshnessDay), string(BingFreshnessWeek), string(BingFreshnessMonth)},
+		DateRange:   true,
+		Locales:     slices.Clone(bingMarkets),
+		Suggestions: true,
+	}
+}
+
+// Info 返回Google可编程搜索的能力，单次搜索最多返回10个结果
+func (g *GoogleSearch) Info() EngineInfo {
+	return EngineInfo{
+		Name:        g.Name(),
+		Modes:       []SearchMode{SearchModeWeb},
+		MaxLimit:    10,
+		Suggestions: true,
+	}
+}
+
+// Info 返回百度搜索的能力，结果为中文网页，千帆智能搜索固定使用一年内的结果，不支持指定时效和市场
+func (b *BaiduSearch) Info() EngineInfo {
+	return EngineInfo{
+		Name:     b.Name(),
+		Modes:    []SearchMode{SearchModeWeb},
+		MaxLimit: 50,
+	}
+}
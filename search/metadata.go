@@ -0,0 +1,48 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// SearchResponse 一次搜索的结果及其元数据，用于调试和对比不同搜索引擎
+type SearchResponse struct {
+	Query     string         `json:"query"`            // 搜索查询
+	Requested string         `json:"requested"`        // 请求的搜索引擎，未通过 WithEngine 指定时为默认搜索引擎
+	Engine    string         `json:"engine"`           // 实际返回结果的搜索引擎，备用搜索引擎生效时与 Requested 不同
+	Cached    bool           `json:"cached"`           // 结果是否来自缓存，开启查询扩展时需要所有查询都命中缓存
+	Latency   time.Duration  `json:"latency"`          // 总耗时，包括失败的搜索引擎和后处理
+	Errors    []string       `json:"errors,omitempty"` // 返回结果之前失败的搜索引擎及其错误
+	Results   []SearchResult `json:"results"`          // 搜索结果，Rank 为从1开始的排名
+}
+
+// Fallback 判断结果是否由备用搜索引擎返回
+func (r *SearchResponse) Fallback() bool {
+	return r.Engine != r.Requested
+}
+
+// SearchWithMetadata 与 Search 相同，同时返回回答的搜索引擎、是否来自缓存和总耗时等元数据，
+// 并为每个结果设置最终的排名（经过过滤和查询扩展合并之后）
+func (c *Client) SearchWithMetadata(ctx context.Context, query string, limit int, opts ...SearchOption) (*SearchResponse, error) {
+	response, err := c.searchWithMetadata(ctx, query, limit, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 缓存中的结果可能与其他调用共享，设置排名前先复制
+	response.Results = slices.Clone(response.Results)
+	for i := range response.Results {
+		response.Results[i].Rank = i + 1
+	}
+	return response, nil
+}
+
+// errorStrings 将错误转换为字符串
+func errorStrings(errs []error) []string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return messages
+}
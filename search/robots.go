@@ -0,0 +1,109 @@
+package search
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// robotsRule robots.txt 中的一条 Allow 或 Disallow 规则
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// robotsRules 适用于本客户端的 robots.txt 规则
+type robotsRules struct {
+	rules       []robotsRule
+	disallowAll bool // robots.txt 无法获取（服务器错误或网络错误）时视为禁止抓取整个站点
+}
+
+// parseRobots 按RFC 9309解析robots.txt，返回适用于agent的规则
+// agent 为User-Agent的产品名（不含版本），不区分大小写；没有针对agent的分组时使用 * 分组
+func parseRobots(r io.Reader, agent string) *robotsRules {
+	agent = strings.ToLower(agent)
+
+	var matched, wildcard []robotsRule
+	var hasMatched bool
+	var groupAgents []string
+	inRules := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// 规则之后出现的 User-agent 开始新的分组，连续的 User-agent 共享同一分组
+			if inRules {
+				groupAgents, inRules = nil, false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue
+			}
+			rule := robotsRule{pattern: value, allow: key == "allow"}
+			for _, name := range groupAgents {
+				switch name {
+				case agent:
+					matched, hasMatched = append(matched, rule), true
+				case "*":
+					wildcard = append(wildcard, rule)
+				}
+			}
+		}
+	}
+
+	if hasMatched {
+		return &robotsRules{rules: matched}
+	}
+	return &robotsRules{rules: wildcard}
+}
+
+// allowed 判断是否允许抓取path（包含查询参数），匹配最长的规则生效，长度相同时 Allow 优先
+func (r *robotsRules) allowed(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+
+	allow, longest := true, -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > longest || (n == longest && rule.allow) {
+			allow, longest = rule.allow, n
+		}
+	}
+	return allow
+}
+
+// robotsMatch 判断path是否匹配规则，* 匹配任意字符序列，结尾的 $ 表示必须匹配到path末尾
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		index := strings.Index(rest, part)
+		if index < 0 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+	return !anchored || rest == ""
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRobotsAllowed(t *testing.T) {
+	robots := `# 注释
+User-agent: *
+Disallow: /
+
+User-agent: Other-Bot
+User-agent: sjzsdu-utils-search
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Allow: /tie
+Disallow: /tie
+Disallow: /search?*&page=
+
+User-agent: other-bot-2
+Allow: /
+`
+	rules := parseRobots(strings.NewReader(robots), "SJZSDU-Utils-Search")
+
+	cases := map[string]bool{
+		"/":                      true,
+		"/private":               false,
+		"/private/x":             false,
+		"/private/public/x":      true,
+		"/docs/a.pdf":            false,
+		"/docs/a.pdf?download=1": true,
+		"/tie":                   true,
+		"/search?q=go":           true,
+		"/search?q=go&page=2":    false,
+	}
+	for path, expected := range cases {
+		if allowed := rules.allowed(path); allowed != expected {
+			t.Errorf("%s 期望允许抓取为 %v，实际为 %v", path, expected, allowed)
+		}
+	}
+
+	// 没有针对agent的分组时使用 * 分组
+	if parseRobots(strings.NewReader(robots), "unknown-bot").allowed("/search") {
+		t.Error("期望 * 分组禁止所有路径")
+	}
+	if !parseRobots(strings.NewReader(""), "unknown-bot").allowed("/search") {
+		t.Error("空的robots.txt期望允许所有路径")
+	}
+	if (&robotsRules{disallowAll: true}).allowed("/") {
+		t.Error("disallowAll 期望禁止所有路径")
+	}
+}
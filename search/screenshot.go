@@ -0,0 +1,183 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sjzsdu/utils/coroutine"
+)
+
+// Screenshotter 截取网页截图的无头浏览器，返回图片数据
+type Screenshotter interface {
+	Screenshot(ctx context.Context, url string) ([]byte, error)
+}
+
+// ScreenshotOptions 搜索结果截图的选项
+type ScreenshotOptions struct {
+	// Screenshotter 截图使用的浏览器，为nil时不截图
+	Screenshotter Screenshotter
+	// TopN 只为前TopN个结果截图，小于等于0时使用3
+	TopN int
+	// MaxWorkers 同时截图的数量，小于等于0时使用TopN
+	MaxWorkers int
+}
+
+// SetScreenshots 开启搜索结果截图，Search 和 SearchWithEngine 返回前为排名靠前的结果截取缩略图
+// 截图在过滤之后进行且不会被缓存，单个结果截图失败时该结果不带截图；传入零值时关闭截图
+func (c *Client) SetScreenshots(options ScreenshotOptions) {
+	c.screenshots = options
+}
+
+// capture 为排名靠前的结果截图，返回新的切片，不修改传入的结果
+func (o ScreenshotOptions) capture(ctx context.Context, results []SearchResult) []SearchResult {
+	if o.Screenshotter == nil || len(results) == 0 {
+		return results
+	}
+
+	topN := o.TopN
+	if topN <= 0 {
+		topN = 3
+	}
+	// 不为被过滤但保留的结果截图
+	var indexes []int
+	for i, result := range results {
+		if len(indexes) < topN && result.Filtered == "" {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return results
+	}
+	workers := o.MaxWorkers
+	if workers <= 0 {
+		workers = len(indexes)
+	}
+
+	captured := make([]SearchResult, len(results))
+	copy(captured, results)
+	coroutine.Each(ctx, workers, indexes, func(i int) error {
+		image, err := o.Screenshotter.Screenshot(ctx, captured[i].URL)
+		if err != nil {
+			return err
+		}
+		captured[i].Screenshot = image
+		return nil
+	})
+	return captured
+}
+
+// ScreenshotDataURL 返回截图的data URL，可以直接用于HTML的img标签，没有截图时返回空字符串
+func (r SearchResult) ScreenshotDataURL() string {
+	if len(r.Screenshot) == 0 {
+		return ""
+	}
+	return "data:" + http.DetectContentType(r.Screenshot) + ";base64," + base64.StdEncoding.EncodeToString(r.Screenshot)
+}
+
+// BrowserPool 通过远程无头浏览器服务截图，兼容 browserless 的 /screenshot 接口
+// 同时进行的截图数量受MaxConcurrent限制，多个搜索客户端传入同一个实例时共享这个限制；
+// 它只用于搜索结果截图，爬虫不会使用它
+type BrowserPool struct {
+	endpoint string
+	token    string
+	width    int
+	height   int
+	client   *http.Client
+	slots    chan struct{}
+}
+
+// BrowserPoolOptions 远程无头浏览器的选项
+type BrowserPoolOptions struct {
+	// Token 浏览器服务的访问令牌，作为token查询参数发送
+	Token string
+	// MaxConcurrent 同时进行的截图数量，小于等于0时使用4
+	MaxConcurrent int
+	// Width 和 Height 浏览器窗口大小，小于等于0时使用1280x800
+	Width  int
+	Height int
+	// Timeout 单次截图的超时时间，小于等于0时使用30秒
+	Timeout time.Duration
+}
+
+// NewBrowserPool 创建连接到endpoint（如 http://localhost:3000）的远程无头浏览器
+func NewBrowserPool(endpoint string, options BrowserPoolOptions) *BrowserPool {
+	if options.MaxConcurrent <= 0 {
+		options.MaxConcurrent = 4
+	}
+	if options.Width <= 0 || options.Height <= 0 {
+		options.Width, options.Height = 1280, 800
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 30 * time.Second
+	}
+
+	return &BrowserPool{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		token:    options.Token,
+		width:    options.Width,
+		height:   options.Height,
+		client:   &http.Client{Timeout: options.Timeout},
+		slots:    make(chan struct{}, options.MaxConcurrent),
+	}
+}
+
+// Screenshot 截取url的首屏截图，返回PNG图片数据
+func (p *BrowserPool) Screenshot(ctx context.Context, pageURL string) ([]byte, error) {
+	select {
+	case p.slots <- struct{}{}:
+		defer func() { <-p.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	type viewport struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	}
+	body, err := json.Marshal(struct {
+		URL      string            `json:"url"`
+		Options  map[string]string `json:"options"`
+		Viewport viewport          `json:"viewport"`
+	}{
+		URL:      pageURL,
+		Options:  map[string]string{"type": "png"},
+		Viewport: viewport{Width: p.width, Height: p.height},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化截图请求失败: %v", err)
+	}
+
+	screenshotURL := p.endpoint + "/screenshot"
+	if p.token != "" {
+		screenshotURL += "?token=" + url.QueryEscape(p.token)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", screenshotURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("截图失败，状态码: %d", resp.StatusCode)
+	}
+
+	image, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取截图失败: %v", err)
+	}
+	return image, nil
+}
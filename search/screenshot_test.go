@@ -0,0 +1,85 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// fakeScreenshotter 用于测试的截图器，返回URL作为图片数据，failURLs 中的URL截图失败
+type fakeScreenshotter struct {
+	mu       sync.Mutex
+	calls    []string
+	failURLs map[string]bool
+}
+
+func (s *fakeScreenshotter) Screenshot(ctx context.Context, url string) ([]byte, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, url)
+	s.mu.Unlock()
+	if s.failURLs[url] {
+		return nil, errors.New("截图失败")
+	}
+	return []byte(url), nil
+}
+
+func TestScreenshotCapture(t *testing.T) {
+	results := []SearchResult{
+		{URL: "https://a.com"},
+		{URL: "https://blocked.com", Filtered: FilterBlocked},
+		{URL: "https://b.com"},
+		{URL: "https://c.com"},
+		{URL: "https://d.com"},
+	}
+	screenshotter := &fakeScreenshotter{failURLs: map[string]bool{"https://b.com": true}}
+	options := ScreenshotOptions{Screenshotter: screenshotter, TopN: 3, MaxWorkers: 2}
+
+	captured := options.capture(context.Background(), results)
+
+	expected := map[string]string{
+		"https://a.com":       "https://a.com",
+		"https://blocked.com": "",
+		"https://b.com":       "",
+		"https://c.com":       "https://c.com",
+		"https://d.com":       "",
+	}
+	if len(captured) != len(results) {
+		t.Fatalf("期望 %d 个结果，实际为 %d 个", len(results), len(captured))
+	}
+	for _, result := range captured {
+		if string(result.Screenshot) != expected[result.URL] {
+			t.Errorf("%s 的截图期望为 %q，实际为 %q", result.URL, expected[result.URL], result.Screenshot)
+		}
+	}
+	if len(screenshotter.calls) != 3 {
+		t.Errorf("期望截图 3 次，实际为 %v", screenshotter.calls)
+	}
+	for _, url := range screenshotter.calls {
+		if url == "https://blocked.com" || url == "https://d.com" {
+			t.Errorf("不应为 %s 截图", url)
+		}
+	}
+	for _, result := range results {
+		if result.Screenshot != nil {
+			t.Errorf("capture 不应修改传入的结果: %s", result.URL)
+		}
+	}
+}
+
+func TestScreenshotCaptureDefaults(t *testing.T) {
+	results := make([]SearchResult, 5)
+	for i := range results {
+		results[i].URL = string(rune('a'+i)) + ".com"
+	}
+
+	if captured := (ScreenshotOptions{}).capture(context.Background(), results); &captured[0] != &results[0] {
+		t.Error("没有设置 Screenshotter 时应原样返回结果")
+	}
+
+	screenshotter := &fakeScreenshotter{}
+	(ScreenshotOptions{Screenshotter: screenshotter}).capture(context.Background(), results)
+	if len(screenshotter.calls) != 3 {
+		t.Errorf("TopN 默认值期望为 3，实际截图 %d 次", len(screenshotter.calls))
+	}
+}
@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"fmt"
 )
 
 // SearchResult 表示搜索结果
@@ -9,6 +10,11 @@ type SearchResult struct {
 	Title   string `json:"title"`   // 搜索结果标题
 	URL     string `json:"url"`     // 搜索结果URL
 	Snippet string `json:"snippet"` // 搜索结果摘要
+
+	Screenshot []byte       `json:"screenshot,omitempty"` // 网页缩略图，开启截图时才有，JSON中为base64编码
+	Filtered   FilterReason `json:"filtered,omitempty"`   // 被过滤的原因，只在 FilterOptions.KeepFiltered 时出现
+	Highlight  *Highlight   `json:"highlight,omitempty"`  // 查询词的匹配位置和高亮的HTML，开启高亮时才有
+	Rank       int          `json:"rank,omitempty"`       // 从1开始的排名，只在 SearchWithMetadata 返回的结果中设置
 }
 
 // SearchEngine 定义搜索引擎接口
@@ -22,6 +28,20 @@ type SearchEngine interface {
 	Name() string
 }
 
+// Suggester 支持搜索建议（自动补全）的搜索引擎可以实现的接口
+type Suggester interface {
+	// Suggest 返回以prefix开头的搜索建议
+	Suggest(ctx context.Context, prefix string) ([]string, error)
+}
+
+// OptionsSearcher 支持按次指定搜索参数的搜索引擎可以实现的接口
+// Client.Search 传入了搜索引擎参数（如 WithMarket、WithFreshness）时调用 SearchWithOptions，
+// 不支持的搜索引擎返回错误，避免参数被静默忽略；目前bing支持
+type OptionsSearcher interface {
+	// SearchWithOptions 使用本次指定的参数覆盖创建时的参数执行搜索
+	SearchWithOptions(ctx context.Context, query string, limit int, opts ...SearchOption) ([]SearchResult, error)
+}
+
 // SearchOption 定义搜索选项
 type SearchOption func(*SearchConfig)
 
@@ -29,9 +49,26 @@ type SearchOption func(*SearchConfig)
 type SearchConfig struct {
 	Engine   string            // 搜索引擎名称
 	APIKey   string            // API密钥
-	OtherKey string            // 其他密钥（如Google的Search Engine ID）
+	OtherKey string            // 其他密钥（如Google的Search Engine ID、百度的Secret Key）
 	Timeout  int               // 超时时间（秒）
 	Headers  map[string]string // 自定义请求头
+
+	WebSearchFallback bool        // 百度：千帆智能搜索失败时改用百度搜索API
+	TokenSource       TokenSource // Google：使用OAuth访问令牌代替API密钥鉴权
+	QuotaProject      string      // Google：计费和配额使用的项目
+
+	Market         string               // Bing：市场代码，如 zh-CN、en-US
+	Freshness      BingFreshness        // Bing：只返回指定时间内发现的网页
+	ResponseFilter []BingResponseFilter // Bing：返回的结果类型
+	SafeSearch     BingSafeSearch       // Bing：成人内容过滤级别
+}
+
+// engineOptionsKey 返回按次生效的搜索引擎参数的缓存键，没有设置时返回空字符串
+func (cfg *SearchConfig) engineOptionsKey() string {
+	if cfg.Market == "" && cfg.Freshness == "" && len(cfg.ResponseFilter) == 0 && cfg.SafeSearch == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s|%s|%v|%s", cfg.Market, cfg.Freshness, cfg.ResponseFilter, cfg.SafeSearch)
 }
 
 // WithEngine 设置搜索引擎
@@ -68,3 +105,24 @@ func WithHeaders(headers map[string]string) SearchOption {
 		cfg.Headers = headers
 	}
 }
+
+// WithWebSearchFallback 设置百度搜索引擎在千帆智能搜索失败时是否改用百度搜索API
+func WithWebSearchFallback(enabled bool) SearchOption {
+	return func(cfg *SearchConfig) {
+		cfg.WebSearchFallback = enabled
+	}
+}
+
+// WithTokenSource 设置Google搜索引擎使用OAuth访问令牌鉴权，例如服务账号，设置后不再使用API密钥
+func WithTokenSource(tokenSource TokenSource) SearchOption {
+	return func(cfg *SearchConfig) {
+		cfg.TokenSource = tokenSource
+	}
+}
+
+// WithQuotaProject 设置Google搜索引擎计费和配额使用的项目
+func WithQuotaProject(project string) SearchOption {
+	return func(cfg *SearchConfig) {
+		cfg.QuotaProject = project
+	}
+}
@@ -0,0 +1,278 @@
+package search
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sjzsdu/utils/notifier"
+)
+
+// DefaultPrices 各搜索引擎每次查询的估算费用（美元），按官方公开的付费档位估算，实际费用以账单为准
+var DefaultPrices = map[string]float64{
+	"bing":   0.015,
+	"google": 0.005,
+	"baidu":  0.004,
+}
+
+// DefaultMaxUsageRecords 默认保留的用量记录数
+const DefaultMaxUsageRecords = 10000
+
+// UsageRecord 一次搜索的用量记录
+type UsageRecord struct {
+	Time    time.Time `json:"time"`
+	Engine  string    `json:"engine"`
+	Query   string    `json:"query"`
+	Results int       `json:"results"`
+	Cost    float64   `json:"cost"`             // 估算费用，命中缓存时为0
+	Cached  bool      `json:"cached,omitempty"` // 是否命中缓存
+	Error   string    `json:"error,omitempty"`  // 搜索失败时的错误
+}
+
+// BudgetAlert 当月估算费用超过预算时的告警，实现了 notifier.MessageItem，可以直接发送通知
+type BudgetAlert struct {
+	Month  string  // 月份，格式为 2006-01
+	Budget float64 // 月度预算
+	Cost   float64 // 当月已产生的估算费用
+}
+
+// Title 获取标题
+func (a BudgetAlert) Title() string {
+	return fmt.Sprintf("搜索API费用超出预算: %s", a.Month)
+}
+
+// URL 获取链接
+func (a BudgetAlert) URL() string {
+	return ""
+}
+
+// Content 获取内容
+func (a BudgetAlert) Content() string {
+	return fmt.Sprintf("%s 估算费用 $%.2f，已超过月度预算 $%.2f", a.Month, a.Cost, a.Budget)
+}
+
+// BudgetHook 当月估算费用首次超过预算时调用，每个月最多调用一次
+type BudgetHook func(ctx context.Context, alert BudgetAlert)
+
+// NotifyBudget 返回通过通知器发送预算告警的BudgetHook，未指定渠道时发送到所有启用的渠道
+func NotifyBudget(manager *notifier.NotifierManager, channels ...string) BudgetHook {
+	return func(ctx context.Context, alert BudgetAlert) {
+		items := []notifier.MessageItem{alert}
+		if len(channels) == 0 {
+			manager.SendToAllContext(ctx, items)
+			return
+		}
+		for _, channel := range channels {
+			manager.SendToSpecificContext(ctx, channel, items)
+		}
+	}
+}
+
+// UsageOptions 用量统计的选项
+type UsageOptions struct {
+	// Prices 各搜索引擎每次查询的估算费用，为nil时使用 DefaultPrices，未列出的搜索引擎按0计算
+	Prices map[string]float64
+	// MaxRecords 保留的用量记录数，超出时丢弃最早的记录，小于等于0时使用 DefaultMaxUsageRecords
+	// 月度费用单独累计，不受丢弃记录的影响
+	MaxRecords int
+	// MonthlyBudget 每月的估算费用预算，小于等于0时不检查
+	MonthlyBudget float64
+	// OnBudgetExceeded 当月费用首次超过预算时在新的goroutine中调用
+	OnBudgetExceeded BudgetHook
+}
+
+// UsageTracker 记录搜索的查询、搜索引擎、结果数量和估算费用
+type UsageTracker struct {
+	options UsageOptions
+
+	mu          sync.Mutex
+	records     []UsageRecord
+	month       string
+	monthlyCost float64
+	alerted     bool
+}
+
+// NewUsageTracker 创建用量统计
+func NewUsageTracker(options UsageOptions) *UsageTracker {
+	if options.Prices == nil {
+		options.Prices = DefaultPrices
+	}
+	if options.MaxRecords <= 0 {
+		options.MaxRecords = DefaultMaxUsageRecords
+	}
+	return &UsageTracker{options: options}
+}
+
+// SetUsageTracker 设置用量统计，记录每次调用搜索引擎或命中缓存的搜索，传入nil时关闭统计
+func (c *Client) SetUsageTracker(tracker *UsageTracker) {
+	c.usage = tracker
+}
+
+// record 记录一次搜索
+func (t *UsageTracker) record(ctx context.Context, engine, query string, results int, cached bool, err error) {
+	record := UsageRecord{
+		Time:    time.Now(),
+		Engine:  engine,
+		Query:   query,
+		Results: results,
+		Cached:  cached,
+	}
+	if !cached {
+		record.Cost = t.options.Prices[engine]
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	t.mu.Lock()
+	t.records = append(t.records, record)
+	if len(t.records) > t.options.MaxRecords {
+		t.records = append(t.records[:0], t.records[len(t.records)-t.options.MaxRecords:]...)
+	}
+
+	month := record.Time.Format("2006-01")
+	if month != t.month {
+		t.month, t.monthlyCost, t.alerted = month, 0, false
+	}
+	t.monthlyCost += record.Cost
+	var alert *BudgetAlert
+	if t.options.MonthlyBudget > 0 && !t.alerted && t.monthlyCost > t.options.MonthlyBudget {
+		t.alerted = true
+		alert = &BudgetAlert{Month: month, Budget: t.options.MonthlyBudget, Cost: t.monthlyCost}
+	}
+	t.mu.Unlock()
+
+	if alert != nil && t.options.OnBudgetExceeded != nil {
+		go t.options.OnBudgetExceeded(context.WithoutCancel(ctx), *alert)
+	}
+}
+
+// MonthlyCost 返回当月的估算费用
+func (t *UsageTracker) MonthlyCost() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.month != time.Now().Format("2006-01") {
+		return 0
+	}
+	return t.monthlyCost
+}
+
+// Records 返回from到to（不包含）之间的用量记录，零值表示不限制
+func (t *UsageTracker) Records(from, to time.Time) []UsageRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records := make([]UsageRecord, 0, len(t.records))
+	for _, record := range t.records {
+		if !from.IsZero() && record.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !record.Time.Before(to) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Reset 清空用量记录和当月费用
+func (t *UsageTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.records = nil
+	t.month, t.monthlyCost, t.alerted = "", 0, false
+}
+
+// EngineUsage 单个搜索引擎的用量汇总
+type EngineUsage struct {
+	Engine  string  `json:"engine"`
+	Queries int     `json:"queries"`
+	Cached  int     `json:"cached"`
+	Errors  int     `json:"errors"`
+	Results int     `json:"results"`
+	Cost    float64 `json:"cost"`
+}
+
+// UsageReport 用量报告
+type UsageReport struct {
+	From      time.Time     `json:"from,omitzero"`
+	To        time.Time     `json:"to,omitzero"`
+	Engines   []EngineUsage `json:"engines"`
+	TotalCost float64       `json:"total_cost"`
+	Records   []UsageRecord `json:"records"`
+}
+
+// Report 生成from到to（不包含）之间的用量报告，零值表示不限制
+func (t *UsageTracker) Report(from, to time.Time) *UsageReport {
+	report := &UsageReport{
+		From:    from,
+		To:      to,
+		Records: t.Records(from, to),
+	}
+
+	engines := make(map[string]*EngineUsage)
+	for _, record := range report.Records {
+		usage, ok := engines[record.Engine]
+		if !ok {
+			usage = &EngineUsage{Engine: record.Engine}
+			engines[record.Engine] = usage
+		}
+		usage.Queries++
+		usage.Results += record.Results
+		usage.Cost += record.Cost
+		if record.Cached {
+			usage.Cached++
+		}
+		if record.Error != "" {
+			usage.Errors++
+		}
+		report.TotalCost += record.Cost
+	}
+
+	report.Engines = make([]EngineUsage, 0, len(engines))
+	for _, usage := range engines {
+		report.Engines = append(report.Engines, *usage)
+	}
+	sort.Slice(report.Engines, func(i, j int) bool {
+		return report.Engines[i].Engine < report.Engines[j].Engine
+	})
+	return report
+}
+
+// WriteJSON 将报告以JSON格式写入w
+func (r *UsageReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV 将报告中的用量记录以CSV格式写入w，第一行为表头
+func (r *UsageReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"time", "engine", "query", "results", "cost", "cached", "error"}); err != nil {
+		return err
+	}
+	for _, record := range r.Records {
+		if err := writer.Write([]string{
+			record.Time.Format(time.RFC3339),
+			record.Engine,
+			record.Query,
+			strconv.Itoa(record.Results),
+			strconv.FormatFloat(record.Cost, 'f', -1, 64),
+			strconv.FormatBool(record.Cached),
+			record.Error,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUsageBudgetAlert(t *testing.T) {
+	alerts := make(chan BudgetAlert, 4)
+	tracker := NewUsageTracker(UsageOptions{
+		Prices:           map[string]float64{"paid": 1},
+		MonthlyBudget:    1.5,
+		OnBudgetExceeded: func(ctx context.Context, alert BudgetAlert) { alerts <- alert },
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		engine    string
+		cached    bool
+		wantCost  float64
+		wantAlert bool
+	}{
+		{"未超出预算", "paid", false, 1, false},
+		{"命中缓存不计费", "paid", true, 1, false},
+		{"未定价的搜索引擎不计费", "free", false, 1, false},
+		{"首次超出预算时告警", "paid", false, 2, true},
+		{"每月只告警一次", "paid", false, 3, false},
+	}
+	for _, tt := range tests {
+		tracker.record(ctx, tt.engine, "q", 1, tt.cached, nil)
+		if cost := tracker.MonthlyCost(); cost != tt.wantCost {
+			t.Errorf("%s: 期望当月费用 %v，实际为 %v", tt.name, tt.wantCost, cost)
+		}
+		select {
+		case alert := <-alerts:
+			if !tt.wantAlert {
+				t.Errorf("%s: 不应告警，实际收到 %+v", tt.name, alert)
+			} else if alert.Budget != 1.5 || alert.Cost != 2 || alert.Month != time.Now().Format("2006-01") {
+				t.Errorf("%s: 告警内容不符: %+v", tt.name, alert)
+			}
+		case <-time.After(50 * time.Millisecond):
+			if tt.wantAlert {
+				t.Errorf("%s: 期望收到告警", tt.name)
+			}
+		}
+	}
+}
+
+func TestUsageMonthlyRollover(t *testing.T) {
+	alerts := make(chan BudgetAlert, 4)
+	tracker := NewUsageTracker(UsageOptions{
+		Prices:           map[string]float64{"paid": 1},
+		MonthlyBudget:    0.5,
+		OnBudgetExceeded: func(ctx context.Context, alert BudgetAlert) { alerts <- alert },
+	})
+
+	// 模拟上个月已经超出预算并告警
+	tracker.month, tracker.monthlyCost, tracker.alerted = "2000-01", 100, true
+	if cost := tracker.MonthlyCost(); cost != 0 {
+		t.Errorf("上个月的费用不应计入当月，实际为 %v", cost)
+	}
+
+	// 新的月份重新累计费用，超出预算时再次告警
+	tracker.record(context.Background(), "paid", "q", 1, false, errors.New("失败"))
+	if cost := tracker.MonthlyCost(); cost != 1 {
+		t.Errorf("期望新的月份重新累计费用，实际为 %v", cost)
+	}
+	select {
+	case alert := <-alerts:
+		if alert.Cost != 1 {
+			t.Errorf("期望按新月份的费用告警，实际为 %+v", alert)
+		}
+	case <-time.After(time.Second):
+		t.Error("新的月份超出预算时应再次告警")
+	}
+
+	report := tracker.Report(time.Time{}, time.Time{})
+	if len(report.Engines) != 1 || report.Engines[0].Errors != 1 || report.TotalCost != 1 {
+		t.Errorf("用量报告不符: %+v", report)
+	}
+}